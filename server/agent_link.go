@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/command"
+	"github.com/mattermost/mattermost-plugin-cursor/server/cursor"
+	"github.com/mattermost/mattermost-plugin-cursor/server/ghclient"
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+// linkPullRequest backs /cursor link. It checks the PR exists on GitHub,
+// records its URL and head branch on the agent, which also indexes the agent
+// by PR URL for webhook lookups, and bootstraps the review loop when the
+// agent has already finished.
+func (p *Plugin) linkPullRequest(record *kvstore.AgentRecord, prURL string) (command.LinkPullRequestResult, error) {
+	ghClient := p.getGitHubClient()
+	if ghClient == nil {
+		return command.LinkPullRequestResult{}, command.ErrGitHubNotConfigured
+	}
+	prRef, err := ghclient.ParsePRURL(prURL)
+	if err != nil {
+		return command.LinkPullRequestResult{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	pr, err := ghClient.GetPullRequest(ctx, prRef.Owner, prRef.Repo, prRef.Number)
+	if err != nil {
+		if ghclient.IsNotFound(err) {
+			return command.LinkPullRequestResult{}, command.ErrPullRequestNotFound
+		}
+		return command.LinkPullRequestResult{}, errors.Wrap(err, "failed to fetch pull request")
+	}
+
+	record.PrURL = pr.GetHTMLURL()
+	if record.PrURL == "" {
+		record.PrURL = prURL
+	}
+	if branch := pr.GetHead().GetRef(); branch != "" {
+		record.TargetBranch = branch
+	}
+	record.UpdatedAt = p.now().UnixMilli()
+	if err := p.kvstore.SaveAgent(record); err != nil {
+		return command.LinkPullRequestResult{}, errors.Wrap(err, "failed to save agent")
+	}
+	p.publishAgentStatusChange(record)
+
+	result := command.LinkPullRequestResult{
+		PRURL:  record.PrURL,
+		Branch: record.TargetBranch,
+	}
+	if cursor.AgentStatus(record.Status).IsTerminal() {
+		result.ReviewLoopStarted = p.ensureReviewLoop(record.PrURL) != nil
+	}
+	return result, nil
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-github/v68/github"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/command"
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+const linkTestPRURL = "https://github.com/org/repo/pull/42"
+
+func TestLinkPullRequest_LinksAndBootstrapsReviewLoop(t *testing.T) {
+	p, api, store, ghMock := setupReviewLoopTestPlugin(t)
+
+	agent := &kvstore.AgentRecord{
+		CursorAgentID:  "agent-1",
+		UserID:         "user-1",
+		ChannelID:      "ch-1",
+		PostID:         "root-1",
+		TriggerPostID:  "trigger-1",
+		BotReplyPostID: "reply-1",
+		Status:         "FINISHED",
+		Repository:     "org/repo",
+	}
+
+	ghMock.On("GetPullRequest", mock.Anything, "org", "repo", 42).Return(&github.PullRequest{
+		HTMLURL: github.Ptr(linkTestPRURL),
+		Head:    &github.PullRequestBranch{Ref: github.Ptr("cursor/fix-login")},
+	}, nil)
+	store.On("SaveAgent", mock.MatchedBy(func(r *kvstore.AgentRecord) bool {
+		return r.PrURL == linkTestPRURL && r.TargetBranch == "cursor/fix-login"
+	})).Return(nil).Once()
+	api.On("PublishWebSocketEvent", "agent_status_change", mock.Anything, mock.Anything).Return().Once()
+
+	// ensureReviewLoop: no loop yet, bootstrap from the now-linked agent.
+	store.On("GetReviewLoopByPRURL", linkTestPRURL).Return(nil, nil).Twice()
+	store.On("GetAgentByPRURL", linkTestPRURL).Return(agent, nil)
+	store.On("GetWorkflowByAgent", "agent-1").Return("", nil)
+	store.On("SaveReviewLoop", mock.MatchedBy(func(loop *kvstore.ReviewLoop) bool {
+		return loop.AgentRecordID == "agent-1" && loop.PRNumber == 42
+	})).Return(nil)
+	ghMock.On("MarkPRReadyForReview", mock.Anything, "org", "repo", 42).Return(nil)
+	ghMock.On("RequestReviewers", mock.Anything, "org", "repo", 42, mock.Anything).Return(nil)
+	mockInlineStatusUpdate(store, api, "agent-1", agent)
+	api.On("AddReaction", mock.MatchedBy(func(r *model.Reaction) bool {
+		return r.PostId == "trigger-1" && r.EmojiName == "eyes"
+	})).Return(nil, nil)
+	store.On("GetReviewLoopByPRURL", linkTestPRURL).Return(&kvstore.ReviewLoop{
+		ID:            "loop-1",
+		AgentRecordID: "agent-1",
+		Phase:         kvstore.ReviewPhaseAwaitingReview,
+		PRURL:         linkTestPRURL,
+	}, nil).Once()
+
+	result, err := p.linkPullRequest(agent, linkTestPRURL)
+	require.NoError(t, err)
+
+	assert.Equal(t, command.LinkPullRequestResult{
+		PRURL:             linkTestPRURL,
+		Branch:            "cursor/fix-login",
+		ReviewLoopStarted: true,
+	}, result)
+	store.AssertExpectations(t)
+	ghMock.AssertExpectations(t)
+}
+
+func TestLinkPullRequest_RunningAgentDoesNotStartLoop(t *testing.T) {
+	p, api, store, ghMock := setupReviewLoopTestPlugin(t)
+
+	agent := &kvstore.AgentRecord{CursorAgentID: "agent-1", UserID: "user-1", Status: "RUNNING", Repository: "org/repo"}
+
+	ghMock.On("GetPullRequest", mock.Anything, "org", "repo", 42).Return(&github.PullRequest{
+		HTMLURL: github.Ptr(linkTestPRURL),
+		Head:    &github.PullRequestBranch{Ref: github.Ptr("cursor/fix-login")},
+	}, nil)
+	store.On("SaveAgent", mock.Anything).Return(nil).Once()
+	api.On("PublishWebSocketEvent", "agent_status_change", mock.Anything, mock.Anything).Return().Once()
+
+	result, err := p.linkPullRequest(agent, linkTestPRURL)
+	require.NoError(t, err)
+
+	assert.False(t, result.ReviewLoopStarted)
+	assert.Equal(t, linkTestPRURL, agent.PrURL)
+	store.AssertNotCalled(t, "GetReviewLoopByPRURL", mock.Anything)
+}
+
+func TestLinkPullRequest_RejectsMissingPR(t *testing.T) {
+	p, _, store, ghMock := setupReviewLoopTestPlugin(t)
+
+	agent := &kvstore.AgentRecord{CursorAgentID: "agent-1", UserID: "user-1", Status: "FINISHED"}
+
+	ghMock.On("GetPullRequest", mock.Anything, "org", "repo", 42).
+		Return(nil, &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusNotFound}})
+
+	_, err := p.linkPullRequest(agent, linkTestPRURL)
+
+	assert.True(t, errors.Is(err, command.ErrPullRequestNotFound))
+	assert.Empty(t, agent.PrURL)
+	store.AssertNotCalled(t, "SaveAgent", mock.Anything)
+}
+
+func TestLinkPullRequest_RequiresGitHubClient(t *testing.T) {
+	p, _, store, _ := setupReviewLoopTestPlugin(t)
+	p.setGitHubClient(nil)
+
+	_, err := p.linkPullRequest(&kvstore.AgentRecord{CursorAgentID: "agent-1"}, linkTestPRURL)
+
+	assert.True(t, errors.Is(err, command.ErrGitHubNotConfigured))
+	store.AssertNotCalled(t, "SaveAgent", mock.Anything)
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+// maxAgentSummaryLength bounds the agent summary posted when an agent finishes.
+const maxAgentSummaryLength = 3000
+
+// agentFinalSummary returns the agent's last assistant message, sanitized for
+// Mattermost and truncated, for posting when the agent finishes. It returns
+// an empty string when PostAgentSummaryOnFinish is off or no summary is
+// available.
+func (p *Plugin) agentFinalSummary(record *kvstore.AgentRecord) string {
+	if !p.getConfiguration().PostAgentSummaryOnFinish {
+		return ""
+	}
+	cursorClient := p.getCursorClient()
+	if cursorClient == nil {
+		return ""
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	conv, err := cursorClient.GetConversation(ctx, record.CursorAgentID)
+	if err != nil {
+		p.API.LogWarn("Failed to fetch conversation for agent summary",
+			"agent_id", record.CursorAgentID,
+			"error", err.Error(),
+		)
+		return ""
+	}
+	msg := latestAssistantMessage(conv)
+	if msg == nil {
+		return ""
+	}
+	summary := strings.TrimSpace(sanitizeReviewBodyForMattermost(msg.Text))
+	return truncateText(summary, maxAgentSummaryLength)
+}
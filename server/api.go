@@ -6,7 +6,10 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -14,6 +17,8 @@ import (
 
 	"github.com/mattermost/mattermost-plugin-cursor/server/attachments"
 	"github.com/mattermost/mattermost-plugin-cursor/server/cursor"
+	"github.com/mattermost/mattermost-plugin-cursor/server/ghclient"
+	"github.com/mattermost/mattermost-plugin-cursor/server/parser"
 	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
 )
 
@@ -34,6 +39,9 @@ func (p *Plugin) initRouter() *mux.Router {
 	// HITL action button handler (Phase 2).
 	authedRouter.HandleFunc("/actions/hitl-response", p.handleHITLResponse).Methods(http.MethodPost)
 
+	// Effective HITL flags for a prompt preview in the webapp.
+	authedRouter.HandleFunc("/hitl/flags", p.handleGetHITLFlags).Methods(http.MethodPost)
+
 	// Phase 4: REST endpoints for the webapp frontend.
 	authedRouter.HandleFunc("/agents", p.handleGetAgents).Methods(http.MethodGet)
 	authedRouter.HandleFunc("/agents/{id}", p.handleGetAgent).Methods(http.MethodGet)
@@ -46,12 +54,25 @@ func (p *Plugin) initRouter() *mux.Router {
 	authedRouter.HandleFunc("/workflows/{id}", p.handleGetWorkflow).Methods(http.MethodGet)
 
 	// Phase 5: Review loop detail endpoint for the webapp.
+	authedRouter.HandleFunc("/review-loops/dropped-candidates", p.handleGetDroppedCandidates).Methods(http.MethodGet)
 	authedRouter.HandleFunc("/review-loops/{id}", p.handleGetReviewLoop).Methods(http.MethodGet)
+	authedRouter.HandleFunc("/review-loops/{id}/export", p.handleExportReviewLoop).Methods(http.MethodGet)
+	authedRouter.HandleFunc("/review-loops/{id}/reset", p.handleResetReviewLoop).Methods(http.MethodPost)
+	authedRouter.HandleFunc("/review-loops/{id}/snooze", p.handleSnoozeReviewLoop).Methods(http.MethodPost)
+	authedRouter.HandleFunc("/review-loops/{id}/findings/{key}/snooze", p.handleSnoozeReviewFinding).Methods(http.MethodPost)
+	authedRouter.HandleFunc("/review-loops/{id}/idempotency-window", p.handleSetReviewLoopIdempotencyWindow).Methods(http.MethodPost)
 
 	// Admin-only routes.
 	adminRouter := authedRouter.PathPrefix("/admin").Subrouter()
 	adminRouter.Use(p.RequireSystemAdmin)
 	adminRouter.HandleFunc("/health", p.handleHealthCheck).Methods(http.MethodGet)
+	adminRouter.HandleFunc("/review-loops/{id}/iterations/{iteration}", p.handleGetReviewLoopSnapshot).Methods(http.MethodGet)
+
+	// Admin-only webhook delivery debugging.
+	webhookDebugRouter := authedRouter.PathPrefix("/webhooks").Subrouter()
+	webhookDebugRouter.Use(p.RequireSystemAdmin)
+	webhookDebugRouter.HandleFunc("/recent", p.handleGetRecentWebhookDeliveries).Methods(http.MethodGet)
+	webhookDebugRouter.HandleFunc("/{deliveryID}/replay", p.handleReplayWebhookDelivery).Methods(http.MethodPost)
 
 	return router
 }
@@ -159,6 +180,96 @@ func (p *Plugin) handleHealthCheck(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// WebhookDeliveryResponse is the JSON representation of a recorded webhook
+// delivery.
+type WebhookDeliveryResponse struct {
+	DeliveryID string `json:"delivery_id"`
+	Event      string `json:"event"`
+	Action     string `json:"action,omitempty"`
+	Body       string `json:"body"`
+	Truncated  bool   `json:"truncated,omitempty"`
+	Status     int    `json:"status"`
+	Duplicate  bool   `json:"duplicate,omitempty"`
+	ReceivedAt int64  `json:"received_at"`
+}
+
+// WebhookDeliveryListResponse is the JSON response for GET /webhooks/recent.
+type WebhookDeliveryListResponse struct {
+	Deliveries []WebhookDeliveryResponse `json:"deliveries"`
+}
+
+// WebhookReplayResponse is the JSON response for POST /webhooks/{deliveryID}/replay.
+type WebhookReplayResponse struct {
+	DeliveryID string `json:"delivery_id"`
+	Event      string `json:"event"`
+	Status     int    `json:"status"`
+	Response   string `json:"response,omitempty"`
+}
+
+func (p *Plugin) handleGetRecentWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	if !p.getConfiguration().RecordWebhookDeliveries {
+		http.Error(w, "Webhook delivery log is disabled", http.StatusNotFound)
+		return
+	}
+
+	deliveries, err := p.kvstore.ListWebhookDeliveries()
+	if err != nil {
+		p.API.LogError("Failed to list webhook deliveries", "error", err.Error())
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	resp := WebhookDeliveryListResponse{Deliveries: make([]WebhookDeliveryResponse, 0, len(deliveries))}
+	for _, delivery := range deliveries {
+		resp.Deliveries = append(resp.Deliveries, WebhookDeliveryResponse{
+			DeliveryID: delivery.DeliveryID,
+			Event:      delivery.Event,
+			Action:     delivery.Action,
+			Body:       delivery.Body,
+			Truncated:  delivery.Truncated,
+			Status:     delivery.Status,
+			Duplicate:  delivery.Duplicate,
+			ReceivedAt: delivery.ReceivedAt,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (p *Plugin) handleReplayWebhookDelivery(w http.ResponseWriter, r *http.Request) {
+	if !p.getConfiguration().RecordWebhookDeliveries {
+		http.Error(w, "Webhook delivery log is disabled", http.StatusNotFound)
+		return
+	}
+
+	deliveryID := mux.Vars(r)["deliveryID"]
+	delivery, err := p.kvstore.GetWebhookDelivery(deliveryID)
+	if err != nil {
+		p.API.LogError("Failed to get webhook delivery", "delivery", deliveryID, "error", err.Error())
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if delivery == nil {
+		http.Error(w, "Webhook delivery not found", http.StatusNotFound)
+		return
+	}
+	if delivery.Truncated {
+		http.Error(w, "Webhook delivery payload was truncated and cannot be replayed", http.StatusUnprocessableEntity)
+		return
+	}
+
+	status, body := p.replayWebhookDelivery(delivery)
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(WebhookReplayResponse{
+		DeliveryID: delivery.DeliveryID,
+		Event:      delivery.Event,
+		Status:     status,
+		Response:   body,
+	})
+}
+
 // isSystemAdmin checks if the user is a system admin.
 func (p *Plugin) isSystemAdmin(userID string) bool {
 	if p.client == nil {
@@ -180,6 +291,7 @@ type AgentResponse struct {
 	Repository         string `json:"repository"`
 	Branch             string `json:"branch"`
 	TargetBranch       string `json:"target_branch,omitempty"`
+	BaseBranch         string `json:"base_branch,omitempty"`
 	Prompt             string `json:"prompt"`
 	Description        string `json:"description"`
 	PrURL              string `json:"pr_url"`
@@ -196,12 +308,24 @@ type AgentResponse struct {
 	WorkflowPhase      string `json:"workflow_phase,omitempty"`
 	PlanIterationCount int    `json:"plan_iteration_count,omitempty"`
 
+	// ExtraPRURLs lists further PRs the agent opened after PrURL.
+	ExtraPRURLs []string `json:"extra_pr_urls,omitempty"`
+
 	// Review loop fields (populated when agent has an active review loop)
 	ReviewLoopID        string `json:"review_loop_id,omitempty"`
 	ReviewLoopPhase     string `json:"review_loop_phase,omitempty"`
 	ReviewLoopIteration int    `json:"review_loop_iteration,omitempty"`
 }
 
+// agentBaseBranch returns the branch the agent's work targets: the PR base
+// once GitHub has reported one, otherwise the ref the agent was launched from.
+func agentBaseBranch(record *kvstore.AgentRecord) string {
+	if record.BaseBranch != "" {
+		return record.BaseBranch
+	}
+	return record.Branch
+}
+
 // AgentsListResponse is the response from GET /api/v1/agents.
 type AgentsListResponse struct {
 	Agents []AgentResponse `json:"agents"`
@@ -212,6 +336,48 @@ type FollowupRequestBody struct {
 	Message string `json:"message"`
 }
 
+// HITLFlagsRequestBody is the request body for POST /api/v1/hitl/flags.
+type HITLFlagsRequestBody struct {
+	Prompt string `json:"prompt"`
+}
+
+// HITLFlagsResponse reports which HITL stages would be skipped for a prompt.
+type HITLFlagsResponse struct {
+	SkipContextReview bool `json:"skip_context_review"`
+	SkipPlanLoop      bool `json:"skip_plan_loop"`
+}
+
+// SnoozeReviewLoopRequestBody is the request body for
+// POST /api/v1/review-loops/{id}/snooze. Duration accepts Go durations or
+// whole days ("4h", "2d"); an empty value or "off" clears the snooze.
+type SnoozeReviewLoopRequestBody struct {
+	Duration string `json:"duration"`
+}
+
+// SnoozeReviewLoopResponse reports when stale escalation resumes for a loop.
+type SnoozeReviewLoopResponse struct {
+	SnoozeUntil int64 `json:"snooze_until"`
+}
+
+// SnoozeReviewFindingResponse reports the iteration through which a finding
+// is kept out of review feedback dispatches.
+type SnoozeReviewFindingResponse struct {
+	Key                   string `json:"key"`
+	SnoozedUntilIteration int    `json:"snoozed_until_iteration"`
+}
+
+// ReviewLoopIdempotencyWindowRequestBody is the request body for
+// POST /api/v1/review-loops/{id}/idempotency-window. Window accepts Go
+// durations ("10m", "1h"); an empty value, "0" or "off" disables the window.
+type ReviewLoopIdempotencyWindowRequestBody struct {
+	Window string `json:"window"`
+}
+
+// ReviewLoopIdempotencyWindowResponse reports a loop's idempotency window.
+type ReviewLoopIdempotencyWindowResponse struct {
+	IdempotencyWindowSeconds int `json:"idempotency_window_seconds"`
+}
+
 // StatusOKResponse is a generic OK response.
 type StatusOKResponse struct {
 	Status string `json:"status"`
@@ -243,7 +409,9 @@ func (p *Plugin) handleGetAgents(w http.ResponseWriter, r *http.Request) {
 			Repository:   a.Repository,
 			Branch:       a.Branch,
 			TargetBranch: a.TargetBranch,
+			BaseBranch:   agentBaseBranch(a),
 			PrURL:        a.PrURL,
+			ExtraPRURLs:  a.ExtraPRURLs,
 			CursorURL:    fmt.Sprintf("https://cursor.com/agents/%s", a.CursorAgentID),
 			ChannelID:    a.ChannelID,
 			PostID:       a.PostID,
@@ -325,7 +493,7 @@ func (p *Plugin) handleGetAgent(w http.ResponseWriter, r *http.Request) {
 				if remoteAgent.Summary != "" {
 					record.Summary = remoteAgent.Summary
 				}
-				record.UpdatedAt = time.Now().UnixMilli()
+				record.UpdatedAt = p.now().UnixMilli()
 				_ = p.kvstore.SaveAgent(record)
 			}
 		}
@@ -338,14 +506,13 @@ func (p *Plugin) handleGetAgent(w http.ResponseWriter, r *http.Request) {
 	if record.PrURL == "" && record.TargetBranch != "" {
 		ghClient := p.getGitHubClient()
 		if ghClient != nil {
-			parts := strings.SplitN(record.Repository, "/", 2)
-			if len(parts) == 2 {
+			if repoRef, refErr := ghclient.ParseRepoRef(record.Repository); refErr == nil {
 				ctx2, cancel2 := context.WithTimeout(context.Background(), 10*time.Second)
 				defer cancel2()
-				pr, ghErr := ghClient.GetPullRequestByBranch(ctx2, parts[0], parts[1], record.TargetBranch)
+				pr, ghErr := ghClient.GetPullRequestByBranch(ctx2, repoRef.Owner, repoRef.Repo, record.TargetBranch)
 				if ghErr == nil && pr != nil {
 					record.PrURL = pr.GetHTMLURL()
-					record.UpdatedAt = time.Now().UnixMilli()
+					record.UpdatedAt = p.now().UnixMilli()
 					_ = p.kvstore.SaveAgent(record)
 
 					// Bootstrap a review loop if applicable.
@@ -370,7 +537,9 @@ func (p *Plugin) handleGetAgent(w http.ResponseWriter, r *http.Request) {
 		Repository:   record.Repository,
 		Branch:       record.Branch,
 		TargetBranch: record.TargetBranch,
+		BaseBranch:   agentBaseBranch(record),
 		PrURL:        record.PrURL,
+		ExtraPRURLs:  record.ExtraPRURLs,
 		CursorURL:    fmt.Sprintf("https://cursor.com/agents/%s", record.CursorAgentID),
 		ChannelID:    record.ChannelID,
 		PostID:       record.PostID,
@@ -453,12 +622,12 @@ func (p *Plugin) handleAddFollowup(w http.ResponseWriter, r *http.Request) {
 
 	// Post a thread reply via bot.
 	if record.PostID != "" {
-		_, _ = p.API.CreatePost(&model.Post{
-			UserId:    p.getBotUserID(),
+		_, _ = p.API.CreatePost(p.decorateBotPost(&model.Post{
+			UserId:    p.botUserIDForChannel(record.ChannelID),
 			ChannelId: record.ChannelID,
 			RootId:    record.PostID,
 			Message:   fmt.Sprintf(":speech_balloon: Follow-up sent: %s", reqBody.Message),
-		})
+		}))
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -510,8 +679,8 @@ func (p *Plugin) handleCancelAgent(w http.ResponseWriter, r *http.Request) {
 
 	// Update thread.
 	if record.TriggerPostID != "" {
-		p.removeReaction(record.TriggerPostID, "hourglass_flowing_sand")
-		p.addReaction(record.TriggerPostID, "no_entry_sign")
+		p.removeReaction(record.ChannelID, record.TriggerPostID, "hourglass_flowing_sand")
+		p.addReaction(record.ChannelID, record.TriggerPostID, "no_entry_sign")
 	}
 	if record.PostID != "" {
 		cancelAttachment := attachments.BuildStoppedAttachment(
@@ -520,12 +689,12 @@ func (p *Plugin) handleCancelAgent(w http.ResponseWriter, r *http.Request) {
 		cancelAttachment.Title = "Agent was cancelled via the dashboard."
 
 		cancelPost := &model.Post{
-			UserId:    p.getBotUserID(),
+			UserId:    p.botUserIDForChannel(record.ChannelID),
 			ChannelId: record.ChannelID,
 			RootId:    record.PostID,
 		}
 		model.ParseSlackAttachment(cancelPost, []*model.SlackAttachment{cancelAttachment})
-		_, _ = p.API.CreatePost(cancelPost)
+		_, _ = p.API.CreatePost(p.decorateBotPost(cancelPost))
 
 		// Also update the original bot reply post to reflect cancellation.
 		p.updateBotReplyWithAttachment(record.BotReplyPostID, cancelAttachment)
@@ -581,7 +750,7 @@ func (p *Plugin) handleArchiveAgent(w http.ResponseWriter, r *http.Request) {
 	}
 
 	record.Archived = true
-	record.UpdatedAt = time.Now().UnixMilli()
+	record.UpdatedAt = p.now().UnixMilli()
 	if err := p.kvstore.SaveAgent(record); err != nil {
 		p.API.LogError("Failed to save archived agent", "agentID", agentID, "error", err.Error())
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
@@ -612,7 +781,7 @@ func (p *Plugin) handleUnarchiveAgent(w http.ResponseWriter, r *http.Request) {
 	}
 
 	record.Archived = false
-	record.UpdatedAt = time.Now().UnixMilli()
+	record.UpdatedAt = p.now().UnixMilli()
 	if err := p.kvstore.SaveAgent(record); err != nil {
 		p.API.LogError("Failed to save unarchived agent", "agentID", agentID, "error", err.Error())
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
@@ -623,6 +792,41 @@ func (p *Plugin) handleUnarchiveAgent(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(StatusOKResponse{Status: "ok"})
 }
 
+// handleGetHITLFlags resolves the effective HITL flags for the requesting user
+// and a draft prompt, applying the same global -> user -> mention precedence
+// as a real launch. The prompt may include inline flags such as --no-plan and
+// does not need to start with the bot mention.
+func (p *Plugin) handleGetHITLFlags(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	var reqBody HITLFlagsRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	message := reqBody.Prompt
+	botMention := p.findBotMention(message)
+	if botMention == "" {
+		botMention = "@" + p.getBotUsername()
+		message = botMention + " " + message
+	}
+
+	parsed := p.parseMention(userID, message, botMention)
+	if parsed == nil {
+		// No prompt yet; report the user's defaults.
+		parsed = &parser.ParsedMention{}
+	}
+
+	skipReview, skipPlan := p.resolveHITLFlags(parsed, userID)
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(HITLFlagsResponse{
+		SkipContextReview: skipReview,
+		SkipPlanLoop:      skipPlan,
+	})
+}
+
 // WorkflowResponse is the JSON representation of a HITL workflow for the webapp.
 type WorkflowResponse struct {
 	ID                 string `json:"id"`
@@ -649,22 +853,25 @@ type WorkflowResponse struct {
 
 // ReviewLoopResponse is the JSON representation of a review loop for the webapp.
 type ReviewLoopResponse struct {
-	ID            string                    `json:"id"`
-	AgentRecordID string                    `json:"agent_record_id"`
-	WorkflowID    string                    `json:"workflow_id,omitempty"`
-	UserID        string                    `json:"user_id"`
-	ChannelID     string                    `json:"channel_id"`
-	RootPostID    string                    `json:"root_post_id"`
-	TriggerPostID string                    `json:"trigger_post_id"`
-	PRURL         string                    `json:"pr_url"`
-	PRNumber      int                       `json:"pr_number"`
-	Repository    string                    `json:"repository"`
-	Phase         string                    `json:"phase"`
-	Iteration     int                       `json:"iteration"`
-	LastCommitSHA string                    `json:"last_commit_sha,omitempty"`
-	History       []ReviewLoopEventResponse `json:"history"`
-	CreatedAt     int64                     `json:"created_at"`
-	UpdatedAt     int64                     `json:"updated_at"`
+	ID                       string                    `json:"id"`
+	AgentRecordID            string                    `json:"agent_record_id"`
+	WorkflowID               string                    `json:"workflow_id,omitempty"`
+	UserID                   string                    `json:"user_id"`
+	ChannelID                string                    `json:"channel_id"`
+	RootPostID               string                    `json:"root_post_id"`
+	TriggerPostID            string                    `json:"trigger_post_id"`
+	PRURL                    string                    `json:"pr_url"`
+	PRNumber                 int                       `json:"pr_number"`
+	Repository               string                    `json:"repository"`
+	BaseBranch               string                    `json:"base_branch,omitempty"`
+	Phase                    string                    `json:"phase"`
+	Iteration                int                       `json:"iteration"`
+	LastCommitSHA            string                    `json:"last_commit_sha,omitempty"`
+	SnoozeUntil              int64                     `json:"snooze_until,omitempty"`
+	IdempotencyWindowSeconds int                       `json:"idempotency_window_seconds,omitempty"`
+	History                  []ReviewLoopEventResponse `json:"history"`
+	CreatedAt                int64                     `json:"created_at"`
+	UpdatedAt                int64                     `json:"updated_at"`
 }
 
 // ReviewLoopEventResponse is the JSON representation of a review loop timeline event.
@@ -672,6 +879,14 @@ type ReviewLoopEventResponse struct {
 	Phase     string `json:"phase"`
 	Timestamp int64  `json:"timestamp"`
 	Detail    string `json:"detail,omitempty"`
+
+	// Feedback dispatch outcome, set on dispatch events.
+	Mode        string `json:"mode,omitempty"`
+	New         int    `json:"new,omitempty"`
+	Repeated    int    `json:"repeated,omitempty"`
+	Dismissed   int    `json:"dismissed,omitempty"`
+	DispatchSHA string `json:"dispatch_sha,omitempty"`
+	Digest      string `json:"digest,omitempty"`
 }
 
 func (p *Plugin) handleGetReviewLoop(w http.ResponseWriter, r *http.Request) {
@@ -689,36 +904,552 @@ func (p *Plugin) handleGetReviewLoop(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	history := make([]ReviewLoopEventResponse, 0, len(loop.History))
-	for _, evt := range loop.History {
+	resp := ReviewLoopResponse{
+		ID:                       loop.ID,
+		AgentRecordID:            loop.AgentRecordID,
+		WorkflowID:               loop.WorkflowID,
+		UserID:                   loop.UserID,
+		ChannelID:                loop.ChannelID,
+		RootPostID:               loop.RootPostID,
+		TriggerPostID:            loop.TriggerPostID,
+		PRURL:                    loop.PRURL,
+		PRNumber:                 loop.PRNumber,
+		Repository:               loop.Repository,
+		BaseBranch:               loop.BaseBranch,
+		Phase:                    loop.Phase,
+		Iteration:                loop.Iteration,
+		LastCommitSHA:            loop.LastCommitSHA,
+		SnoozeUntil:              loop.SnoozeUntil,
+		IdempotencyWindowSeconds: loop.IdempotencyWindowSeconds,
+		History:                  reviewLoopEventResponses(loop.History),
+		CreatedAt:                loop.CreatedAt,
+		UpdatedAt:                loop.UpdatedAt,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func reviewLoopEventResponses(events []kvstore.ReviewLoopEvent) []ReviewLoopEventResponse {
+	history := make([]ReviewLoopEventResponse, 0, len(events))
+	for _, evt := range events {
 		history = append(history, ReviewLoopEventResponse{
-			Phase:     evt.Phase,
-			Timestamp: evt.Timestamp,
-			Detail:    evt.Detail,
+			Phase:       evt.Phase,
+			Timestamp:   evt.Timestamp,
+			Detail:      evt.Detail,
+			Mode:        evt.Mode,
+			New:         evt.New,
+			Repeated:    evt.Repeated,
+			Dismissed:   evt.Dismissed,
+			DispatchSHA: evt.DispatchSHA,
+			Digest:      evt.Digest,
 		})
 	}
+	return history
+}
 
-	resp := ReviewLoopResponse{
-		ID:            loop.ID,
-		AgentRecordID: loop.AgentRecordID,
-		WorkflowID:    loop.WorkflowID,
-		UserID:        loop.UserID,
-		ChannelID:     loop.ChannelID,
-		RootPostID:    loop.RootPostID,
-		TriggerPostID: loop.TriggerPostID,
+// ReviewLoopSnapshotResponse is the JSON response for
+// GET /api/v1/admin/review-loops/{id}/iterations/{iteration}: the loop's
+// findings and history as reconstructed for a past iteration.
+type ReviewLoopSnapshotResponse struct {
+	ReviewLoopID     string                          `json:"review_loop_id"`
+	Iteration        int                             `json:"iteration"`
+	CurrentIteration int                             `json:"current_iteration"`
+	Phase            string                          `json:"phase,omitempty"`
+	Findings         []ReviewFindingSnapshotResponse `json:"findings"`
+	History          []ReviewLoopEventResponse       `json:"history"`
+}
+
+// ReviewFindingSnapshotResponse is a finding as it stood at the snapshot
+// iteration. Classification is new, repeated, unseen (open but not reported
+// at that iteration), resolved, dismissed, or superseded.
+type ReviewFindingSnapshotResponse struct {
+	Key                string `json:"key"`
+	ShortID            string `json:"short_id,omitempty"`
+	Status             string `json:"status"`
+	Classification     string `json:"classification"`
+	Dispatchable       bool   `json:"dispatchable"`
+	ReviewerLogin      string `json:"reviewer_login,omitempty"`
+	ReviewerType       string `json:"reviewer_type,omitempty"`
+	Path               string `json:"path,omitempty"`
+	Line               int    `json:"line,omitempty"`
+	Severity           string `json:"severity,omitempty"`
+	ActionableText     string `json:"actionable_text,omitempty"`
+	FirstSeenIteration int    `json:"first_seen_iteration"`
+	LastSeenIteration  int    `json:"last_seen_iteration"`
+}
+
+// handleGetReviewLoopSnapshot reconstructs a review loop's findings and
+// history as of a past iteration for debugging classifications. It is
+// read-only and uses only stored state.
+func (p *Plugin) handleGetReviewLoopSnapshot(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	reviewLoopID := vars["id"]
+
+	iteration, err := strconv.Atoi(vars["iteration"])
+	if err != nil || iteration < 1 {
+		http.Error(w, "Iteration must be a positive number", http.StatusBadRequest)
+		return
+	}
+
+	loop, err := p.kvstore.GetReviewLoop(reviewLoopID)
+	if err != nil {
+		p.API.LogError("Failed to get review loop", "reviewLoopID", reviewLoopID, "error", err.Error())
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if loop == nil {
+		http.Error(w, "Review loop not found", http.StatusNotFound)
+		return
+	}
+	if iteration > loop.Iteration {
+		http.Error(w, fmt.Sprintf("Review loop is only at iteration %d", loop.Iteration), http.StatusBadRequest)
+		return
+	}
+
+	snapshot := reviewLoopSnapshotAt(loop, iteration)
+	resp := ReviewLoopSnapshotResponse{
+		ReviewLoopID:     loop.ID,
+		Iteration:        snapshot.Iteration,
+		CurrentIteration: loop.Iteration,
+		Phase:            snapshot.Phase,
+		Findings:         make([]ReviewFindingSnapshotResponse, 0, len(snapshot.Findings)),
+		History:          reviewLoopEventResponses(snapshot.History),
+	}
+	for _, entry := range snapshot.Findings {
+		resp.Findings = append(resp.Findings, ReviewFindingSnapshotResponse{
+			Key:                entry.Finding.Key,
+			ShortID:            entry.Finding.ShortID,
+			Status:             entry.Status,
+			Classification:     entry.Classification,
+			Dispatchable:       entry.Dispatchable,
+			ReviewerLogin:      entry.Finding.ReviewerLogin,
+			ReviewerType:       entry.Finding.ReviewerType,
+			Path:               entry.Finding.Path,
+			Line:               entry.Finding.Line,
+			Severity:           entry.Finding.Severity,
+			ActionableText:     entry.Finding.ActionableText,
+			FirstSeenIteration: entry.Finding.FirstSeenIteration,
+			LastSeenIteration:  entry.Finding.LastSeenIteration,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// reviewLoopExportSchemaVersion is bumped whenever a field of
+// ReviewLoopExportResponse changes meaning or is removed; adding fields keeps
+// the version.
+const reviewLoopExportSchemaVersion = 1
+
+// ReviewLoopExportResponse is the audit trail of a review loop returned by
+// GET /api/v1/review-loops/{id}/export: its lifecycle, every history event
+// with its dispatch telemetry, every finding with its status timeline, and
+// the feedback dropped before classification.
+type ReviewLoopExportResponse struct {
+	SchemaVersion     int                           `json:"schema_version"`
+	ExportedAt        int64                         `json:"exported_at"`
+	ReviewLoop        ReviewLoopExportSummary       `json:"review_loop"`
+	History           []ReviewLoopEventResponse     `json:"history"`
+	Findings          []ReviewFindingExportResponse `json:"findings"`
+	DroppedCandidates []DroppedCandidateResponse    `json:"dropped_candidates"`
+}
+
+// ReviewLoopExportSummary is the exported loop's identity and final state.
+type ReviewLoopExportSummary struct {
+	ID                      string `json:"id"`
+	AgentRecordID           string `json:"agent_record_id"`
+	WorkflowID              string `json:"workflow_id,omitempty"`
+	UserID                  string `json:"user_id"`
+	ChannelID               string `json:"channel_id"`
+	PRURL                   string `json:"pr_url"`
+	PRNumber                int    `json:"pr_number"`
+	Repository              string `json:"repository"`
+	Phase                   string `json:"phase"`
+	Iteration               int    `json:"iteration"`
+	LastCommitSHA           string `json:"last_commit_sha,omitempty"`
+	LastFeedbackDispatchAt  int64  `json:"last_feedback_dispatch_at,omitempty"`
+	LastFeedbackDispatchSHA string `json:"last_feedback_dispatch_sha,omitempty"`
+	LastFeedbackDigest      string `json:"last_feedback_digest,omitempty"`
+	CreatedAt               int64  `json:"created_at"`
+	UpdatedAt               int64  `json:"updated_at"`
+}
+
+// ReviewFindingExportResponse is an exported finding. Timeline lists its
+// status from the iteration each status took effect.
+type ReviewFindingExportResponse struct {
+	Key                string                        `json:"key"`
+	ShortID            string                        `json:"short_id,omitempty"`
+	Status             string                        `json:"status"`
+	ResolvedBy         string                        `json:"resolved_by,omitempty"`
+	SourceType         string                        `json:"source_type,omitempty"`
+	SourceURL          string                        `json:"source_url,omitempty"`
+	ReviewerLogin      string                        `json:"reviewer_login,omitempty"`
+	ReviewerType       string                        `json:"reviewer_type,omitempty"`
+	Path               string                        `json:"path,omitempty"`
+	Line               int                           `json:"line,omitempty"`
+	CommitSHA          string                        `json:"commit_sha,omitempty"`
+	Severity           string                        `json:"severity,omitempty"`
+	ActionableText     string                        `json:"actionable_text,omitempty"`
+	FirstSeenAt        int64                         `json:"first_seen_at,omitempty"`
+	LastSeenAt         int64                         `json:"last_seen_at,omitempty"`
+	FirstSeenIteration int                           `json:"first_seen_iteration"`
+	LastSeenIteration  int                           `json:"last_seen_iteration"`
+	AcknowledgedAt     int64                         `json:"acknowledged_at,omitempty"`
+	Timeline           []FindingStatusChangeResponse `json:"timeline"`
+}
+
+// FindingStatusChangeResponse is one entry of a finding's status timeline.
+type FindingStatusChangeResponse struct {
+	Iteration int    `json:"iteration"`
+	StartedAt int64  `json:"started_at,omitempty"`
+	Status    string `json:"status"`
+}
+
+// handleExportReviewLoop returns the review loop's audit trail as a JSON
+// download. Only the loop's owner may export it.
+func (p *Plugin) handleExportReviewLoop(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("Mattermost-User-ID")
+	reviewLoopID := mux.Vars(r)["id"]
+
+	loop, err := p.kvstore.GetReviewLoop(reviewLoopID)
+	if err != nil {
+		p.API.LogError("Failed to get review loop", "reviewLoopID", reviewLoopID, "error", err.Error())
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if loop == nil || loop.UserID != userID {
+		http.Error(w, "Review loop not found", http.StatusNotFound)
+		return
+	}
+
+	resp := ReviewLoopExportResponse{
+		SchemaVersion: reviewLoopExportSchemaVersion,
+		ExportedAt:    p.now().UnixMilli(),
+		ReviewLoop: ReviewLoopExportSummary{
+			ID:                      loop.ID,
+			AgentRecordID:           loop.AgentRecordID,
+			WorkflowID:              loop.WorkflowID,
+			UserID:                  loop.UserID,
+			ChannelID:               loop.ChannelID,
+			PRURL:                   loop.PRURL,
+			PRNumber:                loop.PRNumber,
+			Repository:              loop.Repository,
+			Phase:                   loop.Phase,
+			Iteration:               loop.Iteration,
+			LastCommitSHA:           loop.LastCommitSHA,
+			LastFeedbackDispatchAt:  loop.LastFeedbackDispatchAt,
+			LastFeedbackDispatchSHA: loop.LastFeedbackDispatchSHA,
+			LastFeedbackDigest:      loop.LastFeedbackDigest,
+			CreatedAt:               loop.CreatedAt,
+			UpdatedAt:               loop.UpdatedAt,
+		},
+		History:           reviewLoopEventResponses(loop.History),
+		Findings:          make([]ReviewFindingExportResponse, 0, len(loop.Findings)),
+		DroppedCandidates: make([]DroppedCandidateResponse, 0, len(loop.DroppedCandidates)),
+	}
+
+	timelines := findingStatusTimelines(loop)
+	for _, finding := range loop.Findings {
+		status := finding.Status
+		if status == "" {
+			status = findingStatusOpen
+		}
+		timeline := make([]FindingStatusChangeResponse, 0, len(timelines[finding.Key]))
+		for _, change := range timelines[finding.Key] {
+			timeline = append(timeline, FindingStatusChangeResponse{
+				Iteration: change.Iteration,
+				StartedAt: change.StartedAt,
+				Status:    change.Status,
+			})
+		}
+		resp.Findings = append(resp.Findings, ReviewFindingExportResponse{
+			Key:                finding.Key,
+			ShortID:            finding.ShortID,
+			Status:             status,
+			ResolvedBy:         finding.ResolvedBy,
+			SourceType:         finding.SourceType,
+			SourceURL:          finding.SourceURL,
+			ReviewerLogin:      finding.ReviewerLogin,
+			ReviewerType:       finding.ReviewerType,
+			Path:               finding.Path,
+			Line:               finding.Line,
+			CommitSHA:          finding.CommitSHA,
+			Severity:           finding.Severity,
+			ActionableText:     finding.ActionableText,
+			FirstSeenAt:        finding.FirstSeenAt,
+			LastSeenAt:         finding.LastSeenAt,
+			FirstSeenIteration: finding.FirstSeenIteration,
+			LastSeenIteration:  finding.LastSeenIteration,
+			AcknowledgedAt:     finding.AcknowledgedAt,
+			Timeline:           timeline,
+		})
+	}
+	for _, candidate := range loop.DroppedCandidates {
+		resp.DroppedCandidates = append(resp.DroppedCandidates, droppedCandidateResponse(loop, candidate))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "review-loop-"+loop.ID+".json"))
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// DroppedCandidateResponse is the JSON representation of a review feedback
+// candidate dropped by one of the user's review loops.
+type DroppedCandidateResponse struct {
+	ReviewLoopID  string `json:"review_loop_id"`
+	PRURL         string `json:"pr_url"`
+	Repository    string `json:"repository"`
+	Reason        string `json:"reason"`
+	Route         string `json:"route"`
+	SourceType    string `json:"source_type"`
+	SourceURL     string `json:"source_url,omitempty"`
+	ReviewerLogin string `json:"reviewer_login,omitempty"`
+	Path          string `json:"path,omitempty"`
+	Line          int    `json:"line,omitempty"`
+	CommitSHA     string `json:"commit_sha,omitempty"`
+	Iteration     int    `json:"iteration"`
+	DroppedAt     int64  `json:"dropped_at"`
+}
+
+// DroppedCandidatesResponse is the JSON response for GET /review-loops/dropped-candidates.
+type DroppedCandidatesResponse struct {
+	Candidates []DroppedCandidateResponse `json:"candidates"`
+}
+
+// handleGetDroppedCandidates returns the recent dropped feedback candidates
+// across the requesting user's active review loops, newest first.
+func (p *Plugin) handleGetDroppedCandidates(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("Mattermost-User-ID")
+
+	loops, err := p.kvstore.ListActiveReviewLoops()
+	if err != nil {
+		p.API.LogError("Failed to list active review loops", "error", err.Error())
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	candidates := []DroppedCandidateResponse{}
+	for _, loop := range loops {
+		if loop.UserID != userID {
+			continue
+		}
+		for _, dropped := range loop.DroppedCandidates {
+			candidates = append(candidates, droppedCandidateResponse(loop, dropped))
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].DroppedAt > candidates[j].DroppedAt
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(DroppedCandidatesResponse{Candidates: candidates})
+}
+
+func droppedCandidateResponse(loop *kvstore.ReviewLoop, dropped kvstore.DroppedCandidate) DroppedCandidateResponse {
+	return DroppedCandidateResponse{
+		ReviewLoopID:  loop.ID,
 		PRURL:         loop.PRURL,
-		PRNumber:      loop.PRNumber,
 		Repository:    loop.Repository,
-		Phase:         loop.Phase,
-		Iteration:     loop.Iteration,
-		LastCommitSHA: loop.LastCommitSHA,
-		History:       history,
-		CreatedAt:     loop.CreatedAt,
-		UpdatedAt:     loop.UpdatedAt,
+		Reason:        dropped.Reason,
+		Route:         dropped.Route,
+		SourceType:    dropped.SourceType,
+		SourceURL:     dropped.SourceURL,
+		ReviewerLogin: dropped.ReviewerLogin,
+		Path:          dropped.Path,
+		Line:          dropped.Line,
+		CommitSHA:     dropped.CommitSHA,
+		Iteration:     dropped.Iteration,
+		DroppedAt:     dropped.DroppedAt,
 	}
+}
+
+// handleResetReviewLoop moves an errored review loop back to awaiting_review
+// so the next AI review drives it again. Only the loop owner may reset it.
+func (p *Plugin) handleResetReviewLoop(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("Mattermost-User-ID")
+	reviewLoopID := mux.Vars(r)["id"]
+
+	loop, err := p.kvstore.GetReviewLoop(reviewLoopID)
+	if err != nil {
+		p.API.LogError("Failed to get review loop", "reviewLoopID", reviewLoopID, "error", err.Error())
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if loop == nil || loop.UserID != userID {
+		http.Error(w, "Review loop not found", http.StatusNotFound)
+		return
+	}
+	if loop.Phase != kvstore.ReviewPhaseError {
+		http.Error(w, "Review loop is not in the error phase", http.StatusBadRequest)
+		return
+	}
+
+	now := p.now().UnixMilli()
+	// Any half-finished dispatch is abandoned; the next review is collected fresh.
+	clearDispatchCheckpoint(loop)
+	loop.Phase = kvstore.ReviewPhaseAwaitingReview
+	loop.History = append(loop.History, kvstore.ReviewLoopEvent{
+		Phase:     kvstore.ReviewPhaseAwaitingReview,
+		Timestamp: now,
+		Detail:    "Reset from error by owner",
+	})
+	loop.UpdatedAt = now
+	if err := p.kvstore.SaveReviewLoop(loop); err != nil {
+		p.API.LogError("Failed to save reset review loop", "reviewLoopID", reviewLoopID, "error", err.Error())
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	p.updateReviewLoopInlineStatus(loop)
+	p.publishReviewLoopChange(loop)
+	p.swapReaction(loop.ChannelID, loop.TriggerPostID, "x", "eyes")
 
 	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(resp)
+	_ = json.NewEncoder(w).Encode(StatusOKResponse{Status: "ok"})
+}
+
+func (p *Plugin) handleSnoozeReviewLoop(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("Mattermost-User-ID")
+	reviewLoopID := mux.Vars(r)["id"]
+
+	var reqBody SnoozeReviewLoopRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	var until int64
+	if duration := strings.TrimSpace(reqBody.Duration); duration != "" && !strings.EqualFold(duration, "off") {
+		d, err := parser.ParseSnoozeDuration(duration)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		until = p.now().Add(d).UnixMilli()
+	}
+
+	loop, err := p.kvstore.GetReviewLoop(reviewLoopID)
+	if err != nil {
+		p.API.LogError("Failed to get review loop", "reviewLoopID", reviewLoopID, "error", err.Error())
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if loop == nil || loop.UserID != userID {
+		http.Error(w, "Review loop not found", http.StatusNotFound)
+		return
+	}
+	if kvstore.IsReviewPhaseTerminal(loop.Phase) {
+		http.Error(w, "Review loop has already finished", http.StatusBadRequest)
+		return
+	}
+
+	p.snoozeReviewLoop(loop, until)
+	if err := p.kvstore.SaveReviewLoop(loop); err != nil {
+		p.API.LogError("Failed to save snoozed review loop", "reviewLoopID", reviewLoopID, "error", err.Error())
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	p.publishReviewLoopChange(loop)
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(SnoozeReviewLoopResponse{SnoozeUntil: until})
+}
+
+// handleSnoozeReviewFinding keeps one open finding out of the current
+// iteration's dispatch. It is reconsidered once the loop moves past it.
+func (p *Plugin) handleSnoozeReviewFinding(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("Mattermost-User-ID")
+	vars := mux.Vars(r)
+	reviewLoopID := vars["id"]
+	findingKey := vars["key"]
+
+	loop, err := p.kvstore.GetReviewLoop(reviewLoopID)
+	if err != nil {
+		p.API.LogError("Failed to get review loop", "reviewLoopID", reviewLoopID, "error", err.Error())
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if loop == nil || loop.UserID != userID {
+		http.Error(w, "Review loop not found", http.StatusNotFound)
+		return
+	}
+	if kvstore.IsReviewPhaseTerminal(loop.Phase) {
+		http.Error(w, "Review loop has already finished", http.StatusBadRequest)
+		return
+	}
+
+	finding := snoozeReviewFinding(loop, findingKey)
+	if finding == nil {
+		http.Error(w, "Open finding not found", http.StatusNotFound)
+		return
+	}
+
+	loop.UpdatedAt = p.now().UnixMilli()
+	if err := p.kvstore.SaveReviewLoop(loop); err != nil {
+		p.API.LogError("Failed to save snoozed review finding", "reviewLoopID", reviewLoopID, "error", err.Error())
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	p.publishReviewLoopChange(loop)
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(SnoozeReviewFindingResponse{
+		Key:                   finding.Key,
+		SnoozedUntilIteration: finding.SnoozedUntilIteration,
+	})
+}
+
+func (p *Plugin) handleSetReviewLoopIdempotencyWindow(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("Mattermost-User-ID")
+	reviewLoopID := mux.Vars(r)["id"]
+
+	var reqBody ReviewLoopIdempotencyWindowRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	var seconds int
+	if window := strings.TrimSpace(reqBody.Window); window != "" && window != "0" && !strings.EqualFold(window, "off") {
+		d, err := time.ParseDuration(window)
+		if err != nil || d < time.Second {
+			http.Error(w, "Invalid idempotency window, expected a duration like 10m", http.StatusBadRequest)
+			return
+		}
+		seconds = int(d / time.Second)
+	}
+
+	loop, err := p.kvstore.GetReviewLoop(reviewLoopID)
+	if err != nil {
+		p.API.LogError("Failed to get review loop", "reviewLoopID", reviewLoopID, "error", err.Error())
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if loop == nil || loop.UserID != userID {
+		http.Error(w, "Review loop not found", http.StatusNotFound)
+		return
+	}
+	if kvstore.IsReviewPhaseTerminal(loop.Phase) {
+		http.Error(w, "Review loop has already finished", http.StatusBadRequest)
+		return
+	}
+
+	loop.IdempotencyWindowSeconds = seconds
+	loop.UpdatedAt = p.now().UnixMilli()
+	if err := p.kvstore.SaveReviewLoop(loop); err != nil {
+		p.API.LogError("Failed to save review loop idempotency window", "reviewLoopID", reviewLoopID, "error", err.Error())
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	p.publishReviewLoopChange(loop)
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(ReviewLoopIdempotencyWindowResponse{IdempotencyWindowSeconds: seconds})
 }
 
 func (p *Plugin) handleGetWorkflow(w http.ResponseWriter, r *http.Request) {
@@ -796,7 +1527,7 @@ func (p *Plugin) reconcileRejectedImplementerWorkflowPhase(
 
 	updated := *workflow
 	updated.Phase = desiredPhase
-	updated.UpdatedAt = time.Now().UnixMilli()
+	updated.UpdatedAt = p.now().UnixMilli()
 	if err := p.kvstore.SaveWorkflow(&updated); err != nil {
 		p.API.LogError("Failed to reconcile stale workflow phase",
 			"workflow_id", workflow.ID,
@@ -837,7 +1568,11 @@ func (p *Plugin) handleHITLResponse(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Step 3: Load the workflow.
+	// Step 3: Load the workflow while holding its lock, so a concurrent click
+	// (double-click, second tab) waits and then sees the phase this one claims.
+	unlock := p.lockWorkflow(workflowID)
+	defer unlock()
+
 	workflow, err := p.kvstore.GetWorkflow(workflowID)
 	if err != nil {
 		p.API.LogError("Failed to get workflow for HITL action",
@@ -891,44 +1626,95 @@ func (p *Plugin) handleHITLResponse(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Step 6: Handle the action.
+	// Accepting a partial plan only leaves planning once a non-empty plan has
+	// been read from the planner, so it claims no phase here.
+	if action == "accept-partial" {
+		if phase != kvstore.PhasePlanning || workflow.Phase != kvstore.PhasePlanning {
+			p.sendEphemeralToActionUser(request, "This planning pass has already finished.")
+			p.writePostActionResponseAttachment(w, nil)
+			return
+		}
+		p.writePostActionResponseAttachment(w, nil)
+		go p.acceptPartialPlan(workflow.ID)
+		return
+	}
+
+	// Step 6: Resolve the action to its response attachment, the phase it
+	// moves the workflow to, and the follow-up work.
+	var (
+		responseAttachment *model.SlackAttachment
+		nextPhase          string
+		followUp           func(*kvstore.HITLWorkflow)
+	)
 	switch action {
 	case "accept":
 		switch phase {
 		case kvstore.PhaseContextReview:
-			acceptedAttachment := attachments.BuildContextAcceptedAttachment(
+			responseAttachment = attachments.BuildContextAcceptedAttachment(
 				workflow.Repository, workflow.Branch, workflow.Model, username,
 			)
-			p.writePostActionResponseAttachment(w, acceptedAttachment)
-			go p.acceptContext(workflow)
+			nextPhase = kvstore.PhasePlanning
+			if workflow.SkipPlanLoop {
+				nextPhase = kvstore.PhaseImplementing
+			}
+			followUp = p.acceptContext
 		case kvstore.PhasePlanReview:
-			acceptedAttachment := attachments.BuildPlanAcceptedAttachment(username, workflow.PlanIterationCount)
-			p.writePostActionResponseAttachment(w, acceptedAttachment)
-			go p.acceptPlan(workflow)
+			responseAttachment = attachments.BuildPlanAcceptedAttachment(username, workflow.PlanIterationCount)
+			nextPhase = kvstore.PhaseImplementing
+			followUp = p.acceptPlan
 		default:
 			p.API.LogError("Unknown HITL phase for accept", "phase", phase, "action", action)
 			p.writePostActionResponseAttachment(w, nil)
+			return
 		}
 
 	case "reject":
 		switch phase {
 		case kvstore.PhaseContextReview:
-			rejectedAttachment := attachments.BuildContextRejectedAttachment(username)
-			p.writePostActionResponseAttachment(w, rejectedAttachment)
-			go p.rejectWorkflow(workflow)
+			responseAttachment = attachments.BuildContextRejectedAttachment(username)
 		case kvstore.PhasePlanReview:
-			rejectedAttachment := attachments.BuildPlanRejectedAttachment(username)
-			p.writePostActionResponseAttachment(w, rejectedAttachment)
-			go p.rejectWorkflow(workflow)
+			responseAttachment = attachments.BuildPlanRejectedAttachment(username)
 		default:
 			p.API.LogError("Unknown HITL phase for reject", "phase", phase, "action", action)
 			p.writePostActionResponseAttachment(w, nil)
+			return
 		}
+		nextPhase = kvstore.PhaseRejected
+		followUp = p.rejectWorkflow
 
 	default:
 		p.API.LogError("Unknown HITL action", "action", action)
 		p.writePostActionResponseAttachment(w, nil)
+		return
 	}
+
+	// Step 7: Claim the transition before releasing the lock, then run the
+	// slow follow-up work asynchronously.
+	workflow.Phase = nextPhase
+	workflow.UpdatedAt = p.now().UnixMilli()
+	if err := p.kvstore.SaveWorkflow(workflow); err != nil {
+		p.API.LogError("Failed to save workflow for HITL action",
+			"workflow_id", workflowID,
+			"action", action,
+			"phase", phase,
+			"error", err.Error(),
+		)
+		p.sendEphemeralToActionUser(request, "Failed to record your response. Please try again.")
+		p.writePostActionResponseAttachment(w, nil)
+		return
+	}
+
+	p.writePostActionResponseAttachment(w, responseAttachment)
+	go followUp(workflow)
+}
+
+// lockWorkflow locks the HITL workflow with the given ID and returns the
+// function that unlocks it.
+func (p *Plugin) lockWorkflow(workflowID string) func() {
+	lock, _ := p.workflowLocks.LoadOrStore(workflowID, &sync.Mutex{})
+	mu := lock.(*sync.Mutex)
+	mu.Lock()
+	return mu.Unlock
 }
 
 // writePostActionResponseAttachment writes a PostActionIntegrationResponse.
@@ -956,10 +1742,10 @@ func (p *Plugin) sendEphemeralToActionUser(request model.PostActionIntegrationRe
 		rootID = post.RootId
 	}
 
-	_ = p.API.SendEphemeralPost(request.UserId, &model.Post{
-		UserId:    p.getBotUserID(),
+	_ = p.API.SendEphemeralPost(request.UserId, p.decorateBotPost(&model.Post{
+		UserId:    p.botUserIDForChannel(request.ChannelId),
 		ChannelId: request.ChannelId,
 		RootId:    rootID,
 		Message:   message,
-	})
+	}))
 }
@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/go-github/v68/github"
 	"github.com/mattermost/mattermost/server/public/model"
@@ -46,6 +48,8 @@ func setupAPITestPlugin(t *testing.T) (*Plugin, *plugintest.API, *mockCursorClie
 
 	cursorClient := &mockCursorClient{}
 	store := &mockKVStore{}
+	// Launches record the user's launch history; tests that care assert it.
+	store.On("AddUserLaunchHistoryEntry", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
 
 	p := &Plugin{}
 	p.SetAPI(api)
@@ -113,6 +117,7 @@ func TestGetAgents_Success(t *testing.T) {
 			Status:        "FINISHED",
 			Repository:    "org/repo2",
 			Branch:        "develop",
+			BaseBranch:    "release-2.0",
 			ChannelID:     "ch-2",
 			PostID:        "post-2",
 			UserID:        "user-1",
@@ -133,9 +138,11 @@ func TestGetAgents_Success(t *testing.T) {
 	assert.Equal(t, "agent-1", resp.Agents[0].ID)
 	assert.Equal(t, "RUNNING", resp.Agents[0].Status)
 	assert.Equal(t, "cursor/fix-login", resp.Agents[0].TargetBranch)
+	assert.Equal(t, "main", resp.Agents[0].BaseBranch)
 	assert.Equal(t, "post-1", resp.Agents[0].RootPostID)
 	assert.Equal(t, "agent-2", resp.Agents[1].ID)
 	assert.Equal(t, "https://github.com/org/repo2/pull/1", resp.Agents[1].PrURL)
+	assert.Equal(t, "release-2.0", resp.Agents[1].BaseBranch)
 	assert.Equal(t, "post-2", resp.Agents[1].RootPostID)
 }
 
@@ -195,10 +202,40 @@ func TestGetAgent_Success(t *testing.T) {
 	assert.Equal(t, "agent-1", resp.ID)
 	assert.Equal(t, "RUNNING", resp.Status)
 	assert.Equal(t, "cursor/fix-login", resp.TargetBranch)
+	assert.Equal(t, "main", resp.BaseBranch)
 	assert.Equal(t, "https://cursor.com/agents/agent-1", resp.CursorURL)
 	assert.Equal(t, "post-1", resp.RootPostID)
 }
 
+func TestGetAgent_BaseBranchFromPR(t *testing.T) {
+	p, _, _, store := setupAPITestPlugin(t)
+
+	record := &kvstore.AgentRecord{
+		CursorAgentID: "agent-1",
+		Status:        "FINISHED",
+		Repository:    "org/repo",
+		Branch:        "main",
+		TargetBranch:  "cursor/fix-login",
+		BaseBranch:    "release-1.0",
+		PrURL:         "https://github.com/org/repo/pull/7",
+		PostID:        "post-1",
+		UserID:        "user-1",
+	}
+
+	store.On("GetAgent", "agent-1").Return(record, nil)
+	store.On("GetWorkflowByAgent", "agent-1").Return("", nil)
+	store.On("GetReviewLoopByAgent", "agent-1").Return(nil, nil)
+
+	rr := doRequest(p, http.MethodGet, "/api/v1/agents/agent-1", nil, "user-1")
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var resp AgentResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, "main", resp.Branch)
+	assert.Equal(t, "cursor/fix-login", resp.TargetBranch)
+	assert.Equal(t, "release-1.0", resp.BaseBranch)
+}
+
 func TestGetAgent_NotFound(t *testing.T) {
 	p, _, _, store := setupAPITestPlugin(t)
 
@@ -344,6 +381,108 @@ func TestAddFollowup_AgentNotFound(t *testing.T) {
 	assert.Equal(t, http.StatusNotFound, rr.Code)
 }
 
+// --- POST /api/v1/hitl/flags ---
+
+func TestGetHITLFlags_Precedence(t *testing.T) {
+	tests := []struct {
+		name             string
+		enableReview     bool
+		enablePlan       bool
+		userSettings     *kvstore.UserSettings
+		prompt           string
+		expectSkipReview bool
+		expectSkipPlan   bool
+	}{
+		{
+			name:             "global defaults enabled",
+			enableReview:     true,
+			enablePlan:       true,
+			prompt:           "fix the login bug",
+			expectSkipReview: false,
+			expectSkipPlan:   false,
+		},
+		{
+			name:             "global defaults disabled",
+			prompt:           "fix the login bug",
+			expectSkipReview: true,
+			expectSkipPlan:   true,
+		},
+		{
+			name:         "user settings override global",
+			enableReview: true,
+			enablePlan:   true,
+			userSettings: &kvstore.UserSettings{
+				EnableContextReview: boolPtr(false),
+			},
+			prompt:           "fix the login bug",
+			expectSkipReview: true,
+			expectSkipPlan:   false,
+		},
+		{
+			name: "mention flag overrides user settings",
+			userSettings: &kvstore.UserSettings{
+				EnableContextReview: boolPtr(true),
+				EnablePlanLoop:      boolPtr(true),
+			},
+			prompt:           "--no-plan fix the login bug",
+			expectSkipReview: false,
+			expectSkipPlan:   true,
+		},
+		{
+			name:             "inline option with bot mention",
+			enableReview:     true,
+			enablePlan:       false,
+			prompt:           "@cursor review=off plan=on fix the login bug",
+			expectSkipReview: true,
+			expectSkipPlan:   false,
+		},
+		{
+			name:             "direct skips both stages",
+			enableReview:     true,
+			enablePlan:       true,
+			prompt:           "--direct fix the login bug",
+			expectSkipReview: true,
+			expectSkipPlan:   true,
+		},
+		{
+			name:             "empty prompt reports defaults",
+			enableReview:     true,
+			enablePlan:       false,
+			prompt:           "",
+			expectSkipReview: false,
+			expectSkipPlan:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, _, _, store := setupAPITestPlugin(t)
+			p.configuration.EnableContextReview = tt.enableReview
+			p.configuration.EnablePlanLoop = tt.enablePlan
+			store.On("GetUserSettings", "user-1").Return(tt.userSettings, nil)
+
+			body := HITLFlagsRequestBody{Prompt: tt.prompt}
+			rr := doRequest(p, http.MethodPost, "/api/v1/hitl/flags", body, "user-1")
+			require.Equal(t, http.StatusOK, rr.Code)
+
+			var resp HITLFlagsResponse
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			assert.Equal(t, tt.expectSkipReview, resp.SkipContextReview)
+			assert.Equal(t, tt.expectSkipPlan, resp.SkipPlanLoop)
+		})
+	}
+}
+
+func TestGetHITLFlags_InvalidBody(t *testing.T) {
+	p, _, _, _ := setupAPITestPlugin(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/hitl/flags", bytes.NewBufferString("{not json"))
+	req.Header.Set("Mattermost-User-ID", "user-1")
+	rr := httptest.NewRecorder()
+	p.ServeHTTP(nil, rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
 func TestAddFollowup_CursorAPIError(t *testing.T) {
 	p, _, cursorClient, store := setupAPITestPlugin(t)
 
@@ -584,6 +723,109 @@ func TestHandleHITLResponse_RejectContext(t *testing.T) {
 	assert.NotNil(t, resp.Update)
 }
 
+// workflowStateStore serves HITL workflows from the last saved copy, like the
+// KV store, with a slow read so unserialized clicks would both load the same
+// phase.
+type workflowStateStore struct {
+	*mockKVStore
+
+	mu    sync.Mutex
+	saved kvstore.HITLWorkflow
+}
+
+func (s *workflowStateStore) GetWorkflow(string) (*kvstore.HITLWorkflow, error) {
+	s.mu.Lock()
+	workflow := s.saved
+	s.mu.Unlock()
+	time.Sleep(20 * time.Millisecond)
+	return &workflow, nil
+}
+
+func (s *workflowStateStore) SaveWorkflow(workflow *kvstore.HITLWorkflow) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.saved = *workflow
+	return nil
+}
+
+func TestHandleHITLResponse_ConcurrentAcceptsLaunchOnce(t *testing.T) {
+	p, api, cursorClient, store := setupAPITestPlugin(t)
+
+	workflows := &workflowStateStore{
+		mockKVStore: store,
+		saved: kvstore.HITLWorkflow{
+			ID:              "wf-1",
+			UserID:          "user-1",
+			ChannelID:       "ch-1",
+			RootPostID:      "root-1",
+			TriggerPostID:   "trigger-1",
+			Phase:           kvstore.PhaseContextReview,
+			Repository:      "org/repo",
+			Branch:          "main",
+			Model:           "auto",
+			OriginalPrompt:  "fix the bug",
+			EnrichedContext: "Enriched context text",
+			SkipPlanLoop:    true,
+		},
+	}
+	p.kvstore = workflows
+
+	launched := make(chan struct{}, 2)
+	cursorClient.On("LaunchAgent", mock.Anything, mock.Anything).Run(func(mock.Arguments) {
+		launched <- struct{}{}
+	}).Return(&cursor.Agent{
+		ID:     "agent-impl-1",
+		Status: cursor.AgentStatusCreating,
+	}, nil)
+	api.On("CreatePost", mock.Anything).Return(&model.Post{Id: "reply-1"}, nil).Maybe()
+	api.On("SendEphemeralPost", "user-1", mock.MatchedBy(func(p *model.Post) bool {
+		return containsSubstring(p.Message, "already been resolved")
+	})).Return(nil).Once()
+	store.On("SaveAgent", mock.Anything).Return(nil).Maybe()
+	store.On("SetThreadAgent", "root-1", "agent-impl-1").Return(nil).Maybe()
+	store.On("SetAgentWorkflow", "agent-impl-1", "wf-1").Return(nil).Maybe()
+	api.On("PublishWebSocketEvent", mock.Anything, mock.Anything, mock.Anything).Return().Maybe()
+
+	body := model.PostActionIntegrationRequest{
+		UserId: "user-1",
+		Context: map[string]any{
+			"action":      "accept",
+			"phase":       "context_review",
+			"workflow_id": "wf-1",
+		},
+	}
+
+	var wg sync.WaitGroup
+	responses := make([]*httptest.ResponseRecorder, 2)
+	for i := range responses {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			responses[i] = doRequest(p, http.MethodPost, "/api/v1/actions/hitl-response", body, "user-1")
+		}(i)
+	}
+	wg.Wait()
+
+	for _, rr := range responses {
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var resp model.PostActionIntegrationResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+		assert.NotNil(t, resp.Update)
+	}
+
+	select {
+	case <-launched:
+	case <-time.After(time.Second):
+		t.Fatal("implementer agent was not launched")
+	}
+	select {
+	case <-launched:
+		t.Fatal("implementer agent was launched twice")
+	case <-time.After(100 * time.Millisecond):
+	}
+	api.AssertNumberOfCalls(t, "SendEphemeralPost", 1)
+}
+
 func TestHandleHITLResponse_WrongUser(t *testing.T) {
 	p, api, _, store := setupAPITestPlugin(t)
 
@@ -653,6 +895,37 @@ func TestHandleHITLResponse_PhaseMismatch(t *testing.T) {
 	assert.NotNil(t, resp.Update)
 }
 
+func TestHandleHITLResponse_AcceptPartialAfterPlanningFinished(t *testing.T) {
+	p, api, cursorClient, store := setupAPITestPlugin(t)
+
+	workflow := &kvstore.HITLWorkflow{
+		ID:             "wf-1",
+		UserID:         "user-1",
+		Phase:          kvstore.PhasePlanReview,
+		PlannerAgentID: "planner-1",
+	}
+
+	store.On("GetWorkflow", "wf-1").Return(workflow, nil)
+	api.On("SendEphemeralPost", "user-1", mock.MatchedBy(func(p *model.Post) bool {
+		return containsSubstring(p.Message, "already finished")
+	})).Return(nil).Once()
+
+	body := model.PostActionIntegrationRequest{
+		UserId: "user-1",
+		Context: map[string]any{
+			"action":      "accept-partial",
+			"phase":       "planning",
+			"workflow_id": "wf-1",
+		},
+	}
+	rr := doRequest(p, http.MethodPost, "/api/v1/actions/hitl-response", body, "user-1")
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	api.AssertExpectations(t)
+	cursorClient.AssertNotCalled(t, "GetConversation", mock.Anything, mock.Anything)
+	store.AssertNotCalled(t, "SaveWorkflow", mock.Anything)
+}
+
 func TestHandleHITLResponse_WorkflowNotFound(t *testing.T) {
 	p, api, _, store := setupAPITestPlugin(t)
 
@@ -1161,7 +1434,17 @@ func TestGetReviewLoop_Success(t *testing.T) {
 		LastCommitSHA: "abc123",
 		History: []kvstore.ReviewLoopEvent{
 			{Phase: kvstore.ReviewPhaseRequestingReview, Timestamp: 1000, Detail: "Review requested"},
-			{Phase: kvstore.ReviewPhaseAwaitingReview, Timestamp: 1500, Detail: "Iteration 3 (direct follow-up dispatched; 2 new, 1 repeated, 4 dismissed)"},
+			{
+				Phase:       kvstore.ReviewPhaseAwaitingReview,
+				Timestamp:   1500,
+				Detail:      "Iteration 3 (direct follow-up dispatched; 2 new, 1 repeated, 4 dismissed)",
+				Mode:        "direct",
+				New:         2,
+				Repeated:    1,
+				Dismissed:   4,
+				DispatchSHA: "abc123",
+				Digest:      "digest-1",
+			},
 			{Phase: kvstore.ReviewPhaseCursorFixing, Timestamp: 2000},
 		},
 		CreatedAt: 1000,
@@ -1187,6 +1470,13 @@ func TestGetReviewLoop_Success(t *testing.T) {
 	assert.Equal(t, "Review requested", resp.History[0].Detail)
 	assert.Equal(t, int64(1000), resp.History[0].Timestamp)
 	assert.Equal(t, "Iteration 3 (direct follow-up dispatched; 2 new, 1 repeated, 4 dismissed)", resp.History[1].Detail)
+	assert.Equal(t, "direct", resp.History[1].Mode)
+	assert.Equal(t, 2, resp.History[1].New)
+	assert.Equal(t, 1, resp.History[1].Repeated)
+	assert.Equal(t, 4, resp.History[1].Dismissed)
+	assert.Equal(t, "abc123", resp.History[1].DispatchSHA)
+	assert.Equal(t, "digest-1", resp.History[1].Digest)
+	assert.Empty(t, resp.History[0].Mode)
 	assert.Equal(t, kvstore.ReviewPhaseCursorFixing, resp.History[2].Phase)
 	assert.Empty(t, resp.History[2].Detail)
 }
@@ -1215,6 +1505,277 @@ func TestGetReviewLoop_WrongUser(t *testing.T) {
 	assert.Equal(t, http.StatusNotFound, rr.Code)
 }
 
+// --- POST /api/v1/review-loops/{id}/reset ---
+
+func TestResetReviewLoop_Success(t *testing.T) {
+	p, api, _, store := setupAPITestPlugin(t)
+
+	loop := &kvstore.ReviewLoop{
+		ID:                "loop-1",
+		AgentRecordID:     "agent-1",
+		UserID:            "user-1",
+		TriggerPostID:     "trigger-1",
+		Phase:             kvstore.ReviewPhaseError,
+		Iteration:         2,
+		PendingDispatchID: "loop-1-1700000000000",
+		History: []kvstore.ReviewLoopEvent{
+			{Phase: kvstore.ReviewPhaseError, Timestamp: 1000, Detail: "Failed to dispatch review feedback"},
+		},
+	}
+
+	store.On("GetReviewLoop", "loop-1").Return(loop, nil)
+	store.On("SaveReviewLoop", mock.MatchedBy(func(l *kvstore.ReviewLoop) bool {
+		return l.Phase == kvstore.ReviewPhaseAwaitingReview && l.PendingDispatchID == ""
+	})).Return(nil).Once()
+	store.On("GetAgent", "agent-1").Return(&kvstore.AgentRecord{CursorAgentID: "agent-1"}, nil)
+	api.On("PublishWebSocketEvent", "review_loop_changed", mock.Anything, mock.Anything).Return().Once()
+	api.On("RemoveReaction", mock.MatchedBy(func(r *model.Reaction) bool {
+		return r.PostId == "trigger-1" && r.EmojiName == "x"
+	})).Return(nil).Once()
+	api.On("AddReaction", mock.MatchedBy(func(r *model.Reaction) bool {
+		return r.PostId == "trigger-1" && r.EmojiName == "eyes"
+	})).Return(nil, nil).Once()
+
+	rr := doRequest(p, http.MethodPost, "/api/v1/review-loops/loop-1/reset", nil, "user-1")
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	assert.Equal(t, kvstore.ReviewPhaseAwaitingReview, loop.Phase)
+	assert.Equal(t, 2, loop.Iteration)
+	require.Len(t, loop.History, 2)
+	assert.Equal(t, kvstore.ReviewPhaseAwaitingReview, loop.History[1].Phase)
+	store.AssertExpectations(t)
+	api.AssertExpectations(t)
+}
+
+func TestResetReviewLoop_WrongUser(t *testing.T) {
+	p, _, _, store := setupAPITestPlugin(t)
+
+	store.On("GetReviewLoop", "loop-1").Return(&kvstore.ReviewLoop{
+		ID:     "loop-1",
+		UserID: "other-user",
+		Phase:  kvstore.ReviewPhaseError,
+	}, nil)
+
+	rr := doRequest(p, http.MethodPost, "/api/v1/review-loops/loop-1/reset", nil, "user-1")
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	store.AssertNotCalled(t, "SaveReviewLoop", mock.Anything)
+}
+
+func TestResetReviewLoop_NotInErrorPhase(t *testing.T) {
+	p, _, _, store := setupAPITestPlugin(t)
+
+	store.On("GetReviewLoop", "loop-1").Return(&kvstore.ReviewLoop{
+		ID:     "loop-1",
+		UserID: "user-1",
+		Phase:  kvstore.ReviewPhaseCursorFixing,
+	}, nil)
+
+	rr := doRequest(p, http.MethodPost, "/api/v1/review-loops/loop-1/reset", nil, "user-1")
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	store.AssertNotCalled(t, "SaveReviewLoop", mock.Anything)
+}
+
+// --- POST /api/v1/review-loops/{id}/snooze ---
+
+func TestSnoozeReviewLoop_Success(t *testing.T) {
+	p, api, _, store := setupAPITestPlugin(t)
+
+	loop := &kvstore.ReviewLoop{
+		ID:               "loop-1",
+		UserID:           "user-1",
+		Phase:            kvstore.ReviewPhaseHumanReview,
+		StaleEscalatedAt: 1000,
+	}
+
+	store.On("GetReviewLoop", "loop-1").Return(loop, nil)
+	store.On("SaveReviewLoop", loop).Return(nil).Once()
+	api.On("PublishWebSocketEvent", "review_loop_changed", mock.Anything, mock.Anything).Return().Once()
+
+	before := time.Now()
+	rr := doRequest(p, http.MethodPost, "/api/v1/review-loops/loop-1/snooze", SnoozeReviewLoopRequestBody{Duration: "2d"}, "user-1")
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var resp SnoozeReviewLoopResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, loop.SnoozeUntil, resp.SnoozeUntil)
+	assert.GreaterOrEqual(t, loop.SnoozeUntil, before.Add(48*time.Hour).UnixMilli())
+	assert.Zero(t, loop.StaleEscalatedAt)
+	store.AssertExpectations(t)
+}
+
+func TestSnoozeReviewLoop_Off(t *testing.T) {
+	p, api, _, store := setupAPITestPlugin(t)
+
+	loop := &kvstore.ReviewLoop{
+		ID:          "loop-1",
+		UserID:      "user-1",
+		Phase:       kvstore.ReviewPhaseAwaitingReview,
+		SnoozeUntil: time.Now().Add(time.Hour).UnixMilli(),
+	}
+
+	store.On("GetReviewLoop", "loop-1").Return(loop, nil)
+	store.On("SaveReviewLoop", loop).Return(nil).Once()
+	api.On("PublishWebSocketEvent", "review_loop_changed", mock.Anything, mock.Anything).Return().Once()
+
+	rr := doRequest(p, http.MethodPost, "/api/v1/review-loops/loop-1/snooze", SnoozeReviewLoopRequestBody{Duration: "off"}, "user-1")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Zero(t, loop.SnoozeUntil)
+}
+
+func TestSnoozeReviewLoop_InvalidDuration(t *testing.T) {
+	p, _, _, store := setupAPITestPlugin(t)
+
+	rr := doRequest(p, http.MethodPost, "/api/v1/review-loops/loop-1/snooze", SnoozeReviewLoopRequestBody{Duration: "forever"}, "user-1")
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	store.AssertNotCalled(t, "GetReviewLoop", mock.Anything)
+}
+
+func TestSnoozeReviewLoop_WrongUser(t *testing.T) {
+	p, _, _, store := setupAPITestPlugin(t)
+
+	store.On("GetReviewLoop", "loop-1").Return(&kvstore.ReviewLoop{
+		ID:     "loop-1",
+		UserID: "other-user",
+		Phase:  kvstore.ReviewPhaseAwaitingReview,
+	}, nil)
+
+	rr := doRequest(p, http.MethodPost, "/api/v1/review-loops/loop-1/snooze", SnoozeReviewLoopRequestBody{Duration: "4h"}, "user-1")
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	store.AssertNotCalled(t, "SaveReviewLoop", mock.Anything)
+}
+
+func TestSnoozeReviewLoop_TerminalLoop(t *testing.T) {
+	p, _, _, store := setupAPITestPlugin(t)
+
+	store.On("GetReviewLoop", "loop-1").Return(&kvstore.ReviewLoop{
+		ID:     "loop-1",
+		UserID: "user-1",
+		Phase:  kvstore.ReviewPhaseComplete,
+	}, nil)
+
+	rr := doRequest(p, http.MethodPost, "/api/v1/review-loops/loop-1/snooze", SnoozeReviewLoopRequestBody{Duration: "4h"}, "user-1")
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	store.AssertNotCalled(t, "SaveReviewLoop", mock.Anything)
+}
+
+// --- POST /api/v1/review-loops/{id}/findings/{key}/snooze ---
+
+func TestSnoozeReviewFinding_Success(t *testing.T) {
+	p, api, _, store := setupAPITestPlugin(t)
+
+	loop := &kvstore.ReviewLoop{
+		ID:        "loop-1",
+		UserID:    "user-1",
+		Phase:     kvstore.ReviewPhaseAwaitingReview,
+		Iteration: 2,
+		Findings: []kvstore.ReviewFinding{
+			{Key: "finding-a", ShortID: "RF-aaaa", Status: findingStatusOpen},
+			{Key: "finding-b", ShortID: "RF-bbbb", Status: findingStatusOpen},
+		},
+	}
+
+	store.On("GetReviewLoop", "loop-1").Return(loop, nil)
+	store.On("SaveReviewLoop", loop).Return(nil).Once()
+	api.On("PublishWebSocketEvent", "review_loop_changed", mock.Anything, mock.Anything).Return().Once()
+
+	rr := doRequest(p, http.MethodPost, "/api/v1/review-loops/loop-1/findings/finding-b/snooze", nil, "user-1")
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var resp SnoozeReviewFindingResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, "finding-b", resp.Key)
+	assert.Equal(t, 2, resp.SnoozedUntilIteration)
+	assert.Zero(t, loop.Findings[0].SnoozedUntilIteration)
+	assert.Equal(t, 2, loop.Findings[1].SnoozedUntilIteration)
+	store.AssertExpectations(t)
+	api.AssertExpectations(t)
+}
+
+func TestSnoozeReviewFinding_NotOpen(t *testing.T) {
+	p, _, _, store := setupAPITestPlugin(t)
+
+	store.On("GetReviewLoop", "loop-1").Return(&kvstore.ReviewLoop{
+		ID:        "loop-1",
+		UserID:    "user-1",
+		Phase:     kvstore.ReviewPhaseAwaitingReview,
+		Iteration: 1,
+		Findings: []kvstore.ReviewFinding{
+			{Key: "finding-a", Status: findingStatusResolved},
+		},
+	}, nil)
+
+	rr := doRequest(p, http.MethodPost, "/api/v1/review-loops/loop-1/findings/finding-a/snooze", nil, "user-1")
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	store.AssertNotCalled(t, "SaveReviewLoop", mock.Anything)
+}
+
+func TestSnoozeReviewFinding_WrongUser(t *testing.T) {
+	p, _, _, store := setupAPITestPlugin(t)
+
+	store.On("GetReviewLoop", "loop-1").Return(&kvstore.ReviewLoop{
+		ID:       "loop-1",
+		UserID:   "other-user",
+		Phase:    kvstore.ReviewPhaseAwaitingReview,
+		Findings: []kvstore.ReviewFinding{{Key: "finding-a", Status: findingStatusOpen}},
+	}, nil)
+
+	rr := doRequest(p, http.MethodPost, "/api/v1/review-loops/loop-1/findings/finding-a/snooze", nil, "user-1")
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	store.AssertNotCalled(t, "SaveReviewLoop", mock.Anything)
+}
+
+// --- POST /api/v1/review-loops/{id}/idempotency-window ---
+
+func TestSetReviewLoopIdempotencyWindow_Success(t *testing.T) {
+	p, api, _, store := setupAPITestPlugin(t)
+
+	loop := &kvstore.ReviewLoop{
+		ID:     "loop-1",
+		UserID: "user-1",
+		Phase:  kvstore.ReviewPhaseAwaitingReview,
+	}
+
+	store.On("GetReviewLoop", "loop-1").Return(loop, nil)
+	store.On("SaveReviewLoop", loop).Return(nil).Once()
+	api.On("PublishWebSocketEvent", "review_loop_changed", mock.Anything, mock.Anything).Return().Once()
+
+	rr := doRequest(p, http.MethodPost, "/api/v1/review-loops/loop-1/idempotency-window", ReviewLoopIdempotencyWindowRequestBody{Window: "15m"}, "user-1")
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var resp ReviewLoopIdempotencyWindowResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, 900, resp.IdempotencyWindowSeconds)
+	assert.Equal(t, 900, loop.IdempotencyWindowSeconds)
+	store.AssertExpectations(t)
+}
+
+func TestSetReviewLoopIdempotencyWindow_Off(t *testing.T) {
+	p, api, _, store := setupAPITestPlugin(t)
+
+	loop := &kvstore.ReviewLoop{
+		ID:                       "loop-1",
+		UserID:                   "user-1",
+		Phase:                    kvstore.ReviewPhaseAwaitingReview,
+		IdempotencyWindowSeconds: 600,
+	}
+
+	store.On("GetReviewLoop", "loop-1").Return(loop, nil)
+	store.On("SaveReviewLoop", loop).Return(nil).Once()
+	api.On("PublishWebSocketEvent", "review_loop_changed", mock.Anything, mock.Anything).Return().Once()
+
+	rr := doRequest(p, http.MethodPost, "/api/v1/review-loops/loop-1/idempotency-window", ReviewLoopIdempotencyWindowRequestBody{Window: "off"}, "user-1")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Zero(t, loop.IdempotencyWindowSeconds)
+}
+
+func TestSetReviewLoopIdempotencyWindow_InvalidWindow(t *testing.T) {
+	p, _, _, store := setupAPITestPlugin(t)
+
+	rr := doRequest(p, http.MethodPost, "/api/v1/review-loops/loop-1/idempotency-window", ReviewLoopIdempotencyWindowRequestBody{Window: "soon"}, "user-1")
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	store.AssertNotCalled(t, "GetReviewLoop", mock.Anything)
+}
+
 // --- GET /api/v1/agents -- review loop field inclusion ---
 
 func TestGetAgents_IncludesReviewLoopFields(t *testing.T) {
@@ -1418,3 +1979,250 @@ func TestHandleGetAgent_FreshParam(t *testing.T) {
 	// ensureReviewLoop was called (via GetReviewLoopByPRURL).
 	store.AssertCalled(t, "GetReviewLoopByPRURL", "https://github.com/org/repo/pull/42")
 }
+
+// --- Webhook delivery debugging tests ---
+
+// asSystemAdmin makes userID resolve to a system admin; every other user stays
+// a regular user.
+func asSystemAdmin(api *plugintest.API, userID string) {
+	calls := api.ExpectedCalls[:0]
+	for _, call := range api.ExpectedCalls {
+		if call.Method != "GetUser" {
+			calls = append(calls, call)
+		}
+	}
+	api.ExpectedCalls = calls
+
+	api.On("GetUser", userID).Return(&model.User{
+		Id:    userID,
+		Roles: model.SystemAdminRoleId + " " + model.SystemUserRoleId,
+	}, nil).Maybe()
+	api.On("GetUser", mock.AnythingOfType("string")).Return(&model.User{
+		Id:       "user-1",
+		Username: "testuser",
+	}, nil).Maybe()
+}
+
+func TestGetRecentWebhookDeliveries(t *testing.T) {
+	p, api, _, store := setupAPITestPlugin(t)
+	asSystemAdmin(api, "admin-1")
+	p.configuration.RecordWebhookDeliveries = true
+
+	store.On("ListWebhookDeliveries").Return([]*kvstore.WebhookDelivery{
+		{DeliveryID: "d-2", Event: "pull_request", Action: "synchronize", Body: `{"action":"synchronize"}`, Status: 200, ReceivedAt: 2000},
+		{DeliveryID: "d-1", Event: "ping", Body: `{}`, Status: 200, Duplicate: true, ReceivedAt: 1000},
+	}, nil)
+
+	rr := doRequest(p, http.MethodGet, "/api/v1/webhooks/recent", nil, "admin-1")
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var resp WebhookDeliveryListResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	require.Len(t, resp.Deliveries, 2)
+	assert.Equal(t, "d-2", resp.Deliveries[0].DeliveryID)
+	assert.Equal(t, "synchronize", resp.Deliveries[0].Action)
+	assert.True(t, resp.Deliveries[1].Duplicate)
+}
+
+func TestWebhookDeliveryEndpoints_RequireSystemAdmin(t *testing.T) {
+	p, api, _, store := setupAPITestPlugin(t)
+	asSystemAdmin(api, "admin-1")
+	p.configuration.RecordWebhookDeliveries = true
+
+	rr := doRequest(p, http.MethodGet, "/api/v1/webhooks/recent", nil, "user-1")
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	rr = doRequest(p, http.MethodPost, "/api/v1/webhooks/d-1/replay", nil, "user-1")
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	store.AssertNotCalled(t, "ListWebhookDeliveries")
+	store.AssertNotCalled(t, "GetWebhookDelivery", mock.Anything)
+}
+
+func TestWebhookDeliveryEndpoints_DisabledByDefault(t *testing.T) {
+	p, api, _, store := setupAPITestPlugin(t)
+	asSystemAdmin(api, "admin-1")
+
+	rr := doRequest(p, http.MethodGet, "/api/v1/webhooks/recent", nil, "admin-1")
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = doRequest(p, http.MethodPost, "/api/v1/webhooks/d-1/replay", nil, "admin-1")
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	store.AssertNotCalled(t, "ListWebhookDeliveries")
+	store.AssertNotCalled(t, "GetWebhookDelivery", mock.Anything)
+}
+
+func TestReplayWebhookDelivery_BypassesDedupe(t *testing.T) {
+	p, api, _, store := setupAPITestPlugin(t)
+	asSystemAdmin(api, "admin-1")
+	p.configuration.RecordWebhookDeliveries = true
+
+	store.On("GetWebhookDelivery", "d-1").Return(&kvstore.WebhookDelivery{
+		DeliveryID: "d-1",
+		Event:      eventPing,
+		Body:       `{"zen":"Keep it logically awesome.","hook_id":42}`,
+		Status:     http.StatusOK,
+	}, nil)
+
+	rr := doRequest(p, http.MethodPost, "/api/v1/webhooks/d-1/replay", nil, "admin-1")
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var resp WebhookReplayResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, "d-1", resp.DeliveryID)
+	assert.Equal(t, http.StatusOK, resp.Status)
+	assert.Contains(t, resp.Response, `"status": "ok"`)
+
+	store.AssertNotCalled(t, "HasDeliveryBeenProcessed", mock.Anything)
+	store.AssertNotCalled(t, "MarkDeliveryProcessed", mock.Anything)
+}
+
+func TestReplayWebhookDelivery_ReportsHandlerFailure(t *testing.T) {
+	p, api, _, store := setupAPITestPlugin(t)
+	asSystemAdmin(api, "admin-1")
+	p.configuration.RecordWebhookDeliveries = true
+
+	store.On("GetWebhookDelivery", "d-bad").Return(&kvstore.WebhookDelivery{
+		DeliveryID: "d-bad",
+		Event:      eventPullRequest,
+		Body:       `not json`,
+	}, nil)
+
+	rr := doRequest(p, http.MethodPost, "/api/v1/webhooks/d-bad/replay", nil, "admin-1")
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var resp WebhookReplayResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, http.StatusBadRequest, resp.Status)
+}
+
+func TestReplayWebhookDelivery_RejectsTruncatedAndMissing(t *testing.T) {
+	p, api, _, store := setupAPITestPlugin(t)
+	asSystemAdmin(api, "admin-1")
+	p.configuration.RecordWebhookDeliveries = true
+
+	store.On("GetWebhookDelivery", "d-big").Return(&kvstore.WebhookDelivery{
+		DeliveryID: "d-big",
+		Event:      eventPullRequest,
+		Body:       `{"action":"opened"`,
+		Truncated:  true,
+	}, nil)
+	store.On("GetWebhookDelivery", "d-missing").Return(nil, nil)
+
+	rr := doRequest(p, http.MethodPost, "/api/v1/webhooks/d-big/replay", nil, "admin-1")
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+
+	rr = doRequest(p, http.MethodPost, "/api/v1/webhooks/d-missing/replay", nil, "admin-1")
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestGetReviewLoopSnapshot(t *testing.T) {
+	p, api, _, store := setupAPITestPlugin(t)
+	asSystemAdmin(api, "admin-1")
+
+	store.On("GetReviewLoop", "loop-1").Return(newMultiIterationReviewLoop(), nil)
+
+	rr := doRequest(p, http.MethodGet, "/api/v1/admin/review-loops/loop-1/iterations/2", nil, "admin-1")
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var resp ReviewLoopSnapshotResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, "loop-1", resp.ReviewLoopID)
+	assert.Equal(t, 2, resp.Iteration)
+	assert.Equal(t, 4, resp.CurrentIteration)
+	assert.Equal(t, kvstore.ReviewPhaseCursorFixing, resp.Phase)
+	assert.Len(t, resp.History, 5)
+
+	keys := []string{}
+	for _, finding := range resp.Findings {
+		keys = append(keys, finding.Key)
+	}
+	assert.Equal(t, []string{"repeated", "new-at-2", "resolved-after-1", "unseen-after-1"}, keys)
+	assert.Equal(t, snapshotFindingNew, resp.Findings[1].Classification)
+	assert.Equal(t, findingStatusOpen, resp.Findings[1].Status)
+
+	store.AssertNotCalled(t, "SaveReviewLoop", mock.Anything)
+}
+
+func TestGetReviewLoopSnapshot_InvalidIteration(t *testing.T) {
+	p, api, _, store := setupAPITestPlugin(t)
+	asSystemAdmin(api, "admin-1")
+
+	store.On("GetReviewLoop", "loop-1").Return(newMultiIterationReviewLoop(), nil)
+
+	for _, iteration := range []string{"0", "abc", "5"} {
+		rr := doRequest(p, http.MethodGet, "/api/v1/admin/review-loops/loop-1/iterations/"+iteration, nil, "admin-1")
+		assert.Equal(t, http.StatusBadRequest, rr.Code, "iteration %s", iteration)
+	}
+
+	store.On("GetReviewLoop", "missing").Return(nil, nil)
+	rr := doRequest(p, http.MethodGet, "/api/v1/admin/review-loops/missing/iterations/1", nil, "admin-1")
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestGetReviewLoopSnapshot_RequiresSystemAdmin(t *testing.T) {
+	p, api, _, store := setupAPITestPlugin(t)
+	asSystemAdmin(api, "admin-1")
+
+	rr := doRequest(p, http.MethodGet, "/api/v1/admin/review-loops/loop-1/iterations/2", nil, "user-1")
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	store.AssertNotCalled(t, "GetReviewLoop", mock.Anything)
+}
+
+// --- GET /api/v1/review-loops/{id}/export ---
+
+func TestExportReviewLoop(t *testing.T) {
+	p, _, _, store := setupAPITestPlugin(t)
+
+	loop := newMultiIterationReviewLoop()
+	loop.PRURL = "https://github.com/org/repo/pull/42"
+	loop.DroppedCandidates = []kvstore.DroppedCandidate{{Reason: "ignored_path", Route: "coderabbit", Path: "vendor/x.go", Iteration: 2, DroppedAt: 3}}
+	store.On("GetReviewLoop", "loop-1").Return(loop, nil)
+
+	rr := doRequest(p, http.MethodGet, "/api/v1/review-loops/loop-1/export", nil, "user-1")
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, `attachment; filename="review-loop-loop-1.json"`, rr.Header().Get("Content-Disposition"))
+
+	var resp ReviewLoopExportResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, reviewLoopExportSchemaVersion, resp.SchemaVersion)
+	assert.Equal(t, "loop-1", resp.ReviewLoop.ID)
+	assert.Equal(t, 4, resp.ReviewLoop.Iteration)
+	assert.Equal(t, reviewLoopEventResponses(loop.History), resp.History)
+	require.Len(t, resp.DroppedCandidates, 1)
+	assert.Equal(t, "vendor/x.go", resp.DroppedCandidates[0].Path)
+
+	timelines := map[string][]FindingStatusChangeResponse{}
+	for _, finding := range resp.Findings {
+		timelines[finding.Key] = finding.Timeline
+	}
+	require.Len(t, resp.Findings, len(loop.Findings))
+	assert.Equal(t, []FindingStatusChangeResponse{
+		{Iteration: 1, StartedAt: 1, Status: findingStatusOpen},
+	}, timelines["repeated"])
+	assert.Equal(t, []FindingStatusChangeResponse{
+		{Iteration: 2, StartedAt: 2, Status: findingStatusOpen},
+		{Iteration: 3, StartedAt: 5, Status: findingStatusResolved},
+	}, timelines["new-at-2"])
+	assert.Equal(t, []FindingStatusChangeResponse{
+		{Iteration: 3, StartedAt: 5, Status: findingStatusOpen},
+	}, timelines["new-at-3"])
+	assert.Equal(t, []FindingStatusChangeResponse{
+		{Iteration: 1, StartedAt: 1, Status: findingStatusOpen},
+		{Iteration: 2, StartedAt: 2, Status: findingStatusResolved},
+	}, timelines["resolved-after-1"])
+	assert.Equal(t, findingStatusResolved, resp.Findings[1].Status)
+
+	store.AssertNotCalled(t, "SaveReviewLoop", mock.Anything)
+}
+
+func TestExportReviewLoop_OwnerOnly(t *testing.T) {
+	p, _, _, store := setupAPITestPlugin(t)
+
+	store.On("GetReviewLoop", "loop-1").Return(newMultiIterationReviewLoop(), nil)
+
+	rr := doRequest(p, http.MethodGet, "/api/v1/review-loops/loop-1/export", nil, "user-2")
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
@@ -101,6 +101,22 @@ func BuildRunningAttachment(agentID, repo, branch, modelName string) *model.Slac
 	}
 }
 
+// BuildRunningProgressAttachment creates a running attachment that also shows
+// the agent's latest activity. newUpdates is the number of agent messages
+// since the card was last edited.
+func BuildRunningProgressAttachment(agentID, repo, branch, modelName, activity string, newUpdates int) *model.SlackAttachment {
+	att := BuildRunningAttachment(agentID, repo, branch, modelName)
+	title := "Latest activity"
+	if newUpdates > 1 {
+		title = fmt.Sprintf("Latest activity (%d new updates)", newUpdates)
+	}
+	att.Fields = append(att.Fields, &model.SlackAttachmentField{
+		Title: title,
+		Value: activity,
+	})
+	return att
+}
+
 // BuildFinishedAttachment creates an attachment for a successfully finished agent.
 // If prURL is non-empty, a "View PR" link is prepended to the links line.
 // If prURL is empty but targetBranch is non-empty, a note about the missing PR is shown
@@ -177,6 +193,25 @@ func BuildPlanningStatusAttachment(repo, branch, modelName string, iterationCoun
 	}
 }
 
+// BuildAcceptPartialPlanAction creates the button on the planning status post
+// that stops a running planner and accepts whatever plan it has produced.
+func BuildAcceptPartialPlanAction(workflowID, pluginURL string) *model.PostAction {
+	return &model.PostAction{
+		Id:    "acceptpartialplan",
+		Name:  "Accept Partial Plan",
+		Type:  model.PostActionTypeButton,
+		Style: "default",
+		Integration: &model.PostActionIntegration{
+			URL: pluginURL + "/api/v1/actions/hitl-response",
+			Context: map[string]any{
+				"workflow_id": workflowID,
+				"action":      "accept-partial",
+				"phase":       "planning",
+			},
+		},
+	}
+}
+
 // BuildPlanReviewAttachment creates an attachment for reviewing a plan.
 // The plan text is truncated if it exceeds 14000 characters (leaving room for
 // attachment metadata within Mattermost's 16KB post limit).
@@ -342,20 +377,35 @@ func reviewStatusLine(phase string, iteration int) string {
 		return "AI Review: Complete"
 	case "max_iterations":
 		return "AI Review: Max iterations reached -- needs manual review"
+	case "stalled":
+		return "AI Review: Stalled -- loop exceeded its maximum lifetime"
 	case "failed":
 		return "AI Review: Error -- check logs"
+	case "error":
+		return "AI Review: Error -- reset the loop to resume"
 	default:
 		return fmt.Sprintf("AI Review: %s", phase)
 	}
 }
 
+// reviewFindingsTallyLine returns the resolved-vs-outstanding finding counts
+// shown under the review status line, or "" before any finding is tracked.
+func reviewFindingsTallyLine(resolved, outstanding int) string {
+	if resolved+outstanding == 0 {
+		return ""
+	}
+	return fmt.Sprintf(":white_check_mark: %d/%d findings resolved | %d outstanding", resolved, resolved+outstanding, outstanding)
+}
+
 // BuildFinishedWithReviewStatusAttachment creates a finished attachment with an
 // appended review loop status line. This is used to update the existing bot reply
-// post in-place as the review loop progresses.
+// post in-place as the review loop progresses. When findings are tracked, a
+// resolved-vs-outstanding tally is shown under the status line.
 func BuildFinishedWithReviewStatusAttachment(
 	agentID, repo, branch, modelName, summary, prURL string,
 	reviewPhase string,
 	iteration int,
+	resolvedFindings, outstandingFindings int,
 ) *model.SlackAttachment {
 	links := agentLinks(agentID)
 	if prURL != "" {
@@ -371,15 +421,18 @@ func BuildFinishedWithReviewStatusAttachment(
 	textParts = append(textParts, links)
 	textParts = append(textParts, "---")
 	textParts = append(textParts, statusLine)
+	if tally := reviewFindingsTallyLine(resolvedFindings, outstandingFindings); tally != "" {
+		textParts = append(textParts, tally)
+	}
 	text := strings.Join(textParts, "\n\n")
 
 	color := ColorGreen // default: finished card stays green
 	switch reviewPhase {
 	case "requesting_review", "awaiting_review", "cursor_fixing":
 		color = ColorBlue
-	case "max_iterations":
+	case "max_iterations", "stalled":
 		color = ColorGrey
-	case "failed":
+	case "failed", "error":
 		color = ColorRed
 	}
 
@@ -411,6 +464,38 @@ func BuildReviewApprovedAttachment(prURL string, totalIterations int) *model.Sla
 	}
 }
 
+// BuildAutoMergedAttachment creates a completion attachment for when the
+// review loop merges an AI-approved PR with green CI. Posted as a new thread
+// message.
+func BuildAutoMergedAttachment(prURL, mergeMethod string) *model.SlackAttachment {
+	text := ""
+	if prURL != "" {
+		text = fmt.Sprintf("[View PR](%s)", prURL)
+	}
+
+	return &model.SlackAttachment{
+		Color: ColorGreen,
+		Title: fmt.Sprintf("PR auto-merged (%s) after AI approval and passing CI.", mergeMethod),
+		Text:  text,
+	}
+}
+
+// BuildAutoMergeFailedAttachment creates an attachment for when GitHub
+// rejects the automatic merge of an approved PR, e.g. because of a protected
+// branch rule. Posted as a new thread message.
+func BuildAutoMergeFailedAttachment(prURL, detail string) *model.SlackAttachment {
+	text := detail
+	if prURL != "" {
+		text = fmt.Sprintf("%s\n\n[View PR](%s)", detail, prURL)
+	}
+
+	return &model.SlackAttachment{
+		Color: ColorRed,
+		Title: "Could not auto-merge the approved PR. It is waiting for human review.",
+		Text:  text,
+	}
+}
+
 // BuildMaxIterationsAttachment creates a completion attachment for when
 // the review loop hits the max iteration limit. Posted as a new thread message.
 func BuildMaxIterationsAttachment(prURL string, maxIterations int) *model.SlackAttachment {
@@ -428,6 +513,83 @@ func BuildMaxIterationsAttachment(prURL string, maxIterations int) *model.SlackA
 	}
 }
 
+// BuildLifetimeExceededAttachment creates a completion attachment for when a
+// review loop is stalled for running longer than its maximum lifetime.
+// Posted as a new thread message.
+func BuildLifetimeExceededAttachment(prURL string, maxLifetimeHours int) *model.SlackAttachment {
+	title := fmt.Sprintf("AI review loop stopped after exceeding its maximum lifetime of %d hours.", maxLifetimeHours)
+
+	text := "Manual review is required."
+	if prURL != "" {
+		text = fmt.Sprintf("[View PR](%s) -- manual review is required.", prURL)
+	}
+
+	return &model.SlackAttachment{
+		Color: ColorGrey,
+		Title: title,
+		Text:  text,
+	}
+}
+
+// BuildIterationWarningAttachment creates a heads-up attachment for when the
+// review loop crosses the configured iteration warning threshold. Posted as a
+// new thread message while the loop keeps running.
+func BuildIterationWarningAttachment(prURL string, iteration, maxIterations int) *model.SlackAttachment {
+	title := fmt.Sprintf("AI review loop is at iteration %d of %d.", iteration, maxIterations)
+
+	text := "The loop may be struggling to converge; consider stepping in."
+	if prURL != "" {
+		text = fmt.Sprintf("[View PR](%s) -- the loop may be struggling to converge; consider stepping in.", prURL)
+	}
+
+	return &model.SlackAttachment{
+		Color: ColorYellow,
+		Title: title,
+		Text:  text,
+	}
+}
+
+// BuildStaleReviewLoopAttachment creates a reminder for a review loop that
+// has been waiting on reviewers longer than the configured threshold. Posted
+// as a new thread message while the loop keeps waiting.
+func BuildStaleReviewLoopAttachment(prURL string, waitingHours int, humanReview bool) *model.SlackAttachment {
+	waitingOn := "AI reviewers"
+	if humanReview {
+		waitingOn = "human reviewers"
+	}
+	title := fmt.Sprintf("PR has been waiting on %s for %d hours.", waitingOn, waitingHours)
+
+	text := "Snooze this reminder with `/cursor snooze <PR URL> <duration>` if the delay is expected."
+	if prURL != "" {
+		text = fmt.Sprintf("[View PR](%s) -- snooze this reminder with `/cursor snooze %s <duration>` if the delay is expected.", prURL, prURL)
+	}
+
+	return &model.SlackAttachment{
+		Color: ColorYellow,
+		Title: title,
+		Text:  text,
+	}
+}
+
+// BuildQuietHoursDigestAttachment creates a single summary of the review loop
+// notifications that were held during quiet hours. Posted as a new thread
+// message when the window ends.
+func BuildQuietHoursDigestAttachment(prURL string, entries []string) *model.SlackAttachment {
+	var sb strings.Builder
+	for _, entry := range entries {
+		sb.WriteString("- " + entry + "\n")
+	}
+	if prURL != "" {
+		sb.WriteString(fmt.Sprintf("\n[View PR](%s)", prURL))
+	}
+
+	return &model.SlackAttachment{
+		Color: ColorGrey,
+		Title: fmt.Sprintf("Quiet hours ended. %d review loop update(s) were held:", len(entries)),
+		Text:  strings.TrimRight(sb.String(), "\n"),
+	}
+}
+
 // BuildReviewCompleteAttachment creates a completion attachment for when
 // a human reviewer approves the PR. Posted as a new thread message.
 func BuildReviewCompleteAttachment(prURL, reviewer string) *model.SlackAttachment {
@@ -445,6 +607,33 @@ func BuildReviewCompleteAttachment(prURL, reviewer string) *model.SlackAttachmen
 	}
 }
 
+// maxWorkflowPromptFieldLen caps the original prompt shown on review loop
+// notifications for workflow-backed agents.
+const maxWorkflowPromptFieldLen = 300
+
+// AddWorkflowContextFields appends the HITL workflow's original prompt and a
+// link to its approved plan to a review loop notification. Empty values are
+// skipped.
+func AddWorkflowContextFields(attachment *model.SlackAttachment, originalPrompt, planURL string) *model.SlackAttachment {
+	if prompt := strings.TrimSpace(originalPrompt); prompt != "" {
+		if len(prompt) > maxWorkflowPromptFieldLen {
+			prompt = prompt[:maxWorkflowPromptFieldLen-3] + "..."
+		}
+		attachment.Fields = append(attachment.Fields, &model.SlackAttachmentField{
+			Title: "Original prompt",
+			Value: prompt,
+		})
+	}
+	if planURL != "" {
+		attachment.Fields = append(attachment.Fields, &model.SlackAttachmentField{
+			Title: "Approved plan",
+			Value: fmt.Sprintf("[View plan](%s)", planURL),
+			Short: model.SlackCompatibleBool(true),
+		})
+	}
+	return attachment
+}
+
 // BuildReviewFailedAttachment creates a completion attachment for when
 // the review loop fails due to an error. Posted as a new thread message.
 func BuildReviewFailedAttachment(detail string) *model.SlackAttachment {
@@ -1,6 +1,7 @@
 package attachments
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/mattermost/mattermost/server/public/model"
@@ -124,6 +125,21 @@ func TestBuildRunningAttachment(t *testing.T) {
 	assert.Contains(t, att.Text, "[Open in Web](https://cursor.com/agents/a1)")
 }
 
+func TestBuildRunningProgressAttachment(t *testing.T) {
+	att := BuildRunningProgressAttachment("a1", "org/repo", "main", "claude-sonnet", "Editing server/api.go", 1)
+
+	assert.Equal(t, ColorBlue, att.Color)
+	assert.Equal(t, "Agent is now running...", att.Title)
+	require.Len(t, att.Fields, 4)
+	assert.Equal(t, "Latest activity", att.Fields[3].Title)
+	assert.Equal(t, "Editing server/api.go", att.Fields[3].Value)
+	assert.Contains(t, att.Text, "[Open in Cursor](https://cursor.com/agents/a1)")
+
+	att = BuildRunningProgressAttachment("a1", "", "", "", "Running tests", 3)
+	require.Len(t, att.Fields, 1)
+	assert.Equal(t, "Latest activity (3 new updates)", att.Fields[0].Title)
+}
+
 func TestBuildFinishedAttachment(t *testing.T) {
 	t.Run("with PR URL", func(t *testing.T) {
 		prURL := "https://github.com/org/repo/pull/42"
@@ -297,6 +313,17 @@ func TestBuildPlanningStatusAttachment(t *testing.T) {
 	})
 }
 
+func TestBuildAcceptPartialPlanAction(t *testing.T) {
+	action := BuildAcceptPartialPlanAction("wf-1", "https://mm.example.com/plugins/cursor")
+
+	assert.Equal(t, "Accept Partial Plan", action.Name)
+	require.NotNil(t, action.Integration)
+	assert.Equal(t, "https://mm.example.com/plugins/cursor/api/v1/actions/hitl-response", action.Integration.URL)
+	assert.Equal(t, "wf-1", action.Integration.Context["workflow_id"])
+	assert.Equal(t, "accept-partial", action.Integration.Context["action"])
+	assert.Equal(t, "planning", action.Integration.Context["phase"])
+}
+
 func TestBuildPlanReviewAttachment(t *testing.T) {
 	pluginURL := "https://mattermost.example.com/plugins/com.mattermost.plugin-cursor"
 
@@ -501,6 +528,12 @@ func TestReviewStatusLine(t *testing.T) {
 			iteration: 1,
 			contains:  []string{"Error", "check logs"},
 		},
+		{
+			name:      "error",
+			phase:     "error",
+			iteration: 2,
+			contains:  []string{"Error", "reset the loop"},
+		},
 		{
 			name:      "unknown phase",
 			phase:     "something_new",
@@ -527,7 +560,7 @@ func TestBuildFinishedWithReviewStatusAttachment(t *testing.T) {
 		att := BuildFinishedWithReviewStatusAttachment(
 			"a1", "org/repo", "main", "claude-sonnet", "Fixed the bug",
 			"https://github.com/org/repo/pull/42",
-			"awaiting_review", 2,
+			"awaiting_review", 2, 0, 0,
 		)
 
 		assert.Equal(t, ColorBlue, att.Color)
@@ -545,7 +578,7 @@ func TestBuildFinishedWithReviewStatusAttachment(t *testing.T) {
 		att := BuildFinishedWithReviewStatusAttachment(
 			"a1", "org/repo", "main", "", "",
 			"https://github.com/org/repo/pull/42",
-			"approved", 3,
+			"approved", 3, 0, 0,
 		)
 
 		assert.Equal(t, ColorGreen, att.Color)
@@ -557,7 +590,7 @@ func TestBuildFinishedWithReviewStatusAttachment(t *testing.T) {
 		att := BuildFinishedWithReviewStatusAttachment(
 			"a1", "org/repo", "main", "", "",
 			"",
-			"max_iterations", 5,
+			"max_iterations", 5, 0, 0,
 		)
 
 		assert.Equal(t, ColorGrey, att.Color)
@@ -568,7 +601,7 @@ func TestBuildFinishedWithReviewStatusAttachment(t *testing.T) {
 		att := BuildFinishedWithReviewStatusAttachment(
 			"a1", "", "", "", "",
 			"",
-			"failed", 1,
+			"failed", 1, 0, 0,
 		)
 
 		assert.Equal(t, ColorRed, att.Color)
@@ -579,7 +612,7 @@ func TestBuildFinishedWithReviewStatusAttachment(t *testing.T) {
 		att := BuildFinishedWithReviewStatusAttachment(
 			"a1", "", "", "", "",
 			"",
-			"requesting_review", 1,
+			"requesting_review", 1, 0, 0,
 		)
 
 		assert.Equal(t, ColorBlue, att.Color)
@@ -590,7 +623,7 @@ func TestBuildFinishedWithReviewStatusAttachment(t *testing.T) {
 		att := BuildFinishedWithReviewStatusAttachment(
 			"a1", "", "", "", "",
 			"",
-			"cursor_fixing", 1,
+			"cursor_fixing", 1, 0, 0,
 		)
 
 		assert.Equal(t, ColorBlue, att.Color)
@@ -601,7 +634,7 @@ func TestBuildFinishedWithReviewStatusAttachment(t *testing.T) {
 		att := BuildFinishedWithReviewStatusAttachment(
 			"a1", "", "", "", "",
 			"https://github.com/org/repo/pull/1",
-			"human_review", 2,
+			"human_review", 2, 0, 0,
 		)
 
 		assert.Equal(t, ColorGreen, att.Color)
@@ -612,7 +645,7 @@ func TestBuildFinishedWithReviewStatusAttachment(t *testing.T) {
 		att := BuildFinishedWithReviewStatusAttachment(
 			"a1", "", "", "", "",
 			"",
-			"awaiting_review", 1,
+			"awaiting_review", 1, 0, 0,
 		)
 
 		// Should still have links and status line
@@ -626,7 +659,7 @@ func TestBuildFinishedWithReviewStatusAttachment(t *testing.T) {
 			"a1", "org/repo", "main", "auto",
 			"Implemented the feature",
 			"https://github.com/org/repo/pull/99",
-			"cursor_fixing", 3,
+			"cursor_fixing", 3, 0, 0,
 		)
 
 		// Verify ordering: summary, links, separator, status
@@ -635,6 +668,18 @@ func TestBuildFinishedWithReviewStatusAttachment(t *testing.T) {
 		assert.Contains(t, att.Text, "---")
 		assert.Contains(t, att.Text, "Cursor fixing feedback")
 		assert.Contains(t, att.Text, "iteration 3")
+		assert.NotContains(t, att.Text, "findings resolved")
+	})
+
+	t.Run("finding tally follows status line", func(t *testing.T) {
+		att := BuildFinishedWithReviewStatusAttachment(
+			"a1", "", "", "", "",
+			"",
+			"cursor_fixing", 2, 3, 2,
+		)
+
+		assert.Contains(t, att.Text, ":white_check_mark: 3/5 findings resolved | 2 outstanding")
+		assert.Less(t, strings.Index(att.Text, "Cursor fixing feedback"), strings.Index(att.Text, "findings resolved"))
 	})
 }
 
@@ -689,6 +734,126 @@ func TestBuildMaxIterationsAttachment(t *testing.T) {
 	})
 }
 
+func TestBuildLifetimeExceededAttachment(t *testing.T) {
+	t.Run("with PR URL", func(t *testing.T) {
+		att := BuildLifetimeExceededAttachment("https://github.com/org/repo/pull/42", 72)
+
+		assert.Equal(t, ColorGrey, att.Color)
+		assert.Contains(t, att.Title, "maximum lifetime of 72 hours")
+		assert.Contains(t, att.Text, "[View PR](https://github.com/org/repo/pull/42)")
+		assert.Empty(t, att.Actions)
+	})
+
+	t.Run("without PR URL", func(t *testing.T) {
+		att := BuildLifetimeExceededAttachment("", 24)
+
+		assert.Contains(t, att.Title, "24 hours")
+		assert.Contains(t, att.Text, "Manual review")
+		assert.NotContains(t, att.Text, "[View PR]")
+	})
+}
+
+func TestAddWorkflowContextFields(t *testing.T) {
+	t.Run("prompt and plan", func(t *testing.T) {
+		att := AddWorkflowContextFields(BuildReviewCompleteAttachment("https://github.com/org/repo/pull/42", "alice"),
+			"Add retry handling", "http://localhost/_redirect/pl/plan-1")
+
+		require.Len(t, att.Fields, 2)
+		assert.Equal(t, "Original prompt", att.Fields[0].Title)
+		assert.Equal(t, "Add retry handling", att.Fields[0].Value)
+		assert.Equal(t, "Approved plan", att.Fields[1].Title)
+		assert.Equal(t, "[View plan](http://localhost/_redirect/pl/plan-1)", att.Fields[1].Value)
+	})
+
+	t.Run("long prompt truncated", func(t *testing.T) {
+		att := AddWorkflowContextFields(&model.SlackAttachment{}, strings.Repeat("a", 400), "")
+
+		require.Len(t, att.Fields, 1)
+		value := att.Fields[0].Value.(string)
+		assert.Len(t, value, 300)
+		assert.True(t, strings.HasSuffix(value, "..."))
+	})
+
+	t.Run("nothing to add", func(t *testing.T) {
+		att := AddWorkflowContextFields(&model.SlackAttachment{}, "  ", "")
+		assert.Empty(t, att.Fields)
+	})
+}
+
+func TestBuildIterationWarningAttachment(t *testing.T) {
+	t.Run("with PR URL", func(t *testing.T) {
+		att := BuildIterationWarningAttachment("https://github.com/org/repo/pull/42", 4, 5)
+
+		assert.Equal(t, ColorYellow, att.Color)
+		assert.Contains(t, att.Title, "iteration 4 of 5")
+		assert.Contains(t, att.Text, "[View PR](https://github.com/org/repo/pull/42)")
+		assert.Empty(t, att.Actions)
+	})
+
+	t.Run("without PR URL", func(t *testing.T) {
+		att := BuildIterationWarningAttachment("", 2, 3)
+
+		assert.Contains(t, att.Title, "iteration 2 of 3")
+		assert.NotContains(t, att.Text, "[View PR]")
+	})
+}
+
+func TestBuildAutoMergedAttachment(t *testing.T) {
+	att := BuildAutoMergedAttachment("https://github.com/org/repo/pull/42", "squash")
+
+	assert.Equal(t, ColorGreen, att.Color)
+	assert.Equal(t, "PR auto-merged (squash) after AI approval and passing CI.", att.Title)
+	assert.Equal(t, "[View PR](https://github.com/org/repo/pull/42)", att.Text)
+}
+
+func TestBuildAutoMergeFailedAttachment(t *testing.T) {
+	t.Run("with PR URL", func(t *testing.T) {
+		att := BuildAutoMergeFailedAttachment("https://github.com/org/repo/pull/42", "At least 1 approving review is required.")
+
+		assert.Equal(t, ColorRed, att.Color)
+		assert.Contains(t, att.Title, "Could not auto-merge")
+		assert.Contains(t, att.Text, "At least 1 approving review is required.")
+		assert.Contains(t, att.Text, "[View PR](https://github.com/org/repo/pull/42)")
+	})
+
+	t.Run("without PR URL", func(t *testing.T) {
+		att := BuildAutoMergeFailedAttachment("", "Base branch was modified.")
+
+		assert.Equal(t, "Base branch was modified.", att.Text)
+	})
+}
+
+func TestBuildStaleReviewLoopAttachment(t *testing.T) {
+	t.Run("AI reviewers with PR URL", func(t *testing.T) {
+		att := BuildStaleReviewLoopAttachment("https://github.com/org/repo/pull/42", 26, false)
+
+		assert.Equal(t, ColorYellow, att.Color)
+		assert.Equal(t, "PR has been waiting on AI reviewers for 26 hours.", att.Title)
+		assert.Contains(t, att.Text, "[View PR](https://github.com/org/repo/pull/42)")
+		assert.Contains(t, att.Text, "/cursor snooze https://github.com/org/repo/pull/42 <duration>")
+	})
+
+	t.Run("human reviewers without PR URL", func(t *testing.T) {
+		att := BuildStaleReviewLoopAttachment("", 48, true)
+
+		assert.Contains(t, att.Title, "human reviewers for 48 hours")
+		assert.NotContains(t, att.Text, "[View PR]")
+	})
+}
+
+func TestBuildQuietHoursDigestAttachment(t *testing.T) {
+	att := BuildQuietHoursDigestAttachment("https://github.com/org/repo/pull/42", []string{
+		"AI review loop is at iteration 4 of 5.",
+		"AI review loop reached max iterations.",
+	})
+
+	assert.Equal(t, ColorGrey, att.Color)
+	assert.Contains(t, att.Title, "2 review loop update(s)")
+	assert.Contains(t, att.Text, "- AI review loop is at iteration 4 of 5.\n- AI review loop reached max iterations.")
+	assert.Contains(t, att.Text, "[View PR](https://github.com/org/repo/pull/42)")
+	assert.Empty(t, att.Actions)
+}
+
 func TestBuildReviewFailedAttachment(t *testing.T) {
 	t.Run("with detail", func(t *testing.T) {
 		att := BuildReviewFailedAttachment("GitHub API rate limited")
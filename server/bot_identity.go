@@ -0,0 +1,147 @@
+package main
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/pluginapi"
+)
+
+// ensureBotIdentities creates or updates a bot account for each configured
+// additional identity. Identities that fail to be created are logged and left
+// out, so mentions of them are ignored until the next configuration change.
+func (p *Plugin) ensureBotIdentities() {
+	specs := p.getConfiguration().ParseBotIdentities()
+	if len(specs) == 0 {
+		p.setBotIdentities(nil)
+		return
+	}
+
+	identities := make(map[string]string, len(specs))
+	for _, spec := range specs {
+		userID, err := p.client.Bot.EnsureBot(&model.Bot{
+			Username:    spec.Username,
+			DisplayName: spec.DisplayName,
+			Description: botDescription,
+		}, pluginapi.ProfileImagePath("assets/cursor-icon.png"))
+		if err != nil {
+			p.API.LogError("Failed to ensure bot identity", "username", spec.Username, "error", err.Error())
+			continue
+		}
+		identities[spec.Username] = userID
+	}
+	p.setBotIdentities(identities)
+}
+
+// botIdentityUsernames returns the usernames of the additional bot
+// identities, sorted.
+func (p *Plugin) botIdentityUsernames() []string {
+	identities := p.getBotIdentities()
+	usernames := make([]string, 0, len(identities))
+	for username := range identities {
+		usernames = append(usernames, username)
+	}
+	sort.Strings(usernames)
+	return usernames
+}
+
+// isBotUser reports whether userID is the default bot or any additional
+// bot identity.
+func (p *Plugin) isBotUser(userID string) bool {
+	if userID == p.getBotUserID() {
+		return true
+	}
+	for _, id := range p.getBotIdentities() {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// botMentions returns the "@handle" mention of every bot identity, longest
+// first so "@cursor-frontend" is matched before its "@cursor" prefix.
+func (p *Plugin) botMentions() []string {
+	mentions := []string{"@" + p.getBotUsername()}
+	for username := range p.getBotIdentities() {
+		mentions = append(mentions, "@"+username)
+	}
+	sort.SliceStable(mentions, func(i, j int) bool {
+		return len(mentions[i]) > len(mentions[j])
+	})
+	return mentions
+}
+
+// findBotMention returns the bot mention contained in message, or "" if no
+// configured handle is mentioned.
+func (p *Plugin) findBotMention(message string) string {
+	for _, mention := range p.botMentions() {
+		if containsMention(message, mention) {
+			return mention
+		}
+	}
+	return ""
+}
+
+// stripBotMention removes the first bot mention from text, if any.
+func (p *Plugin) stripBotMention(text string) string {
+	mention := p.findBotMention(text)
+	if mention == "" {
+		return text
+	}
+	idx := strings.Index(strings.ToLower(text), strings.ToLower(mention))
+	return text[:idx] + text[idx+len(mention):]
+}
+
+// botUserIDForChannel returns the user ID of the bot identity selected for
+// the channel, falling back to the default bot. Channel settings are only
+// read when additional identities are configured.
+func (p *Plugin) botUserIDForChannel(channelID string) string {
+	identities := p.getBotIdentities()
+	if len(identities) == 0 || channelID == "" {
+		return p.getBotUserID()
+	}
+
+	settings, err := p.kvstore.GetChannelSettings(channelID)
+	if err != nil || settings == nil || settings.BotUsername == "" {
+		return p.getBotUserID()
+	}
+	if userID, ok := identities[settings.BotUsername]; ok {
+		return userID
+	}
+	return p.getBotUserID()
+}
+
+// decorateBotPost prepends the configured bot post prefix to post's message so
+// users can tell which plugin instance posted it. Attachment-only posts get
+// the prefix as their message. Messages that already carry the prefix are
+// left alone, so posts that are edited in place are not prefixed twice.
+func (p *Plugin) decorateBotPost(post *model.Post) *model.Post {
+	prefix := p.getConfiguration().GetBotPostPrefix()
+	if prefix == "" || post == nil {
+		return post
+	}
+	switch {
+	case post.Message == "":
+		post.Message = prefix
+	case strings.HasPrefix(post.Message, prefix):
+	case startsWithMarkdownBlock(post.Message):
+		// Keep headings, quotes, lists, tables and code blocks intact.
+		post.Message = prefix + "\n" + post.Message
+	default:
+		post.Message = prefix + " " + post.Message
+	}
+	return post
+}
+
+// startsWithMarkdownBlock reports whether message opens with a block-level
+// Markdown element that only renders at the start of a line.
+func startsWithMarkdownBlock(message string) bool {
+	for _, marker := range []string{"#", ">", "- ", "* ", "|", "```"} {
+		if strings.HasPrefix(message, marker) {
+			return true
+		}
+	}
+	return false
+}
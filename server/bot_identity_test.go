@@ -0,0 +1,217 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/cursor"
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+func TestParseBotIdentities(t *testing.T) {
+	cfg := &configuration{
+		AdditionalBotIdentities: " cursor-frontend:Cursor Frontend, @Cursor-Backend ,cursor,bad name,cursor-frontend:Dup,",
+	}
+
+	assert.Equal(t, []botIdentitySpec{
+		{Username: "cursor-frontend", DisplayName: "Cursor Frontend"},
+		{Username: "cursor-backend", DisplayName: "cursor-backend"},
+	}, cfg.ParseBotIdentities())
+	assert.Nil(t, (&configuration{}).ParseBotIdentities())
+}
+
+func TestFindBotMention_PrefersLongestHandle(t *testing.T) {
+	p, _, _, _ := setupTestPlugin(t)
+	p.botIdentities = map[string]string{"cursor-frontend": "frontend-bot-id"}
+
+	assert.Equal(t, "@cursor-frontend", p.findBotMention("@Cursor-Frontend fix the nav bar"))
+	assert.Equal(t, "@cursor", p.findBotMention("hey @cursor fix the nav bar"))
+	assert.Empty(t, p.findBotMention("no mention here"))
+	assert.Equal(t, " fix it", p.stripBotMention("@cursor-frontend fix it"))
+}
+
+func TestBotUserIDForChannel(t *testing.T) {
+	p, _, _, store := setupTestPlugin(t)
+
+	// Without additional identities the channel settings are never read.
+	assert.Equal(t, "bot-user-id", p.botUserIDForChannel("ch-1"))
+	store.AssertNotCalled(t, "GetChannelSettings", mock.Anything)
+
+	p.botIdentities = map[string]string{"cursor-frontend": "frontend-bot-id"}
+	store.On("GetChannelSettings", "ch-frontend").Return(&kvstore.ChannelSettings{BotUsername: "cursor-frontend"}, nil)
+	store.On("GetChannelSettings", "ch-removed").Return(&kvstore.ChannelSettings{BotUsername: "cursor-old"}, nil)
+	store.On("GetChannelSettings", "ch-plain").Return(nil, nil)
+
+	assert.Equal(t, "frontend-bot-id", p.botUserIDForChannel("ch-frontend"))
+	assert.Equal(t, "bot-user-id", p.botUserIDForChannel("ch-removed"))
+	assert.Equal(t, "bot-user-id", p.botUserIDForChannel("ch-plain"))
+}
+
+func TestMessageHasBeenPosted_IgnoresAdditionalBotIdentityPosts(t *testing.T) {
+	p, _, cursorClient, store := setupTestPlugin(t)
+	p.botIdentities = map[string]string{"cursor-frontend": "frontend-bot-id"}
+
+	p.MessageHasBeenPosted(nil, &model.Post{
+		UserId:    "frontend-bot-id",
+		ChannelId: "ch-1",
+		Message:   "@cursor fix something",
+	})
+
+	cursorClient.AssertNotCalled(t, "LaunchAgent")
+	store.AssertNotCalled(t, "SaveAgent")
+}
+
+func TestMessageHasBeenPosted_ChannelBotIdentity(t *testing.T) {
+	p, api, cursorClient, store := setupTestPlugin(t)
+	p.botIdentities = map[string]string{"cursor-frontend": "frontend-bot-id"}
+
+	post := &model.Post{
+		Id:        "post-1",
+		UserId:    "user-1",
+		ChannelId: "ch-1",
+		Message:   "@cursor-frontend fix the login bug",
+	}
+
+	store.On("GetUserSettings", "user-1").Return(nil, nil)
+	store.On("GetChannelSettings", "ch-1").Return(&kvstore.ChannelSettings{BotUsername: "cursor-frontend"}, nil)
+
+	// Reactions are added and removed as the channel's bot.
+	api.On("AddReaction", mock.MatchedBy(func(r *model.Reaction) bool {
+		return r.PostId == "post-1" && r.UserId == "frontend-bot-id"
+	})).Return(nil, nil)
+	api.On("RemoveReaction", mock.MatchedBy(func(r *model.Reaction) bool {
+		return r.PostId == "post-1" && r.UserId == "frontend-bot-id" && r.EmojiName == "eyes"
+	})).Return(nil)
+
+	cursorClient.On("LaunchAgent", mock.Anything, mock.MatchedBy(func(req cursor.LaunchAgentRequest) bool {
+		return strings.Contains(req.Prompt.Text, "fix the login bug") &&
+			!strings.Contains(req.Prompt.Text, "-frontend")
+	})).Return(&cursor.Agent{
+		ID:     "agent-123",
+		Status: cursor.AgentStatusCreating,
+	}, nil)
+
+	api.On("CreatePost", mock.MatchedBy(func(p *model.Post) bool {
+		return p.RootId == "post-1" && p.UserId == "frontend-bot-id"
+	})).Return(&model.Post{Id: "reply-1"}, nil)
+
+	store.On("SaveAgent", mock.Anything).Return(nil)
+	store.On("SetThreadAgent", "post-1", "agent-123").Return(nil)
+	api.On("PublishWebSocketEvent", "agent_created", mock.Anything, mock.Anything).Return()
+
+	p.MessageHasBeenPosted(nil, post)
+
+	cursorClient.AssertExpectations(t)
+	api.AssertExpectations(t)
+	api.AssertNotCalled(t, "AddReaction", mock.MatchedBy(func(r *model.Reaction) bool {
+		return r.UserId == "bot-user-id"
+	}))
+}
+
+func TestSettingsDialog_ChannelBotIdentity(t *testing.T) {
+	p, api, store := setupDialogTestPlugin(t)
+	p.botIdentities = map[string]string{"cursor-frontend": "frontend-bot-id"}
+
+	submission := model.SubmitDialogRequest{
+		UserId: "user-1",
+		State:  "ch-1|user-1",
+		Submission: map[string]any{
+			"channel_bot_username": "cursor-frontend",
+		},
+	}
+
+	store.On("SaveChannelSettings", "ch-1", &kvstore.ChannelSettings{BotUsername: "cursor-frontend"}).Return(nil)
+	store.On("GetUserSettings", "user-1").Return(nil, nil)
+	store.On("SaveUserSettings", "user-1", &kvstore.UserSettings{}).Return(nil)
+	store.On("GetChannelSettings", "ch-1").Return(&kvstore.ChannelSettings{BotUsername: "cursor-frontend"}, nil)
+	api.On("SendEphemeralPost", "user-1", mock.MatchedBy(func(p *model.Post) bool {
+		return p.ChannelId == "ch-1" && p.UserId == "frontend-bot-id"
+	})).Return(&model.Post{})
+
+	body, _ := json.Marshal(submission)
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/dialog/settings", bytes.NewReader(body))
+	r.Header.Set("Mattermost-User-ID", "user-1")
+
+	p.ServeHTTP(nil, w, r)
+
+	assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+	store.AssertExpectations(t)
+	api.AssertExpectations(t)
+}
+
+func TestSettingsDialog_UnknownChannelBotIdentity(t *testing.T) {
+	p, _, store := setupDialogTestPlugin(t)
+	store.On("GetChannelSettings", "ch-1").Return(nil, nil)
+	p.botIdentities = map[string]string{"cursor-frontend": "frontend-bot-id"}
+
+	submission := model.SubmitDialogRequest{
+		UserId: "user-1",
+		State:  "ch-1|user-1",
+		Submission: map[string]any{
+			"channel_bot_username": "cursor-unknown",
+		},
+	}
+
+	body, _ := json.Marshal(submission)
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/dialog/settings", bytes.NewReader(body))
+	r.Header.Set("Mattermost-User-ID", "user-1")
+
+	p.ServeHTTP(nil, w, r)
+
+	var resp model.SubmitDialogResponse
+	require.NoError(t, json.NewDecoder(w.Result().Body).Decode(&resp))
+	assert.Contains(t, resp.Errors, "channel_bot_username")
+	store.AssertNotCalled(t, "SaveChannelSettings", mock.Anything, mock.Anything)
+}
+
+func TestDecorateBotPost(t *testing.T) {
+	p, _, _, _ := setupTestPlugin(t)
+
+	// No prefix configured: posts are untouched.
+	post := &model.Post{Message: "Agent finished"}
+	assert.Equal(t, "Agent finished", p.decorateBotPost(post).Message)
+
+	p.configuration.BotPostPrefix = " [staging] "
+	tests := []struct {
+		name     string
+		message  string
+		expected string
+	}{
+		{name: "plain message", message: "Agent finished", expected: "[staging] Agent finished"},
+		{name: "attachment only", message: "", expected: "[staging]"},
+		{name: "markdown heading", message: "#### Plan ready", expected: "[staging]\n#### Plan ready"},
+		{name: "quote", message: "> original request", expected: "[staging]\n> original request"},
+		{name: "already prefixed", message: "[staging] Agent finished", expected: "[staging] Agent finished"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			post := p.decorateBotPost(&model.Post{Message: tt.message})
+			assert.Equal(t, tt.expected, post.Message)
+		})
+	}
+	assert.Nil(t, p.decorateBotPost(nil))
+}
+
+func TestPostBotReply_AddsConfiguredPrefix(t *testing.T) {
+	p, api, _, _ := setupTestPlugin(t)
+	p.configuration.BotPostPrefix = ":test_tube: staging"
+
+	api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		return post.RootId == "post-1" && post.Message == ":test_tube: staging Agent launched."
+	})).Return(&model.Post{Id: "reply-1"}, nil)
+
+	p.postBotReply(&model.Post{Id: "post-1", ChannelId: "ch-1"}, "Agent launched.")
+
+	api.AssertExpectations(t)
+}
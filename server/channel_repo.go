@@ -0,0 +1,61 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/ghclient"
+)
+
+// channelRepositoryProp is the channel property other integrations can set to
+// link a channel to a GitHub repository, in owner/repo format.
+const channelRepositoryProp = "github_repository"
+
+// githubRepoLinkRe matches a github.com/owner/repo link in free text.
+var githubRepoLinkRe = regexp.MustCompile(`(?i)github\.com/([a-zA-Z0-9._-]+/[a-zA-Z0-9._-]+)`)
+
+// channelLinkedRepository returns the GitHub repository a channel is linked
+// to, or "" if none can be found. The github_repository channel property wins;
+// otherwise the first repository link in the channel header, then purpose, is
+// used.
+func (p *Plugin) channelLinkedRepository(channelID string) string {
+	channel, appErr := p.API.GetChannel(channelID)
+	if appErr != nil || channel == nil {
+		return ""
+	}
+
+	if prop, ok := channel.Props[channelRepositoryProp].(string); ok {
+		if repoRef, err := ghclient.ParseRepoRef(prop); err == nil {
+			return repoRef.String()
+		}
+	}
+	for _, text := range []string{channel.Header, channel.Purpose} {
+		if repo := repositoryFromGitHubLink(text); repo != "" {
+			return repo
+		}
+	}
+	return ""
+}
+
+// channelLinkedRepositoryFallback returns the channel's linked repository
+// when UseChannelLinkedRepo is enabled, or "" otherwise.
+func (p *Plugin) channelLinkedRepositoryFallback(channelID string) string {
+	if !p.getConfiguration().UseChannelLinkedRepo {
+		return ""
+	}
+	return p.channelLinkedRepository(channelID)
+}
+
+// repositoryFromGitHubLink returns owner/repo from the first github.com
+// repository link in text, without a trailing ".git" or sentence period.
+func repositoryFromGitHubLink(text string) string {
+	match := githubRepoLinkRe.FindStringSubmatch(text)
+	if match == nil {
+		return ""
+	}
+	repoRef, err := ghclient.ParseRepoRef(strings.TrimRight(match[1], "."))
+	if err != nil {
+		return ""
+	}
+	return repoRef.String()
+}
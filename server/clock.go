@@ -0,0 +1,27 @@
+package main
+
+import "time"
+
+// Clock is the source of the current time for the plugin's time-based logic,
+// such as cooldowns, quiet hours, stale reaping and lifetime caps. Tests swap
+// in a fake clock to control time deterministically.
+type Clock interface {
+	Now() time.Time
+}
+
+// realClock reads the system clock.
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// now returns the current time from the plugin's clock, falling back to the
+// system clock when none is set.
+func (p *Plugin) now() time.Time {
+	clock := p.clock
+	if clock == nil {
+		clock = realClock{}
+	}
+	return clock.Now()
+}
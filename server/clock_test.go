@@ -0,0 +1,45 @@
+package main
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeClock is a Clock whose time only moves when a test advances it.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock(now time.Time) *fakeClock {
+	return &fakeClock{now: now}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Advance moves the clock forward by d.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestPluginNow(t *testing.T) {
+	p := &Plugin{}
+	before := time.Now()
+	assert.False(t, p.now().Before(before), "a plugin without a clock uses the system clock")
+
+	clock := newFakeClock(time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC))
+	p.clock = clock
+	assert.Equal(t, time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC), p.now())
+
+	clock.Advance(90 * time.Second)
+	assert.Equal(t, time.Date(2026, 3, 10, 12, 1, 30, 0, time.UTC), p.now())
+}
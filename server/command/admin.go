@@ -0,0 +1,56 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+const adminUsage = "Usage: `/cursor admin pause-loops`, `/cursor admin resume-loops` or `/cursor admin gc [--prune]`"
+
+func isAdminAction(action string) bool {
+	switch strings.ToLower(action) {
+	case adminActionPauseLoops, adminActionResumeLoops, adminActionGC:
+		return true
+	default:
+		return false
+	}
+}
+
+// executeAdmin handles system-admin-only operations.
+func (h *Handler) executeAdmin(args *model.CommandArgs, params []string) (*model.CommandResponse, error) {
+	if !h.deps.Client.User.HasPermissionTo(args.UserId, model.PermissionManageSystem) {
+		return ephemeralResponse("Only system administrators can use `/cursor admin`."), nil
+	}
+	if strings.EqualFold(params[0], adminActionGC) {
+		return h.executeGC(args, params[1:]), nil
+	}
+	if len(params) != 1 {
+		return ephemeralResponse(adminUsage), nil
+	}
+
+	switch strings.ToLower(params[0]) {
+	case adminActionPauseLoops:
+		return h.setReviewLoopsPaused(args, true), nil
+	case adminActionResumeLoops:
+		return h.setReviewLoopsPaused(args, false), nil
+	default:
+		return ephemeralResponse(adminUsage), nil
+	}
+}
+
+// setReviewLoopsPaused toggles the global review loop pause.
+func (h *Handler) setReviewLoopsPaused(args *model.CommandArgs, paused bool) *model.CommandResponse {
+	if h.deps.SetReviewLoopsPausedFn == nil {
+		return ephemeralResponse("Pausing review loops is not available.")
+	}
+	if err := h.deps.SetReviewLoopsPausedFn(paused); err != nil {
+		h.deps.Client.Log.Error("Failed to update the global review loop pause", "paused", paused, "user_id", args.UserId, "error", err.Error())
+		return ephemeralResponse("Failed to update the review loop pause. Please try again.")
+	}
+
+	if paused {
+		return ephemeralResponse("All review loops are paused. Reviews that arrive are recorded and re-evaluated after `/cursor admin resume-loops`.")
+	}
+	return ephemeralResponse("Review loops resumed. Reviews held during the pause will be re-evaluated on the next poll.")
+}
@@ -0,0 +1,120 @@
+package command
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/parser"
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+var aliasNameRe = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{0,31}$`)
+
+const aliasUsage = "Usage: `/cursor alias set <name> <options>`, `/cursor alias list`, or `/cursor alias remove <name>`"
+
+// executeAlias dispatches /cursor alias subcommands.
+func (h *Handler) executeAlias(args *model.CommandArgs, params []string) (*model.CommandResponse, error) {
+	if len(params) == 0 {
+		return ephemeralResponse(aliasUsage), nil
+	}
+
+	switch strings.ToLower(params[0]) {
+	case aliasActionSet:
+		return h.executeAliasSet(args, params[1:])
+	case aliasActionList:
+		return h.executeAliasList(args)
+	case aliasActionRemove:
+		return h.executeAliasRemove(args, params[1:])
+	default:
+		return ephemeralResponse(aliasUsage), nil
+	}
+}
+
+func (h *Handler) executeAliasSet(args *model.CommandArgs, params []string) (*model.CommandResponse, error) {
+	if len(params) < 2 {
+		return ephemeralResponse("Usage: `/cursor alias set <name> <options>` (e.g. `/cursor alias set frontend repo=org/web branch=develop model=claude-sonnet`)"), nil
+	}
+
+	name := strings.ToLower(strings.TrimPrefix(params[0], "@"))
+	if !aliasNameRe.MatchString(name) {
+		return ephemeralResponse("Alias names must be 1-32 characters of letters, numbers, `.`, `_`, or `-`, starting with a letter or number."), nil
+	}
+
+	options := strings.Join(params[1:], " ")
+	parsed, rest := parser.ParseOptions(options)
+	if rest != "" {
+		return ephemeralResponse(fmt.Sprintf("Unrecognized alias options: `%s`. Use `repo=`, `branch=`, `ref=`, `base=`, `model=`, `autopr=`, `review=`, `plan=`, or flags like `--no-plan`.", rest)), nil
+	}
+	if !parsed.HasOptions() {
+		return ephemeralResponse("An alias needs at least one option, e.g. `repo=org/web`."), nil
+	}
+
+	settings, err := h.deps.Store.GetUserSettings(args.UserId)
+	if err != nil {
+		return ephemeralResponse("Failed to load your settings. Please try again."), nil
+	}
+	if settings == nil {
+		settings = &kvstore.UserSettings{}
+	}
+	if _, exists := settings.Aliases[name]; !exists && len(settings.Aliases) >= maxAliasesPerUser {
+		return ephemeralResponse(fmt.Sprintf("You can have at most %d aliases. Remove one with `/cursor alias remove <name>` first.", maxAliasesPerUser)), nil
+	}
+	if settings.Aliases == nil {
+		settings.Aliases = make(map[string]string)
+	}
+	settings.Aliases[name] = options
+
+	if err := h.deps.Store.SaveUserSettings(args.UserId, settings); err != nil {
+		h.deps.Client.Log.Error("Failed to save alias", "user_id", args.UserId, "error", err.Error())
+		return ephemeralResponse("Failed to save your alias. Please try again."), nil
+	}
+
+	return ephemeralResponse(fmt.Sprintf("Saved alias `@%s` → `%s`. Use it with `@cursor @%s <prompt>`.", name, options, name)), nil
+}
+
+func (h *Handler) executeAliasList(args *model.CommandArgs) (*model.CommandResponse, error) {
+	settings, _ := h.deps.Store.GetUserSettings(args.UserId)
+	aliases := safeUserAliases(settings)
+	if len(aliases) == 0 {
+		return ephemeralResponse("You have no aliases. Create one with `/cursor alias set <name> <options>`."), nil
+	}
+
+	names := make([]string, 0, len(aliases))
+	for name := range aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	sb.WriteString("#### Your Aliases\n\n| Alias | Options |\n|:------|:--------|\n")
+	for _, name := range names {
+		fmt.Fprintf(&sb, "| `@%s` | `%s` |\n", name, aliases[name])
+	}
+	return ephemeralResponse(sb.String()), nil
+}
+
+func (h *Handler) executeAliasRemove(args *model.CommandArgs, params []string) (*model.CommandResponse, error) {
+	if len(params) != 1 {
+		return ephemeralResponse("Usage: `/cursor alias remove <name>`"), nil
+	}
+	name := strings.ToLower(strings.TrimPrefix(params[0], "@"))
+
+	settings, err := h.deps.Store.GetUserSettings(args.UserId)
+	if err != nil {
+		return ephemeralResponse("Failed to load your settings. Please try again."), nil
+	}
+	if _, exists := safeUserAliases(settings)[name]; !exists {
+		return ephemeralResponse(fmt.Sprintf("You have no alias named `@%s`.", name)), nil
+	}
+	delete(settings.Aliases, name)
+
+	if err := h.deps.Store.SaveUserSettings(args.UserId, settings); err != nil {
+		h.deps.Client.Log.Error("Failed to remove alias", "user_id", args.UserId, "error", err.Error())
+		return ephemeralResponse("Failed to remove your alias. Please try again."), nil
+	}
+	return ephemeralResponse(fmt.Sprintf("Removed alias `@%s`.", name)), nil
+}
@@ -15,6 +15,7 @@ import (
 
 	"github.com/mattermost/mattermost-plugin-cursor/server/attachments"
 	"github.com/mattermost/mattermost-plugin-cursor/server/cursor"
+	"github.com/mattermost/mattermost-plugin-cursor/server/ghclient"
 	"github.com/mattermost/mattermost-plugin-cursor/server/parser"
 	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
 )
@@ -28,10 +29,37 @@ const (
 	subcommandSettings = "settings"
 	subcommandModels   = "models"
 	subcommandHelp     = "help"
+	subcommandAlias    = "alias"
+	subcommandSnooze   = "snooze"
+	subcommandAdmin    = "admin"
+	subcommandPlan     = "plan"
+	subcommandTransfer = "transfer"
+	subcommandReview   = "review"
+	subcommandWhoami   = "whoami"
+	subcommandRecent   = "recent"
+	subcommandLink     = "link"
+
+	settingsActionReset = "reset"
+
+	aliasActionSet    = "set"
+	aliasActionList   = "list"
+	aliasActionRemove = "remove"
+
+	reviewActionDispatch = "dispatch"
+
+	adminActionPauseLoops  = "pause-loops"
+	adminActionResumeLoops = "resume-loops"
+	adminActionGC          = "gc"
+
+	maxAliasesPerUser = 25
 
 	errNoCursorClient = "Cursor API key is not configured. Please ask your system administrator to configure it in System Console > Plugins > Cursor Background Agents."
 )
 
+// LaunchNotPermittedMessage is shown to a user who is not one of the
+// channel's allowed launchers.
+const LaunchNotPermittedMessage = "You are not permitted to launch Cursor agents in this channel. Ask a channel admin to add you to the channel's Allowed Launchers in `/cursor settings`."
+
 // Dependencies groups the external dependencies the command handler needs.
 type Dependencies struct {
 	Client         *pluginapi.Client
@@ -40,6 +68,68 @@ type Dependencies struct {
 	BotUserID      string
 	SiteURL        string
 	PluginID       string
+
+	// BotUserIDFn returns the bot identity selected for a channel. Optional;
+	// BotUserID is used when nil.
+	BotUserIDFn func(channelID string) string
+
+	// BotUsernamesFn returns the usernames of additional bot identities that
+	// can be selected per channel. Optional.
+	BotUsernamesFn func() []string
+
+	// CanLaunchFn reports whether a user may launch agents in a channel.
+	// Optional; everyone may launch when nil.
+	CanLaunchFn func(userID, channelID string) bool
+
+	// CanManageLaunchersFn reports whether a user may change who launches
+	// agents in a channel. Optional; the settings dialog omits the allowed
+	// launchers field when nil.
+	CanManageLaunchersFn func(userID, channelID string) bool
+
+	// ChannelLinkedRepoFn returns the GitHub repository linked to a channel,
+	// used when no repository is given or configured. Optional; there is no
+	// fallback when nil.
+	ChannelLinkedRepoFn func(channelID string) string
+
+	// BaseBranchMissingFn reports whether GitHub confirms that a "base="
+	// branch does not exist in a repository. Optional; the base is not
+	// checked when nil.
+	BaseBranchMissingFn func(repo, base string) bool
+
+	// AllowedModelsFn returns the models users may launch agents with.
+	// Optional; every model is allowed when nil or when it returns none.
+	AllowedModelsFn func() []string
+
+	// SetReviewLoopsPausedFn pauses or resumes every review loop. Optional;
+	// /cursor admin pause-loops is unavailable when nil.
+	SetReviewLoopsPausedFn func(paused bool) error
+
+	// OwnershipTransferredFn is called after /cursor transfer hands an agent
+	// and its linked records to a new owner, so their current state can be
+	// pushed to that user. Optional.
+	OwnershipTransferredFn func(record *kvstore.AgentRecord)
+
+	// DecorateBotPostFn applies the configured bot post prefix to a post
+	// before it is created. Optional; posts are sent unchanged when nil.
+	DecorateBotPostFn func(post *model.Post) *model.Post
+
+	// DispatchReviewLoopFn collects and dispatches review feedback for a loop
+	// on demand. Optional; /cursor review dispatch is unavailable when nil.
+	DispatchReviewLoopFn func(loop *kvstore.ReviewLoop) (ReviewDispatchResult, error)
+
+	// IntegrationStatusFn reports which GitHub integrations are configured,
+	// for /cursor whoami. Optional; the integrations section is omitted when
+	// nil.
+	IntegrationStatusFn func() IntegrationStatus
+
+	// LinkPullRequestFn verifies a PR on GitHub and binds it to an agent,
+	// bootstrapping its review loop when applicable. Optional; /cursor link
+	// is unavailable when nil.
+	LinkPullRequestFn func(record *kvstore.AgentRecord, prURL string) (LinkPullRequestResult, error)
+
+	// NowFn returns the current time, so timestamps and snooze deadlines
+	// follow the plugin's clock. Optional; the system clock is used when nil.
+	NowFn func() time.Time
 }
 
 // Handler processes /cursor slash commands.
@@ -60,12 +150,36 @@ func NewHandler(deps Dependencies) Command {
 	return &Handler{deps: deps}
 }
 
+// botUserID returns the user ID the handler posts and reacts as in channelID.
+func (h *Handler) botUserID(channelID string) string {
+	if h.deps.BotUserIDFn != nil {
+		return h.deps.BotUserIDFn(channelID)
+	}
+	return h.deps.BotUserID
+}
+
+// now returns the current time from NowFn, if set.
+func (h *Handler) now() time.Time {
+	if h.deps.NowFn != nil {
+		return h.deps.NowFn()
+	}
+	return time.Now()
+}
+
+// decorateBotPost applies DecorateBotPostFn to post, if set.
+func (h *Handler) decorateBotPost(post *model.Post) *model.Post {
+	if h.deps.DecorateBotPostFn != nil {
+		return h.deps.DecorateBotPostFn(post)
+	}
+	return post
+}
+
 func getCommand() *model.Command {
 	return &model.Command{
 		Trigger:          CommandTrigger,
 		AutoComplete:     true,
 		AutoCompleteDesc: "Launch and manage Cursor Background Agents",
-		AutoCompleteHint: "[prompt] | list | status | cancel | settings | models | help",
+		AutoCompleteHint: "[prompt] | list | recent | status | cancel | transfer | link | settings | alias | snooze | plan | models | whoami | help",
 		AutocompleteData: getAutocompleteData(),
 	}
 }
@@ -80,6 +194,12 @@ func getAutocompleteData() *model.AutocompleteData {
 	list := model.NewAutocompleteData(subcommandList, "", "List your active agents with status")
 	ac.AddCommand(list)
 
+	recent := model.NewAutocompleteData(subcommandRecent, "[run]", "List your recent launches")
+	recentRun := model.NewAutocompleteData(recentActionRun, "<n>", "Launch entry n from your recent launches again")
+	recentRun.AddTextArgument("Entry number from /cursor recent", "<n>", "")
+	recent.AddCommand(recentRun)
+	ac.AddCommand(recent)
+
 	status := model.NewAutocompleteData(subcommandStatus, "<agentID>", "Show detailed status of a specific agent")
 	status.AddTextArgument("Agent ID (from /cursor list)", "[agentID]", "")
 	ac.AddCommand(status)
@@ -88,12 +208,58 @@ func getAutocompleteData() *model.AutocompleteData {
 	cancel.AddTextArgument("Agent ID to cancel", "[agentID]", "")
 	ac.AddCommand(cancel)
 
-	settings := model.NewAutocompleteData(subcommandSettings, "", "Configure channel and user defaults")
+	transfer := model.NewAutocompleteData(subcommandTransfer, "<agentID> @user", "Hand an agent and its workflow or review loop to another user")
+	transfer.AddTextArgument("Agent ID, then the new owner", "<agentID> @user", "")
+	ac.AddCommand(transfer)
+
+	link := model.NewAutocompleteData(subcommandLink, "<agentID> <PR URL>", "Link an existing PR to an agent whose PR was not detected")
+	link.AddTextArgument("Agent ID, then the PR URL", "<agentID> <PR URL>", "")
+	ac.AddCommand(link)
+
+	settings := model.NewAutocompleteData(subcommandSettings, "[reset]", "Configure channel and user defaults")
+	settingsReset := model.NewAutocompleteData(settingsActionReset, "", "Clear your user settings so channel and global defaults apply")
+	settings.AddCommand(settingsReset)
 	ac.AddCommand(settings)
 
+	alias := model.NewAutocompleteData(subcommandAlias, "[set|list|remove]", "Manage your launch shortcuts")
+	aliasSet := model.NewAutocompleteData(aliasActionSet, "<name> <options>", "Save a shortcut, e.g. frontend repo=org/web branch=develop")
+	aliasSet.AddTextArgument("Alias name and options", "<name> <options>", "")
+	alias.AddCommand(aliasSet)
+	alias.AddCommand(model.NewAutocompleteData(aliasActionList, "", "List your shortcuts"))
+	aliasRemove := model.NewAutocompleteData(aliasActionRemove, "<name>", "Delete a shortcut")
+	aliasRemove.AddTextArgument("Alias name", "<name>", "")
+	alias.AddCommand(aliasRemove)
+	ac.AddCommand(alias)
+
+	snooze := model.NewAutocompleteData(subcommandSnooze, "<PR URL or review loop ID> <duration|off>", "Pause stale-review reminders for a review loop")
+	snooze.AddTextArgument("PR URL or review loop ID, then a duration like 4h or 2d", "<PR URL> <duration|off>", "")
+	ac.AddCommand(snooze)
+
+	review := model.NewAutocompleteData(subcommandReview, "[dispatch]", "Review loop operations")
+	reviewDispatch := model.NewAutocompleteData(reviewActionDispatch, "<PR URL or review loop ID>", "Collect review feedback and send it to the agent now")
+	reviewDispatch.AddTextArgument("PR URL or review loop ID", "<PR URL>", "")
+	review.AddCommand(reviewDispatch)
+	ac.AddCommand(review)
+
+	plan := model.NewAutocompleteData(subcommandPlan, "[diff]", "Inspect HITL workflow plans")
+	planDiff := model.NewAutocompleteData(planActionDiff, "<workflow-id>", "Show what changed between the latest two plan versions")
+	planDiff.AddTextArgument("Workflow ID", "<workflow-id>", "")
+	plan.AddCommand(planDiff)
+	ac.AddCommand(plan)
+
+	admin := model.NewAutocompleteData(subcommandAdmin, "[pause-loops|resume-loops|gc]", "System admin operations")
+	admin.RoleID = model.SystemAdminRoleId
+	admin.AddCommand(model.NewAutocompleteData(adminActionPauseLoops, "", "Pause all review loops, e.g. during an incident"))
+	admin.AddCommand(model.NewAutocompleteData(adminActionResumeLoops, "", "Resume review loops and re-evaluate held reviews"))
+	admin.AddCommand(model.NewAutocompleteData(adminActionGC, "[--prune]", "Report orphaned stored index entries; --prune deletes them"))
+	ac.AddCommand(admin)
+
 	models := model.NewAutocompleteData(subcommandModels, "", "List available Cursor AI models")
 	ac.AddCommand(models)
 
+	whoami := model.NewAutocompleteData(subcommandWhoami, "", "Show the Cursor account and integrations this server uses")
+	ac.AddCommand(whoami)
+
 	help := model.NewAutocompleteData(subcommandHelp, "", "Show help for /cursor commands")
 	ac.AddCommand(help)
 
@@ -117,10 +283,65 @@ func (h *Handler) Handle(args *model.CommandArgs) (*model.CommandResponse, error
 		return h.executeStatus(args, fields[2:])
 	case subcommandCancel:
 		return h.executeCancel(args, fields[2:])
+	case subcommandTransfer:
+		// "/cursor transfer ..." may also start a launch prompt; only
+		// "<agentID> @user" is treated as a transfer.
+		if isTransferCommand(fields[2:]) {
+			return h.executeTransfer(args, fields[2:])
+		}
+		return h.executeLaunch(args)
+	case subcommandLink:
+		// Like "transfer", "/cursor link ..." may start a launch prompt; only
+		// "<agentID> <PR URL>" is treated as a link.
+		if isLinkCommand(fields[2:]) {
+			return h.executeLink(args, fields[2:])
+		}
+		return h.executeLaunch(args)
 	case subcommandSettings:
+		if len(fields) > 2 && strings.EqualFold(fields[2], settingsActionReset) {
+			return h.executeSettingsReset(args)
+		}
 		return h.executeSettings(args)
+	case subcommandAlias:
+		return h.executeAlias(args, fields[2:])
+	case subcommandSnooze:
+		return h.executeSnooze(args, fields[2:])
+	case subcommandPlan:
+		// "/cursor plan ..." is also a natural launch prompt; only the
+		// diff action is treated as a subcommand.
+		if len(fields) > 2 && strings.EqualFold(fields[2], planActionDiff) {
+			return h.executePlan(args, fields[2:])
+		}
+		return h.executeLaunch(args)
+	case subcommandReview:
+		// "/cursor review ..." is also a natural launch prompt; only the
+		// dispatch action is treated as a subcommand.
+		if len(fields) > 2 && strings.EqualFold(fields[2], reviewActionDispatch) {
+			return h.executeReview(args, fields[2:])
+		}
+		return h.executeLaunch(args)
+	case subcommandAdmin:
+		// Like "plan", "admin ..." may start a launch prompt; only the known
+		// admin actions are treated as a subcommand.
+		if len(fields) > 2 && isAdminAction(fields[2]) {
+			return h.executeAdmin(args, fields[2:])
+		}
+		return h.executeLaunch(args)
 	case subcommandModels:
 		return h.executeModels(args)
+	case subcommandRecent:
+		// "/cursor recent ..." may also start a launch prompt; only the bare
+		// subcommand and the run action are treated as a subcommand.
+		if len(fields) == 2 || strings.EqualFold(fields[2], recentActionRun) {
+			return h.executeRecent(args, fields[2:])
+		}
+		return h.executeLaunch(args)
+	case subcommandWhoami:
+		// Like "plan", "whoami ..." with more words is a launch prompt.
+		if len(fields) == 2 {
+			return h.executeWhoami(args)
+		}
+		return h.executeLaunch(args)
 	case subcommandHelp:
 		return h.executeHelp(), nil
 	default:
@@ -140,7 +361,10 @@ func (h *Handler) executeLaunch(args *model.CommandArgs) (*model.CommandResponse
 		return ephemeralResponse("Please provide a prompt. Usage: `/cursor <prompt>`"), nil
 	}
 
-	parsed := parser.Parse(prompt, "")
+	channelSettings, _ := h.deps.Store.GetChannelSettings(args.ChannelId)
+	userSettings, _ := h.deps.Store.GetUserSettings(args.UserId)
+
+	parsed := parser.ParseWithAliases(prompt, "", safeUserAliases(userSettings))
 	if parsed == nil {
 		parsed = &parser.ParsedMention{Prompt: prompt}
 	}
@@ -148,14 +372,14 @@ func (h *Handler) executeLaunch(args *model.CommandArgs) (*model.CommandResponse
 		parsed.Prompt = prompt
 	}
 
-	channelSettings, _ := h.deps.Store.GetChannelSettings(args.ChannelId)
-	userSettings, _ := h.deps.Store.GetUserSettings(args.UserId)
-
 	repo := coalesce(
 		parsed.Repository,
 		safeChannelRepo(channelSettings),
 		safeUserRepo(userSettings),
 	)
+	if repo == "" && h.deps.ChannelLinkedRepoFn != nil {
+		repo = h.deps.ChannelLinkedRepoFn(args.ChannelId)
+	}
 	branch := coalesce(
 		parsed.Branch,
 		safeChannelBranch(channelSettings),
@@ -176,21 +400,64 @@ func (h *Handler) executeLaunch(args *model.CommandArgs) (*model.CommandResponse
 		return ephemeralResponse("No repository specified. Use `repo=owner/repo` in your prompt or set a default with `/cursor settings`."), nil
 	}
 
-	repoURL := repo
-	if !strings.Contains(repo, "://") {
-		repoURL = "https://github.com/" + repo
+	return h.launchAgent(args, launchParams{
+		Prompt:       parsed.Prompt,
+		Repository:   repo,
+		Branch:       branch,
+		Ref:          parsed.Ref,
+		BaseBranch:   parsed.Base,
+		Model:        cursorModel,
+		AutoCreatePR: autoCreatePR,
+		Draft:        parsed.Draft != nil && *parsed.Draft,
+	})
+}
+
+// launchParams are the resolved options for a slash command launch.
+type launchParams struct {
+	Prompt       string
+	Repository   string
+	Branch       string
+	Ref          string // Commit, tag, or branch from "ref=", empty to start from Branch
+	BaseBranch   string
+	Model        string
+	AutoCreatePR bool
+	Draft        bool
+}
+
+// launchAgent launches an agent with resolved options, posts its status
+// message in the channel and records the launch in the user's history.
+func (h *Handler) launchAgent(args *model.CommandArgs, params launchParams) (*model.CommandResponse, error) {
+	if h.deps.CanLaunchFn != nil && !h.deps.CanLaunchFn(args.UserId, args.ChannelId) {
+		return ephemeralResponse(LaunchNotPermittedMessage), nil
+	}
+	branch, cursorModel := params.Branch, params.Model
+
+	repoRef, err := ghclient.ParseRepoRef(params.Repository)
+	if err != nil {
+		return ephemeralResponse(formatRepoRefError(err)), nil
+	}
+	repo := repoRef.String()
+
+	if allowed := h.allowedModels(); len(allowed) > 0 && !containsFold(allowed, cursorModel) {
+		return ephemeralResponse(fmt.Sprintf("Model `%s` is not allowed on this server. Choose one of: `%s`.", cursorModel, strings.Join(allowed, "`, `"))), nil
+	}
+
+	if params.BaseBranch != "" && h.deps.BaseBranchMissingFn != nil && h.deps.BaseBranchMissingFn(repo, params.BaseBranch) {
+		return ephemeralResponse(fmt.Sprintf("Base branch `%s` was not found in `%s`. Check the `base=` option and try again.", params.BaseBranch, repo)), nil
 	}
 
 	launchReq := cursor.LaunchAgentRequest{
-		Prompt: cursor.Prompt{Text: parsed.Prompt},
+		Prompt: cursor.Prompt{Text: params.Prompt},
 		Source: cursor.Source{
-			Repository: repoURL,
-			Ref:        branch,
+			Repository: repoRef.URL(),
+			Ref:        coalesce(params.Ref, branch),
 		},
 		Target: &cursor.Target{
-			BranchName:   fmt.Sprintf("cursor/%s", sanitizeBranchName(parsed.Prompt)),
-			AutoCreatePr: autoCreatePR,
+			BranchName:   fmt.Sprintf("cursor/%s", sanitizeBranchName(params.Prompt)),
+			BaseBranch:   params.BaseBranch,
+			AutoCreatePr: params.AutoCreatePR,
 			AutoBranch:   true,
+			Draft:        params.Draft,
 		},
 		Model: cursorModel,
 	}
@@ -205,23 +472,23 @@ func (h *Handler) executeLaunch(args *model.CommandArgs) (*model.CommandResponse
 
 	launchAttachment := attachments.BuildLaunchAttachment(agent.ID, repo, branch, cursorModel)
 	botPost := &model.Post{
-		UserId:    h.deps.BotUserID,
+		UserId:    h.botUserID(args.ChannelId),
 		ChannelId: args.ChannelId,
 	}
 	model.ParseSlackAttachment(botPost, []*model.SlackAttachment{launchAttachment})
 	botPost.AddProp("cursor_agent_id", agent.ID)
 	botPost.AddProp("cursor_agent_status", string(agent.Status))
-	if err := h.deps.Client.Post.CreatePost(botPost); err != nil {
+	if err := h.deps.Client.Post.CreatePost(h.decorateBotPost(botPost)); err != nil {
 		return ephemeralResponse("Failed to post agent status message."), nil
 	}
 
 	_ = h.deps.Client.Post.AddReaction(&model.Reaction{
-		UserId:    h.deps.BotUserID,
+		UserId:    h.botUserID(args.ChannelId),
 		PostId:    botPost.Id,
 		EmojiName: "hourglass_flowing_sand",
 	})
 
-	now := time.Now().UnixMilli()
+	now := h.now().UnixMilli()
 	_ = h.deps.Store.SaveAgent(&kvstore.AgentRecord{
 		CursorAgentID:  agent.ID,
 		PostID:         botPost.Id,
@@ -231,14 +498,29 @@ func (h *Handler) executeLaunch(args *model.CommandArgs) (*model.CommandResponse
 		Status:         string(agent.Status),
 		Repository:     repo,
 		Branch:         branch,
+		SourceRef:      params.Ref,
 		TargetBranch:   launchReq.Target.BranchName,
-		Prompt:         parsed.Prompt,
+		BaseBranch:     params.BaseBranch,
+		Draft:          params.Draft,
+		Prompt:         params.Prompt,
 		Model:          cursorModel,
 		BotReplyPostID: botPost.Id,
 		CreatedAt:      now,
 		UpdatedAt:      now,
 	})
 	_ = h.deps.Store.SetThreadAgent(botPost.Id, agent.ID)
+	autoCreatePR := params.AutoCreatePR
+	h.recordLaunch(args.UserId, kvstore.LaunchHistoryEntry{
+		Prompt:       params.Prompt,
+		Repository:   repo,
+		Branch:       branch,
+		SourceRef:    params.Ref,
+		BaseBranch:   params.BaseBranch,
+		Model:        cursorModel,
+		AutoCreatePR: &autoCreatePR,
+		Draft:        params.Draft,
+		LaunchedAt:   now,
+	})
 
 	return &model.CommandResponse{}, nil
 }
@@ -417,20 +699,20 @@ func (h *Handler) executeCancel(args *model.CommandArgs, params []string) (*mode
 
 	if localAgent.PostID != "" {
 		cancelPost := &model.Post{
-			UserId:    h.deps.BotUserID,
+			UserId:    h.botUserID(localAgent.ChannelID),
 			ChannelId: localAgent.ChannelID,
 			RootId:    localAgent.PostID,
 			Message:   fmt.Sprintf(":no_entry_sign: Agent `%s` was cancelled by <@%s>.", id, args.UserId),
 		}
-		_ = h.deps.Client.Post.CreatePost(cancelPost)
+		_ = h.deps.Client.Post.CreatePost(h.decorateBotPost(cancelPost))
 
 		_ = h.deps.Client.Post.RemoveReaction(&model.Reaction{
-			UserId:    h.deps.BotUserID,
+			UserId:    h.botUserID(localAgent.ChannelID),
 			PostId:    localAgent.TriggerPostID,
 			EmojiName: "hourglass_flowing_sand",
 		})
 		_ = h.deps.Client.Post.AddReaction(&model.Reaction{
-			UserId:    h.deps.BotUserID,
+			UserId:    h.botUserID(localAgent.ChannelID),
 			PostId:    localAgent.TriggerPostID,
 			EmojiName: "no_entry_sign",
 		})
@@ -474,18 +756,18 @@ func (h *Handler) cancelWorkflow(args *model.CommandArgs, workflow *kvstore.HITL
 
 	// Update workflow to rejected.
 	workflow.Phase = kvstore.PhaseRejected
-	workflow.UpdatedAt = time.Now().UnixMilli()
+	workflow.UpdatedAt = h.now().UnixMilli()
 	_ = h.deps.Store.SaveWorkflow(workflow)
 
 	// Update reactions on trigger post.
 	if workflow.TriggerPostID != "" {
 		_ = h.deps.Client.Post.RemoveReaction(&model.Reaction{
-			UserId:    h.deps.BotUserID,
+			UserId:    h.botUserID(workflow.ChannelID),
 			PostId:    workflow.TriggerPostID,
 			EmojiName: "hourglass_flowing_sand",
 		})
 		_ = h.deps.Client.Post.AddReaction(&model.Reaction{
-			UserId:    h.deps.BotUserID,
+			UserId:    h.botUserID(workflow.ChannelID),
 			PostId:    workflow.TriggerPostID,
 			EmojiName: "no_entry_sign",
 		})
@@ -494,17 +776,40 @@ func (h *Handler) cancelWorkflow(args *model.CommandArgs, workflow *kvstore.HITL
 	// Post cancellation message in thread.
 	if workflow.RootPostID != "" {
 		cancelPost := &model.Post{
-			UserId:    h.deps.BotUserID,
+			UserId:    h.botUserID(workflow.ChannelID),
 			ChannelId: workflow.ChannelID,
 			RootId:    workflow.RootPostID,
 			Message:   fmt.Sprintf(":no_entry_sign: Workflow cancelled by <@%s>.", args.UserId),
 		}
-		_ = h.deps.Client.Post.CreatePost(cancelPost)
+		_ = h.deps.Client.Post.CreatePost(h.decorateBotPost(cancelPost))
 	}
 
 	return ephemeralResponse(fmt.Sprintf("Workflow `%s` has been cancelled.", workflow.ID)), nil
 }
 
+// executeSettingsReset clears the invoking user's stored settings so channel
+// and global defaults apply again. Channel settings are left untouched.
+func (h *Handler) executeSettingsReset(args *model.CommandArgs) (*model.CommandResponse, error) {
+	settings, err := h.deps.Store.GetUserSettings(args.UserId)
+	if err != nil {
+		return ephemeralResponse("Failed to load your settings. Please try again."), nil
+	}
+
+	// Aliases are managed with /cursor alias, so a settings reset only clears
+	// the launch defaults and leaves them in place.
+	if aliases := safeUserAliases(settings); len(aliases) > 0 {
+		err = h.deps.Store.SaveUserSettings(args.UserId, &kvstore.UserSettings{Aliases: aliases})
+	} else {
+		err = h.deps.Store.DeleteUserSettings(args.UserId)
+	}
+	if err != nil {
+		h.deps.Client.Log.Error("Failed to reset user settings", "user_id", args.UserId, "error", err.Error())
+		return ephemeralResponse("Failed to reset your settings. Please try again."), nil
+	}
+
+	return ephemeralResponse("Your Cursor settings have been reset. Channel and global defaults now apply."), nil
+}
+
 func (h *Handler) executeSettings(args *model.CommandArgs) (*model.CommandResponse, error) {
 	channelSettings, _ := h.deps.Store.GetChannelSettings(args.ChannelId)
 	userSettings, _ := h.deps.Store.GetUserSettings(args.UserId)
@@ -538,6 +843,14 @@ func (h *Handler) executeSettings(args *model.CommandArgs) (*model.CommandRespon
 					Optional:    true,
 					Default:     safeChannelBranch(channelSettings),
 				},
+				{
+					DisplayName: "Mirror Agent Progress",
+					Name:        "channel_mirror_progress",
+					Type:        "bool",
+					HelpText:    "When enabled, the latest message from a running agent is posted to its thread and kept up to date until the agent finishes.",
+					Optional:    true,
+					Default:     safeChannelMirrorProgress(channelSettings),
+				},
 				{
 					DisplayName: "Your Default Repo",
 					Name:        "user_default_repo",
@@ -589,6 +902,19 @@ func (h *Handler) executeSettings(args *model.CommandArgs) (*model.CommandRespon
 		},
 	}
 
+	var channelElements []model.DialogElement
+	if botElement := h.channelBotElement(channelSettings); botElement != nil {
+		channelElements = append(channelElements, *botElement)
+	}
+	if launchersElement := h.channelLaunchersElement(args, channelSettings); launchersElement != nil {
+		channelElements = append(channelElements, *launchersElement)
+	}
+	if len(channelElements) > 0 {
+		// Keep channel settings together, ahead of the personal ones.
+		elements := dialogRequest.Dialog.Elements
+		dialogRequest.Dialog.Elements = append(elements[:2:2], append(channelElements, elements[2:]...)...)
+	}
+
 	appErr := h.deps.Client.Frontend.OpenInteractiveDialog(dialogRequest)
 	if appErr != nil {
 		return ephemeralResponse("Failed to open settings dialog."), nil
@@ -614,9 +940,22 @@ func (h *Handler) executeModels(args *model.CommandArgs) (*model.CommandResponse
 		return ephemeralResponse("No models available."), nil
 	}
 
+	models := resp.Models
+	if allowed := h.allowedModels(); len(allowed) > 0 {
+		models = nil
+		for _, m := range resp.Models {
+			if containsFold(allowed, m) {
+				models = append(models, m)
+			}
+		}
+		if len(models) == 0 {
+			return ephemeralResponse("None of the available models are allowed on this server. Ask your admin to update the allowed models."), nil
+		}
+	}
+
 	var sb strings.Builder
 	sb.WriteString("#### Available Cursor Models\n\n")
-	for _, m := range resp.Models {
+	for _, m := range models {
 		sb.WriteString(fmt.Sprintf("- `%s`\n", m))
 	}
 	sb.WriteString("\nUse a model with: `@cursor with <model>, <prompt>` or `model=<model>` in your prompt.")
@@ -624,6 +963,15 @@ func (h *Handler) executeModels(args *model.CommandArgs) (*model.CommandResponse
 	return ephemeralResponse(sb.String()), nil
 }
 
+// allowedModels returns the configured model allowlist, or nil when every
+// model is allowed.
+func (h *Handler) allowedModels() []string {
+	if h.deps.AllowedModelsFn == nil {
+		return nil
+	}
+	return h.deps.AllowedModelsFn()
+}
+
 func (h *Handler) executeHelp() *model.CommandResponse {
 	helpText := `#### Cursor Background Agents - Help
 
@@ -633,6 +981,9 @@ func (h *Handler) executeHelp() *model.CommandResponse {
 ` + "- `@cursor in <repo>, <prompt>` - Specify repository" + `
 ` + "- `@cursor with <model>, <prompt>` - Specify AI model" + `
 ` + "- `@cursor [repo=org/repo, branch=dev, model=opus] <prompt>` - Inline options" + `
+` + "- `@cursor ref=v1.2.0 <prompt>` - Start from a tag or commit instead of the branch" + `
+` + "- `@cursor --no-attach <prompt>` - Don't include files attached to the post in the prompt" + `
+` + "- `@cursor --no-pr <prompt>` - Push a branch without opening a PR" + `
 
 **HITL Verification Flags:**
 ` + "- `@cursor --direct <prompt>` - Skip both review stages (legacy behavior)" + `
@@ -641,12 +992,32 @@ func (h *Handler) executeHelp() *model.CommandResponse {
 
 **Management:**
 ` + "- `/cursor list` - List your active agents with status" + `
+` + "- `/cursor recent` - List your recent launches" + `
+` + "- `/cursor recent run <n>` - Launch entry n from your recent launches again" + `
 ` + "- `/cursor status <agentID>` - Detailed status of a specific agent" + `
 ` + "- `/cursor cancel <agentID or workflowID>` - Cancel an agent or HITL workflow" + `
+` + "- `/cursor transfer <agentID> @user` - Hand an agent and its workflow or review loop to another user (owner or channel admin)" + `
+` + "- `/cursor link <agentID> <PR URL>` - Link an existing PR to your agent when it was not detected, starting its review loop" + `
+` + "- `/cursor snooze <PR URL> <duration|off>` - Pause stale-review reminders for a review loop (e.g. `4h`, `2d`)" + `
+` + "- `/cursor review dispatch <PR URL>` - Collect review feedback for your review loop and send it to the agent now" + `
+` + "- `/cursor plan diff <workflowID>` - Show what changed between the latest two plan versions" + `
+
+**Shortcuts:**
+` + "- `/cursor alias set <name> <options>` - Save launch options, e.g. `/cursor alias set frontend repo=org/web branch=develop`" + `
+` + "- `/cursor alias list` - List your shortcuts" + `
+` + "- `/cursor alias remove <name>` - Delete a shortcut" + `
+` + "- `@cursor @<name> <prompt>` - Launch with a shortcut's options; options in the message take precedence" + `
 
 **Configuration:**
 ` + "- `/cursor settings` - Configure channel and user defaults (including HITL toggles)" + `
+` + "- `/cursor settings reset` - Clear your user defaults so channel and global defaults apply (aliases are kept)" + `
 ` + "- `/cursor models` - List available AI models" + `
+` + "- `/cursor whoami` - Show the Cursor account behind the API key and which GitHub integrations are configured" + `
+
+**Administration (system admins):**
+` + "- `/cursor admin pause-loops` - Pause all review loops; reviews are held until resumed" + `
+` + "- `/cursor admin resume-loops` - Resume review loops and re-evaluate held reviews" + `
+` + "- `/cursor admin gc [--prune]` - Report orphaned stored index entries, and delete them with `--prune`" + `
 
 **In Threads:**
 - Reply in a review thread to refine context or plan
@@ -700,6 +1071,16 @@ func phaseToEmoji(phase string) string {
 	}
 }
 
+// containsFold reports whether values contains s, ignoring case.
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
 func coalesce(values ...string) string {
 	for _, v := range values {
 		if v != "" {
@@ -726,6 +1107,16 @@ func sanitizeBranchName(prompt string) string {
 	return s
 }
 
+// formatRepoRefError explains why a repository could not be used for a
+// launch.
+func formatRepoRefError(err error) string {
+	var refErr *ghclient.RepoRefError
+	if errors.As(err, &refErr) {
+		return fmt.Sprintf("Invalid repository `%s`: %s. Use `repo=owner/repo` or a GitHub repository URL.", strings.TrimSpace(refErr.Input), refErr.Err)
+	}
+	return fmt.Sprintf("Invalid repository: %s.", err.Error())
+}
+
 // formatAPIError formats an error from the Cursor API into a user-friendly message.
 // If the error is a cursor.APIError with a JSON RawBody, the JSON is pretty-printed inside a
 // markdown code block so that Mattermost renders it cleanly (no emoji parsing, proper wrapping).
@@ -741,6 +1132,60 @@ func formatAPIError(action string, err error) string {
 	return fmt.Sprintf(":x: **%s**\n\n%s", action, err.Error())
 }
 
+// channelBotElement returns the channel bot identity select for the settings
+// dialog, or nil when no additional bot identities are configured.
+func (h *Handler) channelBotElement(channelSettings *kvstore.ChannelSettings) *model.DialogElement {
+	if h.deps.BotUsernamesFn == nil {
+		return nil
+	}
+	usernames := h.deps.BotUsernamesFn()
+	if len(usernames) == 0 {
+		return nil
+	}
+
+	options := make([]*model.PostActionOptions, 0, len(usernames))
+	for _, username := range usernames {
+		options = append(options, &model.PostActionOptions{Text: "@" + username, Value: username})
+	}
+	return &model.DialogElement{
+		DisplayName: "Channel Bot",
+		Name:        "channel_bot_username",
+		Type:        "select",
+		HelpText:    "Bot identity that replies in this channel. Leave empty to use the default bot.",
+		Optional:    true,
+		Default:     safeChannelBotUsername(channelSettings),
+		Options:     options,
+	}
+}
+
+// channelLaunchersElement returns the allowed launchers field for the settings
+// dialog, or nil when the user may not manage who launches in the channel.
+// The field lists usernames; the submission handler resolves them to IDs.
+func (h *Handler) channelLaunchersElement(args *model.CommandArgs, channelSettings *kvstore.ChannelSettings) *model.DialogElement {
+	if h.deps.CanManageLaunchersFn == nil || !h.deps.CanManageLaunchersFn(args.UserId, args.ChannelId) {
+		return nil
+	}
+
+	var usernames []string
+	if channelSettings != nil {
+		for _, userID := range channelSettings.AllowedLauncherUserIDs {
+			if user, err := h.deps.Client.User.Get(userID); err == nil && user != nil {
+				usernames = append(usernames, user.Username)
+			}
+		}
+	}
+	return &model.DialogElement{
+		DisplayName: "Allowed Launchers",
+		Name:        "channel_allowed_launchers",
+		Type:        "text",
+		SubType:     "text",
+		Placeholder: "alice, bob",
+		HelpText:    "Usernames, separated by commas, of the only people who may launch agents in this channel. Channel and system admins can always launch. Leave empty to let everyone launch.",
+		Optional:    true,
+		Default:     strings.Join(usernames, ", "),
+	}
+}
+
 // Safe accessors for nil settings.
 func safeChannelRepo(s *kvstore.ChannelSettings) string {
 	if s == nil {
@@ -756,6 +1201,27 @@ func safeChannelBranch(s *kvstore.ChannelSettings) string {
 	return s.DefaultBranch
 }
 
+func safeChannelBotUsername(s *kvstore.ChannelSettings) string {
+	if s == nil {
+		return ""
+	}
+	return s.BotUsername
+}
+
+func safeChannelMirrorProgress(s *kvstore.ChannelSettings) string {
+	if s == nil || !s.MirrorProgress {
+		return "false"
+	}
+	return "true"
+}
+
+func safeUserAliases(s *kvstore.UserSettings) map[string]string {
+	if s == nil {
+		return nil
+	}
+	return s.Aliases
+}
+
 func safeUserRepo(s *kvstore.UserSettings) string {
 	if s == nil {
 		return ""
@@ -3,8 +3,10 @@ package command
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
@@ -115,6 +117,10 @@ func (m *mockKVStore) DeleteAgent(cursorAgentID string) error {
 	return m.Called(cursorAgentID).Error(0)
 }
 
+func (m *mockKVStore) RemoveAgentFromUser(userID, cursorAgentID string) error {
+	return m.Called(userID, cursorAgentID).Error(0)
+}
+
 func (m *mockKVStore) ListActiveAgents() ([]*kvstore.AgentRecord, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
@@ -168,6 +174,22 @@ func (m *mockKVStore) SaveUserSettings(userID string, settings *kvstore.UserSett
 	return m.Called(userID, settings).Error(0)
 }
 
+func (m *mockKVStore) DeleteUserSettings(userID string) error {
+	return m.Called(userID).Error(0)
+}
+
+func (m *mockKVStore) GetUserLaunchHistory(userID string) (*kvstore.UserLaunchHistory, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*kvstore.UserLaunchHistory), args.Error(1)
+}
+
+func (m *mockKVStore) AddUserLaunchHistoryEntry(userID string, entry kvstore.LaunchHistoryEntry, keep int) error {
+	return m.Called(userID, entry, keep).Error(0)
+}
+
 func (m *mockKVStore) GetAgentByPRURL(prURL string) (*kvstore.AgentRecord, error) {
 	args := m.Called(prURL)
 	if args.Get(0) == nil {
@@ -193,6 +215,26 @@ func (m *mockKVStore) MarkDeliveryProcessed(deliveryID string) error {
 	return m.Called(deliveryID).Error(0)
 }
 
+func (m *mockKVStore) SaveWebhookDelivery(delivery *kvstore.WebhookDelivery, keep int) error {
+	return m.Called(delivery, keep).Error(0)
+}
+
+func (m *mockKVStore) GetWebhookDelivery(deliveryID string) (*kvstore.WebhookDelivery, error) {
+	args := m.Called(deliveryID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*kvstore.WebhookDelivery), args.Error(1)
+}
+
+func (m *mockKVStore) ListWebhookDeliveries() ([]*kvstore.WebhookDelivery, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*kvstore.WebhookDelivery), args.Error(1)
+}
+
 func (m *mockKVStore) GetWorkflow(workflowID string) (*kvstore.HITLWorkflow, error) {
 	args := m.Called(workflowID)
 	if args.Get(0) == nil {
@@ -266,6 +308,88 @@ func (m *mockKVStore) GetReviewLoopByAgent(agentRecordID string) (*kvstore.Revie
 	return args.Get(0).(*kvstore.ReviewLoop), args.Error(1)
 }
 
+func (m *mockKVStore) ListQuietHoursDeferredReviewLoops() ([]*kvstore.ReviewLoop, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*kvstore.ReviewLoop), args.Error(1)
+}
+
+func (m *mockKVStore) ListWorkflows() ([]*kvstore.HITLWorkflow, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*kvstore.HITLWorkflow), args.Error(1)
+}
+
+func (m *mockKVStore) ListActiveReviewLoops() ([]*kvstore.ReviewLoop, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*kvstore.ReviewLoop), args.Error(1)
+}
+
+func (m *mockKVStore) GetReviewLoopDigestDate() (string, error) {
+	args := m.Called()
+	return args.String(0), args.Error(1)
+}
+
+func (m *mockKVStore) SetReviewLoopDigestDate(date string) error {
+	args := m.Called(date)
+	return args.Error(0)
+}
+
+func (m *mockKVStore) ListGitHubRetryReviewLoops() ([]*kvstore.ReviewLoop, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*kvstore.ReviewLoop), args.Error(1)
+}
+
+func (m *mockKVStore) ListRateLimitedReviewLoops() ([]*kvstore.ReviewLoop, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*kvstore.ReviewLoop), args.Error(1)
+}
+
+func (m *mockKVStore) ListPendingDispatchReviewLoops() ([]*kvstore.ReviewLoop, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*kvstore.ReviewLoop), args.Error(1)
+}
+
+func (m *mockKVStore) ListGloballyPausedReviewLoops() ([]*kvstore.ReviewLoop, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*kvstore.ReviewLoop), args.Error(1)
+}
+
+func (m *mockKVStore) ListWaitingReviewLoops() ([]*kvstore.ReviewLoop, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*kvstore.ReviewLoop), args.Error(1)
+}
+
+func (m *mockKVStore) ListFixingReviewLoops() ([]*kvstore.ReviewLoop, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*kvstore.ReviewLoop), args.Error(1)
+}
+
 func (m *mockKVStore) GetAllFinishedAgentsWithPR() ([]*kvstore.AgentRecord, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
@@ -274,6 +398,19 @@ func (m *mockKVStore) GetAllFinishedAgentsWithPR() ([]*kvstore.AgentRecord, erro
 	return args.Get(0).([]*kvstore.AgentRecord), args.Error(1)
 }
 
+func (m *mockKVStore) ListIndexEntries() ([]kvstore.IndexEntry, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]kvstore.IndexEntry), args.Error(1)
+}
+
+func (m *mockKVStore) DeleteIndexEntry(key string) error {
+	args := m.Called(key)
+	return args.Error(0)
+}
+
 type testEnv struct {
 	handler      Command
 	api          *plugintest.API
@@ -568,6 +705,174 @@ func TestCancel_Success(t *testing.T) {
 	env.store.AssertCalled(t, "SaveAgent", mock.Anything)
 }
 
+// setupTransferTest registers an agent owned by user-1 that belongs to a HITL
+// workflow (with a planner agent) and has a review loop.
+func setupTransferTest(t *testing.T) (*testEnv, *kvstore.AgentRecord, *kvstore.AgentRecord, *kvstore.HITLWorkflow, *kvstore.ReviewLoop) {
+	t.Helper()
+	env := setupTest(t)
+
+	implementer := &kvstore.AgentRecord{CursorAgentID: "impl-1", UserID: "user-1", ChannelID: "ch-1", PostID: "post-1", Status: "RUNNING"}
+	planner := &kvstore.AgentRecord{CursorAgentID: "plan-1", UserID: "user-1", ChannelID: "ch-1", Status: "FINISHED"}
+	workflow := &kvstore.HITLWorkflow{ID: "wf-1", UserID: "user-1", ChannelID: "ch-1", PlannerAgentID: "plan-1", ImplementerAgentID: "impl-1"}
+	loop := &kvstore.ReviewLoop{ID: "loop-1", UserID: "user-1", AgentRecordID: "impl-1"}
+
+	env.store.On("GetAgent", "impl-1").Return(implementer, nil)
+	env.store.On("GetAgent", "plan-1").Return(planner, nil)
+	env.store.On("GetWorkflowByAgent", "impl-1").Return("wf-1", nil)
+	env.store.On("GetWorkflow", "wf-1").Return(workflow, nil)
+	env.store.On("GetReviewLoopByAgent", "impl-1").Return(loop, nil)
+	env.api.On("GetUserByUsername", "bob").Return(&model.User{Id: "user-2", Username: "bob"}, nil)
+	env.api.On("GetChannelMember", "ch-1", "user-2").Return(&model.ChannelMember{ChannelId: "ch-1", UserId: "user-2"}, nil).Maybe()
+	env.api.On("GetUser", "user-1").Return(&model.User{Id: "user-1", Username: "alice"}, nil).Maybe()
+	env.api.On("GetUser", "admin-1").Return(&model.User{Id: "admin-1", Username: "carol"}, nil).Maybe()
+
+	return env, implementer, planner, workflow, loop
+}
+
+func TestTransfer_OwnerTransfersLinkedRecords(t *testing.T) {
+	env, implementer, planner, workflow, loop := setupTransferTest(t)
+
+	var notified *kvstore.AgentRecord
+	env.handler.(*Handler).deps.OwnershipTransferredFn = func(record *kvstore.AgentRecord) {
+		notified = record
+	}
+
+	env.store.On("SaveAgent", mock.Anything).Return(nil)
+	env.store.On("RemoveAgentFromUser", "user-1", "impl-1").Return(nil)
+	env.store.On("RemoveAgentFromUser", "user-1", "plan-1").Return(nil)
+	env.store.On("SaveWorkflow", workflow).Return(nil)
+	env.store.On("SaveReviewLoop", loop).Return(nil)
+	env.api.On("CreatePost", mock.MatchedBy(func(p *model.Post) bool {
+		return p.RootId == "post-1" &&
+			strings.Contains(p.Message, "transferred from @alice to @bob by @alice")
+	})).Return(&model.Post{Id: "note-1"}, nil)
+
+	resp, err := env.handler.Handle(&model.CommandArgs{
+		Command:   "/cursor transfer impl-1 @bob",
+		UserId:    "user-1",
+		ChannelId: "ch-1",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Agent `impl-1` now belongs to @bob.", resp.Text)
+	assert.Equal(t, "user-2", implementer.UserID)
+	assert.Equal(t, "user-2", planner.UserID)
+	assert.Equal(t, "user-2", workflow.UserID)
+	assert.Equal(t, "user-2", loop.UserID)
+	env.store.AssertNumberOfCalls(t, "SaveAgent", 2)
+	env.store.AssertExpectations(t)
+	env.api.AssertNotCalled(t, "HasPermissionToChannel", mock.Anything, mock.Anything, mock.Anything)
+	require.NotNil(t, notified)
+	assert.Equal(t, "impl-1", notified.CursorAgentID)
+}
+
+func TestTransfer_NoteUsesBotPostDecorator(t *testing.T) {
+	env, _, _, _, _ := setupTransferTest(t)
+	env.handler.(*Handler).deps.DecorateBotPostFn = func(post *model.Post) *model.Post {
+		post.Message = "[staging] " + post.Message
+		return post
+	}
+
+	env.store.On("SaveAgent", mock.Anything).Return(nil)
+	env.store.On("RemoveAgentFromUser", "user-1", mock.Anything).Return(nil)
+	env.store.On("SaveWorkflow", mock.Anything).Return(nil)
+	env.store.On("SaveReviewLoop", mock.Anything).Return(nil)
+	env.api.On("CreatePost", mock.MatchedBy(func(p *model.Post) bool {
+		return strings.HasPrefix(p.Message, "[staging] :arrows_counterclockwise: Ownership of agent")
+	})).Return(&model.Post{Id: "note-1"}, nil)
+
+	_, err := env.handler.Handle(&model.CommandArgs{
+		Command:   "/cursor transfer impl-1 @bob",
+		UserId:    "user-1",
+		ChannelId: "ch-1",
+	})
+
+	require.NoError(t, err)
+	env.api.AssertExpectations(t)
+}
+
+func TestTransfer_NonOwnerRejected(t *testing.T) {
+	env, implementer, _, workflow, loop := setupTransferTest(t)
+
+	env.api.On("HasPermissionToChannel", "user-3", "ch-1", model.PermissionManageChannelRoles).Return(false)
+
+	resp, err := env.handler.Handle(&model.CommandArgs{
+		Command:   "/cursor transfer impl-1 @bob",
+		UserId:    "user-3",
+		ChannelId: "ch-1",
+	})
+
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "You can only transfer your own agents")
+	assert.Equal(t, "user-1", implementer.UserID)
+	assert.Equal(t, "user-1", workflow.UserID)
+	assert.Equal(t, "user-1", loop.UserID)
+	env.store.AssertNotCalled(t, "SaveAgent", mock.Anything)
+	env.store.AssertNotCalled(t, "RemoveAgentFromUser", mock.Anything, mock.Anything)
+	env.store.AssertNotCalled(t, "SaveWorkflow", mock.Anything)
+	env.store.AssertNotCalled(t, "SaveReviewLoop", mock.Anything)
+}
+
+func TestTransfer_ChannelAdminAllowed(t *testing.T) {
+	env, implementer, _, _, _ := setupTransferTest(t)
+
+	env.api.On("HasPermissionToChannel", "admin-1", "ch-1", model.PermissionManageChannelRoles).Return(true)
+	env.store.On("SaveAgent", mock.Anything).Return(nil)
+	env.store.On("RemoveAgentFromUser", "user-1", mock.Anything).Return(nil)
+	env.store.On("SaveWorkflow", mock.Anything).Return(nil)
+	env.store.On("SaveReviewLoop", mock.Anything).Return(nil)
+	env.api.On("CreatePost", mock.MatchedBy(func(p *model.Post) bool {
+		return strings.Contains(p.Message, "by @carol")
+	})).Return(&model.Post{Id: "note-1"}, nil)
+
+	resp, err := env.handler.Handle(&model.CommandArgs{
+		Command:   "/cursor transfer impl-1 @bob",
+		UserId:    "admin-1",
+		ChannelId: "ch-1",
+	})
+
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "now belongs to @bob")
+	assert.Equal(t, "user-2", implementer.UserID)
+}
+
+func TestTransfer_RejectsNonChannelMember(t *testing.T) {
+	env, implementer, _, workflow, loop := setupTransferTest(t)
+	env.api.On("GetUserByUsername", "dave").Return(&model.User{Id: "user-4", Username: "dave"}, nil)
+	env.api.On("GetChannelMember", "ch-1", "user-4").Return(nil, model.NewAppError("GetChannelMember", "app.channel.get_member.missing.app_error", nil, "", http.StatusNotFound))
+
+	resp, err := env.handler.Handle(&model.CommandArgs{
+		Command:   "/cursor transfer impl-1 @dave",
+		UserId:    "user-1",
+		ChannelId: "ch-1",
+	})
+
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "`@dave` is not a member of the agent's channel")
+	assert.Equal(t, "user-1", implementer.UserID)
+	assert.Equal(t, "user-1", workflow.UserID)
+	assert.Equal(t, "user-1", loop.UserID)
+	env.store.AssertNotCalled(t, "SaveAgent", mock.Anything)
+	env.store.AssertNotCalled(t, "SaveWorkflow", mock.Anything)
+	env.store.AssertNotCalled(t, "SaveReviewLoop", mock.Anything)
+}
+
+func TestTransfer_RejectsBotAndCurrentOwner(t *testing.T) {
+	env, _, _, _, _ := setupTransferTest(t)
+	env.api.On("GetUserByUsername", "cursor").Return(&model.User{Id: "bot-user-id", Username: "cursor", IsBot: true}, nil)
+	env.api.On("GetUserByUsername", "alice").Return(&model.User{Id: "user-1", Username: "alice"}, nil)
+
+	resp, err := env.handler.Handle(&model.CommandArgs{Command: "/cursor transfer impl-1 @cursor", UserId: "user-1"})
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "cannot own agents")
+
+	resp, err = env.handler.Handle(&model.CommandArgs{Command: "/cursor transfer impl-1 @alice", UserId: "user-1"})
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "already owns agent")
+
+	env.store.AssertNotCalled(t, "SaveAgent", mock.Anything)
+}
+
 func TestSettings_OpensDialog(t *testing.T) {
 	env := setupTest(t)
 
@@ -598,6 +903,118 @@ func TestSettings_OpensDialog(t *testing.T) {
 	env.api.AssertCalled(t, "OpenInteractiveDialog", mock.Anything)
 }
 
+func TestSettings_ChannelBotSelectWithBotIdentities(t *testing.T) {
+	env := setupTest(t)
+	env.handler.(*Handler).deps.BotUsernamesFn = func() []string { return []string{"cursor-backend", "cursor-frontend"} }
+
+	env.store.On("GetChannelSettings", "ch-1").Return(&kvstore.ChannelSettings{BotUsername: "cursor-frontend"}, nil)
+	env.store.On("GetUserSettings", "user-1").Return(nil, nil)
+
+	var dialog model.OpenDialogRequest
+	env.api.On("OpenInteractiveDialog", mock.Anything).Run(func(args mock.Arguments) {
+		dialog = args.Get(0).(model.OpenDialogRequest)
+	}).Return(nil)
+
+	_, err := env.handler.Handle(&model.CommandArgs{
+		Command:   "/cursor settings",
+		ChannelId: "ch-1",
+		UserId:    "user-1",
+		TriggerId: "trigger-abc",
+	})
+	require.NoError(t, err)
+
+	require.Greater(t, len(dialog.Dialog.Elements), 2)
+	element := dialog.Dialog.Elements[2]
+	assert.Equal(t, "channel_bot_username", element.Name)
+	assert.Equal(t, "select", element.Type)
+	assert.Equal(t, "cursor-frontend", element.Default)
+	require.Len(t, element.Options, 2)
+	assert.Equal(t, "@cursor-backend", element.Options[0].Text)
+}
+
+func TestSettings_NoChannelBotSelectWithoutBotIdentities(t *testing.T) {
+	env := setupTest(t)
+
+	env.store.On("GetChannelSettings", "ch-1").Return(nil, nil)
+	env.store.On("GetUserSettings", "user-1").Return(nil, nil)
+
+	var dialog model.OpenDialogRequest
+	env.api.On("OpenInteractiveDialog", mock.Anything).Run(func(args mock.Arguments) {
+		dialog = args.Get(0).(model.OpenDialogRequest)
+	}).Return(nil)
+
+	_, err := env.handler.Handle(&model.CommandArgs{
+		Command:   "/cursor settings",
+		ChannelId: "ch-1",
+		UserId:    "user-1",
+		TriggerId: "trigger-abc",
+	})
+	require.NoError(t, err)
+
+	for _, element := range dialog.Dialog.Elements {
+		assert.NotEqual(t, "channel_bot_username", element.Name)
+	}
+}
+
+func TestSettingsReset_ClearsUserSettings(t *testing.T) {
+	env := setupTest(t)
+
+	env.store.On("GetUserSettings", "user-1").Return(nil, nil)
+	env.store.On("DeleteUserSettings", "user-1").Return(nil)
+
+	resp, err := env.handler.Handle(&model.CommandArgs{
+		Command:   "/cursor settings reset",
+		ChannelId: "ch-1",
+		UserId:    "user-1",
+	})
+
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "have been reset")
+	env.store.AssertCalled(t, "DeleteUserSettings", "user-1")
+	env.store.AssertNotCalled(t, "SaveChannelSettings", mock.Anything, mock.Anything)
+	env.api.AssertNotCalled(t, "OpenInteractiveDialog", mock.Anything)
+}
+
+func TestSettingsReset_PreservesAliases(t *testing.T) {
+	env := setupTest(t)
+
+	env.store.On("GetUserSettings", "user-1").Return(&kvstore.UserSettings{
+		DefaultRepository: "org/repo",
+		DefaultBranch:     "develop",
+		DefaultModel:      "gpt-4o",
+		Aliases:           map[string]string{"fe": "repo=org/webapp"},
+	}, nil)
+	env.store.On("SaveUserSettings", "user-1", &kvstore.UserSettings{
+		Aliases: map[string]string{"fe": "repo=org/webapp"},
+	}).Return(nil)
+
+	resp, err := env.handler.Handle(&model.CommandArgs{
+		Command:   "/cursor settings reset",
+		ChannelId: "ch-1",
+		UserId:    "user-1",
+	})
+
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "have been reset")
+	env.store.AssertExpectations(t)
+	env.store.AssertNotCalled(t, "DeleteUserSettings", mock.Anything)
+}
+
+func TestSettingsReset_StoreError(t *testing.T) {
+	env := setupTest(t)
+
+	env.store.On("GetUserSettings", "user-1").Return(nil, nil)
+	env.store.On("DeleteUserSettings", "user-1").Return(fmt.Errorf("kv unavailable"))
+
+	resp, err := env.handler.Handle(&model.CommandArgs{
+		Command: "/cursor settings reset",
+		UserId:  "user-1",
+	})
+
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "Failed to reset your settings")
+}
+
 func TestModels_Success(t *testing.T) {
 	env := setupTest(t)
 
@@ -616,6 +1033,24 @@ func TestModels_Success(t *testing.T) {
 	assert.Contains(t, resp.Text, "gpt-4o")
 }
 
+func TestModels_FiltersByAllowlist(t *testing.T) {
+	env := setupTest(t)
+	env.handler.(*Handler).deps.AllowedModelsFn = func() []string { return []string{"claude-sonnet"} }
+
+	env.cursorClient.On("ListModels", mock.Anything).Return(&cursor.ListModelsResponse{
+		Models: []string{"auto", "claude-sonnet", "o3"},
+	}, nil)
+
+	resp, err := env.handler.Handle(&model.CommandArgs{
+		Command: "/cursor models",
+	})
+
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "`claude-sonnet`")
+	assert.NotContains(t, resp.Text, "`o3`")
+	assert.NotContains(t, resp.Text, "`auto`")
+}
+
 func TestModels_APIError(t *testing.T) {
 	env := setupTest(t)
 
@@ -644,27 +1079,96 @@ func TestModels_Empty(t *testing.T) {
 	assert.Contains(t, resp.Text, "No models available")
 }
 
-func TestLaunch_NoPrompt(t *testing.T) {
+func TestWhoami_Success(t *testing.T) {
 	env := setupTest(t)
+	env.handler.(*Handler).deps.IntegrationStatusFn = func() IntegrationStatus {
+		return IntegrationStatus{GitHubPATConfigured: true, ReviewLoopEnabled: true}
+	}
+
+	env.cursorClient.On("GetMe", mock.Anything).Return(&cursor.APIKeyInfo{
+		APIKeyName: "mattermost-prod",
+		CreatedAt:  "2025-03-14T09:26:53Z",
+		UserEmail:  "dev@example.com",
+	}, nil)
 
 	resp, err := env.handler.Handle(&model.CommandArgs{
-		Command: "/cursor ",
+		Command: "/cursor whoami",
 	})
 
 	require.NoError(t, err)
-	// Empty after trimming falls through to help
-	assert.Contains(t, resp.Text, "Cursor Background Agents - Help")
+	assert.Equal(t, model.CommandResponseTypeEphemeral, resp.ResponseType)
+	assert.Contains(t, resp.Text, "- Account: `dev@example.com`")
+	assert.Contains(t, resp.Text, "- API key: `mattermost-prod`")
+	assert.Contains(t, resp.Text, "- Key created: Mar 14, 2025")
+	assert.Contains(t, resp.Text, "- GitHub token: :white_check_mark: configured")
+	assert.Contains(t, resp.Text, "- GitHub webhook secret: :x: not configured")
+	assert.Contains(t, resp.Text, "- AI review loop: :white_check_mark: enabled")
 }
 
-func TestLaunch_NoRepo(t *testing.T) {
+func TestWhoami_APIError(t *testing.T) {
 	env := setupTest(t)
 
-	env.store.On("GetChannelSettings", "ch-1").Return(nil, nil)
-	env.store.On("GetUserSettings", "user-1").Return(nil, nil)
+	env.cursorClient.On("GetMe", mock.Anything).Return(nil, &cursor.APIError{StatusCode: 401, Message: "invalid API key"})
 
 	resp, err := env.handler.Handle(&model.CommandArgs{
-		Command:   "/cursor fix bug",
-		ChannelId: "ch-1",
+		Command: "/cursor whoami",
+	})
+
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "Failed to fetch the Cursor API key details")
+	assert.Contains(t, resp.Text, "invalid API key")
+	assert.NotContains(t, resp.Text, "Integrations")
+}
+
+func TestWhoami_RateLimited(t *testing.T) {
+	env := setupTest(t)
+
+	env.cursorClient.On("GetMe", mock.Anything).Return(nil, &cursor.APIError{StatusCode: 429, Message: "slow down"})
+
+	resp, err := env.handler.Handle(&model.CommandArgs{
+		Command: "/cursor whoami",
+	})
+
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "rate limiting this API key")
+}
+
+func TestWhoami_WithPromptLaunches(t *testing.T) {
+	env := setupTest(t)
+
+	env.store.On("GetChannelSettings", mock.Anything).Return(nil, nil)
+	env.store.On("GetUserSettings", mock.Anything).Return(nil, nil)
+
+	resp, err := env.handler.Handle(&model.CommandArgs{
+		Command: "/cursor whoami owns the billing module",
+	})
+
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "No repository specified")
+	env.cursorClient.AssertNotCalled(t, "GetMe", mock.Anything)
+}
+
+func TestLaunch_NoPrompt(t *testing.T) {
+	env := setupTest(t)
+
+	resp, err := env.handler.Handle(&model.CommandArgs{
+		Command: "/cursor ",
+	})
+
+	require.NoError(t, err)
+	// Empty after trimming falls through to help
+	assert.Contains(t, resp.Text, "Cursor Background Agents - Help")
+}
+
+func TestLaunch_NoRepo(t *testing.T) {
+	env := setupTest(t)
+
+	env.store.On("GetChannelSettings", "ch-1").Return(nil, nil)
+	env.store.On("GetUserSettings", "user-1").Return(nil, nil)
+
+	resp, err := env.handler.Handle(&model.CommandArgs{
+		Command:   "/cursor fix bug",
+		ChannelId: "ch-1",
 		UserId:    "user-1",
 	})
 
@@ -672,6 +1176,92 @@ func TestLaunch_NoRepo(t *testing.T) {
 	assert.Contains(t, resp.Text, "No repository specified")
 }
 
+func TestLaunch_ChannelLinkedRepoFallback(t *testing.T) {
+	env := setupTest(t)
+	env.handler.(*Handler).deps.ChannelLinkedRepoFn = func(channelID string) string {
+		if channelID == "ch-1" {
+			return "org/linked"
+		}
+		return ""
+	}
+
+	env.store.On("GetChannelSettings", "ch-1").Return(nil, nil)
+	env.store.On("GetUserSettings", "user-1").Return(nil, nil)
+
+	env.cursorClient.On("LaunchAgent", mock.Anything, mock.MatchedBy(func(req cursor.LaunchAgentRequest) bool {
+		return req.Source.Repository == "https://github.com/org/linked"
+	})).Return(&cursor.Agent{ID: "new-agent", Status: cursor.AgentStatusCreating}, nil).Once()
+	env.api.On("CreatePost", mock.Anything).Return(&model.Post{Id: "bot-post-1"}, nil)
+	env.api.On("AddReaction", mock.Anything).Return(&model.Reaction{}, nil)
+	env.store.On("SaveAgent", mock.MatchedBy(func(r *kvstore.AgentRecord) bool {
+		return r.Repository == "org/linked"
+	})).Return(nil).Once()
+	env.store.On("SetThreadAgent", mock.Anything, "new-agent").Return(nil)
+	env.store.On("AddUserLaunchHistoryEntry", "user-1", mock.Anything, LaunchHistorySize).Return(nil)
+
+	resp, err := env.handler.Handle(&model.CommandArgs{
+		Command:   "/cursor fix bug",
+		ChannelId: "ch-1",
+		UserId:    "user-1",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "", resp.Text)
+	env.cursorClient.AssertExpectations(t)
+	env.store.AssertExpectations(t)
+}
+
+func TestLaunch_ChannelSettingsRepoBeatsLinkedRepo(t *testing.T) {
+	env := setupTest(t)
+	linkedCalled := false
+	env.handler.(*Handler).deps.ChannelLinkedRepoFn = func(string) string {
+		linkedCalled = true
+		return "org/linked"
+	}
+
+	env.store.On("GetChannelSettings", "ch-1").Return(&kvstore.ChannelSettings{
+		DefaultRepository: "org/repo",
+	}, nil)
+	env.store.On("GetUserSettings", "user-1").Return(nil, nil)
+
+	env.cursorClient.On("LaunchAgent", mock.Anything, mock.MatchedBy(func(req cursor.LaunchAgentRequest) bool {
+		return req.Source.Repository == "https://github.com/org/repo"
+	})).Return(&cursor.Agent{ID: "new-agent", Status: cursor.AgentStatusCreating}, nil).Once()
+	env.api.On("CreatePost", mock.Anything).Return(&model.Post{Id: "bot-post-1"}, nil)
+	env.api.On("AddReaction", mock.Anything).Return(&model.Reaction{}, nil)
+	env.store.On("SaveAgent", mock.Anything).Return(nil)
+	env.store.On("SetThreadAgent", mock.Anything, "new-agent").Return(nil)
+	env.store.On("AddUserLaunchHistoryEntry", "user-1", mock.Anything, LaunchHistorySize).Return(nil)
+
+	_, err := env.handler.Handle(&model.CommandArgs{
+		Command:   "/cursor fix bug",
+		ChannelId: "ch-1",
+		UserId:    "user-1",
+	})
+
+	require.NoError(t, err)
+	env.cursorClient.AssertExpectations(t)
+	assert.False(t, linkedCalled)
+}
+
+func TestLaunch_InvalidRepo(t *testing.T) {
+	env := setupTest(t)
+
+	env.store.On("GetChannelSettings", "ch-1").Return(nil, nil)
+	env.store.On("GetUserSettings", "user-1").Return(nil, nil)
+
+	resp, err := env.handler.Handle(&model.CommandArgs{
+		Command:   "/cursor repo=org/repo/ fix bug",
+		ChannelId: "ch-1",
+		UserId:    "user-1",
+	})
+
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "Invalid repository `org/repo/`")
+	assert.Contains(t, resp.Text, "must not end with a slash")
+	env.cursorClient.AssertNotCalled(t, "LaunchAgent", mock.Anything, mock.Anything)
+}
+
 func TestLaunch_Success(t *testing.T) {
 	env := setupTest(t)
 
@@ -713,6 +1303,11 @@ func TestLaunch_Success(t *testing.T) {
 	// Set thread mapping
 	env.store.On("SetThreadAgent", mock.Anything, "new-agent").Return(nil)
 
+	// Record the launch in the user's history
+	env.store.On("AddUserLaunchHistoryEntry", "user-1", mock.MatchedBy(func(entry kvstore.LaunchHistoryEntry) bool {
+		return entry.Prompt == "fix bug" && entry.Repository == "org/repo" && entry.Branch == "main" && entry.Model == "auto"
+	}), LaunchHistorySize).Return(nil).Once()
+
 	resp, err := env.handler.Handle(&model.CommandArgs{
 		Command:   "/cursor fix bug",
 		ChannelId: "ch-1",
@@ -722,42 +1317,84 @@ func TestLaunch_Success(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "", resp.Text) // No ephemeral text on success
 	env.cursorClient.AssertCalled(t, "LaunchAgent", mock.Anything, mock.Anything)
+	env.store.AssertExpectations(t)
 }
 
-func TestLaunch_WithInlineOptions(t *testing.T) {
+func TestRecent_ListsEntries(t *testing.T) {
 	env := setupTest(t)
 
-	env.store.On("GetChannelSettings", "ch-1").Return(nil, nil)
-	env.store.On("GetUserSettings", "user-1").Return(nil, nil)
-
-	env.cursorClient.On("LaunchAgent", mock.Anything, mock.MatchedBy(func(req cursor.LaunchAgentRequest) bool {
-		return req.Source.Repository == "https://github.com/custom/repo" &&
-			req.Source.Ref == "dev"
-	})).Return(&cursor.Agent{
-		ID:     "agent-opts",
-		Status: cursor.AgentStatusCreating,
+	env.store.On("GetUserLaunchHistory", "user-1").Return(&kvstore.UserLaunchHistory{
+		Entries: []kvstore.LaunchHistoryEntry{
+			{Prompt: "add rate limiting", Repository: "org/api", Branch: "develop", Model: "claude-sonnet"},
+			{Prompt: "fix bug", Repository: "org/repo", Branch: "main", Model: "auto"},
+		},
 	}, nil)
 
-	env.api.On("CreatePost", mock.MatchedBy(func(p *model.Post) bool {
-		p.Id = "bot-post-opts"
-		return true
-	})).Return(&model.Post{Id: "bot-post-opts"}, nil)
+	resp, err := env.handler.Handle(&model.CommandArgs{
+		Command: "/cursor recent",
+		UserId:  "user-1",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, model.CommandResponseTypeEphemeral, resp.ResponseType)
+	assert.Contains(t, resp.Text, "1. `org/api@develop` with `claude-sonnet`: add rate limiting")
+	assert.Contains(t, resp.Text, "2. `org/repo@main` with `auto`: fix bug")
+	assert.Contains(t, resp.Text, "/cursor recent run <n>")
+}
+
+func TestRecent_Empty(t *testing.T) {
+	env := setupTest(t)
+
+	env.store.On("GetUserLaunchHistory", "user-1").Return(&kvstore.UserLaunchHistory{}, nil)
+
+	resp, err := env.handler.Handle(&model.CommandArgs{
+		Command: "/cursor recent",
+		UserId:  "user-1",
+	})
+
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "You have no recent launches")
+}
+
+func TestRecent_RunRelaunchesEntry(t *testing.T) {
+	env := setupTest(t)
+
+	env.store.On("GetUserLaunchHistory", "user-1").Return(&kvstore.UserLaunchHistory{
+		Entries: []kvstore.LaunchHistoryEntry{
+			{Prompt: "add rate limiting", Repository: "org/api", Branch: "develop", Model: "claude-sonnet"},
+			{Prompt: "fix bug", Repository: "org/repo", Branch: "main", Model: "auto"},
+		},
+	}, nil)
+	env.cursorClient.On("LaunchAgent", mock.Anything, mock.MatchedBy(func(req cursor.LaunchAgentRequest) bool {
+		return req.Prompt.Text == "fix bug" &&
+			req.Source.Repository == "https://github.com/org/repo" &&
+			req.Source.Ref == "main" &&
+			req.Model == "auto" &&
+			req.Target.AutoCreatePr
+	})).Return(&cursor.Agent{ID: "new-agent", Status: cursor.AgentStatusCreating}, nil).Once()
+	env.api.On("CreatePost", mock.Anything).Return(&model.Post{Id: "bot-post-1"}, nil)
 	env.api.On("AddReaction", mock.Anything).Return(&model.Reaction{}, nil)
-	env.store.On("SaveAgent", mock.Anything).Return(nil)
-	env.store.On("SetThreadAgent", mock.Anything, "agent-opts").Return(nil)
+	env.store.On("SaveAgent", mock.MatchedBy(func(r *kvstore.AgentRecord) bool {
+		return r.CursorAgentID == "new-agent" && r.Prompt == "fix bug" && r.Repository == "org/repo"
+	})).Return(nil)
+	env.store.On("SetThreadAgent", mock.Anything, "new-agent").Return(nil)
+	env.store.On("AddUserLaunchHistoryEntry", "user-1", mock.MatchedBy(func(entry kvstore.LaunchHistoryEntry) bool {
+		return entry.Prompt == "fix bug" && entry.Repository == "org/repo"
+	}), LaunchHistorySize).Return(nil).Once()
 
 	resp, err := env.handler.Handle(&model.CommandArgs{
-		Command:   "/cursor repo=custom/repo branch=dev fix bug",
+		Command:   "/cursor recent run 2",
 		ChannelId: "ch-1",
 		UserId:    "user-1",
 	})
 
 	require.NoError(t, err)
 	assert.Equal(t, "", resp.Text)
-	env.cursorClient.AssertCalled(t, "LaunchAgent", mock.Anything, mock.Anything)
+	env.cursorClient.AssertExpectations(t)
+	env.store.AssertExpectations(t)
 }
 
-func TestUnknownFallsToLaunch(t *testing.T) {
+func TestLaunch_RefStartsFromRef(t *testing.T) {
 	env := setupTest(t)
 
 	env.store.On("GetChannelSettings", "ch-1").Return(&kvstore.ChannelSettings{
@@ -766,140 +1403,411 @@ func TestUnknownFallsToLaunch(t *testing.T) {
 	env.store.On("GetUserSettings", "user-1").Return(nil, nil)
 
 	env.cursorClient.On("LaunchAgent", mock.Anything, mock.MatchedBy(func(req cursor.LaunchAgentRequest) bool {
-		return req.Prompt.Text == "fix the login bug"
-	})).Return(&cursor.Agent{
-		ID:     "agent-unknown",
-		Status: cursor.AgentStatusCreating,
-	}, nil)
-
-	env.api.On("CreatePost", mock.MatchedBy(func(p *model.Post) bool {
-		p.Id = "bot-post-unknown"
-		return true
-	})).Return(&model.Post{Id: "bot-post-unknown"}, nil)
+		return req.Source.Ref == "v1.2.3" && req.Prompt.Text == "fix bug"
+	})).Return(&cursor.Agent{ID: "new-agent", Status: cursor.AgentStatusCreating}, nil).Once()
+	env.api.On("CreatePost", mock.Anything).Return(&model.Post{Id: "bot-post-1"}, nil)
 	env.api.On("AddReaction", mock.Anything).Return(&model.Reaction{}, nil)
-	env.store.On("SaveAgent", mock.Anything).Return(nil)
-	env.store.On("SetThreadAgent", mock.Anything, "agent-unknown").Return(nil)
+	env.store.On("SaveAgent", mock.MatchedBy(func(r *kvstore.AgentRecord) bool {
+		return r.CursorAgentID == "new-agent" && r.Branch == "main" && r.SourceRef == "v1.2.3"
+	})).Return(nil).Once()
+	env.store.On("SetThreadAgent", mock.Anything, "new-agent").Return(nil)
+	env.store.On("AddUserLaunchHistoryEntry", "user-1", mock.Anything, LaunchHistorySize).Return(nil)
 
 	resp, err := env.handler.Handle(&model.CommandArgs{
-		Command:   "/cursor fix the login bug",
+		Command:   "/cursor ref=v1.2.3 fix bug",
 		ChannelId: "ch-1",
 		UserId:    "user-1",
 	})
 
 	require.NoError(t, err)
 	assert.Equal(t, "", resp.Text)
-	env.cursorClient.AssertCalled(t, "LaunchAgent", mock.Anything, mock.Anything)
+	env.cursorClient.AssertExpectations(t)
+	env.store.AssertExpectations(t)
 }
 
-func TestCancel_NotFound(t *testing.T) {
+func TestRecent_RunReplaysLaunchOptions(t *testing.T) {
 	env := setupTest(t)
 
-	env.store.On("GetWorkflow", "nonexistent").Return(nil, nil)
-	env.store.On("GetAgent", "nonexistent").Return(nil, nil)
+	env.store.On("GetChannelSettings", "ch-1").Return(nil, nil)
+	env.store.On("GetUserSettings", "user-1").Return(nil, nil)
 
-	resp, err := env.handler.Handle(&model.CommandArgs{
-		Command: "/cursor cancel nonexistent",
-		UserId:  "user-1",
-	})
+	var recorded kvstore.LaunchHistoryEntry
+	launch := func(command string) {
+		resp, err := env.handler.Handle(&model.CommandArgs{
+			Command:   command,
+			ChannelId: "ch-1",
+			UserId:    "user-1",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "", resp.Text)
+	}
+	isReplay := func(req cursor.LaunchAgentRequest) bool {
+		return req.Prompt.Text == "fix bug" &&
+			req.Source.Ref == "v1.2.3" &&
+			req.Target.BaseBranch == "release" &&
+			!req.Target.AutoCreatePr &&
+			req.Target.Draft
+	}
 
-	require.NoError(t, err)
-	assert.Contains(t, resp.Text, "not found")
+	env.cursorClient.On("LaunchAgent", mock.Anything, mock.MatchedBy(isReplay)).
+		Return(&cursor.Agent{ID: "new-agent", Status: cursor.AgentStatusCreating}, nil).Twice()
+	env.api.On("CreatePost", mock.Anything).Return(&model.Post{Id: "bot-post-1"}, nil)
+	env.api.On("AddReaction", mock.Anything).Return(&model.Reaction{}, nil)
+	env.store.On("SaveAgent", mock.Anything).Return(nil)
+	env.store.On("SetThreadAgent", mock.Anything, "new-agent").Return(nil)
+	env.store.On("AddUserLaunchHistoryEntry", "user-1", mock.Anything, LaunchHistorySize).Run(func(args mock.Arguments) {
+		recorded = args.Get(1).(kvstore.LaunchHistoryEntry)
+	}).Return(nil)
+
+	launch("/cursor repo=org/repo ref=v1.2.3 base=release autopr=false draft=true fix bug")
+	require.NotNil(t, recorded.AutoCreatePR)
+	assert.False(t, *recorded.AutoCreatePR)
+	assert.Equal(t, "v1.2.3", recorded.SourceRef)
+	assert.Equal(t, "release", recorded.BaseBranch)
+	assert.True(t, recorded.Draft)
+
+	env.store.On("GetUserLaunchHistory", "user-1").Return(&kvstore.UserLaunchHistory{
+		Entries: []kvstore.LaunchHistoryEntry{recorded},
+	}, nil)
+	launch("/cursor recent run 1")
+
+	env.cursorClient.AssertExpectations(t)
 }
 
-func TestCancel_StopAPIError(t *testing.T) {
+func TestRecent_RunOutOfRange(t *testing.T) {
 	env := setupTest(t)
 
-	env.store.On("GetWorkflow", "abc").Return(nil, nil)
-	env.store.On("GetWorkflowByAgent", "abc").Return("", nil)
-	env.store.On("GetAgent", "abc").Return(&kvstore.AgentRecord{
-		CursorAgentID: "abc",
-		UserID:        "user-1",
-		Status:        "RUNNING",
+	env.store.On("GetUserLaunchHistory", "user-1").Return(&kvstore.UserLaunchHistory{
+		Entries: []kvstore.LaunchHistoryEntry{{Prompt: "fix bug", Repository: "org/repo", Branch: "main", Model: "auto"}},
 	}, nil)
 
-	env.cursorClient.On("StopAgent", mock.Anything, "abc").Return(nil, fmt.Errorf("API down"))
-
 	resp, err := env.handler.Handle(&model.CommandArgs{
-		Command: "/cursor cancel abc",
+		Command: "/cursor recent run 3",
 		UserId:  "user-1",
 	})
 
 	require.NoError(t, err)
-	assert.Contains(t, resp.Text, "Failed to cancel agent")
+	assert.Contains(t, resp.Text, "Choose an entry between 1 and 1")
+	env.cursorClient.AssertNotCalled(t, "LaunchAgent", mock.Anything, mock.Anything)
 }
 
-func TestCancel_AlreadyFailed(t *testing.T) {
+func TestLaunch_DisallowedModel(t *testing.T) {
 	env := setupTest(t)
+	env.handler.(*Handler).deps.AllowedModelsFn = func() []string { return []string{"auto", "claude-sonnet"} }
 
-	env.store.On("GetWorkflow", "abc").Return(nil, nil)
-	env.store.On("GetWorkflowByAgent", "abc").Return("", nil)
-	env.store.On("GetAgent", "abc").Return(&kvstore.AgentRecord{
-		CursorAgentID: "abc",
-		UserID:        "user-1",
-		Status:        "FAILED",
+	env.store.On("GetChannelSettings", "ch-1").Return(&kvstore.ChannelSettings{
+		DefaultRepository: "org/repo",
 	}, nil)
+	env.store.On("GetUserSettings", "user-1").Return(nil, nil)
 
 	resp, err := env.handler.Handle(&model.CommandArgs{
-		Command: "/cursor cancel abc",
-		UserId:  "user-1",
+		Command:   "/cursor model=o3 fix bug",
+		ChannelId: "ch-1",
+		UserId:    "user-1",
 	})
 
 	require.NoError(t, err)
-	assert.Contains(t, resp.Text, "already FAILED")
+	assert.Equal(t, model.CommandResponseTypeEphemeral, resp.ResponseType)
+	assert.Contains(t, resp.Text, "Model `o3` is not allowed")
+	assert.Contains(t, resp.Text, "`auto`, `claude-sonnet`")
+	env.cursorClient.AssertNotCalled(t, "LaunchAgent", mock.Anything, mock.Anything)
 }
 
-func TestCancel_AlreadyStopped(t *testing.T) {
+func TestLaunch_MissingBaseBranch(t *testing.T) {
 	env := setupTest(t)
+	var checked []string
+	env.handler.(*Handler).deps.BaseBranchMissingFn = func(repo, base string) bool {
+		checked = append(checked, repo+"@"+base)
+		return true
+	}
 
-	env.store.On("GetWorkflow", "abc").Return(nil, nil)
-	env.store.On("GetWorkflowByAgent", "abc").Return("", nil)
-	env.store.On("GetAgent", "abc").Return(&kvstore.AgentRecord{
-		CursorAgentID: "abc",
-		UserID:        "user-1",
-		Status:        "STOPPED",
+	env.store.On("GetChannelSettings", "ch-1").Return(&kvstore.ChannelSettings{
+		DefaultRepository: "org/repo",
 	}, nil)
+	env.store.On("GetUserSettings", "user-1").Return(nil, nil)
 
 	resp, err := env.handler.Handle(&model.CommandArgs{
-		Command: "/cursor cancel abc",
-		UserId:  "user-1",
+		Command:   "/cursor base=relase fix bug",
+		ChannelId: "ch-1",
+		UserId:    "user-1",
 	})
 
 	require.NoError(t, err)
-	assert.Contains(t, resp.Text, "already STOPPED")
+	assert.Equal(t, model.CommandResponseTypeEphemeral, resp.ResponseType)
+	assert.Contains(t, resp.Text, "Base branch `relase` was not found in `org/repo`")
+	assert.Equal(t, []string{"org/repo@relase"}, checked)
+	env.cursorClient.AssertNotCalled(t, "LaunchAgent", mock.Anything, mock.Anything)
 }
 
-func TestLaunch_APIError(t *testing.T) {
+func TestLaunch_AllowedModel(t *testing.T) {
 	env := setupTest(t)
+	env.handler.(*Handler).deps.AllowedModelsFn = func() []string { return []string{"auto", "claude-sonnet"} }
 
 	env.store.On("GetChannelSettings", "ch-1").Return(&kvstore.ChannelSettings{
 		DefaultRepository: "org/repo",
 	}, nil)
 	env.store.On("GetUserSettings", "user-1").Return(nil, nil)
 
-	env.cursorClient.On("LaunchAgent", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("server error"))
+	env.cursorClient.On("LaunchAgent", mock.Anything, mock.MatchedBy(func(req cursor.LaunchAgentRequest) bool {
+		return req.Model == "Claude-Sonnet"
+	})).Return(&cursor.Agent{ID: "new-agent", Status: cursor.AgentStatusCreating}, nil)
+	env.api.On("CreatePost", mock.MatchedBy(func(p *model.Post) bool {
+		p.Id = "bot-post-1"
+		return true
+	})).Return(&model.Post{Id: "bot-post-1"}, nil)
+	env.api.On("AddReaction", mock.Anything).Return(&model.Reaction{}, nil)
+	env.store.On("SaveAgent", mock.Anything).Return(nil)
+	env.store.On("SetThreadAgent", mock.Anything, "new-agent").Return(nil)
+	env.store.On("AddUserLaunchHistoryEntry", "user-1", mock.Anything, LaunchHistorySize).Return(nil)
 
 	resp, err := env.handler.Handle(&model.CommandArgs{
-		Command:   "/cursor fix something",
+		Command:   "/cursor model=Claude-Sonnet fix bug",
 		ChannelId: "ch-1",
 		UserId:    "user-1",
 	})
 
 	require.NoError(t, err)
-	assert.Contains(t, resp.Text, "Failed to launch agent")
+	assert.Equal(t, "", resp.Text)
+	env.cursorClient.AssertExpectations(t)
 }
 
-func TestList_StoreError(t *testing.T) {
+func TestLaunch_NotPermittedLauncher(t *testing.T) {
 	env := setupTest(t)
+	env.handler.(*Handler).deps.CanLaunchFn = func(userID, channelID string) bool {
+		return userID != "user-1" || channelID != "ch-1"
+	}
 
-	env.store.On("GetAgentsByUser", "user-1").Return(nil, fmt.Errorf("store error"))
+	env.store.On("GetChannelSettings", "ch-1").Return(&kvstore.ChannelSettings{
+		DefaultRepository: "org/repo",
+	}, nil)
+	env.store.On("GetUserSettings", "user-1").Return(nil, nil)
 
 	resp, err := env.handler.Handle(&model.CommandArgs{
-		Command: "/cursor list",
-		UserId:  "user-1",
+		Command:   "/cursor fix bug",
+		ChannelId: "ch-1",
+		UserId:    "user-1",
 	})
 
 	require.NoError(t, err)
-	assert.Contains(t, resp.Text, "Failed to retrieve agents")
+	assert.Equal(t, model.CommandResponseTypeEphemeral, resp.ResponseType)
+	assert.Equal(t, LaunchNotPermittedMessage, resp.Text)
+	env.cursorClient.AssertNotCalled(t, "LaunchAgent", mock.Anything, mock.Anything)
+}
+
+func TestSettings_AllowedLaunchersFieldForAdmins(t *testing.T) {
+	env := setupTest(t)
+	env.handler.(*Handler).deps.CanManageLaunchersFn = func(userID, _ string) bool { return userID == "admin-1" }
+
+	env.store.On("GetChannelSettings", "ch-1").Return(&kvstore.ChannelSettings{
+		AllowedLauncherUserIDs: []string{"user-2"},
+	}, nil)
+	env.store.On("GetUserSettings", mock.Anything).Return(nil, nil)
+	env.api.On("GetUser", "user-2").Return(&model.User{Id: "user-2", Username: "bob"}, nil)
+
+	var elements []model.DialogElement
+	env.api.On("OpenInteractiveDialog", mock.MatchedBy(func(d model.OpenDialogRequest) bool {
+		elements = d.Dialog.Elements
+		return true
+	})).Return(nil)
+
+	findLaunchers := func() *model.DialogElement {
+		for i := range elements {
+			if elements[i].Name == "channel_allowed_launchers" {
+				return &elements[i]
+			}
+		}
+		return nil
+	}
+
+	_, err := env.handler.Handle(&model.CommandArgs{Command: "/cursor settings", ChannelId: "ch-1", UserId: "admin-1", TriggerId: "t"})
+	require.NoError(t, err)
+	launchers := findLaunchers()
+	require.NotNil(t, launchers)
+	assert.Equal(t, "bob", launchers.Default)
+
+	_, err = env.handler.Handle(&model.CommandArgs{Command: "/cursor settings", ChannelId: "ch-1", UserId: "user-1", TriggerId: "t"})
+	require.NoError(t, err)
+	assert.Nil(t, findLaunchers())
+}
+
+func TestLaunch_WithInlineOptions(t *testing.T) {
+	env := setupTest(t)
+
+	env.store.On("GetChannelSettings", "ch-1").Return(nil, nil)
+	env.store.On("GetUserSettings", "user-1").Return(nil, nil)
+
+	env.cursorClient.On("LaunchAgent", mock.Anything, mock.MatchedBy(func(req cursor.LaunchAgentRequest) bool {
+		return req.Source.Repository == "https://github.com/custom/repo" &&
+			req.Source.Ref == "dev"
+	})).Return(&cursor.Agent{
+		ID:     "agent-opts",
+		Status: cursor.AgentStatusCreating,
+	}, nil)
+
+	env.api.On("CreatePost", mock.MatchedBy(func(p *model.Post) bool {
+		p.Id = "bot-post-opts"
+		return true
+	})).Return(&model.Post{Id: "bot-post-opts"}, nil)
+	env.api.On("AddReaction", mock.Anything).Return(&model.Reaction{}, nil)
+	env.store.On("SaveAgent", mock.Anything).Return(nil)
+	env.store.On("SetThreadAgent", mock.Anything, "agent-opts").Return(nil)
+	env.store.On("AddUserLaunchHistoryEntry", mock.Anything, mock.Anything, LaunchHistorySize).Return(nil)
+
+	resp, err := env.handler.Handle(&model.CommandArgs{
+		Command:   "/cursor repo=custom/repo branch=dev fix bug",
+		ChannelId: "ch-1",
+		UserId:    "user-1",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "", resp.Text)
+	env.cursorClient.AssertCalled(t, "LaunchAgent", mock.Anything, mock.Anything)
+}
+
+func TestUnknownFallsToLaunch(t *testing.T) {
+	env := setupTest(t)
+
+	env.store.On("GetChannelSettings", "ch-1").Return(&kvstore.ChannelSettings{
+		DefaultRepository: "org/repo",
+	}, nil)
+	env.store.On("GetUserSettings", "user-1").Return(nil, nil)
+
+	env.cursorClient.On("LaunchAgent", mock.Anything, mock.MatchedBy(func(req cursor.LaunchAgentRequest) bool {
+		return req.Prompt.Text == "fix the login bug"
+	})).Return(&cursor.Agent{
+		ID:     "agent-unknown",
+		Status: cursor.AgentStatusCreating,
+	}, nil)
+
+	env.api.On("CreatePost", mock.MatchedBy(func(p *model.Post) bool {
+		p.Id = "bot-post-unknown"
+		return true
+	})).Return(&model.Post{Id: "bot-post-unknown"}, nil)
+	env.api.On("AddReaction", mock.Anything).Return(&model.Reaction{}, nil)
+	env.store.On("SaveAgent", mock.Anything).Return(nil)
+	env.store.On("SetThreadAgent", mock.Anything, "agent-unknown").Return(nil)
+	env.store.On("AddUserLaunchHistoryEntry", mock.Anything, mock.Anything, LaunchHistorySize).Return(nil)
+
+	resp, err := env.handler.Handle(&model.CommandArgs{
+		Command:   "/cursor fix the login bug",
+		ChannelId: "ch-1",
+		UserId:    "user-1",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "", resp.Text)
+	env.cursorClient.AssertCalled(t, "LaunchAgent", mock.Anything, mock.Anything)
+}
+
+func TestCancel_NotFound(t *testing.T) {
+	env := setupTest(t)
+
+	env.store.On("GetWorkflow", "nonexistent").Return(nil, nil)
+	env.store.On("GetAgent", "nonexistent").Return(nil, nil)
+
+	resp, err := env.handler.Handle(&model.CommandArgs{
+		Command: "/cursor cancel nonexistent",
+		UserId:  "user-1",
+	})
+
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "not found")
+}
+
+func TestCancel_StopAPIError(t *testing.T) {
+	env := setupTest(t)
+
+	env.store.On("GetWorkflow", "abc").Return(nil, nil)
+	env.store.On("GetWorkflowByAgent", "abc").Return("", nil)
+	env.store.On("GetAgent", "abc").Return(&kvstore.AgentRecord{
+		CursorAgentID: "abc",
+		UserID:        "user-1",
+		Status:        "RUNNING",
+	}, nil)
+
+	env.cursorClient.On("StopAgent", mock.Anything, "abc").Return(nil, fmt.Errorf("API down"))
+
+	resp, err := env.handler.Handle(&model.CommandArgs{
+		Command: "/cursor cancel abc",
+		UserId:  "user-1",
+	})
+
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "Failed to cancel agent")
+}
+
+func TestCancel_AlreadyFailed(t *testing.T) {
+	env := setupTest(t)
+
+	env.store.On("GetWorkflow", "abc").Return(nil, nil)
+	env.store.On("GetWorkflowByAgent", "abc").Return("", nil)
+	env.store.On("GetAgent", "abc").Return(&kvstore.AgentRecord{
+		CursorAgentID: "abc",
+		UserID:        "user-1",
+		Status:        "FAILED",
+	}, nil)
+
+	resp, err := env.handler.Handle(&model.CommandArgs{
+		Command: "/cursor cancel abc",
+		UserId:  "user-1",
+	})
+
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "already FAILED")
+}
+
+func TestCancel_AlreadyStopped(t *testing.T) {
+	env := setupTest(t)
+
+	env.store.On("GetWorkflow", "abc").Return(nil, nil)
+	env.store.On("GetWorkflowByAgent", "abc").Return("", nil)
+	env.store.On("GetAgent", "abc").Return(&kvstore.AgentRecord{
+		CursorAgentID: "abc",
+		UserID:        "user-1",
+		Status:        "STOPPED",
+	}, nil)
+
+	resp, err := env.handler.Handle(&model.CommandArgs{
+		Command: "/cursor cancel abc",
+		UserId:  "user-1",
+	})
+
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "already STOPPED")
+}
+
+func TestLaunch_APIError(t *testing.T) {
+	env := setupTest(t)
+
+	env.store.On("GetChannelSettings", "ch-1").Return(&kvstore.ChannelSettings{
+		DefaultRepository: "org/repo",
+	}, nil)
+	env.store.On("GetUserSettings", "user-1").Return(nil, nil)
+
+	env.cursorClient.On("LaunchAgent", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("server error"))
+
+	resp, err := env.handler.Handle(&model.CommandArgs{
+		Command:   "/cursor fix something",
+		ChannelId: "ch-1",
+		UserId:    "user-1",
+	})
+
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "Failed to launch agent")
+}
+
+func TestList_StoreError(t *testing.T) {
+	env := setupTest(t)
+
+	env.store.On("GetAgentsByUser", "user-1").Return(nil, fmt.Errorf("store error"))
+
+	resp, err := env.handler.Handle(&model.CommandArgs{
+		Command: "/cursor list",
+		UserId:  "user-1",
+	})
+
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "Failed to retrieve agents")
 }
 
 func TestStatusToEmoji(t *testing.T) {
@@ -993,6 +1901,23 @@ func setupTestNilClient(t *testing.T) *testEnv {
 	}
 }
 
+func TestNilCursorClient_Whoami(t *testing.T) {
+	env := setupTestNilClient(t)
+	env.handler.(*Handler).deps.IntegrationStatusFn = func() IntegrationStatus {
+		return IntegrationStatus{WebhookSecretConfigured: true}
+	}
+
+	resp, err := env.handler.Handle(&model.CommandArgs{
+		Command: "/cursor whoami",
+	})
+
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "Cursor API key is not configured")
+	assert.Contains(t, resp.Text, "- GitHub token: :x: not configured")
+	assert.Contains(t, resp.Text, "- GitHub webhook secret: :white_check_mark: configured")
+	assert.Contains(t, resp.Text, "- AI review loop: disabled")
+}
+
 func TestNilCursorClient_Models(t *testing.T) {
 	env := setupTestNilClient(t)
 
@@ -1412,3 +2337,695 @@ func TestSafeUserEnablePlanLoop(t *testing.T) {
 	bFalse := false
 	assert.Equal(t, "false", safeUserEnablePlanLoop(&kvstore.UserSettings{EnablePlanLoop: &bFalse}))
 }
+
+func TestAliasSet_SavesAlias(t *testing.T) {
+	env := setupTest(t)
+
+	env.store.On("GetUserSettings", "user-1").Return(&kvstore.UserSettings{DefaultRepository: "org/default"}, nil)
+	var saved *kvstore.UserSettings
+	env.store.On("SaveUserSettings", "user-1", mock.Anything).Run(func(args mock.Arguments) {
+		saved = args.Get(1).(*kvstore.UserSettings)
+	}).Return(nil)
+
+	resp, err := env.handler.Handle(&model.CommandArgs{
+		Command: "/cursor alias set Frontend repo=org/web branch=develop model=claude-sonnet",
+		UserId:  "user-1",
+	})
+
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "Saved alias `@frontend`")
+	require.NotNil(t, saved)
+	assert.Equal(t, "org/default", saved.DefaultRepository, "existing settings must be preserved")
+	assert.Equal(t, map[string]string{"frontend": "repo=org/web branch=develop model=claude-sonnet"}, saved.Aliases)
+}
+
+func TestAliasSet_InvalidName(t *testing.T) {
+	env := setupTest(t)
+
+	resp, err := env.handler.Handle(&model.CommandArgs{
+		Command: "/cursor alias set -bad repo=org/web",
+		UserId:  "user-1",
+	})
+
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "Alias names must be")
+	env.store.AssertNotCalled(t, "SaveUserSettings", mock.Anything, mock.Anything)
+}
+
+func TestAliasSet_RejectsNonOptionText(t *testing.T) {
+	env := setupTest(t)
+
+	resp, err := env.handler.Handle(&model.CommandArgs{
+		Command: "/cursor alias set web repo=org/web fix the bug",
+		UserId:  "user-1",
+	})
+
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "Unrecognized alias options: `fix the bug`")
+	env.store.AssertNotCalled(t, "SaveUserSettings", mock.Anything, mock.Anything)
+}
+
+func TestAliasSet_MissingOptions(t *testing.T) {
+	env := setupTest(t)
+
+	resp, err := env.handler.Handle(&model.CommandArgs{
+		Command: "/cursor alias set web",
+		UserId:  "user-1",
+	})
+
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "Usage: `/cursor alias set")
+}
+
+func TestAliasList_Sorted(t *testing.T) {
+	env := setupTest(t)
+
+	env.store.On("GetUserSettings", "user-1").Return(&kvstore.UserSettings{Aliases: map[string]string{
+		"web": "repo=org/web branch=develop",
+		"api": "repo=org/api --no-plan",
+	}}, nil)
+
+	resp, err := env.handler.Handle(&model.CommandArgs{
+		Command: "/cursor alias list",
+		UserId:  "user-1",
+	})
+
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "| `@api` | `repo=org/api --no-plan` |")
+	assert.Contains(t, resp.Text, "| `@web` | `repo=org/web branch=develop` |")
+	assert.Less(t, strings.Index(resp.Text, "@api"), strings.Index(resp.Text, "@web"))
+}
+
+func TestAliasList_Empty(t *testing.T) {
+	env := setupTest(t)
+
+	env.store.On("GetUserSettings", "user-1").Return(nil, nil)
+
+	resp, err := env.handler.Handle(&model.CommandArgs{
+		Command: "/cursor alias list",
+		UserId:  "user-1",
+	})
+
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "You have no aliases")
+}
+
+func TestAliasRemove(t *testing.T) {
+	env := setupTest(t)
+
+	env.store.On("GetUserSettings", "user-1").Return(&kvstore.UserSettings{Aliases: map[string]string{
+		"web": "repo=org/web",
+		"api": "repo=org/api",
+	}}, nil)
+	var saved *kvstore.UserSettings
+	env.store.On("SaveUserSettings", "user-1", mock.Anything).Run(func(args mock.Arguments) {
+		saved = args.Get(1).(*kvstore.UserSettings)
+	}).Return(nil)
+
+	resp, err := env.handler.Handle(&model.CommandArgs{
+		Command: "/cursor alias remove @web",
+		UserId:  "user-1",
+	})
+
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "Removed alias `@web`")
+	require.NotNil(t, saved)
+	assert.Equal(t, map[string]string{"api": "repo=org/api"}, saved.Aliases)
+}
+
+func TestAliasRemove_Unknown(t *testing.T) {
+	env := setupTest(t)
+
+	env.store.On("GetUserSettings", "user-1").Return(nil, nil)
+
+	resp, err := env.handler.Handle(&model.CommandArgs{
+		Command: "/cursor alias remove web",
+		UserId:  "user-1",
+	})
+
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "You have no alias named `@web`")
+	env.store.AssertNotCalled(t, "SaveUserSettings", mock.Anything, mock.Anything)
+}
+
+func TestLaunch_ExpandsAliasWithInlineOverride(t *testing.T) {
+	env := setupTest(t)
+
+	env.store.On("GetChannelSettings", "ch-1").Return(nil, nil)
+	env.store.On("GetUserSettings", "user-1").Return(&kvstore.UserSettings{Aliases: map[string]string{
+		"web": "repo=org/web branch=develop",
+	}}, nil)
+
+	env.cursorClient.On("LaunchAgent", mock.Anything, mock.MatchedBy(func(req cursor.LaunchAgentRequest) bool {
+		return req.Source.Repository == "https://github.com/org/web" &&
+			req.Source.Ref == "hotfix" &&
+			req.Prompt.Text == "fix bug"
+	})).Return(&cursor.Agent{
+		ID:     "agent-alias",
+		Status: cursor.AgentStatusCreating,
+	}, nil)
+
+	env.api.On("CreatePost", mock.Anything).Return(&model.Post{Id: "bot-post-alias"}, nil)
+	env.api.On("AddReaction", mock.Anything).Return(&model.Reaction{}, nil)
+	env.store.On("SaveAgent", mock.Anything).Return(nil)
+	env.store.On("SetThreadAgent", mock.Anything, "agent-alias").Return(nil)
+	env.store.On("AddUserLaunchHistoryEntry", mock.Anything, mock.Anything, LaunchHistorySize).Return(nil)
+
+	resp, err := env.handler.Handle(&model.CommandArgs{
+		Command:   "/cursor @web branch=hotfix fix bug",
+		ChannelId: "ch-1",
+		UserId:    "user-1",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "", resp.Text)
+	env.cursorClient.AssertExpectations(t)
+}
+
+func TestSnooze_ByPRURL(t *testing.T) {
+	env := setupTest(t)
+
+	loop := &kvstore.ReviewLoop{
+		ID:               "loop-1",
+		UserID:           "user-1",
+		PRURL:            "https://github.com/org/repo/pull/42",
+		Phase:            kvstore.ReviewPhaseAwaitingReview,
+		StaleEscalatedAt: 1000,
+	}
+	env.store.On("GetReviewLoopByPRURL", "https://github.com/org/repo/pull/42").Return(loop, nil)
+	env.store.On("SaveReviewLoop", loop).Return(nil)
+
+	before := time.Now()
+	resp, err := env.handler.Handle(&model.CommandArgs{
+		Command: "/cursor snooze https://github.com/org/repo/pull/42 4h",
+		UserId:  "user-1",
+	})
+
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "are snoozed until")
+	assert.GreaterOrEqual(t, loop.SnoozeUntil, before.Add(4*time.Hour).UnixMilli())
+	assert.Zero(t, loop.StaleEscalatedAt)
+	env.store.AssertExpectations(t)
+}
+
+func TestSnooze_OffByLoopID(t *testing.T) {
+	env := setupTest(t)
+
+	loop := &kvstore.ReviewLoop{
+		ID:          "loop-1",
+		UserID:      "user-1",
+		PRURL:       "https://github.com/org/repo/pull/42",
+		Phase:       kvstore.ReviewPhaseHumanReview,
+		SnoozeUntil: time.Now().Add(time.Hour).UnixMilli(),
+	}
+	env.store.On("GetReviewLoop", "loop-1").Return(loop, nil)
+	env.store.On("SaveReviewLoop", loop).Return(nil)
+
+	resp, err := env.handler.Handle(&model.CommandArgs{
+		Command: "/cursor snooze loop-1 off",
+		UserId:  "user-1",
+	})
+
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "reminders resumed")
+	assert.Zero(t, loop.SnoozeUntil)
+}
+
+func TestSnooze_NotOwner(t *testing.T) {
+	env := setupTest(t)
+
+	env.store.On("GetReviewLoop", "loop-1").Return(&kvstore.ReviewLoop{
+		ID:     "loop-1",
+		UserID: "other-user",
+		Phase:  kvstore.ReviewPhaseAwaitingReview,
+	}, nil)
+
+	resp, err := env.handler.Handle(&model.CommandArgs{
+		Command: "/cursor snooze loop-1 2d",
+		UserId:  "user-1",
+	})
+
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "only snooze your own")
+	env.store.AssertNotCalled(t, "SaveReviewLoop", mock.Anything)
+}
+
+func TestSnooze_InvalidDuration(t *testing.T) {
+	env := setupTest(t)
+
+	resp, err := env.handler.Handle(&model.CommandArgs{
+		Command: "/cursor snooze loop-1 someday",
+		UserId:  "user-1",
+	})
+
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "Invalid snooze duration")
+	env.store.AssertNotCalled(t, "GetReviewLoop", mock.Anything)
+}
+
+func TestSnooze_MissingArgs(t *testing.T) {
+	env := setupTest(t)
+
+	resp, err := env.handler.Handle(&model.CommandArgs{
+		Command: "/cursor snooze loop-1",
+		UserId:  "user-1",
+	})
+
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "Usage: `/cursor snooze")
+}
+
+func setupAdminTest(t *testing.T, isAdmin bool, setPaused func(bool) error) *testEnv {
+	t.Helper()
+
+	env := setupTest(t)
+	env.api.On("HasPermissionTo", "user-1", model.PermissionManageSystem).Return(isAdmin)
+	env.handler = NewHandler(Dependencies{
+		Client:                 pluginapi.NewClient(env.api, nil),
+		CursorClientFn:         func() cursor.Client { return env.cursorClient },
+		Store:                  env.store,
+		BotUserID:              "bot-user-id",
+		SiteURL:                "http://localhost:8065",
+		PluginID:               "com.mattermost.plugin-cursor",
+		SetReviewLoopsPausedFn: setPaused,
+	})
+	return env
+}
+
+func TestAdmin_PauseAndResumeLoops(t *testing.T) {
+	var calls []bool
+	env := setupAdminTest(t, true, func(paused bool) error {
+		calls = append(calls, paused)
+		return nil
+	})
+
+	resp, err := env.handler.Handle(&model.CommandArgs{Command: "/cursor admin pause-loops", UserId: "user-1"})
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "All review loops are paused")
+
+	resp, err = env.handler.Handle(&model.CommandArgs{Command: "/cursor admin resume-loops", UserId: "user-1"})
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "Review loops resumed")
+
+	assert.Equal(t, []bool{true, false}, calls)
+}
+
+func TestAdmin_RequiresSystemAdmin(t *testing.T) {
+	called := false
+	env := setupAdminTest(t, false, func(bool) error {
+		called = true
+		return nil
+	})
+
+	resp, err := env.handler.Handle(&model.CommandArgs{Command: "/cursor admin pause-loops", UserId: "user-1"})
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "Only system administrators")
+	assert.False(t, called)
+}
+
+func TestAdmin_PauseLoopsExtraArgs(t *testing.T) {
+	env := setupAdminTest(t, true, func(bool) error { return nil })
+
+	resp, err := env.handler.Handle(&model.CommandArgs{Command: "/cursor admin pause-loops now", UserId: "user-1"})
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "Usage: `/cursor admin pause-loops`")
+}
+
+// setupGCTest seeds the index scan with one valid and one orphaned entry of
+// each target kind.
+func setupGCTest(t *testing.T) *testEnv {
+	t.Helper()
+
+	env := setupAdminTest(t, true, nil)
+	env.store.On("ListIndexEntries").Return([]kvstore.IndexEntry{
+		{Kind: kvstore.IndexKindThread, Key: "thread:post-1", TargetKind: kvstore.IndexTargetAgent, TargetID: "agent-live"},
+		{Kind: kvstore.IndexKindThread, Key: "thread:post-2", TargetKind: kvstore.IndexTargetAgent, TargetID: "agent-gone"},
+		{Kind: kvstore.IndexKindThread, Key: "thread:post-3", TargetKind: kvstore.IndexTargetWorkflow, TargetID: "wf-live"},
+		{Kind: kvstore.IndexKindAgentWorkflow, Key: "hitlagent:agent-live", TargetKind: kvstore.IndexTargetWorkflow, TargetID: "wf-gone"},
+		{Kind: kvstore.IndexKindPRURL, Key: "prurlidx:https://github.com/org/repo/pull/1", TargetKind: kvstore.IndexTargetAgent, TargetID: "agent-live"},
+		{Kind: kvstore.IndexKindReviewLoopByAgent, Key: "rlbyagent:agent-live", TargetKind: kvstore.IndexTargetReviewLoop, TargetID: "loop-gone"},
+		{Kind: kvstore.IndexKindWebhookDelivery, Key: "whdelivery:d-1", TargetKind: kvstore.IndexTargetDeliveryLog, TargetID: "d-1"},
+		{Kind: kvstore.IndexKindWebhookDelivery, Key: "whdelivery:d-2", TargetKind: kvstore.IndexTargetDeliveryLog, TargetID: "d-2"},
+	}, nil)
+	env.store.On("GetAgent", "agent-live").Return(&kvstore.AgentRecord{CursorAgentID: "agent-live"}, nil)
+	env.store.On("GetAgent", "agent-gone").Return(nil, nil)
+	env.store.On("GetWorkflow", "wf-live").Return(&kvstore.HITLWorkflow{ID: "wf-live"}, nil)
+	env.store.On("GetWorkflow", "wf-gone").Return(nil, nil)
+	env.store.On("GetReviewLoop", "loop-gone").Return(nil, nil)
+	env.store.On("ListWebhookDeliveries").Return([]*kvstore.WebhookDelivery{{DeliveryID: "d-1"}}, nil)
+	return env
+}
+
+func TestAdmin_GCReportsOrphansWithoutPruning(t *testing.T) {
+	env := setupGCTest(t)
+
+	resp, err := env.handler.Handle(&model.CommandArgs{Command: "/cursor admin gc", UserId: "user-1"})
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "| `thread` | 3 | 1 |")
+	assert.Contains(t, resp.Text, "| `agent_workflow` | 1 | 1 |")
+	assert.Contains(t, resp.Text, "| `pr_url` | 1 | 0 |")
+	assert.Contains(t, resp.Text, "| `review_loop_agent` | 1 | 1 |")
+	assert.Contains(t, resp.Text, "| `webhook_delivery` | 2 | 1 |")
+	assert.Contains(t, resp.Text, "Found 4 orphaned entries")
+	env.store.AssertNotCalled(t, "DeleteIndexEntry", mock.Anything)
+
+	// Each distinct target is looked up once.
+	env.store.AssertNumberOfCalls(t, "GetAgent", 2)
+}
+
+func TestAdmin_GCPruneDeletesOnlyOrphans(t *testing.T) {
+	env := setupGCTest(t)
+	for _, key := range []string{"thread:post-2", "hitlagent:agent-live", "rlbyagent:agent-live", "whdelivery:d-2"} {
+		env.store.On("DeleteIndexEntry", key).Return(nil).Once()
+	}
+
+	resp, err := env.handler.Handle(&model.CommandArgs{Command: "/cursor admin gc --prune", UserId: "user-1"})
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "Pruned 4 of 4 orphaned entries.")
+	env.store.AssertExpectations(t)
+	env.store.AssertNumberOfCalls(t, "DeleteIndexEntry", 4)
+}
+
+func TestAdmin_GCSkipsEntriesWhoseTargetCannotBeRead(t *testing.T) {
+	env := setupAdminTest(t, true, nil)
+	env.store.On("ListIndexEntries").Return([]kvstore.IndexEntry{
+		{Kind: kvstore.IndexKindBranch, Key: "branchidx:cursor/fix", TargetKind: kvstore.IndexTargetAgent, TargetID: "agent-1"},
+	}, nil)
+	env.store.On("GetAgent", "agent-1").Return(nil, fmt.Errorf("kv unavailable"))
+
+	resp, err := env.handler.Handle(&model.CommandArgs{Command: "/cursor admin gc --prune", UserId: "user-1"})
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "No orphaned entries found.")
+	assert.Contains(t, resp.Text, "1 entries were skipped")
+	env.store.AssertNotCalled(t, "DeleteIndexEntry", mock.Anything)
+}
+
+func TestAdmin_GCRequiresSystemAdmin(t *testing.T) {
+	env := setupAdminTest(t, false, nil)
+
+	resp, err := env.handler.Handle(&model.CommandArgs{Command: "/cursor admin gc --prune", UserId: "user-1"})
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "Only system administrators")
+	env.store.AssertNotCalled(t, "ListIndexEntries")
+}
+
+func TestAdmin_OtherTextLaunchesAgent(t *testing.T) {
+	env := setupTest(t)
+
+	// "admin" followed by anything else is a launch prompt; with no
+	// repository configured the launch stops at the repository check.
+	env.store.On("GetChannelSettings", "ch-1").Return(nil, nil)
+	env.store.On("GetUserSettings", "user-1").Return(nil, nil)
+
+	resp, err := env.handler.Handle(&model.CommandArgs{Command: "/cursor admin page is broken", UserId: "user-1", ChannelId: "ch-1"})
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "No repository specified")
+}
+
+func TestReviewDispatch_Dispatched(t *testing.T) {
+	env := setupTest(t)
+
+	loop := &kvstore.ReviewLoop{
+		ID:     "loop-1",
+		UserID: "user-1",
+		PRURL:  "https://github.com/org/repo/pull/42",
+		Phase:  kvstore.ReviewPhaseAwaitingReview,
+	}
+	env.store.On("GetReviewLoopByPRURL", "https://github.com/org/repo/pull/42").Return(loop, nil)
+
+	var dispatched *kvstore.ReviewLoop
+	env.handler.(*Handler).deps.DispatchReviewLoopFn = func(l *kvstore.ReviewLoop) (ReviewDispatchResult, error) {
+		dispatched = l
+		return ReviewDispatchResult{Outcome: ReviewDispatchDispatched, Reason: "direct", New: 2, Repeated: 1}, nil
+	}
+
+	resp, err := env.handler.Handle(&model.CommandArgs{
+		Command: "/cursor review dispatch https://github.com/org/repo/pull/42",
+		UserId:  "user-1",
+	})
+
+	require.NoError(t, err)
+	assert.Same(t, loop, dispatched)
+	assert.Equal(t, "Dispatched review feedback for https://github.com/org/repo/pull/42 to the agent (2 new, 1 repeated, 0 dismissed).", resp.Text)
+}
+
+func TestReviewDispatch_SkippedWhenIdempotent(t *testing.T) {
+	env := setupTest(t)
+
+	env.store.On("GetReviewLoop", "loop-1").Return(&kvstore.ReviewLoop{
+		ID:     "loop-1",
+		UserID: "user-1",
+		PRURL:  "https://github.com/org/repo/pull/42",
+		Phase:  kvstore.ReviewPhaseHumanReview,
+	}, nil)
+	env.handler.(*Handler).deps.DispatchReviewLoopFn = func(*kvstore.ReviewLoop) (ReviewDispatchResult, error) {
+		return ReviewDispatchResult{Outcome: ReviewDispatchSkipped, Reason: "skipped_idempotent", Repeated: 3}, nil
+	}
+
+	resp, err := env.handler.Handle(&model.CommandArgs{Command: "/cursor review dispatch loop-1", UserId: "user-1"})
+
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "Skipped dispatching review feedback")
+	assert.Contains(t, resp.Text, "`skipped_idempotent`; 0 new, 3 repeated, 0 dismissed")
+}
+
+func TestReviewDispatch_NotOwner(t *testing.T) {
+	env := setupTest(t)
+
+	env.store.On("GetReviewLoop", "loop-1").Return(&kvstore.ReviewLoop{
+		ID:     "loop-1",
+		UserID: "user-2",
+		Phase:  kvstore.ReviewPhaseAwaitingReview,
+	}, nil)
+	env.handler.(*Handler).deps.DispatchReviewLoopFn = func(*kvstore.ReviewLoop) (ReviewDispatchResult, error) {
+		t.Fatal("dispatch must not run for another user's loop")
+		return ReviewDispatchResult{}, nil
+	}
+
+	resp, err := env.handler.Handle(&model.CommandArgs{Command: "/cursor review dispatch loop-1", UserId: "user-1"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "You can only dispatch feedback for your own review loops.", resp.Text)
+}
+
+func TestReviewDispatch_RequiresWaitingPhase(t *testing.T) {
+	env := setupTest(t)
+
+	env.store.On("GetReviewLoop", "loop-fixing").Return(&kvstore.ReviewLoop{
+		UserID: "user-1",
+		PRURL:  "https://github.com/org/repo/pull/42",
+		Phase:  kvstore.ReviewPhaseCursorFixing,
+	}, nil)
+	env.store.On("GetReviewLoop", "loop-done").Return(&kvstore.ReviewLoop{
+		UserID: "user-1",
+		PRURL:  "https://github.com/org/repo/pull/43",
+		Phase:  kvstore.ReviewPhaseComplete,
+	}, nil)
+	env.handler.(*Handler).deps.DispatchReviewLoopFn = func(*kvstore.ReviewLoop) (ReviewDispatchResult, error) {
+		t.Fatal("dispatch must not run outside the waiting phases")
+		return ReviewDispatchResult{}, nil
+	}
+
+	resp, err := env.handler.Handle(&model.CommandArgs{Command: "/cursor review dispatch loop-fixing", UserId: "user-1"})
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "only be dispatched while it is waiting on reviews")
+
+	resp, err = env.handler.Handle(&model.CommandArgs{Command: "/cursor review dispatch loop-done", UserId: "user-1"})
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "has already finished")
+}
+
+func TestReviewDispatch_ReportsPausedAndMaxIterations(t *testing.T) {
+	env := setupTest(t)
+
+	env.store.On("GetReviewLoop", "loop-1").Return(&kvstore.ReviewLoop{
+		UserID: "user-1",
+		PRURL:  "https://github.com/org/repo/pull/42",
+		Phase:  kvstore.ReviewPhaseAwaitingReview,
+	}, nil)
+	outcome := ReviewDispatchPaused
+	env.handler.(*Handler).deps.DispatchReviewLoopFn = func(*kvstore.ReviewLoop) (ReviewDispatchResult, error) {
+		return ReviewDispatchResult{Outcome: outcome, Reason: outcome}, nil
+	}
+
+	resp, err := env.handler.Handle(&model.CommandArgs{Command: "/cursor review dispatch loop-1", UserId: "user-1"})
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "Review loops are paused by an administrator")
+
+	outcome = ReviewDispatchMaxIterations
+	resp, err = env.handler.Handle(&model.CommandArgs{Command: "/cursor review dispatch loop-1", UserId: "user-1"})
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "has reached its max iterations")
+}
+
+func TestReviewDispatch_UsageAndLaunchFallback(t *testing.T) {
+	env := setupTest(t)
+
+	resp, err := env.handler.Handle(&model.CommandArgs{Command: "/cursor review dispatch", UserId: "user-1"})
+	require.NoError(t, err)
+	assert.Equal(t, reviewUsage, resp.Text)
+
+	// Any other "review ..." text is a launch prompt.
+	env.store.On("GetChannelSettings", "ch-1").Return(nil, nil)
+	env.store.On("GetUserSettings", "user-1").Return(nil, nil)
+
+	resp, err = env.handler.Handle(&model.CommandArgs{Command: "/cursor review the login flow", UserId: "user-1", ChannelId: "ch-1"})
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "No repository specified")
+}
+
+func TestPlanDiff_ShowsAddedAndRemovedLines(t *testing.T) {
+	env := setupTest(t)
+	env.store.On("GetWorkflow", "wf-1").Return(&kvstore.HITLWorkflow{
+		ID:                 "wf-1",
+		UserID:             "user-1",
+		PlanIterationCount: 1,
+		PlanVersions: []string{
+			"1. Add the endpoint\n2. Write tests\n3. Update docs",
+			"1. Add the endpoint\n2. Add input validation\n3. Write tests",
+		},
+	}, nil)
+
+	resp, err := env.handler.Handle(&model.CommandArgs{Command: "/cursor plan diff wf-1", UserId: "user-1"})
+	require.NoError(t, err)
+
+	assert.Contains(t, resp.Text, "```diff\n--- plan v1\n+++ plan v2\n")
+	assert.Contains(t, resp.Text, "\n+2. Add input validation\n")
+	assert.Contains(t, resp.Text, "\n-2. Write tests\n")
+	assert.Contains(t, resp.Text, "\n-3. Update docs\n")
+	assert.Contains(t, resp.Text, "\n+3. Write tests\n")
+	assert.Contains(t, resp.Text, "\n 1. Add the endpoint\n")
+}
+
+func TestPlanDiff_NeedsTwoVersions(t *testing.T) {
+	env := setupTest(t)
+	env.store.On("GetWorkflow", "wf-1").Return(&kvstore.HITLWorkflow{
+		ID:           "wf-1",
+		UserID:       "user-1",
+		PlanVersions: []string{"1. Add the endpoint"},
+	}, nil)
+
+	resp, err := env.handler.Handle(&model.CommandArgs{Command: "/cursor plan diff wf-1", UserId: "user-1"})
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "fewer than two plan versions")
+}
+
+func TestPlanDiff_NotOwner(t *testing.T) {
+	env := setupTest(t)
+	env.store.On("GetWorkflow", "wf-1").Return(&kvstore.HITLWorkflow{
+		ID:           "wf-1",
+		UserID:       "someone-else",
+		PlanVersions: []string{"a", "b"},
+	}, nil)
+
+	resp, err := env.handler.Handle(&model.CommandArgs{Command: "/cursor plan diff wf-1", UserId: "user-1"})
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "your own workflows")
+}
+
+func TestUnifiedLineDiff(t *testing.T) {
+	t.Run("identical texts", func(t *testing.T) {
+		assert.Empty(t, unifiedLineDiff("a\nb\n", "a\nb", "old", "new"))
+	})
+
+	t.Run("hunks keep three lines of context", func(t *testing.T) {
+		oldText := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n14"
+		newText := "1\n2\n3\n4\n5\nsix\n7\n8\n9\n10\n11\n12\n13\n14\n15"
+
+		diff := unifiedLineDiff(oldText, newText, "old", "new")
+		assert.Equal(t, "--- old\n+++ new\n"+
+			"@@ -3,7 +3,7 @@\n 3\n 4\n 5\n-6\n+six\n 7\n 8\n 9\n"+
+			"@@ -12,3 +12,4 @@\n 12\n 13\n 14\n+15\n", diff)
+	})
+
+	t.Run("nearby changes share a hunk", func(t *testing.T) {
+		diff := unifiedLineDiff("a\nb\nc\nd", "a\nB\nc\nD", "old", "new")
+		assert.Equal(t, "--- old\n+++ new\n@@ -1,4 +1,4 @@\n a\n-b\n+B\n c\n-d\n+D\n", diff)
+	})
+}
+
+func TestLink_LinksPullRequest(t *testing.T) {
+	env := setupTest(t)
+
+	record := &kvstore.AgentRecord{CursorAgentID: "agent-1", UserID: "user-1", Repository: "org/repo"}
+	env.store.On("GetAgent", "agent-1").Return(record, nil)
+	env.store.On("GetAgentByPRURL", "https://github.com/org/repo/pull/42").Return(nil, nil)
+
+	var linked *kvstore.AgentRecord
+	env.handler.(*Handler).deps.LinkPullRequestFn = func(r *kvstore.AgentRecord, prURL string) (LinkPullRequestResult, error) {
+		linked = r
+		assert.Equal(t, "https://github.com/org/repo/pull/42", prURL)
+		return LinkPullRequestResult{PRURL: prURL, Branch: "cursor/fix", ReviewLoopStarted: true}, nil
+	}
+
+	resp, err := env.handler.Handle(&model.CommandArgs{
+		Command: "/cursor link agent-1 https://github.com/org/repo/pull/42",
+		UserId:  "user-1",
+	})
+
+	require.NoError(t, err)
+	assert.Same(t, record, linked)
+	assert.Equal(t, "Linked https://github.com/org/repo/pull/42 (branch `cursor/fix`) to agent `agent-1`. The AI review loop has started.", resp.Text)
+}
+
+func TestLink_Rejections(t *testing.T) {
+	env := setupTest(t)
+
+	env.store.On("GetAgent", "agent-mine").Return(&kvstore.AgentRecord{CursorAgentID: "agent-mine", UserID: "user-1", Repository: "org/repo"}, nil)
+	env.store.On("GetAgent", "agent-theirs").Return(&kvstore.AgentRecord{CursorAgentID: "agent-theirs", UserID: "user-2"}, nil)
+	env.store.On("GetAgent", "agent-linked").Return(&kvstore.AgentRecord{CursorAgentID: "agent-linked", UserID: "user-1", PrURL: "https://github.com/org/repo/pull/7"}, nil)
+	env.store.On("GetAgentByPRURL", "https://github.com/org/repo/pull/43").Return(&kvstore.AgentRecord{CursorAgentID: "agent-other"}, nil)
+	env.store.On("GetAgentByPRURL", "https://github.com/org/repo/pull/44").Return(nil, nil)
+	env.store.On("GetAgentByPRURL", "https://github.com/org/repo/pull/45").Return(nil, nil)
+
+	env.handler.(*Handler).deps.LinkPullRequestFn = func(_ *kvstore.AgentRecord, prURL string) (LinkPullRequestResult, error) {
+		switch prURL {
+		case "https://github.com/org/repo/pull/44":
+			return LinkPullRequestResult{}, ErrPullRequestNotFound
+		case "https://github.com/org/repo/pull/45":
+			return LinkPullRequestResult{}, ErrGitHubNotConfigured
+		}
+		t.Fatalf("link must not run for %s", prURL)
+		return LinkPullRequestResult{}, nil
+	}
+
+	tests := []struct {
+		command  string
+		expected string
+	}{
+		{"/cursor link agent-theirs https://github.com/org/repo/pull/42", "You can only link PRs to your own agents."},
+		{"/cursor link agent-linked https://github.com/org/repo/pull/42", "Agent `agent-linked` is already linked to https://github.com/org/repo/pull/7."},
+		{"/cursor link agent-mine https://github.com/org/repo/issues/42", "`https://github.com/org/repo/issues/42` is not a GitHub pull request URL."},
+		{"/cursor link agent-mine https://github.com/org/web/pull/42", "That PR is in `org/web`, but agent `agent-mine` works on `org/repo`."},
+		{"/cursor link agent-mine https://github.com/org/repo/pull/43", "https://github.com/org/repo/pull/43 is already linked to agent `agent-other`."},
+		{"/cursor link agent-mine https://github.com/org/repo/pull/44", "PR https://github.com/org/repo/pull/44 was not found on GitHub."},
+		{"/cursor link agent-mine https://github.com/org/repo/pull/45", "GitHub is not configured, so the PR cannot be verified. Please ask your system administrator to set a GitHub Personal Access Token."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			resp, err := env.handler.Handle(&model.CommandArgs{Command: tt.command, UserId: "user-1"})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, resp.Text)
+		})
+	}
+}
+
+func TestLink_UsageAndLaunchFallback(t *testing.T) {
+	env := setupTest(t)
+	env.store.On("GetAgent", "ghost").Return(nil, nil)
+	env.handler.(*Handler).deps.LinkPullRequestFn = func(*kvstore.AgentRecord, string) (LinkPullRequestResult, error) {
+		t.Fatal("link must not run")
+		return LinkPullRequestResult{}, nil
+	}
+
+	resp, err := env.handler.Handle(&model.CommandArgs{Command: "/cursor link ghost https://github.com/org/repo/pull/42", UserId: "user-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "Agent `ghost` not found.", resp.Text)
+
+	// Any other "link ..." text is a launch prompt.
+	env.store.On("GetChannelSettings", "ch-1").Return(nil, nil)
+	env.store.On("GetUserSettings", "user-1").Return(nil, nil)
+	resp, err = env.handler.Handle(&model.CommandArgs{Command: "/cursor link the docs page to the API reference", UserId: "user-1", ChannelId: "ch-1"})
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "No repository specified")
+}
@@ -0,0 +1,179 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+const gcPruneFlag = "--prune"
+
+// gcIndexKinds lists the index kinds in the order they are reported.
+var gcIndexKinds = []string{
+	kvstore.IndexKindThread,
+	kvstore.IndexKindAgentWorkflow,
+	kvstore.IndexKindPRURL,
+	kvstore.IndexKindBranch,
+	kvstore.IndexKindActiveAgent,
+	kvstore.IndexKindUserAgent,
+	kvstore.IndexKindReviewLoopByPR,
+	kvstore.IndexKindReviewLoopByAgent,
+	kvstore.IndexKindWebhookDelivery,
+}
+
+// indexGCReport summarizes a scan of the kvstore lookup indexes.
+type indexGCReport struct {
+	Total    map[string]int
+	Orphans  []kvstore.IndexEntry
+	Skipped  int // Entries whose target could not be checked
+	Pruned   int
+	PruneErr int
+}
+
+// executeGC scans the lookup indexes for entries whose referenced record no
+// longer exists and, with --prune, deletes them.
+func (h *Handler) executeGC(args *model.CommandArgs, params []string) *model.CommandResponse {
+	prune := false
+	for _, param := range params {
+		if !strings.EqualFold(param, gcPruneFlag) {
+			return ephemeralResponse(adminUsage)
+		}
+		prune = true
+	}
+
+	report, err := h.scanIndexes()
+	if err != nil {
+		h.deps.Client.Log.Error("Failed to scan kvstore indexes", "user_id", args.UserId, "error", err.Error())
+		return ephemeralResponse("Failed to scan the plugin's stored indexes. Please try again.")
+	}
+
+	if prune {
+		for _, orphan := range report.Orphans {
+			if err := h.deps.Store.DeleteIndexEntry(orphan.Key); err != nil {
+				h.deps.Client.Log.Warn("Failed to prune orphaned index entry", "key", orphan.Key, "error", err.Error())
+				report.PruneErr++
+				continue
+			}
+			report.Pruned++
+		}
+		h.deps.Client.Log.Info("Pruned orphaned kvstore index entries", "user_id", args.UserId, "pruned", report.Pruned)
+	}
+
+	return ephemeralResponse(formatIndexGCReport(report, prune))
+}
+
+// scanIndexes lists every lookup index entry and checks that the record it
+// references still exists. Lookups that fail are skipped rather than treated
+// as orphans, so a transient store error never leads to pruning.
+func (h *Handler) scanIndexes() (*indexGCReport, error) {
+	entries, err := h.deps.Store.ListIndexEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &indexGCReport{Total: map[string]int{}}
+	exists := map[string]bool{}
+	var loggedDeliveries map[string]bool
+	for _, entry := range entries {
+		report.Total[entry.Kind]++
+
+		if entry.TargetKind == kvstore.IndexTargetDeliveryLog {
+			if loggedDeliveries == nil {
+				loggedDeliveries, err = h.loggedDeliveryIDs()
+				if err != nil {
+					return nil, err
+				}
+			}
+			if !loggedDeliveries[entry.TargetID] {
+				report.Orphans = append(report.Orphans, entry)
+			}
+			continue
+		}
+
+		cacheKey := entry.TargetKind + ":" + entry.TargetID
+		found, checked := exists[cacheKey]
+		if !checked {
+			found, err = h.indexTargetExists(entry)
+			if err != nil {
+				report.Skipped++
+				continue
+			}
+			exists[cacheKey] = found
+		}
+		if !found {
+			report.Orphans = append(report.Orphans, entry)
+		}
+	}
+	return report, nil
+}
+
+// indexTargetExists reports whether the record an index entry references is
+// still stored.
+func (h *Handler) indexTargetExists(entry kvstore.IndexEntry) (bool, error) {
+	if entry.TargetID == "" {
+		return false, nil
+	}
+
+	switch entry.TargetKind {
+	case kvstore.IndexTargetAgent:
+		record, err := h.deps.Store.GetAgent(entry.TargetID)
+		return record != nil, err
+	case kvstore.IndexTargetWorkflow:
+		workflow, err := h.deps.Store.GetWorkflow(entry.TargetID)
+		return workflow != nil, err
+	case kvstore.IndexTargetReviewLoop:
+		loop, err := h.deps.Store.GetReviewLoop(entry.TargetID)
+		return loop != nil, err
+	default:
+		return false, fmt.Errorf("unknown index target kind %q", entry.TargetKind)
+	}
+}
+
+// loggedDeliveryIDs returns the IDs of the webhook deliveries still listed in
+// the recorded delivery log.
+func (h *Handler) loggedDeliveryIDs() (map[string]bool, error) {
+	deliveries, err := h.deps.Store.ListWebhookDeliveries()
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[string]bool, len(deliveries))
+	for _, delivery := range deliveries {
+		ids[delivery.DeliveryID] = true
+	}
+	return ids, nil
+}
+
+func formatIndexGCReport(report *indexGCReport, pruned bool) string {
+	orphansByKind := map[string]int{}
+	for _, orphan := range report.Orphans {
+		orphansByKind[orphan.Kind]++
+	}
+
+	var sb strings.Builder
+	sb.WriteString("#### Stored index scan\n\n")
+	sb.WriteString("| Index | Entries | Orphaned |\n")
+	sb.WriteString("|:------|--------:|---------:|\n")
+	for _, kind := range gcIndexKinds {
+		fmt.Fprintf(&sb, "| `%s` | %d | %d |\n", kind, report.Total[kind], orphansByKind[kind])
+	}
+
+	sb.WriteString("\n")
+	switch {
+	case len(report.Orphans) == 0:
+		sb.WriteString("No orphaned entries found.")
+	case pruned:
+		fmt.Fprintf(&sb, "Pruned %d of %d orphaned entries.", report.Pruned, len(report.Orphans))
+		if report.PruneErr > 0 {
+			fmt.Fprintf(&sb, " %d could not be deleted; see the server logs.", report.PruneErr)
+		}
+	default:
+		fmt.Fprintf(&sb, "Found %d orphaned entries. Run `/cursor admin gc --prune` to delete them.", len(report.Orphans))
+	}
+	if report.Skipped > 0 {
+		fmt.Fprintf(&sb, "\n%d entries were skipped because their records could not be read.", report.Skipped)
+	}
+	return sb.String()
+}
@@ -0,0 +1,87 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/ghclient"
+)
+
+const linkUsage = "Usage: `/cursor link <agentID> <PR URL>`\nGet IDs from `/cursor list` or `/cursor status`."
+
+// Errors LinkPullRequestFn returns for problems the user can act on.
+var (
+	ErrGitHubNotConfigured = errors.New("GitHub is not configured")
+	ErrPullRequestNotFound = errors.New("pull request not found")
+)
+
+// LinkPullRequestResult reports what linking a PR to an agent did.
+type LinkPullRequestResult struct {
+	PRURL             string // The PR's URL as reported by GitHub
+	Branch            string // The PR's head branch
+	ReviewLoopStarted bool
+}
+
+// isLinkCommand reports whether the fields after "/cursor link" look like a
+// link rather than a launch prompt starting with "link".
+func isLinkCommand(params []string) bool {
+	return len(params) == 2 &&
+		(strings.HasPrefix(params[1], "https://") || strings.HasPrefix(params[1], "http://"))
+}
+
+// executeLink binds an existing PR to one of the user's agents whose PR was
+// not detected automatically, e.g. because it was opened before the plugin
+// was configured. A review loop is started when the agent has finished.
+func (h *Handler) executeLink(args *model.CommandArgs, params []string) (*model.CommandResponse, error) {
+	if !isLinkCommand(params) {
+		return ephemeralResponse(linkUsage), nil
+	}
+	if h.deps.LinkPullRequestFn == nil {
+		return ephemeralResponse("Linking PRs is not available."), nil
+	}
+	agentID, prURL := params[0], params[1]
+
+	record, err := h.deps.Store.GetAgent(agentID)
+	if err != nil || record == nil {
+		return ephemeralResponse(fmt.Sprintf("Agent `%s` not found.", agentID)), nil
+	}
+	if record.UserID != args.UserId {
+		return ephemeralResponse("You can only link PRs to your own agents."), nil
+	}
+	if record.PrURL != "" {
+		return ephemeralResponse(fmt.Sprintf("Agent `%s` is already linked to %s.", agentID, record.PrURL)), nil
+	}
+
+	prRef, err := ghclient.ParsePRURL(prURL)
+	if err != nil {
+		return ephemeralResponse(fmt.Sprintf("`%s` is not a GitHub pull request URL.", prURL)), nil
+	}
+	if repoRef, refErr := ghclient.ParseRepoRef(record.Repository); refErr == nil &&
+		(!strings.EqualFold(repoRef.Owner, prRef.Owner) || !strings.EqualFold(repoRef.Repo, prRef.Repo)) {
+		return ephemeralResponse(fmt.Sprintf("That PR is in `%s/%s`, but agent `%s` works on `%s`.",
+			prRef.Owner, prRef.Repo, agentID, record.Repository)), nil
+	}
+	if existing, _ := h.deps.Store.GetAgentByPRURL(prURL); existing != nil && existing.CursorAgentID != agentID {
+		return ephemeralResponse(fmt.Sprintf("%s is already linked to agent `%s`.", prURL, existing.CursorAgentID)), nil
+	}
+
+	result, err := h.deps.LinkPullRequestFn(record, prURL)
+	switch {
+	case errors.Is(err, ErrGitHubNotConfigured):
+		return ephemeralResponse("GitHub is not configured, so the PR cannot be verified. Please ask your system administrator to set a GitHub Personal Access Token."), nil
+	case errors.Is(err, ErrPullRequestNotFound):
+		return ephemeralResponse(fmt.Sprintf("PR %s was not found on GitHub.", prURL)), nil
+	case err != nil:
+		h.deps.Client.Log.Error("Failed to link PR to agent", "agent_id", agentID, "pr_url", prURL, "error", err.Error())
+		return ephemeralResponse("Failed to link the PR. Please try again."), nil
+	}
+
+	message := fmt.Sprintf("Linked %s (branch `%s`) to agent `%s`.", result.PRURL, result.Branch, agentID)
+	if result.ReviewLoopStarted {
+		message += " The AI review loop has started."
+	}
+	return ephemeralResponse(message), nil
+}
@@ -0,0 +1,189 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+const (
+	planActionDiff = "diff"
+
+	planUsage = "Usage: `/cursor plan diff <workflow-id>`"
+
+	// planDiffContext is the number of unchanged lines shown around changes.
+	planDiffContext = 3
+)
+
+// executePlan handles /cursor plan subcommands.
+func (h *Handler) executePlan(args *model.CommandArgs, params []string) (*model.CommandResponse, error) {
+	if len(params) != 2 || !strings.EqualFold(params[0], planActionDiff) {
+		return ephemeralResponse(planUsage), nil
+	}
+	return h.executePlanDiff(args, params[1])
+}
+
+// executePlanDiff shows what changed between the latest two plan versions of
+// one of the user's HITL workflows.
+func (h *Handler) executePlanDiff(args *model.CommandArgs, workflowID string) (*model.CommandResponse, error) {
+	workflow, err := h.deps.Store.GetWorkflow(workflowID)
+	if err != nil || workflow == nil {
+		return ephemeralResponse(fmt.Sprintf("No workflow found for `%s`.", workflowID)), nil
+	}
+	if workflow.UserID != args.UserId {
+		return ephemeralResponse("You can only view plans of your own workflows."), nil
+	}
+
+	versions := workflow.PlanVersions
+	if len(versions) < 2 {
+		return ephemeralResponse(fmt.Sprintf("Workflow `%s` has fewer than two plan versions to compare.", workflowID)), nil
+	}
+
+	// Version numbers match the "Plan vN" labels shown in the thread.
+	newest := workflow.PlanIterationCount + 1
+	if newest < len(versions) {
+		newest = len(versions)
+	}
+	previous, latest := versions[len(versions)-2], versions[len(versions)-1]
+
+	diff := unifiedLineDiff(previous, latest, fmt.Sprintf("plan v%d", newest-1), fmt.Sprintf("plan v%d", newest))
+	if diff == "" {
+		return ephemeralResponse(fmt.Sprintf("Plan v%d is identical to plan v%d.", newest, newest-1)), nil
+	}
+
+	return ephemeralResponse(fmt.Sprintf("#### Plan changes for workflow `%s`\n\n```diff\n%s```", workflowID, diff)), nil
+}
+
+// unifiedLineDiff renders a unified diff of two texts line by line, with
+// planDiffContext lines of context around each change. Returns "" when the
+// texts have the same lines.
+func unifiedLineDiff(oldText, newText, oldLabel, newLabel string) string {
+	oldLines := splitDiffLines(oldText)
+	newLines := splitDiffLines(newText)
+	ops := diffLines(oldLines, newLines)
+
+	changed := false
+	for _, op := range ops {
+		if op.kind != ' ' {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", oldLabel, newLabel)
+
+	for start := 0; start < len(ops); {
+		// Find the next change and open a hunk with leading context.
+		first := start
+		for first < len(ops) && ops[first].kind == ' ' {
+			first++
+		}
+		if first == len(ops) {
+			break
+		}
+		hunkStart := max(first-planDiffContext, start)
+
+		// Extend the hunk while changes are within 2*context of each other.
+		hunkEnd := first
+		for i := first; i < len(ops); i++ {
+			if ops[i].kind != ' ' {
+				hunkEnd = i
+				continue
+			}
+			if i-hunkEnd > 2*planDiffContext {
+				break
+			}
+		}
+		hunkEnd = min(hunkEnd+planDiffContext+1, len(ops))
+
+		oldStart, newStart := ops[hunkStart].oldLine, ops[hunkStart].newLine
+		oldCount, newCount := 0, 0
+		for _, op := range ops[hunkStart:hunkEnd] {
+			if op.kind != '+' {
+				oldCount++
+			}
+			if op.kind != '-' {
+				newCount++
+			}
+		}
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(oldStart, oldCount), hunkRange(newStart, newCount))
+		for _, op := range ops[hunkStart:hunkEnd] {
+			fmt.Fprintf(&sb, "%c%s\n", op.kind, op.text)
+		}
+
+		start = hunkEnd
+	}
+
+	return sb.String()
+}
+
+// diffOp is one line of a line diff: ' ' kept, '-' removed or '+' added.
+// oldLine and newLine are the 1-based positions the op starts at.
+type diffOp struct {
+	kind    byte
+	text    string
+	oldLine int
+	newLine int
+}
+
+// diffLines computes a minimal line diff using the longest common subsequence.
+func diffLines(oldLines, newLines []string) []diffOp {
+	n, m := len(oldLines), len(newLines)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if oldLines[i] == newLines[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	ops := make([]diffOp, 0, n+m)
+	i, j := 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && oldLines[i] == newLines[j]:
+			ops = append(ops, diffOp{kind: ' ', text: oldLines[i], oldLine: i + 1, newLine: j + 1})
+			i++
+			j++
+		case i < n && (j == m || lcs[i+1][j] >= lcs[i][j+1]):
+			// Removals come before additions, as in diff(1).
+			ops = append(ops, diffOp{kind: '-', text: oldLines[i], oldLine: i + 1, newLine: j + 1})
+			i++
+		default:
+			ops = append(ops, diffOp{kind: '+', text: newLines[j], oldLine: i + 1, newLine: j + 1})
+			j++
+		}
+	}
+	return ops
+}
+
+func splitDiffLines(text string) []string {
+	text = strings.TrimRight(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
+
+// hunkRange formats a unified diff range. An empty range points at the line
+// before it, as in diff(1).
+func hunkRange(start, count int) string {
+	if count == 0 {
+		start--
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
@@ -0,0 +1,85 @@
+package command
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+// LaunchHistorySize is how many recent launches are kept per user.
+const LaunchHistorySize = 10
+
+const (
+	recentActionRun = "run"
+
+	recentUsage = "Usage: `/cursor recent` to list your recent launches, or `/cursor recent run <n>` to launch entry n again."
+
+	maxRecentPromptLen = 100
+)
+
+// executeRecent lists the user's recent launches, or relaunches one of them
+// with "run <n>".
+func (h *Handler) executeRecent(args *model.CommandArgs, params []string) (*model.CommandResponse, error) {
+	if len(params) > 0 && (len(params) != 2 || !strings.EqualFold(params[0], recentActionRun)) {
+		return ephemeralResponse(recentUsage), nil
+	}
+
+	history, err := h.deps.Store.GetUserLaunchHistory(args.UserId)
+	if err != nil {
+		return ephemeralResponse("Failed to load your recent launches."), nil
+	}
+	if history == nil || len(history.Entries) == 0 {
+		return ephemeralResponse("You have no recent launches. Launch one with `/cursor <prompt>` or `@cursor <prompt>`."), nil
+	}
+
+	if len(params) == 0 {
+		return ephemeralResponse(formatRecentLaunches(history.Entries)), nil
+	}
+
+	n, err := strconv.Atoi(params[1])
+	if err != nil || n < 1 || n > len(history.Entries) {
+		return ephemeralResponse(fmt.Sprintf("Choose an entry between 1 and %d from `/cursor recent`.", len(history.Entries))), nil
+	}
+	if h.deps.CursorClientFn() == nil {
+		return ephemeralResponse(errNoCursorClient), nil
+	}
+
+	entry := history.Entries[n-1]
+	return h.launchAgent(args, launchParams{
+		Prompt:       entry.Prompt,
+		Repository:   entry.Repository,
+		Branch:       entry.Branch,
+		Ref:          entry.SourceRef,
+		BaseBranch:   entry.BaseBranch,
+		Model:        entry.Model,
+		AutoCreatePR: entry.AutoCreatePR == nil || *entry.AutoCreatePR,
+		Draft:        entry.Draft,
+	})
+}
+
+// formatRecentLaunches renders the numbered list shown by /cursor recent.
+func formatRecentLaunches(entries []kvstore.LaunchHistoryEntry) string {
+	var sb strings.Builder
+	sb.WriteString("#### Your Recent Launches\n\n")
+	for i, entry := range entries {
+		prompt := strings.Join(strings.Fields(entry.Prompt), " ")
+		if runes := []rune(prompt); len(runes) > maxRecentPromptLen {
+			prompt = string(runes[:maxRecentPromptLen]) + "..."
+		}
+		sb.WriteString(fmt.Sprintf("%d. `%s@%s` with `%s`: %s\n", i+1, entry.Repository, entry.Branch, entry.Model, prompt))
+	}
+	sb.WriteString("\nLaunch one again with `/cursor recent run <n>`.")
+	return sb.String()
+}
+
+// recordLaunch adds a launch to the user's history. Failures are logged and
+// never block the launch.
+func (h *Handler) recordLaunch(userID string, entry kvstore.LaunchHistoryEntry) {
+	if err := h.deps.Store.AddUserLaunchHistoryEntry(userID, entry, LaunchHistorySize); err != nil {
+		h.deps.Client.Log.Warn("Failed to record launch history", "user_id", userID, "error", err.Error())
+	}
+}
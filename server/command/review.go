@@ -0,0 +1,93 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+const reviewUsage = "Usage: `/cursor review dispatch <PR URL or review loop ID>`"
+
+// Review dispatch outcomes reported by DispatchReviewLoopFn.
+const (
+	ReviewDispatchDispatched = "dispatched"
+	ReviewDispatchSkipped    = "skipped"
+	ReviewDispatchFailed     = "failed"
+
+	// ReviewDispatchPaused means review loops are globally paused, so
+	// nothing was dispatched.
+	ReviewDispatchPaused = "paused"
+
+	// ReviewDispatchMaxIterations means the loop has used all of its fix
+	// iterations, so nothing was dispatched.
+	ReviewDispatchMaxIterations = "max_iterations"
+)
+
+// ReviewDispatchResult reports what an on-demand review feedback dispatch did.
+type ReviewDispatchResult struct {
+	Outcome   string // One of the ReviewDispatch* outcomes
+	Reason    string // Dispatch mode, e.g. "skipped_idempotent"
+	New       int
+	Repeated  int
+	Dismissed int
+}
+
+// executeReview handles review loop operations.
+func (h *Handler) executeReview(args *model.CommandArgs, params []string) (*model.CommandResponse, error) {
+	if len(params) != 2 || !strings.EqualFold(params[0], reviewActionDispatch) {
+		return ephemeralResponse(reviewUsage), nil
+	}
+	return h.executeReviewDispatch(args, params[1]), nil
+}
+
+// executeReviewDispatch collects the current review feedback for one of the
+// user's review loops and sends it to the agent right away. A dispatch that
+// matches the last one sent is still skipped.
+func (h *Handler) executeReviewDispatch(args *model.CommandArgs, ref string) *model.CommandResponse {
+	if h.deps.DispatchReviewLoopFn == nil {
+		return ephemeralResponse("Dispatching review feedback is not available.")
+	}
+
+	var loop *kvstore.ReviewLoop
+	var err error
+	if strings.HasPrefix(ref, "http://") || strings.HasPrefix(ref, "https://") {
+		loop, err = h.deps.Store.GetReviewLoopByPRURL(ref)
+	} else {
+		loop, err = h.deps.Store.GetReviewLoop(ref)
+	}
+	if err != nil || loop == nil {
+		return ephemeralResponse(fmt.Sprintf("No review loop found for `%s`.", ref))
+	}
+	if loop.UserID != args.UserId {
+		return ephemeralResponse("You can only dispatch feedback for your own review loops.")
+	}
+	if kvstore.IsReviewPhaseTerminal(loop.Phase) {
+		return ephemeralResponse(fmt.Sprintf("The review loop for %s has already finished.", loop.PRURL))
+	}
+	if loop.Phase != kvstore.ReviewPhaseAwaitingReview && loop.Phase != kvstore.ReviewPhaseHumanReview {
+		return ephemeralResponse(fmt.Sprintf("The review loop for %s is in phase `%s`. Feedback can only be dispatched while it is waiting on reviews.", loop.PRURL, loop.Phase))
+	}
+
+	result, err := h.deps.DispatchReviewLoopFn(loop)
+	if err != nil {
+		h.deps.Client.Log.Error("Failed to dispatch review feedback on demand", "review_loop_id", loop.ID, "error", err.Error())
+		return ephemeralResponse(fmt.Sprintf("Failed to dispatch review feedback for %s. Please try again.", loop.PRURL))
+	}
+
+	counts := fmt.Sprintf("%d new, %d repeated, %d dismissed", result.New, result.Repeated, result.Dismissed)
+	switch result.Outcome {
+	case ReviewDispatchDispatched:
+		return ephemeralResponse(fmt.Sprintf("Dispatched review feedback for %s to the agent (%s).", loop.PRURL, counts))
+	case ReviewDispatchPaused:
+		return ephemeralResponse(fmt.Sprintf("Review loops are paused by an administrator. Review feedback for %s was not dispatched.", loop.PRURL))
+	case ReviewDispatchMaxIterations:
+		return ephemeralResponse(fmt.Sprintf("The review loop for %s has reached its max iterations. Review feedback was not dispatched.", loop.PRURL))
+	case ReviewDispatchFailed:
+		return ephemeralResponse(fmt.Sprintf("Dispatching review feedback for %s failed (`%s`; %s). The loop has been marked as errored.", loop.PRURL, result.Reason, counts))
+	default:
+		return ephemeralResponse(fmt.Sprintf("Skipped dispatching review feedback for %s (`%s`; %s).", loop.PRURL, result.Reason, counts))
+	}
+}
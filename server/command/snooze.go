@@ -0,0 +1,64 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/parser"
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+const snoozeUsage = "Usage: `/cursor snooze <PR URL or review loop ID> <duration|off>` (e.g. `/cursor snooze https://github.com/org/repo/pull/42 2d`)"
+
+// executeSnooze suppresses stale-review escalation for one of the user's
+// review loops until the given duration has passed, or clears the snooze.
+func (h *Handler) executeSnooze(args *model.CommandArgs, params []string) (*model.CommandResponse, error) {
+	if len(params) != 2 {
+		return ephemeralResponse(snoozeUsage), nil
+	}
+
+	var until int64
+	clearing := strings.EqualFold(params[1], "off")
+	if !clearing {
+		d, err := parser.ParseSnoozeDuration(params[1])
+		if err != nil {
+			return ephemeralResponse(fmt.Sprintf("Invalid snooze duration: %s. Use a duration like `4h` or `2d`.", err.Error())), nil
+		}
+		until = h.now().Add(d).UnixMilli()
+	}
+
+	ref := params[0]
+	var loop *kvstore.ReviewLoop
+	var err error
+	if strings.HasPrefix(ref, "http://") || strings.HasPrefix(ref, "https://") {
+		loop, err = h.deps.Store.GetReviewLoopByPRURL(ref)
+	} else {
+		loop, err = h.deps.Store.GetReviewLoop(ref)
+	}
+	if err != nil || loop == nil {
+		return ephemeralResponse(fmt.Sprintf("No review loop found for `%s`.", ref)), nil
+	}
+	if loop.UserID != args.UserId {
+		return ephemeralResponse("You can only snooze your own review loops."), nil
+	}
+	if kvstore.IsReviewPhaseTerminal(loop.Phase) {
+		return ephemeralResponse(fmt.Sprintf("The review loop for %s has already finished.", loop.PRURL)), nil
+	}
+
+	now := h.now().UnixMilli()
+	loop.SnoozeUntil = until
+	loop.StaleEscalatedAt = 0
+	loop.UpdatedAt = now
+	if err := h.deps.Store.SaveReviewLoop(loop); err != nil {
+		h.deps.Client.Log.Error("Failed to save snoozed review loop", "review_loop_id", loop.ID, "error", err.Error())
+		return ephemeralResponse("Failed to snooze the review loop. Please try again."), nil
+	}
+
+	if clearing {
+		return ephemeralResponse(fmt.Sprintf("Stale-review reminders resumed for %s.", loop.PRURL)), nil
+	}
+	return ephemeralResponse(fmt.Sprintf("Stale-review reminders for %s are snoozed until %s.", loop.PRURL, time.UnixMilli(until).UTC().Format("Jan 2 15:04 MST"))), nil
+}
@@ -0,0 +1,127 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+const transferUsage = "Usage: `/cursor transfer <agentID> @user`\nGet IDs from `/cursor list` or `/cursor status`."
+
+// isTransferCommand reports whether the fields after "/cursor transfer" look
+// like a transfer rather than a launch prompt starting with "transfer".
+func isTransferCommand(params []string) bool {
+	return len(params) == 2 && strings.HasPrefix(params[1], "@")
+}
+
+// executeTransfer hands an agent, and the HITL workflow and review loop linked
+// to it, to another user. Only the current owner or an admin of the agent's
+// channel may transfer it.
+func (h *Handler) executeTransfer(args *model.CommandArgs, params []string) (*model.CommandResponse, error) {
+	if !isTransferCommand(params) {
+		return ephemeralResponse(transferUsage), nil
+	}
+	agentID := params[0]
+	username := strings.TrimPrefix(params[1], "@")
+
+	record, err := h.deps.Store.GetAgent(agentID)
+	if err != nil || record == nil {
+		return ephemeralResponse(fmt.Sprintf("Agent `%s` not found.", agentID)), nil
+	}
+	if record.UserID != args.UserId &&
+		!h.deps.Client.User.HasPermissionToChannel(args.UserId, record.ChannelID, model.PermissionManageChannelRoles) {
+		return ephemeralResponse("You can only transfer your own agents unless you are a channel admin."), nil
+	}
+
+	newOwner, appErr := h.deps.Client.User.GetByUsername(username)
+	if appErr != nil || newOwner == nil {
+		return ephemeralResponse(fmt.Sprintf("User `@%s` not found.", username)), nil
+	}
+	if newOwner.IsBot || newOwner.DeleteAt != 0 {
+		return ephemeralResponse(fmt.Sprintf("`@%s` cannot own agents. Choose an active user.", username)), nil
+	}
+	if newOwner.Id == record.UserID {
+		return ephemeralResponse(fmt.Sprintf("`@%s` already owns agent `%s`.", username, agentID)), nil
+	}
+	// The new owner has to see the agent's thread to follow it and use its
+	// buttons.
+	if member, err := h.deps.Client.Channel.GetMember(record.ChannelID, newOwner.Id); err != nil || member == nil {
+		return ephemeralResponse(fmt.Sprintf("`@%s` is not a member of the agent's channel. Add them to the channel first.", username)), nil
+	}
+
+	previousOwnerID := record.UserID
+	now := h.now().UnixMilli()
+
+	// Collect the agent and everything linked to it: the workflow it belongs
+	// to (with the workflow's other agents) and its review loop.
+	agents := []*kvstore.AgentRecord{record}
+	var workflow *kvstore.HITLWorkflow
+	if workflowID, _ := h.deps.Store.GetWorkflowByAgent(agentID); workflowID != "" {
+		workflow, _ = h.deps.Store.GetWorkflow(workflowID)
+	}
+	if workflow != nil {
+		for _, id := range []string{workflow.PlannerAgentID, workflow.ImplementerAgentID} {
+			if id == "" || id == agentID {
+				continue
+			}
+			if sibling, _ := h.deps.Store.GetAgent(id); sibling != nil && sibling.UserID == previousOwnerID {
+				agents = append(agents, sibling)
+			}
+		}
+	}
+	loop, _ := h.deps.Store.GetReviewLoopByAgent(agentID)
+
+	for _, agent := range agents {
+		agent.UserID = newOwner.Id
+		agent.UpdatedAt = now
+		if err := h.deps.Store.SaveAgent(agent); err != nil {
+			h.deps.Client.Log.Error("Failed to transfer agent", "agent_id", agent.CursorAgentID, "error", err.Error())
+			return ephemeralResponse("Failed to transfer the agent. Please try again."), nil
+		}
+		if err := h.deps.Store.RemoveAgentFromUser(previousOwnerID, agent.CursorAgentID); err != nil {
+			h.deps.Client.Log.Warn("Failed to drop previous owner's agent index", "agent_id", agent.CursorAgentID, "error", err.Error())
+		}
+	}
+	if workflow != nil && workflow.UserID == previousOwnerID {
+		workflow.UserID = newOwner.Id
+		workflow.UpdatedAt = now
+		if err := h.deps.Store.SaveWorkflow(workflow); err != nil {
+			h.deps.Client.Log.Error("Failed to transfer workflow", "workflow_id", workflow.ID, "error", err.Error())
+		}
+	}
+	if loop != nil && loop.UserID == previousOwnerID {
+		loop.UserID = newOwner.Id
+		loop.UpdatedAt = now
+		if err := h.deps.Store.SaveReviewLoop(loop); err != nil {
+			h.deps.Client.Log.Error("Failed to transfer review loop", "review_loop_id", loop.ID, "error", err.Error())
+		}
+	}
+
+	if h.deps.OwnershipTransferredFn != nil {
+		h.deps.OwnershipTransferredFn(record)
+	}
+
+	if record.PostID != "" {
+		_ = h.deps.Client.Post.CreatePost(h.decorateBotPost(&model.Post{
+			UserId:    h.botUserID(record.ChannelID),
+			ChannelId: record.ChannelID,
+			RootId:    record.PostID,
+			Message: fmt.Sprintf(":arrows_counterclockwise: Ownership of agent `%s` was transferred from %s to @%s by %s.",
+				agentID, h.mentionUser(previousOwnerID), newOwner.Username, h.mentionUser(args.UserId)),
+		}))
+	}
+
+	return ephemeralResponse(fmt.Sprintf("Agent `%s` now belongs to @%s.", agentID, newOwner.Username)), nil
+}
+
+// mentionUser returns an @mention for userID, falling back to the raw ID when
+// the user cannot be loaded.
+func (h *Handler) mentionUser(userID string) string {
+	if user, err := h.deps.Client.User.Get(userID); err == nil && user != nil {
+		return "@" + user.Username
+	}
+	return fmt.Sprintf("`%s`", userID)
+}
@@ -0,0 +1,102 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/cursor"
+)
+
+// IntegrationStatus reports which optional integrations are configured. It
+// only carries booleans so no secret can end up in command output.
+type IntegrationStatus struct {
+	GitHubPATConfigured     bool
+	WebhookSecretConfigured bool
+	ReviewLoopEnabled       bool
+}
+
+// executeWhoami shows the Cursor account behind the configured API key and
+// which GitHub integrations are set up, to help debug authentication issues.
+func (h *Handler) executeWhoami(_ *model.CommandArgs) (*model.CommandResponse, error) {
+	var sb strings.Builder
+	sb.WriteString("#### Cursor Identity\n\n")
+	sb.WriteString(h.formatCursorIdentity())
+
+	if h.deps.IntegrationStatusFn != nil {
+		status := h.deps.IntegrationStatusFn()
+		sb.WriteString("\n\n#### Integrations\n\n")
+		sb.WriteString(fmt.Sprintf("- GitHub token: %s\n", formatConfigured(status.GitHubPATConfigured)))
+		sb.WriteString(fmt.Sprintf("- GitHub webhook secret: %s\n", formatConfigured(status.WebhookSecretConfigured)))
+		sb.WriteString(fmt.Sprintf("- AI review loop: %s", formatEnabled(status.ReviewLoopEnabled)))
+	}
+
+	return ephemeralResponse(sb.String()), nil
+}
+
+// formatCursorIdentity renders the API key details returned by the Cursor
+// API, or why they could not be fetched.
+func (h *Handler) formatCursorIdentity() string {
+	cursorClient := h.deps.CursorClientFn()
+	if cursorClient == nil {
+		return errNoCursorClient
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	info, err := cursorClient.GetMe(ctx)
+	if err != nil {
+		if cursor.IsRateLimited(err) {
+			return ":warning: The Cursor API is rate limiting this API key. Try again in a few minutes."
+		}
+		return formatAPIError("Failed to fetch the Cursor API key details", err)
+	}
+	if info == nil {
+		return "The Cursor API returned no details for this API key."
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("- Account: %s\n", valueOrUnknown(info.UserEmail)))
+	sb.WriteString(fmt.Sprintf("- API key: %s\n", valueOrUnknown(info.APIKeyName)))
+	sb.WriteString(fmt.Sprintf("- Key created: %s\n", formatKeyCreatedAt(info.CreatedAt)))
+	sb.WriteString("\n_The Cursor API does not report key scopes or usage quotas._")
+	return sb.String()
+}
+
+// formatKeyCreatedAt renders an RFC 3339 timestamp as a date, falling back to
+// the raw value when it cannot be parsed.
+func formatKeyCreatedAt(createdAt string) string {
+	if createdAt == "" {
+		return "unknown"
+	}
+	t, err := time.Parse(time.RFC3339, createdAt)
+	if err != nil {
+		return createdAt
+	}
+	return t.UTC().Format("Jan 2, 2006")
+}
+
+func valueOrUnknown(value string) string {
+	if strings.TrimSpace(value) == "" {
+		return "unknown"
+	}
+	return fmt.Sprintf("`%s`", value)
+}
+
+func formatConfigured(configured bool) string {
+	if configured {
+		return ":white_check_mark: configured"
+	}
+	return ":x: not configured"
+}
+
+func formatEnabled(enabled bool) string {
+	if enabled {
+		return ":white_check_mark: enabled"
+	}
+	return "disabled"
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// missingRequiredSettings lists the settings an enabled feature depends on
+// that are left empty, one human-readable line per setting naming the
+// features that need it.
+func (c *configuration) missingRequiredSettings() []string {
+	var missing []string
+
+	if c.EnableAIReviewLoop && strings.TrimSpace(c.GitHubWebhookSecret) == "" {
+		missing = append(missing,
+			"**GitHub Webhook Secret**: the AI review loop needs it to accept GitHub webhooks, which are rejected until it is set")
+	}
+
+	var needsPAT []string
+	if c.EnableAIReviewLoop {
+		needsPAT = append(needsPAT, "the AI review loop (requesting AI reviewers)")
+	}
+	if c.PostApprovedPlanToPR || c.PlanAsPRChecklist {
+		needsPAT = append(needsPAT, "posting approved plans to PRs")
+	}
+	if len(needsPAT) > 0 && strings.TrimSpace(c.GitHubPAT) == "" {
+		missing = append(missing, fmt.Sprintf("**GitHub Personal Access Token**: needed by %s", strings.Join(needsPAT, " and ")))
+	}
+
+	return missing
+}
+
+// checkRequiredSettings warns when enabled features are missing settings they
+// depend on, so the problem is visible in Mattermost rather than only in
+// GitHub's webhook delivery log. It logs a warning and sends system admins a
+// direct message listing what is missing.
+func (p *Plugin) checkRequiredSettings() {
+	missing := p.getConfiguration().missingRequiredSettings()
+	if len(missing) == 0 {
+		return
+	}
+
+	p.API.LogWarn("Cursor plugin is missing settings required by enabled features",
+		"missing", strings.Join(missing, "; "),
+	)
+
+	var message strings.Builder
+	message.WriteString("The Cursor plugin is missing settings required by enabled features:\n")
+	for _, line := range missing {
+		message.WriteString("\n- " + line)
+	}
+	message.WriteString("\n\nSet them in **System Console > Plugins > Cursor**.")
+	p.notifySystemAdmins("missing settings", message.String())
+}
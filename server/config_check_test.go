@@ -0,0 +1,99 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMissingRequiredSettings(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   configuration
+		expected []string
+	}{
+		{
+			name:   "nothing enabled",
+			config: configuration{},
+		},
+		{
+			name:   "review loop fully configured",
+			config: configuration{EnableAIReviewLoop: true, GitHubWebhookSecret: "secret", GitHubPAT: "ghp_test"},
+		},
+		{
+			name:     "review loop without webhook secret",
+			config:   configuration{EnableAIReviewLoop: true, GitHubPAT: "ghp_test"},
+			expected: []string{"**GitHub Webhook Secret**"},
+		},
+		{
+			name:     "review loop without PAT",
+			config:   configuration{EnableAIReviewLoop: true, GitHubWebhookSecret: "secret"},
+			expected: []string{"**GitHub Personal Access Token**: needed by the AI review loop"},
+		},
+		{
+			name:     "review loop without either",
+			config:   configuration{EnableAIReviewLoop: true, GitHubWebhookSecret: "  "},
+			expected: []string{"**GitHub Webhook Secret**", "**GitHub Personal Access Token**"},
+		},
+		{
+			name:     "plan posting without PAT",
+			config:   configuration{PostApprovedPlanToPR: true},
+			expected: []string{"**GitHub Personal Access Token**: needed by posting approved plans to PRs"},
+		},
+		{
+			name:     "plan checklist without PAT",
+			config:   configuration{PlanAsPRChecklist: true},
+			expected: []string{"**GitHub Personal Access Token**: needed by posting approved plans to PRs"},
+		},
+		{
+			name:     "PAT listed once for every feature needing it",
+			config:   configuration{EnableAIReviewLoop: true, GitHubWebhookSecret: "secret", PostApprovedPlanToPR: true},
+			expected: []string{"needed by the AI review loop (requesting AI reviewers) and posting approved plans to PRs"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			missing := tt.config.missingRequiredSettings()
+			require.Len(t, missing, len(tt.expected))
+			for i, want := range tt.expected {
+				assert.Contains(t, missing[i], want)
+			}
+		})
+	}
+}
+
+func TestCheckRequiredSettings_NotifiesAdmins(t *testing.T) {
+	p, api, _, _ := setupTestPlugin(t)
+	p.configuration = &configuration{EnableAIReviewLoop: true}
+
+	api.On("GetUsers", mock.MatchedBy(func(opts *model.UserGetOptions) bool {
+		return opts.Role == model.SystemAdminRoleId && opts.Active
+	})).Return([]*model.User{{Id: "admin-1"}, {Id: "admin-2"}}, nil).Once()
+	api.On("GetDirectChannel", mock.Anything, "admin-1").Return(&model.Channel{Id: "dm-admin-1"}, nil).Once()
+	api.On("GetDirectChannel", mock.Anything, "admin-2").Return(&model.Channel{Id: "dm-admin-2"}, nil).Once()
+	api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		return strings.HasPrefix(post.ChannelId, "dm-admin-") &&
+			strings.Contains(post.Message, "GitHub Webhook Secret") &&
+			strings.Contains(post.Message, "GitHub Personal Access Token")
+	})).Return(&model.Post{Id: "notice"}, nil).Twice()
+
+	p.checkRequiredSettings()
+
+	api.AssertExpectations(t)
+	api.AssertCalled(t, "LogWarn", "Cursor plugin is missing settings required by enabled features", "missing", mock.Anything)
+}
+
+func TestCheckRequiredSettings_SilentWhenConfigured(t *testing.T) {
+	p, api, _, _ := setupTestPlugin(t)
+	p.configuration = &configuration{EnableAIReviewLoop: true, GitHubWebhookSecret: "secret", GitHubPAT: "ghp_test"}
+
+	p.checkRequiredSettings()
+
+	api.AssertNotCalled(t, "GetUsers", mock.Anything)
+	api.AssertNotCalled(t, "LogWarn", "Cursor plugin is missing settings required by enabled features", "missing", mock.Anything)
+}
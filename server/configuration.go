@@ -3,10 +3,14 @@ package main
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"reflect"
+	"regexp"
 	"strings"
 	"time"
 
+	"github.com/mattermost/mattermost/server/public/model"
+
 	"github.com/mattermost/mattermost-plugin-cursor/server/cursor"
 	"github.com/mattermost/mattermost-plugin-cursor/server/ghclient"
 )
@@ -15,25 +19,82 @@ import (
 // configuration, as well as values computed from the configuration. Any public fields will be
 // deserialized from the Mattermost server configuration in OnConfigurationChange.
 type configuration struct {
-	CursorAPIKey            string `json:"CursorAPIKey"`
-	DefaultRepository       string `json:"DefaultRepository"`
-	DefaultBranch           string `json:"DefaultBranch"`
-	DefaultModel            string `json:"DefaultModel"`
-	AutoCreatePR            bool   `json:"AutoCreatePR"`
-	PollIntervalSeconds     int    `json:"PollIntervalSeconds"`
-	GitHubWebhookSecret     string `json:"GitHubWebhookSecret"`
-	CursorAgentSystemPrompt string `json:"CursorAgentSystemPrompt"`
-	EnableDebugLogging      bool   `json:"EnableDebugLogging"`
-	EnableContextReview     bool   `json:"EnableContextReview"`
-	EnablePlanLoop          bool   `json:"EnablePlanLoop"`
-	PlannerSystemPrompt     string `json:"PlannerSystemPrompt"`
+	CursorAPIKey             string `json:"CursorAPIKey"`
+	DefaultRepository        string `json:"DefaultRepository"`
+	UseChannelLinkedRepo     bool   `json:"UseChannelLinkedRepo"`
+	DefaultBranch            string `json:"DefaultBranch"`
+	DefaultModel             string `json:"DefaultModel"`
+	AllowedModels            string `json:"AllowedModels"`
+	AutoCreatePR             bool   `json:"AutoCreatePR"`
+	PollIntervalSeconds      int    `json:"PollIntervalSeconds"`
+	ProgressUpdateSeconds    int    `json:"ProgressUpdateSeconds"`
+	GitHubWebhookSecret      string `json:"GitHubWebhookSecret"`
+	WebhookMaxBodySizeKB     int    `json:"WebhookMaxBodySizeKB"`
+	MutedPRAuthors           string `json:"MutedPRAuthors"`
+	NotificationStyles       string `json:"NotificationStyles"`
+	CursorAgentSystemPrompt  string `json:"CursorAgentSystemPrompt"`
+	SecretRedactionPatterns  string `json:"SecretRedactionPatterns"`
+	EnableDebugLogging       bool   `json:"EnableDebugLogging"`
+	RecordWebhookDeliveries  bool   `json:"RecordWebhookDeliveries"`
+	EnableContextReview      bool   `json:"EnableContextReview"`
+	EnablePlanLoop           bool   `json:"EnablePlanLoop"`
+	PlannerSystemPrompt      string `json:"PlannerSystemPrompt"`
+	WorkflowRetentionDays    int    `json:"WorkflowRetentionDays"`
+	PostApprovedPlanToPR     bool   `json:"PostApprovedPlanToPR"`
+	PostAgentSummaryOnFinish bool   `json:"PostAgentSummaryOnFinish"`
+	PlanAsPRChecklist        bool   `json:"PlanAsPRChecklist"`
+	SlackWebhookURL          string `json:"SlackWebhookURL"`
+	PhaseWebhookURLs         string `json:"PhaseWebhookURLs"`
+	PhaseWebhookSecret       string `json:"PhaseWebhookSecret"`
+	AdditionalBotIdentities  string `json:"AdditionalBotIdentities"`
+	BotPostPrefix            string `json:"BotPostPrefix"`
 
 	// --- AI Review Loop settings ---
-	GitHubPAT           string `json:"GitHubPAT"`
-	EnableAIReviewLoop  bool   `json:"EnableAIReviewLoop"`
-	MaxReviewIterations int    `json:"MaxReviewIterations"`
-	AIReviewerBots      string `json:"AIReviewerBots"`
-	HumanReviewTeam     string `json:"HumanReviewTeam"`
+	GitHubPAT                           string `json:"GitHubPAT"`
+	GitHubRepoMappings                  string `json:"GitHubRepoMappings"`
+	EnableAIReviewLoop                  bool   `json:"EnableAIReviewLoop"`
+	MaxReviewIterations                 int    `json:"MaxReviewIterations"`
+	ReviewIterationWarning              int    `json:"ReviewIterationWarning"`
+	ReviewMinimumSeverity               string `json:"ReviewMinimumSeverity"`
+	AIReviewerBots                      string `json:"AIReviewerBots"`
+	AIReviewerBotPaths                  string `json:"AIReviewerBotPaths"`
+	ReviewLoopWarmupComment             string `json:"ReviewLoopWarmupComment"`
+	ReviewLoopIgnorePaths               string `json:"ReviewLoopIgnorePaths"`
+	ReviewLoopDropUnchangedLineFindings bool   `json:"ReviewLoopDropUnchangedLineFindings"`
+	MinActionableTextLength             int    `json:"MinActionableTextLength"`
+	ReviewLoopReRequestOnSynchronize    bool   `json:"ReviewLoopReRequestOnSynchronize"`
+	ReviewLoopDispatchInlineOnlyReviews bool   `json:"ReviewLoopDispatchInlineOnlyReviews"`
+	ReviewLoopReopenOnAIFindings        bool   `json:"ReviewLoopReopenOnAIFindings"`
+	TerminalReactionDelayMs             int    `json:"TerminalReactionDelayMs"`
+	AIReviewerPriority                  string `json:"AIReviewerPriority"`
+	AIReviewerPriorityExclusive         bool   `json:"AIReviewerPriorityExclusive"`
+	ReviewLoopQuietHours                string `json:"ReviewLoopQuietHours"`
+	ReviewLoopQuietHoursTimezone        string `json:"ReviewLoopQuietHoursTimezone"`
+	ReviewLoopStaleHours                int    `json:"ReviewLoopStaleHours"`
+	ReviewLoopNudgeAfterMinutes         int    `json:"ReviewLoopNudgeAfterMinutes"`
+	ReviewLoopNudgeComment              string `json:"ReviewLoopNudgeComment"`
+	ReviewLoopMaxNudges                 int    `json:"ReviewLoopMaxNudges"`
+	ReviewLoopMaxLifetimeHours          int    `json:"ReviewLoopMaxLifetimeHours"`
+	ReviewLoopIdempotencyWindowMinutes  int    `json:"ReviewLoopIdempotencyWindowMinutes"`
+	ReviewLoopDigestChannelID           string `json:"ReviewLoopDigestChannelID"`
+	ReviewLoopDigestTime                string `json:"ReviewLoopDigestTime"`
+	ReviewLoopIncludeOriginalPrompt     bool   `json:"ReviewLoopIncludeOriginalPrompt"`
+	SanitizeReviewFeedback              bool   `json:"SanitizeReviewFeedback"`
+	MaxFindingsPerIteration             int    `json:"MaxFindingsPerIteration"`
+	MaxFindingTextLength                int    `json:"MaxFindingTextLength"`
+	FeedbackCollectionConcurrency       int    `json:"FeedbackCollectionConcurrency"`
+	ReviewFollowupGroupBy               string `json:"ReviewFollowupGroupBy"`
+	HumanReviewTeam                     string `json:"HumanReviewTeam"`
+	DisableHumanReviewFixing            bool   `json:"DisableHumanReviewFixing"`
+	ReviewLoopRequireAIGate             bool   `json:"ReviewLoopRequireAIGate"`
+	ResolveThreadsOnFix                 bool   `json:"ResolveThreadsOnFix"`
+	AckFindingsOnDispatch               bool   `json:"AckFindingsOnDispatch"`
+	AutoMergeOnApproval                 bool   `json:"AutoMergeOnApproval"`
+	AutoMergeMethod                     string `json:"AutoMergeMethod"`
+	ReviewLoopStartForExtraPRs          bool   `json:"ReviewLoopStartForExtraPRs"`
+	ReviewLoopSkipDraftPRs              bool   `json:"ReviewLoopSkipDraftPRs"`
+	ReviewLoopSyncBaseOnEdit            bool   `json:"ReviewLoopSyncBaseOnEdit"`
+	ReviewLoopGloballyPaused            bool   `json:"ReviewLoopGloballyPaused"`
 }
 
 // Clone shallow copies the configuration.
@@ -54,12 +115,29 @@ func (c *configuration) IsValid() error {
 
 	// Validate DefaultRepository format if set.
 	if c.DefaultRepository != "" {
-		parts := strings.Split(c.DefaultRepository, "/")
-		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
-			return fmt.Errorf("default Repository must be in 'owner/repo' format, got %q", c.DefaultRepository)
+		if _, err := ghclient.ParseRepoRef(c.DefaultRepository); err != nil {
+			return fmt.Errorf("default Repository must be in 'owner/repo' format or a GitHub repository URL, got %q: %w", c.DefaultRepository, err)
 		}
 	}
 
+	if webhookURL := strings.TrimSpace(c.SlackWebhookURL); webhookURL != "" {
+		if u, err := url.Parse(webhookURL); err != nil || u.Scheme != "https" || u.Host == "" {
+			return fmt.Errorf("slack Webhook URL must be an https URL")
+		}
+	}
+
+	if _, err := parsePhaseWebhookURLs(c.PhaseWebhookURLs); err != nil {
+		return err
+	}
+
+	if _, err := parseSecretRedactionPatterns(c.SecretRedactionPatterns); err != nil {
+		return err
+	}
+
+	if _, err := parseNotificationStyles(c.NotificationStyles); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -71,6 +149,145 @@ func (c *configuration) GetPollInterval() int {
 	return c.PollIntervalSeconds
 }
 
+// defaultWebhookMaxBodySizeKB is the webhook body limit applied when the
+// setting is unset or invalid. GitHub caps payloads at 25 MB, but review and
+// pull_request events are far smaller in practice.
+const defaultWebhookMaxBodySizeKB = 5 * 1024
+
+// GetProgressUpdateInterval returns how often a running agent's launch card is
+// refreshed with its latest activity. Zero disables live progress updates.
+// Updates happen on status polls, so intervals shorter than the poll interval
+// behave like the poll interval.
+func (c *configuration) GetProgressUpdateInterval() time.Duration {
+	if c.ProgressUpdateSeconds <= 0 {
+		return 0
+	}
+	return time.Duration(c.ProgressUpdateSeconds) * time.Second
+}
+
+// GetMaxWebhookBodySize returns the webhook body size limit in bytes,
+// defaulting to 5 MB if unset or non-positive.
+func (c *configuration) GetMaxWebhookBodySize() int64 {
+	if c.WebhookMaxBodySizeKB <= 0 {
+		return defaultWebhookMaxBodySizeKB * 1024
+	}
+	return int64(c.WebhookMaxBodySizeKB) * 1024
+}
+
+// maxTerminalReactionDelay caps the terminal reaction debounce so a typo in
+// the setting cannot leave the trigger post stale for minutes.
+const maxTerminalReactionDelay = 30 * time.Second
+
+// GetTerminalReactionDelay returns how long to wait for events to settle
+// before swapping the trigger post's terminal reaction. Zero means swap
+// immediately.
+func (c *configuration) GetTerminalReactionDelay() time.Duration {
+	if c.TerminalReactionDelayMs <= 0 {
+		return 0
+	}
+	delay := time.Duration(c.TerminalReactionDelayMs) * time.Millisecond
+	if delay > maxTerminalReactionDelay {
+		return maxTerminalReactionDelay
+	}
+	return delay
+}
+
+// GetBotPostPrefix returns the configured bot post prefix, trimmed.
+func (c *configuration) GetBotPostPrefix() string {
+	return strings.TrimSpace(c.BotPostPrefix)
+}
+
+// botIdentitySpec is an additional bot account configured in
+// AdditionalBotIdentities.
+type botIdentitySpec struct {
+	Username    string
+	DisplayName string
+}
+
+// ParseBotIdentities parses AdditionalBotIdentities, a comma-separated list of
+// "username" or "username:Display Name" entries. Invalid usernames, duplicates,
+// and the default bot username are skipped.
+func (c *configuration) ParseBotIdentities() []botIdentitySpec {
+	if strings.TrimSpace(c.AdditionalBotIdentities) == "" {
+		return nil
+	}
+	seen := map[string]bool{botUsername: true}
+	var identities []botIdentitySpec
+	for _, entry := range strings.Split(c.AdditionalBotIdentities, ",") {
+		username, displayName, _ := strings.Cut(entry, ":")
+		username = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(username), "@"))
+		if !model.IsValidUsername(username) || seen[username] {
+			continue
+		}
+		seen[username] = true
+		displayName = strings.TrimSpace(displayName)
+		if displayName == "" {
+			displayName = username
+		}
+		identities = append(identities, botIdentitySpec{Username: username, DisplayName: displayName})
+	}
+	return identities
+}
+
+// IsMutedPRAuthor reports whether GitHub notifications for pull requests by
+// login are muted via MutedPRAuthors. Matching is case-insensitive and ignores
+// the "[bot]" suffix GitHub adds to app accounts, so "dependabot" matches
+// "dependabot[bot]".
+func (c *configuration) IsMutedPRAuthor(login string) bool {
+	login = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(login)), "[bot]")
+	if login == "" {
+		return false
+	}
+	for _, entry := range strings.Split(c.MutedPRAuthors, ",") {
+		entry = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(entry)), "[bot]")
+		if entry == login {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseAllowedModels splits the AllowedModels config string into model names.
+// An empty result means every model may be selected.
+func (c *configuration) ParseAllowedModels() []string {
+	var models []string
+	for _, p := range strings.Split(c.AllowedModels, ",") {
+		if trimmed := strings.TrimSpace(p); trimmed != "" {
+			models = append(models, trimmed)
+		}
+	}
+	return models
+}
+
+// IsModelAllowed reports whether users may launch agents with modelName.
+// Matching is case-insensitive; every model is allowed when no allowlist is set.
+func (c *configuration) IsModelAllowed(modelName string) bool {
+	allowed := c.ParseAllowedModels()
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, m := range allowed {
+		if strings.EqualFold(m, modelName) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseSecretRedactionPatterns returns the compiled extra patterns from
+// SecretRedactionPatterns. Invalid lines are skipped; IsValid reports them.
+func (c *configuration) ParseSecretRedactionPatterns() []*regexp.Regexp {
+	patterns, _ := parseSecretRedactionPatterns(c.SecretRedactionPatterns)
+	return patterns
+}
+
+// GetPhaseWebhookURLs returns the parsed PhaseWebhookURLs. Invalid values
+// yield no URLs; IsValid reports them.
+func (c *configuration) GetPhaseWebhookURLs() []string {
+	urls, _ := parsePhaseWebhookURLs(c.PhaseWebhookURLs)
+	return urls
+}
+
 // ParseAIReviewerBots splits the AIReviewerBots config string into individual
 // bot usernames, trimming whitespace and filtering empties.
 func (c *configuration) ParseAIReviewerBots() []string {
@@ -88,6 +305,209 @@ func (c *configuration) ParseAIReviewerBots() []string {
 	return bots
 }
 
+// ParseReviewLoopIgnorePaths returns the globs from ReviewLoopIgnorePaths, or
+// nil when none are configured.
+func (c *configuration) ParseReviewLoopIgnorePaths() []string {
+	return parseReviewIgnorePaths(c.ReviewLoopIgnorePaths)
+}
+
+// ParseAIReviewerBotPaths returns the path scopes of AI reviewer bots from
+// AIReviewerBotPaths, or nil when none are configured.
+func (c *configuration) ParseAIReviewerBotPaths() []reviewerBotPathScope {
+	if strings.TrimSpace(c.AIReviewerBotPaths) == "" {
+		return nil
+	}
+	return parseReviewerBotPathScopes(c.AIReviewerBotPaths)
+}
+
+// ParseAIReviewerPriority splits the AIReviewerPriority config string into
+// reviewer logins ordered from highest to lowest priority.
+func (c *configuration) ParseAIReviewerPriority() []string {
+	if c.AIReviewerPriority == "" {
+		return nil
+	}
+	var logins []string
+	for _, p := range strings.Split(c.AIReviewerPriority, ",") {
+		trimmed := strings.TrimSpace(p)
+		if trimmed != "" {
+			logins = append(logins, trimmed)
+		}
+	}
+	return logins
+}
+
+// GetQuietHours returns the parsed review loop quiet hours window, or nil
+// when quiet hours are not configured or invalid.
+func (c *configuration) GetQuietHours() *quietHours {
+	if strings.TrimSpace(c.ReviewLoopQuietHours) == "" {
+		return nil
+	}
+	window, err := parseQuietHours(c.ReviewLoopQuietHours, c.ReviewLoopQuietHoursTimezone)
+	if err != nil {
+		return nil
+	}
+	return window
+}
+
+// GetReviewLoopDigestSchedule returns the daily review loop digest schedule,
+// interpreted in the quiet hours timezone, or nil when no digest channel is
+// set or the time is invalid.
+func (c *configuration) GetReviewLoopDigestSchedule() *reviewLoopDigestSchedule {
+	if strings.TrimSpace(c.ReviewLoopDigestChannelID) == "" {
+		return nil
+	}
+	clock := c.ReviewLoopDigestTime
+	if strings.TrimSpace(clock) == "" {
+		clock = defaultReviewLoopDigestTime
+	}
+	schedule, err := parseReviewLoopDigestSchedule(c.ReviewLoopDigestChannelID, clock, c.ReviewLoopQuietHoursTimezone)
+	if err != nil {
+		return nil
+	}
+	return schedule
+}
+
+// GetReviewLoopStaleAfter returns how long a review loop may wait on
+// reviewers before it is escalated. Zero disables stale escalation.
+func (c *configuration) GetReviewLoopStaleAfter() time.Duration {
+	if c.ReviewLoopStaleHours <= 0 {
+		return 0
+	}
+	return time.Duration(c.ReviewLoopStaleHours) * time.Hour
+}
+
+// GetReviewLoopNudgeAfter returns how long a review loop may wait on AI
+// reviewers before they are nudged with a PR comment. It is also the cooldown
+// between nudges. Zero disables nudging.
+func (c *configuration) GetReviewLoopNudgeAfter() time.Duration {
+	if c.ReviewLoopNudgeAfterMinutes <= 0 {
+		return 0
+	}
+	return time.Duration(c.ReviewLoopNudgeAfterMinutes) * time.Minute
+}
+
+// Nudge defaults applied when the settings are unset.
+const (
+	defaultReviewLoopNudgeComment = "@coderabbitai review"
+	defaultReviewLoopMaxNudges    = 3
+)
+
+// GetReviewLoopNudgeComment returns the PR comment posted to nudge AI
+// reviewers, defaulting to a CodeRabbit review request.
+func (c *configuration) GetReviewLoopNudgeComment() string {
+	if comment := strings.TrimSpace(c.ReviewLoopNudgeComment); comment != "" {
+		return comment
+	}
+	return defaultReviewLoopNudgeComment
+}
+
+// GetReviewLoopMaxNudges returns how many times a single review loop may
+// nudge its AI reviewers.
+func (c *configuration) GetReviewLoopMaxNudges() int {
+	if c.ReviewLoopMaxNudges <= 0 {
+		return defaultReviewLoopMaxNudges
+	}
+	return c.ReviewLoopMaxNudges
+}
+
+// GetReviewLoopMaxLifetime returns how long a review loop may run, measured
+// from its creation, before it is stalled. Zero disables the limit.
+func (c *configuration) GetReviewLoopMaxLifetime() time.Duration {
+	if c.ReviewLoopMaxLifetimeHours <= 0 {
+		return 0
+	}
+	return time.Duration(c.ReviewLoopMaxLifetimeHours) * time.Hour
+}
+
+// GetReviewLoopIdempotencyWindowSeconds returns the idempotency window given
+// to new review loops, in seconds. Zero disables the window.
+func (c *configuration) GetReviewLoopIdempotencyWindowSeconds() int {
+	if c.ReviewLoopIdempotencyWindowMinutes <= 0 {
+		return 0
+	}
+	return c.ReviewLoopIdempotencyWindowMinutes * 60
+}
+
+// GetWorkflowRetention returns how long a rejected or abandoned HITL
+// workflow is kept before it is deleted. Zero keeps workflows forever.
+func (c *configuration) GetWorkflowRetention() time.Duration {
+	if c.WorkflowRetentionDays <= 0 {
+		return 0
+	}
+	return time.Duration(c.WorkflowRetentionDays) * 24 * time.Hour
+}
+
+// GetMaxFindingsPerIteration returns the most findings sent to the agent in
+// a single follow-up. Zero means no limit.
+func (c *configuration) GetMaxFindingsPerIteration() int {
+	if c.MaxFindingsPerIteration <= 0 {
+		return 0
+	}
+	return c.MaxFindingsPerIteration
+}
+
+// feedbackCollectionLists is the number of GitHub lists fetched when
+// collecting review feedback: review comments, reviews, and issue comments.
+const feedbackCollectionLists = 3
+
+// GetFeedbackCollectionConcurrency returns how many of the GitHub lists read
+// during feedback collection are fetched at once. Defaults to all of them; 1
+// fetches them one after another.
+func (c *configuration) GetFeedbackCollectionConcurrency() int {
+	if c.FeedbackCollectionConcurrency <= 0 || c.FeedbackCollectionConcurrency > feedbackCollectionLists {
+		return feedbackCollectionLists
+	}
+	return c.FeedbackCollectionConcurrency
+}
+
+// GetMaxFindingTextLength returns the most characters of a single finding's
+// text sent to the agent. Zero means no limit.
+func (c *configuration) GetMaxFindingTextLength() int {
+	if c.MaxFindingTextLength <= 0 {
+		return 0
+	}
+	return c.MaxFindingTextLength
+}
+
+// GetReviewFollowupGroupBy returns how review follow-up findings are grouped
+// ("file", "reviewer", or "severity"), or "" for a flat list.
+func (c *configuration) GetReviewFollowupGroupBy() string {
+	switch groupBy := strings.ToLower(strings.TrimSpace(c.ReviewFollowupGroupBy)); groupBy {
+	case followupGroupByFile, followupGroupByReviewer, followupGroupBySeverity:
+		return groupBy
+	default:
+		return ""
+	}
+}
+
+// Merge methods accepted by AutoMergeMethod.
+const (
+	mergeMethodMerge  = "merge"
+	mergeMethodSquash = "squash"
+	mergeMethodRebase = "rebase"
+)
+
+// GetAutoMergeMethod returns the GitHub merge method used to auto-merge
+// approved PRs. Defaults to squash.
+func (c *configuration) GetAutoMergeMethod() string {
+	switch method := strings.ToLower(strings.TrimSpace(c.AutoMergeMethod)); method {
+	case mergeMethodMerge, mergeMethodRebase:
+		return method
+	default:
+		return mergeMethodSquash
+	}
+}
+
+// GetGitHubRepoMappings returns the parsed PR URL to owner/repo mappings, or
+// nil when none are configured or the setting is invalid.
+func (c *configuration) GetGitHubRepoMappings() []ghclient.RepoMapping {
+	mappings, err := ghclient.ParseRepoMappings(c.GitHubRepoMappings)
+	if err != nil {
+		return nil
+	}
+	return mappings
+}
+
 // getConfiguration retrieves the active configuration under lock, making it safe to use
 // concurrently. The active configuration may change underneath the client of this method, but
 // the struct returned by this API call is considered immutable.
@@ -136,6 +556,9 @@ func (p *Plugin) OnConfigurationChange() error {
 	if cfg.PollIntervalSeconds == 0 {
 		cfg.PollIntervalSeconds = 30
 	}
+	if cfg.WebhookMaxBodySizeKB <= 0 {
+		cfg.WebhookMaxBodySizeKB = defaultWebhookMaxBodySizeKB
+	}
 	if cfg.MaxReviewIterations == 0 {
 		cfg.MaxReviewIterations = 5
 	}
@@ -145,9 +568,46 @@ func (p *Plugin) OnConfigurationChange() error {
 	if cfg.MaxReviewIterations > 20 {
 		cfg.MaxReviewIterations = 20
 	}
+	if cfg.ReviewIterationWarning < 0 {
+		cfg.ReviewIterationWarning = 0
+	}
+	if cfg.ReviewIterationWarning >= cfg.MaxReviewIterations {
+		if cfg.ReviewIterationWarning > 0 {
+			p.API.LogWarn("ReviewIterationWarning must be below MaxReviewIterations; disabling iteration warning",
+				"warning_threshold", fmt.Sprintf("%d", cfg.ReviewIterationWarning),
+				"max_iterations", fmt.Sprintf("%d", cfg.MaxReviewIterations),
+			)
+		}
+		cfg.ReviewIterationWarning = 0
+	}
 	if cfg.AIReviewerBots == "" {
 		cfg.AIReviewerBots = "coderabbitai[bot],copilot-pull-request-reviewer"
 	}
+	if cfg.ReviewMinimumSeverity == "" {
+		cfg.ReviewMinimumSeverity = findingSeverityNit
+	}
+	if findingSeverityRank(cfg.ReviewMinimumSeverity) == 0 {
+		p.API.LogWarn("Unknown ReviewMinimumSeverity; falling back to nit",
+			"value", cfg.ReviewMinimumSeverity,
+		)
+		cfg.ReviewMinimumSeverity = findingSeverityNit
+	}
+	if cfg.ReviewLoopQuietHours != "" {
+		if _, err := parseQuietHours(cfg.ReviewLoopQuietHours, cfg.ReviewLoopQuietHoursTimezone); err != nil {
+			p.API.LogWarn("Invalid ReviewLoopQuietHours; quiet hours disabled",
+				"value", cfg.ReviewLoopQuietHours,
+				"error", err.Error(),
+			)
+			cfg.ReviewLoopQuietHours = ""
+		}
+	}
+
+	if _, err := ghclient.ParseRepoMappings(cfg.GitHubRepoMappings); err != nil {
+		p.API.LogWarn("Invalid GitHubRepoMappings; PR URLs will be parsed as-is",
+			"error", err.Error(),
+		)
+		cfg.GitHubRepoMappings = ""
+	}
 
 	// Validate the configuration.
 	if err := cfg.IsValid(); err != nil {
@@ -180,14 +640,19 @@ func (p *Plugin) OnConfigurationChange() error {
 
 	// Re-initialize the Cursor client with the new API key if the plugin is activated.
 	if cfg.CursorAPIKey != "" && p.client != nil {
-		p.setCursorClient(cursor.NewClient(cfg.CursorAPIKey, cursor.WithLogger(&pluginLogger{plugin: p})))
+		p.setCursorClient(p.newCursorClient(cfg.CursorAPIKey))
 	} else {
 		p.setCursorClient(nil)
 	}
 
+	// Create any newly configured bot identities if the plugin is activated.
+	if p.client != nil {
+		p.ensureBotIdentities()
+	}
+
 	// Re-initialize the GitHub client with the new PAT.
 	if cfg.GitHubPAT != "" {
-		p.setGitHubClient(ghclient.NewClient(cfg.GitHubPAT))
+		p.setGitHubClient(newGitHubClient(cfg.GitHubPAT))
 	} else {
 		p.setGitHubClient(nil)
 	}
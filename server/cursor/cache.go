@@ -0,0 +1,147 @@
+package cursor
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// conversationCache wraps a Client and caches GetConversation responses per
+// agent for a short TTL, so plan polling and status commands that read the
+// same conversation in quick succession hit the Cursor API once. Concurrent
+// callers for the same agent share a single in-flight request. An agent's
+// entry is dropped when GetAgent reports a status change or when a follow-up,
+// stop, or delete is sent to it.
+type conversationCache struct {
+	Client
+
+	ttl time.Duration
+	now func() time.Time
+
+	mu       sync.Mutex
+	entries  map[string]conversationEntry
+	inflight map[string]*conversationCall
+	statuses map[string]AgentStatus
+}
+
+type conversationEntry struct {
+	conversation *Conversation
+	expiresAt    time.Time
+}
+
+type conversationCall struct {
+	done         chan struct{}
+	conversation *Conversation
+	err          error
+	// stale is set when the agent is invalidated while the call is in
+	// flight, so its result is returned but not cached.
+	stale bool
+}
+
+// NewConversationCache wraps next with a GetConversation cache whose entries
+// live for ttl. Returns nil if next is nil.
+func NewConversationCache(next Client, ttl time.Duration) Client {
+	if next == nil {
+		return nil
+	}
+	return newConversationCache(next, ttl, time.Now)
+}
+
+func newConversationCache(next Client, ttl time.Duration, now func() time.Time) *conversationCache {
+	return &conversationCache{
+		Client:   next,
+		ttl:      ttl,
+		now:      now,
+		entries:  make(map[string]conversationEntry),
+		inflight: make(map[string]*conversationCall),
+		statuses: make(map[string]AgentStatus),
+	}
+}
+
+func (c *conversationCache) GetConversation(ctx context.Context, id string) (*Conversation, error) {
+	c.mu.Lock()
+	if entry, ok := c.entries[id]; ok {
+		if c.now().Before(entry.expiresAt) {
+			c.mu.Unlock()
+			return entry.conversation, nil
+		}
+		delete(c.entries, id)
+	}
+	if call, ok := c.inflight[id]; ok {
+		c.mu.Unlock()
+		select {
+		case <-call.done:
+			return call.conversation, call.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	call := &conversationCall{done: make(chan struct{})}
+	c.inflight[id] = call
+	c.mu.Unlock()
+
+	call.conversation, call.err = c.Client.GetConversation(ctx, id)
+
+	c.mu.Lock()
+	delete(c.inflight, id)
+	if call.err == nil && !call.stale {
+		c.entries[id] = conversationEntry{
+			conversation: call.conversation,
+			expiresAt:    c.now().Add(c.ttl),
+		}
+	}
+	c.mu.Unlock()
+	close(call.done)
+
+	return call.conversation, call.err
+}
+
+func (c *conversationCache) GetAgent(ctx context.Context, id string) (*Agent, error) {
+	agent, err := c.Client.GetAgent(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	if previous, ok := c.statuses[id]; ok && previous != agent.Status {
+		c.invalidateLocked(id)
+	}
+	if agent.Status.IsTerminal() {
+		// Terminal agents stop changing; forget the status to keep the map
+		// bounded. Their cached conversation still expires with the TTL.
+		delete(c.statuses, id)
+	} else {
+		c.statuses[id] = agent.Status
+	}
+	c.mu.Unlock()
+
+	return agent, nil
+}
+
+func (c *conversationCache) AddFollowup(ctx context.Context, id string, req FollowupRequest) (*FollowupResponse, error) {
+	defer c.invalidate(id)
+	return c.Client.AddFollowup(ctx, id, req)
+}
+
+func (c *conversationCache) StopAgent(ctx context.Context, id string) (*StopResponse, error) {
+	defer c.invalidate(id)
+	return c.Client.StopAgent(ctx, id)
+}
+
+func (c *conversationCache) DeleteAgent(ctx context.Context, id string) (*DeleteResponse, error) {
+	defer c.invalidate(id)
+	return c.Client.DeleteAgent(ctx, id)
+}
+
+func (c *conversationCache) invalidate(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.invalidateLocked(id)
+}
+
+func (c *conversationCache) invalidateLocked(id string) {
+	delete(c.entries, id)
+	if call, ok := c.inflight[id]; ok {
+		call.stale = true
+	}
+}
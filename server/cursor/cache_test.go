@@ -0,0 +1,175 @@
+package cursor
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubClient counts upstream calls. GetConversation blocks on release when it
+// is set, and GetAgent reports *status.
+type stubClient struct {
+	Client
+
+	conversationCalls int32
+	conversationErr   error
+	release           chan struct{}
+	status            AgentStatus
+}
+
+func (s *stubClient) GetConversation(_ context.Context, id string) (*Conversation, error) {
+	n := atomic.AddInt32(&s.conversationCalls, 1)
+	if s.release != nil {
+		<-s.release
+	}
+	if s.conversationErr != nil {
+		return nil, s.conversationErr
+	}
+	return &Conversation{ID: id, Messages: []Message{{Text: string(rune('0' + n))}}}, nil
+}
+
+func (s *stubClient) GetAgent(_ context.Context, id string) (*Agent, error) {
+	return &Agent{ID: id, Status: s.status}, nil
+}
+
+func (s *stubClient) AddFollowup(_ context.Context, id string, _ FollowupRequest) (*FollowupResponse, error) {
+	return &FollowupResponse{ID: id}, nil
+}
+
+func setupConversationCache(ttl time.Duration) (*conversationCache, *stubClient, *time.Time) {
+	stub := &stubClient{status: AgentStatusRunning}
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	cache := newConversationCache(stub, ttl, func() time.Time { return now })
+	return cache, stub, &now
+}
+
+func TestConversationCache_RapidCallsHitUpstreamOnce(t *testing.T) {
+	cache, stub, _ := setupConversationCache(10 * time.Second)
+	ctx := context.Background()
+
+	first, err := cache.GetConversation(ctx, "agent-1")
+	require.NoError(t, err)
+	second, err := cache.GetConversation(ctx, "agent-1")
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&stub.conversationCalls))
+	assert.Same(t, first, second)
+
+	_, err = cache.GetConversation(ctx, "agent-2")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&stub.conversationCalls))
+}
+
+func TestConversationCache_ConcurrentCallersShareRequest(t *testing.T) {
+	cache, stub, _ := setupConversationCache(10 * time.Second)
+	stub.release = make(chan struct{})
+	ctx := context.Background()
+
+	const callers = 5
+	results := make([]*Conversation, callers)
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			conv, err := cache.GetConversation(ctx, "agent-1")
+			assert.NoError(t, err)
+			results[i] = conv
+		}(i)
+	}
+
+	// Let every caller reach the cache before the upstream call returns.
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&stub.conversationCalls) == 1
+	}, time.Second, time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	close(stub.release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&stub.conversationCalls))
+	for _, conv := range results {
+		assert.Same(t, results[0], conv)
+	}
+}
+
+func TestConversationCache_EntryExpiresAfterTTL(t *testing.T) {
+	cache, stub, now := setupConversationCache(10 * time.Second)
+	ctx := context.Background()
+
+	_, err := cache.GetConversation(ctx, "agent-1")
+	require.NoError(t, err)
+
+	*now = now.Add(9 * time.Second)
+	_, err = cache.GetConversation(ctx, "agent-1")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&stub.conversationCalls))
+
+	*now = now.Add(time.Second)
+	_, err = cache.GetConversation(ctx, "agent-1")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&stub.conversationCalls))
+}
+
+func TestConversationCache_StatusChangeInvalidates(t *testing.T) {
+	cache, stub, _ := setupConversationCache(10 * time.Second)
+	ctx := context.Background()
+
+	_, err := cache.GetAgent(ctx, "agent-1")
+	require.NoError(t, err)
+	_, err = cache.GetConversation(ctx, "agent-1")
+	require.NoError(t, err)
+
+	// Same status: the cached conversation is kept.
+	_, err = cache.GetAgent(ctx, "agent-1")
+	require.NoError(t, err)
+	_, err = cache.GetConversation(ctx, "agent-1")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&stub.conversationCalls))
+
+	stub.status = AgentStatusFinished
+	_, err = cache.GetAgent(ctx, "agent-1")
+	require.NoError(t, err)
+	_, err = cache.GetConversation(ctx, "agent-1")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&stub.conversationCalls))
+	assert.Empty(t, cache.statuses)
+}
+
+func TestConversationCache_FollowupInvalidates(t *testing.T) {
+	cache, stub, _ := setupConversationCache(10 * time.Second)
+	ctx := context.Background()
+
+	_, err := cache.GetConversation(ctx, "agent-1")
+	require.NoError(t, err)
+	_, err = cache.AddFollowup(ctx, "agent-1", FollowupRequest{Prompt: Prompt{Text: "more"}})
+	require.NoError(t, err)
+	_, err = cache.GetConversation(ctx, "agent-1")
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&stub.conversationCalls))
+}
+
+func TestConversationCache_ErrorsAreNotCached(t *testing.T) {
+	cache, stub, _ := setupConversationCache(10 * time.Second)
+	stub.conversationErr = errors.New("boom")
+	ctx := context.Background()
+
+	_, err := cache.GetConversation(ctx, "agent-1")
+	require.Error(t, err)
+
+	stub.conversationErr = nil
+	conv, err := cache.GetConversation(ctx, "agent-1")
+	require.NoError(t, err)
+	require.NotNil(t, conv)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&stub.conversationCalls))
+}
+
+func TestNewConversationCache_NilClient(t *testing.T) {
+	assert.Nil(t, NewConversationCache(nil, time.Second))
+}
@@ -3,6 +3,7 @@ package cursor
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"sync/atomic"
@@ -393,6 +394,14 @@ func TestAPIErrorMessage(t *testing.T) {
 	assert.Equal(t, "cursor API error (HTTP 401): Invalid API key", apiErr.Error())
 }
 
+func TestIsRateLimited(t *testing.T) {
+	assert.True(t, IsRateLimited(&APIError{StatusCode: 429}))
+	assert.True(t, IsRateLimited(fmt.Errorf("request failed after 3 retries: %w", &APIError{StatusCode: 429})))
+	assert.False(t, IsRateLimited(&APIError{StatusCode: 503}))
+	assert.False(t, IsRateLimited(fmt.Errorf("boom")))
+	assert.False(t, IsRateLimited(nil))
+}
+
 func TestNonJSONErrorResponse(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusBadRequest)
@@ -1,6 +1,7 @@
 package cursor
 
 import (
+	"errors"
 	"fmt"
 	"time"
 )
@@ -55,10 +56,12 @@ type Source struct {
 
 type Target struct {
 	BranchName            string `json:"branchName,omitempty"`
+	BaseBranch            string `json:"baseBranch,omitempty"`
 	AutoCreatePr          bool   `json:"autoCreatePr"`
 	AutoBranch            bool   `json:"autoBranch"`
 	OpenAsCursorGithubApp bool   `json:"openAsCursorGithubApp,omitempty"`
 	SkipReviewerRequest   bool   `json:"skipReviewerRequest,omitempty"`
+	Draft                 bool   `json:"draft,omitempty"`
 }
 
 type Webhook struct {
@@ -169,3 +172,10 @@ func (e *APIError) Error() string {
 	}
 	return fmt.Sprintf("cursor API error (HTTP %d): %s", e.StatusCode, msg)
 }
+
+// IsRateLimited reports whether err is a Cursor API 429 response, including
+// one returned after the client exhausted its own retries.
+func IsRateLimited(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == 429
+}
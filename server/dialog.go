@@ -2,16 +2,27 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
-	"regexp"
 	"strings"
 
 	"github.com/mattermost/mattermost/server/public/model"
 
+	"github.com/mattermost/mattermost-plugin-cursor/server/ghclient"
 	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
 )
 
-var repoFormatRe = regexp.MustCompile(`^[a-zA-Z0-9._-]+/[a-zA-Z0-9._-]+$`)
+// repoDialogError describes why a repository entered in the settings dialog
+// was rejected.
+func repoDialogError(err error) string {
+	reason := err.Error()
+	var refErr *ghclient.RepoRefError
+	if errors.As(err, &refErr) {
+		reason = refErr.Err.Error()
+	}
+	return fmt.Sprintf("Repository %s. Use owner/repo (e.g., mattermost/mattermost) or a GitHub repository URL.", reason)
+}
 
 func (p *Plugin) handleSettingsDialogSubmission(w http.ResponseWriter, r *http.Request) {
 	var request model.SubmitDialogRequest
@@ -38,15 +49,42 @@ func (p *Plugin) handleSettingsDialogSubmission(w http.ResponseWriter, r *http.R
 
 	channelRepo, _ := request.Submission["channel_default_repo"].(string)
 	channelBranch, _ := request.Submission["channel_default_branch"].(string)
+	channelBot, _ := request.Submission["channel_bot_username"].(string)
 	userRepo, _ := request.Submission["user_default_repo"].(string)
 	userBranch, _ := request.Submission["user_default_branch"].(string)
 	userModel, _ := request.Submission["user_default_model"].(string)
 
-	if channelRepo != "" && !repoFormatRe.MatchString(channelRepo) {
-		dialogErrors["channel_default_repo"] = "Must be in owner/repo format (e.g., mattermost/mattermost)"
+	if channelRepo != "" {
+		if repoRef, err := ghclient.ParseRepoRef(channelRepo); err != nil {
+			dialogErrors["channel_default_repo"] = repoDialogError(err)
+		} else {
+			channelRepo = repoRef.String()
+		}
 	}
-	if userRepo != "" && !repoFormatRe.MatchString(userRepo) {
-		dialogErrors["user_default_repo"] = "Must be in owner/repo format (e.g., mattermost/mattermost)"
+	if channelBot != "" {
+		if _, ok := p.getBotIdentities()[channelBot]; !ok {
+			dialogErrors["channel_bot_username"] = "Must be one of the configured bot identities"
+		}
+	}
+	if userRepo != "" {
+		if repoRef, err := ghclient.ParseRepoRef(userRepo); err != nil {
+			dialogErrors["user_default_repo"] = repoDialogError(err)
+		} else {
+			userRepo = repoRef.String()
+		}
+	}
+
+	// Allowed launchers are only submitted by, and only changed for, users
+	// who may manage them.
+	var allowedLaunchers []string
+	raw, submitted := request.Submission["channel_allowed_launchers"].(string)
+	manageLaunchers := submitted && p.canManageChannelLaunchers(userID, channelID)
+	if manageLaunchers {
+		userIDs, err := p.resolveLauncherUsernames(raw)
+		if err != nil {
+			dialogErrors["channel_allowed_launchers"] = "Must be existing usernames separated by commas: " + err.Error()
+		}
+		allowedLaunchers = userIDs
 	}
 
 	if len(dialogErrors) > 0 {
@@ -56,10 +94,32 @@ func (p *Plugin) handleSettingsDialogSubmission(w http.ResponseWriter, r *http.R
 		return
 	}
 
+	mirrorProgress := false
+	if raw, ok := request.Submission["channel_mirror_progress"]; ok {
+		if value, parsed := parseOptionalDialogBool(raw); parsed {
+			mirrorProgress = value != nil && *value
+		} else {
+			p.API.LogWarn("Ignoring invalid progress mirroring toggle value",
+				"value", raw,
+			)
+		}
+	}
+
+	// Everyone else keeps the channel's stored allowed launchers.
+	if !manageLaunchers {
+		if existing, _ := p.kvstore.GetChannelSettings(channelID); existing != nil {
+			allowedLaunchers = existing.AllowedLauncherUserIDs
+		}
+	}
+
 	// Save channel settings.
 	err := p.kvstore.SaveChannelSettings(channelID, &kvstore.ChannelSettings{
 		DefaultRepository: channelRepo,
 		DefaultBranch:     channelBranch,
+		BotUsername:       channelBot,
+		MirrorProgress:    mirrorProgress,
+
+		AllowedLauncherUserIDs: allowedLaunchers,
 	})
 	if err != nil {
 		p.API.LogError("Failed to save channel settings", "error", err.Error())
@@ -72,6 +132,11 @@ func (p *Plugin) handleSettingsDialogSubmission(w http.ResponseWriter, r *http.R
 		DefaultModel:      userModel,
 	}
 
+	// Launch aliases are managed with /cursor alias, not this dialog.
+	if existing, _ := p.kvstore.GetUserSettings(userID); existing != nil {
+		userSettingsToSave.Aliases = existing.Aliases
+	}
+
 	if raw, ok := request.Submission["user_enable_context_review"]; ok {
 		if value, parsed := parseOptionalDialogBool(raw); parsed {
 			userSettingsToSave.EnableContextReview = value
@@ -99,11 +164,11 @@ func (p *Plugin) handleSettingsDialogSubmission(w http.ResponseWriter, r *http.R
 	}
 
 	// Send confirmation ephemeral post.
-	_ = p.API.SendEphemeralPost(userID, &model.Post{
-		UserId:    p.getBotUserID(),
+	_ = p.API.SendEphemeralPost(userID, p.decorateBotPost(&model.Post{
+		UserId:    p.botUserIDForChannel(channelID),
 		ChannelId: channelID,
 		Message:   ":white_check_mark: Cursor settings saved successfully.",
-	})
+	}))
 
 	w.Header().Set("Content-Type", "application/json")
 	_, _ = w.Write([]byte("{}"))
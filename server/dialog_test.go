@@ -40,6 +40,7 @@ func setupDialogTestPlugin(t *testing.T) (*Plugin, *plugintest.API, *mockKVStore
 
 func TestSettingsDialog_ValidSubmission(t *testing.T) {
 	p, api, store := setupDialogTestPlugin(t)
+	store.On("GetChannelSettings", "ch-1").Return(nil, nil)
 
 	submission := model.SubmitDialogRequest{
 		UserId: "user-1",
@@ -57,6 +58,7 @@ func TestSettingsDialog_ValidSubmission(t *testing.T) {
 		DefaultRepository: "org/repo",
 		DefaultBranch:     "main",
 	}).Return(nil)
+	store.On("GetUserSettings", "user-1").Return(nil, nil)
 	store.On("SaveUserSettings", "user-1", &kvstore.UserSettings{
 		DefaultRepository: "user/personal",
 		DefaultBranch:     "develop",
@@ -83,6 +85,103 @@ func TestSettingsDialog_ValidSubmission(t *testing.T) {
 	store.AssertExpectations(t)
 }
 
+func TestSettingsDialog_SavesMirrorProgress(t *testing.T) {
+	p, api, store := setupDialogTestPlugin(t)
+	store.On("GetChannelSettings", "ch-1").Return(nil, nil)
+
+	submission := model.SubmitDialogRequest{
+		UserId: "user-1",
+		State:  "ch-1|user-1",
+		Submission: map[string]any{
+			"channel_default_repo":    "org/repo",
+			"channel_mirror_progress": true,
+		},
+	}
+
+	store.On("SaveChannelSettings", "ch-1", &kvstore.ChannelSettings{
+		DefaultRepository: "org/repo",
+		MirrorProgress:    true,
+	}).Return(nil)
+	store.On("GetUserSettings", "user-1").Return(nil, nil)
+	store.On("SaveUserSettings", "user-1", &kvstore.UserSettings{}).Return(nil)
+	api.On("SendEphemeralPost", "user-1", mock.Anything).Return(&model.Post{})
+
+	body, _ := json.Marshal(submission)
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/dialog/settings", bytes.NewReader(body))
+	r.Header.Set("Mattermost-User-ID", "user-1")
+
+	p.ServeHTTP(nil, w, r)
+
+	result := w.Result()
+	defer func() { _ = result.Body.Close() }()
+	assert.Equal(t, http.StatusOK, result.StatusCode)
+	store.AssertExpectations(t)
+}
+
+func TestSettingsDialog_PreservesAliases(t *testing.T) {
+	p, api, store := setupDialogTestPlugin(t)
+	store.On("GetChannelSettings", "ch-1").Return(nil, nil)
+
+	submission := model.SubmitDialogRequest{
+		UserId: "user-1",
+		State:  "ch-1|user-1",
+		Submission: map[string]any{
+			"user_default_repo": "user/personal",
+		},
+	}
+
+	store.On("SaveChannelSettings", "ch-1", &kvstore.ChannelSettings{}).Return(nil)
+	store.On("GetUserSettings", "user-1").Return(&kvstore.UserSettings{
+		DefaultRepository: "old/repo",
+		Aliases:           map[string]string{"web": "repo=org/web"},
+	}, nil)
+	store.On("SaveUserSettings", "user-1", &kvstore.UserSettings{
+		DefaultRepository: "user/personal",
+		Aliases:           map[string]string{"web": "repo=org/web"},
+	}).Return(nil)
+	api.On("SendEphemeralPost", "user-1", mock.Anything).Return(&model.Post{})
+
+	body, _ := json.Marshal(submission)
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/dialog/settings", bytes.NewReader(body))
+	r.Header.Set("Mattermost-User-ID", "user-1")
+
+	p.ServeHTTP(nil, w, r)
+
+	assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+	store.AssertExpectations(t)
+}
+
+func TestSettingsDialog_NormalizesRepoURL(t *testing.T) {
+	p, api, store := setupDialogTestPlugin(t)
+	store.On("GetChannelSettings", "ch-1").Return(nil, nil)
+
+	submission := model.SubmitDialogRequest{
+		UserId: "user-1",
+		State:  "ch-1|user-1",
+		Submission: map[string]any{
+			"channel_default_repo": "https://github.com/org/repo.git",
+			"user_default_repo":    "github.com/user/personal",
+		},
+	}
+
+	store.On("SaveChannelSettings", "ch-1", &kvstore.ChannelSettings{DefaultRepository: "org/repo"}).Return(nil)
+	store.On("GetUserSettings", "user-1").Return(nil, nil)
+	store.On("SaveUserSettings", "user-1", &kvstore.UserSettings{DefaultRepository: "user/personal"}).Return(nil)
+	api.On("SendEphemeralPost", "user-1", mock.Anything).Return(&model.Post{})
+
+	body, _ := json.Marshal(submission)
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/dialog/settings", bytes.NewReader(body))
+	r.Header.Set("Mattermost-User-ID", "user-1")
+
+	p.ServeHTTP(nil, w, r)
+
+	assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+	store.AssertExpectations(t)
+}
+
 func TestSettingsDialog_InvalidRepo(t *testing.T) {
 	p, _, _ := setupDialogTestPlugin(t)
 
@@ -117,6 +216,7 @@ func TestSettingsDialog_InvalidRepo(t *testing.T) {
 
 func TestSettingsDialog_EmptySubmission(t *testing.T) {
 	p, api, store := setupDialogTestPlugin(t)
+	store.On("GetChannelSettings", "ch-1").Return(nil, nil)
 
 	submission := model.SubmitDialogRequest{
 		UserId: "user-1",
@@ -131,6 +231,7 @@ func TestSettingsDialog_EmptySubmission(t *testing.T) {
 	}
 
 	store.On("SaveChannelSettings", "ch-1", &kvstore.ChannelSettings{}).Return(nil)
+	store.On("GetUserSettings", "user-1").Return(nil, nil)
 	store.On("SaveUserSettings", "user-1", &kvstore.UserSettings{}).Return(nil)
 	api.On("SendEphemeralPost", "user-1", mock.Anything).Return(&model.Post{})
 
@@ -218,6 +319,7 @@ func TestSettingsDialog_InvalidUserRepo(t *testing.T) {
 
 func TestSettingsDialog_SavesHITLSettings(t *testing.T) {
 	p, api, store := setupDialogTestPlugin(t)
+	store.On("GetChannelSettings", "ch-1").Return(nil, nil)
 
 	submission := model.SubmitDialogRequest{
 		UserId: "user-1",
@@ -234,6 +336,7 @@ func TestSettingsDialog_SavesHITLSettings(t *testing.T) {
 	}
 
 	store.On("SaveChannelSettings", "ch-1", mock.Anything).Return(nil)
+	store.On("GetUserSettings", "user-1").Return(nil, nil)
 	store.On("SaveUserSettings", "user-1", mock.MatchedBy(func(s *kvstore.UserSettings) bool {
 		return s.DefaultRepository == "org/repo" &&
 			s.EnableContextReview != nil && *s.EnableContextReview == true &&
@@ -257,6 +360,7 @@ func TestSettingsDialog_SavesHITLSettings(t *testing.T) {
 
 func TestSettingsDialog_SavesHITLSettings_StringCoercion(t *testing.T) {
 	p, api, store := setupDialogTestPlugin(t)
+	store.On("GetChannelSettings", "ch-1").Return(nil, nil)
 
 	submission := model.SubmitDialogRequest{
 		UserId: "user-1",
@@ -273,6 +377,7 @@ func TestSettingsDialog_SavesHITLSettings_StringCoercion(t *testing.T) {
 	}
 
 	store.On("SaveChannelSettings", "ch-1", mock.Anything).Return(nil)
+	store.On("GetUserSettings", "user-1").Return(nil, nil)
 	store.On("SaveUserSettings", "user-1", mock.MatchedBy(func(s *kvstore.UserSettings) bool {
 		return s.DefaultRepository == "org/repo" &&
 			s.EnableContextReview != nil && *s.EnableContextReview == true &&
@@ -296,6 +401,7 @@ func TestSettingsDialog_SavesHITLSettings_StringCoercion(t *testing.T) {
 
 func TestSettingsDialog_NilHITLSettings_NoOverride(t *testing.T) {
 	p, api, store := setupDialogTestPlugin(t)
+	store.On("GetChannelSettings", "ch-1").Return(nil, nil)
 
 	submission := model.SubmitDialogRequest{
 		UserId: "user-1",
@@ -311,6 +417,7 @@ func TestSettingsDialog_NilHITLSettings_NoOverride(t *testing.T) {
 	}
 
 	store.On("SaveChannelSettings", "ch-1", mock.Anything).Return(nil)
+	store.On("GetUserSettings", "user-1").Return(nil, nil)
 	store.On("SaveUserSettings", "user-1", mock.MatchedBy(func(s *kvstore.UserSettings) bool {
 		return s.EnableContextReview == nil && s.EnablePlanLoop == nil
 	})).Return(nil)
@@ -0,0 +1,277 @@
+package ghclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v68/github"
+)
+
+// ErrCircuitOpen is returned without calling GitHub while the circuit breaker
+// is open after repeated failures.
+var ErrCircuitOpen = errors.New("github circuit breaker is open")
+
+// breakerState is the state of a circuitBreaker.
+type breakerState int
+
+const (
+	breakerClosed   breakerState = iota // Calls pass through
+	breakerOpen                         // Calls fast-fail until the cooldown elapses
+	breakerHalfOpen                     // A single trial call is in flight
+)
+
+// circuitBreaker wraps a Client and fast-fails calls once GitHub has failed
+// threshold times in a row, so an outage does not tie up every webhook handler
+// on slow failing requests. After cooldown one trial call is let through; its
+// success closes the breaker and its failure re-opens it.
+type circuitBreaker struct {
+	next      Client
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu       sync.Mutex
+	state    breakerState
+	failures int
+	openedAt time.Time
+}
+
+// NewCircuitBreaker wraps next with a circuit breaker that opens after
+// threshold consecutive failures and half-opens after cooldown.
+// Returns nil if next is nil.
+func NewCircuitBreaker(next Client, threshold int, cooldown time.Duration) Client {
+	if next == nil {
+		return nil
+	}
+	return newCircuitBreaker(next, threshold, cooldown, time.Now)
+}
+
+func newCircuitBreaker(next Client, threshold int, cooldown time.Duration, now func() time.Time) *circuitBreaker {
+	if threshold < 1 {
+		threshold = 1
+	}
+	return &circuitBreaker{
+		next:      next,
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       now,
+	}
+}
+
+// allow reports whether a call may proceed, moving an open breaker to
+// half-open once the cooldown has elapsed.
+func (b *circuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return ErrCircuitOpen
+		}
+		b.state = breakerHalfOpen
+		return nil
+	case breakerHalfOpen:
+		// Only the trial call is allowed through until it completes.
+		return ErrCircuitOpen
+	default:
+		return nil
+	}
+}
+
+// record updates the breaker with the outcome of a call that was allowed.
+func (b *circuitBreaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !isBreakerFailure(err) {
+		b.state = breakerClosed
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= b.threshold {
+		b.state = breakerOpen
+		b.openedAt = b.now()
+	}
+}
+
+// isBreakerFailure reports whether err indicates GitHub is unhealthy.
+// Client errors such as 404 or 422 mean GitHub answered and do not count.
+func isBreakerFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	var errResp *github.ErrorResponse
+	if errors.As(err, &errResp) && errResp.Response != nil {
+		status := errResp.Response.StatusCode
+		return status >= http.StatusInternalServerError || status == http.StatusTooManyRequests
+	}
+	return true
+}
+
+func (b *circuitBreaker) RequestReviewers(ctx context.Context, owner, repo string, prNumber int, reviewers github.ReviewersRequest) error {
+	if err := b.allow(); err != nil {
+		return err
+	}
+	err := b.next.RequestReviewers(ctx, owner, repo, prNumber, reviewers)
+	b.record(err)
+	return err
+}
+
+func (b *circuitBreaker) CreateComment(ctx context.Context, owner, repo string, prNumber int, body string) (*github.IssueComment, error) {
+	if err := b.allow(); err != nil {
+		return nil, err
+	}
+	comment, err := b.next.CreateComment(ctx, owner, repo, prNumber, body)
+	b.record(err)
+	return comment, err
+}
+
+func (b *circuitBreaker) ListReviews(ctx context.Context, owner, repo string, prNumber int) ([]*github.PullRequestReview, error) {
+	if err := b.allow(); err != nil {
+		return nil, err
+	}
+	reviews, err := b.next.ListReviews(ctx, owner, repo, prNumber)
+	b.record(err)
+	return reviews, err
+}
+
+func (b *circuitBreaker) ListReviewComments(ctx context.Context, owner, repo string, prNumber int) ([]*github.PullRequestComment, error) {
+	if err := b.allow(); err != nil {
+		return nil, err
+	}
+	comments, err := b.next.ListReviewComments(ctx, owner, repo, prNumber)
+	b.record(err)
+	return comments, err
+}
+
+func (b *circuitBreaker) ListIssueComments(ctx context.Context, owner, repo string, issueNumber int) ([]*github.IssueComment, error) {
+	if err := b.allow(); err != nil {
+		return nil, err
+	}
+	comments, err := b.next.ListIssueComments(ctx, owner, repo, issueNumber)
+	b.record(err)
+	return comments, err
+}
+
+func (b *circuitBreaker) ListPullRequestCommits(ctx context.Context, owner, repo string, prNumber int) ([]*github.RepositoryCommit, error) {
+	if err := b.allow(); err != nil {
+		return nil, err
+	}
+	commits, err := b.next.ListPullRequestCommits(ctx, owner, repo, prNumber)
+	b.record(err)
+	return commits, err
+}
+
+func (b *circuitBreaker) ReplyToReviewComment(ctx context.Context, owner, repo string, prNumber int, commentID int64, body string) (*github.PullRequestComment, error) {
+	if err := b.allow(); err != nil {
+		return nil, err
+	}
+	reply, err := b.next.ReplyToReviewComment(ctx, owner, repo, prNumber, commentID, body)
+	b.record(err)
+	return reply, err
+}
+
+func (b *circuitBreaker) MarkPRReadyForReview(ctx context.Context, owner, repo string, prNumber int) error {
+	if err := b.allow(); err != nil {
+		return err
+	}
+	err := b.next.MarkPRReadyForReview(ctx, owner, repo, prNumber)
+	b.record(err)
+	return err
+}
+
+func (b *circuitBreaker) GetPullRequest(ctx context.Context, owner, repo string, prNumber int) (*github.PullRequest, error) {
+	if err := b.allow(); err != nil {
+		return nil, err
+	}
+	pr, err := b.next.GetPullRequest(ctx, owner, repo, prNumber)
+	b.record(err)
+	return pr, err
+}
+
+func (b *circuitBreaker) GetPullRequestByBranch(ctx context.Context, owner, repo, branch string) (*github.PullRequest, error) {
+	if err := b.allow(); err != nil {
+		return nil, err
+	}
+	pr, err := b.next.GetPullRequestByBranch(ctx, owner, repo, branch)
+	b.record(err)
+	return pr, err
+}
+
+func (b *circuitBreaker) GetPullRequestDiff(ctx context.Context, owner, repo string, prNumber int) (string, error) {
+	if err := b.allow(); err != nil {
+		return "", err
+	}
+	diff, err := b.next.GetPullRequestDiff(ctx, owner, repo, prNumber)
+	b.record(err)
+	return diff, err
+}
+
+func (b *circuitBreaker) CompareCommits(ctx context.Context, owner, repo, base, head string) (*github.CommitsComparison, error) {
+	if err := b.allow(); err != nil {
+		return nil, err
+	}
+	comparison, err := b.next.CompareCommits(ctx, owner, repo, base, head)
+	b.record(err)
+	return comparison, err
+}
+
+func (b *circuitBreaker) GetBranch(ctx context.Context, owner, repo, branch string) (*github.Branch, error) {
+	if err := b.allow(); err != nil {
+		return nil, err
+	}
+	result, err := b.next.GetBranch(ctx, owner, repo, branch)
+	b.record(err)
+	return result, err
+}
+
+func (b *circuitBreaker) ListReviewThreads(ctx context.Context, owner, repo string, prNumber int) ([]ReviewThread, error) {
+	if err := b.allow(); err != nil {
+		return nil, err
+	}
+	threads, err := b.next.ListReviewThreads(ctx, owner, repo, prNumber)
+	b.record(err)
+	return threads, err
+}
+
+func (b *circuitBreaker) ResolveReviewThread(ctx context.Context, threadID string) error {
+	if err := b.allow(); err != nil {
+		return err
+	}
+	err := b.next.ResolveReviewThread(ctx, threadID)
+	b.record(err)
+	return err
+}
+
+func (b *circuitBreaker) GetChecksState(ctx context.Context, owner, repo, ref string) (string, error) {
+	if err := b.allow(); err != nil {
+		return "", err
+	}
+	state, err := b.next.GetChecksState(ctx, owner, repo, ref)
+	b.record(err)
+	return state, err
+}
+
+func (b *circuitBreaker) GetIssue(ctx context.Context, owner, repo string, number int) (*github.Issue, error) {
+	if err := b.allow(); err != nil {
+		return nil, err
+	}
+	issue, err := b.next.GetIssue(ctx, owner, repo, number)
+	b.record(err)
+	return issue, err
+}
+
+func (b *circuitBreaker) MergePR(ctx context.Context, owner, repo string, prNumber int, sha, mergeMethod string) error {
+	if err := b.allow(); err != nil {
+		return err
+	}
+	err := b.next.MergePR(ctx, owner, repo, prNumber, sha, mergeMethod)
+	b.record(err)
+	return err
+}
@@ -0,0 +1,122 @@
+package ghclient
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupBreaker wires a circuit breaker with a controllable clock in front of a
+// test server whose review comments endpoint answers with *status.
+func setupBreaker(t *testing.T, threshold int, cooldown time.Duration) (*circuitBreaker, *int32, *int32, *time.Time) {
+	t.Helper()
+	client, mux, _ := setup(t)
+
+	status := int32(http.StatusInternalServerError)
+	var hits int32
+	mux.HandleFunc("/repos/owner/repo/pulls/42/comments", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.WriteHeader(int(atomic.LoadInt32(&status)))
+		if atomic.LoadInt32(&status) == http.StatusOK {
+			_, _ = w.Write([]byte(`[]`))
+		}
+	})
+
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	breaker := newCircuitBreaker(client, threshold, cooldown, func() time.Time { return now })
+	return breaker, &status, &hits, &now
+}
+
+func TestCircuitBreaker_OpensAfterConsecutiveFailures(t *testing.T) {
+	breaker, _, hits, _ := setupBreaker(t, 3, time.Minute)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		_, err := breaker.ListReviewComments(ctx, "owner", "repo", 42)
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrCircuitOpen)
+	}
+	assert.Equal(t, int32(3), atomic.LoadInt32(hits))
+
+	_, err := breaker.ListReviewComments(ctx, "owner", "repo", 42)
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+}
+
+func TestCircuitBreaker_FastFailsWhileOpen(t *testing.T) {
+	breaker, _, hits, now := setupBreaker(t, 2, time.Minute)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		_, _ = breaker.ListReviewComments(ctx, "owner", "repo", 42)
+	}
+
+	*now = now.Add(30 * time.Second)
+	for i := 0; i < 5; i++ {
+		_, err := breaker.ListReviewComments(ctx, "owner", "repo", 42)
+		assert.ErrorIs(t, err, ErrCircuitOpen)
+	}
+	// Every other method shares the same breaker.
+	err := breaker.MarkPRReadyForReview(ctx, "owner", "repo", 42)
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(hits), "no requests should reach GitHub while open")
+}
+
+func TestCircuitBreaker_RecoversAfterCooldown(t *testing.T) {
+	breaker, status, hits, now := setupBreaker(t, 2, time.Minute)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		_, _ = breaker.ListReviewComments(ctx, "owner", "repo", 42)
+	}
+
+	atomic.StoreInt32(status, http.StatusOK)
+	*now = now.Add(time.Minute)
+
+	_, err := breaker.ListReviewComments(ctx, "owner", "repo", 42)
+	require.NoError(t, err, "trial call after cooldown should reach GitHub")
+
+	_, err = breaker.ListReviewComments(ctx, "owner", "repo", 42)
+	require.NoError(t, err, "breaker should be closed after a successful trial")
+	assert.Equal(t, int32(4), atomic.LoadInt32(hits))
+}
+
+func TestCircuitBreaker_FailedTrialReopens(t *testing.T) {
+	breaker, _, hits, now := setupBreaker(t, 2, time.Minute)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		_, _ = breaker.ListReviewComments(ctx, "owner", "repo", 42)
+	}
+
+	*now = now.Add(time.Minute)
+	_, err := breaker.ListReviewComments(ctx, "owner", "repo", 42)
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrCircuitOpen)
+
+	_, err = breaker.ListReviewComments(ctx, "owner", "repo", 42)
+	assert.ErrorIs(t, err, ErrCircuitOpen, "a failed trial should re-open for a full cooldown")
+	assert.Equal(t, int32(3), atomic.LoadInt32(hits))
+}
+
+func TestCircuitBreaker_ClientErrorsDoNotCount(t *testing.T) {
+	breaker, status, hits, _ := setupBreaker(t, 2, time.Minute)
+	atomic.StoreInt32(status, http.StatusNotFound)
+	ctx := context.Background()
+
+	for i := 0; i < 4; i++ {
+		_, err := breaker.ListReviewComments(ctx, "owner", "repo", 42)
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrCircuitOpen)
+	}
+	assert.Equal(t, int32(4), atomic.LoadInt32(hits))
+}
+
+func TestNewCircuitBreaker_NilClient(t *testing.T) {
+	assert.Nil(t, NewCircuitBreaker(nil, 5, time.Minute))
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"regexp"
 	"strconv"
 
@@ -31,6 +32,9 @@ type Client interface {
 	// ListIssueComments returns all issue comments on a PR issue (auto-paginates).
 	ListIssueComments(ctx context.Context, owner, repo string, issueNumber int) ([]*github.IssueComment, error)
 
+	// ListPullRequestCommits returns all commits on a PR (auto-paginates).
+	ListPullRequestCommits(ctx context.Context, owner, repo string, prNumber int) ([]*github.RepositoryCommit, error)
+
 	// ReplyToReviewComment replies to a pull request review comment.
 	// It prefers the dedicated replies endpoint, then falls back to in_reply_to.
 	ReplyToReviewComment(ctx context.Context, owner, repo string, prNumber int, commentID int64, body string) (*github.PullRequestComment, error)
@@ -40,11 +44,56 @@ type Client interface {
 	// (e.g., CodeRabbit) skip draft PRs.
 	MarkPRReadyForReview(ctx context.Context, owner, repo string, prNumber int) error
 
+	// GetPullRequest returns a PR, including its mergeable state. Mergeable
+	// is nil while GitHub is still computing it.
+	GetPullRequest(ctx context.Context, owner, repo string, prNumber int) (*github.PullRequest, error)
+
 	// GetPullRequestByBranch finds an open PR with the given head branch.
 	// Returns nil, nil if no matching PR is found.
 	GetPullRequestByBranch(ctx context.Context, owner, repo, branch string) (*github.PullRequest, error)
+
+	// GetPullRequestDiff returns the PR's unified diff against its base at
+	// the current head commit.
+	GetPullRequestDiff(ctx context.Context, owner, repo string, prNumber int) (string, error)
+
+	// CompareCommits compares base with head. The comparison status is
+	// "ahead" when head extends base, "identical", "behind", or "diverged".
+	CompareCommits(ctx context.Context, owner, repo, base, head string) (*github.CommitsComparison, error)
+
+	// GetBranch returns a branch of the repository. The error satisfies
+	// IsNotFound when the branch does not exist.
+	GetBranch(ctx context.Context, owner, repo, branch string) (*github.Branch, error)
+
+	// ListReviewThreads returns the review threads on a PR with the IDs of
+	// their comments (GraphQL, auto-paginates).
+	ListReviewThreads(ctx context.Context, owner, repo string, prNumber int) ([]ReviewThread, error)
+
+	// ResolveReviewThread marks a review thread as resolved (GraphQL).
+	ResolveReviewThread(ctx context.Context, threadID string) error
+
+	// GetChecksState returns the combined CI state of a commit across its
+	// commit statuses and check runs: one of the ChecksState constants.
+	GetChecksState(ctx context.Context, owner, repo, ref string) (string, error)
+
+	// GetIssue returns an issue (or a PR, through the issues API). The error
+	// satisfies IsNotFound when the issue does not exist or is not visible
+	// to the token.
+	GetIssue(ctx context.Context, owner, repo string, number int) (*github.Issue, error)
+
+	// MergePR merges a PR with the given merge method ("merge", "squash", or
+	// "rebase"). When sha is set, GitHub rejects the merge if the PR head has
+	// moved on.
+	MergePR(ctx context.Context, owner, repo string, prNumber int, sha, mergeMethod string) error
 }
 
+// Combined CI states returned by GetChecksState.
+const (
+	ChecksStateSuccess = "success" // Every status and check run passed
+	ChecksStatePending = "pending" // At least one is still running, none failed
+	ChecksStateFailure = "failure" // At least one failed, errored, or was cancelled
+	ChecksStateNone    = "none"    // The commit has no statuses or check runs
+)
+
 // clientImpl implements Client by delegating to go-github.
 type clientImpl struct {
 	gh    *github.Client
@@ -136,9 +185,15 @@ func (c *clientImpl) graphqlMarkReady(ctx context.Context, pullRequestNodeID str
 		}
 	}`
 
+	return c.doGraphQL(ctx, query, map[string]any{"id": pullRequestNodeID}, nil)
+}
+
+// doGraphQL posts a GraphQL request and decodes its data into out, if non-nil.
+// GraphQL-level errors are returned as errors even on HTTP 200.
+func (c *clientImpl) doGraphQL(ctx context.Context, query string, variables map[string]any, out any) error {
 	payload := map[string]any{
 		"query":     query,
-		"variables": map[string]string{"id": pullRequestNodeID},
+		"variables": variables,
 	}
 	body, err := json.Marshal(payload)
 	if err != nil {
@@ -173,16 +228,26 @@ func (c *clientImpl) graphqlMarkReady(ctx context.Context, pullRequestNodeID str
 
 	// Check for GraphQL-level errors.
 	var result struct {
+		Data   json.RawMessage `json:"data"`
 		Errors []struct {
 			Message string `json:"message"`
 		} `json:"errors"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil // Response parsed fine, mutation likely succeeded.
+		if out == nil {
+			return nil // Response parsed fine, mutation likely succeeded.
+		}
+		return fmt.Errorf("failed to decode GraphQL response: %w", err)
 	}
 	if len(result.Errors) > 0 {
 		return fmt.Errorf("GraphQL error: %s", result.Errors[0].Message)
 	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(result.Data, out); err != nil {
+		return fmt.Errorf("failed to decode GraphQL data: %w", err)
+	}
 	return nil
 }
 
@@ -224,6 +289,23 @@ func (c *clientImpl) ListIssueComments(ctx context.Context, owner, repo string,
 	return all, nil
 }
 
+func (c *clientImpl) ListPullRequestCommits(ctx context.Context, owner, repo string, prNumber int) ([]*github.RepositoryCommit, error) {
+	var all []*github.RepositoryCommit
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		commits, resp, err := c.gh.PullRequests.ListCommits(ctx, owner, repo, prNumber, opts)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, commits...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return all, nil
+}
+
 func (c *clientImpl) ReplyToReviewComment(ctx context.Context, owner, repo string, prNumber int, commentID int64, body string) (*github.PullRequestComment, error) {
 	comment, preferredErr := c.replyToReviewCommentViaRepliesEndpoint(ctx, owner, repo, prNumber, commentID, body)
 	if preferredErr == nil {
@@ -297,6 +379,11 @@ func (c *clientImpl) replyToReviewCommentViaInReplyTo(ctx context.Context, owner
 	return &comment, nil
 }
 
+func (c *clientImpl) GetPullRequest(ctx context.Context, owner, repo string, prNumber int) (*github.PullRequest, error) {
+	pr, _, err := c.gh.PullRequests.Get(ctx, owner, repo, prNumber)
+	return pr, err
+}
+
 func (c *clientImpl) GetPullRequestByBranch(ctx context.Context, owner, repo, branch string) (*github.PullRequest, error) {
 	prs, _, err := c.gh.PullRequests.List(ctx, owner, repo, &github.PullRequestListOptions{
 		Head:        owner + ":" + branch,
@@ -312,6 +399,199 @@ func (c *clientImpl) GetPullRequestByBranch(ctx context.Context, owner, repo, br
 	return prs[0], nil
 }
 
+func (c *clientImpl) GetPullRequestDiff(ctx context.Context, owner, repo string, prNumber int) (string, error) {
+	diff, _, err := c.gh.PullRequests.GetRaw(ctx, owner, repo, prNumber, github.RawOptions{Type: github.Diff})
+	return diff, err
+}
+
+func (c *clientImpl) CompareCommits(ctx context.Context, owner, repo, base, head string) (*github.CommitsComparison, error) {
+	// Only the status is needed; keep the embedded commit and file lists small.
+	comparison, _, err := c.gh.Repositories.CompareCommits(ctx, owner, repo, base, head, &github.ListOptions{PerPage: 1})
+	return comparison, err
+}
+
+func (c *clientImpl) GetBranch(ctx context.Context, owner, repo, branch string) (*github.Branch, error) {
+	// Repositories.GetBranch bypasses the client's error handling, so a
+	// missing branch would not surface as an *github.ErrorResponse.
+	u := fmt.Sprintf("repos/%s/%s/branches/%s", owner, repo, url.PathEscape(branch))
+	req, err := c.gh.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	var b github.Branch
+	if _, err = c.gh.Do(ctx, req, &b); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// ReviewThread is a pull request review thread and the database IDs of the
+// review comments in it.
+type ReviewThread struct {
+	ID         string
+	IsResolved bool
+	CommentIDs []int64
+}
+
+func (c *clientImpl) ListReviewThreads(ctx context.Context, owner, repo string, prNumber int) ([]ReviewThread, error) {
+	query := `query($owner: String!, $repo: String!, $number: Int!, $after: String) {
+		repository(owner: $owner, name: $repo) {
+			pullRequest(number: $number) {
+				reviewThreads(first: 100, after: $after) {
+					nodes {
+						id
+						isResolved
+						comments(first: 100) { nodes { databaseId } }
+					}
+					pageInfo { hasNextPage endCursor }
+				}
+			}
+		}
+	}`
+
+	var all []ReviewThread
+	variables := map[string]any{"owner": owner, "repo": repo, "number": prNumber, "after": nil}
+	for {
+		var data struct {
+			Repository struct {
+				PullRequest struct {
+					ReviewThreads struct {
+						Nodes []struct {
+							ID         string `json:"id"`
+							IsResolved bool   `json:"isResolved"`
+							Comments   struct {
+								Nodes []struct {
+									DatabaseID int64 `json:"databaseId"`
+								} `json:"nodes"`
+							} `json:"comments"`
+						} `json:"nodes"`
+						PageInfo struct {
+							HasNextPage bool   `json:"hasNextPage"`
+							EndCursor   string `json:"endCursor"`
+						} `json:"pageInfo"`
+					} `json:"reviewThreads"`
+				} `json:"pullRequest"`
+			} `json:"repository"`
+		}
+		if err := c.doGraphQL(ctx, query, variables, &data); err != nil {
+			return nil, err
+		}
+
+		threads := data.Repository.PullRequest.ReviewThreads
+		for _, node := range threads.Nodes {
+			thread := ReviewThread{ID: node.ID, IsResolved: node.IsResolved}
+			for _, comment := range node.Comments.Nodes {
+				thread.CommentIDs = append(thread.CommentIDs, comment.DatabaseID)
+			}
+			all = append(all, thread)
+		}
+		if !threads.PageInfo.HasNextPage {
+			break
+		}
+		variables["after"] = threads.PageInfo.EndCursor
+	}
+	return all, nil
+}
+
+func (c *clientImpl) ResolveReviewThread(ctx context.Context, threadID string) error {
+	query := `mutation($id: ID!) {
+		resolveReviewThread(input: {threadId: $id}) {
+			thread { isResolved }
+		}
+	}`
+
+	return c.doGraphQL(ctx, query, map[string]any{"id": threadID}, nil)
+}
+
+func (c *clientImpl) GetChecksState(ctx context.Context, owner, repo, ref string) (string, error) {
+	combined, _, err := c.gh.Repositories.GetCombinedStatus(ctx, owner, repo, ref, &github.ListOptions{PerPage: 100})
+	if err != nil {
+		return "", err
+	}
+
+	var runs []*github.CheckRun
+	opts := &github.ListCheckRunsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		result, resp, err := c.gh.Checks.ListCheckRunsForRef(ctx, owner, repo, ref, opts)
+		if err != nil {
+			return "", err
+		}
+		runs = append(runs, result.CheckRuns...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return combineChecksState(combined, runs), nil
+}
+
+// combineChecksState folds a commit's combined status and check runs into a
+// single ChecksState. The combined status only counts when the commit has
+// statuses, since GitHub reports "pending" for a commit without any.
+func combineChecksState(combined *github.CombinedStatus, runs []*github.CheckRun) string {
+	pending := false
+	if combined.GetTotalCount() > 0 {
+		switch combined.GetState() {
+		case "success":
+		case "pending":
+			pending = true
+		default:
+			return ChecksStateFailure
+		}
+	}
+
+	for _, run := range runs {
+		if run.GetStatus() != "completed" {
+			pending = true
+			continue
+		}
+		switch run.GetConclusion() {
+		case "success", "neutral", "skipped":
+		default:
+			return ChecksStateFailure
+		}
+	}
+
+	switch {
+	case pending:
+		return ChecksStatePending
+	case combined.GetTotalCount() == 0 && len(runs) == 0:
+		return ChecksStateNone
+	default:
+		return ChecksStateSuccess
+	}
+}
+
+func (c *clientImpl) GetIssue(ctx context.Context, owner, repo string, number int) (*github.Issue, error) {
+	issue, _, err := c.gh.Issues.Get(ctx, owner, repo, number)
+	return issue, err
+}
+
+func (c *clientImpl) MergePR(ctx context.Context, owner, repo string, prNumber int, sha, mergeMethod string) error {
+	_, _, err := c.gh.PullRequests.Merge(ctx, owner, repo, prNumber, "", &github.PullRequestOptions{
+		SHA:         sha,
+		MergeMethod: mergeMethod,
+	})
+	return err
+}
+
+// ErrorMessage returns the message GitHub gave for a failed request, such as
+// a protected branch rule blocking a merge, or err's text for other errors.
+func ErrorMessage(err error) string {
+	var ghErr *github.ErrorResponse
+	if errors.As(err, &ghErr) && ghErr.Message != "" {
+		return ghErr.Message
+	}
+	return err.Error()
+}
+
+// IsNotFound reports whether err is a GitHub 404 response.
+func IsNotFound(err error) bool {
+	var ghErr *github.ErrorResponse
+	return errors.As(err, &ghErr) && ghErr.Response != nil && ghErr.Response.StatusCode == http.StatusNotFound
+}
+
 // --- PR URL Parser ---
 
 var prURLRegex = regexp.MustCompile(`^https?://github\.com/([^/]+)/([^/]+)/pull/(\d+)`)
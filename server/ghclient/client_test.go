@@ -146,6 +146,161 @@ func TestListIssueComments(t *testing.T) {
 	assert.Equal(t, "second", comments[1].GetBody())
 }
 
+func TestListPullRequestCommits(t *testing.T) {
+	client, mux, _ := setup(t)
+
+	page := 0
+	mux.HandleFunc("/repos/owner/repo/pulls/42/commits", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		page++
+
+		switch page {
+		case 1:
+			w.Header().Set("Link", fmt.Sprintf(`<http://%s%s/repos/owner/repo/pulls/42/commits?page=2>; rel="next"`, r.Host, baseURLPath))
+			_, _ = fmt.Fprint(w, `[{"sha":"abc","commit":{"message":"first"}}]`)
+		case 2:
+			_, _ = fmt.Fprint(w, `[{"sha":"def","commit":{"message":"second"}}]`)
+		default:
+			t.Fatal("unexpected page request")
+		}
+	})
+
+	commits, err := client.ListPullRequestCommits(context.Background(), "owner", "repo", 42)
+	require.NoError(t, err)
+	assert.Len(t, commits, 2)
+	assert.Equal(t, "first", commits[0].GetCommit().GetMessage())
+	assert.Equal(t, "def", commits[1].GetSHA())
+}
+
+func TestGetPullRequestDiff(t *testing.T) {
+	client, mux, _ := setup(t)
+
+	mux.HandleFunc("/repos/owner/repo/pulls/42", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "application/vnd.github.v3.diff", r.Header.Get("Accept"))
+		_, _ = fmt.Fprint(w, "diff --git a/main.go b/main.go\n")
+	})
+
+	diff, err := client.GetPullRequestDiff(context.Background(), "owner", "repo", 42)
+	require.NoError(t, err)
+	assert.Equal(t, "diff --git a/main.go b/main.go\n", diff)
+}
+
+func TestCompareCommits(t *testing.T) {
+	client, mux, _ := setup(t)
+
+	mux.HandleFunc("/repos/owner/repo/compare/abc...def", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		_, _ = fmt.Fprint(w, `{"status":"diverged","ahead_by":1,"behind_by":2}`)
+	})
+
+	comparison, err := client.CompareCommits(context.Background(), "owner", "repo", "abc", "def")
+	require.NoError(t, err)
+	assert.Equal(t, "diverged", comparison.GetStatus())
+	assert.Equal(t, 2, comparison.GetBehindBy())
+}
+
+func TestCompareCommits_NotFound(t *testing.T) {
+	client, mux, _ := setup(t)
+
+	mux.HandleFunc("/repos/owner/repo/compare/gone...def", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = fmt.Fprint(w, `{"message":"Not Found"}`)
+	})
+
+	_, err := client.CompareCommits(context.Background(), "owner", "repo", "gone", "def")
+	require.Error(t, err)
+	assert.True(t, IsNotFound(err))
+	assert.False(t, IsNotFound(fmt.Errorf("network down")))
+}
+
+func TestGetBranch(t *testing.T) {
+	client, mux, _ := setup(t)
+
+	mux.HandleFunc("/repos/owner/repo/branches/release-1.2", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		_, _ = fmt.Fprint(w, `{"name":"release-1.2"}`)
+	})
+	mux.HandleFunc("/repos/owner/repo/branches/missing", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = fmt.Fprint(w, `{"message":"Branch not found"}`)
+	})
+
+	branch, err := client.GetBranch(context.Background(), "owner", "repo", "release-1.2")
+	require.NoError(t, err)
+	assert.Equal(t, "release-1.2", branch.GetName())
+
+	_, err = client.GetBranch(context.Background(), "owner", "repo", "missing")
+	require.Error(t, err)
+	assert.True(t, IsNotFound(err))
+}
+
+func TestListReviewThreads(t *testing.T) {
+	client, mux, _ := setup(t)
+
+	calls := 0
+	mux.HandleFunc("/graphql", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		var req struct {
+			Query     string         `json:"query"`
+			Variables map[string]any `json:"variables"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Contains(t, req.Query, "reviewThreads")
+		assert.Equal(t, "owner", req.Variables["owner"])
+		assert.Equal(t, float64(42), req.Variables["number"])
+
+		calls++
+		if req.Variables["after"] == nil {
+			_, _ = fmt.Fprint(w, `{"data":{"repository":{"pullRequest":{"reviewThreads":{
+				"nodes":[{"id":"T1","isResolved":false,"comments":{"nodes":[{"databaseId":101},{"databaseId":102}]}}],
+				"pageInfo":{"hasNextPage":true,"endCursor":"c1"}}}}}}`)
+			return
+		}
+		assert.Equal(t, "c1", req.Variables["after"])
+		_, _ = fmt.Fprint(w, `{"data":{"repository":{"pullRequest":{"reviewThreads":{
+			"nodes":[{"id":"T2","isResolved":true,"comments":{"nodes":[{"databaseId":201}]}}],
+			"pageInfo":{"hasNextPage":false,"endCursor":"c2"}}}}}}`)
+	})
+
+	threads, err := client.ListReviewThreads(context.Background(), "owner", "repo", 42)
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, []ReviewThread{
+		{ID: "T1", CommentIDs: []int64{101, 102}},
+		{ID: "T2", IsResolved: true, CommentIDs: []int64{201}},
+	}, threads)
+}
+
+func TestResolveReviewThread(t *testing.T) {
+	client, mux, _ := setup(t)
+
+	mux.HandleFunc("/graphql", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query     string         `json:"query"`
+			Variables map[string]any `json:"variables"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Contains(t, req.Query, "resolveReviewThread")
+		assert.Equal(t, "T1", req.Variables["id"])
+		_, _ = fmt.Fprint(w, `{"data":{"resolveReviewThread":{"thread":{"isResolved":true}}}}`)
+	})
+
+	require.NoError(t, client.ResolveReviewThread(context.Background(), "T1"))
+}
+
+func TestResolveReviewThread_GraphQLError(t *testing.T) {
+	client, mux, _ := setup(t)
+
+	mux.HandleFunc("/graphql", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"errors":[{"message":"Resource not accessible by integration"}]}`)
+	})
+
+	err := client.ResolveReviewThread(context.Background(), "T1")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Resource not accessible by integration")
+}
+
 func TestReplyToReviewComment_PreferredEndpointSuccess(t *testing.T) {
 	client, mux, _ := setup(t)
 
@@ -255,6 +410,83 @@ func TestReplyToReviewComment_DoesNotFallbackOnPreferredServerError(t *testing.T
 	assert.Equal(t, 0, fallbackCalls)
 }
 
+func TestGetChecksState(t *testing.T) {
+	client, mux, _ := setup(t)
+
+	mux.HandleFunc("/repos/owner/repo/commits/sha-1/status", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"state":"success","total_count":1}`)
+	})
+	mux.HandleFunc("/repos/owner/repo/commits/sha-1/check-runs", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"total_count":2,"check_runs":[{"status":"completed","conclusion":"success"},{"status":"completed","conclusion":"skipped"}]}`)
+	})
+
+	state, err := client.GetChecksState(context.Background(), "owner", "repo", "sha-1")
+	require.NoError(t, err)
+	assert.Equal(t, ChecksStateSuccess, state)
+}
+
+func TestCombineChecksState(t *testing.T) {
+	run := func(status, conclusion string) *github.CheckRun {
+		return &github.CheckRun{Status: github.Ptr(status), Conclusion: github.Ptr(conclusion)}
+	}
+	status := func(state string, total int) *github.CombinedStatus {
+		return &github.CombinedStatus{State: github.Ptr(state), TotalCount: github.Ptr(total)}
+	}
+
+	tests := []struct {
+		name     string
+		combined *github.CombinedStatus
+		runs     []*github.CheckRun
+		expected string
+	}{
+		{name: "no checks", combined: status("pending", 0), expected: ChecksStateNone},
+		{name: "runs only", combined: status("pending", 0), runs: []*github.CheckRun{run("completed", "success")}, expected: ChecksStateSuccess},
+		{name: "statuses only", combined: status("success", 2), expected: ChecksStateSuccess},
+		{name: "status pending", combined: status("pending", 1), runs: []*github.CheckRun{run("completed", "success")}, expected: ChecksStatePending},
+		{name: "status failed", combined: status("failure", 1), runs: []*github.CheckRun{run("in_progress", "")}, expected: ChecksStateFailure},
+		{name: "run in progress", combined: status("success", 1), runs: []*github.CheckRun{run("in_progress", "")}, expected: ChecksStatePending},
+		{name: "run failed", combined: status("success", 1), runs: []*github.CheckRun{run("in_progress", ""), run("completed", "failure")}, expected: ChecksStateFailure},
+		{name: "neutral run", combined: status("pending", 0), runs: []*github.CheckRun{run("completed", "neutral")}, expected: ChecksStateSuccess},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, combineChecksState(tt.combined, tt.runs))
+		})
+	}
+}
+
+func TestMergePR(t *testing.T) {
+	client, mux, _ := setup(t)
+
+	mux.HandleFunc("/repos/owner/repo/pulls/42/merge", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+
+		var req map[string]any
+		err := json.NewDecoder(r.Body).Decode(&req)
+		require.NoError(t, err)
+		assert.Equal(t, "sha-1", req["sha"])
+		assert.Equal(t, "squash", req["merge_method"])
+
+		_, _ = fmt.Fprint(w, `{"merged":true}`)
+	})
+
+	err := client.MergePR(context.Background(), "owner", "repo", 42, "sha-1", "squash")
+	require.NoError(t, err)
+}
+
+func TestMergePR_RejectedReportsGitHubMessage(t *testing.T) {
+	client, mux, _ := setup(t)
+
+	mux.HandleFunc("/repos/owner/repo/pulls/42/merge", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_, _ = fmt.Fprint(w, `{"message":"Required status check \"build\" is expected."}`)
+	})
+
+	err := client.MergePR(context.Background(), "owner", "repo", 42, "sha-1", "merge")
+	require.Error(t, err)
+	assert.Equal(t, `Required status check "build" is expected.`, ErrorMessage(err))
+}
+
 func TestParsePRURL(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -0,0 +1,88 @@
+package ghclient
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// RepoMapping maps the host and path prefix a PR URL is served under (for
+// example a mirror or a URL rewrite) to the canonical owner/repo the GitHub
+// API expects.
+type RepoMapping struct {
+	Prefix string // Lowercased host and path, e.g. "git.example.com/mirrors/app"
+	Owner  string
+	Repo   string
+}
+
+var ownerRepoRegex = regexp.MustCompile(`^[A-Za-z0-9._-]+/[A-Za-z0-9._-]+$`)
+
+// ParseRepoMappings parses mappings of the form "host/path=owner/repo", one
+// per line or comma-separated. A scheme on the left-hand side is ignored.
+func ParseRepoMappings(spec string) ([]RepoMapping, error) {
+	var mappings []RepoMapping
+	for _, entry := range strings.FieldsFunc(spec, func(r rune) bool { return r == '\n' || r == ',' }) {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		from, to, found := strings.Cut(entry, "=")
+		if !found {
+			return nil, fmt.Errorf("repo mapping %q must be in host/path=owner/repo format", entry)
+		}
+		prefix := normalizeURLPrefix(from)
+		if !strings.Contains(prefix, "/") {
+			return nil, fmt.Errorf("repo mapping %q must include a path after the host", entry)
+		}
+		to = strings.TrimSpace(to)
+		if !ownerRepoRegex.MatchString(to) {
+			return nil, fmt.Errorf("repo mapping %q must map to owner/repo", entry)
+		}
+		owner, repo, _ := strings.Cut(to, "/")
+		mappings = append(mappings, RepoMapping{Prefix: prefix, Owner: owner, Repo: repo})
+	}
+	return mappings, nil
+}
+
+// ParsePRURLWithMappings parses a PR URL, resolving the owner and repo through
+// the first mapping whose prefix matches everything before "/pull/<number>".
+// URLs that match no mapping are parsed with ParsePRURL.
+func ParsePRURLWithMappings(rawURL string, mappings []RepoMapping) (*PRReference, error) {
+	if len(mappings) > 0 {
+		if prefix, number, ok := splitPRURL(rawURL); ok {
+			for _, mapping := range mappings {
+				if prefix == mapping.Prefix {
+					return &PRReference{Owner: mapping.Owner, Repo: mapping.Repo, Number: number}, nil
+				}
+			}
+		}
+	}
+	return ParsePRURL(rawURL)
+}
+
+// splitPRURL splits a PR URL into its normalized host/path prefix and number.
+func splitPRURL(rawURL string) (string, int, bool) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || u.Host == "" {
+		return "", 0, false
+	}
+	path, rest, found := strings.Cut(u.Path, "/pull/")
+	if !found {
+		return "", 0, false
+	}
+	numberText, _, _ := strings.Cut(rest, "/")
+	number, err := strconv.Atoi(numberText)
+	if err != nil || number <= 0 {
+		return "", 0, false
+	}
+	return normalizeURLPrefix(u.Host + path), number, true
+}
+
+func normalizeURLPrefix(prefix string) string {
+	prefix = strings.ToLower(strings.TrimSpace(prefix))
+	prefix = strings.TrimPrefix(prefix, "https://")
+	prefix = strings.TrimPrefix(prefix, "http://")
+	return strings.Trim(prefix, "/")
+}
@@ -0,0 +1,89 @@
+package ghclient
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRepoMappings(t *testing.T) {
+	mappings, err := ParseRepoMappings(`
+		https://git.example.com/mirrors/App/=acme/app
+		github.com/old-org/widget=new-org/widget, ghe.internal/team/api=acme/api
+	`)
+	require.NoError(t, err)
+	assert.Equal(t, []RepoMapping{
+		{Prefix: "git.example.com/mirrors/app", Owner: "acme", Repo: "app"},
+		{Prefix: "github.com/old-org/widget", Owner: "new-org", Repo: "widget"},
+		{Prefix: "ghe.internal/team/api", Owner: "acme", Repo: "api"},
+	}, mappings)
+
+	empty, err := ParseRepoMappings("  ")
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+}
+
+func TestParseRepoMappings_Invalid(t *testing.T) {
+	for _, spec := range []string{
+		"git.example.com/mirrors/app",
+		"git.example.com=acme/app",
+		"git.example.com/mirrors/app=acme",
+		"git.example.com/mirrors/app=acme/app/extra",
+	} {
+		t.Run(spec, func(t *testing.T) {
+			_, err := ParseRepoMappings(spec)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParsePRURLWithMappings(t *testing.T) {
+	mappings, err := ParseRepoMappings("git.example.com/mirrors/app=acme/app,github.com/old-org/widget=new-org/widget")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		url     string
+		want    *PRReference
+		wantErr bool
+	}{
+		{
+			name: "mirror host resolves to canonical repo",
+			url:  "https://git.example.com/mirrors/app/pull/7",
+			want: &PRReference{Owner: "acme", Repo: "app", Number: 7},
+		},
+		{
+			name: "mapping match is case-insensitive and ignores trailing path",
+			url:  "https://Git.Example.com/Mirrors/App/pull/7/files",
+			want: &PRReference{Owner: "acme", Repo: "app", Number: 7},
+		},
+		{
+			name: "renamed github.com repo resolves to canonical repo",
+			url:  "https://github.com/old-org/widget/pull/3",
+			want: &PRReference{Owner: "new-org", Repo: "widget", Number: 3},
+		},
+		{
+			name: "unmapped github.com URL parses normally",
+			url:  "https://github.com/owner/repo/pull/42",
+			want: &PRReference{Owner: "owner", Repo: "repo", Number: 42},
+		},
+		{
+			name:    "unmapped mirror URL is rejected",
+			url:     "https://git.example.com/mirrors/other/pull/7",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePRURLWithMappings(tt.url, mappings)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
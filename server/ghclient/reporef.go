@@ -0,0 +1,136 @@
+package ghclient
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// defaultRepoHost is the host assumed for owner/repo shorthand.
+const defaultRepoHost = "github.com"
+
+// Reasons a repository reference is rejected. RepoRefError wraps one of these,
+// so callers can tell them apart with errors.Is.
+var (
+	ErrRepoRefEmpty         = errors.New("no repository given")
+	ErrRepoRefMalformed     = errors.New("must be in owner/repo format")
+	ErrRepoRefTrailingSlash = errors.New("must not end with a slash")
+	ErrRepoRefInvalidName   = errors.New("owner and repository names may only contain letters, digits, '.', '_' and '-'")
+)
+
+// repoNameRegex matches a single owner or repository name.
+var repoNameRegex = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// RepoRefError reports why a repository reference could not be parsed.
+type RepoRefError struct {
+	Input string
+	Err   error
+}
+
+func (e *RepoRefError) Error() string {
+	return fmt.Sprintf("invalid repository %q: %s", e.Input, e.Err)
+}
+
+func (e *RepoRefError) Unwrap() error {
+	return e.Err
+}
+
+// RepoRef is a validated repository reference.
+type RepoRef struct {
+	Host  string // Lowercased host, e.g. "github.com"
+	Owner string
+	Repo  string
+}
+
+// FullName returns "owner/repo", the form the GitHub API expects.
+func (r *RepoRef) FullName() string {
+	return r.Owner + "/" + r.Repo
+}
+
+// String returns the canonical form stored on records: "owner/repo" for
+// github.com, or "host/owner/repo" for any other host. ParseRepoRef parses it
+// back to the same reference.
+func (r *RepoRef) String() string {
+	if r.Host == defaultRepoHost {
+		return r.FullName()
+	}
+	return r.Host + "/" + r.FullName()
+}
+
+// URL returns the repository's https URL.
+func (r *RepoRef) URL() string {
+	return "https://" + r.Host + "/" + r.FullName()
+}
+
+// ParseRepoRef parses a repository given as "owner/repo" shorthand or as a
+// URL: "https://github.com/owner/repo", "github.com/owner/repo" or
+// "git@github.com:owner/repo.git". A ".git" suffix is stripped, shorthand
+// defaults to github.com, and URL paths past the repository (such as
+// "/pull/42") are ignored. Errors are *RepoRefError.
+func ParseRepoRef(input string) (*RepoRef, error) {
+	raw := strings.TrimSpace(input)
+	if raw == "" {
+		return nil, &RepoRefError{Input: input, Err: ErrRepoRefEmpty}
+	}
+
+	host, path, isURL, err := splitRepoHost(raw)
+	if err != nil {
+		return nil, &RepoRefError{Input: input, Err: err}
+	}
+
+	segments := strings.Split(path, "/")
+	switch {
+	case isURL && len(segments) > 2:
+		// Ignore the rest of the URL, e.g. "/pull/42" or a trailing slash.
+		segments = segments[:2]
+	case !isURL && len(segments) == 3 && segments[2] == "":
+		return nil, &RepoRefError{Input: input, Err: ErrRepoRefTrailingSlash}
+	}
+	if len(segments) != 2 {
+		return nil, &RepoRefError{Input: input, Err: ErrRepoRefMalformed}
+	}
+
+	owner := segments[0]
+	repo := segments[1]
+	if len(repo) >= len(".git") && strings.EqualFold(repo[len(repo)-len(".git"):], ".git") {
+		repo = repo[:len(repo)-len(".git")]
+	}
+	if owner == "" || repo == "" {
+		return nil, &RepoRefError{Input: input, Err: ErrRepoRefMalformed}
+	}
+	for _, name := range []string{owner, repo} {
+		if !repoNameRegex.MatchString(name) || name == "." || name == ".." {
+			return nil, &RepoRefError{Input: input, Err: ErrRepoRefInvalidName}
+		}
+	}
+
+	return &RepoRef{Host: host, Owner: owner, Repo: repo}, nil
+}
+
+// splitRepoHost separates the host from the owner/repo path. Shorthand has
+// no host; a leading segment containing a dot ("github.com/owner/repo") is
+// taken as one.
+func splitRepoHost(raw string) (host, path string, isURL bool, err error) {
+	switch {
+	case strings.Contains(raw, "://"):
+		u, parseErr := url.Parse(raw)
+		if parseErr != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return "", "", false, ErrRepoRefMalformed
+		}
+		return strings.ToLower(u.Host), strings.TrimPrefix(u.Path, "/"), true, nil
+	case strings.HasPrefix(raw, "git@"):
+		hostPart, rest, found := strings.Cut(strings.TrimPrefix(raw, "git@"), ":")
+		if !found || hostPart == "" {
+			return "", "", false, ErrRepoRefMalformed
+		}
+		return strings.ToLower(hostPart), rest, true, nil
+	}
+
+	first, rest, found := strings.Cut(raw, "/")
+	if found && strings.Contains(first, ".") && strings.Contains(rest, "/") {
+		return strings.ToLower(first), rest, true, nil
+	}
+	return defaultRepoHost, raw, false, nil
+}
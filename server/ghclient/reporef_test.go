@@ -0,0 +1,98 @@
+package ghclient
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRepoRef_Valid(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		host      string
+		owner     string
+		repo      string
+		canonical string
+	}{
+		{"shorthand", "mattermost/mattermost", "github.com", "mattermost", "mattermost", "mattermost/mattermost"},
+		{"shorthand with whitespace", "  org/my-repo.js \n", "github.com", "org", "my-repo.js", "org/my-repo.js"},
+		{"shorthand with .git", "org/repo.git", "github.com", "org", "repo", "org/repo"},
+		{"shorthand with .GIT", "org/repo.GIT", "github.com", "org", "repo", "org/repo"},
+		{"https URL", "https://github.com/org/repo", "github.com", "org", "repo", "org/repo"},
+		{"URL with .git and trailing slash", "https://github.com/org/repo.git/", "github.com", "org", "repo", "org/repo"},
+		{"URL host is lowercased", "https://GitHub.com/Org/Repo", "github.com", "Org", "Repo", "Org/Repo"},
+		{"PR URL", "https://github.com/org/repo/pull/42", "github.com", "org", "repo", "org/repo"},
+		{"host without scheme", "github.com/org/repo", "github.com", "org", "repo", "org/repo"},
+		{"SSH remote", "git@github.com:org/repo.git", "github.com", "org", "repo", "org/repo"},
+		{"enterprise host", "https://ghe.example.com/team/service", "ghe.example.com", "team", "service", "ghe.example.com/team/service"},
+		{"enterprise canonical form", "ghe.example.com/team/service", "ghe.example.com", "team", "service", "ghe.example.com/team/service"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ref, err := ParseRepoRef(tc.input)
+			require.NoError(t, err)
+			assert.Equal(t, tc.host, ref.Host)
+			assert.Equal(t, tc.owner, ref.Owner)
+			assert.Equal(t, tc.repo, ref.Repo)
+			assert.Equal(t, tc.canonical, ref.String())
+			assert.Equal(t, tc.owner+"/"+tc.repo, ref.FullName())
+		})
+	}
+}
+
+func TestParseRepoRef_Malformed(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  error
+	}{
+		{"empty", "", ErrRepoRefEmpty},
+		{"blank", "   ", ErrRepoRefEmpty},
+		{"no slash", "justrepo", ErrRepoRefMalformed},
+		{"empty owner", "/repo", ErrRepoRefMalformed},
+		{"empty repo", "owner/", ErrRepoRefMalformed},
+		{"only .git", "owner/.git", ErrRepoRefMalformed},
+		{"trailing slash", "owner/repo/", ErrRepoRefTrailingSlash},
+		{"too many segments", "owner/repo/extra", ErrRepoRefMalformed},
+		{"invalid characters", "own er/repo", ErrRepoRefInvalidName},
+		{"dot-dot repo", "owner/..", ErrRepoRefInvalidName},
+		{"URL without repo", "https://github.com/owner", ErrRepoRefMalformed},
+		{"unsupported scheme", "ftp://github.com/owner/repo", ErrRepoRefMalformed},
+		{"SSH remote without path", "git@github.com", ErrRepoRefMalformed},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ref, err := ParseRepoRef(tc.input)
+			require.Error(t, err)
+			assert.Nil(t, ref)
+			assert.ErrorIs(t, err, tc.want)
+
+			var refErr *RepoRefError
+			require.True(t, errors.As(err, &refErr))
+			assert.Equal(t, tc.input, refErr.Input)
+		})
+	}
+}
+
+func TestParseRepoRef_URLVersusShorthand(t *testing.T) {
+	shorthand, err := ParseRepoRef("org/repo")
+	require.NoError(t, err)
+	url, err := ParseRepoRef("https://github.com/org/repo.git")
+	require.NoError(t, err)
+
+	assert.Equal(t, shorthand, url)
+	assert.Equal(t, "https://github.com/org/repo", shorthand.URL())
+
+	// The canonical form of a non-GitHub host round-trips through ParseRepoRef.
+	enterprise, err := ParseRepoRef("https://ghe.example.com/team/service")
+	require.NoError(t, err)
+	roundTripped, err := ParseRepoRef(enterprise.String())
+	require.NoError(t, err)
+	assert.Equal(t, enterprise, roundTripped)
+	assert.Equal(t, "https://ghe.example.com/team/service", roundTripped.URL())
+}
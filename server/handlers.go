@@ -15,6 +15,7 @@ import (
 	"sort"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 	"github.com/mattermost/mattermost-plugin-ai/public/bridgeclient"
@@ -23,21 +24,22 @@ import (
 	"github.com/mattermost/mattermost/server/public/pluginapi"
 
 	"github.com/mattermost/mattermost-plugin-cursor/server/attachments"
+	"github.com/mattermost/mattermost-plugin-cursor/server/command"
 	"github.com/mattermost/mattermost-plugin-cursor/server/cursor"
+	"github.com/mattermost/mattermost-plugin-cursor/server/ghclient"
 	"github.com/mattermost/mattermost-plugin-cursor/server/parser"
 	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
 )
 
 // MessageHasBeenPosted is invoked after a message is posted.
 func (p *Plugin) MessageHasBeenPosted(_ *plugin.Context, post *model.Post) {
-	// 1. Fast-reject: skip posts from the bot itself to prevent loops.
-	botUserID := p.getBotUserID()
-	if post.UserId == botUserID {
+	// 1. Fast-reject: skip posts from any of our bot identities to prevent loops.
+	if p.isBotUser(post.UserId) {
 		return
 	}
 
 	// 2. Use pluginapi ShouldProcessMessage to skip system messages, webhooks, and other bots.
-	shouldProcess, err := p.client.Post.ShouldProcessMessage(post, pluginapi.BotID(botUserID))
+	shouldProcess, err := p.client.Post.ShouldProcessMessage(post, pluginapi.BotID(p.getBotUserID()))
 	if err != nil {
 		p.API.LogError("ShouldProcessMessage failed", "error", err.Error())
 		return
@@ -46,16 +48,16 @@ func (p *Plugin) MessageHasBeenPosted(_ *plugin.Context, post *model.Post) {
 		return
 	}
 
-	// 3. Detect bot mention in the message.
-	botMention := "@" + p.getBotUsername()
-	if !containsMention(post.Message, botMention) {
+	// 3. Detect a mention of any bot identity in the message.
+	botMention := p.findBotMention(post.Message)
+	if botMention == "" {
 		// Not a direct mention. Check if this is a thread reply for follow-up.
 		p.handlePossibleFollowUp(post)
 		return
 	}
 
 	// Acknowledge the mention immediately with :eyes: reaction.
-	p.addReaction(post.Id, "eyes")
+	p.addReaction(post.ChannelId, post.Id, "eyes")
 
 	p.logDebug("Bot mention detected",
 		"post_id", post.Id,
@@ -66,19 +68,22 @@ func (p *Plugin) MessageHasBeenPosted(_ *plugin.Context, post *model.Post) {
 	)
 
 	// 4. Parse the mention message.
-	parsed := parser.Parse(post.Message, botMention)
+	parsed := p.parseMention(post.UserId, post.Message, botMention)
 	if parsed == nil {
-		p.removeReaction(post.Id, "eyes")
+		p.removeReaction(post.ChannelId, post.Id, "eyes")
 		// User just typed "@cursor" with no prompt -- post help text.
 		p.postBotReply(post, "Please provide a prompt. Example: `@cursor fix the login bug`")
 		return
 	}
+	parsed.FileIDs = post.FileIds
 
 	p.logDebug("Parsed mention",
 		"post_id", post.Id,
 		"prompt_length", len(parsed.Prompt),
 		"repository", parsed.Repository,
 		"branch", parsed.Branch,
+		"ref", parsed.Ref,
+		"base", parsed.Base,
 		"model", parsed.Model,
 		"force_new", parsed.ForceNew,
 	)
@@ -94,6 +99,19 @@ func (p *Plugin) MessageHasBeenPosted(_ *plugin.Context, post *model.Post) {
 	p.launchNewAgent(post, parsed)
 }
 
+// parseMention parses a mention message, expanding the user's launch aliases.
+// User settings are only loaded when the message references an "@name"
+// besides the bot itself.
+func (p *Plugin) parseMention(userID, message, botMention string) *parser.ParsedMention {
+	var aliases map[string]string
+	if len(parser.AliasReferences(message, botMention)) > 0 {
+		if settings, err := p.kvstore.GetUserSettings(userID); err == nil && settings != nil {
+			aliases = settings.Aliases
+		}
+	}
+	return parser.ParseWithAliases(message, botMention, aliases)
+}
+
 // containsMention checks if the message contains the bot mention.
 // Uses case-insensitive matching.
 func containsMention(message, botMention string) bool {
@@ -156,7 +174,7 @@ func (p *Plugin) handleMentionInThread(post *model.Post, parsed *parser.ParsedMe
 					} else {
 						workflow.PendingFeedback = feedbackText
 					}
-					workflow.UpdatedAt = time.Now().UnixMilli()
+					workflow.UpdatedAt = p.now().UnixMilli()
 					if err := p.kvstore.SaveWorkflow(workflow); err != nil {
 						p.API.LogError("Failed to save pending feedback from mention",
 							"workflow_id", workflow.ID,
@@ -210,16 +228,44 @@ func (p *Plugin) launchNewAgent(post *model.Post, parsed *parser.ParsedMention)
 		"auto_create_pr", autoCreatePR,
 	)
 
-	// Step 2: Validate -- repo is required.
+	// Step 2: Validate -- only the channel's allowed launchers may launch.
+	if !p.canLaunchInChannel(post.UserId, post.ChannelId) {
+		p.removeReaction(post.ChannelId, post.Id, "eyes")
+		p.sendEphemeralReply(post, command.LaunchNotPermittedMessage)
+		return
+	}
+
+	// Step 2a: Validate -- repo is required.
 	if repo == "" {
-		p.removeReaction(post.Id, "eyes")
+		p.removeReaction(post.ChannelId, post.Id, "eyes")
 		p.postBotReply(post, "No repository specified. Set a default with `/cursor settings` or specify one: `@cursor in org/repo, fix the bug`")
 		return
 	}
+	repoRef, err := ghclient.ParseRepoRef(repo)
+	if err != nil {
+		p.removeReaction(post.ChannelId, post.Id, "eyes")
+		p.postBotReply(post, formatRepoRefError(err))
+		return
+	}
+	repo = repoRef.String()
+
+	// Step 2b: Validate -- the resolved model must be on the admin allowlist.
+	if cfg := p.getConfiguration(); !cfg.IsModelAllowed(modelName) {
+		p.removeReaction(post.ChannelId, post.Id, "eyes")
+		p.sendEphemeralReply(post, formatModelNotAllowed(modelName, cfg.ParseAllowedModels()))
+		return
+	}
+
+	// Step 2c: Validate -- an explicit PR base must exist in the repo.
+	if parsed.Base != "" && p.baseBranchMissing(repo, parsed.Base) {
+		p.removeReaction(post.ChannelId, post.Id, "eyes")
+		p.postBotReply(post, fmt.Sprintf("Base branch `%s` was not found in `%s`. Check the `base=` option and try again.", parsed.Base, repo))
+		return
+	}
 
 	// Step 3: Swap :eyes: -> :hourglass_flowing_sand: to indicate launch in progress.
-	p.removeReaction(post.Id, "eyes")
-	p.addReaction(post.Id, "hourglass_flowing_sand")
+	p.removeReaction(post.ChannelId, post.Id, "eyes")
+	p.addReaction(post.ChannelId, post.Id, "hourglass_flowing_sand")
 
 	// Step 4: Enrich the prompt with thread context if this is a thread reply.
 	promptText := parsed.Prompt
@@ -231,6 +277,16 @@ func (p *Plugin) launchNewAgent(post *model.Post, parsed *parser.ParsedMention)
 		}
 	}
 
+	// Step 4a: Append files attached to the mention post itself. Thread replies
+	// already have their images collected by enrichFromThread.
+	if !parsed.SkipAttachments && len(parsed.FileIDs) > 0 {
+		attachmentText, attachmentImages := p.buildPromptAttachments(parsed.FileIDs, post.RootId == "")
+		if attachmentText != "" {
+			promptText += "\n\n" + attachmentText
+		}
+		promptImages = append(promptImages, attachmentImages...)
+	}
+
 	// Log the enriched prompt (truncate if very long to avoid log spam).
 	debugPrompt := promptText
 	if len(debugPrompt) > 500 {
@@ -262,7 +318,7 @@ func (p *Plugin) launchNewAgent(post *model.Post, parsed *parser.ParsedMention)
 			rootID = post.RootId
 		}
 
-		now := time.Now().UnixMilli()
+		now := p.now().UnixMilli()
 		workflow := &kvstore.HITLWorkflow{
 			ID:                uuid.New().String(),
 			UserID:            post.UserId,
@@ -272,8 +328,11 @@ func (p *Plugin) launchNewAgent(post *model.Post, parsed *parser.ParsedMention)
 			Phase:             kvstore.PhasePlanning,
 			Repository:        repo,
 			Branch:            branch,
+			BaseBranch:        parsed.Base,
+			SourceRef:         parsed.Ref,
 			Model:             modelName,
 			AutoCreatePR:      autoCreatePR,
+			Draft:             parsed.Draft != nil && *parsed.Draft,
 			OriginalPrompt:    parsed.Prompt,
 			ApprovedContext:   promptText, // Use enriched prompt as approved context
 			SkipContextReview: true,
@@ -297,17 +356,15 @@ func (p *Plugin) launchNewAgent(post *model.Post, parsed *parser.ParsedMention)
 	promptText = p.wrapPromptWithSystemInstructions(promptText)
 
 	// Step 6: Build the Cursor API request.
-	repoURL := repo
-	if !strings.Contains(repo, "://") {
-		repoURL = "https://github.com/" + repo
-	}
 	launchReq := cursor.LaunchAgentRequest{
 		Prompt: cursor.Prompt{Text: promptText, Images: promptImages},
-		Source: cursor.Source{Repository: repoURL, Ref: branch},
+		Source: cursor.Source{Repository: repoRef.URL(), Ref: launchSourceRef(branch, parsed.Ref)},
 		Target: &cursor.Target{
 			BranchName:   sanitizeBranchName(parsed.Prompt),
+			BaseBranch:   parsed.Base,
 			AutoCreatePr: autoCreatePR,
 			AutoBranch:   true,
+			Draft:        parsed.Draft != nil && *parsed.Draft,
 		},
 		Model: modelName,
 	}
@@ -317,15 +374,17 @@ func (p *Plugin) launchNewAgent(post *model.Post, parsed *parser.ParsedMention)
 		"source_repository", launchReq.Source.Repository,
 		"source_ref", launchReq.Source.Ref,
 		"target_branch", launchReq.Target.BranchName,
+		"target_base_branch", launchReq.Target.BaseBranch,
 		"target_auto_create_pr", launchReq.Target.AutoCreatePr,
+		"target_draft", launchReq.Target.Draft,
 		"model", launchReq.Model,
 	)
 
 	// Step 7: Call Cursor API to launch the agent.
 	cursorClient := p.getCursorClient()
 	if cursorClient == nil {
-		p.removeReaction(post.Id, "hourglass_flowing_sand")
-		p.addReaction(post.Id, "x")
+		p.removeReaction(post.ChannelId, post.Id, "hourglass_flowing_sand")
+		p.addReaction(post.ChannelId, post.Id, "x")
 		p.postBotReply(post, "Cursor API key is not configured. Ask your admin to configure the plugin.")
 		return
 	}
@@ -336,8 +395,8 @@ func (p *Plugin) launchNewAgent(post *model.Post, parsed *parser.ParsedMention)
 	agent, err := cursorClient.LaunchAgent(ctx, launchReq)
 	if err != nil {
 		p.API.LogError("Failed to launch Cursor agent", "error", err.Error())
-		p.removeReaction(post.Id, "hourglass_flowing_sand")
-		p.addReaction(post.Id, "x")
+		p.removeReaction(post.ChannelId, post.Id, "hourglass_flowing_sand")
+		p.addReaction(post.ChannelId, post.Id, "x")
 		p.postBotReply(post, formatAPIError("Failed to launch agent", err))
 		return
 	}
@@ -350,14 +409,14 @@ func (p *Plugin) launchNewAgent(post *model.Post, parsed *parser.ParsedMention)
 
 	attachment := attachments.BuildLaunchAttachment(agent.ID, repo, branch, modelName)
 	replyPost := &model.Post{
-		UserId:    p.getBotUserID(),
+		UserId:    p.botUserIDForChannel(post.ChannelId),
 		ChannelId: post.ChannelId,
 		RootId:    rootID,
 	}
 	model.ParseSlackAttachment(replyPost, []*model.SlackAttachment{attachment})
 	replyPost.AddProp("cursor_agent_id", agent.ID)
 	replyPost.AddProp("cursor_agent_status", string(agent.Status))
-	createdReply, appErr := p.API.CreatePost(replyPost)
+	createdReply, appErr := p.API.CreatePost(p.decorateBotPost(replyPost))
 	if appErr != nil {
 		p.API.LogError("Failed to create bot reply", "error", appErr.Error())
 	}
@@ -367,7 +426,7 @@ func (p *Plugin) launchNewAgent(post *model.Post, parsed *parser.ParsedMention)
 	if createdReply != nil {
 		botReplyID = createdReply.Id
 	}
-	now := time.Now().UnixMilli()
+	now := p.now().UnixMilli()
 	agentRecord := &kvstore.AgentRecord{
 		CursorAgentID:  agent.ID,
 		Status:         string(agent.Status),
@@ -378,6 +437,9 @@ func (p *Plugin) launchNewAgent(post *model.Post, parsed *parser.ParsedMention)
 		Repository:     repo,
 		Branch:         branch,
 		TargetBranch:   launchReq.Target.BranchName,
+		BaseBranch:     parsed.Base,
+		SourceRef:      parsed.Ref,
+		Draft:          launchReq.Target.Draft,
 		Prompt:         parsed.Prompt,
 		Model:          modelName,
 		BotReplyPostID: botReplyID,
@@ -394,13 +456,25 @@ func (p *Plugin) launchNewAgent(post *model.Post, parsed *parser.ParsedMention)
 	if err := p.kvstore.SetThreadAgent(rootID, agent.ID); err != nil {
 		p.API.LogError("Failed to save thread mapping", "error", err.Error())
 	}
+	p.recordLaunchHistory(post.UserId, kvstore.LaunchHistoryEntry{
+		Prompt:       parsed.Prompt,
+		Repository:   repo,
+		Branch:       branch,
+		SourceRef:    parsed.Ref,
+		BaseBranch:   parsed.Base,
+		Model:        modelName,
+		AutoCreatePR: &launchReq.Target.AutoCreatePr,
+		Draft:        launchReq.Target.Draft,
+		LaunchedAt:   now,
+	})
 
 	// Step 11: Publish WebSocket event for real-time frontend updates.
 	p.publishAgentCreated(agentRecord)
 }
 
 // resolveDefaults resolves repo, branch, model, and autoCreatePR from the cascade:
-// parsed mention > channel settings > user settings > global config.
+// parsed mention > channel settings > user settings > channel-linked repo >
+// global config.
 func (p *Plugin) resolveDefaults(post *model.Post, parsed *parser.ParsedMention) (repo, branch, modelName string, autoCreatePR bool) {
 	config := p.getConfiguration()
 
@@ -409,12 +483,14 @@ func (p *Plugin) resolveDefaults(post *model.Post, parsed *parser.ParsedMention)
 	branch = config.DefaultBranch
 	modelName = config.DefaultModel
 	autoCreatePR = config.AutoCreatePR
+	repoFromSettings := false
 
 	// Override with user-level settings (if set).
 	userSettings, _ := p.kvstore.GetUserSettings(post.UserId)
 	if userSettings != nil {
 		if userSettings.DefaultRepository != "" {
 			repo = userSettings.DefaultRepository
+			repoFromSettings = true
 		}
 		if userSettings.DefaultBranch != "" {
 			branch = userSettings.DefaultBranch
@@ -429,12 +505,21 @@ func (p *Plugin) resolveDefaults(post *model.Post, parsed *parser.ParsedMention)
 	if channelSettings != nil {
 		if channelSettings.DefaultRepository != "" {
 			repo = channelSettings.DefaultRepository
+			repoFromSettings = true
 		}
 		if channelSettings.DefaultBranch != "" {
 			branch = channelSettings.DefaultBranch
 		}
 	}
 
+	// Fall back to the GitHub repository linked to the channel (if enabled)
+	// before the global default.
+	if !repoFromSettings && parsed.Repository == "" {
+		if linked := p.channelLinkedRepositoryFallback(post.ChannelId); linked != "" {
+			repo = linked
+		}
+	}
+
 	// Override with explicit values from the parsed mention (highest priority).
 	if parsed.Repository != "" {
 		repo = parsed.Repository
@@ -452,6 +537,63 @@ func (p *Plugin) resolveDefaults(post *model.Post, parsed *parser.ParsedMention)
 	return repo, branch, modelName, autoCreatePR
 }
 
+// launchSourceRef returns the ref an agent starts from: the explicit "ref="
+// option when given, otherwise the resolved branch.
+func launchSourceRef(branch, ref string) string {
+	if ref != "" {
+		return ref
+	}
+	return branch
+}
+
+// recordLaunchHistory adds a launch to the user's recent launches for
+// /cursor recent. Failures are logged and never block the launch.
+func (p *Plugin) recordLaunchHistory(userID string, entry kvstore.LaunchHistoryEntry) {
+	if err := p.kvstore.AddUserLaunchHistoryEntry(userID, entry, command.LaunchHistorySize); err != nil {
+		p.API.LogWarn("Failed to record launch history", "user_id", userID, "error", err.Error())
+	}
+}
+
+// allowedModels returns the admin's model allowlist, or nil when every model
+// may be selected.
+func (p *Plugin) allowedModels() []string {
+	return p.getConfiguration().ParseAllowedModels()
+}
+
+// formatModelNotAllowed builds the message shown when a launch selects a model
+// outside the configured allowlist.
+func formatModelNotAllowed(modelName string, allowed []string) string {
+	quoted := make([]string, len(allowed))
+	for i, m := range allowed {
+		quoted[i] = "`" + m + "`"
+	}
+	return fmt.Sprintf("Model `%s` is not allowed on this server. Choose one of: %s.", modelName, strings.Join(quoted, ", "))
+}
+
+// baseBranchMissing reports whether GitHub confirms that base does not exist
+// in repo. Without a GitHub client, or when the lookup fails for any other
+// reason, the branch is assumed to exist and the launch proceeds.
+func (p *Plugin) baseBranchMissing(repo, base string) bool {
+	ghClient := p.getGitHubClient()
+	if ghClient == nil {
+		return false
+	}
+	repoRef, err := ghclient.ParseRepoRef(repo)
+	if err != nil {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := ghClient.GetBranch(ctx, repoRef.Owner, repoRef.Repo, base); err != nil {
+		if ghclient.IsNotFound(err) {
+			return true
+		}
+		p.API.LogWarn("Failed to verify base branch", "repository", repo, "base", base, "error", err.Error())
+	}
+	return false
+}
+
 // sendFollowUp sends a follow-up message to a running agent.
 func (p *Plugin) sendFollowUp(post *model.Post, agentRecord *kvstore.AgentRecord) {
 	p.logDebug("Sending follow-up to agent",
@@ -461,15 +603,10 @@ func (p *Plugin) sendFollowUp(post *model.Post, agentRecord *kvstore.AgentRecord
 	)
 
 	// Step 1: Add eyes reaction to acknowledge the follow-up.
-	p.addReaction(post.Id, "eyes")
+	p.addReaction(post.ChannelId, post.Id, "eyes")
 
 	// Step 2: Extract the follow-up text.
-	followUpText := post.Message
-	botMention := "@" + p.getBotUsername()
-	if idx := strings.Index(strings.ToLower(followUpText), strings.ToLower(botMention)); idx >= 0 {
-		followUpText = followUpText[:idx] + followUpText[idx+len(botMention):]
-	}
-	followUpText = strings.TrimSpace(followUpText)
+	followUpText := strings.TrimSpace(p.stripBotMention(post.Message))
 
 	if followUpText == "" {
 		return
@@ -484,8 +621,8 @@ func (p *Plugin) sendFollowUp(post *model.Post, agentRecord *kvstore.AgentRecord
 	// Step 3: Call Cursor API to send follow-up.
 	cursorClient := p.getCursorClient()
 	if cursorClient == nil {
-		p.removeReaction(post.Id, "eyes")
-		p.addReaction(post.Id, "x")
+		p.removeReaction(post.ChannelId, post.Id, "eyes")
+		p.addReaction(post.ChannelId, post.Id, "x")
 		p.postBotReply(post, "Cursor API key is not configured.")
 		return
 	}
@@ -498,8 +635,8 @@ func (p *Plugin) sendFollowUp(post *model.Post, agentRecord *kvstore.AgentRecord
 	})
 	if err != nil {
 		p.API.LogError("Failed to send follow-up", "agentID", agentRecord.CursorAgentID, "error", err.Error())
-		p.removeReaction(post.Id, "eyes")
-		p.addReaction(post.Id, "x")
+		p.removeReaction(post.ChannelId, post.Id, "eyes")
+		p.addReaction(post.ChannelId, post.Id, "x")
 		p.postBotReply(post, formatAPIError("Failed to send follow-up", err))
 		return
 	}
@@ -520,10 +657,10 @@ func (p *Plugin) getThreadAgentRecord(rootPostID string) (*kvstore.AgentRecord,
 	return p.kvstore.GetAgent(agentID)
 }
 
-// addReaction adds an emoji reaction to a post as the bot user.
-func (p *Plugin) addReaction(postID, emojiName string) {
+// addReaction adds an emoji reaction to a post as the channel's bot user.
+func (p *Plugin) addReaction(channelID, postID, emojiName string) {
 	_, appErr := p.API.AddReaction(&model.Reaction{
-		UserId:    p.getBotUserID(),
+		UserId:    p.botUserIDForChannel(channelID),
 		PostId:    postID,
 		EmojiName: emojiName,
 	})
@@ -532,10 +669,10 @@ func (p *Plugin) addReaction(postID, emojiName string) {
 	}
 }
 
-// removeReaction removes an emoji reaction from a post.
-func (p *Plugin) removeReaction(postID, emojiName string) {
+// removeReaction removes the channel's bot user's emoji reaction from a post.
+func (p *Plugin) removeReaction(channelID, postID, emojiName string) {
 	appErr := p.API.RemoveReaction(&model.Reaction{
-		UserId:    p.getBotUserID(),
+		UserId:    p.botUserIDForChannel(channelID),
 		PostId:    postID,
 		EmojiName: emojiName,
 	})
@@ -550,21 +687,38 @@ func (p *Plugin) postBotReply(post *model.Post, message string) {
 	if post.RootId != "" {
 		rootID = post.RootId
 	}
-	_, appErr := p.API.CreatePost(&model.Post{
-		UserId:    p.getBotUserID(),
+	_, appErr := p.API.CreatePost(p.decorateBotPost(&model.Post{
+		UserId:    p.botUserIDForChannel(post.ChannelId),
 		ChannelId: post.ChannelId,
 		RootId:    rootID,
 		Message:   message,
-	})
+	}))
 	if appErr != nil {
 		p.API.LogError("Failed to create bot reply", "error", appErr.Error())
 	}
 }
 
+// sendEphemeralReply shows message only to the author of post, in its thread.
+func (p *Plugin) sendEphemeralReply(post *model.Post, message string) {
+	rootID := post.Id
+	if post.RootId != "" {
+		rootID = post.RootId
+	}
+	_ = p.API.SendEphemeralPost(post.UserId, p.decorateBotPost(&model.Post{
+		UserId:    p.botUserIDForChannel(post.ChannelId),
+		ChannelId: post.ChannelId,
+		RootId:    rootID,
+		Message:   message,
+	}))
+}
+
 const (
 	maxThreadImages    = 5
 	maxThreadImageSize = 10 * 1024 * 1024 // 10MB total
 
+	maxPromptAttachments    = 5
+	maxPromptAttachmentSize = 256 * 1024 // 256KB total of inlined text
+
 	descriptionPrompt = `You are a ticket title generator. Your ONLY output is a single short noun phrase (5-10 words) summarizing the coding task. No explanation, no reasoning, no quotes, no punctuation at the end. Just the title.
 
 Examples of correct output:
@@ -730,6 +884,107 @@ func (p *Plugin) formatThread(postList *model.PostList) (string, []cursor.Image)
 	return sb.String(), images
 }
 
+// textAttachmentExtensions lists file extensions treated as plain text even
+// when Mattermost reports a generic MIME type for them.
+var textAttachmentExtensions = map[string]bool{
+	"txt": true, "log": true, "diff": true, "patch": true, "md": true,
+	"json": true, "yaml": true, "yml": true, "xml": true, "csv": true,
+	"go": true, "js": true, "ts": true, "tsx": true, "py": true, "sh": true,
+}
+
+// isTextAttachment reports whether a file can be inlined into the prompt as text.
+func isTextAttachment(info *model.FileInfo) bool {
+	if strings.HasPrefix(info.MimeType, "text/") {
+		return true
+	}
+	switch info.MimeType {
+	case "application/json", "application/xml", "application/x-yaml", "application/yaml":
+		return true
+	}
+	return textAttachmentExtensions[strings.ToLower(info.Extension)]
+}
+
+// buildPromptAttachments loads the files attached to the mention post and
+// formats them for the agent prompt. Text files (logs, diffs) are inlined,
+// images are referenced by name and dimensions, and anything else is listed
+// by name. Files past maxPromptAttachments or that would exceed the
+// maxPromptAttachmentSize budget are skipped with a note so the agent knows
+// they exist. When includeImages is true, image data is also returned for
+// the Cursor API request.
+func (p *Plugin) buildPromptAttachments(fileIDs []string, includeImages bool) (string, []cursor.Image) {
+	var sb strings.Builder
+	var images []cursor.Image
+	totalTextSize := 0
+	totalImageSize := 0
+
+	for i, fileID := range fileIDs {
+		if i >= maxPromptAttachments {
+			sb.WriteString(fmt.Sprintf("[Skipped %d more attachment(s): limit is %d per message]\n", len(fileIDs)-i, maxPromptAttachments))
+			break
+		}
+
+		fileInfo, appErr := p.API.GetFileInfo(fileID)
+		if appErr != nil {
+			p.API.LogWarn("Failed to get attachment info", "file_id", fileID, "error", appErr.Error())
+			continue
+		}
+
+		switch {
+		case isTextAttachment(fileInfo):
+			if totalTextSize+int(fileInfo.Size) > maxPromptAttachmentSize {
+				sb.WriteString(fmt.Sprintf("[Skipped attachment %s (%d KB): exceeds the %d KB attachment limit]\n",
+					fileInfo.Name, fileInfo.Size/1024, maxPromptAttachmentSize/1024))
+				continue
+			}
+			fileData, appErr := p.API.GetFile(fileID)
+			if appErr != nil {
+				p.API.LogWarn("Failed to load attachment", "file_id", fileID, "error", appErr.Error())
+				continue
+			}
+			if !utf8.Valid(fileData) {
+				sb.WriteString(fmt.Sprintf("[Skipped attachment %s: not valid UTF-8 text]\n", fileInfo.Name))
+				continue
+			}
+			totalTextSize += len(fileData)
+			sb.WriteString(fmt.Sprintf("[Attachment: %s]\n```\n%s\n```\n", fileInfo.Name, strings.TrimRight(string(fileData), "\n")))
+
+		case strings.HasPrefix(fileInfo.MimeType, "image/"):
+			if fileInfo.Width > 0 && fileInfo.Height > 0 {
+				sb.WriteString(fmt.Sprintf("[Image attachment: %s (%dx%d)]\n", fileInfo.Name, fileInfo.Width, fileInfo.Height))
+			} else {
+				sb.WriteString(fmt.Sprintf("[Image attachment: %s]\n", fileInfo.Name))
+			}
+			if !includeImages || len(images) >= maxThreadImages || totalImageSize+int(fileInfo.Size) > maxThreadImageSize {
+				continue
+			}
+			fileData, appErr := p.API.GetFile(fileID)
+			if appErr != nil {
+				continue
+			}
+			imgConfig, _, imgErr := image.DecodeConfig(bytes.NewReader(fileData))
+			if imgErr != nil {
+				continue
+			}
+			totalImageSize += len(fileData)
+			images = append(images, cursor.Image{
+				Data: base64.StdEncoding.EncodeToString(fileData),
+				Dimension: cursor.ImageDimension{
+					Width:  imgConfig.Width,
+					Height: imgConfig.Height,
+				},
+			})
+
+		default:
+			sb.WriteString(fmt.Sprintf("[Attachment %s (%s) not included: unsupported file type]\n", fileInfo.Name, fileInfo.MimeType))
+		}
+	}
+
+	if sb.Len() == 0 {
+		return "", images
+	}
+	return "--- Attachments ---\n" + sb.String() + "--- End Attachments ---", images
+}
+
 // getDisplayName returns the display name for a user, falling back to "unknown".
 func (p *Plugin) getDisplayName(userID string) string {
 	user, appErr := p.API.GetUser(userID)
@@ -841,6 +1096,26 @@ func (p *Plugin) generateDescription(contextText string) string {
 	return result
 }
 
+// formatRepoRefError explains why a repository named in a mention could not be
+// used for a launch.
+func formatRepoRefError(err error) string {
+	var refErr *ghclient.RepoRefError
+	if errors.As(err, &refErr) {
+		return fmt.Sprintf("Invalid repository `%s`: %s. Use `@cursor in owner/repo, ...` or a GitHub repository URL.", strings.TrimSpace(refErr.Input), refErr.Err)
+	}
+	return fmt.Sprintf("Invalid repository: %s.", err.Error())
+}
+
+// launchRepositoryURL returns the Cursor source URL for a stored repository
+// value, falling back to the raw value when it cannot be parsed.
+func launchRepositoryURL(repo string) string {
+	repoRef, err := ghclient.ParseRepoRef(repo)
+	if err != nil {
+		return repo
+	}
+	return repoRef.URL()
+}
+
 // formatAPIError formats an error from the Cursor API into a user-friendly Mattermost message.
 // If the error is a cursor.APIError with a JSON RawBody, the JSON is pretty-printed inside a
 // markdown code block so that Mattermost renders it cleanly (no emoji parsing, proper wrapping).
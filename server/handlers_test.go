@@ -3,12 +3,15 @@ package main
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"image"
 	"image/color"
 	"image/png"
+	"net/http"
 	"strings"
 	"testing"
 
+	"github.com/google/go-github/v68/github"
 	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
 	"github.com/mattermost/mattermost/server/public/pluginapi"
@@ -135,6 +138,10 @@ func (m *mockKVStore) DeleteAgent(cursorAgentID string) error {
 	return m.Called(cursorAgentID).Error(0)
 }
 
+func (m *mockKVStore) RemoveAgentFromUser(userID, cursorAgentID string) error {
+	return m.Called(userID, cursorAgentID).Error(0)
+}
+
 func (m *mockKVStore) ListActiveAgents() ([]*kvstore.AgentRecord, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
@@ -180,6 +187,22 @@ func (m *mockKVStore) SaveUserSettings(userID string, settings *kvstore.UserSett
 	return m.Called(userID, settings).Error(0)
 }
 
+func (m *mockKVStore) DeleteUserSettings(userID string) error {
+	return m.Called(userID).Error(0)
+}
+
+func (m *mockKVStore) GetUserLaunchHistory(userID string) (*kvstore.UserLaunchHistory, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*kvstore.UserLaunchHistory), args.Error(1)
+}
+
+func (m *mockKVStore) AddUserLaunchHistoryEntry(userID string, entry kvstore.LaunchHistoryEntry, keep int) error {
+	return m.Called(userID, entry, keep).Error(0)
+}
+
 func (m *mockKVStore) GetAgentByPRURL(prURL string) (*kvstore.AgentRecord, error) {
 	args := m.Called(prURL)
 	if args.Get(0) == nil {
@@ -205,6 +228,26 @@ func (m *mockKVStore) MarkDeliveryProcessed(deliveryID string) error {
 	return m.Called(deliveryID).Error(0)
 }
 
+func (m *mockKVStore) SaveWebhookDelivery(delivery *kvstore.WebhookDelivery, keep int) error {
+	return m.Called(delivery, keep).Error(0)
+}
+
+func (m *mockKVStore) GetWebhookDelivery(deliveryID string) (*kvstore.WebhookDelivery, error) {
+	args := m.Called(deliveryID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*kvstore.WebhookDelivery), args.Error(1)
+}
+
+func (m *mockKVStore) ListWebhookDeliveries() ([]*kvstore.WebhookDelivery, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*kvstore.WebhookDelivery), args.Error(1)
+}
+
 func (m *mockKVStore) GetWorkflow(workflowID string) (*kvstore.HITLWorkflow, error) {
 	args := m.Called(workflowID)
 	if args.Get(0) == nil {
@@ -278,6 +321,88 @@ func (m *mockKVStore) GetReviewLoopByAgent(agentRecordID string) (*kvstore.Revie
 	return args.Get(0).(*kvstore.ReviewLoop), args.Error(1)
 }
 
+func (m *mockKVStore) ListQuietHoursDeferredReviewLoops() ([]*kvstore.ReviewLoop, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*kvstore.ReviewLoop), args.Error(1)
+}
+
+func (m *mockKVStore) ListWorkflows() ([]*kvstore.HITLWorkflow, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*kvstore.HITLWorkflow), args.Error(1)
+}
+
+func (m *mockKVStore) ListActiveReviewLoops() ([]*kvstore.ReviewLoop, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*kvstore.ReviewLoop), args.Error(1)
+}
+
+func (m *mockKVStore) GetReviewLoopDigestDate() (string, error) {
+	args := m.Called()
+	return args.String(0), args.Error(1)
+}
+
+func (m *mockKVStore) SetReviewLoopDigestDate(date string) error {
+	args := m.Called(date)
+	return args.Error(0)
+}
+
+func (m *mockKVStore) ListGitHubRetryReviewLoops() ([]*kvstore.ReviewLoop, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*kvstore.ReviewLoop), args.Error(1)
+}
+
+func (m *mockKVStore) ListRateLimitedReviewLoops() ([]*kvstore.ReviewLoop, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*kvstore.ReviewLoop), args.Error(1)
+}
+
+func (m *mockKVStore) ListPendingDispatchReviewLoops() ([]*kvstore.ReviewLoop, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*kvstore.ReviewLoop), args.Error(1)
+}
+
+func (m *mockKVStore) ListGloballyPausedReviewLoops() ([]*kvstore.ReviewLoop, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*kvstore.ReviewLoop), args.Error(1)
+}
+
+func (m *mockKVStore) ListWaitingReviewLoops() ([]*kvstore.ReviewLoop, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*kvstore.ReviewLoop), args.Error(1)
+}
+
+func (m *mockKVStore) ListFixingReviewLoops() ([]*kvstore.ReviewLoop, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*kvstore.ReviewLoop), args.Error(1)
+}
+
 func (m *mockKVStore) GetAllFinishedAgentsWithPR() ([]*kvstore.AgentRecord, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
@@ -286,6 +411,19 @@ func (m *mockKVStore) GetAllFinishedAgentsWithPR() ([]*kvstore.AgentRecord, erro
 	return args.Get(0).([]*kvstore.AgentRecord), args.Error(1)
 }
 
+func (m *mockKVStore) ListIndexEntries() ([]kvstore.IndexEntry, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]kvstore.IndexEntry), args.Error(1)
+}
+
+func (m *mockKVStore) DeleteIndexEntry(key string) error {
+	args := m.Called(key)
+	return args.Error(0)
+}
+
 // setupTestPlugin creates a Plugin with mocked dependencies for handler testing.
 func setupTestPlugin(t *testing.T) (*Plugin, *plugintest.API, *mockCursorClient, *mockKVStore) {
 	t.Helper()
@@ -332,8 +470,13 @@ func setupTestPlugin(t *testing.T) (*Plugin, *plugintest.API, *mockCursorClient,
 		Username: "testuser",
 	}, nil).Maybe()
 
+	// Review loop notifications check that the owner is still in the channel.
+	api.On("GetChannelMember", mock.Anything, mock.Anything).Return(&model.ChannelMember{}, nil).Maybe()
+
 	cursorClient := &mockCursorClient{}
 	store := &mockKVStore{}
+	// Launches record the user's launch history; tests that care assert it.
+	store.On("AddUserLaunchHistoryEntry", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
 
 	p := &Plugin{}
 	p.SetAPI(api)
@@ -509,6 +652,236 @@ func TestMessageHasBeenPosted_NoRepo_PostsError(t *testing.T) {
 	api.AssertExpectations(t)
 }
 
+func TestMessageHasBeenPosted_DisallowedModel_Ephemeral(t *testing.T) {
+	p, api, cursorClient, store := setupTestPlugin(t)
+	p.configuration.AllowedModels = "auto, claude-sonnet"
+
+	post := &model.Post{
+		Id:        "post-1",
+		UserId:    "user-1",
+		ChannelId: "ch-1",
+		Message:   "@cursor with o3, fix the bug",
+	}
+
+	store.On("GetUserSettings", "user-1").Return(nil, nil)
+	store.On("GetChannelSettings", "ch-1").Return(nil, nil)
+	api.On("AddReaction", mock.Anything).Return(nil, nil)
+	api.On("RemoveReaction", mock.Anything).Return(nil)
+	api.On("SendEphemeralPost", "user-1", mock.MatchedBy(func(p *model.Post) bool {
+		return p.RootId == "post-1" &&
+			strings.Contains(p.Message, "Model `o3` is not allowed") &&
+			strings.Contains(p.Message, "`auto`, `claude-sonnet`")
+	})).Return(&model.Post{})
+
+	p.MessageHasBeenPosted(nil, post)
+
+	api.AssertExpectations(t)
+	cursorClient.AssertNotCalled(t, "LaunchAgent")
+	api.AssertNotCalled(t, "CreatePost", mock.Anything)
+}
+
+func TestMessageHasBeenPosted_AllowedModel_Launches(t *testing.T) {
+	p, api, cursorClient, store := setupTestPlugin(t)
+	p.configuration.AllowedModels = "auto,claude-sonnet"
+
+	post := &model.Post{
+		Id:        "post-1",
+		UserId:    "user-1",
+		ChannelId: "ch-1",
+		Message:   "@cursor with claude-sonnet, fix the bug",
+	}
+
+	store.On("GetUserSettings", "user-1").Return(nil, nil)
+	store.On("GetChannelSettings", "ch-1").Return(nil, nil)
+	api.On("AddReaction", mock.Anything).Return(nil, nil)
+	api.On("RemoveReaction", mock.Anything).Return(nil)
+
+	cursorClient.On("LaunchAgent", mock.Anything, mock.MatchedBy(func(req cursor.LaunchAgentRequest) bool {
+		return req.Model == "claude-sonnet"
+	})).Return(&cursor.Agent{ID: "agent-123", Status: cursor.AgentStatusCreating}, nil)
+
+	api.On("CreatePost", mock.Anything).Return(&model.Post{Id: "reply-1"}, nil)
+	store.On("SaveAgent", mock.Anything).Return(nil)
+	store.On("SetThreadAgent", "post-1", "agent-123").Return(nil)
+	api.On("PublishWebSocketEvent", "agent_created", mock.Anything, mock.Anything).Return()
+
+	p.MessageHasBeenPosted(nil, post)
+
+	cursorClient.AssertExpectations(t)
+	api.AssertNotCalled(t, "SendEphemeralPost", mock.Anything, mock.Anything)
+}
+
+func TestMessageHasBeenPosted_BaseOption_SetsTargetBase(t *testing.T) {
+	p, api, cursorClient, store := setupTestPlugin(t)
+	ghMock := &mockGitHubClient{}
+	p.githubClient = ghMock
+
+	post := &model.Post{
+		Id:        "post-1",
+		UserId:    "user-1",
+		ChannelId: "ch-1",
+		Message:   "@cursor base=release-1.2 backport the fix",
+	}
+
+	store.On("GetUserSettings", "user-1").Return(nil, nil)
+	store.On("GetChannelSettings", "ch-1").Return(nil, nil)
+	api.On("AddReaction", mock.Anything).Return(nil, nil)
+	api.On("RemoveReaction", mock.Anything).Return(nil)
+
+	ghMock.On("GetBranch", mock.Anything, "org", "default-repo", "release-1.2").
+		Return(&github.Branch{Name: github.Ptr("release-1.2")}, nil)
+
+	cursorClient.On("LaunchAgent", mock.Anything, mock.MatchedBy(func(req cursor.LaunchAgentRequest) bool {
+		return req.Source.Ref == "main" &&
+			req.Target != nil &&
+			req.Target.BaseBranch == "release-1.2" &&
+			strings.Contains(req.Prompt.Text, "backport the fix")
+	})).Return(&cursor.Agent{ID: "agent-123", Status: cursor.AgentStatusCreating}, nil)
+
+	api.On("CreatePost", mock.Anything).Return(&model.Post{Id: "reply-1"}, nil)
+	store.On("SaveAgent", mock.MatchedBy(func(r *kvstore.AgentRecord) bool {
+		return r.CursorAgentID == "agent-123" && r.Branch == "main" && r.BaseBranch == "release-1.2"
+	})).Return(nil)
+	store.On("SetThreadAgent", "post-1", "agent-123").Return(nil)
+	api.On("PublishWebSocketEvent", "agent_created", mock.Anything, mock.Anything).Return()
+
+	p.MessageHasBeenPosted(nil, post)
+
+	ghMock.AssertExpectations(t)
+	cursorClient.AssertExpectations(t)
+	store.AssertExpectations(t)
+}
+
+func TestMessageHasBeenPosted_RefOption_SetsSourceRef(t *testing.T) {
+	p, api, cursorClient, store := setupTestPlugin(t)
+
+	post := &model.Post{
+		Id:        "post-1",
+		UserId:    "user-1",
+		ChannelId: "ch-1",
+		Message:   "@cursor ref=4f83a92c reproduce the login crash",
+	}
+
+	store.On("GetUserSettings", "user-1").Return(nil, nil)
+	store.On("GetChannelSettings", "ch-1").Return(nil, nil)
+	api.On("AddReaction", mock.Anything).Return(nil, nil)
+	api.On("RemoveReaction", mock.Anything).Return(nil)
+
+	cursorClient.On("LaunchAgent", mock.Anything, mock.MatchedBy(func(req cursor.LaunchAgentRequest) bool {
+		return req.Source.Ref == "4f83a92c" &&
+			req.Target != nil &&
+			req.Target.BranchName == sanitizeBranchName("reproduce the login crash") &&
+			strings.Contains(req.Prompt.Text, "reproduce the login crash")
+	})).Return(&cursor.Agent{ID: "agent-123", Status: cursor.AgentStatusCreating}, nil)
+
+	api.On("CreatePost", mock.Anything).Return(&model.Post{Id: "reply-1"}, nil)
+	store.On("SaveAgent", mock.MatchedBy(func(r *kvstore.AgentRecord) bool {
+		return r.CursorAgentID == "agent-123" && r.Branch == "main" && r.SourceRef == "4f83a92c"
+	})).Return(nil)
+	store.On("SetThreadAgent", "post-1", "agent-123").Return(nil)
+	api.On("PublishWebSocketEvent", "agent_created", mock.Anything, mock.Anything).Return()
+
+	p.MessageHasBeenPosted(nil, post)
+
+	cursorClient.AssertExpectations(t)
+	store.AssertExpectations(t)
+}
+
+func TestMessageHasBeenPosted_NoPRFlag_DisablesAutoCreatePr(t *testing.T) {
+	p, api, cursorClient, store := setupTestPlugin(t)
+	p.configuration.AutoCreatePR = true
+
+	post := &model.Post{
+		Id:        "post-1",
+		UserId:    "user-1",
+		ChannelId: "ch-1",
+		Message:   "@cursor --no-pr try out the new cache layer",
+	}
+
+	store.On("GetUserSettings", "user-1").Return(nil, nil)
+	store.On("GetChannelSettings", "ch-1").Return(nil, nil)
+	api.On("AddReaction", mock.Anything).Return(nil, nil)
+	api.On("RemoveReaction", mock.Anything).Return(nil)
+
+	cursorClient.On("LaunchAgent", mock.Anything, mock.MatchedBy(func(req cursor.LaunchAgentRequest) bool {
+		return req.Target != nil &&
+			!req.Target.AutoCreatePr &&
+			req.Target.AutoBranch &&
+			req.Prompt.Text != "" && !strings.Contains(req.Prompt.Text, "--no-pr")
+	})).Return(&cursor.Agent{ID: "agent-123", Status: cursor.AgentStatusCreating}, nil)
+
+	api.On("CreatePost", mock.Anything).Return(&model.Post{Id: "reply-1"}, nil)
+	store.On("SaveAgent", mock.Anything).Return(nil)
+	store.On("SetThreadAgent", "post-1", "agent-123").Return(nil)
+	api.On("PublishWebSocketEvent", "agent_created", mock.Anything, mock.Anything).Return()
+
+	p.MessageHasBeenPosted(nil, post)
+
+	cursorClient.AssertExpectations(t)
+}
+
+func TestMessageHasBeenPosted_DraftOption_OpensDraftPR(t *testing.T) {
+	p, api, cursorClient, store := setupTestPlugin(t)
+
+	post := &model.Post{
+		Id:        "post-1",
+		UserId:    "user-1",
+		ChannelId: "ch-1",
+		Message:   "@cursor draft=true explore a new cache layer",
+	}
+
+	store.On("GetUserSettings", "user-1").Return(nil, nil)
+	store.On("GetChannelSettings", "ch-1").Return(nil, nil)
+	api.On("AddReaction", mock.Anything).Return(nil, nil)
+	api.On("RemoveReaction", mock.Anything).Return(nil)
+
+	cursorClient.On("LaunchAgent", mock.Anything, mock.MatchedBy(func(req cursor.LaunchAgentRequest) bool {
+		return req.Target != nil && req.Target.Draft && !strings.Contains(req.Prompt.Text, "draft=")
+	})).Return(&cursor.Agent{ID: "agent-123", Status: cursor.AgentStatusCreating}, nil)
+
+	api.On("CreatePost", mock.Anything).Return(&model.Post{Id: "reply-1"}, nil)
+	store.On("SaveAgent", mock.MatchedBy(func(r *kvstore.AgentRecord) bool {
+		return r.Draft
+	})).Return(nil)
+	store.On("SetThreadAgent", "post-1", "agent-123").Return(nil)
+	api.On("PublishWebSocketEvent", "agent_created", mock.Anything, mock.Anything).Return()
+
+	p.MessageHasBeenPosted(nil, post)
+
+	cursorClient.AssertExpectations(t)
+	store.AssertExpectations(t)
+}
+
+func TestMessageHasBeenPosted_BaseOption_MissingBranch(t *testing.T) {
+	p, api, cursorClient, store := setupTestPlugin(t)
+	ghMock := &mockGitHubClient{}
+	p.githubClient = ghMock
+
+	post := &model.Post{
+		Id:        "post-1",
+		UserId:    "user-1",
+		ChannelId: "ch-1",
+		Message:   "@cursor base=nope fix the bug",
+	}
+
+	store.On("GetUserSettings", "user-1").Return(nil, nil)
+	store.On("GetChannelSettings", "ch-1").Return(nil, nil)
+	api.On("AddReaction", mock.Anything).Return(nil, nil)
+	api.On("RemoveReaction", mock.Anything).Return(nil)
+
+	ghMock.On("GetBranch", mock.Anything, "org", "default-repo", "nope").
+		Return(nil, &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusNotFound}})
+
+	api.On("CreatePost", mock.MatchedBy(func(p *model.Post) bool {
+		return strings.Contains(p.Message, "Base branch `nope` was not found in `org/default-repo`")
+	})).Return(&model.Post{Id: "reply-1"}, nil)
+
+	p.MessageHasBeenPosted(nil, post)
+
+	api.AssertExpectations(t)
+	cursorClient.AssertNotCalled(t, "LaunchAgent")
+}
+
 func TestMessageHasBeenPosted_APIError_AddsX(t *testing.T) {
 	p, api, cursorClient, store := setupTestPlugin(t)
 
@@ -951,6 +1324,96 @@ func TestDefaultResolution(t *testing.T) {
 	assert.True(t, autoCreatePR)                // global default (no override)
 }
 
+func TestDefaultResolution_AfterUserSettingsReset(t *testing.T) {
+	p, _, _, store := setupTestPlugin(t)
+
+	// A reset user has no stored settings; the store returns an empty record.
+	store.On("GetUserSettings", "user-1").Return(&kvstore.UserSettings{}, nil)
+	store.On("GetChannelSettings", "ch-1").Return(&kvstore.ChannelSettings{
+		DefaultRepository: "channel/repo",
+	}, nil)
+
+	post := &model.Post{
+		UserId:    "user-1",
+		ChannelId: "ch-1",
+	}
+
+	repo, branch, modelName, autoCreatePR := p.resolveDefaults(post, &parser.ParsedMention{Prompt: "fix it"})
+	assert.Equal(t, "channel/repo", repo) // channel > global
+	assert.Equal(t, "main", branch)       // global
+	assert.Equal(t, "auto", modelName)    // global
+	assert.True(t, autoCreatePR)          // global
+}
+
+func TestDefaultResolution_ChannelLinkedRepo(t *testing.T) {
+	p, api, _, store := setupTestPlugin(t)
+	p.configuration.UseChannelLinkedRepo = true
+
+	store.On("GetUserSettings", "user-1").Return(nil, nil)
+	store.On("GetChannelSettings", "ch-1").Return(nil, nil)
+	api.On("GetChannel", "ch-1").Return(&model.Channel{
+		Id:     "ch-1",
+		Header: "Frontend team. Code: https://github.com/acme/web-app.git, board: https://example.com",
+	}, nil)
+
+	post := &model.Post{UserId: "user-1", ChannelId: "ch-1"}
+
+	repo, _, _, _ := p.resolveDefaults(post, &parser.ParsedMention{Prompt: "fix it"})
+	assert.Equal(t, "acme/web-app", repo) // linked > global
+
+	// An explicit repository still wins, without reading the channel.
+	repo, _, _, _ = p.resolveDefaults(post, &parser.ParsedMention{Prompt: "fix it", Repository: "explicit/repo"})
+	assert.Equal(t, "explicit/repo", repo)
+	api.AssertNumberOfCalls(t, "GetChannel", 1)
+}
+
+func TestDefaultResolution_ChannelLinkedRepoBelowSettings(t *testing.T) {
+	p, api, _, store := setupTestPlugin(t)
+	p.configuration.UseChannelLinkedRepo = true
+
+	store.On("GetUserSettings", "user-1").Return(&kvstore.UserSettings{DefaultRepository: "user/repo"}, nil)
+	store.On("GetChannelSettings", "ch-1").Return(nil, nil)
+
+	repo, _, _, _ := p.resolveDefaults(&model.Post{UserId: "user-1", ChannelId: "ch-1"}, &parser.ParsedMention{Prompt: "fix it"})
+	assert.Equal(t, "user/repo", repo)
+	api.AssertNotCalled(t, "GetChannel", mock.Anything)
+}
+
+func TestDefaultResolution_ChannelLinkedRepoDisabled(t *testing.T) {
+	p, api, _, store := setupTestPlugin(t)
+
+	store.On("GetUserSettings", "user-1").Return(nil, nil)
+	store.On("GetChannelSettings", "ch-1").Return(nil, nil)
+
+	repo, _, _, _ := p.resolveDefaults(&model.Post{UserId: "user-1", ChannelId: "ch-1"}, &parser.ParsedMention{Prompt: "fix it"})
+	assert.Equal(t, "org/default-repo", repo)
+	api.AssertNotCalled(t, "GetChannel", mock.Anything)
+}
+
+func TestChannelLinkedRepository(t *testing.T) {
+	p, api, _, _ := setupTestPlugin(t)
+
+	api.On("GetChannel", "ch-prop").Return(&model.Channel{
+		Header: "https://github.com/acme/from-header",
+		Props:  map[string]any{"github_repository": "acme/from-prop"},
+	}, nil)
+	api.On("GetChannel", "ch-purpose").Return(&model.Channel{
+		Header:  "Nothing linked here",
+		Purpose: "Tracks github.com/acme/from-purpose.",
+	}, nil)
+	api.On("GetChannel", "ch-none").Return(&model.Channel{Header: "See https://github.com/acme"}, nil)
+	api.On("GetChannel", "ch-prop-url").Return(&model.Channel{
+		Props: map[string]any{"github_repository": "https://github.com/acme/from-url.git"},
+	}, nil)
+	api.On("GetChannel", "ch-missing").Return(nil, &model.AppError{Message: "not found"})
+
+	assert.Equal(t, "acme/from-prop", p.channelLinkedRepository("ch-prop"))
+	assert.Equal(t, "acme/from-purpose", p.channelLinkedRepository("ch-purpose"))
+	assert.Empty(t, p.channelLinkedRepository("ch-none"))
+	assert.Equal(t, "acme/from-url", p.channelLinkedRepository("ch-prop-url"))
+	assert.Empty(t, p.channelLinkedRepository("ch-missing"))
+}
+
 func TestContainsMention(t *testing.T) {
 	assert.True(t, containsMention("hey @cursor fix it", "@cursor"))
 	assert.True(t, containsMention("hey @Cursor fix it", "@cursor"))
@@ -1141,6 +1604,104 @@ func TestEnrichFromThread_GetPostThreadFails(t *testing.T) {
 	assert.Nil(t, result)
 }
 
+func TestMessageHasBeenPosted_TextAttachmentAppendedToPrompt(t *testing.T) {
+	p, api, cursorClient, store := setupTestPlugin(t)
+
+	post := &model.Post{
+		Id:        "post-1",
+		UserId:    "user-1",
+		ChannelId: "ch-1",
+		Message:   "@cursor fix the crash in this log",
+		FileIds:   model.StringArray{"file-log"},
+	}
+
+	store.On("GetUserSettings", "user-1").Return(nil, nil)
+	store.On("GetChannelSettings", "ch-1").Return(nil, nil)
+	api.On("AddReaction", mock.Anything).Return(nil, nil)
+	api.On("RemoveReaction", mock.Anything).Return(nil)
+
+	api.On("GetFileInfo", "file-log").Return(&model.FileInfo{
+		Id:        "file-log",
+		Name:      "server.log",
+		Extension: "log",
+		MimeType:  "application/octet-stream",
+		Size:      42,
+	}, nil)
+	api.On("GetFile", "file-log").Return([]byte("panic: runtime error: nil pointer dereference\n"), nil)
+
+	cursorClient.On("LaunchAgent", mock.Anything, mock.MatchedBy(func(req cursor.LaunchAgentRequest) bool {
+		return strings.Contains(req.Prompt.Text, "fix the crash in this log") &&
+			strings.Contains(req.Prompt.Text, "[Attachment: server.log]") &&
+			strings.Contains(req.Prompt.Text, "panic: runtime error: nil pointer dereference")
+	})).Return(&cursor.Agent{ID: "agent-123", Status: cursor.AgentStatusCreating}, nil)
+
+	api.On("CreatePost", mock.Anything).Return(&model.Post{Id: "reply-1"}, nil)
+	store.On("SaveAgent", mock.Anything).Return(nil)
+	store.On("SetThreadAgent", "post-1", "agent-123").Return(nil)
+	api.On("PublishWebSocketEvent", "agent_created", mock.Anything, mock.Anything).Return()
+
+	p.MessageHasBeenPosted(nil, post)
+
+	cursorClient.AssertExpectations(t)
+}
+
+func TestBuildPromptAttachments_SkipsOversizedAttachmentWithNote(t *testing.T) {
+	p, api, _, _ := setupTestPlugin(t)
+
+	api.On("GetFileInfo", "file-big").Return(&model.FileInfo{
+		Id:        "file-big",
+		Name:      "huge.log",
+		Extension: "log",
+		MimeType:  "text/plain",
+		Size:      maxPromptAttachmentSize + 1,
+	}, nil)
+	api.On("GetFileInfo", "file-small").Return(&model.FileInfo{
+		Id:        "file-small",
+		Name:      "fix.diff",
+		Extension: "diff",
+		MimeType:  "text/x-diff",
+		Size:      20,
+	}, nil)
+	api.On("GetFile", "file-small").Return([]byte("-old line\n+new line\n"), nil)
+
+	text, images := p.buildPromptAttachments([]string{"file-big", "file-small"}, true)
+
+	assert.Empty(t, images)
+	assert.Contains(t, text, "[Skipped attachment huge.log")
+	assert.Contains(t, text, "exceeds the 256 KB attachment limit")
+	assert.Contains(t, text, "[Attachment: fix.diff]")
+	assert.Contains(t, text, "+new line")
+	api.AssertNotCalled(t, "GetFile", "file-big")
+}
+
+func TestBuildPromptAttachments_ReferencesImagesAndCapsCount(t *testing.T) {
+	p, api, _, _ := setupTestPlugin(t)
+
+	fileIDs := make([]string, 0, maxPromptAttachments+2)
+	for i := 0; i < maxPromptAttachments+2; i++ {
+		fileID := fmt.Sprintf("img-%d", i)
+		fileIDs = append(fileIDs, fileID)
+		api.On("GetFileInfo", fileID).Return(&model.FileInfo{
+			Id:       fileID,
+			Name:     fileID + ".png",
+			MimeType: "image/png",
+			Width:    100,
+			Height:   50,
+			Size:     100,
+		}, nil).Maybe()
+	}
+
+	// Thread replies already carry images via enrichFromThread, so only
+	// descriptions are added and image data is not re-downloaded.
+	text, images := p.buildPromptAttachments(fileIDs, false)
+
+	assert.Empty(t, images)
+	assert.Contains(t, text, "[Image attachment: img-0.png (100x50)]")
+	assert.NotContains(t, text, fmt.Sprintf("img-%d.png", maxPromptAttachments))
+	assert.Contains(t, text, "[Skipped 2 more attachment(s)")
+	api.AssertNotCalled(t, "GetFile", mock.Anything)
+}
+
 func TestFormatThread_ChronologicalOrder(t *testing.T) {
 	p, api, _, _ := setupTestPlugin(t)
 
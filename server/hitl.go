@@ -67,14 +67,19 @@ func (p *Plugin) getUsername(userID string) string {
 // getPluginURL returns the full URL prefix for plugin HTTP endpoints.
 // Format: {siteURL}/plugins/{pluginID}
 func (p *Plugin) getPluginURL() string {
-	siteURL := ""
+	return p.getSiteURL() + "/plugins/com.mattermost.plugin-cursor"
+}
+
+// getSiteURL returns the server's SiteURL without a trailing slash, or "" if
+// it is not configured.
+func (p *Plugin) getSiteURL() string {
 	if p.client != nil {
 		cfg := p.client.Configuration.GetConfig()
 		if cfg != nil && cfg.ServiceSettings.SiteURL != nil {
-			siteURL = strings.TrimRight(*cfg.ServiceSettings.SiteURL, "/")
+			return strings.TrimRight(*cfg.ServiceSettings.SiteURL, "/")
 		}
 	}
-	return siteURL + "/plugins/com.mattermost.plugin-cursor"
+	return ""
 }
 
 // startContextReview creates a new HITL workflow and posts the enriched context
@@ -94,8 +99,14 @@ func (p *Plugin) startContextReview(
 		rootID = post.RootId
 	}
 
-	// Step 2: Create the workflow record.
-	now := time.Now().UnixMilli()
+	// Step 2: Create the workflow record, with any GitHub issues the prompt
+	// references. Secrets are redacted up front so they are neither shown for
+	// review nor sent to the agent.
+	if issueContext := p.buildIssueContext(repo, parsed.Prompt); issueContext != "" {
+		enrichedContext += "\n\n" + issueContext
+	}
+	enrichedContext = p.redactSecretsForCursor(enrichedContext, "context_review")
+	now := p.now().UnixMilli()
 	workflow := &kvstore.HITLWorkflow{
 		ID:                uuid.New().String(),
 		UserID:            post.UserId,
@@ -105,8 +116,11 @@ func (p *Plugin) startContextReview(
 		Phase:             kvstore.PhaseContextReview,
 		Repository:        repo,
 		Branch:            branch,
+		BaseBranch:        parsed.Base,
+		SourceRef:         parsed.Ref,
 		Model:             modelName,
 		AutoCreatePR:      autoCreatePR,
+		Draft:             parsed.Draft != nil && *parsed.Draft,
 		OriginalPrompt:    parsed.Prompt,
 		EnrichedContext:   enrichedContext,
 		ContextImages:     images,
@@ -136,13 +150,13 @@ func (p *Plugin) startContextReview(
 	)
 
 	reviewPost := &model.Post{
-		UserId:    p.getBotUserID(),
+		UserId:    p.botUserIDForChannel(post.ChannelId),
 		ChannelId: post.ChannelId,
 		RootId:    rootID,
 	}
 	model.ParseSlackAttachment(reviewPost, []*model.SlackAttachment{attachment})
 
-	createdPost, appErr := p.API.CreatePost(reviewPost)
+	createdPost, appErr := p.API.CreatePost(p.decorateBotPost(reviewPost))
 	if appErr != nil {
 		p.API.LogError("Failed to post context review", "error", appErr.Error())
 		return
@@ -150,7 +164,7 @@ func (p *Plugin) startContextReview(
 
 	// Step 6: Save the context post ID back to the workflow for later updates.
 	workflow.ContextPostID = createdPost.Id
-	workflow.UpdatedAt = time.Now().UnixMilli()
+	workflow.UpdatedAt = p.now().UnixMilli()
 	if err := p.kvstore.SaveWorkflow(workflow); err != nil {
 		p.API.LogError("Failed to update workflow with context post ID", "error", err.Error())
 	}
@@ -165,7 +179,7 @@ func (p *Plugin) startContextReview(
 func (p *Plugin) acceptContext(workflow *kvstore.HITLWorkflow) {
 	// Step 1: Update workflow state.
 	workflow.ApprovedContext = workflow.EnrichedContext
-	workflow.UpdatedAt = time.Now().UnixMilli()
+	workflow.UpdatedAt = p.now().UnixMilli()
 
 	// Step 2: Advance to next phase.
 	if workflow.SkipPlanLoop {
@@ -241,13 +255,16 @@ func (p *Plugin) startPlanLoop(workflow *kvstore.HITLWorkflow) {
 	planningAttachment := attachments.BuildPlanningStatusAttachment(
 		workflow.Repository, workflow.Branch, workflow.Model, workflow.PlanIterationCount,
 	)
+	planningAttachment.Actions = []*model.PostAction{
+		attachments.BuildAcceptPartialPlanAction(workflow.ID, p.getPluginURL()),
+	}
 	statusPost := &model.Post{
-		UserId:    p.getBotUserID(),
+		UserId:    p.botUserIDForChannel(workflow.ChannelID),
 		ChannelId: workflow.ChannelID,
 		RootId:    workflow.RootPostID,
 	}
 	model.ParseSlackAttachment(statusPost, []*model.SlackAttachment{planningAttachment})
-	if _, appErr := p.API.CreatePost(statusPost); appErr != nil {
+	if _, appErr := p.API.CreatePost(p.decorateBotPost(statusPost)); appErr != nil {
 		p.API.LogError("Failed to post planning status", "error", appErr.Error())
 	}
 
@@ -273,16 +290,13 @@ func (p *Plugin) launchPlannerAgent(workflow *kvstore.HITLWorkflow) error {
 	plannerPrompt := p.buildPlannerPrompt(workflow)
 
 	// Build the repo URL.
-	repoURL := workflow.Repository
-	if !strings.Contains(repoURL, "://") {
-		repoURL = "https://github.com/" + repoURL
-	}
+	repoURL := launchRepositoryURL(workflow.Repository)
 
 	launchReq := cursor.LaunchAgentRequest{
 		Prompt: cursor.Prompt{Text: plannerPrompt},
 		Source: cursor.Source{
 			Repository: repoURL,
-			Ref:        workflow.Branch,
+			Ref:        launchSourceRef(workflow.Branch, workflow.SourceRef),
 		},
 		Target: &cursor.Target{
 			AutoCreatePr: false, // Planner must NOT create PRs
@@ -308,7 +322,7 @@ func (p *Plugin) launchPlannerAgent(workflow *kvstore.HITLWorkflow) error {
 	}
 
 	// Create an AgentRecord for the planner (so the poller tracks it).
-	now := time.Now().UnixMilli()
+	now := p.now().UnixMilli()
 	agentRecord := &kvstore.AgentRecord{
 		CursorAgentID: agent.ID,
 		Status:        string(agent.Status),
@@ -409,7 +423,7 @@ func (p *Plugin) handlePlannerFinished(workflow *kvstore.HITLWorkflow, agent *cu
 			":x: **Planning agent failed.** You can reply in this thread to try again.",
 		)
 		workflow.Phase = kvstore.PhasePlanReview // Allow retry via thread reply
-		workflow.UpdatedAt = time.Now().UnixMilli()
+		workflow.UpdatedAt = p.now().UnixMilli()
 		_ = p.kvstore.SaveWorkflow(workflow)
 		p.publishWorkflowPhaseChange(workflow)
 		return
@@ -419,7 +433,7 @@ func (p *Plugin) handlePlannerFinished(workflow *kvstore.HITLWorkflow, agent *cu
 		// Planner was stopped (e.g., user cancelled). Mark workflow as rejected
 		// so the thread is freed up for new agents.
 		workflow.Phase = kvstore.PhaseRejected
-		workflow.UpdatedAt = time.Now().UnixMilli()
+		workflow.UpdatedAt = p.now().UnixMilli()
 		_ = p.kvstore.SaveWorkflow(workflow)
 		p.publishWorkflowPhaseChange(workflow)
 		return
@@ -445,7 +459,7 @@ func (p *Plugin) handlePlannerFinished(workflow *kvstore.HITLWorkflow, agent *cu
 			fmt.Sprintf(":x: **Failed to retrieve plan**: %s\n\nReply in this thread to retry.", err.Error()),
 		)
 		workflow.Phase = kvstore.PhasePlanReview
-		workflow.UpdatedAt = time.Now().UnixMilli()
+		workflow.UpdatedAt = p.now().UnixMilli()
 		if err := p.kvstore.SaveWorkflow(workflow); err != nil {
 			p.API.LogError("Failed to save workflow after GetConversation error", "error", err.Error())
 		} else {
@@ -461,7 +475,7 @@ func (p *Plugin) handlePlannerFinished(workflow *kvstore.HITLWorkflow, agent *cu
 			":warning: **Planning agent finished but produced no plan.** Reply in this thread to try again with more specific instructions.",
 		)
 		workflow.Phase = kvstore.PhasePlanReview
-		workflow.UpdatedAt = time.Now().UnixMilli()
+		workflow.UpdatedAt = p.now().UnixMilli()
 		_ = p.kvstore.SaveWorkflow(workflow)
 		p.publishWorkflowPhaseChange(workflow)
 		return
@@ -469,7 +483,8 @@ func (p *Plugin) handlePlannerFinished(workflow *kvstore.HITLWorkflow, agent *cu
 
 	// Store the plan in the workflow.
 	workflow.RetrievedPlan = plan
-	workflow.UpdatedAt = time.Now().UnixMilli()
+	workflow.AppendPlanVersion(plan)
+	workflow.UpdatedAt = p.now().UnixMilli()
 
 	// Check if there's pending feedback from the user submitted during planning.
 	if workflow.PendingFeedback != "" {
@@ -508,18 +523,18 @@ func (p *Plugin) handlePlannerFinished(workflow *kvstore.HITLWorkflow, agent *cu
 	)
 
 	reviewPost := &model.Post{
-		UserId:    p.getBotUserID(),
+		UserId:    p.botUserIDForChannel(workflow.ChannelID),
 		ChannelId: workflow.ChannelID,
 		RootId:    workflow.RootPostID,
 	}
 	model.ParseSlackAttachment(reviewPost, []*model.SlackAttachment{planAttachment})
 
-	createdPost, appErr := p.API.CreatePost(reviewPost)
+	createdPost, appErr := p.API.CreatePost(p.decorateBotPost(reviewPost))
 	if appErr != nil {
 		p.API.LogError("Failed to post plan review", "error", appErr.Error())
 	} else {
 		workflow.PlanPostID = createdPost.Id
-		workflow.UpdatedAt = time.Now().UnixMilli()
+		workflow.UpdatedAt = p.now().UnixMilli()
 		_ = p.kvstore.SaveWorkflow(workflow)
 	}
 
@@ -547,7 +562,10 @@ func extractPlanFromConversation(conv *cursor.Conversation) string {
 // Note: The button post update is handled by the PostActionIntegrationResponse in handleHITLResponse.
 func (p *Plugin) acceptPlan(workflow *kvstore.HITLWorkflow) {
 	workflow.ApprovedPlan = workflow.RetrievedPlan
-	workflow.UpdatedAt = time.Now().UnixMilli()
+	if p.getConfiguration().PlanAsPRChecklist {
+		workflow.PlanChecklist = parsePlanChecklist(workflow.ApprovedPlan)
+	}
+	workflow.UpdatedAt = p.now().UnixMilli()
 
 	if err := p.kvstore.SaveWorkflow(workflow); err != nil {
 		p.API.LogError("Failed to save workflow after plan approval",
@@ -563,6 +581,67 @@ func (p *Plugin) acceptPlan(workflow *kvstore.HITLWorkflow) {
 	p.launchImplementerFromWorkflow(workflow)
 }
 
+// maxPartialPlanReplyLen keeps the partial plan reply within Mattermost's post
+// size limit.
+const maxPartialPlanReplyLen = 14000
+
+// acceptPartialPlan accepts the plan a still-running planner has produced so
+// far. The planner's conversation is read first: when it holds no plan yet the
+// user is warned and the planner keeps running. Otherwise the plan is approved,
+// which stops the planner and launches the implementation agent.
+func (p *Plugin) acceptPartialPlan(workflowID string) {
+	unlock := p.lockWorkflow(workflowID)
+	defer unlock()
+
+	workflow, err := p.kvstore.GetWorkflow(workflowID)
+	if err != nil || workflow == nil {
+		return
+	}
+	if workflow.Phase != kvstore.PhasePlanning {
+		return // The planner finished or the workflow moved on meanwhile.
+	}
+
+	cursorClient := p.getCursorClient()
+	if cursorClient == nil {
+		p.postBotReplyInThread(workflow, ":x: **Cannot retrieve plan**: Cursor API key is not configured.")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	conv, err := cursorClient.GetConversation(ctx, workflow.PlannerAgentID)
+	if err != nil {
+		p.API.LogError("Failed to get planner conversation for partial plan",
+			"agent_id", workflow.PlannerAgentID,
+			"error", err.Error(),
+		)
+		p.postBotReplyInThread(workflow,
+			fmt.Sprintf(":x: **Failed to retrieve the partial plan**: %s\n\nThe planning agent is still running.", err.Error()),
+		)
+		return
+	}
+
+	plan := extractPlanFromConversation(conv)
+	if plan == "" {
+		p.postBotReplyInThread(workflow,
+			":warning: **The planning agent hasn't produced a plan yet.** It is still running; try again later or wait for it to finish.",
+		)
+		return
+	}
+
+	workflow.RetrievedPlan = plan
+	workflow.AppendPlanVersion(plan)
+	workflow.Phase = kvstore.PhaseImplementing
+	p.publishWorkflowPhaseChange(workflow)
+
+	p.postBotReplyInThread(workflow, fmt.Sprintf(
+		"Planning stopped early. Accepted the partial plan from @%s -- launching implementation agent.\n\n%s",
+		p.getUsername(workflow.UserID), truncateText(plan, maxPartialPlanReplyLen),
+	))
+	p.acceptPlan(workflow)
+}
+
 // iteratePlan stops the current planner (if running), stores user feedback,
 // increments the iteration counter, and launches a new planner agent.
 func (p *Plugin) iteratePlan(workflow *kvstore.HITLWorkflow, userFeedback string) {
@@ -572,7 +651,7 @@ func (p *Plugin) iteratePlan(workflow *kvstore.HITLWorkflow, userFeedback string
 	// Store the user's feedback for the next planner prompt.
 	workflow.PlanFeedback = userFeedback
 	workflow.PlanIterationCount++
-	workflow.UpdatedAt = time.Now().UnixMilli()
+	workflow.UpdatedAt = p.now().UnixMilli()
 
 	if err := p.kvstore.SaveWorkflow(workflow); err != nil {
 		p.API.LogError("Failed to save workflow for plan iteration",
@@ -628,20 +707,23 @@ func (p *Plugin) stopAgentIfRunning(agentID string) {
 	}
 }
 
-// publishWorkflowPhaseChange publishes a WebSocket event when a workflow phase changes.
+// publishWorkflowPhaseChange publishes a WebSocket event when a workflow phase changes
+// and forwards it to any configured phase webhooks.
 func (p *Plugin) publishWorkflowPhaseChange(workflow *kvstore.HITLWorkflow) {
+	payload := map[string]any{
+		"workflow_id":          workflow.ID,
+		"phase":                workflow.Phase,
+		"planner_agent_id":     workflow.PlannerAgentID,
+		"implementer_agent_id": workflow.ImplementerAgentID,
+		"plan_iteration_count": fmt.Sprintf("%d", workflow.PlanIterationCount),
+		"updated_at":           fmt.Sprintf("%d", workflow.UpdatedAt),
+	}
 	p.API.PublishWebSocketEvent(
 		"workflow_phase_change",
-		map[string]any{
-			"workflow_id":          workflow.ID,
-			"phase":                workflow.Phase,
-			"planner_agent_id":     workflow.PlannerAgentID,
-			"implementer_agent_id": workflow.ImplementerAgentID,
-			"plan_iteration_count": fmt.Sprintf("%d", workflow.PlanIterationCount),
-			"updated_at":           fmt.Sprintf("%d", workflow.UpdatedAt),
-		},
+		payload,
 		&model.WebsocketBroadcast{UserId: workflow.UserID},
 	)
+	p.notifyPhaseWebhooks("workflow_phase_change", payload)
 }
 
 // launchImplementerFromWorkflow launches a Cursor implementation agent
@@ -649,8 +731,8 @@ func (p *Plugin) publishWorkflowPhaseChange(workflow *kvstore.HITLWorkflow) {
 func (p *Plugin) launchImplementerFromWorkflow(workflow *kvstore.HITLWorkflow) {
 	cursorClient := p.getCursorClient()
 	if cursorClient == nil {
-		p.removeReaction(workflow.TriggerPostID, "hourglass_flowing_sand")
-		p.addReaction(workflow.TriggerPostID, "x")
+		p.removeReaction(workflow.ChannelID, workflow.TriggerPostID, "hourglass_flowing_sand")
+		p.addReaction(workflow.ChannelID, workflow.TriggerPostID, "x")
 		p.postBotReplyInThread(workflow, "Cursor API key is not configured. Ask your admin to configure the plugin.")
 		return
 	}
@@ -670,18 +752,17 @@ func (p *Plugin) launchImplementerFromWorkflow(workflow *kvstore.HITLWorkflow) {
 	promptText = p.wrapPromptWithSystemInstructions(promptText)
 
 	// Build launch request.
-	repoURL := workflow.Repository
-	if !strings.Contains(repoURL, "://") {
-		repoURL = "https://github.com/" + repoURL
-	}
+	repoURL := launchRepositoryURL(workflow.Repository)
 
 	launchReq := cursor.LaunchAgentRequest{
 		Prompt: cursor.Prompt{Text: promptText},
-		Source: cursor.Source{Repository: repoURL, Ref: workflow.Branch},
+		Source: cursor.Source{Repository: repoURL, Ref: launchSourceRef(workflow.Branch, workflow.SourceRef)},
 		Target: &cursor.Target{
 			BranchName:   fmt.Sprintf("cursor/%s", sanitizeBranchName(workflow.OriginalPrompt)),
+			BaseBranch:   workflow.BaseBranch,
 			AutoCreatePr: workflow.AutoCreatePR,
 			AutoBranch:   true,
+			Draft:        workflow.Draft,
 		},
 		Model: workflow.Model,
 	}
@@ -697,11 +778,11 @@ func (p *Plugin) launchImplementerFromWorkflow(workflow *kvstore.HITLWorkflow) {
 	agent, err := cursorClient.LaunchAgent(ctx, launchReq)
 	if err != nil {
 		p.API.LogError("Failed to launch implementation agent", "error", err.Error())
-		p.removeReaction(workflow.TriggerPostID, "hourglass_flowing_sand")
-		p.addReaction(workflow.TriggerPostID, "x")
+		p.removeReaction(workflow.ChannelID, workflow.TriggerPostID, "hourglass_flowing_sand")
+		p.addReaction(workflow.ChannelID, workflow.TriggerPostID, "x")
 		p.postBotReplyInThread(workflow, formatAPIError("Failed to launch agent", err))
 		workflow.Phase = kvstore.PhaseRejected
-		workflow.UpdatedAt = time.Now().UnixMilli()
+		workflow.UpdatedAt = p.now().UnixMilli()
 		_ = p.kvstore.SaveWorkflow(workflow)
 		return
 	}
@@ -709,14 +790,14 @@ func (p *Plugin) launchImplementerFromWorkflow(workflow *kvstore.HITLWorkflow) {
 	// Post launch attachment in thread.
 	launchAttachment := attachments.BuildImplementerLaunchAttachment(agent.ID, workflow.Repository, workflow.Branch, workflow.Model)
 	replyPost := &model.Post{
-		UserId:    p.getBotUserID(),
+		UserId:    p.botUserIDForChannel(workflow.ChannelID),
 		ChannelId: workflow.ChannelID,
 		RootId:    workflow.RootPostID,
 	}
 	model.ParseSlackAttachment(replyPost, []*model.SlackAttachment{launchAttachment})
 	replyPost.AddProp("cursor_agent_id", agent.ID)
 	replyPost.AddProp("cursor_agent_status", string(agent.Status))
-	createdReply, appErr := p.API.CreatePost(replyPost)
+	createdReply, appErr := p.API.CreatePost(p.decorateBotPost(replyPost))
 	if appErr != nil {
 		p.API.LogError("Failed to create launch reply", "error", appErr.Error())
 	}
@@ -726,7 +807,7 @@ func (p *Plugin) launchImplementerFromWorkflow(workflow *kvstore.HITLWorkflow) {
 	if createdReply != nil {
 		botReplyID = createdReply.Id
 	}
-	now := time.Now().UnixMilli()
+	now := p.now().UnixMilli()
 	agentRecord := &kvstore.AgentRecord{
 		CursorAgentID:  agent.ID,
 		Status:         string(agent.Status),
@@ -737,6 +818,9 @@ func (p *Plugin) launchImplementerFromWorkflow(workflow *kvstore.HITLWorkflow) {
 		Repository:     workflow.Repository,
 		Branch:         workflow.Branch,
 		TargetBranch:   launchReq.Target.BranchName,
+		BaseBranch:     workflow.BaseBranch,
+		SourceRef:      workflow.SourceRef,
+		Draft:          workflow.Draft,
 		Prompt:         workflow.OriginalPrompt,
 		Model:          workflow.Model,
 		BotReplyPostID: botReplyID,
@@ -754,6 +838,17 @@ func (p *Plugin) launchImplementerFromWorkflow(workflow *kvstore.HITLWorkflow) {
 	if err := p.kvstore.SaveAgent(agentRecord); err != nil {
 		p.API.LogError("Failed to save agent record", "error", err.Error())
 	}
+	p.recordLaunchHistory(workflow.UserID, kvstore.LaunchHistoryEntry{
+		Prompt:       workflow.OriginalPrompt,
+		Repository:   workflow.Repository,
+		Branch:       workflow.Branch,
+		SourceRef:    workflow.SourceRef,
+		BaseBranch:   workflow.BaseBranch,
+		Model:        workflow.Model,
+		AutoCreatePR: &workflow.AutoCreatePR,
+		Draft:        workflow.Draft,
+		LaunchedAt:   now,
+	})
 
 	// Update workflow with implementer agent ID.
 	workflow.ImplementerAgentID = agent.ID
@@ -819,7 +914,7 @@ func (p *Plugin) rejectWorkflowForAgent(agentID string) {
 	}
 
 	workflow.Phase = kvstore.PhaseRejected
-	workflow.UpdatedAt = time.Now().UnixMilli()
+	workflow.UpdatedAt = p.now().UnixMilli()
 	if err := p.kvstore.SaveWorkflow(workflow); err != nil {
 		p.API.LogError("Failed to reject workflow for cancelled agent",
 			"agent_id", agentID,
@@ -835,7 +930,7 @@ func (p *Plugin) rejectWorkflowForAgent(agentID string) {
 // Note: The button post update is handled by the PostActionIntegrationResponse.
 func (p *Plugin) rejectWorkflow(workflow *kvstore.HITLWorkflow) {
 	workflow.Phase = kvstore.PhaseRejected
-	workflow.UpdatedAt = time.Now().UnixMilli()
+	workflow.UpdatedAt = p.now().UnixMilli()
 	if err := p.kvstore.SaveWorkflow(workflow); err != nil {
 		p.API.LogError("Failed to save rejected workflow", "error", err.Error())
 	}
@@ -844,8 +939,8 @@ func (p *Plugin) rejectWorkflow(workflow *kvstore.HITLWorkflow) {
 	p.publishWorkflowPhaseChange(workflow)
 
 	// Swap reactions on trigger post.
-	p.removeReaction(workflow.TriggerPostID, "hourglass_flowing_sand")
-	p.addReaction(workflow.TriggerPostID, "no_entry_sign")
+	p.removeReaction(workflow.ChannelID, workflow.TriggerPostID, "hourglass_flowing_sand")
+	p.addReaction(workflow.ChannelID, workflow.TriggerPostID, "no_entry_sign")
 }
 
 // iterateContext re-enriches the context using the user's feedback,
@@ -866,10 +961,11 @@ func (p *Plugin) iterateContext(workflow *kvstore.HITLWorkflow, userFeedback str
 		// Fallback: append user feedback to existing context.
 		reEnriched = workflow.EnrichedContext + "\n\n--- Additional Context ---\n" + userFeedback
 	}
+	reEnriched = p.redactSecretsForCursor(reEnriched, "context_iteration")
 
 	// Step 3: Update workflow.
 	workflow.EnrichedContext = reEnriched
-	workflow.UpdatedAt = time.Now().UnixMilli()
+	workflow.UpdatedAt = p.now().UnixMilli()
 
 	// Step 4: Post a NEW context review attachment.
 	username := p.getUsername(workflow.UserID)
@@ -880,13 +976,13 @@ func (p *Plugin) iterateContext(workflow *kvstore.HITLWorkflow, userFeedback str
 	)
 
 	reviewPost := &model.Post{
-		UserId:    p.getBotUserID(),
+		UserId:    p.botUserIDForChannel(workflow.ChannelID),
 		ChannelId: workflow.ChannelID,
 		RootId:    workflow.RootPostID,
 	}
 	model.ParseSlackAttachment(reviewPost, []*model.SlackAttachment{attachment})
 
-	createdPost, appErr := p.API.CreatePost(reviewPost)
+	createdPost, appErr := p.API.CreatePost(p.decorateBotPost(reviewPost))
 	if appErr != nil {
 		p.API.LogError("Failed to post updated context review", "error", appErr.Error())
 		return
@@ -939,11 +1035,7 @@ func (p *Plugin) handlePossibleWorkflowReply(post *model.Post) bool {
 		feedbackText := strings.TrimSpace(post.Message)
 
 		// Strip bot mention if present (user may @cursor while replying).
-		botMention := "@" + p.getBotUsername()
-		if idx := strings.Index(strings.ToLower(feedbackText), strings.ToLower(botMention)); idx >= 0 {
-			feedbackText = feedbackText[:idx] + feedbackText[idx+len(botMention):]
-			feedbackText = strings.TrimSpace(feedbackText)
-		}
+		feedbackText = strings.TrimSpace(p.stripBotMention(feedbackText))
 
 		if feedbackText == "" {
 			return true
@@ -955,7 +1047,7 @@ func (p *Plugin) handlePossibleWorkflowReply(post *model.Post) bool {
 		} else {
 			workflow.PendingFeedback = feedbackText
 		}
-		workflow.UpdatedAt = time.Now().UnixMilli()
+		workflow.UpdatedAt = p.now().UnixMilli()
 
 		if err := p.kvstore.SaveWorkflow(workflow); err != nil {
 			p.API.LogError("Failed to save pending feedback",
@@ -978,12 +1070,12 @@ func (p *Plugin) handlePossibleWorkflowReply(post *model.Post) bool {
 
 // postBotReplyInThread posts a bot message in the workflow's thread.
 func (p *Plugin) postBotReplyInThread(workflow *kvstore.HITLWorkflow, message string) {
-	_, appErr := p.API.CreatePost(&model.Post{
-		UserId:    p.getBotUserID(),
+	_, appErr := p.API.CreatePost(p.decorateBotPost(&model.Post{
+		UserId:    p.botUserIDForChannel(workflow.ChannelID),
 		ChannelId: workflow.ChannelID,
 		RootId:    workflow.RootPostID,
 		Message:   message,
-	})
+	}))
 	if appErr != nil {
 		p.API.LogError("Failed to post bot reply in thread", "error", appErr.Error())
 	}
@@ -1004,7 +1096,7 @@ func (p *Plugin) updatePostWithAttachment(postID string, attachment *model.Slack
 	}
 	originalPost.Message = ""
 	model.ParseSlackAttachment(originalPost, []*model.SlackAttachment{attachment})
-	if _, appErr := p.API.UpdatePost(originalPost); appErr != nil {
+	if _, appErr := p.API.UpdatePost(p.decorateBotPost(originalPost)); appErr != nil {
 		p.API.LogError("Failed to update post with attachment",
 			"postID", postID,
 			"error", appErr.Error(),
@@ -1071,3 +1163,61 @@ func (p *Plugin) buildImageRefs(post *model.Post) []kvstore.ImageRef {
 	}
 	return refs
 }
+
+// notifyWorkflowOwnerImplementationFinished sends the workflow's owner a direct
+// message linking the PR opened by the implementation agent and the workflow
+// thread.
+func (p *Plugin) notifyWorkflowOwnerImplementationFinished(workflow *kvstore.HITLWorkflow, record *kvstore.AgentRecord, agent *cursor.Agent) {
+	if workflow.UserID == "" {
+		return
+	}
+	botUserID := p.botUserIDForChannel(workflow.ChannelID)
+	channel, appErr := p.API.GetDirectChannel(botUserID, workflow.UserID)
+	if appErr != nil {
+		p.API.LogError("Failed to open direct channel for workflow notification",
+			"workflow_id", workflow.ID,
+			"error", appErr.Error(),
+		)
+		return
+	}
+
+	prURL := agent.Target.PrURL
+	if prURL == "" {
+		prURL = record.PrURL
+	}
+	repository := workflow.Repository
+	if repository == "" {
+		repository = record.Repository
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Your workflow's implementation agent finished")
+	if repository != "" {
+		sb.WriteString(fmt.Sprintf(" on `%s`", repository))
+	}
+	sb.WriteString(".")
+	if prURL != "" {
+		sb.WriteString(fmt.Sprintf(" [View PR](%s)", prURL))
+	} else {
+		sb.WriteString(" No PR was created.")
+	}
+	if workflow.RootPostID != "" {
+		sb.WriteString(fmt.Sprintf(" | [View workflow thread](%s)", p.getPostPermalink(workflow.RootPostID)))
+	}
+
+	if _, appErr := p.API.CreatePost(p.decorateBotPost(&model.Post{
+		UserId:    botUserID,
+		ChannelId: channel.Id,
+		Message:   sb.String(),
+	})); appErr != nil {
+		p.API.LogError("Failed to notify workflow owner",
+			"workflow_id", workflow.ID,
+			"error", appErr.Error(),
+		)
+	}
+}
+
+// getPostPermalink returns a link to a post that works regardless of team.
+func (p *Plugin) getPostPermalink(postID string) string {
+	return p.getSiteURL() + "/_redirect/pl/" + postID
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+// cleanupExpiredWorkflows deletes rejected workflows, and workflows abandoned
+// while waiting on a context or plan review, once they have gone untouched
+// for longer than the configured retention. Workflows with a running agent
+// are never removed here.
+func (p *Plugin) cleanupExpiredWorkflows() {
+	retention := p.getConfiguration().GetWorkflowRetention()
+	if retention == 0 {
+		return
+	}
+
+	workflows, err := p.kvstore.ListWorkflows()
+	if err != nil {
+		p.API.LogError("Failed to list workflows for cleanup", "error", err.Error())
+		return
+	}
+
+	now := p.now()
+	deleted := 0
+	for _, workflow := range workflows {
+		if !isWorkflowExpired(workflow, now, retention) {
+			continue
+		}
+		if p.deleteWorkflow(workflow) {
+			deleted++
+		}
+	}
+
+	if deleted > 0 {
+		p.API.LogInfo("Cleaned up expired workflows", "count", deleted, "retention", retention.String())
+	}
+}
+
+// isWorkflowExpired reports whether a workflow is rejected or parked in a
+// review phase and was last updated more than retention ago.
+func isWorkflowExpired(workflow *kvstore.HITLWorkflow, now time.Time, retention time.Duration) bool {
+	switch workflow.Phase {
+	case kvstore.PhaseRejected, kvstore.PhaseContextReview, kvstore.PhasePlanReview:
+	default:
+		return false
+	}
+
+	updatedAt := workflow.UpdatedAt
+	if updatedAt == 0 {
+		updatedAt = workflow.CreatedAt
+	}
+	if updatedAt <= 0 {
+		return false
+	}
+	return now.Sub(time.UnixMilli(updatedAt)) > retention
+}
+
+// deleteWorkflow removes a workflow record along with its thread and agent
+// mappings. It returns false if the record itself could not be deleted.
+func (p *Plugin) deleteWorkflow(workflow *kvstore.HITLWorkflow) bool {
+	for _, agentID := range []string{workflow.PlannerAgentID, workflow.ImplementerAgentID} {
+		if agentID == "" {
+			continue
+		}
+		if err := p.kvstore.DeleteAgentWorkflow(agentID); err != nil {
+			p.API.LogWarn("Failed to delete agent-to-workflow mapping",
+				"workflow_id", workflow.ID, "agent_id", agentID, "error", err.Error())
+		}
+	}
+
+	// The thread may have been handed to an agent since; only drop the
+	// mapping while it still points at this workflow.
+	if workflow.RootPostID != "" {
+		if mapped, err := p.kvstore.GetWorkflowByThread(workflow.RootPostID); err == nil && mapped != nil && mapped.ID == workflow.ID {
+			if err := p.kvstore.DeleteThreadAgent(workflow.RootPostID); err != nil {
+				p.API.LogWarn("Failed to delete thread-to-workflow mapping",
+					"workflow_id", workflow.ID, "error", err.Error())
+			}
+		}
+	}
+
+	if err := p.kvstore.DeleteWorkflow(workflow.ID); err != nil {
+		p.API.LogError("Failed to delete expired workflow", "workflow_id", workflow.ID, "error", err.Error())
+		return false
+	}
+	return true
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+func TestCleanupExpiredWorkflows_DeletesOldRejectedKeepsRecent(t *testing.T) {
+	p, _, _, store := setupPollerPlugin(t)
+	p.configuration.WorkflowRetentionDays = 30
+
+	now := time.Now()
+	old := &kvstore.HITLWorkflow{
+		ID:             "wf-old",
+		RootPostID:     "root-old",
+		Phase:          kvstore.PhaseRejected,
+		PlannerAgentID: "planner-old",
+		CreatedAt:      now.Add(-45 * 24 * time.Hour).UnixMilli(),
+		UpdatedAt:      now.Add(-40 * 24 * time.Hour).UnixMilli(),
+	}
+	recent := &kvstore.HITLWorkflow{
+		ID:         "wf-recent",
+		RootPostID: "root-recent",
+		Phase:      kvstore.PhaseRejected,
+		CreatedAt:  now.Add(-2 * 24 * time.Hour).UnixMilli(),
+		UpdatedAt:  now.Add(-24 * time.Hour).UnixMilli(),
+	}
+	running := &kvstore.HITLWorkflow{
+		ID:        "wf-running",
+		Phase:     kvstore.PhaseImplementing,
+		CreatedAt: now.Add(-60 * 24 * time.Hour).UnixMilli(),
+		UpdatedAt: now.Add(-60 * 24 * time.Hour).UnixMilli(),
+	}
+
+	store.On("ListWorkflows").Return([]*kvstore.HITLWorkflow{old, recent, running}, nil)
+	store.On("DeleteAgentWorkflow", "planner-old").Return(nil).Once()
+	store.On("GetWorkflowByThread", "root-old").Return(old, nil).Once()
+	store.On("DeleteThreadAgent", "root-old").Return(nil).Once()
+	store.On("DeleteWorkflow", "wf-old").Return(nil).Once()
+
+	p.cleanupExpiredWorkflows()
+
+	store.AssertExpectations(t)
+	store.AssertNotCalled(t, "DeleteWorkflow", "wf-recent")
+	store.AssertNotCalled(t, "DeleteWorkflow", "wf-running")
+}
+
+func TestCleanupExpiredWorkflows_KeepsThreadHandedToAgent(t *testing.T) {
+	p, _, _, store := setupPollerPlugin(t)
+	p.configuration.WorkflowRetentionDays = 7
+
+	abandoned := &kvstore.HITLWorkflow{
+		ID:         "wf-abandoned",
+		RootPostID: "root-1",
+		Phase:      kvstore.PhasePlanReview,
+		UpdatedAt:  time.Now().Add(-10 * 24 * time.Hour).UnixMilli(),
+	}
+
+	store.On("ListWorkflows").Return([]*kvstore.HITLWorkflow{abandoned}, nil)
+	store.On("GetWorkflowByThread", "root-1").Return(nil, nil).Once()
+	store.On("DeleteWorkflow", "wf-abandoned").Return(nil).Once()
+
+	p.cleanupExpiredWorkflows()
+
+	store.AssertExpectations(t)
+	store.AssertNotCalled(t, "DeleteThreadAgent", mock.Anything)
+}
+
+func TestCleanupExpiredWorkflows_DisabledByDefault(t *testing.T) {
+	p, _, _, store := setupPollerPlugin(t)
+
+	p.cleanupExpiredWorkflows()
+
+	store.AssertNotCalled(t, "ListWorkflows")
+}
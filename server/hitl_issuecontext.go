@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/ghclient"
+)
+
+const (
+	// maxContextIssues bounds how many referenced issues are fetched for one
+	// prompt.
+	maxContextIssues = 3
+
+	// maxContextIssueBodyLen caps each issue body added to the context.
+	maxContextIssueBodyLen = 4000
+)
+
+// issueRefRe matches an issue URL or a bare "#123", capturing the owner,
+// repo and number of a URL or the number of a bare reference. A bare
+// reference must not follow a word character, so "a#1" or "&#39;" is ignored.
+var issueRefRe = regexp.MustCompile(`https?://github\.com/([A-Za-z0-9._-]+)/([A-Za-z0-9._-]+)/issues/(\d+)|(?:^|[^\w&])#(\d+)\b`)
+
+// issueReference is an issue mentioned in a prompt.
+type issueReference struct {
+	Owner  string
+	Repo   string
+	Number int
+}
+
+func (r issueReference) String() string {
+	return fmt.Sprintf("%s/%s#%d", r.Owner, r.Repo, r.Number)
+}
+
+// parseIssueReferences returns the distinct issues text references, in order
+// and at most maxContextIssues. Bare "#123" references resolve against repo,
+// and are ignored when repo is not an owner/repo pair.
+func parseIssueReferences(text, repo string) []issueReference {
+	var base *ghclient.RepoRef
+	if ref, err := ghclient.ParseRepoRef(repo); err == nil {
+		base = ref
+	}
+
+	var refs []issueReference
+	seen := map[string]bool{}
+	for _, m := range issueRefRe.FindAllStringSubmatch(text, -1) {
+		var ref issueReference
+		if m[3] != "" {
+			ref.Owner, ref.Repo = m[1], m[2]
+			ref.Number, _ = strconv.Atoi(m[3])
+		} else {
+			if base == nil {
+				continue
+			}
+			ref.Owner, ref.Repo = base.Owner, base.Repo
+			ref.Number, _ = strconv.Atoi(m[4])
+		}
+		key := strings.ToLower(ref.String())
+		if ref.Number <= 0 || seen[key] {
+			continue
+		}
+		seen[key] = true
+		refs = append(refs, ref)
+		if len(refs) == maxContextIssues {
+			break
+		}
+	}
+	return refs
+}
+
+// buildIssueContext fetches the issues referenced in prompt and formats their
+// titles and bodies for the enriched context. Issues that cannot be fetched,
+// e.g. private ones the token cannot see, are skipped; an empty string means
+// nothing was added.
+func (p *Plugin) buildIssueContext(repo, prompt string) string {
+	refs := parseIssueReferences(prompt, repo)
+	if len(refs) == 0 {
+		return ""
+	}
+	ghClient := p.getGitHubClient()
+	if ghClient == nil {
+		return ""
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	var sb strings.Builder
+	for _, ref := range refs {
+		issue, err := ghClient.GetIssue(ctx, ref.Owner, ref.Repo, ref.Number)
+		if err != nil {
+			if ghclient.IsNotFound(err) {
+				p.logDebug("Referenced issue not found or not accessible", "issue", ref.String())
+			} else {
+				p.API.LogWarn("Failed to fetch referenced issue for context", "issue", ref.String(), "error", err.Error())
+			}
+			continue
+		}
+
+		if sb.Len() == 0 {
+			sb.WriteString("## Referenced GitHub Issues\n")
+		}
+		sb.WriteString(fmt.Sprintf("\n### %s: %s\n", ref, issue.GetTitle()))
+		if body := strings.TrimSpace(issue.GetBody()); body != "" {
+			sb.WriteString("\n" + truncateText(body, maxContextIssueBodyLen) + "\n")
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v68/github"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/parser"
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+func TestParseIssueReferences(t *testing.T) {
+	refs := parseIssueReferences(
+		"fix #123 and https://github.com/other/lib/issues/7, see #123 again; ignore a#5 and &#39;",
+		"org/repo",
+	)
+	assert.Equal(t, []issueReference{
+		{Owner: "org", Repo: "repo", Number: 123},
+		{Owner: "other", Repo: "lib", Number: 7},
+	}, refs)
+
+	// Bare references need an owner/repo to resolve against.
+	assert.Equal(t, []issueReference{{Owner: "other", Repo: "lib", Number: 7}},
+		parseIssueReferences("fix #123 per https://github.com/other/lib/issues/7", "backend-api"))
+
+	assert.Len(t, parseIssueReferences("#1 #2 #3 #4 #5", "org/repo"), maxContextIssues)
+}
+
+func TestBuildIssueContext_SkipsUnresolvableIssues(t *testing.T) {
+	p, api, _, ghMock := setupReviewLoopTestPlugin(t)
+	allowAnyLogLines(api)
+
+	notFound := &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusNotFound}}
+	ghMock.On("GetIssue", mock.Anything, "org", "repo", 404).Return(nil, notFound)
+	ghMock.On("GetIssue", mock.Anything, "org", "repo", 500).Return(nil, errors.New("boom"))
+	ghMock.On("GetIssue", mock.Anything, "org", "repo", 123).Return(&github.Issue{
+		Title: github.Ptr("Login fails on Safari"),
+		Body:  github.Ptr("Steps: open /login in Safari 17 and submit."),
+	}, nil)
+
+	issueContext := p.buildIssueContext("org/repo", "fix #404, #500 and #123")
+
+	assert.Contains(t, issueContext, "### org/repo#123: Login fails on Safari")
+	assert.Contains(t, issueContext, "Steps: open /login in Safari 17 and submit.")
+	assert.NotContains(t, issueContext, "#404")
+	assert.NotContains(t, issueContext, "#500")
+
+	assert.Empty(t, p.buildIssueContext("org/repo", "fix #404"))
+}
+
+func TestBuildIssueContext_CapsIssueBody(t *testing.T) {
+	p, _, _, ghMock := setupReviewLoopTestPlugin(t)
+	ghMock.On("GetIssue", mock.Anything, "org", "repo", 9).Return(&github.Issue{
+		Title: github.Ptr("Huge log"),
+		Body:  github.Ptr(strings.Repeat("x", 2*maxContextIssueBodyLen)),
+	}, nil)
+
+	issueContext := p.buildIssueContext("org/repo", "look at #9")
+	assert.Less(t, len(issueContext), maxContextIssueBodyLen+200)
+	assert.Contains(t, issueContext, "...")
+}
+
+func TestStartContextReview_IncludesReferencedIssue(t *testing.T) {
+	p, api, _, store := setupTestPlugin(t)
+	allowAnyLogLines(api)
+	ghMock := &mockGitHubClient{}
+	p.githubClient = ghMock
+	p.configuration.EnableContextReview = true
+
+	api.On("GetConfig").Return(&model.Config{}).Maybe()
+	api.On("GetUser", mock.Anything).Return(&model.User{Id: "user-1", Username: "testuser"}, nil).Maybe()
+	api.On("PublishWebSocketEvent", mock.Anything, mock.Anything, mock.Anything).Return().Maybe()
+	api.On("CreatePost", mock.Anything).Return(&model.Post{Id: "review-post-1"}, nil)
+	store.On("SetThreadWorkflow", "post-1", mock.Anything).Return(nil)
+	store.On("SaveWorkflow", mock.MatchedBy(func(wf *kvstore.HITLWorkflow) bool {
+		return strings.HasPrefix(wf.EnrichedContext, "fix #123") &&
+			strings.Contains(wf.EnrichedContext, "### org/repo#123: Login fails on Safari")
+	})).Return(nil)
+	ghMock.On("GetIssue", mock.Anything, "org", "repo", 123).Return(&github.Issue{
+		Title: github.Ptr("Login fails on Safari"),
+		Body:  github.Ptr("Steps to reproduce."),
+	}, nil)
+
+	post := &model.Post{Id: "post-1", UserId: "user-1", ChannelId: "ch-1"}
+	parsed := &parser.ParsedMention{Prompt: "fix #123"}
+	p.startContextReview(post, parsed, "org/repo", "main", "auto", true, "fix #123", nil, false)
+
+	store.AssertCalled(t, "SaveWorkflow", mock.Anything)
+	ghMock.AssertExpectations(t)
+}
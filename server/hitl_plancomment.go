@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/ghclient"
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+const (
+	// maxPlanCommentLen keeps the plan comment under GitHub's comment size limit.
+	maxPlanCommentLen = 60000
+
+	// maxPlanChecklistItems and maxPlanChecklistItemLen bound the checklist
+	// parsed from an approved plan.
+	maxPlanChecklistItems   = 100
+	maxPlanChecklistItemLen = 300
+)
+
+// planListItemRe matches a numbered ("1.", "2)") or bulleted ("-", "*", "+")
+// markdown list item, capturing its indentation and text.
+var planListItemRe = regexp.MustCompile(`^([ \t]*)(?:\d+[.)]|[-*+])\s+(.+)$`)
+
+// planTaskBoxRe matches a task box already present on a list item.
+var planTaskBoxRe = regexp.MustCompile(`^\[[ xX]\]\s+`)
+
+// postApprovedPlanToPR comments the approved plan of the agent's workflow on
+// the PR the implementer opened, when PostApprovedPlanToPR is enabled, and its
+// steps as a task list when PlanAsPRChecklist is enabled. Each is posted once
+// per PR; agents outside a workflow, or planners, are ignored.
+func (p *Plugin) postApprovedPlanToPR(agent *kvstore.AgentRecord, prURL string) {
+	config := p.getConfiguration()
+	if (!config.PostApprovedPlanToPR && !config.PlanAsPRChecklist) || prURL == "" {
+		return
+	}
+
+	workflowID, err := p.kvstore.GetWorkflowByAgent(agent.CursorAgentID)
+	if err != nil || workflowID == "" {
+		return
+	}
+	workflow, err := p.kvstore.GetWorkflow(workflowID)
+	if err != nil || workflow == nil {
+		return
+	}
+	if workflow.ImplementerAgentID != agent.CursorAgentID || strings.TrimSpace(workflow.ApprovedPlan) == "" {
+		return
+	}
+
+	postPlan := config.PostApprovedPlanToPR && workflow.PlanCommentPRURL != prURL
+	postChecklist := config.PlanAsPRChecklist && len(workflow.PlanChecklist) > 0 && workflow.PlanChecklistPRURL != prURL
+	if !postPlan && !postChecklist {
+		return // Already posted.
+	}
+
+	ghClient := p.getGitHubClient()
+	if ghClient == nil {
+		return
+	}
+	prRef, err := ghclient.ParsePRURLWithMappings(prURL, config.GetGitHubRepoMappings())
+	if err != nil {
+		p.API.LogWarn("Failed to parse PR URL for plan comment", "pr_url", prURL, "error", err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	changed := false
+	if postPlan {
+		if _, err := ghClient.CreateComment(ctx, prRef.Owner, prRef.Repo, prRef.Number, formatPlanComment(workflow)); err != nil {
+			p.API.LogError("Failed to post approved plan to PR",
+				"workflow_id", workflow.ID,
+				"pr_url", prURL,
+				"error", err.Error(),
+			)
+		} else {
+			workflow.PlanCommentPRURL = prURL
+			changed = true
+		}
+	}
+	if postChecklist {
+		if _, err := ghClient.CreateComment(ctx, prRef.Owner, prRef.Repo, prRef.Number, formatPlanChecklistComment(workflow.PlanChecklist)); err != nil {
+			p.API.LogError("Failed to post plan checklist to PR",
+				"workflow_id", workflow.ID,
+				"pr_url", prURL,
+				"error", err.Error(),
+			)
+		} else {
+			workflow.PlanChecklistPRURL = prURL
+			changed = true
+		}
+	}
+	if !changed {
+		return
+	}
+
+	workflow.UpdatedAt = p.now().UnixMilli()
+	if err := p.kvstore.SaveWorkflow(workflow); err != nil {
+		p.API.LogError("Failed to save workflow after posting plan comment", "workflow_id", workflow.ID, "error", err.Error())
+	}
+}
+
+// formatPlanComment renders the approved plan as a PR comment.
+func formatPlanComment(workflow *kvstore.HITLWorkflow) string {
+	var sb strings.Builder
+	sb.WriteString("### Approved implementation plan\n\n")
+	if workflow.PlanIterationCount > 1 {
+		sb.WriteString(fmt.Sprintf("This pull request implements the plan below, approved in Mattermost after %d iterations.\n\n", workflow.PlanIterationCount))
+	} else {
+		sb.WriteString("This pull request implements the plan below, approved in Mattermost.\n\n")
+	}
+	sb.WriteString(truncateText(workflow.ApprovedPlan, maxPlanCommentLen))
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// parsePlanChecklist extracts the numbered and bulleted items of a plan as
+// checklist entries. Indented items are kept one level deep, prefixed with two
+// spaces. Items inside fenced code blocks are skipped.
+func parsePlanChecklist(plan string) []string {
+	var items []string
+	inFence := false
+	for _, line := range strings.Split(plan, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		match := planListItemRe.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		text := strings.TrimSpace(planTaskBoxRe.ReplaceAllString(strings.TrimSpace(match[2]), ""))
+		if text == "" {
+			continue
+		}
+		text = truncateText(text, maxPlanChecklistItemLen)
+		if len(strings.ReplaceAll(match[1], "\t", "  ")) >= 2 {
+			text = "  " + text
+		}
+		items = append(items, text)
+		if len(items) == maxPlanChecklistItems {
+			break
+		}
+	}
+	return items
+}
+
+// formatPlanChecklistComment renders checklist entries as a markdown task list.
+func formatPlanChecklistComment(items []string) string {
+	var sb strings.Builder
+	sb.WriteString("### Plan checklist\n\n")
+	sb.WriteString("Steps from the approved plan, for reviewers to check this pull request against.\n\n")
+	for _, item := range items {
+		indent := ""
+		if strings.HasPrefix(item, "  ") {
+			indent = "  "
+		}
+		sb.WriteString(indent + "- [ ] " + strings.TrimSpace(item) + "\n")
+	}
+	return sb.String()
+}
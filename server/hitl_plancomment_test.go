@@ -0,0 +1,136 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v68/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+func newImplementingWorkflowWithPlan() *kvstore.HITLWorkflow {
+	return &kvstore.HITLWorkflow{
+		ID:                 "wf-1",
+		Phase:              kvstore.PhaseImplementing,
+		PlannerAgentID:     "planner-1",
+		ImplementerAgentID: "impl-1",
+		ApprovedPlan:       "1. Add a retry helper\n2. Use it in the poller",
+		PlanIterationCount: 2,
+	}
+}
+
+func TestPostApprovedPlanToPR_PostsPlanWhenEnabled(t *testing.T) {
+	p, _, store, ghMock := setupReviewLoopTestPlugin(t)
+	p.configuration.PostApprovedPlanToPR = true
+
+	workflow := newImplementingWorkflowWithPlan()
+	agent := &kvstore.AgentRecord{CursorAgentID: "impl-1"}
+	prURL := "https://github.com/org/repo/pull/42"
+
+	store.On("GetWorkflowByAgent", "impl-1").Return("wf-1", nil)
+	store.On("GetWorkflow", "wf-1").Return(workflow, nil)
+	ghMock.On("CreateComment", mock.Anything, "org", "repo", 42, mock.MatchedBy(func(body string) bool {
+		return strings.Contains(body, "Approved implementation plan") &&
+			strings.Contains(body, "after 2 iterations") &&
+			strings.Contains(body, "1. Add a retry helper\n2. Use it in the poller")
+	})).Return(&github.IssueComment{}, nil).Once()
+	store.On("SaveWorkflow", mock.MatchedBy(func(wf *kvstore.HITLWorkflow) bool {
+		return wf.PlanCommentPRURL == prURL
+	})).Return(nil).Once()
+
+	p.postApprovedPlanToPR(agent, prURL)
+
+	// A repeated PR event does not post the plan again.
+	p.postApprovedPlanToPR(agent, prURL)
+
+	ghMock.AssertExpectations(t)
+	store.AssertExpectations(t)
+}
+
+func TestPostApprovedPlanToPR_SkippedWhenDisabled(t *testing.T) {
+	p, _, store, ghMock := setupReviewLoopTestPlugin(t)
+
+	p.postApprovedPlanToPR(&kvstore.AgentRecord{CursorAgentID: "impl-1"}, "https://github.com/org/repo/pull/42")
+
+	store.AssertNotCalled(t, "GetWorkflowByAgent", mock.Anything)
+	ghMock.AssertNotCalled(t, "CreateComment", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPostApprovedPlanToPR_SkipsPlannerAgent(t *testing.T) {
+	p, _, store, ghMock := setupReviewLoopTestPlugin(t)
+	p.configuration.PostApprovedPlanToPR = true
+
+	store.On("GetWorkflowByAgent", "planner-1").Return("wf-1", nil)
+	store.On("GetWorkflow", "wf-1").Return(newImplementingWorkflowWithPlan(), nil)
+
+	p.postApprovedPlanToPR(&kvstore.AgentRecord{CursorAgentID: "planner-1"}, "https://github.com/org/repo/pull/42")
+
+	ghMock.AssertNotCalled(t, "CreateComment", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	store.AssertNotCalled(t, "SaveWorkflow", mock.Anything)
+}
+
+func TestParsePlanChecklist(t *testing.T) {
+	plan := strings.Join([]string{
+		"## Implementation plan",
+		"",
+		"Some context about the change.",
+		"",
+		"1. Add a retry helper",
+		"   - Cover backoff in tests",
+		"2) Use it in the poller",
+		"- [x] Update the docs",
+		"* Release notes",
+		"```go",
+		"- not a step",
+		"```",
+		"+ Clean up",
+	}, "\n")
+
+	assert.Equal(t, []string{
+		"Add a retry helper",
+		"  Cover backoff in tests",
+		"Use it in the poller",
+		"Update the docs",
+		"Release notes",
+		"Clean up",
+	}, parsePlanChecklist(plan))
+
+	assert.Empty(t, parsePlanChecklist("Just prose, no steps."))
+}
+
+func TestFormatPlanChecklistComment(t *testing.T) {
+	body := formatPlanChecklistComment([]string{"Add a retry helper", "  Cover backoff in tests"})
+
+	assert.Contains(t, body, "### Plan checklist")
+	assert.Contains(t, body, "- [ ] Add a retry helper\n  - [ ] Cover backoff in tests\n")
+}
+
+func TestPostApprovedPlanToPR_PostsChecklistWhenEnabled(t *testing.T) {
+	p, _, store, ghMock := setupReviewLoopTestPlugin(t)
+	p.configuration.PlanAsPRChecklist = true
+
+	workflow := newImplementingWorkflowWithPlan()
+	workflow.PlanChecklist = parsePlanChecklist(workflow.ApprovedPlan)
+	agent := &kvstore.AgentRecord{CursorAgentID: "impl-1"}
+	prURL := "https://github.com/org/repo/pull/42"
+
+	store.On("GetWorkflowByAgent", "impl-1").Return("wf-1", nil)
+	store.On("GetWorkflow", "wf-1").Return(workflow, nil)
+	ghMock.On("CreateComment", mock.Anything, "org", "repo", 42, mock.MatchedBy(func(body string) bool {
+		return strings.Contains(body, "- [ ] Add a retry helper\n- [ ] Use it in the poller")
+	})).Return(&github.IssueComment{}, nil).Once()
+	store.On("SaveWorkflow", mock.MatchedBy(func(wf *kvstore.HITLWorkflow) bool {
+		return wf.PlanChecklistPRURL == prURL && wf.PlanCommentPRURL == ""
+	})).Return(nil).Once()
+
+	p.postApprovedPlanToPR(agent, prURL)
+
+	// A repeated PR event does not post the checklist again.
+	p.postApprovedPlanToPR(agent, prURL)
+
+	ghMock.AssertExpectations(t)
+	store.AssertExpectations(t)
+}
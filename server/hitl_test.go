@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
+	"github.com/mattermost/mattermost-plugin-cursor/server/command"
 	"github.com/mattermost/mattermost-plugin-cursor/server/cursor"
 	"github.com/mattermost/mattermost-plugin-cursor/server/parser"
 	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
@@ -290,6 +291,9 @@ func TestAcceptContext_SkipPlan_LaunchesImplementer(t *testing.T) {
 	store.On("SaveAgent", mock.Anything).Return(nil)
 	store.On("SetThreadAgent", "root-1", "agent-impl-1").Return(nil)
 	store.On("SetAgentWorkflow", "agent-impl-1", "wf-1").Return(nil)
+	store.On("AddUserLaunchHistoryEntry", "user-1", mock.MatchedBy(func(entry kvstore.LaunchHistoryEntry) bool {
+		return entry.Prompt == "fix the bug" && entry.Repository == "org/repo" && entry.Branch == "main" && entry.Model == "auto"
+	}), command.LaunchHistorySize).Return(nil).Once()
 	api.On("PublishWebSocketEvent", mock.Anything, mock.Anything, mock.Anything).Return().Maybe()
 
 	p.acceptContext(workflow)
@@ -297,6 +301,7 @@ func TestAcceptContext_SkipPlan_LaunchesImplementer(t *testing.T) {
 	assert.Equal(t, kvstore.PhaseImplementing, workflow.Phase)
 	assert.Equal(t, "Enriched context", workflow.ApprovedContext)
 	cursorClient.AssertCalled(t, "LaunchAgent", mock.Anything, mock.Anything)
+	store.AssertExpectations(t)
 }
 
 func TestAcceptContext_PlanEnabled_TransitionsToPlanning(t *testing.T) {
@@ -345,6 +350,12 @@ func TestAcceptContext_PlanEnabled_TransitionsToPlanning(t *testing.T) {
 		Status: cursor.AgentStatusCreating,
 	}, nil)
 
+	siteURL := "http://localhost:8065"
+	api.On("GetConfig").Return(&model.Config{
+		ServiceSettings: model.ServiceSettings{
+			SiteURL: &siteURL,
+		},
+	}).Maybe()
 	api.On("CreatePost", mock.Anything).Return(&model.Post{Id: "reply-1"}, nil)
 	store.On("SaveAgent", mock.Anything).Return(nil)
 	store.On("SetAgentWorkflow", "planner-1", "wf-1").Return(nil)
@@ -879,6 +890,7 @@ func TestHandlePlannerFinished_Success(t *testing.T) {
 
 	assert.Equal(t, kvstore.PhasePlanReview, workflow.Phase)
 	assert.Equal(t, "### Summary\nHere is the plan.", workflow.RetrievedPlan)
+	assert.Equal(t, []string{"### Summary\nHere is the plan."}, workflow.PlanVersions)
 	assert.Equal(t, "plan-review-post-1", workflow.PlanPostID)
 	cursorClient.AssertExpectations(t)
 }
@@ -1074,11 +1086,105 @@ func TestAcceptPlan(t *testing.T) {
 	assert.Equal(t, "### Summary\nThe plan.", workflow.ApprovedPlan)
 	assert.Equal(t, "impl-agent-1", workflow.ImplementerAgentID)
 	assert.Equal(t, kvstore.PhaseImplementing, workflow.Phase)
+	assert.Empty(t, workflow.PlanChecklist, "checklist is only stored when PlanAsPRChecklist is enabled")
 
 	cursorClient.AssertExpectations(t)
 	store.AssertExpectations(t)
 }
 
+func TestAcceptPartialPlan_WithPlan(t *testing.T) {
+	p, api, cursorClient, store := setupTestPlugin(t)
+
+	workflow := &kvstore.HITLWorkflow{
+		ID:              "wf-1",
+		UserID:          "user-1",
+		ChannelID:       "ch-1",
+		RootPostID:      "root-1",
+		TriggerPostID:   "trigger-1",
+		Repository:      "org/repo",
+		Branch:          "main",
+		Model:           "auto",
+		AutoCreatePR:    true,
+		Phase:           kvstore.PhasePlanning,
+		ApprovedContext: "Fix the bug",
+		OriginalPrompt:  "fix the bug",
+		PlannerAgentID:  "planner-1",
+	}
+	store.On("GetWorkflow", "wf-1").Return(workflow, nil)
+
+	cursorClient.On("GetConversation", mock.Anything, "planner-1").Return(&cursor.Conversation{
+		Messages: []cursor.Message{
+			{Type: "user_message", Text: "plan this"},
+			{Type: "assistant_message", Text: "### Summary\nThe partial plan."},
+		},
+	}, nil)
+
+	// The planner is still running and gets stopped.
+	store.On("GetAgent", "planner-1").Return(&kvstore.AgentRecord{
+		CursorAgentID: "planner-1",
+		Status:        "RUNNING",
+	}, nil)
+	cursorClient.On("StopAgent", mock.Anything, "planner-1").Return(&cursor.StopResponse{ID: "planner-1"}, nil).Once()
+
+	cursorClient.On("LaunchAgent", mock.Anything, mock.MatchedBy(func(req cursor.LaunchAgentRequest) bool {
+		return strings.Contains(req.Prompt.Text, "The partial plan.")
+	})).Return(&cursor.Agent{
+		ID:     "impl-agent-1",
+		Status: cursor.AgentStatusCreating,
+	}, nil)
+
+	store.On("SaveWorkflow", mock.Anything).Return(nil)
+	store.On("SaveAgent", mock.Anything).Return(nil)
+	store.On("SetThreadAgent", "root-1", "impl-agent-1").Return(nil)
+	store.On("SetAgentWorkflow", "impl-agent-1", "wf-1").Return(nil)
+	api.On("CreatePost", mock.Anything).Return(&model.Post{Id: "reply"}, nil)
+	api.On("PublishWebSocketEvent", mock.Anything, mock.Anything, mock.Anything).Return().Maybe()
+
+	p.acceptPartialPlan("wf-1")
+
+	assert.Equal(t, "### Summary\nThe partial plan.", workflow.ApprovedPlan)
+	assert.Equal(t, "impl-agent-1", workflow.ImplementerAgentID)
+	assert.Equal(t, kvstore.PhaseImplementing, workflow.Phase)
+	assert.Len(t, workflow.PlanVersions, 1)
+	api.AssertCalled(t, "CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		return strings.Contains(post.Message, "Accepted the partial plan")
+	}))
+
+	cursorClient.AssertExpectations(t)
+}
+
+func TestAcceptPartialPlan_EmptyPlanStaysInPlanning(t *testing.T) {
+	p, api, cursorClient, store := setupTestPlugin(t)
+
+	workflow := &kvstore.HITLWorkflow{
+		ID:             "wf-1",
+		UserID:         "user-1",
+		ChannelID:      "ch-1",
+		RootPostID:     "root-1",
+		Phase:          kvstore.PhasePlanning,
+		PlannerAgentID: "planner-1",
+	}
+	store.On("GetWorkflow", "wf-1").Return(workflow, nil)
+
+	cursorClient.On("GetConversation", mock.Anything, "planner-1").Return(&cursor.Conversation{
+		Messages: []cursor.Message{
+			{Type: "user_message", Text: "plan this"},
+		},
+	}, nil)
+
+	api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		return strings.Contains(post.Message, "hasn't produced a plan yet")
+	})).Return(&model.Post{Id: "warning-post"}, nil).Once()
+
+	p.acceptPartialPlan("wf-1")
+
+	assert.Equal(t, kvstore.PhasePlanning, workflow.Phase)
+	assert.Empty(t, workflow.RetrievedPlan)
+	cursorClient.AssertNotCalled(t, "StopAgent", mock.Anything, mock.Anything)
+	store.AssertNotCalled(t, "SaveWorkflow", mock.Anything)
+	api.AssertExpectations(t)
+}
+
 func TestIteratePlan(t *testing.T) {
 	p, api, cursorClient, store := setupTestPlugin(t)
 
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+// kvErrorThreshold is how many consecutive KV store errors put the plugin in
+// degraded mode.
+const kvErrorThreshold = 3
+
+// kvHealth tracks consecutive KV store errors seen by the webhook handler and
+// the poller. Once the threshold is reached the store is considered
+// unavailable until the next successful call.
+type kvHealth struct {
+	sync.Mutex
+
+	consecutiveErrors int
+	degraded          bool
+
+	// notified is set once admins have been told about the current outage,
+	// so an outage produces a single notification.
+	notified bool
+}
+
+// recordKVError counts a failed KV store call. Reaching kvErrorThreshold
+// switches the plugin to degraded mode and notifies system admins once per
+// outage.
+func (p *Plugin) recordKVError(op string, err error) {
+	p.kvHealth.Lock()
+	p.kvHealth.consecutiveErrors++
+	enteringDegraded := !p.kvHealth.degraded && p.kvHealth.consecutiveErrors >= kvErrorThreshold
+	if enteringDegraded {
+		p.kvHealth.degraded = true
+	}
+	notify := enteringDegraded && !p.kvHealth.notified
+	if notify {
+		p.kvHealth.notified = true
+	}
+	p.kvHealth.Unlock()
+
+	if !enteringDegraded {
+		return
+	}
+	p.API.LogError("KV store unavailable, entering degraded mode",
+		"operation", op,
+		"error", err.Error(),
+	)
+	if notify {
+		p.notifyAdminsKVUnavailable(op, err)
+	}
+}
+
+// recordKVSuccess resets the error count after a successful KV store call,
+// leaving degraded mode if it was active.
+func (p *Plugin) recordKVSuccess() {
+	p.kvHealth.Lock()
+	recovered := p.kvHealth.degraded
+	p.kvHealth.consecutiveErrors = 0
+	p.kvHealth.degraded = false
+	p.kvHealth.notified = false
+	p.kvHealth.Unlock()
+
+	if recovered {
+		p.API.LogInfo("KV store available again, leaving degraded mode")
+	}
+}
+
+// kvDegraded reports whether the KV store is currently considered unavailable.
+func (p *Plugin) kvDegraded() bool {
+	p.kvHealth.Lock()
+	defer p.kvHealth.Unlock()
+	return p.kvHealth.degraded
+}
+
+// notifyAdminsKVUnavailable sends each system admin a direct message that the
+// KV store is failing and webhooks are being skipped.
+func (p *Plugin) notifyAdminsKVUnavailable(op string, err error) {
+	message := fmt.Sprintf(
+		"The Cursor plugin cannot reach its KV store (`%s` failed %d times in a row: %s). "+
+			"GitHub webhooks are acknowledged but not processed until the store recovers.",
+		op, kvErrorThreshold, err.Error(),
+	)
+	p.notifySystemAdmins("KV store outage", message)
+}
+
+// notifySystemAdmins sends message to each active system admin as a direct
+// message from the bot. topic only labels the log lines of failed sends.
+func (p *Plugin) notifySystemAdmins(topic, message string) {
+	admins, appErr := p.API.GetUsers(&model.UserGetOptions{
+		Role:    model.SystemAdminRoleId,
+		Active:  true,
+		PerPage: 100,
+	})
+	if appErr != nil {
+		p.API.LogError("Failed to list system admins for notification",
+			"topic", topic,
+			"error", appErr.Error(),
+		)
+		return
+	}
+
+	botUserID := p.getBotUserID()
+	for _, admin := range admins {
+		channel, appErr := p.API.GetDirectChannel(botUserID, admin.Id)
+		if appErr != nil {
+			p.API.LogError("Failed to open direct channel for admin notification",
+				"topic", topic,
+				"user_id", admin.Id,
+				"error", appErr.Error(),
+			)
+			continue
+		}
+		if _, appErr := p.API.CreatePost(p.decorateBotPost(&model.Post{
+			UserId:    botUserID,
+			ChannelId: channel.Id,
+			Message:   message,
+		})); appErr != nil {
+			p.API.LogError("Failed to notify admin",
+				"topic", topic,
+				"user_id", admin.Id,
+				"error", appErr.Error(),
+			)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func mockKVOutageAdminNotification(api *mockPluginAPI) {
+	api.On("GetUsers", mock.MatchedBy(func(opts *model.UserGetOptions) bool {
+		return opts.Role == model.SystemAdminRoleId
+	})).Return([]*model.User{{Id: "admin-1"}}, nil).Once()
+	api.On("GetDirectChannel", mock.Anything, "admin-1").Return(&model.Channel{Id: "dm-admin-1"}, nil).Once()
+	api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		return post.ChannelId == "dm-admin-1"
+	})).Return(&model.Post{Id: "kv-notice"}, nil).Once()
+}
+
+func TestWebhook_RepeatedKVErrorsDegradeAndNotifyOnce(t *testing.T) {
+	p, store := setupWebhookTestPlugin(t)
+	api := p.API.(*mockPluginAPI)
+	mockKVOutageAdminNotification(api)
+
+	kvErr := errors.New("kv unavailable")
+	store.On("HasDeliveryBeenProcessed", mock.Anything).Return(false, kvErr)
+	store.On("MarkDeliveryProcessed", mock.Anything).Return(kvErr)
+
+	body, _ := json.Marshal(PingEvent{Zen: "Keep it logically awesome.", HookID: 42})
+	sig := signPayload(testWebhookSecret, body)
+	send := func(deliveryID string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		p.handleGitHubWebhook(rr, makeWebhookRequest(t, "ping", deliveryID, body, sig))
+		return rr
+	}
+
+	// The first delivery is still processed; its two KV failures are below
+	// the threshold.
+	rr := send("delivery-1")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"status": "ok"`)
+	assert.False(t, p.kvDegraded())
+
+	// The third consecutive failure degrades the plugin: deliveries are
+	// acknowledged without being handled.
+	rr = send("delivery-2")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, rr.Body.String())
+	assert.True(t, p.kvDegraded())
+
+	rr = send("delivery-3")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, rr.Body.String())
+
+	// Admins are told once per outage.
+	api.AssertNumberOfCalls(t, "CreatePost", 1)
+	store.AssertNumberOfCalls(t, "MarkDeliveryProcessed", 1)
+}
+
+func TestWebhook_KVRecoveryLeavesDegradedMode(t *testing.T) {
+	p, store := setupWebhookTestPlugin(t)
+	api := p.API.(*mockPluginAPI)
+	mockKVOutageAdminNotification(api)
+
+	for i := 0; i < kvErrorThreshold; i++ {
+		p.recordKVError("ListActiveAgents", errors.New("kv unavailable"))
+	}
+	assert.True(t, p.kvDegraded())
+
+	store.On("HasDeliveryBeenProcessed", "delivery-ok").Return(false, nil)
+	store.On("MarkDeliveryProcessed", "delivery-ok").Return(nil)
+
+	body, _ := json.Marshal(PingEvent{Zen: "Keep it logically awesome.", HookID: 42})
+	rr := httptest.NewRecorder()
+	p.handleGitHubWebhook(rr, makeWebhookRequest(t, "ping", "delivery-ok", body, signPayload(testWebhookSecret, body)))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"status": "ok"`)
+	assert.False(t, p.kvDegraded())
+	store.AssertCalled(t, "MarkDeliveryProcessed", "delivery-ok")
+	api.AssertCalled(t, "LogInfo", "KV store available again, leaving degraded mode")
+}
+
+func TestPollAgentStatuses_RepeatedKVErrorsDegrade(t *testing.T) {
+	p, api, _, store := setupTestPlugin(t)
+	mockKVOutageAdminNotification(api)
+
+	store.On("ListActiveAgents").Return(nil, errors.New("kv unavailable"))
+
+	for i := 0; i < kvErrorThreshold+2; i++ {
+		p.pollAgentStatuses()
+	}
+
+	assert.True(t, p.kvDegraded())
+	api.AssertNumberOfCalls(t, "GetUsers", 1)
+	api.AssertNumberOfCalls(t, "CreatePost", 1)
+}
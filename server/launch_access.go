@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+// canLaunchInChannel reports whether userID may launch agents in channelID.
+// Channels without allowed launchers are open to everyone; otherwise only the
+// listed users and channel or system admins may launch. A settings lookup
+// failure leaves the channel open rather than blocking every launch.
+func (p *Plugin) canLaunchInChannel(userID, channelID string) bool {
+	settings, err := p.kvstore.GetChannelSettings(channelID)
+	if err != nil {
+		p.API.LogWarn("Failed to load channel settings for launch check", "channel_id", channelID, "error", err.Error())
+		return true
+	}
+	if settings == nil || len(settings.AllowedLauncherUserIDs) == 0 {
+		return true
+	}
+	if slices.Contains(settings.AllowedLauncherUserIDs, userID) {
+		return true
+	}
+	return p.canManageChannelLaunchers(userID, channelID)
+}
+
+// canManageChannelLaunchers reports whether userID may change who launches
+// agents in channelID: channel admins and system admins.
+func (p *Plugin) canManageChannelLaunchers(userID, channelID string) bool {
+	return p.API.HasPermissionTo(userID, model.PermissionManageSystem) ||
+		p.API.HasPermissionToChannel(userID, channelID, model.PermissionManageChannelRoles)
+}
+
+// resolveLauncherUsernames resolves the comma-separated usernames entered as
+// a channel's allowed launchers to user IDs, in order and without repeats.
+func (p *Plugin) resolveLauncherUsernames(raw string) ([]string, error) {
+	var userIDs []string
+	for field := range strings.SplitSeq(raw, ",") {
+		username := strings.TrimPrefix(strings.TrimSpace(field), "@")
+		if username == "" {
+			continue
+		}
+		user, appErr := p.API.GetUserByUsername(username)
+		if appErr != nil || user == nil {
+			return nil, fmt.Errorf("user @%s not found", username)
+		}
+		if !slices.Contains(userIDs, user.Id) {
+			userIDs = append(userIDs, user.Id)
+		}
+	}
+	return userIDs, nil
+}
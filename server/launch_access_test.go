@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/command"
+	"github.com/mattermost/mattermost-plugin-cursor/server/cursor"
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+func TestMessageHasBeenPosted_AllowedLauncherLaunches(t *testing.T) {
+	p, api, cursorClient, store := setupTestPlugin(t)
+
+	post := &model.Post{Id: "post-1", UserId: "user-1", ChannelId: "ch-1", Message: "@cursor fix the login bug"}
+
+	store.On("GetUserSettings", "user-1").Return(nil, nil)
+	store.On("GetChannelSettings", "ch-1").Return(&kvstore.ChannelSettings{
+		DefaultRepository:      "org/repo",
+		AllowedLauncherUserIDs: []string{"user-2", "user-1"},
+	}, nil)
+	api.On("AddReaction", mock.Anything).Return(nil, nil)
+	api.On("RemoveReaction", mock.Anything).Return(nil)
+	cursorClient.On("LaunchAgent", mock.Anything, mock.Anything).
+		Return(&cursor.Agent{ID: "agent-123", Status: cursor.AgentStatusCreating}, nil)
+	api.On("CreatePost", mock.Anything).Return(&model.Post{Id: "reply-1"}, nil)
+	store.On("SaveAgent", mock.Anything).Return(nil)
+	store.On("SetThreadAgent", "post-1", "agent-123").Return(nil)
+	api.On("PublishWebSocketEvent", "agent_created", mock.Anything, mock.Anything).Return()
+
+	p.MessageHasBeenPosted(nil, post)
+
+	cursorClient.AssertExpectations(t)
+	api.AssertNotCalled(t, "HasPermissionTo", mock.Anything, mock.Anything)
+}
+
+func TestMessageHasBeenPosted_DisallowedLauncherBlocked(t *testing.T) {
+	p, api, cursorClient, store := setupTestPlugin(t)
+
+	post := &model.Post{Id: "post-1", UserId: "user-1", ChannelId: "ch-1", Message: "@cursor fix the login bug"}
+
+	store.On("GetUserSettings", "user-1").Return(nil, nil)
+	store.On("GetChannelSettings", "ch-1").Return(&kvstore.ChannelSettings{
+		DefaultRepository:      "org/repo",
+		AllowedLauncherUserIDs: []string{"user-2"},
+	}, nil)
+	api.On("HasPermissionTo", "user-1", model.PermissionManageSystem).Return(false)
+	api.On("HasPermissionToChannel", "user-1", "ch-1", model.PermissionManageChannelRoles).Return(false)
+	api.On("AddReaction", mock.Anything).Return(nil, nil)
+	api.On("RemoveReaction", mock.Anything).Return(nil)
+	api.On("SendEphemeralPost", "user-1", mock.MatchedBy(func(post *model.Post) bool {
+		return post.Message == command.LaunchNotPermittedMessage
+	})).Return(&model.Post{}).Once()
+
+	p.MessageHasBeenPosted(nil, post)
+
+	api.AssertExpectations(t)
+	cursorClient.AssertNotCalled(t, "LaunchAgent", mock.Anything, mock.Anything)
+	store.AssertNotCalled(t, "SaveAgent", mock.Anything)
+}
+
+func TestCanLaunchInChannel_AdminsBypassAllowlist(t *testing.T) {
+	p, api, _, store := setupTestPlugin(t)
+
+	store.On("GetChannelSettings", "ch-open").Return(&kvstore.ChannelSettings{}, nil)
+	store.On("GetChannelSettings", "ch-1").Return(&kvstore.ChannelSettings{AllowedLauncherUserIDs: []string{"user-2"}}, nil)
+	api.On("HasPermissionTo", mock.Anything, model.PermissionManageSystem).Return(false)
+	api.On("HasPermissionToChannel", "channel-admin", "ch-1", model.PermissionManageChannelRoles).Return(true)
+	api.On("HasPermissionToChannel", mock.Anything, "ch-1", model.PermissionManageChannelRoles).Return(false)
+
+	assert.True(t, p.canLaunchInChannel("user-1", "ch-open"))
+	assert.True(t, p.canLaunchInChannel("user-2", "ch-1"))
+	assert.True(t, p.canLaunchInChannel("channel-admin", "ch-1"))
+	assert.False(t, p.canLaunchInChannel("user-1", "ch-1"))
+}
+
+func submitSettingsDialog(p *Plugin, userID string, submission map[string]any) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(model.SubmitDialogRequest{
+		UserId:     userID,
+		State:      "ch-1|" + userID,
+		Submission: submission,
+	})
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/dialog/settings", bytes.NewReader(body))
+	r.Header.Set("Mattermost-User-ID", userID)
+	p.ServeHTTP(nil, w, r)
+	return w
+}
+
+func TestSettingsDialog_AdminSavesAllowedLaunchers(t *testing.T) {
+	p, api, store := setupDialogTestPlugin(t)
+
+	api.On("HasPermissionTo", "admin-1", model.PermissionManageSystem).Return(false)
+	api.On("HasPermissionToChannel", "admin-1", "ch-1", model.PermissionManageChannelRoles).Return(true)
+	api.On("GetUserByUsername", "alice").Return(&model.User{Id: "user-alice"}, nil)
+	api.On("GetUserByUsername", "bob").Return(&model.User{Id: "user-bob"}, nil)
+	store.On("SaveChannelSettings", "ch-1", &kvstore.ChannelSettings{
+		AllowedLauncherUserIDs: []string{"user-alice", "user-bob"},
+	}).Return(nil).Once()
+	store.On("GetUserSettings", "admin-1").Return(nil, nil)
+	store.On("SaveUserSettings", "admin-1", mock.Anything).Return(nil)
+	api.On("SendEphemeralPost", "admin-1", mock.Anything).Return(&model.Post{})
+
+	w := submitSettingsDialog(p, "admin-1", map[string]any{"channel_allowed_launchers": "@alice, bob, alice"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	store.AssertExpectations(t)
+	store.AssertNotCalled(t, "GetChannelSettings", mock.Anything)
+}
+
+func TestSettingsDialog_UnknownAllowedLauncher(t *testing.T) {
+	p, api, store := setupDialogTestPlugin(t)
+
+	api.On("HasPermissionTo", "admin-1", model.PermissionManageSystem).Return(true)
+	api.On("GetUserByUsername", "ghost").Return(nil, model.NewAppError("GetUserByUsername", "not_found", nil, "", http.StatusNotFound))
+
+	w := submitSettingsDialog(p, "admin-1", map[string]any{"channel_allowed_launchers": "ghost"})
+
+	var resp model.SubmitDialogResponse
+	_ = json.NewDecoder(w.Result().Body).Decode(&resp)
+	assert.Contains(t, resp.Errors["channel_allowed_launchers"], "@ghost not found")
+	store.AssertNotCalled(t, "SaveChannelSettings", mock.Anything, mock.Anything)
+}
+
+func TestSettingsDialog_NonAdminKeepsAllowedLaunchers(t *testing.T) {
+	p, api, store := setupDialogTestPlugin(t)
+
+	api.On("HasPermissionTo", "user-1", model.PermissionManageSystem).Return(false)
+	api.On("HasPermissionToChannel", "user-1", "ch-1", model.PermissionManageChannelRoles).Return(false)
+	store.On("GetChannelSettings", "ch-1").Return(&kvstore.ChannelSettings{AllowedLauncherUserIDs: []string{"user-2"}}, nil)
+	store.On("SaveChannelSettings", "ch-1", &kvstore.ChannelSettings{
+		DefaultRepository:      "org/repo",
+		AllowedLauncherUserIDs: []string{"user-2"},
+	}).Return(nil).Once()
+	store.On("GetUserSettings", "user-1").Return(nil, nil)
+	store.On("SaveUserSettings", "user-1", mock.Anything).Return(nil)
+	api.On("SendEphemeralPost", "user-1", mock.Anything).Return(&model.Post{})
+
+	// A non-admin cannot change the list, even by submitting the field.
+	w := submitSettingsDialog(p, "user-1", map[string]any{
+		"channel_default_repo":      "org/repo",
+		"channel_allowed_launchers": "",
+	})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	store.AssertExpectations(t)
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/attachments"
+	"github.com/mattermost/mattermost-plugin-cursor/server/cursor"
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+// maxLiveProgressActivityLength bounds the activity line on the launch card.
+const maxLiveProgressActivityLength = 300
+
+// updateLiveProgress edits a running agent's launch card to show its latest
+// activity. It reads the conversation at most once per configured interval,
+// and only edits the card when assistant messages arrived since the last
+// update. Terminal handlers replace the card, so updates stop on their own
+// once the agent finishes. Failures are logged and never affect polling.
+func (p *Plugin) updateLiveProgress(record *kvstore.AgentRecord) {
+	interval := p.getConfiguration().GetProgressUpdateInterval()
+	if interval == 0 || record.BotReplyPostID == "" {
+		return
+	}
+	now := p.now()
+	if record.LiveProgressAt != 0 && now.Sub(time.UnixMilli(record.LiveProgressAt)) < interval {
+		return
+	}
+	cursorClient := p.getCursorClient()
+	if cursorClient == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	conv, err := cursorClient.GetConversation(ctx, record.CursorAgentID)
+	if err != nil {
+		p.API.LogWarn("Failed to fetch conversation for live progress",
+			"agent_id", record.CursorAgentID,
+			"error", err.Error(),
+		)
+		return
+	}
+
+	updates := newAssistantMessages(conv, record.LiveProgressMessageID)
+	if len(updates) == 0 {
+		return
+	}
+	latest := updates[len(updates)-1]
+
+	p.updateBotReplyWithAttachment(record.BotReplyPostID, attachments.BuildRunningProgressAttachment(
+		record.CursorAgentID,
+		record.Repository,
+		record.Branch,
+		record.Model,
+		liveProgressActivity(latest.Text),
+		len(updates),
+	))
+
+	record.LiveProgressMessageID = progressMessageKey(latest)
+	record.LiveProgressAt = now.UnixMilli()
+	if err := p.kvstore.SaveAgent(record); err != nil {
+		p.API.LogWarn("Failed to save agent live progress state",
+			"agent_id", record.CursorAgentID,
+			"error", err.Error(),
+		)
+	}
+}
+
+// newAssistantMessages returns the non-empty assistant messages that follow
+// the message identified by lastKey. All of them are returned when lastKey is
+// empty or no longer in the conversation.
+func newAssistantMessages(conv *cursor.Conversation, lastKey string) []*cursor.Message {
+	if conv == nil {
+		return nil
+	}
+	start := 0
+	if lastKey != "" {
+		for i := len(conv.Messages) - 1; i >= 0; i-- {
+			if progressMessageKey(&conv.Messages[i]) == lastKey {
+				start = i + 1
+				break
+			}
+		}
+	}
+	var messages []*cursor.Message
+	for i := start; i < len(conv.Messages); i++ {
+		msg := &conv.Messages[i]
+		if msg.Type == "assistant_message" && strings.TrimSpace(msg.Text) != "" {
+			messages = append(messages, msg)
+		}
+	}
+	return messages
+}
+
+// liveProgressActivity condenses an agent message to its first non-empty
+// line, which is usually a short description of what the agent is doing.
+func liveProgressActivity(text string) string {
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return truncateText(line, maxLiveProgressActivityLength)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/cursor"
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+// liveProgressEdits returns the "Latest activity" values of launch card edits.
+func liveProgressEdits(api *plugintest.API) []string {
+	var activities []string
+	for _, call := range api.Calls {
+		if call.Method != "UpdatePost" {
+			continue
+		}
+		post, ok := call.Arguments.Get(0).(*model.Post)
+		if !ok {
+			continue
+		}
+		for _, att := range post.Attachments() {
+			for _, field := range att.Fields {
+				if strings.HasPrefix(field.Title, "Latest activity") {
+					activities = append(activities, field.Value.(string))
+				}
+			}
+		}
+	}
+	return activities
+}
+
+func setupLiveProgressTest(t *testing.T) (*Plugin, *plugintest.API, *mockCursorClient, *mockKVStore, *kvstore.AgentRecord) {
+	p, api, cursorClient, store := setupPollerPlugin(t)
+	p.configuration.ProgressUpdateSeconds = 60
+
+	record := newMirrorTestRecord()
+	store.On("ListActiveAgents").Return([]*kvstore.AgentRecord{record}, nil)
+	store.On("GetAgent", "agent-1").Return(record, nil)
+	store.On("SaveAgent", mock.Anything).Return(nil)
+	cursorClient.On("GetAgent", mock.Anything, "agent-1").Return(&cursor.Agent{
+		ID:     "agent-1",
+		Status: cursor.AgentStatusRunning,
+	}, nil)
+
+	return p, api, cursorClient, store, record
+}
+
+func TestPoller_LiveProgressEditsLaunchCard(t *testing.T) {
+	p, api, cursorClient, _, record := setupLiveProgressTest(t)
+
+	cursorClient.On("GetConversation", mock.Anything, "agent-1").Return(&cursor.Conversation{
+		Messages: []cursor.Message{
+			{ID: "m1", Type: "user_message", Text: "Fix the login bug"},
+			{ID: "m2", Type: "assistant_message", Text: "Reading the auth handlers"},
+			{ID: "m3", Type: "assistant_message", Text: "\nEditing server/api.go\n\nThe token check is inverted."},
+		},
+	}, nil).Once()
+
+	p.pollAgentStatuses()
+
+	edits := liveProgressEdits(api)
+	require.Len(t, edits, 1)
+	assert.Equal(t, "Editing server/api.go", edits[0])
+	assert.Equal(t, "m3", record.LiveProgressMessageID)
+	assert.NotZero(t, record.LiveProgressAt)
+	api.AssertCalled(t, "GetPost", "bot-reply-1")
+}
+
+func TestPoller_LiveProgressNoNewMessagesNoEdit(t *testing.T) {
+	p, api, cursorClient, store, record := setupLiveProgressTest(t)
+	record.LiveProgressMessageID = "m2"
+	record.LiveProgressAt = time.Now().Add(-2 * time.Minute).UnixMilli()
+
+	cursorClient.On("GetConversation", mock.Anything, "agent-1").Return(&cursor.Conversation{
+		Messages: []cursor.Message{
+			{ID: "m1", Type: "user_message", Text: "Fix the login bug"},
+			{ID: "m2", Type: "assistant_message", Text: "Reading the auth handlers"},
+		},
+	}, nil).Once()
+
+	p.pollAgentStatuses()
+
+	assert.Empty(t, liveProgressEdits(api))
+	api.AssertNotCalled(t, "UpdatePost", mock.Anything)
+	store.AssertNotCalled(t, "SaveAgent", mock.Anything)
+	cursorClient.AssertExpectations(t)
+}
+
+func TestPoller_LiveProgressRespectsInterval(t *testing.T) {
+	p, api, cursorClient, _, record := setupLiveProgressTest(t)
+	record.LiveProgressMessageID = "m2"
+	record.LiveProgressAt = time.Now().Add(-10 * time.Second).UnixMilli()
+
+	p.pollAgentStatuses()
+
+	cursorClient.AssertNotCalled(t, "GetConversation", mock.Anything, mock.Anything)
+	assert.Empty(t, liveProgressEdits(api))
+}
+
+func TestPoller_LiveProgressDisabled(t *testing.T) {
+	p, api, cursorClient, _, _ := setupLiveProgressTest(t)
+	p.configuration.ProgressUpdateSeconds = 0
+
+	p.pollAgentStatuses()
+
+	cursorClient.AssertNotCalled(t, "GetConversation", mock.Anything, mock.Anything)
+	assert.Empty(t, liveProgressEdits(api))
+}
+
+func TestPoller_LiveProgressStopsAtTerminalStatus(t *testing.T) {
+	p, api, cursorClient, store := setupPollerPlugin(t)
+	p.configuration.ProgressUpdateSeconds = 60
+
+	record := newMirrorTestRecord()
+	store.On("ListActiveAgents").Return([]*kvstore.AgentRecord{record}, nil)
+	store.On("GetAgent", "agent-1").Return(record, nil)
+	store.On("GetWorkflowByAgent", "agent-1").Return("", nil)
+	store.On("SaveAgent", mock.Anything).Return(nil)
+	cursorClient.On("GetAgent", mock.Anything, "agent-1").Return(&cursor.Agent{
+		ID:     "agent-1",
+		Status: cursor.AgentStatusFinished,
+	}, nil)
+	api.On("CreatePost", mock.Anything).Return(&model.Post{Id: "msg-1"}, nil)
+
+	p.pollAgentStatuses()
+
+	cursorClient.AssertNotCalled(t, "GetConversation", mock.Anything, mock.Anything)
+	assert.Empty(t, liveProgressEdits(api))
+}
+
+func TestNewAssistantMessages(t *testing.T) {
+	conv := &cursor.Conversation{
+		Messages: []cursor.Message{
+			{ID: "m1", Type: "assistant_message", Text: "first"},
+			{ID: "m2", Type: "user_message", Text: "follow-up"},
+			{ID: "m3", Type: "assistant_message", Text: "  "},
+			{ID: "m4", Type: "assistant_message", Text: "second"},
+		},
+	}
+
+	assert.Nil(t, newAssistantMessages(nil, ""))
+	assert.Len(t, newAssistantMessages(conv, ""), 2)
+	assert.Len(t, newAssistantMessages(conv, "gone"), 2)
+
+	messages := newAssistantMessages(conv, "m1")
+	require.Len(t, messages, 1)
+	assert.Equal(t, "second", messages[0].Text)
+	assert.Empty(t, newAssistantMessages(conv, "m4"))
+}
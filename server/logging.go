@@ -0,0 +1,67 @@
+package main
+
+import "context"
+
+// logger is the part of the plugin API used to write server log lines.
+type logger interface {
+	LogDebug(msg string, keyValuePairs ...any)
+	LogInfo(msg string, keyValuePairs ...any)
+	LogWarn(msg string, keyValuePairs ...any)
+	LogError(msg string, keyValuePairs ...any)
+}
+
+// deliveryLogger tags every line with the GitHub delivery ID being processed,
+// so all the lines written for one webhook delivery can be traced together.
+type deliveryLogger struct {
+	logger
+	deliveryID string
+}
+
+func (l *deliveryLogger) LogDebug(msg string, keyValuePairs ...any) {
+	l.logger.LogDebug(msg, l.tag(keyValuePairs)...)
+}
+
+func (l *deliveryLogger) LogInfo(msg string, keyValuePairs ...any) {
+	l.logger.LogInfo(msg, l.tag(keyValuePairs)...)
+}
+
+func (l *deliveryLogger) LogWarn(msg string, keyValuePairs ...any) {
+	l.logger.LogWarn(msg, l.tag(keyValuePairs)...)
+}
+
+func (l *deliveryLogger) LogError(msg string, keyValuePairs ...any) {
+	l.logger.LogError(msg, l.tag(keyValuePairs)...)
+}
+
+func (l *deliveryLogger) tag(keyValuePairs []any) []any {
+	tagged := make([]any, 0, len(keyValuePairs)+2)
+	tagged = append(tagged, keyValuePairs...)
+	return append(tagged, "delivery", l.deliveryID)
+}
+
+type loggerContextKey struct{}
+
+// withDeliveryLogger returns a copy of ctx carrying a logger that tags its
+// lines with deliveryID. ctx is returned unchanged when deliveryID is empty.
+func (p *Plugin) withDeliveryLogger(ctx context.Context, deliveryID string) context.Context {
+	if deliveryID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, loggerContextKey{}, &deliveryLogger{logger: p.API, deliveryID: deliveryID})
+}
+
+// logger returns the logger carried by ctx, or the plugin API when ctx has
+// none, e.g. outside webhook processing.
+func (p *Plugin) logger(ctx context.Context) logger {
+	if l, ok := ctx.Value(loggerContextKey{}).(logger); ok {
+		return l
+	}
+	return p.API
+}
+
+// logDebugContext is logDebug through the logger carried by ctx.
+func (p *Plugin) logDebugContext(ctx context.Context, msg string, keyValuePairs ...any) {
+	if p.getConfiguration().EnableDebugLogging {
+		p.logger(ctx).LogDebug(msg, keyValuePairs...)
+	}
+}
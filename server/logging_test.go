@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v68/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/cursor"
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+// allowAnyLogLines accepts log calls of any length, since correlated lines
+// carry two more arguments than the mocks set up for untagged ones.
+func allowAnyLogLines(api *mockPluginAPI) {
+	for _, method := range []string{"LogDebug", "LogInfo", "LogWarn", "LogError"} {
+		args := []any{mock.Anything}
+		for n := 0; n <= 60; n++ {
+			api.On(method, args...).Maybe()
+			args = append(args, mock.Anything)
+		}
+	}
+}
+
+// logLines returns the message and key/value pairs of every log call made.
+func logLines(api *mockPluginAPI) []mock.Arguments {
+	var lines []mock.Arguments
+	for _, call := range api.Calls {
+		if strings.HasPrefix(call.Method, "Log") {
+			lines = append(lines, call.Arguments)
+		}
+	}
+	return lines
+}
+
+func TestLogger_TagsLinesWithDeliveryID(t *testing.T) {
+	p, api, _, _ := setupReviewLoopTestPlugin(t)
+	allowAnyLogLines(api)
+
+	ctx := p.withDeliveryLogger(context.Background(), "delivery-1")
+	p.logger(ctx).LogWarn("Something happened", "review_loop_id", "loop-1")
+	p.logger(context.Background()).LogWarn("Outside a delivery", "review_loop_id", "loop-1")
+	p.logger(p.withDeliveryLogger(context.Background(), "")).LogWarn("No delivery ID", "review_loop_id", "loop-1")
+
+	api.AssertCalled(t, "LogWarn", "Something happened", "review_loop_id", "loop-1", "delivery", "delivery-1")
+	api.AssertCalled(t, "LogWarn", "Outside a delivery", "review_loop_id", "loop-1")
+	api.AssertCalled(t, "LogWarn", "No delivery ID", "review_loop_id", "loop-1")
+}
+
+func TestWebhook_LogLinesShareDeliveryID(t *testing.T) {
+	p, api, store, ghMock := setupReviewLoopTestPlugin(t)
+	cursorMock := p.cursorClient.(*mockCursorClient)
+	p.configuration.GitHubWebhookSecret = testWebhookSecret
+	p.configuration.EnableDebugLogging = true
+	allowAnyLogLines(api)
+
+	loop := newAwaitingReviewLoop()
+	store.On("HasDeliveryBeenProcessed", "delivery-trace").Return(false, nil)
+	store.On("MarkDeliveryProcessed", "delivery-trace").Return(nil)
+	store.On("GetReviewLoopByPRURL", "https://github.com/org/repo/pull/42").Return(loop, nil)
+	store.On("SaveReviewLoop", mock.Anything).Return(nil)
+	mockInlineStatusUpdate(store, api, "agent-1", &kvstore.AgentRecord{CursorAgentID: "agent-1"})
+
+	ghMock.On("ListReviewComments", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestComment{
+		{
+			ID:       github.Ptr(int64(555)),
+			User:     &github.User{Login: github.Ptr("copilot-pull-request-reviewer")},
+			Path:     github.Ptr("server/api.go"),
+			Line:     github.Ptr(14),
+			Body:     github.Ptr("Check the error returned by Close."),
+			CommitID: github.Ptr("sha-1"),
+		},
+	}, nil)
+	ghMock.On("ListReviews", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestReview{}, nil)
+	ghMock.On("ListIssueComments", mock.Anything, "org", "repo", 42).Return([]*github.IssueComment{}, nil)
+	cursorMock.On("AddFollowup", mock.Anything, "agent-1", mock.Anything).Return(&cursor.FollowupResponse{ID: "agent-1"}, nil)
+
+	rr := httptest.NewRecorder()
+	p.handleGitHubWebhook(rr, signedReviewCommentRequest(t, "delivery-trace", newInlineCommentEvent("copilot-pull-request-reviewer", 555)))
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Equal(t, kvstore.ReviewPhaseCursorFixing, loop.Phase)
+
+	lines := logLines(api)
+	messages := make([]string, 0, len(lines))
+	for _, line := range lines {
+		messages = append(messages, line.String(0))
+		require.GreaterOrEqual(t, len(line), 3, "line %q has no delivery ID", line.String(0))
+		assert.Equal(t, []any{"delivery", "delivery-trace"}, []any(line[len(line)-2:]), "line %q", line.String(0))
+	}
+	assert.Contains(t, messages, "GitHub webhook received")
+	assert.Contains(t, messages, "Review feedback collection summary")
+	assert.Contains(t, messages, "Review feedback dispatch decision")
+}
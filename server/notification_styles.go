@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+// Notification events whose attachment color and title can be overridden
+// with NotificationStyles.
+const (
+	notificationEventPROpened                = "pr_opened"
+	notificationEventPRDraftOpened           = "pr_draft_opened"
+	notificationEventPRMerged                = "pr_merged"
+	notificationEventPRClosed                = "pr_closed"
+	notificationEventReviewApproved          = "review_approved"
+	notificationEventReviewChangesRequested  = "review_changes_requested"
+	notificationEventReviewCommented         = "review_commented"
+	notificationEventReviewLoopApproved      = "review_loop_approved"
+	notificationEventReviewLoopAutoMerged    = "review_loop_auto_merged"
+	notificationEventReviewLoopMergeFailed   = "review_loop_auto_merge_failed"
+	notificationEventReviewLoopMaxIterations = "review_loop_max_iterations"
+	notificationEventReviewLoopStalled       = "review_loop_stalled"
+)
+
+var notificationEvents = map[string]bool{
+	notificationEventPROpened:                true,
+	notificationEventPRDraftOpened:           true,
+	notificationEventPRMerged:                true,
+	notificationEventPRClosed:                true,
+	notificationEventReviewApproved:          true,
+	notificationEventReviewChangesRequested:  true,
+	notificationEventReviewCommented:         true,
+	notificationEventReviewLoopApproved:      true,
+	notificationEventReviewLoopAutoMerged:    true,
+	notificationEventReviewLoopMergeFailed:   true,
+	notificationEventReviewLoopMaxIterations: true,
+	notificationEventReviewLoopStalled:       true,
+}
+
+var hexColorRe = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// notificationStyle overrides an event's attachment. An empty field keeps
+// the built-in color or title.
+type notificationStyle struct {
+	Color         string
+	TitleTemplate string
+}
+
+// notificationVars fills the placeholders of a title template.
+type notificationVars struct {
+	PRNumber int
+	PRTitle  string
+	PRURL    string
+	Reviewer string
+}
+
+// parseNotificationStyles parses NotificationStyles: one override per line as
+// event=color|title template, where either part may be left empty, e.g.
+// pr_merged=#1F8B4C|Shipped: {pr_title}. Blank lines are ignored. Valid
+// lines are returned even when another line is invalid; the error names the
+// first bad line.
+func parseNotificationStyles(raw string) (map[string]notificationStyle, error) {
+	styles := map[string]notificationStyle{}
+	var firstErr error
+	fail := func(err error) {
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	for i, line := range strings.Split(raw, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		event, spec, found := strings.Cut(line, "=")
+		event = strings.ToLower(strings.TrimSpace(event))
+		if !found || event == "" {
+			fail(fmt.Errorf("notification style on line %d must be event=color|title", i+1))
+			continue
+		}
+		if !notificationEvents[event] {
+			fail(fmt.Errorf("notification style on line %d names unknown event %q", i+1, event))
+			continue
+		}
+
+		color, title, _ := strings.Cut(spec, "|")
+		style := notificationStyle{
+			Color:         strings.TrimSpace(color),
+			TitleTemplate: strings.TrimSpace(title),
+		}
+		if style.Color != "" && !hexColorRe.MatchString(style.Color) {
+			fail(fmt.Errorf("notification style on line %d has invalid color %q; use a hex color such as #3DB887", i+1, style.Color))
+			continue
+		}
+		styles[event] = style
+	}
+	return styles, firstErr
+}
+
+// GetNotificationStyles returns the valid NotificationStyles overrides keyed
+// by event.
+func (c *configuration) GetNotificationStyles() map[string]notificationStyle {
+	styles, _ := parseNotificationStyles(c.NotificationStyles)
+	return styles
+}
+
+// styleNotification applies the configured override for event to attachment
+// and returns it. The title template may use {title} for the built-in title
+// and {pr_number}, {pr_title}, {pr_url} and {reviewer}.
+func (p *Plugin) styleNotification(event string, attachment *model.SlackAttachment, vars notificationVars) *model.SlackAttachment {
+	style, ok := p.getConfiguration().GetNotificationStyles()[event]
+	if !ok || attachment == nil {
+		return attachment
+	}
+
+	if style.Color != "" {
+		attachment.Color = style.Color
+	}
+	if style.TitleTemplate != "" {
+		attachment.Title = strings.NewReplacer(
+			"{title}", attachment.Title,
+			"{pr_number}", strconv.Itoa(vars.PRNumber),
+			"{pr_title}", vars.PRTitle,
+			"{pr_url}", vars.PRURL,
+			"{reviewer}", vars.Reviewer,
+		).Replace(style.TitleTemplate)
+	}
+	return attachment
+}
+
+// prNotificationVars returns the title placeholders for a PR webhook
+// notification. reviewer is empty for events without one.
+func prNotificationVars(pr ghPullRequest, reviewer string) notificationVars {
+	return notificationVars{
+		PRNumber: pr.Number,
+		PRTitle:  pr.Title,
+		PRURL:    pr.HTMLURL,
+		Reviewer: reviewer,
+	}
+}
+
+// styleReviewLoopNotification is styleNotification for a review loop's
+// completion attachments.
+func (p *Plugin) styleReviewLoopNotification(event string, loop *kvstore.ReviewLoop, attachment *model.SlackAttachment) *model.SlackAttachment {
+	return p.styleNotification(event, attachment, notificationVars{
+		PRNumber: loop.PRNumber,
+		PRURL:    loop.PRURL,
+	})
+}
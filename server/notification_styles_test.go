@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+func TestParseNotificationStyles(t *testing.T) {
+	styles, err := parseNotificationStyles(`
+pr_merged=#1F8B4C|Shipped: {pr_title}
+REVIEW_APPROVED=#abc
+review_commented=|{title} (FYI)
+`)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]notificationStyle{
+		notificationEventPRMerged:        {Color: "#1F8B4C", TitleTemplate: "Shipped: {pr_title}"},
+		notificationEventReviewApproved:  {Color: "#abc"},
+		notificationEventReviewCommented: {TitleTemplate: "{title} (FYI)"},
+	}, styles)
+}
+
+func TestParseNotificationStyles_Invalid(t *testing.T) {
+	for name, raw := range map[string]string{
+		"bad color":      "pr_merged=green|Merged",
+		"short hex":      "pr_merged=#12345",
+		"unknown event":  "pr_reopened=#3DB887",
+		"missing equals": "pr_merged #3DB887",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseNotificationStyles("pr_closed=#8B8FA7\n" + raw)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "line 2")
+		})
+	}
+
+	styles, _ := parseNotificationStyles("pr_merged=green\npr_closed=#8B8FA7")
+	assert.Equal(t, map[string]notificationStyle{notificationEventPRClosed: {Color: "#8B8FA7"}}, styles)
+}
+
+func TestConfigurationIsValid_RejectsInvalidNotificationColor(t *testing.T) {
+	cfg := &configuration{CursorAPIKey: "key", PollIntervalSeconds: 30, NotificationStyles: "pr_merged=#GGGGGG"}
+	err := cfg.IsValid()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid color "#GGGGGG"`)
+}
+
+func TestWebhook_PRMerged_UsesNotificationStyle(t *testing.T) {
+	p, store := setupWebhookTestPlugin(t)
+	api := p.API.(*mockPluginAPI)
+	p.configuration.NotificationStyles = "pr_merged=#1F8B4C|Shipped #{pr_number}: {pr_title}"
+
+	agent := &kvstore.AgentRecord{
+		CursorAgentID: "agent-123",
+		PostID:        "root-post-1",
+		TriggerPostID: "trigger-post-1",
+		ChannelID:     "ch-1",
+		PrURL:         "https://github.com/org/repo/pull/42",
+	}
+
+	event := PullRequestEvent{
+		Action: "closed",
+		PullRequest: ghPullRequest{
+			Number:  42,
+			HTMLURL: "https://github.com/org/repo/pull/42",
+			Title:   "Fix login bug",
+			State:   "closed",
+			Merged:  true,
+		},
+	}
+	body, _ := json.Marshal(event)
+
+	store.On("HasDeliveryBeenProcessed", "delivery-pr-merged-styled").Return(false, nil)
+	store.On("MarkDeliveryProcessed", "delivery-pr-merged-styled").Return(nil)
+	store.On("GetAgentByPRURL", "https://github.com/org/repo/pull/42").Return(agent, nil)
+	store.On("SaveAgent", mock.Anything).Return(nil)
+	api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		return hasAttachmentWithColor(post, "#1F8B4C") && hasAttachmentWithTitle(post, "Shipped #42: Fix login bug")
+	})).Return(&model.Post{Id: "notification-1"}, nil).Once()
+	api.On("RemoveReaction", mock.Anything).Return(nil)
+	api.On("AddReaction", mock.Anything).Return(nil, nil)
+
+	req := makeWebhookRequest(t, "pull_request", "delivery-pr-merged-styled", body, signPayload(testWebhookSecret, body))
+	rr := httptest.NewRecorder()
+	p.handleGitHubWebhook(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	api.AssertExpectations(t)
+}
+
+func TestReviewLoopApproved_UsesNotificationStyle(t *testing.T) {
+	p, api, store, _, loop, review, pr := setupAutoMergeTest(t)
+	p.configuration.AutoMergeOnApproval = false
+	p.configuration.NotificationStyles = "review_loop_approved=#00FF00|{title} ({reviewer} on PR #{pr_number})"
+
+	store.On("SaveReviewLoop", mock.MatchedBy(func(l *kvstore.ReviewLoop) bool {
+		return l.Phase == kvstore.ReviewPhaseHumanReview
+	})).Return(nil).Once()
+	api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		return hasAttachmentWithColor(post, "#00FF00") &&
+			hasAttachmentWithTitle(post, "CodeRabbit approved the PR! (coderabbitai[bot] on PR #42)")
+	})).Return(&model.Post{Id: "notif-1"}, nil).Once()
+	api.On("CreatePost", mock.Anything).Return(&model.Post{Id: "notif-2"}, nil)
+
+	require.NoError(t, p.handleAIReview(context.Background(), loop, review, pr))
+	api.AssertExpectations(t)
+}
@@ -0,0 +1,41 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxSnoozeDuration caps how far ahead a review loop escalation can be snoozed.
+const MaxSnoozeDuration = 30 * 24 * time.Hour
+
+// ParseSnoozeDuration parses a user-supplied snooze length. It accepts Go
+// durations ("90m", "4h") plus whole days ("2d"). The result must be positive
+// and at most MaxSnoozeDuration.
+func ParseSnoozeDuration(text string) (time.Duration, error) {
+	text = strings.ToLower(strings.TrimSpace(text))
+
+	var d time.Duration
+	if days, ok := strings.CutSuffix(text, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", text)
+		}
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		var err error
+		d, err = time.ParseDuration(text)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", text)
+		}
+	}
+
+	if d <= 0 {
+		return 0, fmt.Errorf("duration must be positive, got %q", text)
+	}
+	if d > MaxSnoozeDuration {
+		return 0, fmt.Errorf("duration must be at most %d days, got %q", int(MaxSnoozeDuration/(24*time.Hour)), text)
+	}
+	return d, nil
+}
@@ -0,0 +1,41 @@
+package parser
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSnoozeDuration(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{input: "4h", expected: 4 * time.Hour},
+		{input: "90m", expected: 90 * time.Minute},
+		{input: "2d", expected: 48 * time.Hour},
+		{input: " 1D ", expected: 24 * time.Hour},
+		{input: "30d", expected: MaxSnoozeDuration},
+		{input: "31d", wantErr: true},
+		{input: "0h", wantErr: true},
+		{input: "-1h", wantErr: true},
+		{input: "xd", wantErr: true},
+		{input: "soon", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseSnoozeDuration(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
@@ -18,14 +18,30 @@ type ParsedMention struct {
 	// Empty string means "use defaults".
 	Branch string
 
+	// Ref is the commit SHA, tag, or branch the agent starts from, extracted
+	// from "ref=<ref>". It overrides Branch as the launch source, while the
+	// agent's working branch is still derived from the prompt. Empty string
+	// means start from Branch.
+	Ref string
+
+	// Base is the branch the agent's PR should target, extracted from
+	// "base=<name>". Empty string means the repository's default branch.
+	Base string
+
 	// Model is the AI model name, extracted from "with <model>" or "model=<name>".
 	// Empty string means "use defaults".
 	Model string
 
 	// AutoPR is a pointer to a bool. nil means "use defaults".
-	// Extracted from "autopr=true|false".
+	// Extracted from "autopr=true|false" or "--no-pr". The agent still pushes
+	// its branch when no PR is opened.
 	AutoPR *bool
 
+	// Draft is a pointer to a bool. nil means "use defaults".
+	// Extracted from "draft=true|false". When true the agent's PR stays a
+	// draft and no review loop starts until someone marks it ready.
+	Draft *bool
+
 	// ForceNew is true when the user wrote "@cursor agent <prompt>",
 	// which means "always launch a new agent even in an existing thread".
 	ForceNew bool
@@ -41,15 +57,24 @@ type ParsedMention struct {
 	// Direct is true when "--direct" flag is present, meaning skip both
 	// context review and plan loop (legacy fire-and-forget behavior).
 	Direct bool
+
+	// SkipAttachments is true when "--no-attach" flag is present, meaning the
+	// files attached to the post are not added to the agent prompt.
+	SkipAttachments bool
+
+	// FileIDs holds the Mattermost file IDs attached to the mention post.
+	// Parse never sets it; callers fill it in from the post.
+	FileIDs []string
 }
 
 var (
 	bracketedRe = regexp.MustCompile(`^\[([^\]]+)\]`)
-	inlineOptRe = regexp.MustCompile(`(?i)\b(repo|branch|model|autopr|review|plan)=(\S+)`)
+	inlineOptRe = regexp.MustCompile(`(?i)\b(repo|branch|ref|base|model|autopr|draft|review|plan)=(\S+)`)
 	inRepoRe    = regexp.MustCompile(`(?i)\bin\s+([a-zA-Z0-9._-]+/[a-zA-Z0-9._-]+)\s*,?`)
 	withModelRe = regexp.MustCompile(`(?i)(?:^|,\s*)\s*with\s+([a-zA-Z0-9._-]+)\s*,?`)
 	multiSpace  = regexp.MustCompile(`\s{2,}`)
-	flagRe      = regexp.MustCompile(`(?i)--(?:no-review|no-plan|direct)\b`)
+	flagRe      = regexp.MustCompile(`(?i)--(?:no-review|no-plan|no-attach|no-pr|direct)\b`)
+	aliasRe     = regexp.MustCompile(`(?:^|\s)@([a-zA-Z0-9][a-zA-Z0-9._-]*)`)
 )
 
 // Parse extracts structured fields from a message that has already been
@@ -60,6 +85,16 @@ var (
 // Returns nil if the remaining message is empty after stripping the mention
 // (i.e., the user just typed "@cursor" with no prompt).
 func Parse(message string, botMention string) *ParsedMention {
+	return ParseWithAliases(message, botMention, nil)
+}
+
+// ParseWithAliases is Parse with "@name" launch shortcuts expanded. aliases
+// maps lowercase alias names to option strings such as
+// "repo=org/web branch=develop model=claude-sonnet". Options written in the
+// message itself take precedence over alias options, and when several aliases
+// set the same option the first one wins. Unknown "@name" tokens are left in
+// the prompt untouched.
+func ParseWithAliases(message string, botMention string, aliases map[string]string) *ParsedMention {
 	// Step 1: Trim whitespace from message.
 	message = strings.TrimSpace(message)
 
@@ -76,6 +111,10 @@ func Parse(message string, botMention string) *ParsedMention {
 	// Step 3: Trim leading whitespace from the remainder.
 	remainder = strings.TrimSpace(remainder)
 
+	// Step 3b: Expand "@alias" shortcuts; their options are merged in step 10.
+	remainder, expanded := expandAliases(remainder, aliases)
+	remainder = strings.TrimSpace(remainder)
+
 	// Step 4: If remainder is empty, return nil.
 	if remainder == "" {
 		return nil
@@ -127,10 +166,110 @@ func Parse(message string, botMention string) *ParsedMention {
 	remainder = multiSpace.ReplaceAllString(remainder, " ")
 	result.Prompt = remainder
 
-	// Step 10: Return the populated ParsedMention.
+	// Step 10: Fill options the message left unset from expanded aliases.
+	for _, alias := range expanded {
+		mergeAliasOptions(result, alias)
+	}
+
+	// Step 11: Return the populated ParsedMention.
 	return result
 }
 
+// AliasReferences returns the lowercased names of "@name" tokens in message,
+// ignoring botMention. Callers use it to skip loading aliases for messages
+// that cannot reference one.
+func AliasReferences(message string, botMention string) []string {
+	mention := strings.ToLower(strings.TrimPrefix(botMention, "@"))
+	var names []string
+	for _, match := range aliasRe.FindAllStringSubmatch(message, -1) {
+		name := strings.ToLower(match[1])
+		if name != mention {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// ParseOptions parses a string made only of options (bracketed block,
+// key=value pairs, and flags), as stored in an alias. It returns the options
+// and any text that was not recognized as an option.
+func ParseOptions(options string) (*ParsedMention, string) {
+	result := &ParsedMention{}
+	remainder := strings.TrimSpace(extractFlags(options, result))
+	if loc := bracketedRe.FindStringSubmatchIndex(remainder); loc != nil {
+		parseBracketedOptions(remainder[loc[2]:loc[3]], result)
+		remainder = strings.TrimSpace(remainder[loc[1]:])
+	}
+	remainder = extractInlineOptions(remainder, result)
+	remainder = multiSpace.ReplaceAllString(strings.TrimSpace(remainder), " ")
+	return result, remainder
+}
+
+// HasOptions reports whether any launch option (everything except Prompt,
+// ForceNew, and FileIDs) is set.
+func (m *ParsedMention) HasOptions() bool {
+	return m.Repository != "" || m.Branch != "" || m.Ref != "" || m.Base != "" || m.Model != "" ||
+		m.AutoPR != nil || m.Draft != nil || m.SkipReview != nil || m.SkipPlan != nil ||
+		m.Direct || m.SkipAttachments
+}
+
+// expandAliases removes "@name" tokens that match an alias from remainder and
+// returns the parsed options of each, in message order.
+func expandAliases(remainder string, aliases map[string]string) (string, []*ParsedMention) {
+	if len(aliases) == 0 {
+		return remainder, nil
+	}
+
+	matches := aliasRe.FindAllStringSubmatchIndex(remainder, -1)
+	var expanded []*ParsedMention
+	// Process in reverse order to maintain correct indices when removing.
+	for i := len(matches) - 1; i >= 0; i-- {
+		loc := matches[i]
+		options, ok := aliases[strings.ToLower(remainder[loc[2]:loc[3]])]
+		if !ok {
+			continue
+		}
+		parsed, _ := ParseOptions(options)
+		expanded = append([]*ParsedMention{parsed}, expanded...)
+		remainder = remainder[:loc[0]] + " " + remainder[loc[1]:]
+	}
+	return remainder, expanded
+}
+
+// mergeAliasOptions copies options from alias into result where result has
+// none of its own.
+func mergeAliasOptions(result, alias *ParsedMention) {
+	if result.Repository == "" {
+		result.Repository = alias.Repository
+	}
+	if result.Branch == "" {
+		result.Branch = alias.Branch
+	}
+	if result.Ref == "" {
+		result.Ref = alias.Ref
+	}
+	if result.Base == "" {
+		result.Base = alias.Base
+	}
+	if result.Model == "" {
+		result.Model = alias.Model
+	}
+	if result.AutoPR == nil {
+		result.AutoPR = alias.AutoPR
+	}
+	if result.Draft == nil {
+		result.Draft = alias.Draft
+	}
+	if result.SkipReview == nil {
+		result.SkipReview = alias.SkipReview
+	}
+	if result.SkipPlan == nil {
+		result.SkipPlan = alias.SkipPlan
+	}
+	result.Direct = result.Direct || alias.Direct
+	result.SkipAttachments = result.SkipAttachments || alias.SkipAttachments
+}
+
 // parseBracketedOptions parses comma-separated key=value pairs inside brackets.
 func parseBracketedOptions(content string, result *ParsedMention) {
 	for pair := range strings.SplitSeq(content, ",") {
@@ -160,8 +299,8 @@ func extractInlineOptions(remainder string, result *ParsedMention) string {
 	return remainder
 }
 
-// extractFlags extracts --no-review, --no-plan, and --direct flags from the
-// remainder and returns the remainder with those flags removed.
+// extractFlags extracts --no-review, --no-plan, --no-attach, --no-pr, and --direct flags
+// from the remainder and returns the remainder with those flags removed.
 func extractFlags(remainder string, result *ParsedMention) string {
 	matches := flagRe.FindAllStringIndex(remainder, -1)
 	// Process in reverse order to maintain correct indices when removing.
@@ -175,6 +314,11 @@ func extractFlags(remainder string, result *ParsedMention) string {
 		case "--no-plan":
 			b := true
 			result.SkipPlan = &b
+		case "--no-attach":
+			result.SkipAttachments = true
+		case "--no-pr":
+			b := false
+			result.AutoPR = &b
 		case "--direct":
 			result.Direct = true
 		}
@@ -190,11 +334,18 @@ func applyOption(key, value string, result *ParsedMention) {
 		result.Repository = value
 	case "branch":
 		result.Branch = value
+	case "ref":
+		result.Ref = value
+	case "base":
+		result.Base = value
 	case "model":
 		result.Model = value
 	case "autopr":
 		b := strings.EqualFold(value, "true")
 		result.AutoPR = &b
+	case "draft":
+		b := strings.EqualFold(value, "true")
+		result.Draft = &b
 	case "review":
 		if strings.EqualFold(value, "off") || strings.EqualFold(value, "false") {
 			b := true
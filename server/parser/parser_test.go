@@ -112,6 +112,30 @@ func TestParse(t *testing.T) {
 			botMention: "@cursor",
 			expected:   &ParsedMention{Prompt: "Fix it", Repository: "org/repo", Branch: "dev", Model: "o3"},
 		},
+		{
+			name:       "inline base",
+			message:    "@cursor base=release-1.2 backport the fix",
+			botMention: "@cursor",
+			expected:   &ParsedMention{Prompt: "backport the fix", Base: "release-1.2"},
+		},
+		{
+			name:       "inline branch and base",
+			message:    "@cursor branch=main base=release/2.0 Fix it",
+			botMention: "@cursor",
+			expected:   &ParsedMention{Prompt: "Fix it", Branch: "main", Base: "release/2.0"},
+		},
+		{
+			name:       "inline ref commit",
+			message:    "@cursor ref=4f83a92c reproduce the crash",
+			botMention: "@cursor",
+			expected:   &ParsedMention{Prompt: "reproduce the crash", Ref: "4f83a92c"},
+		},
+		{
+			name:       "inline ref and branch are distinct",
+			message:    "@cursor branch=main ref=v1.4.2 reproduce the crash",
+			botMention: "@cursor",
+			expected:   &ParsedMention{Prompt: "reproduce the crash", Branch: "main", Ref: "v1.4.2"},
+		},
 
 		// --- Bracketed options ---
 		{
@@ -121,6 +145,19 @@ func TestParse(t *testing.T) {
 			expected:   &ParsedMention{Prompt: "Fix the bug", Repository: "org/repo", Branch: "dev", Model: "o3"},
 		},
 
+		{
+			name:       "bracketed base",
+			message:    "@cursor [repo=org/repo, base=release-1.2] Fix the bug",
+			botMention: "@cursor",
+			expected:   &ParsedMention{Prompt: "Fix the bug", Repository: "org/repo", Base: "release-1.2"},
+		},
+		{
+			name:       "bracketed ref and branch",
+			message:    "@cursor [branch=dev, ref=v2.0.0] Fix the bug",
+			botMention: "@cursor",
+			expected:   &ParsedMention{Prompt: "Fix the bug", Branch: "dev", Ref: "v2.0.0"},
+		},
+
 		// --- Force new agent ---
 		{
 			name:       "force new agent",
@@ -220,6 +257,42 @@ func TestParse(t *testing.T) {
 			botMention: "@cursor",
 			expected:   &ParsedMention{Prompt: "fix the login bug", Direct: true},
 		},
+		{
+			name:       "no-attach flag",
+			message:    "@cursor --no-attach fix the login bug",
+			botMention: "@cursor",
+			expected:   &ParsedMention{Prompt: "fix the login bug", SkipAttachments: true},
+		},
+		{
+			name:       "no-pr flag",
+			message:    "@cursor --no-pr fix the login bug",
+			botMention: "@cursor",
+			expected:   &ParsedMention{Prompt: "fix the login bug", AutoPR: boolPtr(false)},
+		},
+		{
+			name:       "no-pr flag mid-message with options",
+			message:    "@cursor repo=org/repo fix the --no-pr login bug",
+			botMention: "@cursor",
+			expected:   &ParsedMention{Prompt: "fix the login bug", Repository: "org/repo", AutoPR: boolPtr(false)},
+		},
+		{
+			name:       "autopr inline overrides no-pr flag",
+			message:    "@cursor --no-pr autopr=true fix it",
+			botMention: "@cursor",
+			expected:   &ParsedMention{Prompt: "fix it", AutoPR: boolPtr(true)},
+		},
+		{
+			name:       "draft true",
+			message:    "@cursor draft=true explore a new caching layer",
+			botMention: "@cursor",
+			expected:   &ParsedMention{Prompt: "explore a new caching layer", Draft: boolPtr(true)},
+		},
+		{
+			name:       "draft false bracketed",
+			message:    "@cursor [repo=org/repo, draft=false] fix it",
+			botMention: "@cursor",
+			expected:   &ParsedMention{Prompt: "fix it", Repository: "org/repo", Draft: boolPtr(false)},
+		},
 		{
 			name:       "review=off inline",
 			message:    "@cursor review=off fix the bug",
@@ -337,6 +410,7 @@ func TestParse(t *testing.T) {
 				assert.Equal(t, tt.expected.Prompt, result.Prompt)
 				assert.Equal(t, tt.expected.Repository, result.Repository)
 				assert.Equal(t, tt.expected.Branch, result.Branch)
+				assert.Equal(t, tt.expected.Base, result.Base)
 				assert.Equal(t, tt.expected.Model, result.Model)
 				assert.Equal(t, tt.expected.ForceNew, result.ForceNew)
 				if tt.expected.AutoPR == nil {
@@ -371,3 +445,125 @@ func TestParse(t *testing.T) {
 		})
 	}
 }
+
+func TestParseWithAliases(t *testing.T) {
+	aliases := map[string]string{
+		"frontend": "repo=org/web branch=develop model=claude-sonnet",
+		"quick":    "[model=o3] --direct",
+		"backend":  "repo=org/api branch=main",
+	}
+
+	tests := []struct {
+		name     string
+		message  string
+		expected *ParsedMention
+	}{
+		{
+			name:    "alias expands to its options",
+			message: "@cursor @frontend fix the nav bar",
+			expected: &ParsedMention{
+				Prompt:     "fix the nav bar",
+				Repository: "org/web",
+				Branch:     "develop",
+				Model:      "claude-sonnet",
+			},
+		},
+		{
+			name:    "alias name is case-insensitive and may appear mid-prompt",
+			message: "@cursor fix the nav bar @Frontend please",
+			expected: &ParsedMention{
+				Prompt:     "fix the nav bar please",
+				Repository: "org/web",
+				Branch:     "develop",
+				Model:      "claude-sonnet",
+			},
+		},
+		{
+			name:    "inline option overrides alias",
+			message: "@cursor @frontend branch=hotfix fix the nav bar",
+			expected: &ParsedMention{
+				Prompt:     "fix the nav bar",
+				Repository: "org/web",
+				Branch:     "hotfix",
+				Model:      "claude-sonnet",
+			},
+		},
+		{
+			name:    "bracketed and natural language options override alias",
+			message: "@cursor @frontend [model=opus] in org/other, fix the nav bar",
+			expected: &ParsedMention{
+				Prompt:     "fix the nav bar",
+				Repository: "org/other",
+				Branch:     "develop",
+				Model:      "opus",
+			},
+		},
+		{
+			name:    "first alias wins when two set the same option",
+			message: "@cursor @backend @frontend fix it",
+			expected: &ParsedMention{
+				Prompt:     "fix it",
+				Repository: "org/api",
+				Branch:     "main",
+				Model:      "claude-sonnet",
+			},
+		},
+		{
+			name:    "alias with bracketed options and flags",
+			message: "@cursor @quick fix it",
+			expected: &ParsedMention{
+				Prompt: "fix it",
+				Model:  "o3",
+				Direct: true,
+			},
+		},
+		{
+			name:     "unknown alias stays in the prompt",
+			message:  "@cursor ask @alice about the nav bar",
+			expected: &ParsedMention{Prompt: "ask @alice about the nav bar"},
+		},
+		{
+			name:     "alias alone is not a prompt",
+			message:  "@cursor @frontend",
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ParseWithAliases(tt.message, "@cursor", aliases)
+			if tt.expected == nil {
+				assert.Nil(t, result)
+				return
+			}
+			if assert.NotNil(t, result) {
+				assert.Equal(t, tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestAliasReferences(t *testing.T) {
+	assert.Equal(t, []string{"frontend", "alice"}, AliasReferences("@cursor @Frontend ask @alice", "@cursor"))
+	assert.Empty(t, AliasReferences("@cursor fix user@example.com handling", "@cursor"))
+}
+
+func TestParseOptions(t *testing.T) {
+	result, rest := ParseOptions("repo=org/web branch=develop --no-plan")
+	assert.Equal(t, "org/web", result.Repository)
+	assert.Equal(t, "develop", result.Branch)
+	assert.Equal(t, boolPtr(true), result.SkipPlan)
+	assert.Empty(t, rest)
+
+	result, _ = ParseOptions("base=release-1.2")
+	assert.Equal(t, "release-1.2", result.Base)
+	assert.True(t, result.HasOptions())
+
+	result, _ = ParseOptions("ref=v1.4.2")
+	assert.Equal(t, "v1.4.2", result.Ref)
+	assert.Empty(t, result.Branch)
+	assert.True(t, result.HasOptions())
+
+	_, rest = ParseOptions("repo=org/web fix things")
+	assert.Equal(t, "fix things", rest)
+}
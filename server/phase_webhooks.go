@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+const (
+	// phaseWebhookTimeout bounds a single phase webhook delivery attempt.
+	phaseWebhookTimeout = 10 * time.Second
+
+	// phaseWebhookMaxRetries is how many times a failed delivery is retried.
+	phaseWebhookMaxRetries = 3
+
+	phaseWebhookEventHeader     = "X-Cursor-Plugin-Event"
+	phaseWebhookDeliveryHeader  = "X-Cursor-Plugin-Delivery"
+	phaseWebhookSignatureHeader = "X-Cursor-Plugin-Signature-256"
+)
+
+// phaseWebhookRetryBaseDelay is the delay before the first retry; each later
+// retry doubles it. It is a variable so tests can shorten it.
+var phaseWebhookRetryBaseDelay = time.Second
+
+// phaseWebhookPayload is the JSON body POSTed to phase webhook URLs. Data is
+// the same payload published to the webapp over the websocket.
+type phaseWebhookPayload struct {
+	Event      string         `json:"event"`
+	DeliveryID string         `json:"delivery_id"`
+	Timestamp  int64          `json:"timestamp"`
+	Data       map[string]any `json:"data"`
+}
+
+// parsePhaseWebhookURLs parses PhaseWebhookURLs: one http(s) URL per line,
+// blank lines ignored.
+func parsePhaseWebhookURLs(raw string) ([]string, error) {
+	var urls []string
+	for i, line := range strings.Split(raw, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		u, err := url.Parse(line)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return nil, fmt.Errorf("phase webhook URL on line %d must be an http or https URL", i+1)
+		}
+		urls = append(urls, line)
+	}
+	return urls, nil
+}
+
+// signPhaseWebhookBody returns the signature header value for body, in the
+// same "sha256=<hex>" form GitHub uses for its webhooks.
+func signPhaseWebhookBody(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// notifyPhaseWebhooks sends a review loop or workflow phase change to every
+// configured phase webhook URL. Delivery happens in the background and
+// failures are only logged, so the loop or workflow never waits on or fails
+// because of an integrator's endpoint.
+func (p *Plugin) notifyPhaseWebhooks(event string, data map[string]any) {
+	config := p.getConfiguration()
+	urls := config.GetPhaseWebhookURLs()
+	if len(urls) == 0 {
+		return
+	}
+
+	payload := phaseWebhookPayload{
+		Event:      event,
+		DeliveryID: model.NewId(),
+		Timestamp:  p.now().UnixMilli(),
+		Data:       data,
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		p.API.LogWarn("Failed to marshal phase webhook payload", "event", event, "error", err.Error())
+		return
+	}
+
+	secret := []byte(config.PhaseWebhookSecret)
+	for _, webhookURL := range urls {
+		go p.deliverPhaseWebhook(webhookURL, payload, body, secret)
+	}
+}
+
+// deliverPhaseWebhook posts body to webhookURL, retrying failed attempts with
+// exponential backoff, and logs the outcome.
+func (p *Plugin) deliverPhaseWebhook(webhookURL string, payload phaseWebhookPayload, body, secret []byte) {
+	var lastErr error
+	for attempt := 0; attempt <= phaseWebhookMaxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(phaseWebhookRetryBaseDelay * time.Duration(1<<(attempt-1)))
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), phaseWebhookTimeout)
+		lastErr = postPhaseWebhook(ctx, http.DefaultClient, webhookURL, payload, body, secret)
+		cancel()
+		if lastErr == nil {
+			p.logDebug("Delivered phase webhook",
+				"event", payload.Event,
+				"delivery_id", payload.DeliveryID,
+				"attempts", attempt+1,
+			)
+			return
+		}
+		p.logDebug("Phase webhook delivery attempt failed",
+			"event", payload.Event,
+			"delivery_id", payload.DeliveryID,
+			"attempt", attempt+1,
+			"error", lastErr.Error(),
+		)
+	}
+
+	p.API.LogWarn("Failed to deliver phase webhook",
+		"event", payload.Event,
+		"delivery_id", payload.DeliveryID,
+		"attempts", phaseWebhookMaxRetries+1,
+		"error", lastErr.Error(),
+	)
+}
+
+// postPhaseWebhook sends one signed delivery attempt. The signature header is
+// omitted when no secret is configured.
+func postPhaseWebhook(ctx context.Context, client *http.Client, webhookURL string, payload phaseWebhookPayload, body, secret []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build phase webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(phaseWebhookEventHeader, payload.Event)
+	req.Header.Set(phaseWebhookDeliveryHeader, payload.DeliveryID)
+	if len(secret) > 0 {
+		req.Header.Set(phaseWebhookSignatureHeader, signPhaseWebhookBody(secret, body))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("phase webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("phase webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
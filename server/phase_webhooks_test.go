@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+// phaseWebhookDelivery is one request received by a test phase webhook.
+type phaseWebhookDelivery struct {
+	header http.Header
+	body   []byte
+}
+
+// shortenPhaseWebhookRetries makes retries near-instant for the test.
+func shortenPhaseWebhookRetries(t *testing.T) {
+	t.Helper()
+	original := phaseWebhookRetryBaseDelay
+	phaseWebhookRetryBaseDelay = time.Millisecond
+	t.Cleanup(func() { phaseWebhookRetryBaseDelay = original })
+}
+
+func TestPublishReviewLoopChange_SendsSignedPhaseWebhook(t *testing.T) {
+	p, api, _, _ := setupReviewLoopTestPlugin(t)
+
+	delivered := make(chan phaseWebhookDelivery, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		delivered <- phaseWebhookDelivery{header: r.Header.Clone(), body: body}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	p.configuration.PhaseWebhookURLs = server.URL
+	p.configuration.PhaseWebhookSecret = "hook-secret"
+
+	p.publishReviewLoopChange(&kvstore.ReviewLoop{
+		ID:            "loop-1",
+		AgentRecordID: "agent-1",
+		UserID:        "user-1",
+		Phase:         kvstore.ReviewPhaseApproved,
+		Iteration:     2,
+		PRURL:         "https://github.com/org/repo/pull/42",
+		UpdatedAt:     1700000000000,
+	})
+
+	var delivery phaseWebhookDelivery
+	select {
+	case delivery = <-delivered:
+	case <-time.After(5 * time.Second):
+		t.Fatal("phase webhook was not delivered")
+	}
+
+	api.AssertCalled(t, "PublishWebSocketEvent", "review_loop_changed", mock.Anything, mock.Anything)
+	assert.Equal(t, "application/json", delivery.header.Get("Content-Type"))
+	assert.Equal(t, "review_loop_changed", delivery.header.Get(phaseWebhookEventHeader))
+	assert.NotEmpty(t, delivery.header.Get(phaseWebhookDeliveryHeader))
+	assert.True(t, verifyWebhookSignature([]byte("hook-secret"), delivery.header.Get(phaseWebhookSignatureHeader), delivery.body))
+
+	var payload phaseWebhookPayload
+	require.NoError(t, json.Unmarshal(delivery.body, &payload))
+	assert.Equal(t, "review_loop_changed", payload.Event)
+	assert.Equal(t, delivery.header.Get(phaseWebhookDeliveryHeader), payload.DeliveryID)
+	assert.Equal(t, map[string]any{
+		"review_loop_id":  "loop-1",
+		"agent_record_id": "agent-1",
+		"phase":           kvstore.ReviewPhaseApproved,
+		"iteration":       "2",
+		"pr_url":          "https://github.com/org/repo/pull/42",
+		"updated_at":      "1700000000000",
+	}, payload.Data)
+}
+
+func TestPublishWorkflowPhaseChange_DeliveryFailureIsRetriedAndDoesNotAffectWorkflow(t *testing.T) {
+	shortenPhaseWebhookRetries(t)
+	p, api, _, _ := setupTestPlugin(t)
+
+	var attempts atomic.Int32
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if attempts.Add(1) == phaseWebhookMaxRetries+1 {
+			close(done)
+		}
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+	p.configuration.PhaseWebhookURLs = server.URL
+
+	api.On("PublishWebSocketEvent", "workflow_phase_change", mock.Anything, mock.Anything).Return().Once()
+
+	workflow := &kvstore.HITLWorkflow{
+		ID:     "wf-1",
+		UserID: "user-1",
+		Phase:  kvstore.PhasePlanReview,
+	}
+	p.publishWorkflowPhaseChange(workflow)
+
+	api.AssertExpectations(t)
+	assert.Equal(t, kvstore.PhasePlanReview, workflow.Phase)
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected %d delivery attempts, got %d", phaseWebhookMaxRetries+1, attempts.Load())
+	}
+}
+
+func TestPublishReviewLoopChange_NoPhaseWebhooksConfigured(t *testing.T) {
+	p, api, _, _ := setupReviewLoopTestPlugin(t)
+
+	p.publishReviewLoopChange(&kvstore.ReviewLoop{ID: "loop-1", UserID: "user-1"})
+
+	api.AssertCalled(t, "PublishWebSocketEvent", "review_loop_changed", mock.Anything, mock.Anything)
+}
+
+func TestConfigurationIsValid_PhaseWebhookURLs(t *testing.T) {
+	cfg := &configuration{CursorAPIKey: "key", PollIntervalSeconds: 30}
+
+	cfg.PhaseWebhookURLs = "https://hooks.example.com/cursor\n\nhttp://automation.internal/phase"
+	assert.NoError(t, cfg.IsValid())
+	assert.Equal(t, []string{"https://hooks.example.com/cursor", "http://automation.internal/phase"}, cfg.GetPhaseWebhookURLs())
+
+	cfg.PhaseWebhookURLs = "https://hooks.example.com/cursor\nftp://example.com/hook"
+	err := cfg.IsValid()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "line 2")
+	assert.Empty(t, cfg.GetPhaseWebhookURLs())
+}
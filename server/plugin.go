@@ -5,6 +5,7 @@ import (
 	"io"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -43,6 +44,9 @@ type Plugin struct {
 	// botUsername is the username of the bot (e.g., "cursor"), used for mention detection.
 	botUsername string
 
+	// botIdentities maps the username of each additional bot identity to its user ID.
+	botIdentities map[string]string
+
 	// backgroundJob is the scheduled background poller for agent statuses.
 	backgroundJob io.Closer
 
@@ -60,6 +64,35 @@ type Plugin struct {
 
 	// configuration is the active plugin configuration.
 	configuration *configuration
+
+	// terminalReactionsLock guards pendingTerminalReactions.
+	terminalReactionsLock sync.Mutex
+
+	// pendingTerminalReactions holds debounced terminal reaction swaps keyed by trigger post ID.
+	pendingTerminalReactions map[string]*pendingTerminalReaction
+
+	// workflowLocks holds a *sync.Mutex per HITL workflow ID, serializing
+	// button clicks on the same workflow.
+	workflowLocks sync.Map
+
+	// reviewLoopLocks is a fixed set of mutexes that review loop IDs hash
+	// onto, serializing review webhooks for the same loop without keeping a
+	// lock per loop for the life of the process.
+	reviewLoopLocks [reviewLoopLockStripes]sync.Mutex
+
+	// reviewLoopsReconciled is set once the poller has caught review loops up
+	// on webhooks missed while the plugin was down.
+	reviewLoopsReconciled atomic.Bool
+
+	// stopping is closed when the plugin is deactivated, so long-running
+	// background passes can exit early.
+	stopping chan struct{}
+
+	// kvHealth tracks KV store errors and whether the plugin is degraded.
+	kvHealth kvHealth
+
+	// clock is the source of the current time. Nil means the system clock.
+	clock Clock
 }
 
 // logDebug logs a debug message only when EnableDebugLogging is true.
@@ -134,12 +167,50 @@ func (p *Plugin) setBotUsername(username string) {
 	p.botUsername = username
 }
 
+// getBotIdentities returns the additional bot identities under read lock.
+func (p *Plugin) getBotIdentities() map[string]string {
+	p.configurationLock.RLock()
+	defer p.configurationLock.RUnlock()
+	return p.botIdentities
+}
+
+// setBotIdentities sets the additional bot identities under write lock.
+func (p *Plugin) setBotIdentities(identities map[string]string) {
+	p.configurationLock.Lock()
+	defer p.configurationLock.Unlock()
+	p.botIdentities = identities
+}
+
 const (
 	botUsername    = "cursor"
 	botDisplayName = "Cursor"
 	botDescription = "Cursor Background Agents bot for launching and managing AI coding agents."
+
+	// GitHub circuit breaker: open after this many consecutive failures and
+	// let a trial request through once the cooldown has elapsed.
+	githubBreakerFailureThreshold = 5
+	githubBreakerCooldown         = time.Minute
+
+	// How long a Cursor agent conversation is served from cache. Plan polling
+	// and status commands read the same conversation in quick succession.
+	cursorConversationCacheTTL = 10 * time.Second
 )
 
+// newCursorClient builds the Cursor API client for the given key, wrapped in
+// a short-lived conversation cache.
+func (p *Plugin) newCursorClient(apiKey string) cursor.Client {
+	return cursor.NewConversationCache(
+		cursor.NewClient(apiKey, cursor.WithLogger(&pluginLogger{plugin: p})),
+		cursorConversationCacheTTL,
+	)
+}
+
+// newGitHubClient builds the GitHub client for the given PAT, wrapped in a
+// circuit breaker. Returns nil if pat is empty.
+func newGitHubClient(pat string) ghclient.Client {
+	return ghclient.NewCircuitBreaker(ghclient.NewClient(pat), githubBreakerFailureThreshold, githubBreakerCooldown)
+}
+
 // OnActivate is invoked when the plugin is activated.
 func (p *Plugin) OnActivate() error {
 	p.client = pluginapi.NewClient(p.API, p.Driver)
@@ -162,6 +233,9 @@ func (p *Plugin) OnActivate() error {
 	}
 	p.setBotUsername(botUser.Username)
 
+	// Ensure any additional bot identities exist.
+	p.ensureBotIdentities()
+
 	// Initialize the KV store.
 	p.kvstore = kvstore.NewKVStore(p.client)
 
@@ -171,14 +245,17 @@ func (p *Plugin) OnActivate() error {
 	// Initialize the Cursor API client (may be nil if API key not configured yet).
 	cfg := p.getConfiguration()
 	if cfg.CursorAPIKey != "" {
-		p.setCursorClient(cursor.NewClient(cfg.CursorAPIKey, cursor.WithLogger(&pluginLogger{plugin: p})))
+		p.setCursorClient(p.newCursorClient(cfg.CursorAPIKey))
 	}
 
 	// Initialize the GitHub client (may be nil if PAT not configured yet).
 	if cfg.GitHubPAT != "" {
-		p.setGitHubClient(ghclient.NewClient(cfg.GitHubPAT))
+		p.setGitHubClient(newGitHubClient(cfg.GitHubPAT))
 	}
 
+	// Surface settings that enabled features need but are missing.
+	p.checkRequiredSettings()
+
 	// Set up the HTTP router.
 	p.router = p.initRouter()
 
@@ -194,11 +271,28 @@ func (p *Plugin) OnActivate() error {
 		CursorClientFn: p.getCursorClient,
 		Store:          p.kvstore,
 		BotUserID:      botUserID,
+		BotUserIDFn:    p.botUserIDForChannel,
+		BotUsernamesFn: p.botIdentityUsernames,
 		SiteURL:        siteURL,
 		PluginID:       "com.mattermost.plugin-cursor",
+
+		CanLaunchFn:            p.canLaunchInChannel,
+		CanManageLaunchersFn:   p.canManageChannelLaunchers,
+		ChannelLinkedRepoFn:    p.channelLinkedRepositoryFallback,
+		BaseBranchMissingFn:    p.baseBranchMissing,
+		AllowedModelsFn:        p.allowedModels,
+		SetReviewLoopsPausedFn: p.setReviewLoopsGloballyPaused,
+		OwnershipTransferredFn: p.publishOwnershipTransfer,
+		DecorateBotPostFn:      p.decorateBotPost,
+		DispatchReviewLoopFn:   p.dispatchReviewLoopNow,
+		IntegrationStatusFn:    p.integrationStatus,
+		LinkPullRequestFn:      p.linkPullRequest,
+		NowFn:                  p.now,
 	})
 
-	// Schedule background poller for agent status updates.
+	// Schedule background poller for agent status updates. Its first run also
+	// catches review loops up on webhooks missed while the plugin was down.
+	p.stopping = make(chan struct{})
 	pollInterval := time.Duration(cfg.GetPollInterval()) * time.Second
 	job, cronErr := cluster.Schedule(
 		p.API,
@@ -214,8 +308,23 @@ func (p *Plugin) OnActivate() error {
 	return nil
 }
 
+// integrationStatus reports which GitHub integrations are configured, for
+// /cursor whoami.
+func (p *Plugin) integrationStatus() command.IntegrationStatus {
+	config := p.getConfiguration()
+	return command.IntegrationStatus{
+		GitHubPATConfigured:     config.GitHubPAT != "",
+		WebhookSecretConfigured: config.GitHubWebhookSecret != "",
+		ReviewLoopEnabled:       config.EnableAIReviewLoop,
+	}
+}
+
 // OnDeactivate is invoked when the plugin is deactivated.
 func (p *Plugin) OnDeactivate() error {
+	if p.stopping != nil {
+		close(p.stopping)
+	}
+	p.flushTerminalReactions()
 	if p.backgroundJob != nil {
 		if err := p.backgroundJob.Close(); err != nil {
 			p.API.LogError("Failed to close background job", "error", err.Error())
@@ -145,6 +145,23 @@ func TestConfigurationIsValid(t *testing.T) {
 				PollIntervalSeconds: 30,
 			},
 		},
+		{
+			name: "repo URL with .git suffix",
+			cfg: configuration{
+				CursorAPIKey:        "cur_abc123",
+				DefaultRepository:   "https://github.com/mattermost/mattermost.git",
+				PollIntervalSeconds: 30,
+			},
+		},
+		{
+			name: "invalid repo name characters",
+			cfg: configuration{
+				CursorAPIKey:        "cur_abc123",
+				DefaultRepository:   "owner/re po",
+				PollIntervalSeconds: 30,
+			},
+			wantErr: "owner and repository names may only contain",
+		},
 		{
 			name: "empty repo is allowed",
 			cfg: configuration{
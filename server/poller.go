@@ -20,13 +20,40 @@ func (p *Plugin) pollAgentStatuses() {
 	activeAgents, err := p.kvstore.ListActiveAgents()
 	if err != nil {
 		p.API.LogError("Failed to list active agents", "error", err.Error())
+		p.recordKVError("ListActiveAgents", err)
 		return
 	}
+	p.recordKVSuccess()
 
 	cleaned := p.cleanupStaleAgents(activeAgents, staleAgentMaxAge)
 	if cleaned > 0 {
 		p.API.LogInfo("Cleaned up stale agents", "count", cleaned, "max_age", staleAgentMaxAge.String())
 	}
+	p.cleanupExpiredWorkflows()
+
+	// Catch review loops up on webhooks missed while the plugin was down (once
+	// per activation), stall review loops past their maximum lifetime, release
+	// review loop work held by a global pause, settle dispatches interrupted
+	// by a restart, release work held during quiet hours, a GitHub outage, a
+	// Cursor rate limit or an idempotency window, nudge idle AI reviewers,
+	// escalate loops stuck waiting on reviewers, re-dispatch fixes that never
+	// reached the PR, merge approved PRs whose CI has since passed, and post
+	// the daily digest. Loops outlive their agents, so this runs even when no
+	// agents are active.
+	p.reconcileReviewLoops()
+	p.stallExpiredReviewLoops()
+	p.replayGloballyPausedReviews()
+	p.reconcileInterruptedDispatches()
+	p.releaseQuietHoursDeferrals()
+	p.retryGitHubDeferredDispatches()
+	p.retryRateLimitedDispatches()
+	p.releaseCoalescedDispatches()
+	p.nudgeIdleReviewers()
+	p.escalateStaleReviewLoops()
+	p.checkCursorFixingPushes()
+	p.checkReviewLoopMergeConflicts()
+	p.retryHeldAutoMerges()
+	p.postScheduledReviewLoopDigest()
 
 	if len(activeAgents) == 0 {
 		return
@@ -50,7 +77,7 @@ func (p *Plugin) pollAgentStatuses() {
 // Called at the end of each poll cycle.
 func (p *Plugin) janitorSweep() {
 	config := p.getConfiguration()
-	if !config.EnableAIReviewLoop || p.getGitHubClient() == nil {
+	if !config.EnableAIReviewLoop || p.reviewLoopsGloballyPaused() || p.getGitHubClient() == nil {
 		return
 	}
 
@@ -63,25 +90,27 @@ func (p *Plugin) janitorSweep() {
 	}
 
 	for _, agent := range agents {
-		if agent.PrURL == "" {
-			continue
-		}
-
-		existing, _ := p.kvstore.GetReviewLoopByPRURL(agent.PrURL)
-		if existing != nil {
-			continue // Loop already exists; nothing to reconcile.
-		}
-
-		p.API.LogInfo("Janitor: bootstrapping missing review loop",
-			"agent_id", agent.CursorAgentID,
-			"pr_url", agent.PrURL,
-		)
+		for _, prURL := range p.reviewLoopPRURLs(agent) {
+			existing, _ := p.kvstore.GetReviewLoopByPRURL(prURL)
+			if existing != nil {
+				continue // Loop already exists; nothing to reconcile.
+			}
+			if !p.reviewLoopAllowedForPR(agent, prURL) {
+				continue // Draft launch still waiting to be marked ready.
+			}
 
-		if err := p.startReviewLoop(agent); err != nil {
-			p.API.LogError("Janitor: failed to start review loop",
-				"error", err.Error(),
+			p.API.LogInfo("Janitor: bootstrapping missing review loop",
 				"agent_id", agent.CursorAgentID,
+				"pr_url", prURL,
 			)
+
+			if err := p.startReviewLoopForPR(agent, prURL); err != nil {
+				p.API.LogError("Janitor: failed to start review loop",
+					"error", err.Error(),
+					"agent_id", agent.CursorAgentID,
+					"pr_url", prURL,
+				)
+			}
 		}
 	}
 }
@@ -125,6 +154,14 @@ func (p *Plugin) pollSingleAgent(record *kvstore.AgentRecord) {
 		return
 	}
 
+	// Mirror the latest agent message and refresh the launch card once this
+	// poll is handled, whether or not the status changed. Terminal agents are
+	// never updated.
+	if agent.Status == cursor.AgentStatusRunning {
+		defer p.mirrorAgentProgress(record)
+		defer p.updateLiveProgress(record)
+	}
+
 	p.logDebug("Polled agent status",
 		"agent_id", record.CursorAgentID,
 		"stored_status", record.Status,
@@ -172,7 +209,7 @@ func (p *Plugin) pollSingleAgent(record *kvstore.AgentRecord) {
 	if agent.Summary != "" {
 		record.Summary = agent.Summary
 	}
-	record.UpdatedAt = time.Now().UnixMilli()
+	record.UpdatedAt = p.now().UnixMilli()
 	if err := p.kvstore.SaveAgent(record); err != nil {
 		p.API.LogError("Failed to update agent record", "agentID", record.CursorAgentID, "error", err.Error())
 	}
@@ -197,8 +234,8 @@ func (p *Plugin) handleAgentRunning(record *kvstore.AgentRecord) {
 
 func (p *Plugin) handleAgentFinished(record *kvstore.AgentRecord, agent *cursor.Agent) {
 	// Step 1: Swap reactions on the TRIGGER post.
-	p.removeReaction(record.TriggerPostID, "hourglass_flowing_sand")
-	p.addReaction(record.TriggerPostID, "white_check_mark")
+	p.removeReaction(record.ChannelID, record.TriggerPostID, "hourglass_flowing_sand")
+	p.addReaction(record.ChannelID, record.TriggerPostID, "white_check_mark")
 
 	// Use the branch name from the API response if available, fall back to the stored target branch.
 	targetBranch := agent.Target.BranchName
@@ -224,11 +261,16 @@ func (p *Plugin) handleAgentFinished(record *kvstore.AgentRecord, agent *cursor.
 	default:
 		msg = "Agent finished but no PR was created. Check the agent output in Cursor for details."
 	}
+	if summary := p.agentFinalSummary(record); summary != "" {
+		msg += "\n\n:memo: **Agent summary**\n\n" + summary
+	}
 	p.postBotReplyToThread(record, msg)
+	p.notifySlackAgentFinished(record, agent.Target.PrURL, agent.Summary)
 
 	// Step 4: Update record with PR URL and actual branch name from Cursor API.
 	if agent.Target.PrURL != "" {
 		record.PrURL = agent.Target.PrURL
+		p.postApprovedPlanToPR(record, record.PrURL)
 	}
 	if agent.Target.BranchName != "" && agent.Target.BranchName != record.TargetBranch {
 		record.TargetBranch = agent.Target.BranchName
@@ -242,8 +284,8 @@ func (p *Plugin) handleAgentFinished(record *kvstore.AgentRecord, agent *cursor.
 
 func (p *Plugin) handleAgentFailed(record *kvstore.AgentRecord, agent *cursor.Agent) {
 	// Step 1: Swap reactions.
-	p.removeReaction(record.TriggerPostID, "hourglass_flowing_sand")
-	p.addReaction(record.TriggerPostID, "x")
+	p.removeReaction(record.ChannelID, record.TriggerPostID, "hourglass_flowing_sand")
+	p.addReaction(record.ChannelID, record.TriggerPostID, "x")
 
 	failedAttachment := attachments.BuildFailedAttachment(
 		record.CursorAgentID, record.Repository, record.Branch, record.Model, agent.Summary,
@@ -258,8 +300,8 @@ func (p *Plugin) handleAgentFailed(record *kvstore.AgentRecord, agent *cursor.Ag
 
 func (p *Plugin) handleAgentStopped(record *kvstore.AgentRecord) {
 	// Step 1: Swap reactions.
-	p.removeReaction(record.TriggerPostID, "hourglass_flowing_sand")
-	p.addReaction(record.TriggerPostID, "no_entry_sign")
+	p.removeReaction(record.ChannelID, record.TriggerPostID, "hourglass_flowing_sand")
+	p.addReaction(record.ChannelID, record.TriggerPostID, "no_entry_sign")
 
 	stoppedAttachment := attachments.BuildStoppedAttachment(
 		record.CursorAgentID, record.Repository, record.Branch, record.Model,
@@ -274,12 +316,12 @@ func (p *Plugin) handleAgentStopped(record *kvstore.AgentRecord) {
 
 // postBotReplyToThread posts a message in the agent's thread.
 func (p *Plugin) postBotReplyToThread(record *kvstore.AgentRecord, message string) {
-	_, appErr := p.API.CreatePost(&model.Post{
-		UserId:    p.getBotUserID(),
+	_, appErr := p.API.CreatePost(p.decorateBotPost(&model.Post{
+		UserId:    p.botUserIDForChannel(record.ChannelID),
 		ChannelId: record.ChannelID,
 		RootId:    record.PostID,
 		Message:   message,
-	})
+	}))
 	if appErr != nil {
 		p.API.LogError("Failed to post bot reply to thread",
 			"agentID", record.CursorAgentID,
@@ -305,7 +347,7 @@ func (p *Plugin) updateBotReplyWithAttachment(botReplyPostID string, attachment
 	}
 	originalPost.Message = ""
 	model.ParseSlackAttachment(originalPost, []*model.SlackAttachment{attachment})
-	if _, appErr := p.API.UpdatePost(originalPost); appErr != nil {
+	if _, appErr := p.API.UpdatePost(p.decorateBotPost(originalPost)); appErr != nil {
 		p.API.LogError("Failed to update bot reply post with attachment",
 			"postID", botReplyPostID,
 			"error", appErr.Error(),
@@ -330,9 +372,8 @@ func (p *Plugin) updateBotPostProps(record *kvstore.AgentRecord) {
 		return
 	}
 
-	botUserID := p.getBotUserID()
 	for _, post := range postList.Posts {
-		if post.UserId != botUserID {
+		if !p.isBotUser(post.UserId) {
 			continue
 		}
 		if agentID, ok := post.GetProp("cursor_agent_id").(string); ok && agentID == record.CursorAgentID {
@@ -347,6 +388,20 @@ func (p *Plugin) updateBotPostProps(record *kvstore.AgentRecord) {
 	}
 }
 
+// publishOwnershipTransfer pushes an agent and its linked workflow and review
+// loop to their new owner after /cursor transfer.
+func (p *Plugin) publishOwnershipTransfer(record *kvstore.AgentRecord) {
+	p.publishAgentStatusChange(record)
+	if workflowID, _ := p.kvstore.GetWorkflowByAgent(record.CursorAgentID); workflowID != "" {
+		if workflow, _ := p.kvstore.GetWorkflow(workflowID); workflow != nil {
+			p.publishWorkflowPhaseChange(workflow)
+		}
+	}
+	if loop, _ := p.kvstore.GetReviewLoopByAgent(record.CursorAgentID); loop != nil {
+		p.publishReviewLoopChange(loop)
+	}
+}
+
 // publishAgentStatusChange publishes a WebSocket event when an agent's status changes.
 func (p *Plugin) publishAgentStatusChange(record *kvstore.AgentRecord) {
 	p.API.PublishWebSocketEvent(
@@ -358,6 +413,7 @@ func (p *Plugin) publishAgentStatusChange(record *kvstore.AgentRecord) {
 			"summary":       record.Summary,
 			"repository":    record.Repository,
 			"target_branch": record.TargetBranch,
+			"base_branch":   record.BaseBranch,
 			"updated_at":    fmt.Sprintf("%d", record.UpdatedAt),
 		},
 		&model.WebsocketBroadcast{UserId: record.UserID},
@@ -387,11 +443,14 @@ func (p *Plugin) handleWorkflowAgentTerminal(record *kvstore.AgentRecord, agent
 	case kvstore.PhaseImplementing:
 		// Implementation agent finished/failed/stopped -- mark workflow complete.
 		workflow.Phase = kvstore.PhaseComplete
-		workflow.UpdatedAt = time.Now().UnixMilli()
+		workflow.UpdatedAt = p.now().UnixMilli()
 		if err := p.kvstore.SaveWorkflow(workflow); err != nil {
 			p.API.LogError("Failed to save workflow in implementing phase", "workflow_id", workflow.ID, "error", err.Error())
 		}
 		p.publishWorkflowPhaseChange(workflow)
+		if agent.Status == cursor.AgentStatusFinished {
+			p.notifyWorkflowOwnerImplementationFinished(workflow, record, agent)
+		}
 		return false // Let normal terminal handling run (PR link, reactions, etc.)
 	}
 
@@ -423,7 +482,7 @@ func (p *Plugin) publishAgentCreated(record *kvstore.AgentRecord) {
 // than maxAge as STOPPED and notifies users via thread messages.
 func (p *Plugin) cleanupStaleAgents(agents []*kvstore.AgentRecord, maxAge time.Duration) int {
 	cleaned := 0
-	now := time.Now()
+	now := p.now()
 
 	for _, agent := range agents {
 		if agent == nil || agent.CreatedAt <= 0 {
@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -60,6 +61,9 @@ func setupPollerPlugin(t *testing.T) (*Plugin, *plugintest.API, *mockCursorClien
 	cursorClient := &mockCursorClient{}
 	store := &mockKVStore{}
 
+	// Progress mirroring is off unless a test turns it on for its channel.
+	store.On("GetChannelSettings", mock.Anything).Return(nil, nil).Maybe()
+
 	p := &Plugin{}
 	p.SetAPI(api)
 	p.client = pluginapi.NewClient(api, nil)
@@ -575,6 +579,82 @@ func TestPoller_ImplementerFinished_NormalHandling(t *testing.T) {
 	api.AssertCalled(t, "AddReaction", mock.Anything)
 }
 
+func TestPoller_ImplementerFinished_NotifiesWorkflowOwner(t *testing.T) {
+	p, api, cursorClient, store := setupPollerPlugin(t)
+
+	record := &kvstore.AgentRecord{
+		CursorAgentID:  "impl-1",
+		Status:         "RUNNING",
+		TriggerPostID:  "trigger-1",
+		PostID:         "root-1",
+		ChannelID:      "ch-1",
+		BotReplyPostID: "bot-reply-1",
+		UserID:         "user-1",
+	}
+
+	store.On("ListActiveAgents").Return([]*kvstore.AgentRecord{record}, nil)
+	store.On("GetAgent", "impl-1").Return(record, nil)
+	cursorClient.On("GetAgent", mock.Anything, "impl-1").Return(&cursor.Agent{
+		ID:     "impl-1",
+		Status: cursor.AgentStatusFinished,
+		Target: cursor.AgentTarget{PrURL: "https://github.com/org/repo/pull/99"},
+	}, nil)
+	store.On("GetWorkflowByAgent", "impl-1").Return("wf-1", nil)
+	store.On("GetWorkflow", "wf-1").Return(&kvstore.HITLWorkflow{
+		ID:                 "wf-1",
+		UserID:             "owner-1",
+		ChannelID:          "ch-1",
+		RootPostID:         "root-1",
+		Repository:         "org/repo",
+		Phase:              kvstore.PhaseImplementing,
+		ImplementerAgentID: "impl-1",
+	}, nil)
+	store.On("SaveWorkflow", mock.Anything).Return(nil)
+	store.On("SaveAgent", mock.Anything).Return(nil)
+
+	api.On("GetDirectChannel", "bot-user-id", "owner-1").Return(&model.Channel{Id: "dm-owner"}, nil).Once()
+	api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		return post.ChannelId == "dm-owner" &&
+			strings.Contains(post.Message, "implementation agent finished on `org/repo`") &&
+			strings.Contains(post.Message, "[View PR](https://github.com/org/repo/pull/99)") &&
+			strings.HasSuffix(post.Message, "[View workflow thread](http://localhost:8065/_redirect/pl/root-1)")
+	})).Return(&model.Post{Id: "dm-1"}, nil).Once()
+	api.On("CreatePost", mock.Anything).Return(&model.Post{Id: "msg-1"}, nil)
+
+	p.pollAgentStatuses()
+
+	api.AssertCalled(t, "GetDirectChannel", "bot-user-id", "owner-1")
+	api.AssertExpectations(t)
+}
+
+func TestPoller_PlainAgentFinished_NoOwnerNotification(t *testing.T) {
+	p, api, cursorClient, store := setupPollerPlugin(t)
+
+	record := &kvstore.AgentRecord{
+		CursorAgentID:  "agent-1",
+		Status:         "RUNNING",
+		TriggerPostID:  "trigger-1",
+		PostID:         "root-1",
+		ChannelID:      "ch-1",
+		BotReplyPostID: "bot-reply-1",
+		UserID:         "user-1",
+	}
+
+	store.On("ListActiveAgents").Return([]*kvstore.AgentRecord{record}, nil)
+	store.On("GetAgent", "agent-1").Return(record, nil)
+	cursorClient.On("GetAgent", mock.Anything, "agent-1").Return(&cursor.Agent{
+		ID:     "agent-1",
+		Status: cursor.AgentStatusFinished,
+	}, nil)
+	store.On("GetWorkflowByAgent", "agent-1").Return("", nil)
+	store.On("SaveAgent", mock.Anything).Return(nil)
+	api.On("CreatePost", mock.Anything).Return(&model.Post{Id: "msg-1"}, nil)
+
+	p.pollAgentStatuses()
+
+	api.AssertNotCalled(t, "GetDirectChannel", mock.Anything, mock.Anything)
+}
+
 func TestPoller_NonWorkflowAgent_NormalHandling(t *testing.T) {
 	p, api, cursorClient, store := setupPollerPlugin(t)
 
@@ -658,6 +738,16 @@ func TestHandleAgentFinished_NoLongerStartsLoop(t *testing.T) {
 	api.On("CreatePost", mock.Anything).Return(&model.Post{Id: "msg-1"}, nil)
 	store.On("SaveAgent", mock.Anything).Return(nil)
 
+	// No review loops are holding work for quiet hours.
+	store.On("ListQuietHoursDeferredReviewLoops").Return([]*kvstore.ReviewLoop{}, nil)
+	store.On("ListGitHubRetryReviewLoops").Return([]*kvstore.ReviewLoop{}, nil)
+	store.On("ListRateLimitedReviewLoops").Return([]*kvstore.ReviewLoop{}, nil)
+	store.On("ListGloballyPausedReviewLoops").Return([]*kvstore.ReviewLoop{}, nil)
+	store.On("ListPendingDispatchReviewLoops").Return([]*kvstore.ReviewLoop{}, nil)
+	store.On("ListActiveReviewLoops").Return([]*kvstore.ReviewLoop{}, nil)
+	store.On("ListFixingReviewLoops").Return([]*kvstore.ReviewLoop{}, nil)
+	store.On("ListWaitingReviewLoops").Return([]*kvstore.ReviewLoop{}, nil)
+
 	// Janitor sweep: returns empty list (no agents pending reconciliation yet).
 	store.On("GetAllFinishedAgentsWithPR").Return([]*kvstore.AgentRecord{}, nil)
 
@@ -752,6 +842,16 @@ func TestPoller_FinishedNoPR_NoReviewLoop(t *testing.T) {
 
 	store.On("SaveAgent", mock.Anything).Return(nil)
 
+	// No review loops are holding work for quiet hours.
+	store.On("ListQuietHoursDeferredReviewLoops").Return([]*kvstore.ReviewLoop{}, nil)
+	store.On("ListGitHubRetryReviewLoops").Return([]*kvstore.ReviewLoop{}, nil)
+	store.On("ListRateLimitedReviewLoops").Return([]*kvstore.ReviewLoop{}, nil)
+	store.On("ListGloballyPausedReviewLoops").Return([]*kvstore.ReviewLoop{}, nil)
+	store.On("ListPendingDispatchReviewLoops").Return([]*kvstore.ReviewLoop{}, nil)
+	store.On("ListActiveReviewLoops").Return([]*kvstore.ReviewLoop{}, nil)
+	store.On("ListFixingReviewLoops").Return([]*kvstore.ReviewLoop{}, nil)
+	store.On("ListWaitingReviewLoops").Return([]*kvstore.ReviewLoop{}, nil)
+
 	// Janitor sweep: returns empty list (no agents with PrURL pending).
 	store.On("GetAllFinishedAgentsWithPR").Return([]*kvstore.AgentRecord{}, nil)
 
@@ -851,3 +951,68 @@ func TestJanitorSweep_NoGitHubClient(t *testing.T) {
 	// Should return immediately without scanning.
 	store.AssertNotCalled(t, "GetAllFinishedAgentsWithPR")
 }
+
+func TestHandleAgentFinished_PostsAgentSummary(t *testing.T) {
+	p, api, cursorClient, _ := setupPollerPlugin(t)
+	p.configuration.PostAgentSummaryOnFinish = true
+
+	record := &kvstore.AgentRecord{
+		CursorAgentID: "agent-1",
+		TriggerPostID: "trigger-1",
+		PostID:        "root-1",
+		ChannelID:     "ch-1",
+	}
+
+	cursorClient.On("GetConversation", mock.Anything, "agent-1").Return(&cursor.Conversation{
+		Messages: []cursor.Message{
+			{Type: "user_message", Text: "fix the login bug"},
+			{Type: "assistant_message", Text: "Looking into it."},
+			{Type: "assistant_message", Text: "<details><summary>Done</summary>Fixed the null check in login.</details>"},
+		},
+	}, nil)
+	api.On("RemoveReaction", mock.Anything).Return(nil)
+	api.On("AddReaction", mock.Anything).Return(nil, nil)
+	api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		return post.RootId == "root-1" &&
+			strings.HasPrefix(post.Message, "Agent finished! [View PR](https://github.com/org/repo/pull/42)") &&
+			strings.Contains(post.Message, ":memo: **Agent summary**\n\n**Done**Fixed the null check in login.") &&
+			!strings.Contains(post.Message, "Looking into it.")
+	})).Return(&model.Post{Id: "msg-1"}, nil)
+
+	p.handleAgentFinished(record, &cursor.Agent{
+		ID:     "agent-1",
+		Status: cursor.AgentStatusFinished,
+		Target: cursor.AgentTarget{PrURL: "https://github.com/org/repo/pull/42"},
+	})
+
+	api.AssertExpectations(t)
+	cursorClient.AssertExpectations(t)
+}
+
+func TestHandleAgentFinished_NoSummaryWhenConversationEmpty(t *testing.T) {
+	p, api, cursorClient, _ := setupPollerPlugin(t)
+	p.configuration.PostAgentSummaryOnFinish = true
+
+	record := &kvstore.AgentRecord{
+		CursorAgentID: "agent-1",
+		TriggerPostID: "trigger-1",
+		PostID:        "root-1",
+		ChannelID:     "ch-1",
+	}
+
+	cursorClient.On("GetConversation", mock.Anything, "agent-1").Return(&cursor.Conversation{}, nil)
+	api.On("RemoveReaction", mock.Anything).Return(nil)
+	api.On("AddReaction", mock.Anything).Return(nil, nil)
+	api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		return post.Message == "Agent finished! [View PR](https://github.com/org/repo/pull/42)"
+	})).Return(&model.Post{Id: "msg-1"}, nil)
+
+	p.handleAgentFinished(record, &cursor.Agent{
+		ID:     "agent-1",
+		Status: cursor.AgentStatusFinished,
+		Target: cursor.AgentTarget{PrURL: "https://github.com/org/repo/pull/42"},
+	})
+
+	api.AssertExpectations(t)
+	cursorClient.AssertExpectations(t)
+}
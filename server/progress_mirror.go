@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/cursor"
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+// maxProgressMirrorLength bounds the mirrored agent message in the thread.
+const maxProgressMirrorLength = 3000
+
+// mirrorAgentProgress posts the running agent's latest assistant message to
+// its thread when the channel has progress mirroring on. A single progress
+// post is created and then edited in place as new messages arrive, so the
+// thread is not flooded on every poll. Failures are logged and never affect
+// status polling.
+func (p *Plugin) mirrorAgentProgress(record *kvstore.AgentRecord) {
+	if record.ChannelID == "" || record.PostID == "" {
+		return
+	}
+	settings, err := p.kvstore.GetChannelSettings(record.ChannelID)
+	if err != nil || settings == nil || !settings.MirrorProgress {
+		return
+	}
+	cursorClient := p.getCursorClient()
+	if cursorClient == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	conv, err := cursorClient.GetConversation(ctx, record.CursorAgentID)
+	if err != nil {
+		p.API.LogWarn("Failed to fetch conversation for progress mirroring",
+			"agent_id", record.CursorAgentID,
+			"error", err.Error(),
+		)
+		return
+	}
+
+	msg := latestAssistantMessage(conv)
+	if msg == nil {
+		return
+	}
+	key := progressMessageKey(msg)
+	if key == record.ProgressMessageID {
+		return // Already showing this message.
+	}
+
+	message := formatAgentProgressMessage(msg.Text)
+	if !p.updateProgressPost(record.ProgressPostID, message) {
+		post := &model.Post{
+			UserId:    p.botUserIDForChannel(record.ChannelID),
+			ChannelId: record.ChannelID,
+			RootId:    record.PostID,
+			Message:   message,
+		}
+		created, appErr := p.API.CreatePost(p.decorateBotPost(post))
+		if appErr != nil {
+			p.API.LogWarn("Failed to post agent progress",
+				"agent_id", record.CursorAgentID,
+				"error", appErr.Error(),
+			)
+			return
+		}
+		record.ProgressPostID = created.Id
+	}
+
+	record.ProgressMessageID = key
+	if err := p.kvstore.SaveAgent(record); err != nil {
+		p.API.LogWarn("Failed to save agent progress mirror state",
+			"agent_id", record.CursorAgentID,
+			"error", err.Error(),
+		)
+	}
+}
+
+// updateProgressPost edits an existing progress post. Returns false when there
+// is no post to edit, e.g. it was never created or has been deleted.
+func (p *Plugin) updateProgressPost(postID, message string) bool {
+	if postID == "" {
+		return false
+	}
+	post, appErr := p.API.GetPost(postID)
+	if appErr != nil || post == nil || post.DeleteAt != 0 {
+		return false
+	}
+	post.Message = message
+	if _, appErr := p.API.UpdatePost(p.decorateBotPost(post)); appErr != nil {
+		p.API.LogWarn("Failed to update agent progress post",
+			"post_id", postID,
+			"error", appErr.Error(),
+		)
+	}
+	return true
+}
+
+// latestAssistantMessage returns the last non-empty assistant message, or nil.
+func latestAssistantMessage(conv *cursor.Conversation) *cursor.Message {
+	if conv == nil {
+		return nil
+	}
+	for i := len(conv.Messages) - 1; i >= 0; i-- {
+		msg := &conv.Messages[i]
+		if msg.Type == "assistant_message" && strings.TrimSpace(msg.Text) != "" {
+			return msg
+		}
+	}
+	return nil
+}
+
+// progressMessageKey identifies a conversation message, falling back to a
+// digest of its text when the API omits message IDs.
+func progressMessageKey(msg *cursor.Message) string {
+	if msg.ID != "" {
+		return msg.ID
+	}
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(msg.Text)))[:16]
+}
+
+func formatAgentProgressMessage(text string) string {
+	return ":speech_balloon: **Agent progress**\n\n" + truncateText(text, maxProgressMirrorLength)
+}
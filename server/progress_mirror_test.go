@@ -0,0 +1,164 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/cursor"
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+// enableProgressMirroring replaces the poller's default channel settings with
+// settings that turn progress mirroring on.
+func enableProgressMirroring(store *mockKVStore, channelID string) {
+	for _, call := range store.ExpectedCalls {
+		if call.Method == "GetChannelSettings" {
+			call.Unset()
+		}
+	}
+	store.On("GetChannelSettings", channelID).Return(&kvstore.ChannelSettings{MirrorProgress: true}, nil)
+}
+
+func newMirrorTestRecord() *kvstore.AgentRecord {
+	return &kvstore.AgentRecord{
+		CursorAgentID:  "agent-1",
+		Status:         "RUNNING",
+		TriggerPostID:  "trigger-1",
+		PostID:         "root-1",
+		ChannelID:      "ch-1",
+		BotReplyPostID: "bot-reply-1",
+		Repository:     "org/repo",
+	}
+}
+
+func progressUpdates(api *plugintest.API) []string {
+	var messages []string
+	for _, call := range api.Calls {
+		if call.Method != "UpdatePost" {
+			continue
+		}
+		if post, ok := call.Arguments.Get(0).(*model.Post); ok && strings.Contains(post.Message, "Agent progress") {
+			messages = append(messages, post.Message)
+		}
+	}
+	return messages
+}
+
+func TestPoller_MirrorsProgressWhileRunning(t *testing.T) {
+	p, api, cursorClient, store := setupPollerPlugin(t)
+	enableProgressMirroring(store, "ch-1")
+
+	record := newMirrorTestRecord()
+	store.On("ListActiveAgents").Return([]*kvstore.AgentRecord{record}, nil)
+	store.On("GetAgent", "agent-1").Return(record, nil)
+	store.On("SaveAgent", mock.Anything).Return(nil)
+	cursorClient.On("GetAgent", mock.Anything, "agent-1").Return(&cursor.Agent{
+		ID:     "agent-1",
+		Status: cursor.AgentStatusRunning,
+	}, nil)
+	cursorClient.On("GetConversation", mock.Anything, "agent-1").Return(&cursor.Conversation{
+		Messages: []cursor.Message{
+			{ID: "m1", Type: "user_message", Text: "Fix the login bug"},
+			{ID: "m2", Type: "assistant_message", Text: "Reading the auth handlers"},
+		},
+	}, nil).Once()
+	api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		return post.RootId == "root-1" && strings.Contains(post.Message, "Reading the auth handlers")
+	})).Return(&model.Post{Id: "progress-1"}, nil).Once()
+
+	p.pollAgentStatuses()
+
+	assert.Equal(t, "progress-1", record.ProgressPostID)
+	assert.Equal(t, "m2", record.ProgressMessageID)
+
+	// A newer message edits the same post instead of posting again.
+	cursorClient.On("GetConversation", mock.Anything, "agent-1").Return(&cursor.Conversation{
+		Messages: []cursor.Message{
+			{ID: "m2", Type: "assistant_message", Text: "Reading the auth handlers"},
+			{ID: "m3", Type: "assistant_message", Text: "Running the test suite"},
+		},
+	}, nil).Twice()
+
+	p.pollAgentStatuses()
+
+	updates := progressUpdates(api)
+	require.Len(t, updates, 1)
+	assert.Contains(t, updates[0], "Running the test suite")
+	assert.Equal(t, "m3", record.ProgressMessageID)
+
+	// Nothing new: no further edits.
+	p.pollAgentStatuses()
+
+	assert.Len(t, progressUpdates(api), 1)
+	api.AssertNumberOfCalls(t, "CreatePost", 1)
+	cursorClient.AssertExpectations(t)
+}
+
+func TestPoller_ProgressMirrorStopsAtTerminalStatus(t *testing.T) {
+	p, api, cursorClient, store := setupPollerPlugin(t)
+	enableProgressMirroring(store, "ch-1")
+
+	record := newMirrorTestRecord()
+	record.ProgressPostID = "progress-1"
+	record.ProgressMessageID = "m2"
+
+	store.On("ListActiveAgents").Return([]*kvstore.AgentRecord{record}, nil)
+	store.On("GetAgent", "agent-1").Return(record, nil)
+	store.On("GetWorkflowByAgent", "agent-1").Return("", nil)
+	store.On("SaveAgent", mock.Anything).Return(nil)
+	cursorClient.On("GetAgent", mock.Anything, "agent-1").Return(&cursor.Agent{
+		ID:      "agent-1",
+		Status:  cursor.AgentStatusFinished,
+		Summary: "Fixed the login bug",
+		Target:  cursor.AgentTarget{PrURL: "https://github.com/org/repo/pull/5"},
+	}, nil)
+	api.On("CreatePost", mock.Anything).Return(&model.Post{Id: "msg-1"}, nil)
+
+	p.pollAgentStatuses()
+
+	assert.Equal(t, string(cursor.AgentStatusFinished), record.Status)
+	cursorClient.AssertNotCalled(t, "GetConversation", mock.Anything, mock.Anything)
+	assert.Empty(t, progressUpdates(api))
+}
+
+func TestPoller_ProgressMirrorOffByDefault(t *testing.T) {
+	p, api, cursorClient, store := setupPollerPlugin(t)
+
+	record := newMirrorTestRecord()
+	store.On("ListActiveAgents").Return([]*kvstore.AgentRecord{record}, nil)
+	store.On("GetAgent", "agent-1").Return(record, nil)
+	cursorClient.On("GetAgent", mock.Anything, "agent-1").Return(&cursor.Agent{
+		ID:     "agent-1",
+		Status: cursor.AgentStatusRunning,
+	}, nil)
+
+	p.pollAgentStatuses()
+
+	cursorClient.AssertNotCalled(t, "GetConversation", mock.Anything, mock.Anything)
+	api.AssertNotCalled(t, "CreatePost", mock.Anything)
+	assert.Empty(t, record.ProgressPostID)
+}
+
+func TestLatestAssistantMessage(t *testing.T) {
+	assert.Nil(t, latestAssistantMessage(nil))
+	assert.Nil(t, latestAssistantMessage(&cursor.Conversation{
+		Messages: []cursor.Message{{Type: "user_message", Text: "hi"}},
+	}))
+
+	msg := latestAssistantMessage(&cursor.Conversation{
+		Messages: []cursor.Message{
+			{Type: "assistant_message", Text: "first"},
+			{Type: "assistant_message", Text: "  "},
+			{Type: "user_message", Text: "follow-up"},
+		},
+	})
+	require.NotNil(t, msg)
+	assert.Equal(t, "first", msg.Text)
+	assert.Len(t, progressMessageKey(msg), 16)
+}
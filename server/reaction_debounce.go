@@ -0,0 +1,102 @@
+package main
+
+import (
+	"slices"
+	"time"
+)
+
+// pendingTerminalReaction is a terminal reaction swap waiting for the debounce
+// window to pass. Swaps that arrive while one is pending are folded into it so
+// only the final emoji is ever added.
+type pendingTerminalReaction struct {
+	channelID string
+	remove    []string
+	add       string
+	timer     *time.Timer
+}
+
+// swapTerminalReaction swaps the trigger post's reaction for a final review
+// loop or PR state (approved, merged, ...). When TerminalReactionDelayMs is
+// set, the swap is delayed and restarted by each further terminal event on
+// the same post, so events arriving together settle on the last one instead
+// of flickering through intermediate reactions. An empty removeEmoji only adds.
+func (p *Plugin) swapTerminalReaction(channelID, postID, removeEmoji, addEmoji string) {
+	if postID == "" {
+		return
+	}
+
+	delay := p.getConfiguration().GetTerminalReactionDelay()
+	if delay <= 0 {
+		p.applyTerminalReaction(channelID, postID, nonEmpty(removeEmoji), addEmoji)
+		return
+	}
+
+	p.terminalReactionsLock.Lock()
+	defer p.terminalReactionsLock.Unlock()
+
+	if p.pendingTerminalReactions == nil {
+		p.pendingTerminalReactions = make(map[string]*pendingTerminalReaction)
+	}
+
+	pending, ok := p.pendingTerminalReactions[postID]
+	if !ok {
+		pending = &pendingTerminalReaction{channelID: channelID, remove: nonEmpty(removeEmoji)}
+		p.pendingTerminalReactions[postID] = pending
+	} else {
+		pending.timer.Stop()
+		// The previous add was never applied, so a swap away from it is a
+		// no-op; anything else still needs removing once the window closes.
+		if removeEmoji != "" && removeEmoji != pending.add && !slices.Contains(pending.remove, removeEmoji) {
+			pending.remove = append(pending.remove, removeEmoji)
+		}
+	}
+	pending.add = addEmoji
+	pending.timer = time.AfterFunc(delay, func() {
+		p.fireTerminalReaction(postID, pending)
+	})
+}
+
+// fireTerminalReaction applies a pending swap once its debounce window ends.
+// A swap that was superseded or already flushed is ignored.
+func (p *Plugin) fireTerminalReaction(postID string, pending *pendingTerminalReaction) {
+	p.terminalReactionsLock.Lock()
+	if p.pendingTerminalReactions[postID] != pending {
+		p.terminalReactionsLock.Unlock()
+		return
+	}
+	delete(p.pendingTerminalReactions, postID)
+	remove, add := pending.remove, pending.add
+	p.terminalReactionsLock.Unlock()
+
+	p.applyTerminalReaction(pending.channelID, postID, remove, add)
+}
+
+// flushTerminalReactions applies every pending swap immediately. Called on
+// deactivation so a restart does not drop the final reaction.
+func (p *Plugin) flushTerminalReactions() {
+	p.terminalReactionsLock.Lock()
+	pending := p.pendingTerminalReactions
+	p.pendingTerminalReactions = nil
+	p.terminalReactionsLock.Unlock()
+
+	for postID, swap := range pending {
+		swap.timer.Stop()
+		p.applyTerminalReaction(swap.channelID, postID, swap.remove, swap.add)
+	}
+}
+
+func (p *Plugin) applyTerminalReaction(channelID, postID string, remove []string, add string) {
+	for _, emoji := range remove {
+		if emoji != add {
+			p.removeReaction(channelID, postID, emoji)
+		}
+	}
+	p.addReaction(channelID, postID, add)
+}
+
+func nonEmpty(emoji string) []string {
+	if emoji == "" {
+		return nil
+	}
+	return []string{emoji}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func reactionOn(postID, emoji string) any {
+	return mock.MatchedBy(func(r *model.Reaction) bool {
+		return r.PostId == postID && r.EmojiName == emoji
+	})
+}
+
+func TestSwapTerminalReaction_ImmediateWithoutDelay(t *testing.T) {
+	p, api, _, _ := setupReviewLoopTestPlugin(t)
+
+	api.On("RemoveReaction", reactionOn("trigger-1", "eyes")).Return(nil).Once()
+	api.On("AddReaction", reactionOn("trigger-1", "white_check_mark")).Return(nil, nil).Once()
+
+	p.swapTerminalReaction("ch-1", "trigger-1", "eyes", "white_check_mark")
+
+	api.AssertExpectations(t)
+	assert.Empty(t, p.pendingTerminalReactions)
+}
+
+func TestSwapTerminalReaction_SettlesOnLastEvent(t *testing.T) {
+	p, api, _, _ := setupReviewLoopTestPlugin(t)
+	p.configuration.TerminalReactionDelayMs = 50
+
+	added := make(chan struct{})
+	api.On("RemoveReaction", reactionOn("trigger-1", "eyes")).Return(nil).Once()
+	api.On("AddReaction", reactionOn("trigger-1", "rocket")).Return(nil, nil).Once().
+		Run(func(mock.Arguments) { close(added) })
+
+	// Approval immediately followed by merge.
+	p.swapTerminalReaction("ch-1", "trigger-1", "eyes", "white_check_mark")
+	p.swapTerminalReaction("ch-1", "trigger-1", "white_check_mark", "rocket")
+
+	api.AssertNotCalled(t, "AddReaction", mock.Anything)
+
+	select {
+	case <-added:
+	case <-time.After(2 * time.Second):
+		t.Fatal("terminal reaction was not applied after the debounce window")
+	}
+
+	api.AssertExpectations(t)
+	api.AssertNotCalled(t, "AddReaction", reactionOn("trigger-1", "white_check_mark"))
+	api.AssertNotCalled(t, "RemoveReaction", reactionOn("trigger-1", "white_check_mark"))
+}
+
+func TestSwapTerminalReaction_KeepsRemovalsFromEarlierEvents(t *testing.T) {
+	p, api, _, _ := setupReviewLoopTestPlugin(t)
+	p.configuration.TerminalReactionDelayMs = 50
+
+	added := make(chan struct{})
+	api.On("RemoveReaction", reactionOn("trigger-1", "eyes")).Return(nil).Once()
+	api.On("AddReaction", reactionOn("trigger-1", "warning")).Return(nil, nil).Once().
+		Run(func(mock.Arguments) { close(added) })
+
+	p.swapTerminalReaction("ch-1", "trigger-1", "", "rocket")
+	p.swapTerminalReaction("ch-1", "trigger-1", "eyes", "warning")
+
+	select {
+	case <-added:
+	case <-time.After(2 * time.Second):
+		t.Fatal("terminal reaction was not applied after the debounce window")
+	}
+
+	api.AssertExpectations(t)
+	api.AssertNotCalled(t, "AddReaction", reactionOn("trigger-1", "rocket"))
+}
+
+func TestFlushTerminalReactions_AppliesPendingSwaps(t *testing.T) {
+	p, api, _, _ := setupReviewLoopTestPlugin(t)
+	p.configuration.TerminalReactionDelayMs = int(maxTerminalReactionDelay / time.Millisecond)
+
+	api.On("RemoveReaction", reactionOn("trigger-1", "eyes")).Return(nil).Once()
+	api.On("AddReaction", reactionOn("trigger-1", "white_check_mark")).Return(nil, nil).Once()
+
+	p.swapTerminalReaction("ch-1", "trigger-1", "eyes", "white_check_mark")
+	p.flushTerminalReactions()
+
+	api.AssertExpectations(t)
+	assert.Empty(t, p.pendingTerminalReactions)
+}
+
+func TestGetTerminalReactionDelay(t *testing.T) {
+	assert.Zero(t, (&configuration{}).GetTerminalReactionDelay())
+	assert.Zero(t, (&configuration{TerminalReactionDelayMs: -5}).GetTerminalReactionDelay())
+	assert.Equal(t, 1500*time.Millisecond, (&configuration{TerminalReactionDelayMs: 1500}).GetTerminalReactionDelay())
+	assert.Equal(t, maxTerminalReactionDelay, (&configuration{TerminalReactionDelayMs: 600000}).GetTerminalReactionDelay())
+}
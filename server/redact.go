@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// redactedPlaceholder replaces secret-looking spans in text sent to Cursor.
+const redactedPlaceholder = "[REDACTED]"
+
+// redactSecretGroup names the capture group that holds the secret in a
+// redaction pattern. When present, only that group is replaced so the
+// surrounding key name or scheme stays readable.
+const redactSecretGroup = "secret"
+
+// builtinSecretPatterns match common credential formats. Generic key/value
+// assignments require a quoted value so ordinary code such as
+// `token := getToken()` is left alone.
+var builtinSecretPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`\b(?:AKIA|ASIA|AGPA|AIDA|AROA|AIPA|ANPA|ANVA)[0-9A-Z]{16}\b`),
+	regexp.MustCompile(`(?i)\baws_?secret_?access_?key\b["']?\s*[:=]\s*["']?(?P<secret>[A-Za-z0-9/+=]{40})`),
+	regexp.MustCompile(`\b(?:ghp|gho|ghu|ghs|ghr)_[A-Za-z0-9]{36,}\b`),
+	regexp.MustCompile(`\bgithub_pat_[A-Za-z0-9_]{22,}\b`),
+	regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}\b`),
+	regexp.MustCompile(`\b[sr]k_(?:live|test)_[A-Za-z0-9]{16,}\b`),
+	regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`),
+	regexp.MustCompile(`(?i)\bbearer\s+(?P<secret>[A-Za-z0-9\-._~+/]{16,}=*)`),
+	regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`),
+	regexp.MustCompile(`(?i)\b(?:api[_-]?key|secret[_-]?key|client[_-]?secret|access[_-]?token|auth[_-]?token|password|passwd)\b["']?\s*[:=]\s*["'](?P<secret>[^"'\s]{8,})["']`),
+}
+
+// parseSecretRedactionPatterns compiles SecretRedactionPatterns: one regular
+// expression per line, blank lines ignored. Valid patterns are returned even
+// when a line fails to compile; the error names the first bad line.
+func parseSecretRedactionPatterns(raw string) ([]*regexp.Regexp, error) {
+	var patterns []*regexp.Regexp
+	var firstErr error
+	for i, line := range strings.Split(raw, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		re, err := regexp.Compile(line)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("secret redaction pattern on line %d is not a valid regular expression: %w", i+1, err)
+			}
+			continue
+		}
+		patterns = append(patterns, re)
+	}
+	return patterns, firstErr
+}
+
+// redactSecrets replaces every span matched by the built-in and extra
+// patterns with [REDACTED] and returns the redacted text and the number of
+// spans replaced.
+func redactSecrets(text string, extra []*regexp.Regexp) (string, int) {
+	if text == "" {
+		return text, 0
+	}
+	count := 0
+	for _, patterns := range [][]*regexp.Regexp{builtinSecretPatterns, extra} {
+		for _, re := range patterns {
+			text = redactPattern(text, re, &count)
+		}
+	}
+	return text, count
+}
+
+func redactPattern(text string, re *regexp.Regexp, count *int) string {
+	group := re.SubexpIndex(redactSecretGroup)
+	matches := re.FindAllStringSubmatchIndex(text, -1)
+	if len(matches) == 0 {
+		return text
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		start, end := m[0], m[1]
+		if group > 0 && m[2*group] >= 0 {
+			start, end = m[2*group], m[2*group+1]
+		}
+		if start == end || start < last {
+			continue
+		}
+		b.WriteString(text[last:start])
+		b.WriteString(redactedPlaceholder)
+		last = end
+		*count++
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// redactSecretsForCursor redacts secrets from text about to be sent to
+// Cursor, logging how many spans were replaced and where.
+func (p *Plugin) redactSecretsForCursor(text, source string) string {
+	redacted, count := redactSecrets(text, p.getConfiguration().ParseSecretRedactionPatterns())
+	if count > 0 {
+		p.API.LogWarn("Redacted secrets before sending text to Cursor",
+			"source", source,
+			"redacted_count", count,
+		)
+	}
+	return redacted
+}
@@ -2,8 +2,13 @@ package main
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"hash/fnv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/go-github/v68/github"
@@ -35,6 +40,11 @@ func (p *Plugin) ensureReviewLoop(prURL string) *kvstore.ReviewLoop {
 		return nil
 	}
 
+	if _, _, err := p.parseReviewLoopPRURL(prURL); err != nil {
+		p.logDebug("Not bootstrapping review loop for unparseable PR URL", "error", err.Error(), "pr_url", prURL)
+		return nil
+	}
+
 	agent, err := p.kvstore.GetAgentByPRURL(prURL)
 	if err != nil || agent == nil {
 		return nil
@@ -44,7 +54,11 @@ func (p *Plugin) ensureReviewLoop(prURL string) *kvstore.ReviewLoop {
 		return nil // Agent not done yet; loop will start when it finishes.
 	}
 
-	if err := p.startReviewLoop(agent); err != nil {
+	if !p.reviewLoopAllowedForPR(agent, prURL) {
+		return nil
+	}
+
+	if err := p.startReviewLoopForPR(agent, prURL); err != nil {
 		p.API.LogError("Failed to bootstrap review loop from review webhook",
 			"error", err.Error(),
 			"agent_id", agent.CursorAgentID,
@@ -58,22 +72,81 @@ func (p *Plugin) ensureReviewLoop(prURL string) *kvstore.ReviewLoop {
 	return loop
 }
 
+// lockReviewLoop serializes review webhook handling for a loop. One AI review
+// with several inline comments arrives as a burst of concurrent deliveries,
+// so the loop is re-read under the lock to see what an earlier delivery
+// already did. It returns the fresh loop, or nil if it no longer exists, and
+// the func that releases the lock.
+func (p *Plugin) lockReviewLoop(loop *kvstore.ReviewLoop) (*kvstore.ReviewLoop, func()) {
+	mu := p.reviewLoopLock(loop.ID)
+	mu.Lock()
+
+	fresh, err := p.kvstore.GetReviewLoopByPRURL(loop.PRURL)
+	if err != nil {
+		p.API.LogWarn("Failed to re-read review loop under lock", "review_loop_id", loop.ID, "error", err.Error())
+		return loop, mu.Unlock
+	}
+	if fresh != nil && fresh.ID != loop.ID {
+		fresh = nil
+	}
+	return fresh, mu.Unlock
+}
+
+// reviewLoopLockStripes is how many mutexes review loop IDs are spread over.
+// Unrelated loops occasionally share one, which only serializes them.
+const reviewLoopLockStripes = 64
+
+// reviewLoopLock returns the mutex guarding the loop with the given ID.
+func (p *Plugin) reviewLoopLock(loopID string) *sync.Mutex {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(loopID))
+	return &p.reviewLoopLocks[h.Sum32()%reviewLoopLockStripes]
+}
+
+// parseReviewLoopPRURL resolves a PR URL through the configured repo mappings
+// and validates the owner/repo it points at. Errors wrap *ghclient.RepoRefError
+// when the PR URL parses but names an invalid repository.
+func (p *Plugin) parseReviewLoopPRURL(prURL string) (*ghclient.PRReference, *ghclient.RepoRef, error) {
+	prRef, err := ghclient.ParsePRURLWithMappings(prURL, p.getConfiguration().GetGitHubRepoMappings())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse PR URL %q: %w", prURL, err)
+	}
+	repoRef, err := ghclient.ParseRepoRef(prRef.Owner + "/" + prRef.Repo)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse PR URL %q: %w", prURL, err)
+	}
+	return prRef, repoRef, nil
+}
+
 // startReviewLoop creates a ReviewLoop record and requests AI reviewers on the PR.
 // Called from handleAgentFinished when EnableAIReviewLoop is true and the agent has a PR URL.
 func (p *Plugin) startReviewLoop(record *kvstore.AgentRecord) error {
-	prRef, err := ghclient.ParsePRURL(record.PrURL)
+	return p.startReviewLoopForPR(record, record.PrURL)
+}
+
+// startReviewLoopForPR starts a review loop for prURL, one of the agent's
+// PRs: its primary PR or one of its additional PRs. No loop is started while
+// review loops are globally paused; the janitor sweep bootstraps it once the
+// pause is lifted.
+func (p *Plugin) startReviewLoopForPR(record *kvstore.AgentRecord, prURL string) error {
+	if p.reviewLoopsGloballyPaused() {
+		p.logDebug("Not starting review loop while review loops are globally paused", "pr_url", prURL)
+		return nil
+	}
+
+	prRef, repoRef, err := p.parseReviewLoopPRURL(prURL)
 	if err != nil {
-		return fmt.Errorf("failed to parse PR URL %q: %w", record.PrURL, err)
+		return err
 	}
 
 	// Idempotency: check for existing review loop for this PR.
-	existing, _ := p.kvstore.GetReviewLoopByPRURL(record.PrURL)
+	existing, _ := p.kvstore.GetReviewLoopByPRURL(prURL)
 	if existing != nil {
-		p.API.LogDebug("Review loop already exists for PR, skipping", "pr_url", record.PrURL, "review_loop_id", existing.ID)
+		p.API.LogDebug("Review loop already exists for PR, skipping", "pr_url", prURL, "review_loop_id", existing.ID)
 		return nil
 	}
 
-	now := time.Now().UnixMilli()
+	now := p.now().UnixMilli()
 	loop := &kvstore.ReviewLoop{
 		ID:            uuid.New().String(),
 		AgentRecordID: record.CursorAgentID,
@@ -81,11 +154,12 @@ func (p *Plugin) startReviewLoop(record *kvstore.AgentRecord) error {
 		ChannelID:     record.ChannelID,
 		RootPostID:    record.PostID,
 		TriggerPostID: record.TriggerPostID,
-		PRURL:         record.PrURL,
+		PRURL:         prURL,
 		PRNumber:      prRef.Number,
-		Repository:    prRef.Owner + "/" + prRef.Repo,
-		Owner:         prRef.Owner,
-		Repo:          prRef.Repo,
+		Repository:    repoRef.FullName(),
+		Owner:         repoRef.Owner,
+		Repo:          repoRef.Repo,
+		BaseBranch:    record.BaseBranch,
 		Phase:         kvstore.ReviewPhaseRequestingReview,
 		Iteration:     1,
 		History: []kvstore.ReviewLoopEvent{
@@ -94,6 +168,7 @@ func (p *Plugin) startReviewLoop(record *kvstore.AgentRecord) error {
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
+	loop.IdempotencyWindowSeconds = p.getConfiguration().GetReviewLoopIdempotencyWindowSeconds()
 
 	// Check for HITL workflow linkage.
 	workflowID, _ := p.kvstore.GetWorkflowByAgent(record.CursorAgentID)
@@ -116,7 +191,7 @@ func (p *Plugin) startReviewLoop(record *kvstore.AgentRecord) error {
 	if err := ghClient.MarkPRReadyForReview(ctx, prRef.Owner, prRef.Repo, prRef.Number); err != nil {
 		p.API.LogError("Failed to mark PR as ready for review; review loop will retry",
 			"error", err.Error(),
-			"pr_url", record.PrURL,
+			"pr_url", prURL,
 		)
 		// Delete the loop record so the janitor can re-bootstrap it cleanly.
 		_ = p.kvstore.DeleteReviewLoop(loop.ID)
@@ -125,32 +200,17 @@ func (p *Plugin) startReviewLoop(record *kvstore.AgentRecord) error {
 
 	// Request AI reviewers via GitHub API (optional -- bots like CodeRabbit
 	// auto-detect PRs, so this is a best-effort nudge).
-	config := p.getConfiguration()
-	botUsernames := config.ParseAIReviewerBots()
-	if len(botUsernames) == 0 {
-		p.API.LogInfo("No AI reviewer bots configured, skipping explicit review request")
-	} else {
-		err := ghClient.RequestReviewers(ctx, prRef.Owner, prRef.Repo, prRef.Number, github.ReviewersRequest{
-			Reviewers: botUsernames,
-		})
-		if err != nil {
-			p.API.LogWarn("Failed to request AI reviewers (non-fatal, bots may auto-detect the PR)",
-				"error", err.Error(),
-				"pr_url", record.PrURL,
-				"reviewers", strings.Join(botUsernames, ", "),
-			)
-			// Non-fatal: bots like CodeRabbit auto-detect new PRs.
-		}
-	}
+	botUsernames := p.requestAIReviewers(ctx, ghClient, prRef.Owner, prRef.Repo, prRef.Number, prURL)
+	p.postReviewerWarmupComment(ctx, ghClient, prRef.Owner, prRef.Repo, prRef.Number, prURL, botUsernames)
 
 	// Transition to awaiting_review.
 	loop.Phase = kvstore.ReviewPhaseAwaitingReview
 	loop.History = append(loop.History, kvstore.ReviewLoopEvent{
 		Phase:     kvstore.ReviewPhaseAwaitingReview,
-		Timestamp: time.Now().UnixMilli(),
+		Timestamp: p.now().UnixMilli(),
 		Detail:    reviewLoopAwaitDetail(botUsernames),
 	})
-	loop.UpdatedAt = time.Now().UnixMilli()
+	loop.UpdatedAt = p.now().UnixMilli()
 	if err := p.kvstore.SaveReviewLoop(loop); err != nil {
 		return fmt.Errorf("failed to update review loop phase: %w", err)
 	}
@@ -160,31 +220,106 @@ func (p *Plugin) startReviewLoop(record *kvstore.AgentRecord) error {
 	p.publishReviewLoopChange(loop)
 
 	// Add eyes reaction on trigger post.
-	p.addReaction(loop.TriggerPostID, "eyes")
+	p.addReaction(loop.ChannelID, loop.TriggerPostID, "eyes")
 
 	return nil
 }
 
+// requestAIReviewers asks the configured AI reviewer bots to review the PR.
+// Failures are logged and non-fatal since bots like CodeRabbit auto-detect
+// PRs. Returns the configured bot usernames.
+func (p *Plugin) requestAIReviewers(ctx context.Context, ghClient ghclient.Client, owner, repo string, prNumber int, prURL string) []string {
+	botUsernames := p.getConfiguration().ParseAIReviewerBots()
+	if len(botUsernames) == 0 {
+		p.API.LogInfo("No AI reviewer bots configured, skipping explicit review request")
+		return nil
+	}
+
+	err := ghClient.RequestReviewers(ctx, owner, repo, prNumber, github.ReviewersRequest{
+		Reviewers: botUsernames,
+	})
+	if err != nil {
+		p.API.LogWarn("Failed to request AI reviewers (non-fatal, bots may auto-detect the PR)",
+			"error", err.Error(),
+			"pr_url", prURL,
+			"reviewers", strings.Join(botUsernames, ", "),
+		)
+	}
+	return botUsernames
+}
+
 const (
 	reviewDispatchModeDirect            = "direct"
 	reviewDispatchModeSkippedIdempotent = "skipped_idempotent"
+	reviewDispatchModeSkippedSeverity   = "skipped_below_min_severity"
+	reviewDispatchModeSkippedNoFindings = "skipped_no_findings"
 	reviewDispatchModeFailed            = "failed"
+	reviewDispatchModeRecovered         = "recovered_checkpoint"
+	reviewDispatchModeDeferred          = "deferred_quiet_hours"
+	reviewDispatchModeDeferredGitHub    = "deferred_github_unavailable"
+	reviewDispatchModeDeferredRateLimit = "deferred_rate_limited"
+	reviewDispatchModeUnconfirmed       = "deferred_unconfirmed_checkpoint"
+	reviewDispatchModeCoalesced         = "coalesced_idempotency_window"
 
 	reviewDispatchReasonDirectSuccess       = "direct_success"
 	reviewDispatchReasonIdempotentSameState = "idempotent_same_sha_digest"
+	reviewDispatchReasonBelowMinSeverity    = "all_findings_below_min_severity"
 	reviewDispatchReasonDirectFailed        = "direct_failed"
 	reviewDispatchReasonCursorClientNil     = "cursor_client_nil"
 	reviewDispatchReasonAddFollowupError    = "add_followup_error"
+	reviewDispatchReasonCheckpointDelivered = "checkpoint_delivered"
+	reviewDispatchReasonCheckpointUnknown   = "checkpoint_unconfirmed"
+	reviewDispatchReasonQuietHours          = "quiet_hours_active"
+	reviewDispatchReasonRateLimited         = "add_followup_rate_limited"
+	reviewDispatchReasonIdempotencyWindow   = "within_idempotency_window"
+	reviewDispatchReasonNoFindings          = "no_actionable_findings"
 
 	reviewFeedbackDropReasonUnknown = "unknown_drop_reason"
 )
 
+// reviewDispatchOptions adjusts how dispatchReviewFeedbackWithOptions treats
+// the collected feedback.
+type reviewDispatchOptions struct {
+	// RequireFindings skips the dispatch when no actionable findings were
+	// extracted, instead of sending the agent an empty follow-up.
+	RequireFindings bool
+}
+
 type reviewDispatchOutcome struct {
-	Dispatched bool
-	Skipped    bool
-	Failed     bool
-	Mode       string
-	Counts     reviewFeedbackClassificationSummary
+	Dispatched  bool
+	Skipped     bool
+	Failed      bool
+	Mode        string
+	Counts      reviewFeedbackClassificationSummary
+	DispatchSHA string
+	Digest      string
+}
+
+// dispatchHistoryEvent returns a loop history event recording the outcome,
+// with detail as its display text.
+func (p *Plugin) dispatchHistoryEvent(o reviewDispatchOutcome, phase, detail string) kvstore.ReviewLoopEvent {
+	return p.newReviewDispatchEvent(phase, detail, o.Mode, o.Counts, o.DispatchSHA, o.Digest)
+}
+
+// newReviewDispatchEvent returns a loop history event for a feedback dispatch
+// decision, carrying its mode, counts, and dispatch state alongside detail.
+func (p *Plugin) newReviewDispatchEvent(phase, detail, mode string, counts reviewFeedbackClassificationSummary, dispatchSHA, digest string) kvstore.ReviewLoopEvent {
+	event := kvstore.ReviewLoopEvent{
+		Phase:     phase,
+		Timestamp: p.now().UnixMilli(),
+		Detail:    detail,
+	}
+	setReviewDispatchEventFields(&event, mode, counts, dispatchSHA, digest)
+	return event
+}
+
+func setReviewDispatchEventFields(event *kvstore.ReviewLoopEvent, mode string, counts reviewFeedbackClassificationSummary, dispatchSHA, digest string) {
+	event.Mode = mode
+	event.New = counts.New
+	event.Repeated = counts.Repeated
+	event.Dismissed = counts.Dismissed
+	event.DispatchSHA = dispatchSHA
+	event.Digest = digest
 }
 
 func formatReviewDispatchHistoryDetail(baseDetail, modeLabel string, counts reviewFeedbackClassificationSummary) string {
@@ -197,187 +332,513 @@ func formatReviewDispatchHistoryDetail(baseDetail, modeLabel string, counts revi
 
 // handleAIReview processes a submitted review from a known AI reviewer bot.
 // It checks whether CodeRabbit is satisfied and either transitions to approved
-// or dispatches follow-up feedback.
-func (p *Plugin) handleAIReview(loop *kvstore.ReviewLoop, review ghReview, pr ghPullRequest) error {
-	isCodeRabbit := strings.EqualFold(review.User.Login, codeRabbitReviewerLogin)
-	codeRabbitSatisfied := false
-
-	if isCodeRabbit {
-		// Primary signal: review state is APPROVED.
-		if strings.EqualFold(review.State, reviewStateApproved) {
-			codeRabbitSatisfied = true
-		}
-		// Fallback signal: body contains "Actionable comments posted: 0".
-		if !codeRabbitSatisfied && strings.Contains(review.Body, "Actionable comments posted: 0") {
-			codeRabbitSatisfied = true
-		}
+// or dispatches follow-up feedback. While review loops are globally paused the
+// review is held on the loop instead.
+func (p *Plugin) handleAIReview(ctx context.Context, loop *kvstore.ReviewLoop, review ghReview, pr ghPullRequest) error {
+	if p.holdReviewForGlobalPause(loop, review, pr) {
+		return nil
 	}
 
+	isCodeRabbit := strings.EqualFold(review.User.Login, codeRabbitReviewerLogin)
+	changesRequested := strings.EqualFold(review.State, reviewStateChangesRequested)
+
 	// If CodeRabbit is satisfied, transition to approved.
-	if codeRabbitSatisfied {
+	if isCodeRabbitSatisfied(review) {
 		loop.Phase = kvstore.ReviewPhaseApproved
 		loop.History = append(loop.History, kvstore.ReviewLoopEvent{
 			Phase:     kvstore.ReviewPhaseApproved,
-			Timestamp: time.Now().UnixMilli(),
+			Timestamp: p.now().UnixMilli(),
 			Detail:    fmt.Sprintf("Approved after %d iteration(s)", loop.Iteration),
 		})
-		loop.UpdatedAt = time.Now().UnixMilli()
+		loop.UpdatedAt = p.now().UnixMilli()
 		if err := p.kvstore.SaveReviewLoop(loop); err != nil {
 			return fmt.Errorf("failed to save approved review loop: %w", err)
 		}
 
 		p.updateReviewLoopInlineStatus(loop)
 		p.publishReviewLoopChange(loop)
-		p.postReviewLoopCompletion(loop, attachments.BuildReviewApprovedAttachment(
-			loop.PRURL,
-			loop.Iteration,
-		))
-		p.swapReaction(loop.TriggerPostID, "eyes", "white_check_mark")
+		p.postReviewLoopCompletion(loop, p.styleNotification(notificationEventReviewLoopApproved,
+			attachments.BuildReviewApprovedAttachment(loop.PRURL, loop.Iteration),
+			notificationVars{PRNumber: loop.PRNumber, PRTitle: pr.Title, PRURL: loop.PRURL, Reviewer: review.User.Login}))
+		p.notifySlackReviewLoopApproved(loop)
+		p.swapTerminalReaction(loop.ChannelID, loop.TriggerPostID, "eyes", "white_check_mark")
 
+		if p.autoMergeApprovedPR(loop, pr) {
+			return nil
+		}
 		return p.transitionToHumanReview(loop)
 	}
 
-	// If CodeRabbit has actionable feedback (not satisfied AND is CodeRabbit).
-	if isCodeRabbit {
-		// Check iteration limit.
-		config := p.getConfiguration()
-		if loop.Iteration >= config.MaxReviewIterations {
-			loop.Phase = kvstore.ReviewPhaseMaxIterations
-			loop.History = append(loop.History, kvstore.ReviewLoopEvent{
-				Phase:     kvstore.ReviewPhaseMaxIterations,
-				Timestamp: time.Now().UnixMilli(),
-				Detail:    fmt.Sprintf("Reached max iterations (%d)", config.MaxReviewIterations),
-			})
-			loop.UpdatedAt = time.Now().UnixMilli()
-			_ = p.kvstore.SaveReviewLoop(loop)
-
-			p.updateReviewLoopInlineStatus(loop)
-			p.publishReviewLoopChange(loop)
-			p.postReviewLoopCompletion(loop, attachments.BuildMaxIterationsAttachment(
-				loop.PRURL,
-				config.MaxReviewIterations,
-			))
-			p.swapReaction(loop.TriggerPostID, "eyes", "warning")
-			return nil
-		}
+	// If CodeRabbit has actionable feedback (not satisfied AND is CodeRabbit),
+	// any AI reviewer explicitly requested changes, or another AI reviewer
+	// left an inline-only review whose comments are the feedback.
+	if isCodeRabbit || changesRequested || p.isInlineOnlyAIReview(review) {
+		return p.dispatchAIReviewIteration(ctx, loop, pr, review.User.Login, changesRequested)
+	}
 
-		if pr.Head.SHA != "" {
-			loop.LastCommitSHA = pr.Head.SHA
-		}
+	// Other non-CodeRabbit bot reviews are informational only.
+	p.logger(ctx).LogDebug("Non-CodeRabbit AI review received, not driving state transition",
+		"reviewer", review.User.Login,
+		"review_loop_id", loop.ID,
+	)
+	return nil
+}
 
-		outcome, err := p.dispatchReviewFeedback(loop, pr)
-		if err != nil {
-			p.API.LogError("Failed to dispatch AI review feedback",
-				"error", err.Error(),
-				"review_loop_id", loop.ID,
-			)
-			return err
-		}
+// handleAIInlineComment dispatches the feedback of an AI reviewer's inline
+// comment while the loop awaits review. Comments already collected as
+// findings were handled by the batched review path and are skipped, as are
+// comments arriving once that path has moved the loop on. CodeRabbit comments
+// are left to its review, which also carries its approval signal.
+func (p *Plugin) handleAIInlineComment(ctx context.Context, loop *kvstore.ReviewLoop, comment ghReviewComment, pr ghPullRequest) error {
+	if loop.Phase != kvstore.ReviewPhaseAwaitingReview || p.reviewLoopsGloballyPaused() {
+		return nil
+	}
+	login := comment.User.Login
+	if strings.EqualFold(login, codeRabbitReviewerLogin) || p.reviewerTypeForLogin(login, comment.Path) != reviewerTypeAIBot {
+		return nil
+	}
+	if hasFindingFromSource(loop, "review_comment", comment.ID) {
+		p.logDebugContext(ctx, "Inline review comment already collected, skipping dispatch",
+			"review_loop_id", loop.ID,
+			"comment_id", comment.ID,
+		)
+		return nil
+	}
 
-		if outcome.Skipped || outcome.Failed {
-			if err := p.kvstore.SaveReviewLoop(loop); err != nil {
-				return fmt.Errorf("failed to save review loop after dispatch outcome: %w", err)
-			}
-			p.publishReviewLoopChange(loop)
-			return nil
-		}
-		if !outcome.Dispatched {
-			return nil
-		}
+	return p.dispatchAIReviewIteration(ctx, loop, pr, login, false)
+}
 
-		detail := formatReviewDispatchHistoryDetail(
-			fmt.Sprintf("Iteration %d", loop.Iteration+1),
-			"",
-			outcome.Counts,
-		)
-		if outcome.Mode == reviewDispatchModeDirect {
-			detail = formatReviewDispatchHistoryDetail(
-				fmt.Sprintf("Iteration %d", loop.Iteration+1),
-				"direct follow-up dispatched",
-				outcome.Counts,
-			)
+// hasFindingFromSource reports whether the loop already tracks a finding
+// collected from the given GitHub comment.
+func hasFindingFromSource(loop *kvstore.ReviewLoop, sourceType string, sourceID int64) bool {
+	if sourceID == 0 {
+		return false
+	}
+	for _, finding := range loop.Findings {
+		if finding.SourceType == sourceType && finding.SourceID == sourceID {
+			return true
 		}
+	}
+	return false
+}
 
-		loop.Phase = kvstore.ReviewPhaseCursorFixing
-		loop.Iteration++
+// dispatchAIReviewIteration sends the AI feedback collected for the PR to the
+// agent and, once it is delivered, starts the next fix iteration. It stops the
+// loop instead when the iteration limit has been reached. changesRequested
+// requires actionable findings before dispatching.
+func (p *Plugin) dispatchAIReviewIteration(ctx context.Context, loop *kvstore.ReviewLoop, pr ghPullRequest, reviewerLogin string, changesRequested bool) error {
+	// Check iteration limit.
+	config := p.getConfiguration()
+	if loop.Iteration >= config.MaxReviewIterations {
+		loop.Phase = kvstore.ReviewPhaseMaxIterations
 		loop.History = append(loop.History, kvstore.ReviewLoopEvent{
-			Phase:     kvstore.ReviewPhaseCursorFixing,
-			Timestamp: time.Now().UnixMilli(),
-			Detail:    detail,
+			Phase:     kvstore.ReviewPhaseMaxIterations,
+			Timestamp: p.now().UnixMilli(),
+			Detail:    fmt.Sprintf("Reached max iterations (%d)", config.MaxReviewIterations),
 		})
-		loop.UpdatedAt = time.Now().UnixMilli()
-		if err := p.kvstore.SaveReviewLoop(loop); err != nil {
-			return fmt.Errorf("failed to save review loop: %w", err)
-		}
+		loop.UpdatedAt = p.now().UnixMilli()
+		_ = p.kvstore.SaveReviewLoop(loop)
 
 		p.updateReviewLoopInlineStatus(loop)
 		p.publishReviewLoopChange(loop)
+		p.postReviewLoopCompletion(loop, p.styleReviewLoopNotification(notificationEventReviewLoopMaxIterations, loop,
+			attachments.BuildMaxIterationsAttachment(loop.PRURL, config.MaxReviewIterations)))
+		p.swapTerminalReaction(loop.ChannelID, loop.TriggerPostID, "eyes", "warning")
 		return nil
 	}
 
-	// Non-CodeRabbit bot reviews are informational only.
-	p.API.LogDebug("Non-CodeRabbit AI review received, not driving state transition",
-		"reviewer", review.User.Login,
-		"review_loop_id", loop.ID,
+	if pr.Head.SHA != "" {
+		loop.LastCommitSHA = pr.Head.SHA
+	}
+
+	outcome, err := p.dispatchReviewFeedbackWithOptions(ctx, loop, pr, reviewDispatchOptions{RequireFindings: changesRequested})
+	if err != nil {
+		p.logger(ctx).LogError("Failed to dispatch AI review feedback",
+			"error", err.Error(),
+			"review_loop_id", loop.ID,
+		)
+		return err
+	}
+
+	if outcome.Mode == reviewDispatchModeSkippedSeverity {
+		// Only low-severity findings remain; don't burn an iteration on them.
+		return p.transitionToHumanReview(loop)
+	}
+	if outcome.Mode == reviewDispatchModeSkippedNoFindings {
+		// The bot requested changes without saying what to change; ask it
+		// for detail and keep waiting for its next review.
+		p.requestChangesRequestedDetail(loop, reviewerLogin)
+	}
+	if outcome.Failed {
+		if err := p.kvstore.SaveReviewLoop(loop); err != nil {
+			return fmt.Errorf("failed to save review loop after dispatch outcome: %w", err)
+		}
+		p.notifyReviewLoopError(loop, loop.History[len(loop.History)-1].Detail)
+		return nil
+	}
+	if outcome.Skipped {
+		if err := p.kvstore.SaveReviewLoop(loop); err != nil {
+			return fmt.Errorf("failed to save review loop after dispatch outcome: %w", err)
+		}
+		p.publishReviewLoopChange(loop)
+		return nil
+	}
+	if !outcome.Dispatched {
+		return nil
+	}
+
+	detail := formatReviewDispatchHistoryDetail(
+		fmt.Sprintf("Iteration %d", loop.Iteration+1),
+		"",
+		outcome.Counts,
 	)
+	switch outcome.Mode {
+	case reviewDispatchModeDirect:
+		detail = formatReviewDispatchHistoryDetail(
+			fmt.Sprintf("Iteration %d", loop.Iteration+1),
+			"direct follow-up dispatched",
+			outcome.Counts,
+		)
+	case reviewDispatchModeRecovered:
+		detail = formatReviewDispatchHistoryDetail(
+			fmt.Sprintf("Iteration %d", loop.Iteration+1),
+			"recovered interrupted dispatch",
+			outcome.Counts,
+		)
+	}
+
+	loop.Phase = kvstore.ReviewPhaseCursorFixing
+	loop.Iteration++
+	loop.History = append(loop.History, p.dispatchHistoryEvent(outcome, kvstore.ReviewPhaseCursorFixing, detail))
+	p.maybeWarnIterationThreshold(loop)
+	loop.UpdatedAt = p.now().UnixMilli()
+	if err := p.kvstore.SaveReviewLoop(loop); err != nil {
+		// Feedback was already sent, so the stored loop no longer matches
+		// what the agent is doing. Park it until the owner resets it.
+		p.enterReviewErrorPhase(loop, fmt.Sprintf("Failed to record dispatched review feedback: %s", err.Error()))
+		return fmt.Errorf("failed to save review loop: %w", err)
+	}
+
+	p.updateReviewLoopInlineStatus(loop)
+	p.publishReviewLoopChange(loop)
 	return nil
 }
 
+// isCodeRabbitSatisfied reports whether review is a CodeRabbit review that
+// clears the AI gate.
+func isCodeRabbitSatisfied(review ghReview) bool {
+	if !strings.EqualFold(review.User.Login, codeRabbitReviewerLogin) {
+		return false
+	}
+	// Primary signal: review state is APPROVED.
+	if strings.EqualFold(review.State, reviewStateApproved) {
+		return true
+	}
+	// Fallback signal: body contains "Actionable comments posted: 0".
+	return strings.Contains(review.Body, "Actionable comments posted: 0")
+}
+
+// isInlineOnlyAIReview reports whether a non-CodeRabbit AI review should drive
+// a fix iteration because its body is empty and the feedback lives entirely in
+// inline comments.
+func (p *Plugin) isInlineOnlyAIReview(review ghReview) bool {
+	if !p.getConfiguration().ReviewLoopDispatchInlineOnlyReviews {
+		return false
+	}
+	if strings.EqualFold(review.State, reviewStateApproved) {
+		return false
+	}
+	return strings.TrimSpace(review.Body) == ""
+}
+
 // handlePRSynchronize processes a push to a PR with an active review loop.
 // Transitions from cursor_fixing -> awaiting_review to trigger re-review.
-func (p *Plugin) handlePRSynchronize(loop *kvstore.ReviewLoop, pr ghPullRequest) error {
+func (p *Plugin) handlePRSynchronize(ctx context.Context, loop *kvstore.ReviewLoop, pr ghPullRequest) error {
+	previousSHA := loop.LastCommitSHA
+	forcePushed := p.isForcePush(ctx, loop, previousSHA, pr.Head.SHA)
 	if pr.Head.SHA != "" {
 		loop.LastCommitSHA = pr.Head.SHA
 	}
+	loop.PushFailureCount = 0
+
+	detail := "Cursor pushed fixes"
+	if resolved := p.resolveReferencedFindings(ctx, loop); len(resolved) > 0 {
+		detail = fmt.Sprintf("Cursor pushed fixes (%d finding(s) resolved by reference)", len(resolved))
+	}
+	if forcePushed {
+		// The last dispatched SHA may no longer be in the branch history, or
+		// may reappear after a reset; either way it must not suppress the
+		// next review's dispatch as a duplicate.
+		loop.LastFeedbackDispatchSHA = ""
+		loop.History = append(loop.History, kvstore.ReviewLoopEvent{
+			Phase:     loop.Phase,
+			Timestamp: p.now().UnixMilli(),
+			Detail:    fmt.Sprintf("Force-push detected (%s rewritten to %s)", shortSHA(previousSHA), shortSHA(pr.Head.SHA)),
+		})
+	}
 
 	loop.Phase = kvstore.ReviewPhaseAwaitingReview
 	loop.History = append(loop.History, kvstore.ReviewLoopEvent{
 		Phase:     kvstore.ReviewPhaseAwaitingReview,
-		Timestamp: time.Now().UnixMilli(),
-		Detail:    "Cursor pushed fixes",
+		Timestamp: p.now().UnixMilli(),
+		Detail:    detail,
 	})
-	loop.UpdatedAt = time.Now().UnixMilli()
+	loop.UpdatedAt = p.now().UnixMilli()
 
 	if err := p.kvstore.SaveReviewLoop(loop); err != nil {
+		// Without the awaiting_review transition the next AI review would be
+		// matched against a stale phase. Park the loop until the owner resets it.
+		p.enterReviewErrorPhase(loop, fmt.Sprintf("Failed to record pushed fixes: %s", err.Error()))
 		return fmt.Errorf("failed to save review loop: %w", err)
 	}
 
 	p.updateReviewLoopInlineStatus(loop)
 	p.publishReviewLoopChange(loop)
+
+	// Nudge the AI reviewers to re-review the new commits; some bots don't
+	// reliably pick up pushes on their own.
+	if p.getConfiguration().ReviewLoopReRequestOnSynchronize {
+		if ghClient := p.getGitHubClient(); ghClient != nil {
+			reqCtx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+			defer cancel()
+			p.requestAIReviewers(reqCtx, ghClient, loop.Owner, loop.Repo, loop.PRNumber, loop.PRURL)
+		}
+	}
+
+	// The push may have resolved outstanding merge conflicts.
+	if loop.MergeConflictAt != 0 {
+		p.checkReviewLoopMergeConflict(loop)
+	}
 	return nil
 }
 
-func (p *Plugin) dispatchReviewFeedback(loop *kvstore.ReviewLoop, pr ghPullRequest) (reviewDispatchOutcome, error) {
+// isForcePush reports whether moving the PR head from previousSHA to newSHA
+// rewrote history, i.e. previousSHA is not an ancestor of newSHA. A previous
+// head GitHub no longer knows about was discarded by a force-push. Other
+// compare failures are logged and treated as a normal push.
+func (p *Plugin) isForcePush(ctx context.Context, loop *kvstore.ReviewLoop, previousSHA, newSHA string) bool {
+	if previousSHA == "" || newSHA == "" || previousSHA == newSHA {
+		return false
+	}
+	ghClient := p.getGitHubClient()
+	if ghClient == nil {
+		return false
+	}
+
+	reqCtx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	comparison, err := ghClient.CompareCommits(reqCtx, loop.Owner, loop.Repo, previousSHA, newSHA)
+	if err != nil {
+		if ghclient.IsNotFound(err) {
+			return true
+		}
+		p.logger(ctx).LogWarn("Failed to compare commits for force-push detection",
+			"review_loop_id", loop.ID,
+			"error", err.Error(),
+		)
+		return false
+	}
+
+	switch comparison.GetStatus() {
+	case "ahead", "identical":
+		return false
+	default:
+		return true
+	}
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+// resolveReferencedFindings explicitly resolves open findings whose short IDs
+// the agent cited in commit messages or PR comments. Failures are logged and
+// leave resolution to the disappearance-based pass in classifyFeedback.
+func (p *Plugin) resolveReferencedFindings(ctx context.Context, loop *kvstore.ReviewLoop) []kvstore.ReviewFinding {
+	hasOpen := false
+	for _, finding := range loop.Findings {
+		if finding.Status == findingStatusOpen {
+			hasOpen = true
+			break
+		}
+	}
+	if !hasOpen {
+		return nil
+	}
+
+	texts, err := p.collectFindingReferenceTexts(loop)
+	if err != nil {
+		p.logger(ctx).LogWarn("Failed to collect finding references",
+			"error", err.Error(),
+			"review_loop_id", loop.ID,
+		)
+		return nil
+	}
+
+	resolved := resolveFindingsByReference(loop, texts, p.now().UnixMilli())
+	p.resolveFindingThreads(loop, resolved)
+	return resolved
+}
+
+func (p *Plugin) dispatchReviewFeedback(ctx context.Context, loop *kvstore.ReviewLoop, pr ghPullRequest) (reviewDispatchOutcome, error) {
+	return p.dispatchReviewFeedbackWithOptions(ctx, loop, pr, reviewDispatchOptions{})
+}
+
+func (p *Plugin) dispatchReviewFeedbackWithOptions(ctx context.Context, loop *kvstore.ReviewLoop, pr ghPullRequest, opts reviewDispatchOptions) (reviewDispatchOutcome, error) {
 	classification, telemetry, _, err := p.collectReviewFeedbackBundle(loop)
+	if errors.Is(err, ghclient.ErrCircuitOpen) {
+		// GitHub is failing; keep the loop where it is and let the poller
+		// retry once the breaker lets requests through again.
+		now := p.now().UnixMilli()
+		if !loop.GitHubRetryPending {
+			loop.History = append(loop.History, p.newReviewDispatchEvent(
+				loop.Phase,
+				"Deferred review feedback dispatch until GitHub is reachable",
+				reviewDispatchModeDeferredGitHub,
+				reviewFeedbackClassificationSummary{},
+				strings.TrimSpace(pr.Head.SHA),
+				"",
+			))
+		}
+		deferDispatchForGitHubOutage(loop, pr)
+		loop.UpdatedAt = now
+		p.logger(ctx).LogWarn("Deferred review feedback dispatch while GitHub is unavailable",
+			"review_loop_id", loop.ID,
+			"phase", loop.Phase,
+		)
+		return reviewDispatchOutcome{
+			Skipped: true,
+			Mode:    reviewDispatchModeDeferredGitHub,
+		}, nil
+	}
 	if err != nil {
 		return reviewDispatchOutcome{}, fmt.Errorf("failed to collect review feedback: %w", err)
 	}
 
+	dispatchable := classification.Dispatchable
+	if loop.Phase == kvstore.ReviewPhaseAwaitingReview {
+		config := p.getConfiguration()
+		dispatchable = prioritizeFindingsByReviewer(dispatchable, config.ParseAIReviewerPriority(), config.AIReviewerPriorityExclusive)
+	}
+	dispatchable, heldBack := limitFindingsPerIteration(dispatchable, p.getConfiguration().GetMaxFindingsPerIteration())
+
 	counts := telemetry.Counts
 	dispatchSHA := strings.TrimSpace(pr.Head.SHA)
 	if dispatchSHA == "" {
 		dispatchSHA = strings.TrimSpace(loop.LastCommitSHA)
 	}
-	dispatchDigest := reviewFeedbackDigest(classification.Dispatchable)
+	dispatchDigest := reviewFeedbackDigest(dispatchable)
 	lastDispatchSHA := loop.LastFeedbackDispatchSHA
 	lastDispatchDigest := loop.LastFeedbackDigest
 
-	p.logReviewFeedbackCollectionSummary(loop, dispatchSHA, telemetry)
+	p.logReviewFeedbackCollectionSummary(ctx, loop, dispatchSHA, telemetry)
+
+	if loop.PendingDispatchAt > 0 {
+		delivered, err := p.reconcilePendingDispatch(loop)
+		if err != nil {
+			// Delivery cannot be confirmed either way; keep the checkpoint and
+			// hold the dispatch until the poller can reconcile it.
+			p.logReviewFeedbackDispatchDecision(
+				ctx,
+				loop,
+				reviewDispatchModeUnconfirmed,
+				reviewDispatchReasonCheckpointUnknown,
+				dispatchSHA,
+				dispatchDigest,
+				lastDispatchSHA,
+				lastDispatchDigest,
+				counts,
+				err.Error(),
+			)
+			return reviewDispatchOutcome{
+				Skipped:     true,
+				Mode:        reviewDispatchModeUnconfirmed,
+				Counts:      counts,
+				DispatchSHA: dispatchSHA,
+				Digest:      dispatchDigest,
+			}, nil
+		}
+		if delivered {
+			p.logReviewFeedbackDispatchDecision(
+				ctx,
+				loop,
+				reviewDispatchModeRecovered,
+				reviewDispatchReasonCheckpointDelivered,
+				dispatchSHA,
+				dispatchDigest,
+				lastDispatchSHA,
+				lastDispatchDigest,
+				counts,
+				"",
+			)
+			return reviewDispatchOutcome{
+				Dispatched:  true,
+				Mode:        reviewDispatchModeRecovered,
+				Counts:      counts,
+				DispatchSHA: dispatchSHA,
+				Digest:      dispatchDigest,
+			}, nil
+		}
+	}
+
+	if opts.RequireFindings && len(dispatchable) == 0 {
+		loop.History = append(loop.History, p.newReviewDispatchEvent(
+			loop.Phase,
+			fmt.Sprintf(
+				"Skipped review feedback dispatch (changes requested without actionable findings; %s)",
+				formatReviewFeedbackCountSummary(counts.New, counts.Repeated, counts.Dismissed),
+			),
+			reviewDispatchModeSkippedNoFindings,
+			counts,
+			dispatchSHA,
+			dispatchDigest,
+		))
+		loop.UpdatedAt = p.now().UnixMilli()
+
+		p.logReviewFeedbackDispatchDecision(
+			ctx,
+			loop,
+			reviewDispatchModeSkippedNoFindings,
+			reviewDispatchReasonNoFindings,
+			dispatchSHA,
+			dispatchDigest,
+			lastDispatchSHA,
+			lastDispatchDigest,
+			counts,
+			"",
+		)
+
+		return reviewDispatchOutcome{
+			Skipped:     true,
+			Mode:        reviewDispatchModeSkippedNoFindings,
+			Counts:      counts,
+			DispatchSHA: dispatchSHA,
+			Digest:      dispatchDigest,
+		}, nil
+	}
 
 	if loop.LastFeedbackDispatchAt > 0 &&
 		dispatchSHA == loop.LastFeedbackDispatchSHA &&
 		dispatchDigest == loop.LastFeedbackDigest {
-		loop.History = append(loop.History, kvstore.ReviewLoopEvent{
-			Phase:     loop.Phase,
-			Timestamp: time.Now().UnixMilli(),
-			Detail: fmt.Sprintf(
+		loop.History = append(loop.History, p.newReviewDispatchEvent(
+			loop.Phase,
+			fmt.Sprintf(
 				"Skipped duplicate review feedback dispatch (same SHA and digest; %s)",
 				formatReviewFeedbackCountSummary(counts.New, counts.Repeated, counts.Dismissed),
 			),
-		})
-		loop.UpdatedAt = time.Now().UnixMilli()
+			reviewDispatchModeSkippedIdempotent,
+			counts,
+			dispatchSHA,
+			dispatchDigest,
+		))
+		loop.UpdatedAt = p.now().UnixMilli()
 
 		p.logReviewFeedbackDispatchDecision(
+			ctx,
 			loop,
 			reviewDispatchModeSkippedIdempotent,
 			reviewDispatchReasonIdempotentSameState,
@@ -390,16 +851,133 @@ func (p *Plugin) dispatchReviewFeedback(loop *kvstore.ReviewLoop, pr ghPullReque
 		)
 
 		return reviewDispatchOutcome{
-			Skipped: true,
-			Mode:    reviewDispatchModeSkippedIdempotent,
-			Counts:  counts,
+			Skipped:     true,
+			Mode:        reviewDispatchModeSkippedIdempotent,
+			Counts:      counts,
+			DispatchSHA: dispatchSHA,
+			Digest:      dispatchDigest,
+		}, nil
+	}
+
+	minSeverity := p.getConfiguration().ReviewMinimumSeverity
+	if allFindingsBelowSeverity(dispatchable, minSeverity) {
+		loop.History = append(loop.History, p.newReviewDispatchEvent(
+			loop.Phase,
+			fmt.Sprintf(
+				"Skipped review feedback dispatch (all findings below %s severity; %s)",
+				minSeverity,
+				formatReviewFeedbackCountSummary(counts.New, counts.Repeated, counts.Dismissed),
+			),
+			reviewDispatchModeSkippedSeverity,
+			counts,
+			dispatchSHA,
+			dispatchDigest,
+		))
+		loop.UpdatedAt = p.now().UnixMilli()
+
+		p.logReviewFeedbackDispatchDecision(
+			ctx,
+			loop,
+			reviewDispatchModeSkippedSeverity,
+			reviewDispatchReasonBelowMinSeverity,
+			dispatchSHA,
+			dispatchDigest,
+			lastDispatchSHA,
+			lastDispatchDigest,
+			counts,
+			"",
+		)
+
+		return reviewDispatchOutcome{
+			Skipped:     true,
+			Mode:        reviewDispatchModeSkippedSeverity,
+			Counts:      counts,
+			DispatchSHA: dispatchSHA,
+			Digest:      dispatchDigest,
+		}, nil
+	}
+
+	if windowEnd := reviewLoopIdempotencyWindowEnd(loop); windowEnd > 0 && p.now().UnixMilli() < windowEnd {
+		coalesceDispatch(loop, pr)
+		loop.History = append(loop.History, p.newReviewDispatchEvent(
+			loop.Phase,
+			fmt.Sprintf(
+				"Held review feedback dispatch within the idempotency window until %s (%s)",
+				time.UnixMilli(windowEnd).UTC().Format("15:04 MST"),
+				formatReviewFeedbackCountSummary(counts.New, counts.Repeated, counts.Dismissed),
+			),
+			reviewDispatchModeCoalesced,
+			counts,
+			dispatchSHA,
+			dispatchDigest,
+		))
+		loop.UpdatedAt = p.now().UnixMilli()
+
+		p.logReviewFeedbackDispatchDecision(
+			ctx,
+			loop,
+			reviewDispatchModeCoalesced,
+			reviewDispatchReasonIdempotencyWindow,
+			dispatchSHA,
+			dispatchDigest,
+			lastDispatchSHA,
+			lastDispatchDigest,
+			counts,
+			"",
+		)
+
+		return reviewDispatchOutcome{
+			Skipped:     true,
+			Mode:        reviewDispatchModeCoalesced,
+			Counts:      counts,
+			DispatchSHA: dispatchSHA,
+			Digest:      dispatchDigest,
+		}, nil
+	}
+
+	if p.inQuietHours(p.now()) {
+		now := p.now().UnixMilli()
+		deferDispatchForQuietHours(loop, pr, now)
+		loop.History = append(loop.History, p.newReviewDispatchEvent(
+			loop.Phase,
+			fmt.Sprintf(
+				"Deferred review feedback dispatch until quiet hours end (%s)",
+				formatReviewFeedbackCountSummary(counts.New, counts.Repeated, counts.Dismissed),
+			),
+			reviewDispatchModeDeferred,
+			counts,
+			dispatchSHA,
+			dispatchDigest,
+		))
+		loop.UpdatedAt = now
+
+		p.logReviewFeedbackDispatchDecision(
+			ctx,
+			loop,
+			reviewDispatchModeDeferred,
+			reviewDispatchReasonQuietHours,
+			dispatchSHA,
+			dispatchDigest,
+			lastDispatchSHA,
+			lastDispatchDigest,
+			counts,
+			"",
+		)
+
+		return reviewDispatchOutcome{
+			Skipped:     true,
+			Mode:        reviewDispatchModeDeferred,
+			Counts:      counts,
+			DispatchSHA: dispatchSHA,
+			Digest:      dispatchDigest,
 		}, nil
 	}
 
-	followupPrompt := formatFindingsForCursorFollowup(loop, pr, classification.Dispatchable)
+	followupPrompt := p.buildReviewFollowupPrompt(loop, pr, dispatchable)
 	if strings.TrimSpace(followupPrompt) == "" {
 		followupPrompt = defaultReviewLoopFeedbackText()
 	}
+	followupPrompt = p.redactSecretsForCursor(followupPrompt, "review_feedback")
 
 	var primaryErr error
 	decisionReason := reviewDispatchReasonDirectFailed
@@ -408,23 +986,36 @@ func (p *Plugin) dispatchReviewFeedback(loop *kvstore.ReviewLoop, pr ghPullReque
 		decisionReason = reviewDispatchReasonCursorClientNil
 		primaryErr = fmt.Errorf("cursor client is not configured")
 	} else {
-		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		p.saveDispatchCheckpoint(loop, dispatchSHA, dispatchDigest, followupPrompt)
+
+		reqCtx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
 
-		_, primaryErr = cursorClient.AddFollowup(ctx, loop.AgentRecordID, cursor.FollowupRequest{
+		_, primaryErr = cursorClient.AddFollowup(reqCtx, loop.AgentRecordID, cursor.FollowupRequest{
 			Prompt: cursor.Prompt{
 				Text: followupPrompt,
 			},
 		})
 		if primaryErr != nil {
 			decisionReason = reviewDispatchReasonAddFollowupError
+			clearDispatchCheckpoint(loop)
 		}
 	}
 
 	if primaryErr == nil {
-		applyReviewFeedbackDispatchTracking(loop, dispatchSHA, dispatchDigest)
+		p.applyReviewFeedbackDispatchTracking(loop, dispatchSHA, dispatchDigest)
+		markHeldBackFindings(loop, heldBack)
+		p.acknowledgeDispatchedFindings(loop, dispatchable)
+		if len(heldBack) > 0 {
+			loop.History = append(loop.History, kvstore.ReviewLoopEvent{
+				Phase:     loop.Phase,
+				Timestamp: p.now().UnixMilli(),
+				Detail:    fmt.Sprintf("Held back %d lower-priority finding(s) for the next iteration", len(heldBack)),
+			})
+		}
 
 		p.logReviewFeedbackDispatchDecision(
+			ctx,
 			loop,
 			reviewDispatchModeDirect,
 			reviewDispatchReasonDirectSuccess,
@@ -437,28 +1028,74 @@ func (p *Plugin) dispatchReviewFeedback(loop *kvstore.ReviewLoop, pr ghPullReque
 		)
 
 		return reviewDispatchOutcome{
-			Dispatched: true,
-			Mode:       reviewDispatchModeDirect,
-			Counts:     counts,
+			Dispatched:  true,
+			Mode:        reviewDispatchModeDirect,
+			Counts:      counts,
+			DispatchSHA: dispatchSHA,
+			Digest:      dispatchDigest,
 		}, nil
 	}
 
-	loop.History = append(loop.History, kvstore.ReviewLoopEvent{
-		Phase:     loop.Phase,
-		Timestamp: time.Now().UnixMilli(),
-		Detail: fmt.Sprintf(
-			"Failed to dispatch review feedback; manual intervention required (%s)",
-			formatReviewFeedbackCountSummary(counts.New, counts.Repeated, counts.Dismissed),
-		),
-	})
-	loop.UpdatedAt = time.Now().UnixMilli()
+	if cursor.IsRateLimited(primaryErr) && loop.RateLimitRetryAttempts < reviewRateLimitMaxRetries {
+		// Cursor is throttling follow-ups; queue this one for the poller to
+		// retry once the rate limit window has passed.
+		now := p.now()
+		deferDispatchForRateLimit(loop, pr, now)
+		loop.History = append(loop.History, p.newReviewDispatchEvent(
+			loop.Phase,
+			fmt.Sprintf(
+				"Cursor rate limited the review feedback follow-up; retrying in %s (attempt %d/%d)",
+				reviewRateLimitRetryWindow,
+				loop.RateLimitRetryAttempts,
+				reviewRateLimitMaxRetries,
+			),
+			reviewDispatchModeDeferredRateLimit,
+			counts,
+			dispatchSHA,
+			dispatchDigest,
+		))
+		loop.UpdatedAt = now.UnixMilli()
+
+		p.logReviewFeedbackDispatchDecision(
+			ctx,
+			loop,
+			reviewDispatchModeDeferredRateLimit,
+			reviewDispatchReasonRateLimited,
+			dispatchSHA,
+			dispatchDigest,
+			lastDispatchSHA,
+			lastDispatchDigest,
+			counts,
+			primaryErr.Error(),
+		)
+
+		return reviewDispatchOutcome{
+			Skipped:     true,
+			Mode:        reviewDispatchModeDeferredRateLimit,
+			Counts:      counts,
+			DispatchSHA: dispatchSHA,
+			Digest:      dispatchDigest,
+		}, nil
+	}
 
 	errorPrimary := primaryErr.Error()
+	if cursor.IsRateLimited(primaryErr) {
+		errorPrimary = fmt.Sprintf("still rate limited after %d retries: %s", loop.RateLimitRetryAttempts, errorPrimary)
+	}
+	resetRateLimitRetry(loop)
+	p.markReviewLoopError(loop, fmt.Sprintf(
+		"Failed to dispatch review feedback; manual intervention required (%s): %s",
+		formatReviewFeedbackCountSummary(counts.New, counts.Repeated, counts.Dismissed),
+		errorPrimary,
+	))
+	setReviewDispatchEventFields(&loop.History[len(loop.History)-1], reviewDispatchModeFailed, counts, dispatchSHA, dispatchDigest)
+
 	if decisionReason == "" {
 		decisionReason = reviewDispatchReasonDirectFailed
 	}
 
 	p.logReviewFeedbackDispatchDecision(
+		ctx,
 		loop,
 		reviewDispatchModeFailed,
 		decisionReason,
@@ -471,22 +1108,125 @@ func (p *Plugin) dispatchReviewFeedback(loop *kvstore.ReviewLoop, pr ghPullReque
 	)
 
 	return reviewDispatchOutcome{
-		Failed: true,
-		Mode:   reviewDispatchModeFailed,
-		Counts: counts,
+		Failed:      true,
+		Mode:        reviewDispatchModeFailed,
+		Counts:      counts,
+		DispatchSHA: dispatchSHA,
+		Digest:      dispatchDigest,
 	}, nil
 }
 
-func applyReviewFeedbackDispatchTracking(loop *kvstore.ReviewLoop, dispatchSHA, dispatchDigest string) {
-	now := time.Now().UnixMilli()
+func (p *Plugin) applyReviewFeedbackDispatchTracking(loop *kvstore.ReviewLoop, dispatchSHA, dispatchDigest string) {
+	now := p.now().UnixMilli()
 	loop.LastFeedbackDispatchAt = now
 	loop.LastFeedbackDispatchSHA = dispatchSHA
 	loop.LastFeedbackDigest = dispatchDigest
 	loop.FeedbackCursor = fmt.Sprintf("%d", now)
+	clearDispatchCheckpoint(loop)
+	clearGitHubRetry(loop)
+	resetRateLimitRetry(loop)
+	clearCoalescedDispatch(loop)
+}
+
+// saveDispatchCheckpoint persists an in-progress dispatch marker before the
+// follow-up is sent so a restart between AddFollowup and the tracking update
+// cannot cause a duplicate dispatch. The prompt is left untouched; only its
+// fingerprint is recorded. Persist failures are logged and do not block
+// dispatch.
+func (p *Plugin) saveDispatchCheckpoint(loop *kvstore.ReviewLoop, dispatchSHA, dispatchDigest, prompt string) {
+	now := p.now().UnixMilli()
+	loop.PendingDispatchID = fmt.Sprintf("%s-%d", loop.ID, now)
+	loop.PendingDispatchAt = now
+	loop.PendingDispatchSHA = dispatchSHA
+	loop.PendingDispatchDigest = dispatchDigest
+	loop.PendingDispatchPromptHash = dispatchPromptHash(prompt)
+
+	if err := p.kvstore.SaveReviewLoop(loop); err != nil {
+		p.API.LogWarn("Failed to persist review dispatch checkpoint",
+			"error", err.Error(),
+			"review_loop_id", loop.ID,
+		)
+	}
+}
+
+func clearDispatchCheckpoint(loop *kvstore.ReviewLoop) {
+	loop.PendingDispatchID = ""
+	loop.PendingDispatchAt = 0
+	loop.PendingDispatchSHA = ""
+	loop.PendingDispatchDigest = ""
+	loop.PendingDispatchPromptHash = ""
+}
+
+// reconcilePendingDispatch resolves a checkpoint left behind by an interrupted
+// dispatch. If the agent conversation shows the follow-up was delivered, the
+// checkpoint is promoted to the recorded dispatch and true is returned; if it
+// was not, the checkpoint is discarded so dispatch can proceed. When the
+// conversation cannot be checked an error is returned and the checkpoint is
+// left pending.
+func (p *Plugin) reconcilePendingDispatch(loop *kvstore.ReviewLoop) (bool, error) {
+	cursorClient := p.getCursorClient()
+	if cursorClient == nil {
+		return false, fmt.Errorf("cursor client is not configured")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	conversation, err := cursorClient.GetConversation(ctx, loop.AgentRecordID)
+	if err != nil {
+		return false, fmt.Errorf("failed to fetch conversation for dispatch checkpoint: %w", err)
+	}
+
+	if !conversationContainsCheckpoint(conversation, loop) {
+		clearDispatchCheckpoint(loop)
+		return false, nil
+	}
+
+	loop.LastFeedbackDispatchAt = loop.PendingDispatchAt
+	loop.LastFeedbackDispatchSHA = loop.PendingDispatchSHA
+	loop.LastFeedbackDigest = loop.PendingDispatchDigest
+	loop.FeedbackCursor = fmt.Sprintf("%d", loop.PendingDispatchAt)
+	clearDispatchCheckpoint(loop)
+	return true, nil
 }
 
-func (p *Plugin) logReviewFeedbackCollectionSummary(loop *kvstore.ReviewLoop, dispatchSHA string, telemetry reviewFeedbackTelemetry) {
-	p.logDebug("Review feedback collection summary",
+// dispatchPromptHash fingerprints a follow-up prompt for checkpoint matching.
+func dispatchPromptHash(prompt string) string {
+	normalized := strings.TrimSpace(strings.ReplaceAll(prompt, "\r\n", "\n"))
+	if normalized == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+// conversationContainsCheckpoint reports whether the loop's checkpointed
+// follow-up appears as a user message in the conversation. Checkpoints saved
+// before prompts were fingerprinted are matched by the ID that used to be
+// appended to the prompt.
+func conversationContainsCheckpoint(conversation *cursor.Conversation, loop *kvstore.ReviewLoop) bool {
+	if conversation == nil {
+		return false
+	}
+	for _, message := range conversation.Messages {
+		if message.Type != "user_message" {
+			continue
+		}
+		if loop.PendingDispatchPromptHash != "" {
+			if dispatchPromptHash(message.Text) == loop.PendingDispatchPromptHash {
+				return true
+			}
+			continue
+		}
+		if loop.PendingDispatchID != "" && strings.Contains(message.Text, loop.PendingDispatchID) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Plugin) logReviewFeedbackCollectionSummary(ctx context.Context, loop *kvstore.ReviewLoop, dispatchSHA string, telemetry reviewFeedbackTelemetry) {
+	p.logDebugContext(ctx, "Review feedback collection summary",
 		"review_loop_id", loop.ID,
 		"agent_record_id", loop.AgentRecordID,
 		"phase", loop.Phase,
@@ -539,6 +1279,7 @@ func (p *Plugin) logReviewFeedbackCandidateDropped(
 }
 
 func (p *Plugin) logReviewFeedbackDispatchDecision(
+	ctx context.Context,
 	loop *kvstore.ReviewLoop,
 	dispatchMode string,
 	decisionReason string,
@@ -568,11 +1309,11 @@ func (p *Plugin) logReviewFeedbackDispatchDecision(
 	if errorPrimary != "" {
 		debugFields = append(debugFields, "error_primary", errorPrimary)
 	}
-	p.logDebug("Review feedback dispatch decision", debugFields...)
+	p.logDebugContext(ctx, "Review feedback dispatch decision", debugFields...)
 
 	switch dispatchMode {
 	case reviewDispatchModeFailed:
-		p.API.LogError("Review feedback dispatch decision",
+		p.logger(ctx).LogError("Review feedback dispatch decision",
 			"review_loop_id", loop.ID,
 			"dispatch_mode", dispatchMode,
 			"decision_reason", decisionReason,
@@ -601,20 +1342,41 @@ func (p *Plugin) collectReviewFeedbackBundle(loop *kvstore.ReviewLoop) (reviewFe
 		return reviewFeedbackClassification{}, reviewFeedbackTelemetry{}, "", err
 	}
 
+	now := p.now().UnixMilli()
+	ignorePaths := p.getConfiguration().ParseReviewLoopIgnorePaths()
+	changedLines := p.loadReviewLoopChangedLines(loop)
+	minTextLength := p.getConfiguration().MinActionableTextLength
 	normalized := make([]reviewFeedbackCandidate, 0, len(candidates))
 	for _, candidate := range candidates {
 		candidate = normalizeFeedbackCandidate(candidate)
+		if reviewPathIgnored(ignorePaths, candidate.Path) {
+			route := resolveReviewerExtractionRoute(candidate)
+			p.logReviewFeedbackCandidateDropped(loop, candidate, route, reviewerExtractionDropReasonIgnoredPath)
+			recordDroppedCandidate(loop, candidate, route, reviewerExtractionDropReasonIgnoredPath, now)
+			continue
+		}
+		if changedLines != nil && changedLines.onUnchangedLine(candidate) {
+			route := resolveReviewerExtractionRoute(candidate)
+			p.logReviewFeedbackCandidateDropped(loop, candidate, route, reviewerExtractionDropReasonUnchangedLine)
+			recordDroppedCandidate(loop, candidate, route, reviewerExtractionDropReasonUnchangedLine, now)
+			continue
+		}
 		actionableText, route, dropReason := extractCandidateActionableText(candidate)
+		if dropReason == "" && actionableTextTooShort(route, actionableText, minTextLength) {
+			actionableText, dropReason = "", reviewerExtractionDropReasonTooShort
+		}
 		candidate.ActionableText = actionableText
 		if candidate.ActionableText == "" {
 			p.logReviewFeedbackCandidateDropped(loop, candidate, route, dropReason)
+			recordDroppedCandidate(loop, candidate, route, dropReason, now)
 			continue
 		}
 
 		normalized = append(normalized, candidate)
 	}
 
-	classification := classifyFeedback(loop, normalized, time.Now().UnixMilli())
+	classification := classifyFeedback(loop, normalized, now)
+	p.resolveFindingThreads(loop, classification.Resolved)
 	telemetry := summarizeReviewFeedbackTelemetry(candidates, classification)
 	return classification, telemetry, formatFindingsForCursorComment(classification.Dispatchable), nil
 }
@@ -624,9 +1386,9 @@ func (p *Plugin) transitionToHumanReview(loop *kvstore.ReviewLoop) error {
 	loop.Phase = kvstore.ReviewPhaseHumanReview
 	loop.History = append(loop.History, kvstore.ReviewLoopEvent{
 		Phase:     kvstore.ReviewPhaseHumanReview,
-		Timestamp: time.Now().UnixMilli(),
+		Timestamp: p.now().UnixMilli(),
 	})
-	loop.UpdatedAt = time.Now().UnixMilli()
+	loop.UpdatedAt = p.now().UnixMilli()
 
 	// TODO: Uncomment when ready for production use.
 	// config := p.getConfiguration()
@@ -671,6 +1433,7 @@ func (p *Plugin) updateReviewLoopInlineStatus(loop *kvstore.ReviewLoop) {
 		return
 	}
 
+	resolved, outstanding := reviewFindingTally(loop.Findings)
 	att := attachments.BuildFinishedWithReviewStatusAttachment(
 		record.CursorAgentID,
 		record.Repository,
@@ -680,26 +1443,64 @@ func (p *Plugin) updateReviewLoopInlineStatus(loop *kvstore.ReviewLoop) {
 		record.PrURL,
 		loop.Phase,
 		loop.Iteration,
+		resolved,
+		outstanding,
 	)
 
 	p.updateBotReplyWithAttachment(record.BotReplyPostID, att)
 }
 
-// postReviewLoopCompletion posts a terminal completion attachment as a new
-// thread message. Only called for terminal review loop states.
+// maybeWarnIterationThreshold posts a one-time heads-up when the loop's
+// iteration count reaches the configured warning threshold. Call it before
+// saving the loop so IterationWarningSent is persisted with the iteration.
+func (p *Plugin) maybeWarnIterationThreshold(loop *kvstore.ReviewLoop) {
+	config := p.getConfiguration()
+	threshold := config.ReviewIterationWarning
+	if threshold <= 0 || threshold >= config.MaxReviewIterations {
+		return
+	}
+	if loop.IterationWarningSent || loop.Iteration < threshold {
+		return
+	}
+
+	loop.IterationWarningSent = true
+	loop.History = append(loop.History, kvstore.ReviewLoopEvent{
+		Phase:     loop.Phase,
+		Timestamp: p.now().UnixMilli(),
+		Detail:    fmt.Sprintf("Iteration warning: %d of %d iterations used", loop.Iteration, config.MaxReviewIterations),
+	})
+	p.postReviewLoopCompletion(loop, attachments.BuildIterationWarningAttachment(
+		loop.PRURL,
+		loop.Iteration,
+		config.MaxReviewIterations,
+	))
+}
+
+// postReviewLoopCompletion posts a review loop attachment as a new thread
+// message. Used for terminal review loop states and the iteration warning.
+// During quiet hours the attachment is held for the end-of-window digest, and
+// when the loop owner has left the channel it is sent to their DM instead.
+// Loops started by a HITL workflow also show its original prompt and plan.
 func (p *Plugin) postReviewLoopCompletion(loop *kvstore.ReviewLoop, attachment *model.SlackAttachment) {
 	if loop.RootPostID == "" {
 		return
 	}
+	p.addReviewLoopWorkflowContext(loop, attachment)
+	if p.holdNotificationForQuietHours(loop, attachment) {
+		return
+	}
+	if p.reviewLoopOwnerLeftChannel(loop) && p.postReviewLoopOwnerDM(loop, attachment) {
+		return
+	}
 
 	post := &model.Post{
-		UserId:    p.getBotUserID(),
+		UserId:    p.botUserIDForChannel(loop.ChannelID),
 		ChannelId: loop.ChannelID,
 		RootId:    loop.RootPostID,
 	}
 	model.ParseSlackAttachment(post, []*model.SlackAttachment{attachment})
 
-	_, appErr := p.API.CreatePost(post)
+	_, appErr := p.API.CreatePost(p.decorateBotPost(post))
 	if appErr != nil {
 		p.API.LogError("Failed to post review loop completion",
 			"error", appErr.Error(),
@@ -708,48 +1509,125 @@ func (p *Plugin) postReviewLoopCompletion(loop *kvstore.ReviewLoop, attachment *
 	}
 }
 
-// publishReviewLoopChange publishes a WebSocket event when a review loop phase changes.
+// addReviewLoopWorkflowContext adds the original prompt and a link to the
+// approved plan to a notification for a loop started by a HITL workflow.
+func (p *Plugin) addReviewLoopWorkflowContext(loop *kvstore.ReviewLoop, attachment *model.SlackAttachment) {
+	if loop.WorkflowID == "" {
+		return
+	}
+	workflow, err := p.kvstore.GetWorkflow(loop.WorkflowID)
+	if err != nil || workflow == nil {
+		if err != nil {
+			p.API.LogWarn("Failed to load workflow for review loop notification",
+				"error", err.Error(),
+				"review_loop_id", loop.ID,
+				"workflow_id", loop.WorkflowID,
+			)
+		}
+		return
+	}
+
+	planURL := ""
+	if workflow.ApprovedPlan != "" && workflow.PlanPostID != "" {
+		planURL = p.getPostPermalink(workflow.PlanPostID)
+	}
+	attachments.AddWorkflowContextFields(attachment, workflow.OriginalPrompt, planURL)
+}
+
+// markReviewLoopError moves the loop into the error phase and records the
+// failure detail in its history. The caller is responsible for persisting the
+// loop and calling notifyReviewLoopError.
+func (p *Plugin) markReviewLoopError(loop *kvstore.ReviewLoop, detail string) {
+	now := p.now().UnixMilli()
+	loop.Phase = kvstore.ReviewPhaseError
+	loop.History = append(loop.History, kvstore.ReviewLoopEvent{
+		Phase:     kvstore.ReviewPhaseError,
+		Timestamp: now,
+		Detail:    detail,
+	})
+	loop.UpdatedAt = now
+}
+
+// notifyReviewLoopError surfaces an errored loop: it refreshes the inline
+// status, publishes the phase change, posts a failure attachment to the
+// thread, and swaps the trigger post reaction to an x.
+func (p *Plugin) notifyReviewLoopError(loop *kvstore.ReviewLoop, detail string) {
+	p.updateReviewLoopInlineStatus(loop)
+	p.publishReviewLoopChange(loop)
+	p.postReviewLoopCompletion(loop, attachments.BuildReviewFailedAttachment(detail))
+	p.swapReaction(loop.ChannelID, loop.TriggerPostID, "eyes", "x")
+}
+
+// enterReviewErrorPhase moves the loop into the error phase after an
+// unrecoverable condition. The save is best-effort since the condition is
+// often itself a storage failure; the thread is notified either way.
+func (p *Plugin) enterReviewErrorPhase(loop *kvstore.ReviewLoop, detail string) {
+	p.markReviewLoopError(loop, detail)
+	if err := p.kvstore.SaveReviewLoop(loop); err != nil {
+		p.API.LogError("Failed to save errored review loop",
+			"error", err.Error(),
+			"review_loop_id", loop.ID,
+		)
+	}
+	p.notifyReviewLoopError(loop, detail)
+}
+
+// publishReviewLoopChange publishes a WebSocket event when a review loop phase changes
+// and forwards it to any configured phase webhooks.
 func (p *Plugin) publishReviewLoopChange(loop *kvstore.ReviewLoop) {
+	payload := map[string]any{
+		"review_loop_id":  loop.ID,
+		"agent_record_id": loop.AgentRecordID,
+		"phase":           loop.Phase,
+		"iteration":       fmt.Sprintf("%d", loop.Iteration),
+		"pr_url":          loop.PRURL,
+		"updated_at":      fmt.Sprintf("%d", loop.UpdatedAt),
+	}
 	p.API.PublishWebSocketEvent(
 		"review_loop_changed",
-		map[string]any{
-			"review_loop_id":  loop.ID,
-			"agent_record_id": loop.AgentRecordID,
-			"phase":           loop.Phase,
-			"iteration":       fmt.Sprintf("%d", loop.Iteration),
-			"pr_url":          loop.PRURL,
-			"updated_at":      fmt.Sprintf("%d", loop.UpdatedAt),
-		},
+		payload,
 		&model.WebsocketBroadcast{UserId: loop.UserID},
 	)
+	p.notifyPhaseWebhooks("review_loop_changed", payload)
 }
 
 // handleHumanReviewFeedback processes human review submissions in human_review
 // phase and triggers a cursor_fixing iteration only for changes_requested.
-func (p *Plugin) handleHumanReviewFeedback(loop *kvstore.ReviewLoop, review ghReview, pr ghPullRequest) error {
+// With DisableHumanReviewFixing set, the review is left to the humans and only
+// the regular review notification is posted.
+func (p *Plugin) handleHumanReviewFeedback(ctx context.Context, loop *kvstore.ReviewLoop, review ghReview, pr ghPullRequest) error {
 	state := strings.ToLower(strings.TrimSpace(review.State))
 	if state != reviewStateChangesRequested {
 		return nil
 	}
 
 	config := p.getConfiguration()
+	if config.DisableHumanReviewFixing {
+		p.logger(ctx).LogDebug("Human review fixing is disabled; not dispatching changes requested to Cursor",
+			"review_loop_id", loop.ID,
+			"reviewer", review.User.Login,
+		)
+		return nil
+	}
+	if p.holdReviewForGlobalPause(loop, review, pr) {
+		return nil
+	}
+
 	if loop.Iteration >= config.MaxReviewIterations {
 		loop.Phase = kvstore.ReviewPhaseMaxIterations
 		loop.History = append(loop.History, kvstore.ReviewLoopEvent{
 			Phase:     kvstore.ReviewPhaseMaxIterations,
-			Timestamp: time.Now().UnixMilli(),
+			Timestamp: p.now().UnixMilli(),
 			Detail:    fmt.Sprintf("Reached max iterations (%d)", config.MaxReviewIterations),
 		})
-		loop.UpdatedAt = time.Now().UnixMilli()
+		loop.UpdatedAt = p.now().UnixMilli()
 		_ = p.kvstore.SaveReviewLoop(loop)
 
 		p.updateReviewLoopInlineStatus(loop)
 		p.publishReviewLoopChange(loop)
-		p.postReviewLoopCompletion(loop, attachments.BuildMaxIterationsAttachment(
-			loop.PRURL,
-			config.MaxReviewIterations,
-		))
-		p.swapReaction(loop.TriggerPostID, "eyes", "warning")
+		p.postReviewLoopCompletion(loop, p.styleReviewLoopNotification(notificationEventReviewLoopMaxIterations, loop,
+			attachments.BuildMaxIterationsAttachment(loop.PRURL, config.MaxReviewIterations)))
+		p.swapTerminalReaction(loop.ChannelID, loop.TriggerPostID, "eyes", "warning")
 		return nil
 	}
 
@@ -757,15 +1635,22 @@ func (p *Plugin) handleHumanReviewFeedback(loop *kvstore.ReviewLoop, review ghRe
 		loop.LastCommitSHA = pr.Head.SHA
 	}
 
-	outcome, err := p.dispatchReviewFeedback(loop, pr)
+	outcome, err := p.dispatchReviewFeedback(ctx, loop, pr)
 	if err != nil {
-		p.API.LogError("Failed to dispatch human review feedback",
+		p.logger(ctx).LogError("Failed to dispatch human review feedback",
 			"error", err.Error(),
 			"review_loop_id", loop.ID,
 		)
 		return err
 	}
-	if outcome.Skipped || outcome.Failed {
+	if outcome.Failed {
+		if err := p.kvstore.SaveReviewLoop(loop); err != nil {
+			return fmt.Errorf("failed to save review loop after dispatch outcome: %w", err)
+		}
+		p.notifyReviewLoopError(loop, loop.History[len(loop.History)-1].Detail)
+		return nil
+	}
+	if outcome.Skipped {
 		if err := p.kvstore.SaveReviewLoop(loop); err != nil {
 			return fmt.Errorf("failed to save review loop after dispatch outcome: %w", err)
 		}
@@ -781,22 +1666,26 @@ func (p *Plugin) handleHumanReviewFeedback(loop *kvstore.ReviewLoop, review ghRe
 		"",
 		outcome.Counts,
 	)
-	if outcome.Mode == reviewDispatchModeDirect {
+	switch outcome.Mode {
+	case reviewDispatchModeDirect:
 		detail = formatReviewDispatchHistoryDetail(
 			fmt.Sprintf("Human feedback iteration %d", loop.Iteration+1),
 			"direct follow-up dispatched",
 			outcome.Counts,
 		)
+	case reviewDispatchModeRecovered:
+		detail = formatReviewDispatchHistoryDetail(
+			fmt.Sprintf("Human feedback iteration %d", loop.Iteration+1),
+			"recovered interrupted dispatch",
+			outcome.Counts,
+		)
 	}
 
 	loop.Phase = kvstore.ReviewPhaseCursorFixing
 	loop.Iteration++
-	loop.History = append(loop.History, kvstore.ReviewLoopEvent{
-		Phase:     kvstore.ReviewPhaseCursorFixing,
-		Timestamp: time.Now().UnixMilli(),
-		Detail:    detail,
-	})
-	loop.UpdatedAt = time.Now().UnixMilli()
+	loop.History = append(loop.History, p.dispatchHistoryEvent(outcome, kvstore.ReviewPhaseCursorFixing, detail))
+	p.maybeWarnIterationThreshold(loop)
+	loop.UpdatedAt = p.now().UnixMilli()
 	if err := p.kvstore.SaveReviewLoop(loop); err != nil {
 		return fmt.Errorf("failed to save review loop: %w", err)
 	}
@@ -807,15 +1696,37 @@ func (p *Plugin) handleHumanReviewFeedback(loop *kvstore.ReviewLoop, review ghRe
 }
 
 // handleHumanReviewApproval transitions the review loop to complete when a human
-// reviewer approves the PR.
+// reviewer approves the PR. When ReviewLoopRequireAIGate is set and no AI
+// reviewer has approved yet, the approval is only recorded in the history.
+// While review loops are globally paused the approval is held on the loop.
 func (p *Plugin) handleHumanReviewApproval(loop *kvstore.ReviewLoop, reviewer string) error {
+	review := ghReview{State: reviewStateApproved}
+	review.User.Login = reviewer
+	if p.holdReviewForGlobalPause(loop, review, ghPullRequest{HTMLURL: loop.PRURL, Number: loop.PRNumber}) {
+		return nil
+	}
+
+	if p.getConfiguration().ReviewLoopRequireAIGate && !reviewLoopAIGatePassed(loop) {
+		loop.History = append(loop.History, kvstore.ReviewLoopEvent{
+			Phase:     loop.Phase,
+			Timestamp: p.now().UnixMilli(),
+			Detail:    fmt.Sprintf("Approved by %s before AI approval; waiting for the AI gate", reviewer),
+		})
+		loop.UpdatedAt = p.now().UnixMilli()
+		if err := p.kvstore.SaveReviewLoop(loop); err != nil {
+			return fmt.Errorf("failed to save review loop after early human approval: %w", err)
+		}
+		p.publishReviewLoopChange(loop)
+		return nil
+	}
+
 	loop.Phase = kvstore.ReviewPhaseComplete
 	loop.History = append(loop.History, kvstore.ReviewLoopEvent{
 		Phase:     kvstore.ReviewPhaseComplete,
-		Timestamp: time.Now().UnixMilli(),
+		Timestamp: p.now().UnixMilli(),
 		Detail:    fmt.Sprintf("Approved by %s", reviewer),
 	})
-	loop.UpdatedAt = time.Now().UnixMilli()
+	loop.UpdatedAt = p.now().UnixMilli()
 
 	if err := p.kvstore.SaveReviewLoop(loop); err != nil {
 		return fmt.Errorf("failed to save completed review loop: %w", err)
@@ -826,12 +1737,27 @@ func (p *Plugin) handleHumanReviewApproval(loop *kvstore.ReviewLoop, reviewer st
 		loop.PRURL,
 		reviewer,
 	))
-	p.addReaction(loop.TriggerPostID, "rocket")
+	p.swapTerminalReaction(loop.ChannelID, loop.TriggerPostID, "", "rocket")
 	p.publishReviewLoopChange(loop)
 
 	return nil
 }
 
+// reviewLoopAIGatePassed reports whether an AI reviewer has approved the PR
+// since the AI review gate was last reopened for new findings.
+func reviewLoopAIGatePassed(loop *kvstore.ReviewLoop) bool {
+	for i := len(loop.History) - 1; i >= 0; i-- {
+		event := loop.History[i]
+		if event.Phase == kvstore.ReviewPhaseApproved {
+			return true
+		}
+		if event.Kind == reviewEventKindAIGateReopened {
+			return false
+		}
+	}
+	return false
+}
+
 // reviewLoopAwaitDetail returns a human-readable detail string for the
 // awaiting_review history entry.
 func reviewLoopAwaitDetail(bots []string) string {
@@ -841,14 +1767,20 @@ func reviewLoopAwaitDetail(bots []string) string {
 	return fmt.Sprintf("Requested: %s", strings.Join(bots, ", "))
 }
 
-// isAIReviewerBot checks if the given GitHub username matches a configured AI reviewer bot.
-func (p *Plugin) isAIReviewerBot(login string) bool {
+// isAIReviewerBot checks if the given GitHub username matches a configured AI
+// reviewer bot. A bot scoped to paths in AIReviewerBotPaths only counts as AI
+// for inline comments on those paths; pass an empty path for comments that are
+// not tied to a file.
+func (p *Plugin) isAIReviewerBot(login, path string) bool {
 	config := p.getConfiguration()
 	botUsernames := config.ParseAIReviewerBots()
 	loginLower := strings.ToLower(login)
 	for _, bot := range botUsernames {
 		if strings.ToLower(bot) == loginLower {
-			return true
+			if path == "" {
+				return true
+			}
+			return reviewerBotCoversPath(config.ParseAIReviewerBotPaths(), login, path)
 		}
 	}
 	return false
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/attachments"
+	"github.com/mattermost/mattermost-plugin-cursor/server/ghclient"
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+// autoMergeApprovedPR merges the PR of a loop the AI reviewer just approved,
+// when AutoMergeOnApproval is enabled and CI on the head commit is green. It
+// reports whether the PR was merged and the loop completed. Otherwise the
+// reason is recorded in the loop history for the caller to save, GitHub
+// merge rejections are also posted to the thread, and the loop carries on to
+// human review. A merge held on CI is retried by the poller.
+func (p *Plugin) autoMergeApprovedPR(loop *kvstore.ReviewLoop, pr ghPullRequest) bool {
+	config := p.getConfiguration()
+	if !config.AutoMergeOnApproval {
+		return false
+	}
+	ghClient := p.getGitHubClient()
+	if ghClient == nil {
+		return false
+	}
+
+	headSHA := strings.TrimSpace(pr.Head.SHA)
+	if headSHA == "" {
+		headSHA = strings.TrimSpace(loop.LastCommitSHA)
+	}
+	if headSHA == "" {
+		p.appendAutoMergeEvent(loop, "Auto-merge skipped: the PR head commit is unknown")
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	state, err := ghClient.GetChecksState(ctx, loop.Owner, loop.Repo, headSHA)
+	if err != nil {
+		p.API.LogWarn("Failed to read CI state for auto-merge",
+			"error", err.Error(),
+			"review_loop_id", loop.ID,
+		)
+		p.appendAutoMergeEvent(loop, "Auto-merge skipped: could not read the CI state")
+		loop.AutoMergeHeldSHA = headSHA
+		return false
+	}
+	if state != ghclient.ChecksStateSuccess {
+		p.appendAutoMergeEvent(loop, fmt.Sprintf("Auto-merge held: CI on %s is %s", shortSHA(headSHA), state))
+		loop.AutoMergeHeldSHA = headSHA
+		return false
+	}
+
+	return p.mergeApprovedPR(ctx, ghClient, loop, headSHA)
+}
+
+// mergeApprovedPR merges the PR at headSHA and completes the loop. A GitHub
+// rejection is recorded in the loop history for the caller to save and posted
+// to the thread, and false is returned.
+func (p *Plugin) mergeApprovedPR(ctx context.Context, ghClient ghclient.Client, loop *kvstore.ReviewLoop, headSHA string) bool {
+	loop.AutoMergeHeldSHA = ""
+	method := p.getConfiguration().GetAutoMergeMethod()
+	if err := ghClient.MergePR(ctx, loop.Owner, loop.Repo, loop.PRNumber, headSHA, method); err != nil {
+		detail := ghclient.ErrorMessage(err)
+		p.API.LogWarn("Failed to auto-merge approved PR",
+			"error", err.Error(),
+			"review_loop_id", loop.ID,
+		)
+		p.appendAutoMergeEvent(loop, fmt.Sprintf("Auto-merge failed: %s", detail))
+		p.postReviewLoopCompletion(loop, p.styleReviewLoopNotification(notificationEventReviewLoopMergeFailed, loop,
+			attachments.BuildAutoMergeFailedAttachment(loop.PRURL, detail)))
+		return false
+	}
+
+	now := p.now().UnixMilli()
+	loop.Phase = kvstore.ReviewPhaseComplete
+	loop.History = append(loop.History, kvstore.ReviewLoopEvent{
+		Phase:     kvstore.ReviewPhaseComplete,
+		Timestamp: now,
+		Detail:    fmt.Sprintf("Auto-merged (%s) after AI approval with passing CI", method),
+	})
+	loop.UpdatedAt = now
+	if err := p.kvstore.SaveReviewLoop(loop); err != nil {
+		p.API.LogError("Failed to save auto-merged review loop",
+			"error", err.Error(),
+			"review_loop_id", loop.ID,
+		)
+	}
+
+	p.updateReviewLoopInlineStatus(loop)
+	p.publishReviewLoopChange(loop)
+	p.postReviewLoopCompletion(loop, p.styleReviewLoopNotification(notificationEventReviewLoopAutoMerged, loop,
+		attachments.BuildAutoMergedAttachment(loop.PRURL, method)))
+	p.swapTerminalReaction(loop.ChannelID, loop.TriggerPostID, "", "rocket")
+	return true
+}
+
+func (p *Plugin) appendAutoMergeEvent(loop *kvstore.ReviewLoop, detail string) {
+	loop.History = append(loop.History, kvstore.ReviewLoopEvent{
+		Phase:     loop.Phase,
+		Timestamp: p.now().UnixMilli(),
+		Detail:    detail,
+	})
+}
+
+// retryHeldAutoMerges is called from the poller. It re-checks CI for loops
+// whose auto-merge was held after AI approval and merges once CI passes, as
+// long as the loop is still waiting on human review and the PR head has not
+// moved since the approval. Holds that no longer apply are dropped.
+func (p *Plugin) retryHeldAutoMerges() {
+	config := p.getConfiguration()
+	if !config.EnableAIReviewLoop || !config.AutoMergeOnApproval || p.reviewLoopsGloballyPaused() {
+		return
+	}
+	ghClient := p.getGitHubClient()
+	if ghClient == nil {
+		return
+	}
+
+	loops, err := p.kvstore.ListActiveReviewLoops()
+	if err != nil {
+		p.API.LogError("Failed to list review loops for held auto-merges", "error", err.Error())
+		return
+	}
+
+	for _, loop := range loops {
+		if loop.AutoMergeHeldSHA == "" {
+			continue
+		}
+		if err := p.retryHeldAutoMerge(ghClient, loop); err != nil {
+			p.API.LogWarn("Failed to retry held auto-merge",
+				"review_loop_id", loop.ID,
+				"error", err.Error(),
+			)
+		}
+	}
+}
+
+func (p *Plugin) retryHeldAutoMerge(ghClient ghclient.Client, loop *kvstore.ReviewLoop) error {
+	if loop.Phase != kvstore.ReviewPhaseHumanReview {
+		// A human review or a new fix iteration superseded the approval.
+		return p.dropHeldAutoMerge(loop, "")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	pr, err := ghClient.GetPullRequest(ctx, loop.Owner, loop.Repo, loop.PRNumber)
+	if err != nil {
+		return fmt.Errorf("failed to fetch pull request: %w", err)
+	}
+	if pr.GetState() != "open" {
+		return p.dropHeldAutoMerge(loop, "")
+	}
+	if pr.GetHead().GetSHA() != loop.AutoMergeHeldSHA {
+		return p.dropHeldAutoMerge(loop, "Auto-merge dropped: new commits were pushed after the AI approval")
+	}
+
+	state, err := ghClient.GetChecksState(ctx, loop.Owner, loop.Repo, loop.AutoMergeHeldSHA)
+	if err != nil {
+		return fmt.Errorf("failed to read CI state: %w", err)
+	}
+	if state != ghclient.ChecksStateSuccess {
+		return nil
+	}
+
+	if p.mergeApprovedPR(ctx, ghClient, loop, loop.AutoMergeHeldSHA) {
+		return nil
+	}
+	loop.UpdatedAt = p.now().UnixMilli()
+	if err := p.kvstore.SaveReviewLoop(loop); err != nil {
+		return fmt.Errorf("failed to save review loop after auto-merge failure: %w", err)
+	}
+	p.publishReviewLoopChange(loop)
+	return nil
+}
+
+// dropHeldAutoMerge clears a held auto-merge, recording detail in the loop
+// history when it is not empty.
+func (p *Plugin) dropHeldAutoMerge(loop *kvstore.ReviewLoop, detail string) error {
+	loop.AutoMergeHeldSHA = ""
+	if detail != "" {
+		p.appendAutoMergeEvent(loop, detail)
+	}
+	loop.UpdatedAt = p.now().UnixMilli()
+	if err := p.kvstore.SaveReviewLoop(loop); err != nil {
+		return fmt.Errorf("failed to save review loop: %w", err)
+	}
+	p.publishReviewLoopChange(loop)
+	return nil
+}
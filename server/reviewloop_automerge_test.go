@@ -0,0 +1,265 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v68/github"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+// setupAutoMergeTest builds an awaiting_review loop whose CodeRabbit review
+// approves the PR, with auto-merge enabled.
+func setupAutoMergeTest(t *testing.T) (*Plugin, *mockPluginAPI, *mockKVStore, *mockGitHubClient, *kvstore.ReviewLoop, ghReview, ghPullRequest) {
+	t.Helper()
+	p, api, store, ghMock := setupReviewLoopTestPlugin(t)
+	p.configuration.AutoMergeOnApproval = true
+
+	loop := &kvstore.ReviewLoop{
+		ID:            "loop-1",
+		AgentRecordID: "agent-1",
+		Owner:         "org",
+		Repo:          "repo",
+		PRNumber:      42,
+		PRURL:         "https://github.com/org/repo/pull/42",
+		Phase:         kvstore.ReviewPhaseAwaitingReview,
+		Iteration:     1,
+		TriggerPostID: "trigger-1",
+		RootPostID:    "root-1",
+		ChannelID:     "ch-1",
+		UserID:        "user-1",
+	}
+	mockInlineStatusUpdate(store, api, "agent-1", &kvstore.AgentRecord{
+		CursorAgentID:  "agent-1",
+		BotReplyPostID: "reply-1",
+		ChannelID:      "ch-1",
+	})
+
+	review := ghReview{State: "approved", Body: "Looks good!"}
+	review.User.Login = "coderabbitai[bot]"
+
+	pr := ghPullRequest{Number: 42}
+	pr.Head.SHA = "sha-1"
+
+	store.On("SaveReviewLoop", mock.MatchedBy(func(l *kvstore.ReviewLoop) bool {
+		return l.Phase == kvstore.ReviewPhaseApproved
+	})).Return(nil).Once()
+	api.On("RemoveReaction", mock.Anything).Return(nil).Maybe()
+	api.On("AddReaction", mock.Anything).Return(nil, nil).Maybe()
+
+	return p, api, store, ghMock, loop, review, pr
+}
+
+func TestAutoMerge_ApprovedWithGreenCI(t *testing.T) {
+	p, api, store, ghMock, loop, review, pr := setupAutoMergeTest(t)
+
+	ghMock.On("GetChecksState", mock.Anything, "org", "repo", "sha-1").Return("success", nil)
+	ghMock.On("MergePR", mock.Anything, "org", "repo", 42, "sha-1", "squash").Return(nil)
+
+	store.On("SaveReviewLoop", mock.MatchedBy(func(l *kvstore.ReviewLoop) bool {
+		return l.Phase == kvstore.ReviewPhaseComplete
+	})).Return(nil).Once()
+	api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		return hasAttachmentWithTitle(post, "CodeRabbit approved the PR!")
+	})).Return(&model.Post{Id: "notif-1"}, nil).Once()
+	api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		return post.RootId == "root-1" && hasAttachmentWithTitle(post, "PR auto-merged (squash)")
+	})).Return(&model.Post{Id: "notif-2"}, nil).Once()
+
+	err := p.handleAIReview(context.Background(), loop, review, pr)
+	require.NoError(t, err)
+
+	assert.Equal(t, kvstore.ReviewPhaseComplete, loop.Phase)
+	last := loop.History[len(loop.History)-1]
+	assert.Equal(t, "Auto-merged (squash) after AI approval with passing CI", last.Detail)
+	ghMock.AssertExpectations(t)
+	store.AssertExpectations(t)
+	api.AssertExpectations(t)
+	store.AssertNotCalled(t, "SaveReviewLoop", mock.MatchedBy(func(l *kvstore.ReviewLoop) bool {
+		return l.Phase == kvstore.ReviewPhaseHumanReview
+	}))
+}
+
+func TestAutoMerge_HeldUntilCIIsGreen(t *testing.T) {
+	for _, state := range []string{"failure", "pending", "none"} {
+		t.Run(state, func(t *testing.T) {
+			p, api, store, ghMock, loop, review, pr := setupAutoMergeTest(t)
+
+			ghMock.On("GetChecksState", mock.Anything, "org", "repo", "sha-1").Return(state, nil)
+			store.On("SaveReviewLoop", mock.MatchedBy(func(l *kvstore.ReviewLoop) bool {
+				return l.Phase == kvstore.ReviewPhaseHumanReview
+			})).Return(nil).Once()
+			api.On("CreatePost", mock.Anything).Return(&model.Post{Id: "notif-1"}, nil)
+
+			err := p.handleAIReview(context.Background(), loop, review, pr)
+			require.NoError(t, err)
+
+			assert.Equal(t, kvstore.ReviewPhaseHumanReview, loop.Phase)
+			ghMock.AssertNotCalled(t, "MergePR", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			assert.True(t, historyContains(loop, "Auto-merge held: CI on sha-1 is "+state))
+			assert.Equal(t, "sha-1", loop.AutoMergeHeldSHA)
+		})
+	}
+}
+
+func TestAutoMerge_MergeRejectedFallsBackToHumanReview(t *testing.T) {
+	p, api, store, ghMock, loop, review, pr := setupAutoMergeTest(t)
+	p.configuration.AutoMergeMethod = "rebase"
+
+	ghMock.On("GetChecksState", mock.Anything, "org", "repo", "sha-1").Return("success", nil)
+	ghMock.On("MergePR", mock.Anything, "org", "repo", 42, "sha-1", "rebase").Return(&github.ErrorResponse{
+		Response: &http.Response{StatusCode: http.StatusMethodNotAllowed},
+		Message:  "At least 1 approving review is required",
+	})
+
+	store.On("SaveReviewLoop", mock.MatchedBy(func(l *kvstore.ReviewLoop) bool {
+		return l.Phase == kvstore.ReviewPhaseHumanReview
+	})).Return(nil).Once()
+	api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		return hasAttachmentWithTitle(post, "CodeRabbit approved the PR!")
+	})).Return(&model.Post{Id: "notif-1"}, nil).Once()
+	api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		attachments := getAttachments(post)
+		return hasAttachmentWithTitle(post, "Could not auto-merge") &&
+			strings.Contains(attachments[0].Text, "At least 1 approving review is required")
+	})).Return(&model.Post{Id: "notif-2"}, nil).Once()
+
+	err := p.handleAIReview(context.Background(), loop, review, pr)
+	require.NoError(t, err)
+
+	assert.Equal(t, kvstore.ReviewPhaseHumanReview, loop.Phase)
+	assert.True(t, historyContains(loop, "Auto-merge failed: At least 1 approving review is required"))
+	api.AssertExpectations(t)
+	store.AssertExpectations(t)
+}
+
+func TestAutoMerge_ChecksLookupErrorSkipsMerge(t *testing.T) {
+	p, api, store, ghMock, loop, review, pr := setupAutoMergeTest(t)
+
+	ghMock.On("GetChecksState", mock.Anything, "org", "repo", "sha-1").Return("", errors.New("boom"))
+	store.On("SaveReviewLoop", mock.MatchedBy(func(l *kvstore.ReviewLoop) bool {
+		return l.Phase == kvstore.ReviewPhaseHumanReview
+	})).Return(nil).Once()
+	api.On("CreatePost", mock.Anything).Return(&model.Post{Id: "notif-1"}, nil)
+
+	err := p.handleAIReview(context.Background(), loop, review, pr)
+	require.NoError(t, err)
+
+	assert.Equal(t, kvstore.ReviewPhaseHumanReview, loop.Phase)
+	assert.True(t, historyContains(loop, "Auto-merge skipped: could not read the CI state"))
+	ghMock.AssertNotCalled(t, "MergePR", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAutoMerge_DisabledByDefault(t *testing.T) {
+	p, api, store, ghMock, loop, review, pr := setupAutoMergeTest(t)
+	p.configuration.AutoMergeOnApproval = false
+
+	store.On("SaveReviewLoop", mock.MatchedBy(func(l *kvstore.ReviewLoop) bool {
+		return l.Phase == kvstore.ReviewPhaseHumanReview
+	})).Return(nil).Once()
+	api.On("CreatePost", mock.Anything).Return(&model.Post{Id: "notif-1"}, nil)
+
+	err := p.handleAIReview(context.Background(), loop, review, pr)
+	require.NoError(t, err)
+
+	assert.Equal(t, kvstore.ReviewPhaseHumanReview, loop.Phase)
+	ghMock.AssertNotCalled(t, "GetChecksState", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// setupHeldAutoMergeTest builds a human_review loop whose auto-merge was held
+// on CI for sha-1.
+func setupHeldAutoMergeTest(t *testing.T) (*Plugin, *mockPluginAPI, *mockKVStore, *mockGitHubClient, *kvstore.ReviewLoop) {
+	t.Helper()
+	p, api, store, ghMock, loop, _, _ := setupAutoMergeTest(t)
+	loop.Phase = kvstore.ReviewPhaseHumanReview
+	loop.LastCommitSHA = "sha-1"
+	loop.AutoMergeHeldSHA = "sha-1"
+	store.On("ListActiveReviewLoops").Return([]*kvstore.ReviewLoop{loop}, nil)
+	return p, api, store, ghMock, loop
+}
+
+func openPullRequest(headSHA string) *github.PullRequest {
+	return &github.PullRequest{
+		State: github.Ptr("open"),
+		Head:  &github.PullRequestBranch{SHA: github.Ptr(headSHA)},
+	}
+}
+
+func TestRetryHeldAutoMerges_MergesOnceCIPasses(t *testing.T) {
+	p, api, store, ghMock, loop := setupHeldAutoMergeTest(t)
+
+	ghMock.On("GetPullRequest", mock.Anything, "org", "repo", 42).Return(openPullRequest("sha-1"), nil)
+	ghMock.On("GetChecksState", mock.Anything, "org", "repo", "sha-1").Return("success", nil)
+	ghMock.On("MergePR", mock.Anything, "org", "repo", 42, "sha-1", "squash").Return(nil)
+	store.On("SaveReviewLoop", mock.MatchedBy(func(l *kvstore.ReviewLoop) bool {
+		return l.Phase == kvstore.ReviewPhaseComplete
+	})).Return(nil).Once()
+	api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		return hasAttachmentWithTitle(post, "PR auto-merged (squash)")
+	})).Return(&model.Post{Id: "notif-1"}, nil).Once()
+
+	p.retryHeldAutoMerges()
+
+	assert.Equal(t, kvstore.ReviewPhaseComplete, loop.Phase)
+	assert.Empty(t, loop.AutoMergeHeldSHA)
+	ghMock.AssertExpectations(t)
+	store.AssertCalled(t, "SaveReviewLoop", loop)
+	api.AssertExpectations(t)
+}
+
+func TestRetryHeldAutoMerges_KeepsWaitingWhileCIIsPending(t *testing.T) {
+	p, _, store, ghMock, loop := setupHeldAutoMergeTest(t)
+
+	ghMock.On("GetPullRequest", mock.Anything, "org", "repo", 42).Return(openPullRequest("sha-1"), nil)
+	ghMock.On("GetChecksState", mock.Anything, "org", "repo", "sha-1").Return("pending", nil)
+
+	p.retryHeldAutoMerges()
+
+	assert.Equal(t, "sha-1", loop.AutoMergeHeldSHA)
+	store.AssertNotCalled(t, "SaveReviewLoop", mock.Anything)
+	ghMock.AssertNotCalled(t, "MergePR", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRetryHeldAutoMerges_DropsHoldWhenHeadMoved(t *testing.T) {
+	p, _, store, ghMock, loop := setupHeldAutoMergeTest(t)
+
+	ghMock.On("GetPullRequest", mock.Anything, "org", "repo", 42).Return(openPullRequest("sha-2"), nil)
+	store.On("SaveReviewLoop", loop).Return(nil).Once()
+
+	p.retryHeldAutoMerges()
+
+	assert.Empty(t, loop.AutoMergeHeldSHA)
+	assert.Equal(t, kvstore.ReviewPhaseHumanReview, loop.Phase)
+	assert.True(t, historyContains(loop, "Auto-merge dropped: new commits were pushed after the AI approval"))
+	ghMock.AssertNotCalled(t, "GetChecksState", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	ghMock.AssertNotCalled(t, "MergePR", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRetryHeldAutoMerges_DropsHoldWhenLoopMovedOn(t *testing.T) {
+	p, _, store, ghMock, loop := setupHeldAutoMergeTest(t)
+	loop.Phase = kvstore.ReviewPhaseCursorFixing
+
+	store.On("SaveReviewLoop", loop).Return(nil).Once()
+
+	p.retryHeldAutoMerges()
+
+	assert.Empty(t, loop.AutoMergeHeldSHA)
+	ghMock.AssertNotCalled(t, "GetPullRequest", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func historyContains(loop *kvstore.ReviewLoop, detail string) bool {
+	for _, event := range loop.History {
+		if strings.Contains(event.Detail, detail) {
+			return true
+		}
+	}
+	return false
+}
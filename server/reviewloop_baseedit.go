@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/ghclient"
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+// handlePREditedWebhook re-syncs a review loop when its PR's base branch is
+// changed after the loop started. Edits that leave the base alone, such as
+// title or body changes, are ignored, as are all edits unless
+// ReviewLoopSyncBaseOnEdit is enabled.
+func (p *Plugin) handlePREditedWebhook(ctx context.Context, event PullRequestEvent, w http.ResponseWriter) {
+	newBase := strings.TrimSpace(event.PullRequest.Base.Ref)
+	if !p.getConfiguration().ReviewLoopSyncBaseOnEdit || event.Changes.Base == nil || newBase == "" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	loop, err := p.kvstore.GetReviewLoopByPRURL(event.PullRequest.HTMLURL)
+	if err != nil {
+		p.logger(ctx).LogError("Failed to look up review loop for edited event",
+			"error", err.Error(),
+			"pr_url", event.PullRequest.HTMLURL,
+		)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if loop == nil || kvstore.IsReviewPhaseTerminal(loop.Phase) {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if err := p.syncReviewLoopBase(loop, event.Changes.Base.Ref.From, newBase, event.Repository.FullName); err != nil {
+		p.logger(ctx).LogError("Failed to re-sync review loop base branch",
+			"error", err.Error(),
+			"review_loop_id", loop.ID,
+		)
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// syncReviewLoopBase records a base branch change on the loop and on its
+// agent record, so the RHS shows the new base. The stored owner and repo are
+// refreshed from repository, the PR's base repository, when it names a
+// different one.
+func (p *Plugin) syncReviewLoopBase(loop *kvstore.ReviewLoop, oldBase, newBase, repository string) error {
+	if oldBase == "" {
+		oldBase = loop.BaseBranch
+	}
+	if newBase == loop.BaseBranch {
+		return nil
+	}
+
+	loop.BaseBranch = newBase
+	if ref, err := ghclient.ParseRepoRef(repository); err == nil && !strings.EqualFold(ref.FullName(), loop.Repository) {
+		loop.Repository = ref.FullName()
+		loop.Owner = ref.Owner
+		loop.Repo = ref.Repo
+	}
+
+	detail := fmt.Sprintf("Base branch changed to `%s`", newBase)
+	if oldBase != "" {
+		detail = fmt.Sprintf("Base branch changed from `%s` to `%s`", oldBase, newBase)
+	}
+	now := p.now().UnixMilli()
+	loop.History = append(loop.History, kvstore.ReviewLoopEvent{
+		Phase:     loop.Phase,
+		Timestamp: now,
+		Detail:    detail,
+	})
+	loop.UpdatedAt = now
+	if err := p.kvstore.SaveReviewLoop(loop); err != nil {
+		return fmt.Errorf("failed to save review loop: %w", err)
+	}
+	p.publishReviewLoopChange(loop)
+	p.syncAgentBaseBranch(loop.AgentRecordID, newBase)
+	return nil
+}
+
+// syncAgentBaseBranch points the loop's agent record at the new base branch.
+// Failures are logged rather than returned since the loop itself is already
+// updated.
+func (p *Plugin) syncAgentBaseBranch(agentRecordID, newBase string) {
+	if agentRecordID == "" {
+		return
+	}
+	record, err := p.kvstore.GetAgent(agentRecordID)
+	if err != nil || record == nil || record.BaseBranch == newBase {
+		return
+	}
+
+	record.BaseBranch = newBase
+	record.UpdatedAt = p.now().UnixMilli()
+	if err := p.kvstore.SaveAgent(record); err != nil {
+		p.API.LogError("Failed to update agent base branch",
+			"error", err.Error(),
+			"agent_id", agentRecordID,
+		)
+		return
+	}
+	p.publishAgentStatusChange(record)
+}
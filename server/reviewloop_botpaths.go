@@ -0,0 +1,77 @@
+package main
+
+import (
+	"path"
+	"strings"
+)
+
+// reviewerBotPathScope makes the listed AI reviewer bots authoritative for
+// files matching Pattern.
+type reviewerBotPathScope struct {
+	Pattern string
+	Bots    []string
+}
+
+// parseReviewerBotPathScopes parses AIReviewerBotPaths: one "glob=bot1,bot2"
+// mapping per line. Malformed lines are skipped.
+func parseReviewerBotPathScopes(spec string) []reviewerBotPathScope {
+	var scopes []reviewerBotPathScope
+	for _, line := range strings.Split(spec, "\n") {
+		pattern, bots, found := strings.Cut(line, "=")
+		pattern = strings.TrimPrefix(strings.TrimSpace(pattern), "/")
+		if !found || pattern == "" {
+			continue
+		}
+		scope := reviewerBotPathScope{Pattern: pattern}
+		for _, bot := range strings.Split(bots, ",") {
+			if bot = strings.TrimSpace(bot); bot != "" {
+				scope.Bots = append(scope.Bots, bot)
+			}
+		}
+		if len(scope.Bots) > 0 {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+// reviewerBotCoversPath reports whether login is authoritative for filePath.
+// Bots that appear in no scope cover every path; scoped bots cover only the
+// paths matching one of their patterns.
+func reviewerBotCoversPath(scopes []reviewerBotPathScope, login, filePath string) bool {
+	scoped := false
+	for _, scope := range scopes {
+		for _, bot := range scope.Bots {
+			if !strings.EqualFold(bot, login) {
+				continue
+			}
+			scoped = true
+			if matchReviewPathGlob(scope.Pattern, filePath) {
+				return true
+			}
+		}
+	}
+	return !scoped
+}
+
+// matchReviewPathGlob matches a repository-relative file path against a glob.
+// "*" and "?" match within one path segment, and a trailing "/**" matches
+// everything below a directory.
+func matchReviewPathGlob(pattern, filePath string) bool {
+	filePath = strings.TrimPrefix(filePath, "/")
+	if dir, ok := strings.CutSuffix(pattern, "/**"); ok {
+		if !strings.ContainsAny(dir, "*?[") {
+			return strings.HasPrefix(filePath, dir+"/")
+		}
+		// Match the leading segments against the directory pattern.
+		segments := strings.Count(dir, "/") + 1
+		parts := strings.SplitN(filePath, "/", segments+1)
+		if len(parts) <= segments {
+			return false
+		}
+		matched, err := path.Match(dir, strings.Join(parts[:segments], "/"))
+		return err == nil && matched
+	}
+	matched, err := path.Match(pattern, filePath)
+	return err == nil && matched
+}
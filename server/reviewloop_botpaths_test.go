@@ -0,0 +1,104 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/google/go-github/v68/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+func TestMatchReviewPathGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"services/api/**", "services/api/main.go", true},
+		{"services/api/**", "services/api/handlers/user.go", true},
+		{"services/api/**", "services/apiv2/main.go", false},
+		{"services/*/**", "services/web/src/app.ts", true},
+		{"services/*/**", "services/main.go", false},
+		{"*.md", "README.md", true},
+		{"*.md", "docs/guide.md", false},
+		{"webapp/src/*.tsx", "webapp/src/index.tsx", true},
+		{"webapp/src/*.tsx", "webapp/src/rhs/index.tsx", false},
+	}
+
+	for _, tc := range tests {
+		assert.Equal(t, tc.want, matchReviewPathGlob(tc.pattern, tc.path), "%s vs %s", tc.pattern, tc.path)
+	}
+}
+
+func TestParseReviewerBotPathScopes(t *testing.T) {
+	scopes := parseReviewerBotPathScopes("services/api/**=coderabbitai[bot], copilot\n\nmalformed line\nwebapp/**=\n/webapp/**=copilot")
+
+	require.Len(t, scopes, 2)
+	assert.Equal(t, reviewerBotPathScope{Pattern: "services/api/**", Bots: []string{"coderabbitai[bot]", "copilot"}}, scopes[0])
+	assert.Equal(t, reviewerBotPathScope{Pattern: "webapp/**", Bots: []string{"copilot"}}, scopes[1])
+}
+
+func TestIsAIReviewerBot_PathScoped(t *testing.T) {
+	p, _, _, _ := setupReviewLoopTestPlugin(t)
+	p.configuration.AIReviewerBotPaths = "services/api/**=coderabbitai[bot]"
+
+	// The scoped bot is AI on its paths and human elsewhere.
+	assert.True(t, p.isAIReviewerBot("coderabbitai[bot]", "services/api/server.go"))
+	assert.False(t, p.isAIReviewerBot("coderabbitai[bot]", "webapp/src/index.tsx"))
+	assert.Equal(t, reviewerTypeHuman, p.reviewerTypeForLogin("coderabbitai[bot]", "webapp/src/index.tsx"))
+
+	// Comments not tied to a file keep the login-based classification.
+	assert.True(t, p.isAIReviewerBot("coderabbitai[bot]", ""))
+
+	// Unscoped bots review every path.
+	assert.True(t, p.isAIReviewerBot("copilot-pull-request-reviewer", "webapp/src/index.tsx"))
+}
+
+func TestCollectFeedbackCandidates_PathScopedBot(t *testing.T) {
+	p, _, _, ghMock := setupReviewLoopTestPlugin(t)
+	p.configuration.AIReviewerBotPaths = "services/api/**=coderabbitai[bot]"
+
+	loop := &kvstore.ReviewLoop{
+		ID:       "loop-monorepo",
+		Owner:    "org",
+		Repo:     "repo",
+		PRNumber: 42,
+		Phase:    kvstore.ReviewPhaseAwaitingReview,
+	}
+
+	ghMock.On("ListReviewComments", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestComment{
+		{
+			ID:   github.Ptr(int64(1)),
+			User: &github.User{Login: github.Ptr("coderabbitai[bot]")},
+			Path: github.Ptr("services/api/server.go"),
+			Line: github.Ptr(10),
+			Body: github.Ptr("Handle the error from Close."),
+		},
+		{
+			ID:   github.Ptr(int64(2)),
+			User: &github.User{Login: github.Ptr("coderabbitai[bot]")},
+			Path: github.Ptr("webapp/src/index.tsx"),
+			Line: github.Ptr(5),
+			Body: github.Ptr("Memoize this selector."),
+		},
+	}, nil)
+	ghMock.On("ListReviews", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestReview{}, nil)
+	ghMock.On("ListIssueComments", mock.Anything, "org", "repo", 42).Return([]*github.IssueComment{}, nil)
+
+	candidates, err := p.collectFeedbackCandidates(loop)
+	require.NoError(t, err)
+	require.Len(t, candidates, 1)
+	assert.Equal(t, "services/api/server.go", candidates[0].Path)
+	assert.Equal(t, reviewerTypeAIBot, candidates[0].ReviewerType)
+
+	// In human review the same bot's out-of-scope comment is collected as human feedback.
+	loop.Phase = kvstore.ReviewPhaseHumanReview
+	candidates, err = p.collectFeedbackCandidates(loop)
+	require.NoError(t, err)
+	require.Len(t, candidates, 1)
+	assert.Equal(t, "webapp/src/index.tsx", candidates[0].Path)
+	assert.Equal(t, reviewerTypeHuman, candidates[0].ReviewerType)
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+// requestChangesRequestedDetail comments on the PR asking an AI reviewer that
+// requested changes, but left no actionable findings, to say what should
+// change. Failures are logged and non-fatal.
+func (p *Plugin) requestChangesRequestedDetail(loop *kvstore.ReviewLoop, reviewerLogin string) {
+	ghClient := p.getGitHubClient()
+	if ghClient == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	if _, err := ghClient.CreateComment(ctx, loop.Owner, loop.Repo, loop.PRNumber, formatChangesRequestedDetailComment(reviewerLogin)); err != nil {
+		p.API.LogWarn("Failed to ask reviewer for detail on requested changes (non-fatal)",
+			"error", err.Error(),
+			"review_loop_id", loop.ID,
+		)
+	}
+}
+
+// formatChangesRequestedDetailComment builds the PR comment asking reviewer
+// for actionable findings. The bot is mentioned without the "[bot]" suffix,
+// which is how GitHub resolves app mentions.
+func formatChangesRequestedDetailComment(reviewerLogin string) string {
+	return fmt.Sprintf(
+		"@%s requested changes, but no actionable findings could be found in the review. "+
+			"Please add inline comments or list the specific changes needed so the agent can address them.",
+		strings.TrimSuffix(reviewerLogin, "[bot]"),
+	)
+}
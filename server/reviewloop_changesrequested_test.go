@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v68/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/cursor"
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+func TestHandleAIReview_ChangesRequestedWithFindingsDispatches(t *testing.T) {
+	p, api, store, ghMock := setupReviewLoopTestPlugin(t)
+	cursorMock := p.cursorClient.(*mockCursorClient)
+
+	loop := newAwaitingReviewLoop()
+
+	// A non-CodeRabbit bot with a summary body: neither the CodeRabbit nor the
+	// inline-only path applies, so only the state drives the dispatch.
+	review := ghReview{State: "changes_requested", Body: "A few issues need attention before merging."}
+	review.User.Login = "copilot-pull-request-reviewer"
+
+	pr := ghPullRequest{}
+	pr.Head.SHA = "sha-1"
+
+	ghMock.On("ListReviewComments", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestComment{
+		{
+			User:     &github.User{Login: github.Ptr("copilot-pull-request-reviewer")},
+			Path:     github.Ptr("server/api.go"),
+			Line:     github.Ptr(14),
+			Body:     github.Ptr("This error is silently dropped."),
+			CommitID: github.Ptr("sha-1"),
+		},
+	}, nil)
+	ghMock.On("ListReviews", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestReview{}, nil)
+	ghMock.On("ListIssueComments", mock.Anything, "org", "repo", 42).Return([]*github.IssueComment{}, nil)
+
+	cursorMock.On("AddFollowup", mock.Anything, "agent-1", mock.MatchedBy(func(req cursor.FollowupRequest) bool {
+		return strings.Contains(req.Prompt.Text, "This error is silently dropped.")
+	})).Return(&cursor.FollowupResponse{ID: "agent-1"}, nil).Once()
+	store.On("SaveReviewLoop", mock.Anything).Return(nil)
+	mockInlineStatusUpdate(store, api, "agent-1", &kvstore.AgentRecord{CursorAgentID: "agent-1"})
+
+	err := p.handleAIReview(context.Background(), loop, review, pr)
+	require.NoError(t, err)
+
+	cursorMock.AssertExpectations(t)
+	assert.Equal(t, kvstore.ReviewPhaseCursorFixing, loop.Phase)
+	assert.Equal(t, 2, loop.Iteration)
+	ghMock.AssertNotCalled(t, "CreateComment", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHandleAIReview_ChangesRequestedWithoutFindingsAsksForDetail(t *testing.T) {
+	p, api, store, ghMock := setupReviewLoopTestPlugin(t)
+	cursorMock := p.cursorClient.(*mockCursorClient)
+
+	loop := newAwaitingReviewLoop()
+
+	review := ghReview{State: "changes_requested", Body: "Changes requested."}
+	review.User.Login = "copilot-pull-request-reviewer[bot]"
+
+	pr := ghPullRequest{}
+	pr.Head.SHA = "sha-1"
+
+	ghMock.On("ListReviewComments", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestComment{}, nil)
+	ghMock.On("ListReviews", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestReview{}, nil)
+	ghMock.On("ListIssueComments", mock.Anything, "org", "repo", 42).Return([]*github.IssueComment{}, nil)
+	ghMock.On("CreateComment", mock.Anything, "org", "repo", 42, mock.MatchedBy(func(body string) bool {
+		return strings.HasPrefix(body, "@copilot-pull-request-reviewer requested changes") &&
+			!strings.Contains(body, "[bot]")
+	})).Return(&github.IssueComment{}, nil).Once()
+	store.On("SaveReviewLoop", mock.Anything).Return(nil)
+	api.On("PublishWebSocketEvent", mock.Anything, mock.Anything, mock.Anything).Return().Maybe()
+
+	err := p.handleAIReview(context.Background(), loop, review, pr)
+	require.NoError(t, err)
+
+	ghMock.AssertExpectations(t)
+	cursorMock.AssertNotCalled(t, "AddFollowup", mock.Anything, mock.Anything, mock.Anything)
+	assert.Equal(t, kvstore.ReviewPhaseAwaitingReview, loop.Phase)
+	assert.Equal(t, 1, loop.Iteration)
+	require.NotEmpty(t, loop.History)
+	assert.Contains(t, loop.History[len(loop.History)-1].Detail, "changes requested without actionable findings")
+}
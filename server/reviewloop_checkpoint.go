@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+// dispatchCheckpointGrace is how old a dispatch checkpoint must be before the
+// poller reconciles it, so a dispatch that is still in flight is left alone.
+const dispatchCheckpointGrace = time.Minute
+
+// reconcileInterruptedDispatches is called from the poller. It resolves
+// dispatch checkpoints left behind when the plugin stopped between sending a
+// follow-up and recording it, so an interrupted dispatch is settled without
+// waiting for the next review webhook. Checkpoints that still cannot be
+// confirmed are kept for the next cycle.
+func (p *Plugin) reconcileInterruptedDispatches() {
+	if !p.getConfiguration().EnableAIReviewLoop || p.reviewLoopsGloballyPaused() {
+		return
+	}
+
+	loops, err := p.kvstore.ListPendingDispatchReviewLoops()
+	if err != nil {
+		p.API.LogError("Failed to list review loops with dispatch checkpoints", "error", err.Error())
+		return
+	}
+
+	cutoff := p.now().Add(-dispatchCheckpointGrace).UnixMilli()
+	for _, loop := range loops {
+		if loop.PendingDispatchAt > cutoff {
+			continue
+		}
+		if err := p.reconcileInterruptedDispatch(loop); err != nil {
+			p.API.LogError("Failed to reconcile interrupted review feedback dispatch",
+				"review_loop_id", loop.ID,
+				"error", err.Error(),
+			)
+		}
+	}
+}
+
+func (p *Plugin) reconcileInterruptedDispatch(loop *kvstore.ReviewLoop) error {
+	if loop.Phase != kvstore.ReviewPhaseAwaitingReview && loop.Phase != kvstore.ReviewPhaseHumanReview {
+		// The loop moved on without the dispatch being recorded; the
+		// checkpoint no longer guards anything.
+		clearDispatchCheckpoint(loop)
+		loop.UpdatedAt = p.now().UnixMilli()
+		if err := p.kvstore.SaveReviewLoop(loop); err != nil {
+			return fmt.Errorf("failed to save review loop after clearing dispatch checkpoint: %w", err)
+		}
+		return nil
+	}
+
+	pr := ghPullRequest{}
+	pr.Head.SHA = loop.PendingDispatchSHA
+	if record, err := p.kvstore.GetAgent(loop.AgentRecordID); err == nil && record != nil {
+		pr.Head.Ref = record.TargetBranch
+	}
+
+	_, err := p.redispatchReviewFeedback(loop, pr, "recovered interrupted dispatch")
+	return err
+}
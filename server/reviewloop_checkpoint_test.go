@@ -0,0 +1,101 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/cursor"
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+func newInterruptedDispatchTestLoop() *kvstore.ReviewLoop {
+	loop := newCheckpointTestLoop()
+	loop.PendingDispatchAt = time.Now().Add(-2 * dispatchCheckpointGrace).UnixMilli()
+	loop.PendingDispatchPromptHash = dispatchPromptHash("Apply the latest pull request review feedback.")
+	return loop
+}
+
+func TestReconcileInterruptedDispatches_DeliveredAdvancesLoop(t *testing.T) {
+	p, api, store, ghMock := setupReviewLoopTestPlugin(t)
+	cursorMock := p.cursorClient.(*mockCursorClient)
+
+	loop := newInterruptedDispatchTestLoop()
+	checkpointAt := loop.PendingDispatchAt
+
+	store.On("ListPendingDispatchReviewLoops").Return([]*kvstore.ReviewLoop{loop}, nil)
+	store.On("SaveReviewLoop", mock.Anything).Return(nil)
+	mockInlineStatusUpdate(store, api, "agent-1", nil)
+	mockCheckpointReviewFeedback(ghMock)
+	cursorMock.On("GetConversation", mock.Anything, "agent-1").Return(&cursor.Conversation{
+		Messages: []cursor.Message{
+			{Type: "user_message", Text: "Apply the latest pull request review feedback."},
+		},
+	}, nil)
+
+	p.reconcileInterruptedDispatches()
+
+	cursorMock.AssertNotCalled(t, "AddFollowup", mock.Anything, mock.Anything, mock.Anything)
+	assert.Equal(t, kvstore.ReviewPhaseCursorFixing, loop.Phase)
+	assert.Equal(t, 2, loop.Iteration)
+	assert.Equal(t, checkpointAt, loop.LastFeedbackDispatchAt)
+	assert.Equal(t, "digest-1", loop.LastFeedbackDigest)
+	assert.Zero(t, loop.PendingDispatchAt)
+	assert.Contains(t, loop.History[len(loop.History)-1].Detail, "recovered interrupted dispatch")
+}
+
+func TestReconcileInterruptedDispatches_UnconfirmedKeepsCheckpoint(t *testing.T) {
+	p, api, store, ghMock := setupReviewLoopTestPlugin(t)
+	api.On("LogWarn", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	cursorMock := p.cursorClient.(*mockCursorClient)
+
+	loop := newInterruptedDispatchTestLoop()
+
+	store.On("ListPendingDispatchReviewLoops").Return([]*kvstore.ReviewLoop{loop}, nil)
+	store.On("SaveReviewLoop", mock.Anything).Return(nil)
+	mockInlineStatusUpdate(store, api, "agent-1", nil)
+	mockCheckpointReviewFeedback(ghMock)
+	cursorMock.On("GetConversation", mock.Anything, "agent-1").Return(nil, assert.AnError)
+
+	p.reconcileInterruptedDispatches()
+
+	cursorMock.AssertNotCalled(t, "AddFollowup", mock.Anything, mock.Anything, mock.Anything)
+	assert.Equal(t, kvstore.ReviewPhaseAwaitingReview, loop.Phase)
+	assert.Equal(t, "loop-checkpoint-1700000000000", loop.PendingDispatchID)
+	assert.NotZero(t, loop.PendingDispatchAt)
+}
+
+func TestReconcileInterruptedDispatches_SkipsRecentCheckpoint(t *testing.T) {
+	p, _, store, _ := setupReviewLoopTestPlugin(t)
+	cursorMock := p.cursorClient.(*mockCursorClient)
+
+	loop := newInterruptedDispatchTestLoop()
+	loop.PendingDispatchAt = time.Now().UnixMilli()
+
+	store.On("ListPendingDispatchReviewLoops").Return([]*kvstore.ReviewLoop{loop}, nil)
+
+	p.reconcileInterruptedDispatches()
+
+	store.AssertNotCalled(t, "SaveReviewLoop", mock.Anything)
+	cursorMock.AssertNotCalled(t, "GetConversation", mock.Anything, mock.Anything)
+}
+
+func TestReconcileInterruptedDispatches_ClearsCheckpointWhenLoopMovedOn(t *testing.T) {
+	p, _, store, _ := setupReviewLoopTestPlugin(t)
+	cursorMock := p.cursorClient.(*mockCursorClient)
+
+	loop := newInterruptedDispatchTestLoop()
+	loop.Phase = kvstore.ReviewPhaseApproved
+
+	store.On("ListPendingDispatchReviewLoops").Return([]*kvstore.ReviewLoop{loop}, nil)
+	store.On("SaveReviewLoop", loop).Return(nil).Once()
+
+	p.reconcileInterruptedDispatches()
+
+	store.AssertExpectations(t)
+	assert.Empty(t, loop.PendingDispatchID)
+	assert.Zero(t, loop.PendingDispatchAt)
+	cursorMock.AssertNotCalled(t, "GetConversation", mock.Anything, mock.Anything)
+}
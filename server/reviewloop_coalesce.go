@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+// reviewLoopIdempotencyWindowEnd returns when the loop's idempotency window
+// after its last feedback dispatch ends, in Unix millis, or 0 when the loop
+// has no window or has not dispatched yet.
+func reviewLoopIdempotencyWindowEnd(loop *kvstore.ReviewLoop) int64 {
+	if loop.IdempotencyWindowSeconds <= 0 || loop.LastFeedbackDispatchAt == 0 {
+		return 0
+	}
+	return loop.LastFeedbackDispatchAt + int64(loop.IdempotencyWindowSeconds)*1000
+}
+
+// coalesceDispatch marks the loop as holding a feedback dispatch until its
+// idempotency window ends. Later holds replace the PR head, so the released
+// dispatch covers everything collected against the newest commit.
+func coalesceDispatch(loop *kvstore.ReviewLoop, pr ghPullRequest) {
+	loop.CoalescePending = true
+	loop.CoalesceSHA = strings.TrimSpace(pr.Head.SHA)
+	loop.CoalesceRef = pr.Head.Ref
+}
+
+func clearCoalescedDispatch(loop *kvstore.ReviewLoop) {
+	loop.CoalescePending = false
+	loop.CoalesceSHA = ""
+	loop.CoalesceRef = ""
+}
+
+// releaseCoalescedDispatches is called from the poller. It sends the feedback
+// dispatches held by an idempotency window once the window has passed. Held
+// loops are always waiting on reviewers, so only those are scanned.
+func (p *Plugin) releaseCoalescedDispatches() {
+	if !p.getConfiguration().EnableAIReviewLoop || p.reviewLoopsGloballyPaused() {
+		return
+	}
+
+	loops, err := p.kvstore.ListWaitingReviewLoops()
+	if err != nil {
+		p.API.LogError("Failed to list review loops for coalesced dispatches", "error", err.Error())
+		return
+	}
+
+	now := p.now().UnixMilli()
+	for _, loop := range loops {
+		if !loop.CoalescePending || now < reviewLoopIdempotencyWindowEnd(loop) {
+			continue
+		}
+		if err := p.releaseCoalescedDispatch(loop); err != nil {
+			p.API.LogError("Failed to release coalesced review feedback dispatch",
+				"review_loop_id", loop.ID,
+				"error", err.Error(),
+			)
+		}
+	}
+}
+
+func (p *Plugin) releaseCoalescedDispatch(loop *kvstore.ReviewLoop) error {
+	pr := ghPullRequest{}
+	pr.Head.SHA = loop.CoalesceSHA
+	pr.Head.Ref = loop.CoalesceRef
+
+	clearCoalescedDispatch(loop)
+	loop.UpdatedAt = p.now().UnixMilli()
+	if err := p.kvstore.SaveReviewLoop(loop); err != nil {
+		return fmt.Errorf("failed to save review loop before coalesced dispatch: %w", err)
+	}
+
+	return p.redispatchDeferredFeedback(loop, pr, "dispatched after idempotency window")
+}
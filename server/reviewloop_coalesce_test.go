@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/cursor"
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+// newWindowedReviewLoop returns a loop that last dispatched feedback
+// lastDispatchAgo ago and holds dispatches for ten minutes after that.
+func newWindowedReviewLoop(lastDispatchAgo time.Duration) *kvstore.ReviewLoop {
+	return &kvstore.ReviewLoop{
+		ID:                       "loop-1",
+		AgentRecordID:            "agent-1",
+		RootPostID:               "root-1",
+		ChannelID:                "ch-1",
+		Owner:                    "org",
+		Repo:                     "repo",
+		PRNumber:                 42,
+		PRURL:                    "https://github.com/org/repo/pull/42",
+		Phase:                    kvstore.ReviewPhaseAwaitingReview,
+		Iteration:                2,
+		IdempotencyWindowSeconds: 600,
+		LastFeedbackDispatchAt:   time.Now().Add(-lastDispatchAgo).UnixMilli(),
+		LastFeedbackDispatchSHA:  "sha-0",
+		LastFeedbackDigest:       "previous-digest",
+	}
+}
+
+func TestDispatchReviewFeedback_CoalescedWithinIdempotencyWindow(t *testing.T) {
+	p, _, _, ghMock := setupReviewLoopTestPlugin(t)
+	cursorMock := p.cursorClient.(*mockCursorClient)
+
+	loop := newWindowedReviewLoop(2 * time.Minute)
+	pr := ghPullRequest{}
+	pr.Head.SHA = "sha-1"
+	pr.Head.Ref = "cursor/fix-nil-guard"
+
+	mockCheckpointReviewFeedback(ghMock)
+
+	outcome, err := p.dispatchReviewFeedback(context.Background(), loop, pr)
+	require.NoError(t, err)
+	assert.True(t, outcome.Skipped)
+	assert.Equal(t, reviewDispatchModeCoalesced, outcome.Mode)
+
+	assert.True(t, loop.CoalescePending)
+	assert.Equal(t, "sha-1", loop.CoalesceSHA)
+	assert.Equal(t, "cursor/fix-nil-guard", loop.CoalesceRef)
+	assert.Equal(t, "previous-digest", loop.LastFeedbackDigest)
+	require.NotEmpty(t, loop.History)
+	assert.Contains(t, loop.History[len(loop.History)-1].Detail, "within the idempotency window")
+	cursorMock.AssertNotCalled(t, "AddFollowup", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDispatchReviewFeedback_ProceedsAfterIdempotencyWindow(t *testing.T) {
+	p, _, store, ghMock := setupReviewLoopTestPlugin(t)
+	cursorMock := p.cursorClient.(*mockCursorClient)
+
+	loop := newWindowedReviewLoop(15 * time.Minute)
+	pr := ghPullRequest{}
+	pr.Head.SHA = "sha-1"
+
+	store.On("SaveReviewLoop", mock.Anything).Return(nil)
+	mockCheckpointReviewFeedback(ghMock)
+	cursorMock.On("AddFollowup", mock.Anything, "agent-1", mock.Anything).Return(&cursor.FollowupResponse{ID: "agent-1"}, nil).Once()
+
+	outcome, err := p.dispatchReviewFeedback(context.Background(), loop, pr)
+	require.NoError(t, err)
+	assert.True(t, outcome.Dispatched)
+	assert.False(t, loop.CoalescePending)
+	cursorMock.AssertExpectations(t)
+}
+
+func TestReleaseCoalescedDispatches_DispatchesOnceWindowPasses(t *testing.T) {
+	p, api, store, ghMock := setupReviewLoopTestPlugin(t)
+	p.configuration.EnableAIReviewLoop = true
+	cursorMock := p.cursorClient.(*mockCursorClient)
+
+	held := newWindowedReviewLoop(15 * time.Minute)
+	held.CoalescePending = true
+	held.CoalesceSHA = "sha-1"
+	held.CoalesceRef = "cursor/fix-nil-guard"
+
+	stillHeld := newWindowedReviewLoop(time.Minute)
+	stillHeld.ID = "loop-2"
+	stillHeld.CoalescePending = true
+
+	store.On("ListWaitingReviewLoops").Return([]*kvstore.ReviewLoop{held, stillHeld}, nil)
+	store.On("SaveReviewLoop", mock.Anything).Return(nil)
+	mockInlineStatusUpdate(store, api, "agent-1", &kvstore.AgentRecord{CursorAgentID: "agent-1"})
+	mockCheckpointReviewFeedback(ghMock)
+	cursorMock.On("AddFollowup", mock.Anything, "agent-1", mock.MatchedBy(func(req cursor.FollowupRequest) bool {
+		return strings.Contains(req.Prompt.Text, "- head_sha: sha-1")
+	})).Return(&cursor.FollowupResponse{ID: "agent-1"}, nil).Once()
+
+	p.releaseCoalescedDispatches()
+
+	cursorMock.AssertExpectations(t)
+	assert.Equal(t, kvstore.ReviewPhaseCursorFixing, held.Phase)
+	assert.False(t, held.CoalescePending)
+	require.NotEmpty(t, held.History)
+	assert.Contains(t, held.History[len(held.History)-1].Detail, "dispatched after idempotency window")
+	assert.True(t, stillHeld.CoalescePending)
+}
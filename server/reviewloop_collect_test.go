@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v68/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+func TestCollectFeedbackCandidates_MergesConcurrentListsInOrder(t *testing.T) {
+	delays := map[string][3]time.Duration{
+		"review comments last": {30 * time.Millisecond, 10 * time.Millisecond, 0},
+		"issue comments last":  {0, 10 * time.Millisecond, 30 * time.Millisecond},
+	}
+	for name, delay := range delays {
+		for _, concurrency := range []int{0, 1} {
+			t.Run(fmt.Sprintf("%s with concurrency %d", name, concurrency), func(t *testing.T) {
+				p, _, _, ghMock := setupReviewLoopTestPlugin(t)
+				p.configuration.FeedbackCollectionConcurrency = concurrency
+
+				loop := &kvstore.ReviewLoop{
+					ID:       "loop-1",
+					Owner:    "org",
+					Repo:     "repo",
+					PRNumber: 42,
+					Phase:    kvstore.ReviewPhaseHumanReview,
+				}
+
+				human := &github.User{Login: github.Ptr("human-reviewer")}
+				ghMock.On("ListReviewComments", mock.Anything, "org", "repo", 42).
+					After(delay[0]).
+					Return([]*github.PullRequestComment{
+						humanInlineComment(101, "a.go", "Please add a nil guard."),
+						humanInlineComment(102, "b.go", "Please cover the empty input."),
+					}, nil).Once()
+				ghMock.On("ListReviews", mock.Anything, "org", "repo", 42).
+					After(delay[1]).
+					Return([]*github.PullRequestReview{
+						{ID: github.Ptr(int64(201)), User: human, Body: github.Ptr("Looks close.")},
+					}, nil).Once()
+				ghMock.On("ListIssueComments", mock.Anything, "org", "repo", 42).
+					After(delay[2]).
+					Return([]*github.IssueComment{
+						{ID: github.Ptr(int64(301)), User: human, Body: github.Ptr("One more thing.")},
+					}, nil).Once()
+
+				candidates, err := p.collectFeedbackCandidates(loop)
+				require.NoError(t, err)
+				ghMock.AssertExpectations(t)
+
+				var sources []int64
+				for _, candidate := range candidates {
+					sources = append(sources, candidate.SourceID)
+				}
+				assert.Equal(t, []int64{101, 102, 201, 301}, sources)
+			})
+		}
+	}
+}
+
+func TestCollectFeedbackCandidates_ReviewCommentErrorAborts(t *testing.T) {
+	p, _, _, ghMock := setupReviewLoopTestPlugin(t)
+
+	loop := &kvstore.ReviewLoop{
+		ID:       "loop-1",
+		Owner:    "org",
+		Repo:     "repo",
+		PRNumber: 42,
+		Phase:    kvstore.ReviewPhaseHumanReview,
+	}
+
+	ghMock.On("ListReviewComments", mock.Anything, "org", "repo", 42).
+		Return([]*github.PullRequestComment(nil), assert.AnError)
+	ghMock.On("ListReviews", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestReview{}, nil).Maybe()
+	ghMock.On("ListIssueComments", mock.Anything, "org", "repo", 42).Return([]*github.IssueComment{}, nil).Maybe()
+
+	candidates, err := p.collectFeedbackCandidates(loop)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Contains(t, err.Error(), "failed to list review comments")
+	assert.Nil(t, candidates)
+}
+
+func TestCollectFeedbackCandidates_ListFailuresStillCollectComments(t *testing.T) {
+	p, api, _, ghMock := setupReviewLoopTestPlugin(t)
+	api.On("LogWarn", mock.Anything, mock.Anything, mock.Anything).Maybe()
+
+	loop := &kvstore.ReviewLoop{
+		ID:       "loop-1",
+		Owner:    "org",
+		Repo:     "repo",
+		PRNumber: 42,
+		Phase:    kvstore.ReviewPhaseHumanReview,
+	}
+
+	ghMock.On("ListReviewComments", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestComment{
+		humanInlineComment(101, "a.go", "Please add a nil guard."),
+	}, nil)
+	ghMock.On("ListReviews", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestReview(nil), assert.AnError)
+	ghMock.On("ListIssueComments", mock.Anything, "org", "repo", 42).Return([]*github.IssueComment(nil), assert.AnError)
+
+	candidates, err := p.collectFeedbackCandidates(loop)
+	require.NoError(t, err)
+	require.Len(t, candidates, 1)
+	assert.Equal(t, int64(101), candidates[0].SourceID)
+}
+
+func TestCollectFeedbackCandidates_SkipsFindingAcknowledgements(t *testing.T) {
+	p, _, _, ghMock := setupReviewLoopTestPlugin(t)
+
+	loop := &kvstore.ReviewLoop{
+		ID:       "loop-1",
+		Owner:    "org",
+		Repo:     "repo",
+		PRNumber: 42,
+		Phase:    kvstore.ReviewPhaseHumanReview,
+	}
+
+	ack := humanInlineComment(102, "a.go", reviewFindingAckText)
+	ack.InReplyTo = github.Ptr(int64(101))
+	ghMock.On("ListReviewComments", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestComment{
+		humanInlineComment(101, "a.go", "Please add a nil guard."),
+		ack,
+	}, nil)
+	ghMock.On("ListReviews", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestReview{}, nil)
+	ghMock.On("ListIssueComments", mock.Anything, "org", "repo", 42).Return([]*github.IssueComment{}, nil)
+
+	candidates, err := p.collectFeedbackCandidates(loop)
+	require.NoError(t, err)
+	require.Len(t, candidates, 1)
+	assert.Equal(t, int64(101), candidates[0].SourceID)
+}
+
+func TestGetFeedbackCollectionConcurrency(t *testing.T) {
+	for value, expected := range map[int]int{-1: 3, 0: 3, 1: 1, 2: 2, 3: 3, 10: 3} {
+		c := &configuration{FeedbackCollectionConcurrency: value}
+		assert.Equal(t, expected, c.GetFeedbackCollectionConcurrency(), "value %d", value)
+	}
+}
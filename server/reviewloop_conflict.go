@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/go-github/v68/github"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/cursor"
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+const (
+	// reviewEventModeConflict and reviewEventModeConflictResolved mark the
+	// history events recorded when a PR's merge conflicts are detected and
+	// cleared.
+	reviewEventModeConflict         = "conflict"
+	reviewEventModeConflictResolved = "conflict_resolved"
+)
+
+// checkReviewLoopMergeConflicts is called from the poller. AI reviewers may
+// stop reviewing a PR with merge conflicts, which would leave the loop waiting
+// forever, so loops awaiting review, and loops with outstanding conflicts, have
+// their PR's mergeable state checked.
+func (p *Plugin) checkReviewLoopMergeConflicts() {
+	if !p.getConfiguration().EnableAIReviewLoop || p.reviewLoopsGloballyPaused() || p.getGitHubClient() == nil {
+		return
+	}
+
+	loops, err := p.kvstore.ListWaitingReviewLoops()
+	if err != nil {
+		p.API.LogError("Failed to list review loops for merge conflict check", "error", err.Error())
+		return
+	}
+	for _, loop := range loops {
+		if loop.Phase != kvstore.ReviewPhaseAwaitingReview && loop.MergeConflictAt == 0 {
+			continue
+		}
+		p.checkReviewLoopMergeConflict(loop)
+	}
+}
+
+// checkReviewLoopMergeConflict fetches the loop's PR and reacts to its
+// mergeable state. An unmergeable PR gets one follow-up per head SHA asking
+// Cursor to resolve the conflicts; a PR that is mergeable again clears the
+// conflict and re-requests the AI reviewers. An unknown state, which GitHub
+// reports while it is still computing mergeability, is left for the next poll.
+func (p *Plugin) checkReviewLoopMergeConflict(loop *kvstore.ReviewLoop) {
+	ghClient := p.getGitHubClient()
+	if ghClient == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	pr, err := ghClient.GetPullRequest(ctx, loop.Owner, loop.Repo, loop.PRNumber)
+	if err != nil {
+		p.API.LogWarn("Failed to get PR for merge conflict check",
+			"review_loop_id", loop.ID,
+			"error", err.Error(),
+		)
+		return
+	}
+	if pr.Mergeable == nil {
+		return
+	}
+
+	if pr.GetMergeable() {
+		if loop.MergeConflictAt != 0 {
+			p.clearReviewLoopMergeConflict(ctx, loop)
+		}
+		return
+	}
+
+	headSHA := pr.GetHead().GetSHA()
+	if loop.MergeConflictAt != 0 && loop.MergeConflictSHA == headSHA {
+		return // Resolution already requested for this head.
+	}
+	p.dispatchMergeConflictResolution(ctx, loop, pr)
+}
+
+// dispatchMergeConflictResolution asks the loop's agent to resolve the PR's
+// merge conflicts and records a conflict event. A failed follow-up leaves the
+// loop unchanged so the next poll retries.
+func (p *Plugin) dispatchMergeConflictResolution(ctx context.Context, loop *kvstore.ReviewLoop, pr *github.PullRequest) {
+	cursorClient := p.getCursorClient()
+	if cursorClient == nil {
+		return
+	}
+
+	if _, err := cursorClient.AddFollowup(ctx, loop.AgentRecordID, cursor.FollowupRequest{
+		Prompt: cursor.Prompt{Text: buildMergeConflictPrompt(loop, pr)},
+	}); err != nil {
+		p.API.LogError("Failed to dispatch merge conflict resolution",
+			"review_loop_id", loop.ID,
+			"error", err.Error(),
+		)
+		return
+	}
+
+	now := p.now().UnixMilli()
+	if loop.MergeConflictAt == 0 {
+		loop.MergeConflictAt = now
+	}
+	loop.MergeConflictSHA = pr.GetHead().GetSHA()
+	loop.History = append(loop.History, kvstore.ReviewLoopEvent{
+		Phase:       loop.Phase,
+		Timestamp:   now,
+		Detail:      fmt.Sprintf("Merge conflicts with %s detected; asked Cursor to resolve them", pr.GetBase().GetRef()),
+		Mode:        reviewEventModeConflict,
+		DispatchSHA: loop.MergeConflictSHA,
+	})
+	loop.UpdatedAt = now
+	if err := p.kvstore.SaveReviewLoop(loop); err != nil {
+		p.API.LogError("Failed to save review loop after merge conflict dispatch",
+			"review_loop_id", loop.ID,
+			"error", err.Error(),
+		)
+		return
+	}
+
+	p.updateReviewLoopInlineStatus(loop)
+	p.publishReviewLoopChange(loop)
+}
+
+// clearReviewLoopMergeConflict records that the PR is mergeable again and
+// re-requests the AI reviewers, which may have skipped the conflicted PR.
+func (p *Plugin) clearReviewLoopMergeConflict(ctx context.Context, loop *kvstore.ReviewLoop) {
+	now := p.now().UnixMilli()
+	loop.MergeConflictAt = 0
+	loop.MergeConflictSHA = ""
+	loop.History = append(loop.History, kvstore.ReviewLoopEvent{
+		Phase:     loop.Phase,
+		Timestamp: now,
+		Detail:    "Merge conflicts resolved; review loop resumed",
+		Mode:      reviewEventModeConflictResolved,
+	})
+	loop.UpdatedAt = now
+	if err := p.kvstore.SaveReviewLoop(loop); err != nil {
+		p.API.LogError("Failed to save review loop after merge conflict cleared",
+			"review_loop_id", loop.ID,
+			"error", err.Error(),
+		)
+		return
+	}
+
+	p.updateReviewLoopInlineStatus(loop)
+	p.publishReviewLoopChange(loop)
+
+	if ghClient := p.getGitHubClient(); ghClient != nil {
+		p.requestAIReviewers(ctx, ghClient, loop.Owner, loop.Repo, loop.PRNumber, loop.PRURL)
+	}
+}
+
+// buildMergeConflictPrompt builds the follow-up asking Cursor to resolve the
+// PR's merge conflicts. It is kept separate from review feedback prompts.
+func buildMergeConflictPrompt(loop *kvstore.ReviewLoop, pr *github.PullRequest) string {
+	base := pr.GetBase().GetRef()
+	head := pr.GetHead().GetRef()
+	return fmt.Sprintf(
+		"The pull request %s has merge conflicts with its base branch `%s`, so reviewers cannot review it.\n\n"+
+			"Please resolve the conflicts:\n"+
+			"1. Merge the latest `%s` into `%s`.\n"+
+			"2. Resolve each conflict, keeping the intent of both sides.\n"+
+			"3. Make sure the code still builds and the tests pass.\n"+
+			"4. Push the result to `%s`.\n\n"+
+			"Do not make changes unrelated to the conflicts.",
+		loop.PRURL, base, base, head, head,
+	)
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v68/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/cursor"
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+func newAwaitingReviewLoop() *kvstore.ReviewLoop {
+	return &kvstore.ReviewLoop{
+		ID:            "loop-1",
+		AgentRecordID: "agent-1",
+		RootPostID:    "root-1",
+		ChannelID:     "ch-1",
+		Owner:         "org",
+		Repo:          "repo",
+		PRNumber:      42,
+		PRURL:         "https://github.com/org/repo/pull/42",
+		Phase:         kvstore.ReviewPhaseAwaitingReview,
+		Iteration:     1,
+	}
+}
+
+func newPullRequestWithMergeable(mergeable *bool, headSHA string) *github.PullRequest {
+	return &github.PullRequest{
+		Mergeable: mergeable,
+		Head:      &github.PullRequestBranch{Ref: github.Ptr("cursor/fix-nil-guard"), SHA: github.Ptr(headSHA)},
+		Base:      &github.PullRequestBranch{Ref: github.Ptr("main")},
+	}
+}
+
+func TestCheckReviewLoopMergeConflicts_ConflictDispatchesResolution(t *testing.T) {
+	p, api, store, ghMock := setupReviewLoopTestPlugin(t)
+	p.configuration.EnableAIReviewLoop = true
+	cursorMock := p.cursorClient.(*mockCursorClient)
+
+	loop := newAwaitingReviewLoop()
+	store.On("ListWaitingReviewLoops").Return([]*kvstore.ReviewLoop{loop}, nil)
+	store.On("SaveReviewLoop", mock.Anything).Return(nil)
+	mockInlineStatusUpdate(store, api, "agent-1", &kvstore.AgentRecord{CursorAgentID: "agent-1"})
+	ghMock.On("GetPullRequest", mock.Anything, "org", "repo", 42).Return(newPullRequestWithMergeable(github.Ptr(false), "sha-1"), nil)
+	cursorMock.On("AddFollowup", mock.Anything, "agent-1", mock.MatchedBy(func(req cursor.FollowupRequest) bool {
+		return strings.Contains(req.Prompt.Text, "merge conflicts with its base branch `main`") &&
+			strings.Contains(req.Prompt.Text, "Push the result to `cursor/fix-nil-guard`")
+	})).Return(&cursor.FollowupResponse{ID: "agent-1"}, nil).Once()
+
+	p.checkReviewLoopMergeConflicts()
+
+	// The same conflicted head is not dispatched twice.
+	p.checkReviewLoopMergeConflicts()
+
+	cursorMock.AssertExpectations(t)
+	assert.Equal(t, kvstore.ReviewPhaseAwaitingReview, loop.Phase)
+	assert.NotZero(t, loop.MergeConflictAt)
+	assert.Equal(t, "sha-1", loop.MergeConflictSHA)
+	require.Len(t, loop.History, 1)
+	assert.Equal(t, reviewEventModeConflict, loop.History[0].Mode)
+	assert.Contains(t, loop.History[0].Detail, "Merge conflicts with main detected")
+}
+
+func TestCheckReviewLoopMergeConflicts_ConflictClearedResumes(t *testing.T) {
+	p, api, store, ghMock := setupReviewLoopTestPlugin(t)
+	p.configuration.EnableAIReviewLoop = true
+	p.configuration.AIReviewerBots = "coderabbitai[bot]"
+	cursorMock := p.cursorClient.(*mockCursorClient)
+
+	loop := newAwaitingReviewLoop()
+	loop.MergeConflictAt = 1700000000000
+	loop.MergeConflictSHA = "sha-1"
+	store.On("ListWaitingReviewLoops").Return([]*kvstore.ReviewLoop{loop}, nil)
+	store.On("SaveReviewLoop", mock.Anything).Return(nil).Once()
+	mockInlineStatusUpdate(store, api, "agent-1", &kvstore.AgentRecord{CursorAgentID: "agent-1"})
+	ghMock.On("GetPullRequest", mock.Anything, "org", "repo", 42).Return(newPullRequestWithMergeable(github.Ptr(true), "sha-2"), nil)
+	ghMock.On("RequestReviewers", mock.Anything, "org", "repo", 42, mock.Anything).Return(nil).Once()
+
+	p.checkReviewLoopMergeConflicts()
+
+	ghMock.AssertExpectations(t)
+	cursorMock.AssertNotCalled(t, "AddFollowup", mock.Anything, mock.Anything, mock.Anything)
+	assert.Zero(t, loop.MergeConflictAt)
+	assert.Empty(t, loop.MergeConflictSHA)
+	require.Len(t, loop.History, 1)
+	assert.Equal(t, reviewEventModeConflictResolved, loop.History[0].Mode)
+}
+
+func TestCheckReviewLoopMergeConflict_UnknownMergeableIsLeftForNextPoll(t *testing.T) {
+	p, _, store, ghMock := setupReviewLoopTestPlugin(t)
+	cursorMock := p.cursorClient.(*mockCursorClient)
+
+	loop := newAwaitingReviewLoop()
+	ghMock.On("GetPullRequest", mock.Anything, "org", "repo", 42).Return(newPullRequestWithMergeable(nil, "sha-1"), nil)
+
+	p.checkReviewLoopMergeConflict(loop)
+
+	cursorMock.AssertNotCalled(t, "AddFollowup", mock.Anything, mock.Anything, mock.Anything)
+	store.AssertNotCalled(t, "SaveReviewLoop", mock.Anything)
+	assert.Zero(t, loop.MergeConflictAt)
+}
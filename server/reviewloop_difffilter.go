@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+// diffChangedLines maps a repository-relative file path to the new-side line
+// numbers the diff adds or modifies in it.
+type diffChangedLines map[string]map[int]bool
+
+// parseDiffChangedLines collects the added lines of each file in a unified
+// diff. Context and removed lines are not counted as changed, and deleted
+// files have no entry.
+func parseDiffChangedLines(diff string) diffChangedLines {
+	changed := diffChangedLines{}
+	var lines map[int]bool
+	inHunk := false
+	newLine := 0
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			lines = nil
+			inHunk = false
+		case !inHunk && strings.HasPrefix(line, "+++ "):
+			lines = nil
+			if filePath, ok := strings.CutPrefix(strings.TrimSpace(line[len("+++ "):]), "b/"); ok {
+				lines = changed[filePath]
+				if lines == nil {
+					lines = map[int]bool{}
+					changed[filePath] = lines
+				}
+			}
+		case strings.HasPrefix(line, "@@ "):
+			start, ok := parseHunkNewStart(line)
+			inHunk = ok
+			newLine = start
+		case !inHunk:
+		case strings.HasPrefix(line, "+"):
+			if lines != nil {
+				lines[newLine] = true
+			}
+			newLine++
+		case strings.HasPrefix(line, " "):
+			newLine++
+		}
+	}
+	return changed
+}
+
+// parseHunkNewStart returns the new-side start line of a hunk header such as
+// "@@ -10,4 +12,6 @@ func main() {".
+func parseHunkNewStart(header string) (int, bool) {
+	fields := strings.Fields(header)
+	if len(fields) < 3 || !strings.HasPrefix(fields[2], "+") {
+		return 0, false
+	}
+	start, _, _ := strings.Cut(fields[2][1:], ",")
+	n, err := strconv.Atoi(start)
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+// onUnchangedLine reports whether candidate is tied to a file and line that
+// the diff does not change. Findings without a path or line are never
+// considered unchanged.
+func (c diffChangedLines) onUnchangedLine(candidate reviewFeedbackCandidate) bool {
+	filePath := strings.TrimPrefix(candidate.Path, "/")
+	if filePath == "" || candidate.Line <= 0 {
+		return false
+	}
+	return !c[filePath][candidate.Line]
+}
+
+// loadReviewLoopChangedLines fetches the PR diff at its current head when
+// ReviewLoopDropUnchangedLineFindings is enabled. It returns nil when the
+// option is off or the diff cannot be fetched, in which case no finding is
+// dropped for being on an unchanged line.
+func (p *Plugin) loadReviewLoopChangedLines(loop *kvstore.ReviewLoop) diffChangedLines {
+	if !p.getConfiguration().ReviewLoopDropUnchangedLineFindings {
+		return nil
+	}
+	ghClient := p.getGitHubClient()
+	if ghClient == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	diff, err := ghClient.GetPullRequestDiff(ctx, loop.Owner, loop.Repo, loop.PRNumber)
+	if err != nil {
+		p.API.LogWarn("Failed to fetch PR diff; not filtering findings on unchanged lines",
+			"review_loop_id", loop.ID,
+			"error", err.Error(),
+		)
+		return nil
+	}
+	return parseDiffChangedLines(diff)
+}
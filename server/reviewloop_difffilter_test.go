@@ -0,0 +1,139 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-github/v68/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testPRDiff = `diff --git a/server/x.go b/server/x.go
+index 1111111..2222222 100644
+--- a/server/x.go
++++ b/server/x.go
+@@ -5,4 +5,5 @@ func run() {
+ 	a := load()
+-	a.Close()
++	if a != nil {
++		a.Close()
++	}
+ 	return
+@@ -20,2 +21,3 @@ func stop() {
+ 	done()
++++counter
+ }
+diff --git a/server/old.go b/server/old.go
+deleted file mode 100644
+--- a/server/old.go
++++ /dev/null
+@@ -1,2 +0,0 @@
+-package main
+-var old = 1
+diff --git a/docs/new.md b/docs/new.md
+new file mode 100644
+--- /dev/null
++++ b/docs/new.md
+@@ -0,0 +1,2 @@
++# New
++\ No newline at end of file
+`
+
+func TestParseDiffChangedLines(t *testing.T) {
+	changed := parseDiffChangedLines(testPRDiff)
+
+	assert.Equal(t, map[int]bool{6: true, 7: true, 8: true, 22: true}, changed["server/x.go"])
+	assert.Equal(t, map[int]bool{1: true, 2: true}, changed["docs/new.md"])
+	assert.NotContains(t, changed, "server/old.go")
+	assert.Empty(t, parseDiffChangedLines(""))
+}
+
+func TestDiffChangedLinesOnUnchangedLine(t *testing.T) {
+	changed := parseDiffChangedLines(testPRDiff)
+
+	assert.False(t, changed.onUnchangedLine(reviewFeedbackCandidate{Path: "server/x.go", Line: 7}))
+	assert.False(t, changed.onUnchangedLine(reviewFeedbackCandidate{Path: "/server/x.go", Line: 22}))
+	assert.True(t, changed.onUnchangedLine(reviewFeedbackCandidate{Path: "server/x.go", Line: 5}))
+	assert.True(t, changed.onUnchangedLine(reviewFeedbackCandidate{Path: "server/other.go", Line: 7}))
+	assert.False(t, changed.onUnchangedLine(reviewFeedbackCandidate{Path: "server/x.go"}))
+	assert.False(t, changed.onUnchangedLine(reviewFeedbackCandidate{}))
+}
+
+func mockDiffFilterReviewComments(ghMock *mockGitHubClient) {
+	ghMock.On("ListReviewComments", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestComment{
+		{
+			ID:       github.Ptr(int64(1)),
+			User:     &github.User{Login: github.Ptr("coderabbitai[bot]")},
+			Path:     github.Ptr("server/x.go"),
+			Line:     github.Ptr(7),
+			Body:     github.Ptr("Prompt for AI Agents\nAdd a nil guard before dereferencing."),
+			CommitID: github.Ptr("sha-2"),
+		},
+		{
+			ID:       github.Ptr(int64(2)),
+			User:     &github.User{Login: github.Ptr("coderabbitai[bot]")},
+			Path:     github.Ptr("server/x.go"),
+			Line:     github.Ptr(30),
+			Body:     github.Ptr("Prompt for AI Agents\nRename the helper for clarity."),
+			CommitID: github.Ptr("sha-2"),
+		},
+	}, nil)
+	ghMock.On("ListReviews", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestReview{}, nil)
+	ghMock.On("ListIssueComments", mock.Anything, "org", "repo", 42).Return([]*github.IssueComment{}, nil)
+}
+
+func TestCollectReviewFeedbackBundle_DropsFindingsOnUnchangedLines(t *testing.T) {
+	p, api, _, ghMock := setupReviewLoopTestPlugin(t)
+	p.configuration.EnableDebugLogging = true
+	p.configuration.ReviewLoopDropUnchangedLineFindings = true
+
+	mockDiffFilterReviewComments(ghMock)
+	ghMock.On("GetPullRequestDiff", mock.Anything, "org", "repo", 42).Return(testPRDiff, nil).Once()
+
+	loop := newAwaitingReviewLoop()
+	classification, _, feedback, err := p.collectReviewFeedbackBundle(loop)
+	require.NoError(t, err)
+
+	ghMock.AssertExpectations(t)
+	require.Len(t, classification.Dispatchable, 1)
+	assert.Equal(t, 7, classification.Dispatchable[0].Line)
+	assert.Contains(t, feedback, "Add a nil guard before dereferencing.")
+	assert.NotContains(t, feedback, "Rename the helper for clarity.")
+
+	droppedLogs := collectDroppedCandidateLogs(api)
+	require.Len(t, droppedLogs, 1)
+	assert.True(t, hasDroppedCandidateLog(droppedLogs, reviewerExtractionDropReasonUnchangedLine, reviewerExtractionRouteCodeRabbit))
+
+	require.Len(t, loop.DroppedCandidates, 1)
+	assert.Equal(t, reviewerExtractionDropReasonUnchangedLine, loop.DroppedCandidates[0].Reason)
+	assert.Equal(t, 30, loop.DroppedCandidates[0].Line)
+}
+
+func TestCollectReviewFeedbackBundle_KeepsFindingsWhenDiffUnavailable(t *testing.T) {
+	p, _, _, ghMock := setupReviewLoopTestPlugin(t)
+	p.configuration.ReviewLoopDropUnchangedLineFindings = true
+
+	mockDiffFilterReviewComments(ghMock)
+	ghMock.On("GetPullRequestDiff", mock.Anything, "org", "repo", 42).Return("", errors.New("boom")).Once()
+
+	loop := newAwaitingReviewLoop()
+	classification, _, _, err := p.collectReviewFeedbackBundle(loop)
+	require.NoError(t, err)
+
+	assert.Len(t, classification.Dispatchable, 2)
+	assert.Empty(t, loop.DroppedCandidates)
+}
+
+func TestCollectReviewFeedbackBundle_DiffFilterDisabled(t *testing.T) {
+	p, _, _, ghMock := setupReviewLoopTestPlugin(t)
+
+	mockDiffFilterReviewComments(ghMock)
+
+	classification, _, _, err := p.collectReviewFeedbackBundle(newAwaitingReviewLoop())
+	require.NoError(t, err)
+
+	assert.Len(t, classification.Dispatchable, 2)
+	ghMock.AssertNotCalled(t, "GetPullRequestDiff", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+// reviewLoopDigestMaxLen keeps each digest post well under the post size
+// limit; longer digests are split across several posts.
+const reviewLoopDigestMaxLen = 12000
+
+// defaultReviewLoopDigestTime is used when a digest channel is set without a
+// time.
+const defaultReviewLoopDigestTime = "09:00"
+
+// reviewLoopDigestSchedule is the parsed daily review loop digest schedule.
+type reviewLoopDigestSchedule struct {
+	channelID string
+	minute    int // Minutes after local midnight
+	location  *time.Location
+}
+
+// parseReviewLoopDigestSchedule parses a daily "HH:MM" digest time in the
+// given IANA timezone. An empty timezone means UTC.
+func parseReviewLoopDigestSchedule(channelID, clock, timezone string) (*reviewLoopDigestSchedule, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(clock))
+	if err != nil {
+		return nil, fmt.Errorf("invalid digest time %q, expected HH:MM", strings.TrimSpace(clock))
+	}
+
+	location := time.UTC
+	if tz := strings.TrimSpace(timezone); tz != "" {
+		location, err = time.LoadLocation(tz)
+		if err != nil {
+			return nil, fmt.Errorf("unknown digest timezone %q: %w", tz, err)
+		}
+	}
+
+	return &reviewLoopDigestSchedule{
+		channelID: strings.TrimSpace(channelID),
+		minute:    t.Hour()*60 + t.Minute(),
+		location:  location,
+	}, nil
+}
+
+// due returns the schedule's local date for now and whether that day's digest
+// should be posted: the digest time has passed and no digest was posted for
+// the date yet.
+func (s *reviewLoopDigestSchedule) due(now time.Time, lastDate string) (string, bool) {
+	local := now.In(s.location)
+	date := local.Format(time.DateOnly)
+	return date, date != lastDate && local.Hour()*60+local.Minute() >= s.minute
+}
+
+// postScheduledReviewLoopDigest posts the daily summary of in-flight review
+// loops to ReviewLoopDigestChannelID once the configured time has passed.
+// Nothing is posted when no loops are active.
+func (p *Plugin) postScheduledReviewLoopDigest() {
+	config := p.getConfiguration()
+	schedule := config.GetReviewLoopDigestSchedule()
+	if !config.EnableAIReviewLoop || schedule == nil {
+		return
+	}
+
+	now := p.now()
+	lastDate, err := p.kvstore.GetReviewLoopDigestDate()
+	if err != nil {
+		p.API.LogError("Failed to load review loop digest date", "error", err.Error())
+		return
+	}
+	date, due := schedule.due(now, lastDate)
+	if !due {
+		return
+	}
+
+	// Record the date first so a failing post is not retried on every poll.
+	if err := p.kvstore.SetReviewLoopDigestDate(date); err != nil {
+		p.API.LogError("Failed to save review loop digest date", "error", err.Error())
+		return
+	}
+
+	loops, err := p.kvstore.ListActiveReviewLoops()
+	if err != nil {
+		p.API.LogError("Failed to list active review loops for digest", "error", err.Error())
+		return
+	}
+	if len(loops) == 0 {
+		p.API.LogDebug("Skipping review loop digest with no active loops", "date", date)
+		return
+	}
+
+	for _, message := range buildReviewLoopDigest(loops, now, reviewLoopDigestMaxLen) {
+		if _, appErr := p.API.CreatePost(p.decorateBotPost(&model.Post{
+			UserId:    p.botUserIDForChannel(schedule.channelID),
+			ChannelId: schedule.channelID,
+			Message:   message,
+		})); appErr != nil {
+			p.API.LogError("Failed to post review loop digest",
+				"channel_id", schedule.channelID,
+				"error", appErr.Error(),
+			)
+			return
+		}
+	}
+}
+
+// buildReviewLoopDigest renders active loops grouped by repository, oldest
+// first, as one or more posts of at most maxLen characters. Returns nil when
+// there are no loops.
+func buildReviewLoopDigest(loops []*kvstore.ReviewLoop, now time.Time, maxLen int) []string {
+	if len(loops) == 0 {
+		return nil
+	}
+
+	byRepo := make(map[string][]*kvstore.ReviewLoop)
+	for _, loop := range loops {
+		repo := reviewLoopDigestRepository(loop)
+		byRepo[repo] = append(byRepo[repo], loop)
+	}
+	repos := make([]string, 0, len(byRepo))
+	for repo := range byRepo {
+		repos = append(repos, repo)
+	}
+	sort.Strings(repos)
+
+	var pages []string
+	var page strings.Builder
+	for _, repo := range repos {
+		repoLoops := byRepo[repo]
+		sort.SliceStable(repoLoops, func(i, j int) bool {
+			return repoLoops[i].CreatedAt < repoLoops[j].CreatedAt
+		})
+
+		heading := fmt.Sprintf("\n**%s**\n", repo)
+		page.WriteString(heading)
+		for _, loop := range repoLoops {
+			line := formatReviewLoopDigestLine(loop, now)
+			if page.Len()+len(line) > maxLen && page.Len() > len(heading) {
+				pages = append(pages, page.String())
+				page.Reset()
+				page.WriteString(fmt.Sprintf("\n**%s** (continued)\n", repo))
+			}
+			page.WriteString(line)
+		}
+	}
+	pages = append(pages, page.String())
+
+	header := fmt.Sprintf("#### Review loop digest\n%d open review loop(s) across %d repository(ies).\n", len(loops), len(repos))
+	messages := make([]string, len(pages))
+	for i, body := range pages {
+		if len(pages) > 1 {
+			messages[i] = fmt.Sprintf("%s_Page %d of %d_\n%s", header, i+1, len(pages), body)
+		} else {
+			messages[i] = header + body
+		}
+	}
+	return messages
+}
+
+func reviewLoopDigestRepository(loop *kvstore.ReviewLoop) string {
+	if repo := strings.TrimSpace(loop.Repository); repo != "" {
+		return repo
+	}
+	if repo := strings.Trim(strings.TrimSpace(loop.Owner+"/"+loop.Repo), "/"); repo != "" {
+		return repo
+	}
+	return "Unknown repository"
+}
+
+func formatReviewLoopDigestLine(loop *kvstore.ReviewLoop, now time.Time) string {
+	pr := loop.PRURL
+	if loop.PRNumber > 0 && loop.PRURL != "" {
+		pr = fmt.Sprintf("[PR #%d](%s)", loop.PRNumber, loop.PRURL)
+	}
+	return fmt.Sprintf("- %s: `%s`, iteration %d, open %s\n",
+		pr, loop.Phase, loop.Iteration, formatReviewLoopAge(now.Sub(time.UnixMilli(loop.CreatedAt))))
+}
+
+// formatReviewLoopAge renders an age as days and hours, e.g. "2d 5h".
+func formatReviewLoopAge(age time.Duration) string {
+	if age < time.Hour {
+		return "<1h"
+	}
+	days := int(age / (24 * time.Hour))
+	hours := int(age%(24*time.Hour)) / int(time.Hour)
+	if days == 0 {
+		return fmt.Sprintf("%dh", hours)
+	}
+	return fmt.Sprintf("%dd %dh", days, hours)
+}
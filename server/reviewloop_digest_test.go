@@ -0,0 +1,133 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+func TestBuildReviewLoopDigest_GroupsByRepository(t *testing.T) {
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	loops := []*kvstore.ReviewLoop{
+		{ID: "rl-1", Repository: "org/web", PRNumber: 7, PRURL: "https://github.com/org/web/pull/7", Phase: kvstore.ReviewPhaseCursorFixing, Iteration: 2, CreatedAt: now.Add(-5 * time.Hour).UnixMilli()},
+		{ID: "rl-2", Repository: "org/api", PRNumber: 42, PRURL: "https://github.com/org/api/pull/42", Phase: kvstore.ReviewPhaseAwaitingReview, Iteration: 1, CreatedAt: now.Add(-53 * time.Hour).UnixMilli()},
+		{ID: "rl-3", Repository: "org/web", PRNumber: 3, PRURL: "https://github.com/org/web/pull/3", Phase: kvstore.ReviewPhaseHumanReview, Iteration: 4, CreatedAt: now.Add(-30 * time.Minute).UnixMilli()},
+	}
+
+	messages := buildReviewLoopDigest(loops, now, reviewLoopDigestMaxLen)
+
+	require.Len(t, messages, 1)
+	assert.Equal(t, "#### Review loop digest\n3 open review loop(s) across 2 repository(ies).\n"+
+		"\n**org/api**\n"+
+		"- [PR #42](https://github.com/org/api/pull/42): `awaiting_review`, iteration 1, open 2d 5h\n"+
+		"\n**org/web**\n"+
+		"- [PR #7](https://github.com/org/web/pull/7): `cursor_fixing`, iteration 2, open 5h\n"+
+		"- [PR #3](https://github.com/org/web/pull/3): `human_review`, iteration 4, open <1h\n",
+		messages[0])
+}
+
+func TestBuildReviewLoopDigest_Paginates(t *testing.T) {
+	now := time.Now()
+	var loops []*kvstore.ReviewLoop
+	for i := 1; i <= 6; i++ {
+		loops = append(loops, &kvstore.ReviewLoop{
+			ID:         "rl",
+			Repository: "org/repo",
+			PRNumber:   i,
+			PRURL:      "https://github.com/org/repo/pull/1",
+			Phase:      kvstore.ReviewPhaseAwaitingReview,
+			CreatedAt:  now.Add(time.Duration(-i) * time.Hour).UnixMilli(),
+		})
+	}
+
+	messages := buildReviewLoopDigest(loops, now, 200)
+
+	require.Greater(t, len(messages), 1)
+	total := 0
+	for i, message := range messages {
+		assert.Contains(t, message, "6 open review loop(s)")
+		assert.Contains(t, message, "_Page ")
+		if i > 0 {
+			assert.Contains(t, message, "**org/repo** (continued)")
+		}
+		total += strings.Count(message, "- [PR #")
+	}
+	assert.Equal(t, 6, total)
+}
+
+func TestBuildReviewLoopDigest_NoLoops(t *testing.T) {
+	assert.Nil(t, buildReviewLoopDigest(nil, time.Now(), reviewLoopDigestMaxLen))
+}
+
+func TestReviewLoopDigestScheduleDue(t *testing.T) {
+	schedule, err := parseReviewLoopDigestSchedule("ch-digest", "09:30", "America/New_York")
+	require.NoError(t, err)
+
+	// 13:00 UTC is 09:00 in New York: not yet due.
+	_, due := schedule.due(time.Date(2026, 10, 17, 13, 0, 0, 0, time.UTC), "")
+	assert.False(t, due)
+
+	date, due := schedule.due(time.Date(2026, 10, 17, 13, 45, 0, 0, time.UTC), "")
+	assert.True(t, due)
+	assert.Equal(t, "2026-10-17", date)
+
+	// Already posted today.
+	_, due = schedule.due(time.Date(2026, 10, 17, 20, 0, 0, 0, time.UTC), "2026-10-17")
+	assert.False(t, due)
+
+	_, err = parseReviewLoopDigestSchedule("ch-digest", "9am", "")
+	assert.Error(t, err)
+}
+
+func TestPostScheduledReviewLoopDigest_PostsToChannel(t *testing.T) {
+	p, api, store, _ := setupReviewLoopTestPlugin(t)
+	p.configuration.ReviewLoopDigestChannelID = "ch-digest"
+	p.configuration.ReviewLoopDigestTime = "00:00"
+
+	loop := &kvstore.ReviewLoop{ID: "rl-1", Repository: "org/repo", PRNumber: 42, PRURL: "https://github.com/org/repo/pull/42", Phase: kvstore.ReviewPhaseAwaitingReview, CreatedAt: time.Now().UnixMilli()}
+	store.On("GetReviewLoopDigestDate").Return("2000-01-01", nil)
+	store.On("SetReviewLoopDigestDate", mock.AnythingOfType("string")).Return(nil).Once()
+	store.On("ListActiveReviewLoops").Return([]*kvstore.ReviewLoop{loop}, nil)
+	api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		return post.ChannelId == "ch-digest" &&
+			post.RootId == "" &&
+			strings.Contains(post.Message, "**org/repo**") &&
+			strings.Contains(post.Message, "[PR #42](https://github.com/org/repo/pull/42)")
+	})).Return(&model.Post{}, nil).Once()
+
+	p.postScheduledReviewLoopDigest()
+
+	store.AssertExpectations(t)
+	api.AssertExpectations(t)
+}
+
+func TestPostScheduledReviewLoopDigest_SkipsWithoutActiveLoops(t *testing.T) {
+	p, api, store, _ := setupReviewLoopTestPlugin(t)
+	p.configuration.ReviewLoopDigestChannelID = "ch-digest"
+	p.configuration.ReviewLoopDigestTime = "00:00"
+
+	store.On("GetReviewLoopDigestDate").Return("", nil)
+	store.On("SetReviewLoopDigestDate", mock.AnythingOfType("string")).Return(nil).Once()
+	store.On("ListActiveReviewLoops").Return([]*kvstore.ReviewLoop{}, nil)
+	api.On("LogDebug", mock.Anything, mock.Anything, mock.Anything).Maybe()
+
+	p.postScheduledReviewLoopDigest()
+
+	store.AssertExpectations(t)
+	api.AssertNotCalled(t, "CreatePost", mock.Anything)
+}
+
+func TestPostScheduledReviewLoopDigest_DisabledWithoutChannel(t *testing.T) {
+	p, _, store, _ := setupReviewLoopTestPlugin(t)
+
+	p.postScheduledReviewLoopDigest()
+
+	store.AssertNotCalled(t, "GetReviewLoopDigestDate")
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"github.com/mattermost/mattermost-plugin-cursor/server/command"
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+// dispatchReviewLoopNow collects review feedback for loop and dispatches it
+// to the agent on demand, for /cursor review dispatch. It goes through the
+// same checks as a webhook-triggered dispatch, so an unchanged bundle is
+// skipped as a duplicate, nothing is sent while review loops are globally
+// paused, and a loop that has used all of its fix iterations gets no more.
+// The dispatch targets the last PR head the loop saw.
+func (p *Plugin) dispatchReviewLoopNow(loop *kvstore.ReviewLoop) (command.ReviewDispatchResult, error) {
+	if p.reviewLoopsGloballyPaused() {
+		return command.ReviewDispatchResult{Outcome: command.ReviewDispatchPaused, Reason: command.ReviewDispatchPaused}, nil
+	}
+	if loop.Iteration >= p.getConfiguration().MaxReviewIterations {
+		return command.ReviewDispatchResult{Outcome: command.ReviewDispatchMaxIterations, Reason: command.ReviewDispatchMaxIterations}, nil
+	}
+
+	pr := ghPullRequest{}
+	pr.Number = loop.PRNumber
+	pr.HTMLURL = loop.PRURL
+	pr.Head.SHA = loop.LastCommitSHA
+	if record, err := p.kvstore.GetAgent(loop.AgentRecordID); err == nil && record != nil {
+		pr.Head.Ref = record.TargetBranch
+	}
+
+	outcome, err := p.redispatchReviewFeedback(loop, pr, "dispatched on demand")
+	if err != nil {
+		return command.ReviewDispatchResult{}, err
+	}
+
+	result := command.ReviewDispatchResult{
+		Outcome:   command.ReviewDispatchSkipped,
+		Reason:    outcome.Mode,
+		New:       outcome.Counts.New,
+		Repeated:  outcome.Counts.Repeated,
+		Dismissed: outcome.Counts.Dismissed,
+	}
+	switch {
+	case outcome.Dispatched:
+		result.Outcome = command.ReviewDispatchDispatched
+	case outcome.Failed:
+		result.Outcome = command.ReviewDispatchFailed
+	}
+	return result, nil
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/command"
+	"github.com/mattermost/mattermost-plugin-cursor/server/cursor"
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+func newDispatchNowTestLoop() *kvstore.ReviewLoop {
+	return &kvstore.ReviewLoop{
+		ID:            "loop-1",
+		AgentRecordID: "agent-1",
+		UserID:        "user-1",
+		RootPostID:    "root-1",
+		ChannelID:     "ch-1",
+		Owner:         "org",
+		Repo:          "repo",
+		PRNumber:      42,
+		PRURL:         "https://github.com/org/repo/pull/42",
+		Phase:         kvstore.ReviewPhaseAwaitingReview,
+		Iteration:     1,
+		LastCommitSHA: "sha-1",
+	}
+}
+
+func TestDispatchReviewLoopNow_DispatchesNewFeedback(t *testing.T) {
+	p, api, store, ghMock := setupReviewLoopTestPlugin(t)
+	cursorMock := p.cursorClient.(*mockCursorClient)
+
+	loop := newDispatchNowTestLoop()
+	store.On("SaveReviewLoop", mock.Anything).Return(nil)
+	mockInlineStatusUpdate(store, api, "agent-1", &kvstore.AgentRecord{
+		CursorAgentID: "agent-1",
+		TargetBranch:  "cursor/fix-nil-guard",
+	})
+	mockCheckpointReviewFeedback(ghMock)
+
+	cursorMock.On("AddFollowup", mock.Anything, "agent-1", mock.MatchedBy(func(req cursor.FollowupRequest) bool {
+		return strings.Contains(req.Prompt.Text, "Add a nil guard before dereferencing.") &&
+			strings.Contains(req.Prompt.Text, "- head_sha: sha-1") &&
+			strings.Contains(req.Prompt.Text, "- branch: cursor/fix-nil-guard")
+	})).Return(&cursor.FollowupResponse{ID: "agent-1"}, nil).Once()
+
+	result, err := p.dispatchReviewLoopNow(loop)
+	require.NoError(t, err)
+
+	assert.Equal(t, command.ReviewDispatchDispatched, result.Outcome)
+	assert.Equal(t, reviewDispatchModeDirect, result.Reason)
+	assert.Equal(t, 1, result.New)
+	assert.Equal(t, kvstore.ReviewPhaseCursorFixing, loop.Phase)
+	assert.Equal(t, 2, loop.Iteration)
+	require.NotEmpty(t, loop.History)
+	assert.Contains(t, loop.History[len(loop.History)-1].Detail, "dispatched on demand")
+	cursorMock.AssertExpectations(t)
+}
+
+func TestDispatchReviewLoopNow_SkipsUnchangedFeedback(t *testing.T) {
+	p, api, store, ghMock := setupReviewLoopTestPlugin(t)
+	cursorMock := p.cursorClient.(*mockCursorClient)
+
+	loop := newDispatchNowTestLoop()
+	store.On("SaveReviewLoop", mock.Anything).Return(nil)
+	mockInlineStatusUpdate(store, api, "agent-1", &kvstore.AgentRecord{CursorAgentID: "agent-1"})
+	mockCheckpointReviewFeedback(ghMock)
+	cursorMock.On("AddFollowup", mock.Anything, "agent-1", mock.Anything).
+		Return(&cursor.FollowupResponse{ID: "agent-1"}, nil).Once()
+
+	_, err := p.dispatchReviewLoopNow(loop)
+	require.NoError(t, err)
+
+	// Nothing changed on the PR since the last dispatch.
+	loop.Phase = kvstore.ReviewPhaseAwaitingReview
+	result, err := p.dispatchReviewLoopNow(loop)
+	require.NoError(t, err)
+
+	assert.Equal(t, command.ReviewDispatchSkipped, result.Outcome)
+	assert.Equal(t, reviewDispatchModeSkippedIdempotent, result.Reason)
+	assert.Equal(t, kvstore.ReviewPhaseAwaitingReview, loop.Phase)
+	assert.Equal(t, 2, loop.Iteration)
+	cursorMock.AssertNumberOfCalls(t, "AddFollowup", 1)
+}
+
+func TestDispatchReviewLoopNow_RespectsMaxIterations(t *testing.T) {
+	p, _, store, _ := setupReviewLoopTestPlugin(t)
+	cursorMock := p.cursorClient.(*mockCursorClient)
+
+	loop := newDispatchNowTestLoop()
+	loop.Iteration = p.configuration.MaxReviewIterations
+
+	result, err := p.dispatchReviewLoopNow(loop)
+	require.NoError(t, err)
+
+	assert.Equal(t, command.ReviewDispatchMaxIterations, result.Outcome)
+	assert.Equal(t, kvstore.ReviewPhaseAwaitingReview, loop.Phase)
+	assert.Equal(t, 5, loop.Iteration)
+	store.AssertNotCalled(t, "SaveReviewLoop", mock.Anything)
+	cursorMock.AssertNotCalled(t, "AddFollowup", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDispatchReviewLoopNow_SkipsWhileGloballyPaused(t *testing.T) {
+	p, _, store, _ := setupReviewLoopTestPlugin(t)
+	cursorMock := p.cursorClient.(*mockCursorClient)
+	p.configuration.ReviewLoopGloballyPaused = true
+
+	loop := newDispatchNowTestLoop()
+
+	result, err := p.dispatchReviewLoopNow(loop)
+	require.NoError(t, err)
+
+	assert.Equal(t, command.ReviewDispatchPaused, result.Outcome)
+	assert.Equal(t, kvstore.ReviewPhaseAwaitingReview, loop.Phase)
+	store.AssertNotCalled(t, "SaveReviewLoop", mock.Anything)
+	cursorMock.AssertNotCalled(t, "AddFollowup", mock.Anything, mock.Anything, mock.Anything)
+}
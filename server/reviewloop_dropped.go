@@ -0,0 +1,50 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+// maxDroppedCandidatesRetained bounds the dropped candidates kept per loop.
+const maxDroppedCandidatesRetained = 50
+
+// recordDroppedCandidate remembers a dropped feedback candidate on the loop so
+// it can be audited later. A candidate dropped again for the same reason is
+// refreshed and moved to the end rather than duplicated. The loop is persisted
+// by the caller along with the rest of the collection results.
+func recordDroppedCandidate(loop *kvstore.ReviewLoop, candidate reviewFeedbackCandidate, route reviewerExtractionRoute, dropReason string, now int64) {
+	if strings.TrimSpace(dropReason) == "" {
+		dropReason = reviewFeedbackDropReasonUnknown
+	}
+	dropped := kvstore.DroppedCandidate{
+		Reason:        dropReason,
+		Route:         string(route),
+		SourceType:    candidate.SourceType,
+		SourceID:      candidate.SourceID,
+		SourceURL:     candidate.SourceURL,
+		ReviewerLogin: candidate.ReviewerLogin,
+		Path:          candidate.Path,
+		Line:          candidate.Line,
+		CommitSHA:     candidate.CommitSHA,
+		Iteration:     loop.Iteration,
+		DroppedAt:     now,
+	}
+
+	retained := make([]kvstore.DroppedCandidate, 0, len(loop.DroppedCandidates)+1)
+	for _, existing := range loop.DroppedCandidates {
+		if existing.SourceType == dropped.SourceType &&
+			existing.SourceID == dropped.SourceID &&
+			existing.Path == dropped.Path &&
+			existing.Line == dropped.Line &&
+			existing.Reason == dropped.Reason {
+			continue
+		}
+		retained = append(retained, existing)
+	}
+	retained = append(retained, dropped)
+	if len(retained) > maxDroppedCandidatesRetained {
+		retained = retained[len(retained)-maxDroppedCandidatesRetained:]
+	}
+	loop.DroppedCandidates = retained
+}
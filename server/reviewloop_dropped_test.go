@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+func TestRecordDroppedCandidate_RefreshesRepeatsAndBounds(t *testing.T) {
+	loop := &kvstore.ReviewLoop{ID: "loop-1", Iteration: 1}
+	candidate := reviewFeedbackCandidate{
+		SourceType:    "review_comment",
+		SourceID:      1,
+		ReviewerLogin: "coderabbitai[bot]",
+		Path:          "vendor/x.go",
+		Line:          3,
+	}
+
+	recordDroppedCandidate(loop, candidate, reviewerExtractionRouteCodeRabbit, reviewerExtractionDropReasonIgnoredPath, 1000)
+	recordDroppedCandidate(loop, reviewFeedbackCandidate{SourceType: "issue_comment", SourceID: 2}, reviewerExtractionRouteNonCodeRabbit, "", 1500)
+	loop.Iteration = 2
+	recordDroppedCandidate(loop, candidate, reviewerExtractionRouteCodeRabbit, reviewerExtractionDropReasonIgnoredPath, 2000)
+
+	require.Len(t, loop.DroppedCandidates, 2)
+	assert.Equal(t, reviewFeedbackDropReasonUnknown, loop.DroppedCandidates[0].Reason)
+	assert.Equal(t, kvstore.DroppedCandidate{
+		Reason:        reviewerExtractionDropReasonIgnoredPath,
+		Route:         string(reviewerExtractionRouteCodeRabbit),
+		SourceType:    "review_comment",
+		SourceID:      1,
+		ReviewerLogin: "coderabbitai[bot]",
+		Path:          "vendor/x.go",
+		Line:          3,
+		Iteration:     2,
+		DroppedAt:     2000,
+	}, loop.DroppedCandidates[1])
+
+	for i := 0; i < maxDroppedCandidatesRetained+5; i++ {
+		recordDroppedCandidate(loop, reviewFeedbackCandidate{SourceType: "issue_comment", SourceID: int64(100 + i)}, reviewerExtractionRouteNonCodeRabbit, "empty_text", int64(3000+i))
+	}
+	require.Len(t, loop.DroppedCandidates, maxDroppedCandidatesRetained)
+	assert.Equal(t, int64(100+maxDroppedCandidatesRetained+4), loop.DroppedCandidates[maxDroppedCandidatesRetained-1].SourceID)
+}
+
+func TestGetDroppedCandidates_AggregatesUserLoops(t *testing.T) {
+	p, _, _, store := setupAPITestPlugin(t)
+
+	loops := []*kvstore.ReviewLoop{
+		{
+			ID:         "loop-1",
+			UserID:     "user-1",
+			PRURL:      "https://github.com/org/repo/pull/42",
+			Repository: "org/repo",
+			DroppedCandidates: []kvstore.DroppedCandidate{
+				{Reason: reviewerExtractionDropReasonIgnoredPath, Route: "coderabbit", SourceType: "review_comment", Path: "vendor/x.go", Iteration: 1, DroppedAt: 1000},
+				{Reason: "empty_text", Route: "non_coderabbit", SourceType: "issue_comment", Iteration: 2, DroppedAt: 3000},
+			},
+		},
+		{
+			ID:         "loop-2",
+			UserID:     "user-1",
+			PRURL:      "https://github.com/org/other/pull/7",
+			Repository: "org/other",
+			DroppedCandidates: []kvstore.DroppedCandidate{
+				{Reason: "no_actionable_text", Route: "coderabbit", SourceType: "review_body", ReviewerLogin: "coderabbitai[bot]", Iteration: 1, DroppedAt: 2000},
+			},
+		},
+		{
+			ID:     "loop-3",
+			UserID: "user-2",
+			DroppedCandidates: []kvstore.DroppedCandidate{
+				{Reason: "empty_text", Route: "non_coderabbit", DroppedAt: 4000},
+			},
+		},
+	}
+	store.On("ListActiveReviewLoops").Return(loops, nil)
+
+	rr := doRequest(p, http.MethodGet, "/api/v1/review-loops/dropped-candidates", nil, "user-1")
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var resp DroppedCandidatesResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	require.Len(t, resp.Candidates, 3)
+
+	var got []string
+	for _, c := range resp.Candidates {
+		got = append(got, fmt.Sprintf("%s:%s:%s", c.ReviewLoopID, c.Reason, c.Route))
+	}
+	assert.Equal(t, []string{
+		"loop-1:empty_text:non_coderabbit",
+		"loop-2:no_actionable_text:coderabbit",
+		"loop-1:ignored_path:coderabbit",
+	}, got)
+	assert.Equal(t, "org/other", resp.Candidates[1].Repository)
+	assert.Equal(t, "coderabbitai[bot]", resp.Candidates[1].ReviewerLogin)
+	assert.Equal(t, "vendor/x.go", resp.Candidates[2].Path)
+	assert.Equal(t, "https://github.com/org/repo/pull/42", resp.Candidates[2].PRURL)
+}
+
+func TestGetDroppedCandidates_EmptyReturnsEmptyList(t *testing.T) {
+	p, _, _, store := setupAPITestPlugin(t)
+	store.On("ListActiveReviewLoops").Return([]*kvstore.ReviewLoop{}, nil)
+
+	rr := doRequest(p, http.MethodGet, "/api/v1/review-loops/dropped-candidates", nil, "user-1")
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"candidates":[]}`, rr.Body.String())
+}
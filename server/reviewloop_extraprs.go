@@ -0,0 +1,62 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+// samePRURL reports whether a and b name the same PR, ignoring case and a
+// trailing slash.
+func samePRURL(a, b string) bool {
+	return strings.EqualFold(strings.TrimRight(a, "/"), strings.TrimRight(b, "/"))
+}
+
+// isExtraPR reports whether prURL is one of the agent's additional PRs rather
+// than its primary PrURL.
+func isExtraPR(record *kvstore.AgentRecord, prURL string) bool {
+	for _, extraURL := range record.ExtraPRURLs {
+		if samePRURL(extraURL, prURL) {
+			return true
+		}
+	}
+	return false
+}
+
+// recordExtraPR adds prURL to the agent's additional PRs when the agent
+// already has a different primary PR. It reports whether the record changed.
+func recordExtraPR(record *kvstore.AgentRecord, prURL string) bool {
+	if prURL == "" || record.PrURL == "" || samePRURL(record.PrURL, prURL) || isExtraPR(record, prURL) {
+		return false
+	}
+	record.ExtraPRURLs = append(record.ExtraPRURLs, prURL)
+	return true
+}
+
+// reviewLoopPRURLs returns the agent's PRs that get review loops: its primary
+// PR, followed by its additional PRs when ReviewLoopStartForExtraPRs is
+// enabled.
+func (p *Plugin) reviewLoopPRURLs(record *kvstore.AgentRecord) []string {
+	var prURLs []string
+	if record.PrURL != "" {
+		prURLs = append(prURLs, record.PrURL)
+	}
+	if p.getConfiguration().ReviewLoopStartForExtraPRs {
+		prURLs = append(prURLs, record.ExtraPRURLs...)
+	}
+	return prURLs
+}
+
+// reviewLoopAllowedForPR reports whether prURL, a PR of the agent, may get a
+// review loop: always for the primary PR, and for additional PRs only when
+// ReviewLoopStartForExtraPRs is enabled. A draft launch gets none while
+// ReviewLoopSkipDraftPRs is enabled; its PR stays a draft until marked ready.
+func (p *Plugin) reviewLoopAllowedForPR(record *kvstore.AgentRecord, prURL string) bool {
+	if record.Draft && p.getConfiguration().ReviewLoopSkipDraftPRs {
+		return false
+	}
+	if !isExtraPR(record, prURL) {
+		return true
+	}
+	return p.getConfiguration().ReviewLoopStartForExtraPRs
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+// deferDispatchForGitHubOutage marks the loop as having a feedback dispatch
+// waiting for the GitHub circuit breaker to close. The PR head is kept so the
+// retried dispatch targets the same commit the review was made against.
+func deferDispatchForGitHubOutage(loop *kvstore.ReviewLoop, pr ghPullRequest) {
+	loop.GitHubRetryPending = true
+	loop.GitHubRetrySHA = strings.TrimSpace(pr.Head.SHA)
+	loop.GitHubRetryRef = pr.Head.Ref
+}
+
+func clearGitHubRetry(loop *kvstore.ReviewLoop) {
+	loop.GitHubRetryPending = false
+	loop.GitHubRetrySHA = ""
+	loop.GitHubRetryRef = ""
+}
+
+// retryGitHubDeferredDispatches is called from the poller. It retries feedback
+// dispatches that were deferred because GitHub was unavailable. Loops whose
+// retry hits the open breaker again are simply re-deferred.
+func (p *Plugin) retryGitHubDeferredDispatches() {
+	if !p.getConfiguration().EnableAIReviewLoop || p.reviewLoopsGloballyPaused() {
+		return
+	}
+
+	loops, err := p.kvstore.ListGitHubRetryReviewLoops()
+	if err != nil {
+		p.API.LogError("Failed to list review loops waiting on GitHub", "error", err.Error())
+		return
+	}
+
+	for _, loop := range loops {
+		if err := p.retryGitHubDeferredDispatch(loop); err != nil {
+			p.API.LogError("Failed to retry review feedback dispatch",
+				"review_loop_id", loop.ID,
+				"error", err.Error(),
+			)
+		}
+	}
+}
+
+func (p *Plugin) retryGitHubDeferredDispatch(loop *kvstore.ReviewLoop) error {
+	pr := ghPullRequest{}
+	pr.Head.SHA = loop.GitHubRetrySHA
+	pr.Head.Ref = loop.GitHubRetryRef
+
+	clearGitHubRetry(loop)
+	loop.UpdatedAt = p.now().UnixMilli()
+	if err := p.kvstore.SaveReviewLoop(loop); err != nil {
+		return fmt.Errorf("failed to save review loop before GitHub retry: %w", err)
+	}
+
+	return p.redispatchDeferredFeedback(loop, pr, "dispatched after GitHub recovered")
+}
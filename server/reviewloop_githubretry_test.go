@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v68/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/cursor"
+	"github.com/mattermost/mattermost-plugin-cursor/server/ghclient"
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+func TestDispatchReviewFeedback_DeferredWhileGitHubCircuitOpen(t *testing.T) {
+	p, _, _, ghMock := setupReviewLoopTestPlugin(t)
+	cursorMock := p.cursorClient.(*mockCursorClient)
+
+	loop := &kvstore.ReviewLoop{
+		ID:            "loop-1",
+		AgentRecordID: "agent-1",
+		Owner:         "org",
+		Repo:          "repo",
+		PRNumber:      42,
+		Phase:         kvstore.ReviewPhaseAwaitingReview,
+		Iteration:     1,
+	}
+
+	pr := ghPullRequest{}
+	pr.Head.SHA = "sha-1"
+	pr.Head.Ref = "cursor/fix-nil-guard"
+
+	ghMock.On("ListReviewComments", mock.Anything, "org", "repo", 42).
+		Return([]*github.PullRequestComment(nil), ghclient.ErrCircuitOpen)
+	// The other lists are fetched concurrently and may fail the same way.
+	ghMock.On("ListReviews", mock.Anything, "org", "repo", 42).
+		Return([]*github.PullRequestReview(nil), ghclient.ErrCircuitOpen).Maybe()
+	ghMock.On("ListIssueComments", mock.Anything, "org", "repo", 42).
+		Return([]*github.IssueComment(nil), ghclient.ErrCircuitOpen).Maybe()
+
+	outcome, err := p.dispatchReviewFeedback(context.Background(), loop, pr)
+	require.NoError(t, err)
+	assert.True(t, outcome.Skipped)
+	assert.False(t, outcome.Failed)
+	assert.Equal(t, reviewDispatchModeDeferredGitHub, outcome.Mode)
+
+	assert.Equal(t, kvstore.ReviewPhaseAwaitingReview, loop.Phase)
+	assert.True(t, loop.GitHubRetryPending)
+	assert.Equal(t, "sha-1", loop.GitHubRetrySHA)
+	assert.Equal(t, "cursor/fix-nil-guard", loop.GitHubRetryRef)
+	require.Len(t, loop.History, 1)
+	assert.Contains(t, loop.History[0].Detail, "until GitHub is reachable")
+	cursorMock.AssertNotCalled(t, "AddFollowup", mock.Anything, mock.Anything, mock.Anything)
+
+	// A second deferral while still waiting does not repeat the history entry.
+	_, err = p.dispatchReviewFeedback(context.Background(), loop, pr)
+	require.NoError(t, err)
+	assert.Len(t, loop.History, 1)
+}
+
+func TestRetryGitHubDeferredDispatches_DispatchesAfterRecovery(t *testing.T) {
+	p, api, store, ghMock := setupReviewLoopTestPlugin(t)
+	cursorMock := p.cursorClient.(*mockCursorClient)
+
+	loop := &kvstore.ReviewLoop{
+		ID:                 "loop-1",
+		AgentRecordID:      "agent-1",
+		RootPostID:         "root-1",
+		ChannelID:          "ch-1",
+		Owner:              "org",
+		Repo:               "repo",
+		PRNumber:           42,
+		PRURL:              "https://github.com/org/repo/pull/42",
+		Phase:              kvstore.ReviewPhaseAwaitingReview,
+		Iteration:          1,
+		GitHubRetryPending: true,
+		GitHubRetrySHA:     "sha-1",
+		GitHubRetryRef:     "cursor/fix-nil-guard",
+	}
+
+	store.On("ListGitHubRetryReviewLoops").Return([]*kvstore.ReviewLoop{loop}, nil)
+	store.On("SaveReviewLoop", mock.Anything).Return(nil)
+	mockInlineStatusUpdate(store, api, "agent-1", &kvstore.AgentRecord{CursorAgentID: "agent-1"})
+	mockCheckpointReviewFeedback(ghMock)
+
+	cursorMock.On("AddFollowup", mock.Anything, "agent-1", mock.MatchedBy(func(req cursor.FollowupRequest) bool {
+		return strings.Contains(req.Prompt.Text, "Add a nil guard before dereferencing.") &&
+			strings.Contains(req.Prompt.Text, "- head_sha: sha-1")
+	})).Return(&cursor.FollowupResponse{ID: "agent-1"}, nil).Once()
+
+	p.retryGitHubDeferredDispatches()
+
+	cursorMock.AssertExpectations(t)
+	assert.Equal(t, kvstore.ReviewPhaseCursorFixing, loop.Phase)
+	assert.Equal(t, 2, loop.Iteration)
+	assert.False(t, loop.GitHubRetryPending)
+	assert.Empty(t, loop.GitHubRetrySHA)
+	require.NotEmpty(t, loop.History)
+	assert.Contains(t, loop.History[len(loop.History)-1].Detail, "dispatched after GitHub recovered")
+}
+
+func TestRetryGitHubDeferredDispatches_SkipsLoopThatMovedOn(t *testing.T) {
+	p, _, store, _ := setupReviewLoopTestPlugin(t)
+	cursorMock := p.cursorClient.(*mockCursorClient)
+
+	loop := &kvstore.ReviewLoop{
+		ID:                 "loop-1",
+		Phase:              kvstore.ReviewPhaseApproved,
+		GitHubRetryPending: true,
+		GitHubRetrySHA:     "sha-1",
+	}
+
+	store.On("ListGitHubRetryReviewLoops").Return([]*kvstore.ReviewLoop{loop}, nil)
+	store.On("SaveReviewLoop", mock.Anything).Return(nil).Once()
+
+	p.retryGitHubDeferredDispatches()
+
+	store.AssertExpectations(t)
+	assert.False(t, loop.GitHubRetryPending)
+	cursorMock.AssertNotCalled(t, "AddFollowup", mock.Anything, mock.Anything, mock.Anything)
+}
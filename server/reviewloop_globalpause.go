@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+// reviewLoopGloballyPausedKey is the plugin setting behind /cursor admin
+// pause-loops. Mattermost stores plugin setting keys lowercased.
+const reviewLoopGloballyPausedKey = "reviewloopgloballypaused"
+
+// reviewLoopsGloballyPaused reports whether an operator has paused every
+// review loop, e.g. during an incident.
+func (p *Plugin) reviewLoopsGloballyPaused() bool {
+	return p.getConfiguration().ReviewLoopGloballyPaused
+}
+
+// setReviewLoopsGloballyPaused persists ReviewLoopGloballyPaused to the plugin
+// configuration, which OnConfigurationChange then applies on every node.
+func (p *Plugin) setReviewLoopsGloballyPaused(paused bool) error {
+	pluginConfig := p.client.Configuration.GetPluginConfig()
+	if pluginConfig == nil {
+		pluginConfig = map[string]any{}
+	}
+	for key := range pluginConfig {
+		if strings.EqualFold(key, reviewLoopGloballyPausedKey) {
+			delete(pluginConfig, key)
+		}
+	}
+	pluginConfig[reviewLoopGloballyPausedKey] = paused
+
+	if err := p.client.Configuration.SavePluginConfig(pluginConfig); err != nil {
+		return fmt.Errorf("failed to save plugin configuration: %w", err)
+	}
+	return nil
+}
+
+// holdReviewForGlobalPause records a review on the loop instead of acting on
+// it while review loops are globally paused. Only the latest held review is
+// kept; it is replayed once the pause is lifted. Returns false when review
+// loops are not paused and the caller should handle the review as usual.
+func (p *Plugin) holdReviewForGlobalPause(loop *kvstore.ReviewLoop, review ghReview, pr ghPullRequest) bool {
+	if !p.reviewLoopsGloballyPaused() {
+		return false
+	}
+
+	now := p.now().UnixMilli()
+	loop.GlobalPauseHeld = &kvstore.HeldReview{
+		ReviewerLogin: review.User.Login,
+		State:         strings.ToLower(strings.TrimSpace(review.State)),
+		Body:          truncateText(review.Body, maxRawFeedbackTextLen),
+		HTMLURL:       review.HTMLURL,
+		HeadSHA:       strings.TrimSpace(pr.Head.SHA),
+		HeadRef:       pr.Head.Ref,
+		HeldAt:        now,
+	}
+	loop.History = append(loop.History, kvstore.ReviewLoopEvent{
+		Phase:     loop.Phase,
+		Timestamp: now,
+		Detail:    fmt.Sprintf("Held %s review from %s while review loops are globally paused", loop.GlobalPauseHeld.State, review.User.Login),
+	})
+	loop.UpdatedAt = now
+	if err := p.kvstore.SaveReviewLoop(loop); err != nil {
+		p.API.LogError("Failed to save review loop held by global pause",
+			"review_loop_id", loop.ID,
+			"error", err.Error(),
+		)
+	}
+	p.publishReviewLoopChange(loop)
+	return true
+}
+
+// holdPushForGlobalPause records a push to the loop's PR instead of acting on
+// it while review loops are globally paused. Only the latest held push is
+// kept; it is replayed once the pause is lifted. Returns false when review
+// loops are not paused and the caller should handle the push as usual.
+func (p *Plugin) holdPushForGlobalPause(loop *kvstore.ReviewLoop, pr ghPullRequest) bool {
+	if !p.reviewLoopsGloballyPaused() {
+		return false
+	}
+
+	now := p.now().UnixMilli()
+	loop.GlobalPauseHeldPush = &kvstore.HeldPush{
+		HeadSHA: strings.TrimSpace(pr.Head.SHA),
+		HeadRef: pr.Head.Ref,
+		HeldAt:  now,
+	}
+	loop.History = append(loop.History, kvstore.ReviewLoopEvent{
+		Phase:     loop.Phase,
+		Timestamp: now,
+		Detail:    fmt.Sprintf("Held push of %s while review loops are globally paused", shortSHA(pr.Head.SHA)),
+	})
+	loop.UpdatedAt = now
+	if err := p.kvstore.SaveReviewLoop(loop); err != nil {
+		p.API.LogError("Failed to save review loop push held by global pause",
+			"review_loop_id", loop.ID,
+			"error", err.Error(),
+		)
+	}
+	p.publishReviewLoopChange(loop)
+	return true
+}
+
+// replayGloballyPausedReviews is called from the poller. Once the global
+// pause is lifted it re-evaluates the push and review each paused loop held,
+// as if they had just arrived.
+func (p *Plugin) replayGloballyPausedReviews() {
+	if !p.getConfiguration().EnableAIReviewLoop || p.reviewLoopsGloballyPaused() {
+		return
+	}
+
+	loops, err := p.kvstore.ListGloballyPausedReviewLoops()
+	if err != nil {
+		p.API.LogError("Failed to list globally paused review loops", "error", err.Error())
+		return
+	}
+
+	for _, loop := range loops {
+		if err := p.replayGloballyPausedReview(loop); err != nil {
+			p.API.LogError("Failed to replay review held by global pause",
+				"review_loop_id", loop.ID,
+				"error", err.Error(),
+			)
+		}
+	}
+}
+
+func (p *Plugin) replayGloballyPausedReview(loop *kvstore.ReviewLoop) error {
+	if loop.GlobalPauseHeldPush != nil {
+		if err := p.replayGloballyPausedPush(loop); err != nil {
+			return err
+		}
+	}
+	held := loop.GlobalPauseHeld
+	if held == nil {
+		return nil
+	}
+	review := ghReview{State: held.State, Body: held.Body, HTMLURL: held.HTMLURL}
+	review.User.Login = held.ReviewerLogin
+	pr := ghPullRequest{HTMLURL: loop.PRURL, Number: loop.PRNumber}
+	pr.Head.SHA = held.HeadSHA
+	pr.Head.Ref = held.HeadRef
+
+	// Clear and persist first so a failure below cannot replay the review twice.
+	now := p.now().UnixMilli()
+	loop.GlobalPauseHeld = nil
+	loop.History = append(loop.History, kvstore.ReviewLoopEvent{
+		Phase:     loop.Phase,
+		Timestamp: now,
+		Detail:    fmt.Sprintf("Global pause lifted; re-evaluating held review from %s", held.ReviewerLogin),
+	})
+	loop.UpdatedAt = now
+	if err := p.kvstore.SaveReviewLoop(loop); err != nil {
+		return fmt.Errorf("failed to save review loop after global pause: %w", err)
+	}
+
+	reviewerType := p.reviewerTypeForLogin(held.ReviewerLogin, "")
+	switch {
+	case loop.Phase == kvstore.ReviewPhaseAwaitingReview && reviewerType == reviewerTypeAIBot:
+		return p.handleAIReview(context.Background(), loop, review, pr)
+	case loop.Phase == kvstore.ReviewPhaseHumanReview && reviewerType == reviewerTypeHuman && held.State == reviewStateApproved:
+		return p.handleHumanReviewApproval(loop, held.ReviewerLogin)
+	case loop.Phase == kvstore.ReviewPhaseHumanReview && reviewerType == reviewerTypeHuman:
+		return p.handleHumanReviewFeedback(context.Background(), loop, review, pr)
+	case loop.Phase == kvstore.ReviewPhaseHumanReview && reviewerType == reviewerTypeAIBot:
+		return p.reopenAIReviewGate(context.Background(), loop, review, pr)
+	default:
+		// The loop moved on while paused; the held review no longer applies.
+		p.publishReviewLoopChange(loop)
+		return nil
+	}
+}
+
+// replayGloballyPausedPush handles the push a loop held during the global
+// pause the way the synchronize webhook would have.
+func (p *Plugin) replayGloballyPausedPush(loop *kvstore.ReviewLoop) error {
+	held := loop.GlobalPauseHeldPush
+	pr := ghPullRequest{HTMLURL: loop.PRURL, Number: loop.PRNumber}
+	pr.Head.SHA = held.HeadSHA
+	pr.Head.Ref = held.HeadRef
+
+	// Clear and persist first so a failure below cannot replay the push twice.
+	now := p.now().UnixMilli()
+	loop.GlobalPauseHeldPush = nil
+	loop.History = append(loop.History, kvstore.ReviewLoopEvent{
+		Phase:     loop.Phase,
+		Timestamp: now,
+		Detail:    fmt.Sprintf("Global pause lifted; re-evaluating held push of %s", shortSHA(held.HeadSHA)),
+	})
+	loop.UpdatedAt = now
+	if err := p.kvstore.SaveReviewLoop(loop); err != nil {
+		return fmt.Errorf("failed to save review loop after global pause: %w", err)
+	}
+
+	switch {
+	case loop.MergeConflictAt != 0 && loop.Phase != kvstore.ReviewPhaseCursorFixing:
+		p.checkReviewLoopMergeConflict(loop)
+		return nil
+	case loop.Phase == kvstore.ReviewPhaseCursorFixing:
+		return p.handlePRSynchronize(context.Background(), loop, pr)
+	default:
+		p.publishReviewLoopChange(loop)
+		return nil
+	}
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/pluginapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/cursor"
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+func newGlobalPauseTestLoop() *kvstore.ReviewLoop {
+	return &kvstore.ReviewLoop{
+		ID:            "loop-1",
+		AgentRecordID: "agent-1",
+		Owner:         "org",
+		Repo:          "repo",
+		PRNumber:      42,
+		PRURL:         "https://github.com/org/repo/pull/42",
+		Phase:         kvstore.ReviewPhaseAwaitingReview,
+		Iteration:     1,
+		RootPostID:    "root-1",
+		ChannelID:     "ch-1",
+		UserID:        "user-1",
+	}
+}
+
+func codeRabbitFeedbackReview() ghReview {
+	review := ghReview{
+		State: "commented",
+		Body:  "## Summary\n\nActionable comments posted: 1",
+	}
+	review.User.Login = "coderabbitai[bot]"
+	return review
+}
+
+func TestHandleAIReview_GloballyPausedHoldsReview(t *testing.T) {
+	p, _, store, ghMock := setupReviewLoopTestPlugin(t)
+	cursorMock := p.cursorClient.(*mockCursorClient)
+	p.configuration.ReviewLoopGloballyPaused = true
+
+	loop := newGlobalPauseTestLoop()
+	pr := ghPullRequest{}
+	pr.Head.SHA = "sha-1"
+	pr.Head.Ref = "cursor/fix"
+
+	store.On("SaveReviewLoop", mock.Anything).Return(nil).Once()
+
+	require.NoError(t, p.handleAIReview(context.Background(), loop, codeRabbitFeedbackReview(), pr))
+
+	assert.Equal(t, kvstore.ReviewPhaseAwaitingReview, loop.Phase)
+	assert.Equal(t, 1, loop.Iteration)
+	require.NotNil(t, loop.GlobalPauseHeld)
+	assert.Equal(t, "coderabbitai[bot]", loop.GlobalPauseHeld.ReviewerLogin)
+	assert.Equal(t, "sha-1", loop.GlobalPauseHeld.HeadSHA)
+	assert.Contains(t, loop.History[len(loop.History)-1].Detail, "globally paused")
+	cursorMock.AssertNotCalled(t, "AddFollowup", mock.Anything, mock.Anything, mock.Anything)
+	ghMock.AssertNotCalled(t, "ListReviewComments", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	store.AssertExpectations(t)
+}
+
+func TestReplayGloballyPausedReviews_ResumeDispatchesHeldReview(t *testing.T) {
+	p, api, store, ghMock := setupReviewLoopTestPlugin(t)
+	cursorMock := p.cursorClient.(*mockCursorClient)
+
+	loop := newGlobalPauseTestLoop()
+	loop.GlobalPauseHeld = &kvstore.HeldReview{
+		ReviewerLogin: "coderabbitai[bot]",
+		State:         "commented",
+		Body:          "## Summary\n\nActionable comments posted: 1",
+		HeadSHA:       "sha-1",
+		HeadRef:       "cursor/fix",
+	}
+
+	// Still paused: nothing is replayed.
+	p.configuration.ReviewLoopGloballyPaused = true
+	p.replayGloballyPausedReviews()
+	store.AssertNotCalled(t, "ListGloballyPausedReviewLoops")
+
+	p.configuration.ReviewLoopGloballyPaused = false
+	store.On("ListGloballyPausedReviewLoops").Return([]*kvstore.ReviewLoop{loop}, nil)
+	store.On("SaveReviewLoop", mock.Anything).Return(nil)
+	mockInlineStatusUpdate(store, api, "agent-1", &kvstore.AgentRecord{CursorAgentID: "agent-1"})
+	mockCheckpointReviewFeedback(ghMock)
+	cursorMock.On("AddFollowup", mock.Anything, "agent-1", mock.MatchedBy(func(req cursor.FollowupRequest) bool {
+		return strings.Contains(req.Prompt.Text, "Add a nil guard before dereferencing.")
+	})).Return(&cursor.FollowupResponse{ID: "agent-1"}, nil).Once()
+
+	p.replayGloballyPausedReviews()
+
+	cursorMock.AssertExpectations(t)
+	assert.Nil(t, loop.GlobalPauseHeld)
+	assert.Equal(t, kvstore.ReviewPhaseCursorFixing, loop.Phase)
+	assert.Equal(t, 2, loop.Iteration)
+	assert.Equal(t, "sha-1", loop.LastFeedbackDispatchSHA)
+
+	var sawResume bool
+	for _, event := range loop.History {
+		if strings.Contains(event.Detail, "Global pause lifted") {
+			sawResume = true
+		}
+	}
+	assert.True(t, sawResume)
+}
+
+func TestHandleHumanReviewFeedback_GloballyPausedHoldsReview(t *testing.T) {
+	p, _, store, _ := setupReviewLoopTestPlugin(t)
+	cursorMock := p.cursorClient.(*mockCursorClient)
+	p.configuration.ReviewLoopGloballyPaused = true
+
+	loop := newGlobalPauseTestLoop()
+	loop.Phase = kvstore.ReviewPhaseHumanReview
+	review := ghReview{State: "CHANGES_REQUESTED", Body: "Please split this function."}
+	review.User.Login = "humandev"
+
+	store.On("SaveReviewLoop", mock.Anything).Return(nil).Once()
+
+	require.NoError(t, p.handleHumanReviewFeedback(context.Background(), loop, review, ghPullRequest{}))
+
+	require.NotNil(t, loop.GlobalPauseHeld)
+	assert.Equal(t, reviewStateChangesRequested, loop.GlobalPauseHeld.State)
+	assert.Equal(t, kvstore.ReviewPhaseHumanReview, loop.Phase)
+	cursorMock.AssertNotCalled(t, "AddFollowup", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPRSynchronize_GloballyPausedHoldsPush(t *testing.T) {
+	p, api, store, ghMock := setupReviewLoopTestPlugin(t)
+	p.configuration.ReviewLoopGloballyPaused = true
+	p.configuration.ReviewLoopReRequestOnSynchronize = true
+
+	loop := newGlobalPauseTestLoop()
+	loop.Phase = kvstore.ReviewPhaseCursorFixing
+	event := PullRequestEvent{Action: "synchronize", PullRequest: ghPullRequest{HTMLURL: loop.PRURL, Number: 42}}
+	event.PullRequest.Head.SHA = "sha-2"
+	event.PullRequest.Head.Ref = "cursor/fix"
+
+	store.On("GetReviewLoopByPRURL", loop.PRURL).Return(loop, nil)
+	store.On("SaveReviewLoop", mock.Anything).Return(nil)
+
+	p.handlePRSynchronizeWebhook(context.Background(), event, httptest.NewRecorder())
+
+	assert.Equal(t, kvstore.ReviewPhaseCursorFixing, loop.Phase)
+	require.NotNil(t, loop.GlobalPauseHeldPush)
+	assert.Equal(t, "sha-2", loop.GlobalPauseHeldPush.HeadSHA)
+	assert.Contains(t, loop.History[len(loop.History)-1].Detail, "globally paused")
+	ghMock.AssertNotCalled(t, "RequestReviewers", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	// Once resumed, the held push moves the loop back to awaiting review.
+	p.configuration.ReviewLoopGloballyPaused = false
+	store.On("ListGloballyPausedReviewLoops").Return([]*kvstore.ReviewLoop{loop}, nil)
+	mockInlineStatusUpdate(store, api, "agent-1", &kvstore.AgentRecord{CursorAgentID: "agent-1"})
+	ghMock.On("RequestReviewers", mock.Anything, "org", "repo", 42, mock.Anything).Return(nil).Once()
+
+	p.replayGloballyPausedReviews()
+
+	assert.Nil(t, loop.GlobalPauseHeldPush)
+	assert.Equal(t, kvstore.ReviewPhaseAwaitingReview, loop.Phase)
+	assert.Equal(t, "sha-2", loop.LastCommitSHA)
+	ghMock.AssertExpectations(t)
+}
+
+func TestHandleHumanReviewApproval_GloballyPausedHoldsApproval(t *testing.T) {
+	p, api, store, _ := setupReviewLoopTestPlugin(t)
+	p.configuration.ReviewLoopGloballyPaused = true
+
+	loop := newGlobalPauseTestLoop()
+	loop.Phase = kvstore.ReviewPhaseHumanReview
+	store.On("SaveReviewLoop", mock.Anything).Return(nil)
+
+	require.NoError(t, p.handleHumanReviewApproval(loop, "humandev"))
+
+	assert.Equal(t, kvstore.ReviewPhaseHumanReview, loop.Phase)
+	require.NotNil(t, loop.GlobalPauseHeld)
+	assert.Equal(t, reviewStateApproved, loop.GlobalPauseHeld.State)
+	assert.Equal(t, "humandev", loop.GlobalPauseHeld.ReviewerLogin)
+	api.AssertNotCalled(t, "CreatePost", mock.Anything)
+
+	// Once resumed, the held approval completes the loop.
+	p.configuration.ReviewLoopGloballyPaused = false
+	store.On("ListGloballyPausedReviewLoops").Return([]*kvstore.ReviewLoop{loop}, nil)
+	mockInlineStatusUpdate(store, api, "agent-1", &kvstore.AgentRecord{CursorAgentID: "agent-1"})
+	api.On("CreatePost", mock.Anything).Return(&model.Post{Id: "notif-1"}, nil).Once()
+	api.On("AddReaction", mock.Anything).Return(nil, nil).Maybe()
+	api.On("RemoveReaction", mock.Anything).Return(nil).Maybe()
+
+	p.replayGloballyPausedReviews()
+
+	assert.Nil(t, loop.GlobalPauseHeld)
+	assert.Equal(t, kvstore.ReviewPhaseComplete, loop.Phase)
+	assert.Equal(t, "Approved by humandev", loop.History[len(loop.History)-1].Detail)
+}
+
+func TestStartReviewLoopForPR_SkippedWhileGloballyPaused(t *testing.T) {
+	p, _, store, ghMock := setupReviewLoopTestPlugin(t)
+	p.configuration.ReviewLoopGloballyPaused = true
+
+	record := &kvstore.AgentRecord{CursorAgentID: "agent-1", PrURL: "https://github.com/org/repo/pull/42"}
+	require.NoError(t, p.startReviewLoopForPR(record, record.PrURL))
+
+	store.AssertNotCalled(t, "SaveReviewLoop", mock.Anything)
+	ghMock.AssertNotCalled(t, "MarkPRReadyForReview", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	ghMock.AssertNotCalled(t, "RequestReviewers", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSetReviewLoopsGloballyPaused_PersistsPluginConfig(t *testing.T) {
+	p, api, _, _ := setupReviewLoopTestPlugin(t)
+	p.client = pluginapi.NewClient(api, nil)
+
+	api.On("GetPluginConfig").Return(map[string]any{
+		"cursorapikey":             "key",
+		"ReviewLoopGloballyPaused": false,
+	})
+	api.On("SavePluginConfig", map[string]any{
+		"cursorapikey":             "key",
+		"reviewloopgloballypaused": true,
+	}).Return(nil).Once()
+
+	require.NoError(t, p.setReviewLoopsGloballyPaused(true))
+	api.AssertExpectations(t)
+}
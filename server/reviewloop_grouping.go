@@ -0,0 +1,98 @@
+package main
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+// Values of ReviewFollowupGroupBy.
+const (
+	followupGroupByFile     = "file"
+	followupGroupByReviewer = "reviewer"
+	followupGroupBySeverity = "severity"
+)
+
+// followupFindingGroup is one section of a grouped follow-up prompt.
+type followupFindingGroup struct {
+	Heading  string
+	Findings []kvstore.ReviewFinding
+}
+
+// groupFindingsForFollowup splits findings into sections for the follow-up
+// prompt. Files and reviewers are sorted by name, with findings lacking one
+// last; severities run from critical to nit, then unlabeled. Findings keep
+// their relative order within a section. Any other groupBy returns a single
+// section without a heading.
+func groupFindingsForFollowup(findings []kvstore.ReviewFinding, groupBy string) []followupFindingGroup {
+	var keyOf func(kvstore.ReviewFinding) string
+	var less func(a, b string) bool
+	var heading func(string) string
+
+	switch groupBy {
+	case followupGroupByFile:
+		keyOf = func(f kvstore.ReviewFinding) string { return strings.TrimSpace(f.Path) }
+		less = emptyLastLess
+		heading = func(key string) string {
+			if key == "" {
+				return "### General (no file)"
+			}
+			return "### File: " + key
+		}
+	case followupGroupByReviewer:
+		keyOf = func(f kvstore.ReviewFinding) string { return strings.TrimSpace(f.ReviewerLogin) }
+		less = emptyLastLess
+		heading = func(key string) string {
+			if key == "" {
+				return "### Reviewer: unknown"
+			}
+			return "### Reviewer: " + key
+		}
+	case followupGroupBySeverity:
+		keyOf = func(f kvstore.ReviewFinding) string { return strings.ToLower(strings.TrimSpace(f.Severity)) }
+		less = func(a, b string) bool {
+			ra, rb := findingSeverityRank(a), findingSeverityRank(b)
+			if ra != rb {
+				return ra > rb
+			}
+			return a < b
+		}
+		heading = func(key string) string {
+			if findingSeverityRank(key) == 0 {
+				return "### Severity: unlabeled"
+			}
+			return "### Severity: " + key
+		}
+	default:
+		return []followupFindingGroup{{Findings: findings}}
+	}
+
+	byKey := map[string][]kvstore.ReviewFinding{}
+	var keys []string
+	for _, finding := range findings {
+		key := keyOf(finding)
+		if groupBy == followupGroupBySeverity && findingSeverityRank(key) == 0 {
+			key = ""
+		}
+		if _, ok := byKey[key]; !ok {
+			keys = append(keys, key)
+		}
+		byKey[key] = append(byKey[key], finding)
+	}
+	sort.SliceStable(keys, func(i, j int) bool { return less(keys[i], keys[j]) })
+
+	groups := make([]followupFindingGroup, 0, len(keys))
+	for _, key := range keys {
+		groups = append(groups, followupFindingGroup{Heading: heading(key), Findings: byKey[key]})
+	}
+	return groups
+}
+
+// emptyLastLess orders names alphabetically, with the empty name last.
+func emptyLastLess(a, b string) bool {
+	if a == "" || b == "" {
+		return b == "" && a != ""
+	}
+	return a < b
+}
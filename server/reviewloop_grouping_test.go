@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v68/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/cursor"
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+func groupingTestFindings() []kvstore.ReviewFinding {
+	return []kvstore.ReviewFinding{
+		{ActionableText: "Rename helper", Path: "util.go", ReviewerLogin: "copilot", Severity: "nit"},
+		{ActionableText: "Guard nil map", Path: "api.go", ReviewerLogin: "coderabbitai[bot]", Severity: "major"},
+		{ActionableText: "Update the changelog", ReviewerLogin: "alice"},
+		{ActionableText: "Close the response body", Path: "api.go", ReviewerLogin: "copilot", Severity: "critical"},
+	}
+}
+
+func TestGroupFindingsForFollowup(t *testing.T) {
+	headings := func(groups []followupFindingGroup) []string {
+		var out []string
+		for _, g := range groups {
+			out = append(out, g.Heading)
+		}
+		return out
+	}
+
+	t.Run("file", func(t *testing.T) {
+		groups := groupFindingsForFollowup(groupingTestFindings(), followupGroupByFile)
+		assert.Equal(t, []string{"### File: api.go", "### File: util.go", "### General (no file)"}, headings(groups))
+		require.Len(t, groups[0].Findings, 2)
+		assert.Equal(t, "Guard nil map", groups[0].Findings[0].ActionableText)
+		assert.Equal(t, "Close the response body", groups[0].Findings[1].ActionableText)
+	})
+
+	t.Run("reviewer", func(t *testing.T) {
+		groups := groupFindingsForFollowup(groupingTestFindings(), followupGroupByReviewer)
+		assert.Equal(t, []string{"### Reviewer: alice", "### Reviewer: coderabbitai[bot]", "### Reviewer: copilot"}, headings(groups))
+	})
+
+	t.Run("severity", func(t *testing.T) {
+		groups := groupFindingsForFollowup(groupingTestFindings(), followupGroupBySeverity)
+		assert.Equal(t, []string{
+			"### Severity: critical",
+			"### Severity: major",
+			"### Severity: nit",
+			"### Severity: unlabeled",
+		}, headings(groups))
+	})
+
+	t.Run("none", func(t *testing.T) {
+		groups := groupFindingsForFollowup(groupingTestFindings(), "")
+		require.Len(t, groups, 1)
+		assert.Empty(t, groups[0].Heading)
+		assert.Len(t, groups[0].Findings, 4)
+	})
+}
+
+func TestFormatFindingsForCursorFollowup_GroupedByFile(t *testing.T) {
+	prompt := formatFindingsForCursorFollowup(&kvstore.ReviewLoop{}, ghPullRequest{}, groupingTestFindings(), followupFormatOptions{GroupBy: followupGroupByFile})
+
+	apiIdx := strings.Index(prompt, "### File: api.go\n1. Guard nil map")
+	utilIdx := strings.Index(prompt, "### File: util.go\n3. Rename helper")
+	generalIdx := strings.Index(prompt, "### General (no file)\n4. Update the changelog")
+	require.NotEqual(t, -1, apiIdx, prompt)
+	require.NotEqual(t, -1, utilIdx, prompt)
+	require.NotEqual(t, -1, generalIdx, prompt)
+	assert.Less(t, apiIdx, utilIdx)
+	assert.Less(t, utilIdx, generalIdx)
+	assert.Contains(t, prompt, "2. Close the response body")
+}
+
+func TestFormatFindingsForCursorFollowup_UngroupedHasNoHeadings(t *testing.T) {
+	prompt := formatFindingsForCursorFollowup(&kvstore.ReviewLoop{}, ghPullRequest{}, groupingTestFindings(), followupFormatOptions{})
+	assert.NotContains(t, prompt, "### ")
+	assert.Contains(t, prompt, "1. Rename helper")
+}
+
+func TestGetReviewFollowupGroupBy(t *testing.T) {
+	assert.Equal(t, "severity", (&configuration{ReviewFollowupGroupBy: " Severity "}).GetReviewFollowupGroupBy())
+	assert.Equal(t, "", (&configuration{ReviewFollowupGroupBy: "directory"}).GetReviewFollowupGroupBy())
+	assert.Equal(t, "", (&configuration{}).GetReviewFollowupGroupBy())
+}
+
+func TestHandleAIReview_GroupedFollowupDispatched(t *testing.T) {
+	p, api, store, ghMock := setupReviewLoopTestPlugin(t)
+	p.configuration.ReviewFollowupGroupBy = followupGroupByFile
+	cursorMock := p.cursorClient.(*mockCursorClient)
+
+	loop := &kvstore.ReviewLoop{
+		ID:            "loop-1",
+		AgentRecordID: "agent-1",
+		Owner:         "org",
+		Repo:          "repo",
+		PRNumber:      42,
+		Phase:         kvstore.ReviewPhaseAwaitingReview,
+		Iteration:     1,
+		ChannelID:     "ch-1",
+		PRURL:         "https://github.com/org/repo/pull/42",
+	}
+	review := ghReview{State: "commented", Body: "Actionable comments posted: 2"}
+	review.User.Login = "coderabbitai[bot]"
+	pr := ghPullRequest{}
+	pr.Head.SHA = "abc123"
+
+	ghMock.On("ListReviewComments", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestComment{
+		{
+			ID:       github.Ptr(int64(1)),
+			User:     &github.User{Login: github.Ptr("coderabbitai[bot]")},
+			Path:     github.Ptr("server/z.go"),
+			Line:     github.Ptr(3),
+			Body:     github.Ptr("Prompt for AI Agents\nHandle the error from Close"),
+			CommitID: github.Ptr("abc123"),
+		},
+		{
+			ID:       github.Ptr(int64(2)),
+			User:     &github.User{Login: github.Ptr("coderabbitai[bot]")},
+			Path:     github.Ptr("server/a.go"),
+			Line:     github.Ptr(9),
+			Body:     github.Ptr("Prompt for AI Agents\nCheck the slice bounds"),
+			CommitID: github.Ptr("abc123"),
+		},
+	}, nil)
+	ghMock.On("ListReviews", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestReview{}, nil)
+	ghMock.On("ListIssueComments", mock.Anything, "org", "repo", 42).Return([]*github.IssueComment{}, nil)
+
+	var dispatched string
+	cursorMock.On("AddFollowup", mock.Anything, "agent-1", mock.Anything).Run(func(args mock.Arguments) {
+		dispatched = args.Get(2).(cursor.FollowupRequest).Prompt.Text
+	}).Return(&cursor.FollowupResponse{ID: "agent-1"}, nil)
+	store.On("SaveReviewLoop", mock.Anything).Return(nil)
+	mockInlineStatusUpdate(store, api, "agent-1", nil)
+
+	require.NoError(t, p.handleAIReview(context.Background(), loop, review, pr))
+
+	aIdx := strings.Index(dispatched, "### File: server/a.go\n")
+	zIdx := strings.Index(dispatched, "### File: server/z.go\n")
+	require.NotEqual(t, -1, aIdx, dispatched)
+	require.NotEqual(t, -1, zIdx, dispatched)
+	assert.Less(t, aIdx, zIdx)
+	assert.Contains(t, dispatched[aIdx:zIdx], "Check the slice bounds")
+	assert.Contains(t, dispatched[zIdx:], "Handle the error from Close")
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"path"
+	"strings"
+)
+
+// parseReviewIgnorePaths parses ReviewLoopIgnorePaths: globs separated by
+// newlines or commas. Blank entries are skipped.
+func parseReviewIgnorePaths(spec string) []string {
+	var patterns []string
+	for _, line := range strings.Split(spec, "\n") {
+		for _, pattern := range strings.Split(line, ",") {
+			pattern = strings.TrimPrefix(strings.TrimSpace(pattern), "/")
+			if pattern != "" {
+				patterns = append(patterns, pattern)
+			}
+		}
+	}
+	return patterns
+}
+
+// reviewPathIgnored reports whether filePath matches one of the ignore
+// patterns. A pattern ending in "/" matches everything below that directory.
+// Patterns without a "/" match a file name ("*.pb.go") or, with the trailing
+// "/", a directory name ("node_modules/") at any depth. Other patterns are
+// matched against the whole repository-relative path.
+func reviewPathIgnored(patterns []string, filePath string) bool {
+	filePath = strings.TrimPrefix(filePath, "/")
+	if filePath == "" {
+		return false
+	}
+	segments := strings.Split(filePath, "/")
+	for _, pattern := range patterns {
+		dir, isDir := strings.CutSuffix(pattern, "/")
+		switch {
+		case isDir && !strings.Contains(dir, "/"):
+			for _, segment := range segments[:len(segments)-1] {
+				if matched, err := path.Match(dir, segment); err == nil && matched {
+					return true
+				}
+			}
+		case isDir:
+			if matchReviewPathGlob(dir+"/**", filePath) {
+				return true
+			}
+		case !strings.Contains(pattern, "/"):
+			if matched, err := path.Match(pattern, segments[len(segments)-1]); err == nil && matched {
+				return true
+			}
+		default:
+			if matchReviewPathGlob(pattern, filePath) {
+				return true
+			}
+		}
+	}
+	return false
+}
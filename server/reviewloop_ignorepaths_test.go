@@ -0,0 +1,104 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/google/go-github/v68/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+func TestParseReviewIgnorePaths(t *testing.T) {
+	assert.Nil(t, parseReviewIgnorePaths(""))
+	assert.Equal(t,
+		[]string{"vendor/", "*.pb.go", "node_modules/", "gen/**"},
+		parseReviewIgnorePaths("vendor/, *.pb.go\n\n node_modules/ \n/gen/**"),
+	)
+}
+
+func TestReviewPathIgnored(t *testing.T) {
+	patterns := []string{"vendor/", "*.pb.go", "node_modules/", "webapp/dist/**", "api/generated/*.ts"}
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"vendor/x.go", true},
+		{"vendor/github.com/pkg/errors/errors.go", true},
+		{"server/x.go", false},
+		{"server/vendor.go", false},
+		{"proto/api.pb.go", true},
+		{"api.pb.go", true},
+		{"api.pb.gox", false},
+		{"webapp/node_modules/react/index.js", true},
+		{"webapp/dist/main.js", true},
+		{"webapp/src/dist/main.js", false},
+		{"api/generated/client.ts", true},
+		{"api/generated/nested/client.ts", false},
+		{"/vendor/x.go", true},
+		{"", false},
+	}
+
+	for _, tc := range tests {
+		assert.Equal(t, tc.want, reviewPathIgnored(patterns, tc.path), tc.path)
+	}
+	assert.False(t, reviewPathIgnored(nil, "vendor/x.go"))
+}
+
+func TestCollectReviewFeedbackBundle_DropsIgnoredPaths(t *testing.T) {
+	p, api, _, ghMock := setupReviewLoopTestPlugin(t)
+	p.configuration.EnableDebugLogging = true
+	p.configuration.ReviewLoopIgnorePaths = "vendor/\n*.pb.go"
+
+	ghMock.On("ListReviewComments", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestComment{
+		{
+			ID:       github.Ptr(int64(1)),
+			User:     &github.User{Login: github.Ptr("coderabbitai[bot]")},
+			Path:     github.Ptr("vendor/x.go"),
+			Line:     github.Ptr(3),
+			Body:     github.Ptr("Prompt for AI Agents\nHandle the error returned by Close."),
+			CommitID: github.Ptr("sha-1"),
+		},
+		{
+			ID:       github.Ptr(int64(2)),
+			User:     &github.User{Login: github.Ptr("coderabbitai[bot]")},
+			Path:     github.Ptr("server/x.go"),
+			Line:     github.Ptr(7),
+			Body:     github.Ptr("Prompt for AI Agents\nAdd a nil guard before dereferencing."),
+			CommitID: github.Ptr("sha-1"),
+		},
+	}, nil)
+	ghMock.On("ListReviews", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestReview{}, nil)
+	ghMock.On("ListIssueComments", mock.Anything, "org", "repo", 42).Return([]*github.IssueComment{}, nil)
+
+	loop := &kvstore.ReviewLoop{
+		ID:            "loop-1",
+		AgentRecordID: "agent-1",
+		Owner:         "org",
+		Repo:          "repo",
+		PRNumber:      42,
+		Phase:         kvstore.ReviewPhaseAwaitingReview,
+		Iteration:     1,
+		LastCommitSHA: "sha-1",
+	}
+
+	classification, _, feedback, err := p.collectReviewFeedbackBundle(loop)
+	require.NoError(t, err)
+
+	require.Len(t, classification.Dispatchable, 1)
+	assert.Equal(t, "server/x.go", classification.Dispatchable[0].Path)
+	assert.Contains(t, feedback, "Add a nil guard before dereferencing.")
+	assert.NotContains(t, feedback, "Handle the error returned by Close.")
+
+	droppedLogs := collectDroppedCandidateLogs(api)
+	require.Len(t, droppedLogs, 1)
+	assert.True(t, hasDroppedCandidateLog(droppedLogs, reviewerExtractionDropReasonIgnoredPath, reviewerExtractionRouteCodeRabbit))
+	assert.Equal(t, "vendor/x.go", droppedLogs[0]["candidate_path"])
+
+	require.Len(t, loop.DroppedCandidates, 1)
+	assert.Equal(t, reviewerExtractionDropReasonIgnoredPath, loop.DroppedCandidates[0].Reason)
+	assert.Equal(t, "vendor/x.go", loop.DroppedCandidates[0].Path)
+}
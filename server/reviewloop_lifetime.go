@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/attachments"
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+// stallExpiredReviewLoops is called from the poller. It stalls every active
+// review loop that has outlived ReviewLoopMaxLifetimeHours. Time spent under
+// a global pause is tracked on each loop and does not count against its
+// lifetime, so a long incident pause does not stall every loop.
+func (p *Plugin) stallExpiredReviewLoops() {
+	config := p.getConfiguration()
+	if !config.EnableAIReviewLoop || config.GetReviewLoopMaxLifetime() == 0 {
+		return
+	}
+
+	loops, err := p.kvstore.ListActiveReviewLoops()
+	if err != nil {
+		p.API.LogError("Failed to list active review loops for lifetime check", "error", err.Error())
+		return
+	}
+	paused := p.reviewLoopsGloballyPaused()
+	for _, loop := range loops {
+		p.trackReviewLoopPausedTime(loop, paused)
+		if !paused {
+			p.stallReviewLoopIfExpired(loop)
+		}
+	}
+}
+
+// trackReviewLoopPausedTime marks when a loop was first seen under a global
+// pause and, once the pause is lifted, adds its duration to the loop's paused
+// total. The loop is saved only when either changes.
+func (p *Plugin) trackReviewLoopPausedTime(loop *kvstore.ReviewLoop, paused bool) {
+	now := p.now().UnixMilli()
+	switch {
+	case paused && loop.LifetimePausedAt == 0:
+		loop.LifetimePausedAt = now
+	case !paused && loop.LifetimePausedAt != 0:
+		loop.LifetimePausedMillis += now - loop.LifetimePausedAt
+		loop.LifetimePausedAt = 0
+	default:
+		return
+	}
+	if err := p.kvstore.SaveReviewLoop(loop); err != nil {
+		p.API.LogError("Failed to save review loop paused time",
+			"review_loop_id", loop.ID,
+			"error", err.Error(),
+		)
+	}
+}
+
+// isReviewLoopLifetimeExceeded reports whether a non-terminal loop has been
+// active, not counting time spent under a global pause, for more than
+// maxLifetime.
+func isReviewLoopLifetimeExceeded(loop *kvstore.ReviewLoop, now time.Time, maxLifetime time.Duration) bool {
+	if maxLifetime == 0 || loop.CreatedAt == 0 || kvstore.IsReviewPhaseTerminal(loop.Phase) {
+		return false
+	}
+	pausedMillis := loop.LifetimePausedMillis
+	if loop.LifetimePausedAt != 0 {
+		pausedMillis += now.UnixMilli() - loop.LifetimePausedAt
+	}
+	active := now.Sub(time.UnixMilli(loop.CreatedAt)) - time.Duration(pausedMillis)*time.Millisecond
+	return active >= maxLifetime
+}
+
+// stallReviewLoopIfExpired moves the loop to the stalled phase when it has
+// outlived its maximum lifetime and posts the completion notification. It
+// returns true when the loop was stalled, in which case the caller must not
+// transition it further. Loops are never stalled while review loops are
+// globally paused.
+func (p *Plugin) stallReviewLoopIfExpired(loop *kvstore.ReviewLoop) bool {
+	config := p.getConfiguration()
+	now := p.now()
+	if p.reviewLoopsGloballyPaused() || !isReviewLoopLifetimeExceeded(loop, now, config.GetReviewLoopMaxLifetime()) {
+		return false
+	}
+
+	loop.Phase = kvstore.ReviewPhaseStalled
+	loop.History = append(loop.History, kvstore.ReviewLoopEvent{
+		Phase:     kvstore.ReviewPhaseStalled,
+		Timestamp: now.UnixMilli(),
+		Detail:    fmt.Sprintf("Lifetime exceeded (%dh)", config.ReviewLoopMaxLifetimeHours),
+	})
+	loop.UpdatedAt = now.UnixMilli()
+	if err := p.kvstore.SaveReviewLoop(loop); err != nil {
+		p.API.LogError("Failed to save stalled review loop",
+			"review_loop_id", loop.ID,
+			"error", err.Error(),
+		)
+	}
+
+	p.updateReviewLoopInlineStatus(loop)
+	p.publishReviewLoopChange(loop)
+	p.postReviewLoopCompletion(loop, p.styleReviewLoopNotification(notificationEventReviewLoopStalled, loop,
+		attachments.BuildLifetimeExceededAttachment(loop.PRURL, config.ReviewLoopMaxLifetimeHours)))
+	p.swapTerminalReaction(loop.ChannelID, loop.TriggerPostID, "eyes", "warning")
+	return true
+}
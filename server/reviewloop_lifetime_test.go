@@ -0,0 +1,171 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+func newLifetimeReviewLoop(createdAt time.Time) *kvstore.ReviewLoop {
+	return &kvstore.ReviewLoop{
+		ID:            "loop-1",
+		AgentRecordID: "agent-1",
+		UserID:        "user-1",
+		ChannelID:     "ch-1",
+		RootPostID:    "root-1",
+		TriggerPostID: "trigger-1",
+		PRURL:         "https://github.com/org/repo/pull/42",
+		Phase:         kvstore.ReviewPhaseCursorFixing,
+		Iteration:     2,
+		CreatedAt:     createdAt.UnixMilli(),
+	}
+}
+
+// mockReviewLoopStalled sets up the notifications sent when a loop is stalled.
+func mockReviewLoopStalled(store *mockKVStore, api *mockPluginAPI) {
+	store.On("SaveReviewLoop", mock.MatchedBy(func(l *kvstore.ReviewLoop) bool {
+		return l.Phase == kvstore.ReviewPhaseStalled
+	})).Return(nil).Once()
+	mockInlineStatusUpdate(store, api, "agent-1", &kvstore.AgentRecord{CursorAgentID: "agent-1"})
+	api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		return post.RootId == "root-1" && hasAttachmentWithTitle(post, "exceeding its maximum lifetime of 72 hours")
+	})).Return(&model.Post{Id: "notif-1"}, nil).Once()
+	api.On("RemoveReaction", mock.MatchedBy(func(r *model.Reaction) bool {
+		return r.EmojiName == "eyes"
+	})).Return(nil).Maybe()
+	api.On("AddReaction", mock.MatchedBy(func(r *model.Reaction) bool {
+		return r.EmojiName == "warning"
+	})).Return(nil, nil).Maybe()
+}
+
+func TestStallExpiredReviewLoops_StallsLoopPastLifetime(t *testing.T) {
+	p, api, store, _ := setupReviewLoopTestPlugin(t)
+	p.configuration.ReviewLoopMaxLifetimeHours = 72
+
+	loop := newLifetimeReviewLoop(time.Now().Add(-73 * time.Hour))
+	store.On("ListActiveReviewLoops").Return([]*kvstore.ReviewLoop{loop}, nil)
+	mockReviewLoopStalled(store, api)
+
+	p.stallExpiredReviewLoops()
+
+	store.AssertExpectations(t)
+	api.AssertExpectations(t)
+	assert.Equal(t, kvstore.ReviewPhaseStalled, loop.Phase)
+	require.NotEmpty(t, loop.History)
+	assert.Equal(t, "Lifetime exceeded (72h)", loop.History[len(loop.History)-1].Detail)
+}
+
+func TestStallExpiredReviewLoops_KeepsLoopWithinLifetime(t *testing.T) {
+	p, api, store, _ := setupReviewLoopTestPlugin(t)
+	p.configuration.ReviewLoopMaxLifetimeHours = 72
+
+	loop := newLifetimeReviewLoop(time.Now().Add(-10 * time.Hour))
+	store.On("ListActiveReviewLoops").Return([]*kvstore.ReviewLoop{loop}, nil)
+
+	p.stallExpiredReviewLoops()
+
+	assert.Equal(t, kvstore.ReviewPhaseCursorFixing, loop.Phase)
+	store.AssertNotCalled(t, "SaveReviewLoop", mock.Anything)
+	api.AssertNotCalled(t, "CreatePost", mock.Anything)
+}
+
+func TestStallExpiredReviewLoops_DisabledByDefault(t *testing.T) {
+	p, _, store, _ := setupReviewLoopTestPlugin(t)
+
+	p.stallExpiredReviewLoops()
+
+	store.AssertNotCalled(t, "ListActiveReviewLoops")
+}
+
+func TestStallExpiredReviewLoops_DoesNotCountGlobalPause(t *testing.T) {
+	p, api, store, _ := setupReviewLoopTestPlugin(t)
+	p.configuration.ReviewLoopMaxLifetimeHours = 72
+	clock := newFakeClock(time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC))
+	p.clock = clock
+
+	loop := newLifetimeReviewLoop(clock.Now().Add(-70 * time.Hour))
+	store.On("ListActiveReviewLoops").Return([]*kvstore.ReviewLoop{loop}, nil)
+	store.On("SaveReviewLoop", mock.MatchedBy(func(l *kvstore.ReviewLoop) bool {
+		return l.Phase == kvstore.ReviewPhaseCursorFixing
+	})).Return(nil)
+
+	// A pause outlasting the remaining lifetime does not stall the loop.
+	p.configuration.ReviewLoopGloballyPaused = true
+	p.stallExpiredReviewLoops()
+	assert.Equal(t, clock.Now().UnixMilli(), loop.LifetimePausedAt)
+	clock.Advance(24 * time.Hour)
+	p.stallExpiredReviewLoops()
+	assert.Equal(t, kvstore.ReviewPhaseCursorFixing, loop.Phase)
+
+	// Once resumed, the paused day is excluded from the loop's lifetime.
+	p.configuration.ReviewLoopGloballyPaused = false
+	p.stallExpiredReviewLoops()
+	assert.Equal(t, kvstore.ReviewPhaseCursorFixing, loop.Phase)
+	assert.Zero(t, loop.LifetimePausedAt)
+	assert.Equal(t, (24 * time.Hour).Milliseconds(), loop.LifetimePausedMillis)
+
+	// The two hours left on the lifetime still run out as usual.
+	clock.Advance(2 * time.Hour)
+	mockReviewLoopStalled(store, api)
+	p.stallExpiredReviewLoops()
+	assert.Equal(t, kvstore.ReviewPhaseStalled, loop.Phase)
+}
+
+func TestIsReviewLoopLifetimeExceeded(t *testing.T) {
+	now := time.Now()
+	loop := newLifetimeReviewLoop(now.Add(-73 * time.Hour))
+
+	assert.True(t, isReviewLoopLifetimeExceeded(loop, now, 72*time.Hour))
+	assert.False(t, isReviewLoopLifetimeExceeded(loop, now, 0))
+	assert.False(t, isReviewLoopLifetimeExceeded(loop, now, 96*time.Hour))
+
+	// Paused time, finished or ongoing, is not counted.
+	loop.LifetimePausedMillis = (2 * time.Hour).Milliseconds()
+	assert.False(t, isReviewLoopLifetimeExceeded(loop, now, 72*time.Hour))
+	loop.LifetimePausedMillis = 0
+	loop.LifetimePausedAt = now.Add(-2 * time.Hour).UnixMilli()
+	assert.False(t, isReviewLoopLifetimeExceeded(loop, now, 72*time.Hour))
+
+	loop.Phase = kvstore.ReviewPhaseComplete
+	assert.False(t, isReviewLoopLifetimeExceeded(loop, now, 72*time.Hour))
+}
+
+func TestWebhook_SynchronizeStallsExpiredReviewLoop(t *testing.T) {
+	p, api, store, _ := setupReviewLoopTestPlugin(t)
+	p.configuration.GitHubWebhookSecret = testWebhookSecret
+	p.configuration.ReviewLoopMaxLifetimeHours = 72
+
+	loop := newLifetimeReviewLoop(time.Now().Add(-80 * time.Hour))
+	store.On("GetReviewLoopByPRURL", "https://github.com/org/repo/pull/42").Return(loop, nil)
+	mockReviewLoopStalled(store, api)
+
+	event := PullRequestEvent{
+		Action: "synchronize",
+		PullRequest: ghPullRequest{
+			Number:  42,
+			HTMLURL: "https://github.com/org/repo/pull/42",
+		},
+	}
+	event.PullRequest.Head.SHA = "newsha"
+	body, _ := json.Marshal(event)
+
+	store.On("HasDeliveryBeenProcessed", "delivery-stall").Return(false, nil)
+	store.On("MarkDeliveryProcessed", "delivery-stall").Return(nil)
+
+	rr := httptest.NewRecorder()
+	p.handleGitHubWebhook(rr, makeWebhookRequest(t, "pull_request", "delivery-stall", body, signPayload(testWebhookSecret, body)))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	store.AssertExpectations(t)
+	assert.Equal(t, kvstore.ReviewPhaseStalled, loop.Phase)
+	assert.Empty(t, loop.LastCommitSHA, "a stalled loop must not process the push")
+}
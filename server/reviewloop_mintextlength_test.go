@@ -0,0 +1,81 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/google/go-github/v68/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+func TestActionableTextTooShort(t *testing.T) {
+	assert.True(t, actionableTextTooShort(reviewerExtractionRouteNonCodeRabbit, "  nit  ", 5))
+	assert.False(t, actionableTextTooShort(reviewerExtractionRouteNonCodeRabbit, "typo!", 5))
+	assert.False(t, actionableTextTooShort(reviewerExtractionRouteCodeRabbit, "nit", 5))
+	assert.False(t, actionableTextTooShort(reviewerExtractionRouteNonCodeRabbit, "nit", 0))
+}
+
+func TestCollectReviewFeedbackBundle_DropsShortNonCodeRabbitComments(t *testing.T) {
+	p, api, _, ghMock := setupReviewLoopTestPlugin(t)
+	p.configuration.EnableDebugLogging = true
+	p.configuration.MinActionableTextLength = 15
+
+	ghMock.On("ListReviewComments", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestComment{
+		{
+			ID:       github.Ptr(int64(1)),
+			User:     &github.User{Login: github.Ptr("copilot-pull-request-reviewer")},
+			Path:     github.Ptr("server/x.go"),
+			Line:     github.Ptr(3),
+			Body:     github.Ptr("nit"),
+			CommitID: github.Ptr("sha-1"),
+		},
+		{
+			ID:       github.Ptr(int64(2)),
+			User:     &github.User{Login: github.Ptr("copilot-pull-request-reviewer")},
+			Path:     github.Ptr("server/x.go"),
+			Line:     github.Ptr(7),
+			Body:     github.Ptr("Rename this variable to describe what it counts."),
+			CommitID: github.Ptr("sha-1"),
+		},
+		{
+			ID:       github.Ptr(int64(3)),
+			User:     &github.User{Login: github.Ptr("coderabbitai[bot]")},
+			Path:     github.Ptr("server/y.go"),
+			Line:     github.Ptr(9),
+			Body:     github.Ptr("Prompt for AI Agents\nFix typo."),
+			CommitID: github.Ptr("sha-1"),
+		},
+	}, nil)
+	ghMock.On("ListReviews", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestReview{}, nil)
+	ghMock.On("ListIssueComments", mock.Anything, "org", "repo", 42).Return([]*github.IssueComment{}, nil)
+
+	loop := &kvstore.ReviewLoop{
+		ID:            "loop-1",
+		AgentRecordID: "agent-1",
+		Owner:         "org",
+		Repo:          "repo",
+		PRNumber:      42,
+		Phase:         kvstore.ReviewPhaseAwaitingReview,
+		Iteration:     1,
+		LastCommitSHA: "sha-1",
+	}
+
+	classification, _, feedback, err := p.collectReviewFeedbackBundle(loop)
+	require.NoError(t, err)
+
+	require.Len(t, classification.Dispatchable, 2)
+	assert.Contains(t, feedback, "Rename this variable to describe what it counts.")
+	// CodeRabbit's marker-extracted text is exempt from the minimum.
+	assert.Contains(t, feedback, "Fix typo.")
+
+	droppedLogs := collectDroppedCandidateLogs(api)
+	require.Len(t, droppedLogs, 1)
+	assert.True(t, hasDroppedCandidateLog(droppedLogs, reviewerExtractionDropReasonTooShort, reviewerExtractionRouteNonCodeRabbit))
+
+	require.Len(t, loop.DroppedCandidates, 1)
+	assert.Equal(t, reviewerExtractionDropReasonTooShort, loop.DroppedCandidates[0].Reason)
+	assert.Equal(t, 3, loop.DroppedCandidates[0].Line)
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+// reviewLoopEventModeNudge marks history events recorded for reviewer nudges.
+const reviewLoopEventModeNudge = "nudge"
+
+// nudgeIdleReviewers is called from the poller. It comments on the PR of each
+// loop that has been awaiting an AI review longer than ReviewLoopNudgeAfter,
+// pinging the reviewer bot. A loop is nudged at most once per cooldown and at
+// most ReviewLoopMaxNudges times, and snoozed loops are skipped. Nothing is
+// nudged while review loops are globally paused, since the pause would only
+// hold the reviews the nudges trigger.
+func (p *Plugin) nudgeIdleReviewers() {
+	config := p.getConfiguration()
+	nudgeAfter := config.GetReviewLoopNudgeAfter()
+	if !config.EnableAIReviewLoop || nudgeAfter == 0 || p.reviewLoopsGloballyPaused() || p.getGitHubClient() == nil {
+		return
+	}
+
+	loops, err := p.kvstore.ListWaitingReviewLoops()
+	if err != nil {
+		p.API.LogError("Failed to list review loops waiting on reviewers", "error", err.Error())
+		return
+	}
+
+	now := p.now()
+	maxNudges := config.GetReviewLoopMaxNudges()
+	for _, loop := range loops {
+		if !shouldNudgeReviewers(loop, now, nudgeAfter, maxNudges) {
+			continue
+		}
+		p.nudgeReviewers(loop, now, maxNudges)
+	}
+}
+
+// shouldNudgeReviewers reports whether a loop awaiting an AI review should
+// nudge its reviewers now.
+func shouldNudgeReviewers(loop *kvstore.ReviewLoop, now time.Time, nudgeAfter time.Duration, maxNudges int) bool {
+	if loop.Phase != kvstore.ReviewPhaseAwaitingReview || loop.NudgeCount >= maxNudges {
+		return false
+	}
+	if loop.SnoozeUntil > now.UnixMilli() {
+		return false
+	}
+	if now.Sub(time.UnixMilli(reviewLoopWaitingSince(loop))) < nudgeAfter {
+		return false
+	}
+	return loop.LastNudgedAt == 0 || now.Sub(time.UnixMilli(loop.LastNudgedAt)) >= nudgeAfter
+}
+
+func (p *Plugin) nudgeReviewers(loop *kvstore.ReviewLoop, now time.Time, maxNudges int) {
+	ghClient := p.getGitHubClient()
+	if ghClient == nil {
+		return
+	}
+
+	// Persist before commenting so a failed save cannot repeat the nudge.
+	loop.NudgeCount++
+	loop.LastNudgedAt = now.UnixMilli()
+	loop.History = append(loop.History, kvstore.ReviewLoopEvent{
+		Phase:     loop.Phase,
+		Timestamp: now.UnixMilli(),
+		Detail:    fmt.Sprintf("Nudged AI reviewers (%d of %d)", loop.NudgeCount, maxNudges),
+		Mode:      reviewLoopEventModeNudge,
+	})
+	loop.UpdatedAt = now.UnixMilli()
+	if err := p.kvstore.SaveReviewLoop(loop); err != nil {
+		p.API.LogError("Failed to save review loop nudge",
+			"review_loop_id", loop.ID,
+			"error", err.Error(),
+		)
+		return
+	}
+	p.publishReviewLoopChange(loop)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	if _, err := ghClient.CreateComment(ctx, loop.Owner, loop.Repo, loop.PRNumber, p.getConfiguration().GetReviewLoopNudgeComment()); err != nil {
+		p.API.LogWarn("Failed to post reviewer nudge comment",
+			"review_loop_id", loop.ID,
+			"pr_url", loop.PRURL,
+			"error", err.Error(),
+		)
+	}
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+func newNudgeReviewLoop(waitingSince time.Time) *kvstore.ReviewLoop {
+	loop := newWaitingReviewLoop(waitingSince)
+	loop.Owner = "org"
+	loop.Repo = "repo"
+	loop.PRNumber = 42
+	return loop
+}
+
+func TestNudgeIdleReviewers_NudgesAfterTimeout(t *testing.T) {
+	p, _, store, ghMock := setupReviewLoopTestPlugin(t)
+	p.configuration.ReviewLoopNudgeAfterMinutes = 30
+
+	loop := newNudgeReviewLoop(time.Now().Add(-45 * time.Minute))
+	store.On("ListWaitingReviewLoops").Return([]*kvstore.ReviewLoop{loop}, nil)
+	store.On("SaveReviewLoop", loop).Return(nil).Once()
+	ghMock.On("CreateComment", mock.Anything, "org", "repo", 42, "@coderabbitai review").Return(nil, nil).Once()
+
+	p.nudgeIdleReviewers()
+
+	store.AssertExpectations(t)
+	ghMock.AssertExpectations(t)
+	assert.Equal(t, 1, loop.NudgeCount)
+	assert.NotZero(t, loop.LastNudgedAt)
+	require.Len(t, loop.History, 2)
+	assert.Equal(t, reviewLoopEventModeNudge, loop.History[1].Mode)
+	assert.Equal(t, kvstore.ReviewPhaseAwaitingReview, loop.History[1].Phase)
+	assert.Equal(t, "Nudged AI reviewers (1 of 3)", loop.History[1].Detail)
+}
+
+func TestNudgeIdleReviewers_NoDoubleNudgeWithinCooldown(t *testing.T) {
+	p, _, store, ghMock := setupReviewLoopTestPlugin(t)
+	p.configuration.ReviewLoopNudgeAfterMinutes = 30
+	p.configuration.ReviewLoopNudgeComment = "@copilot please review"
+
+	loop := newNudgeReviewLoop(time.Now().Add(-45 * time.Minute))
+	store.On("ListWaitingReviewLoops").Return([]*kvstore.ReviewLoop{loop}, nil)
+	store.On("SaveReviewLoop", loop).Return(nil).Once()
+	ghMock.On("CreateComment", mock.Anything, "org", "repo", 42, "@copilot please review").Return(nil, nil).Once()
+
+	p.nudgeIdleReviewers()
+
+	// The nudge event does not restart the wait, but the cooldown holds off
+	// a second nudge.
+	p.nudgeIdleReviewers()
+
+	store.AssertExpectations(t)
+	ghMock.AssertExpectations(t)
+	assert.Equal(t, 1, loop.NudgeCount)
+
+	// Once the cooldown has passed the loop is nudged again.
+	loop.LastNudgedAt = time.Now().Add(-31 * time.Minute).UnixMilli()
+	store.On("SaveReviewLoop", loop).Return(nil).Once()
+	ghMock.On("CreateComment", mock.Anything, "org", "repo", 42, "@copilot please review").Return(nil, nil).Once()
+
+	p.nudgeIdleReviewers()
+
+	assert.Equal(t, 2, loop.NudgeCount)
+	ghMock.AssertNumberOfCalls(t, "CreateComment", 2)
+}
+
+func TestNudgeIdleReviewers_CooldownBoundary(t *testing.T) {
+	p, _, store, ghMock := setupReviewLoopTestPlugin(t)
+	p.configuration.ReviewLoopNudgeAfterMinutes = 30
+	clock := newFakeClock(time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC))
+	p.clock = clock
+
+	loop := newNudgeReviewLoop(clock.Now().Add(-45 * time.Minute))
+	store.On("ListWaitingReviewLoops").Return([]*kvstore.ReviewLoop{loop}, nil)
+	store.On("SaveReviewLoop", loop).Return(nil)
+	ghMock.On("CreateComment", mock.Anything, "org", "repo", 42, "@coderabbitai review").Return(nil, nil)
+
+	p.nudgeIdleReviewers()
+	require.Equal(t, 1, loop.NudgeCount)
+	assert.Equal(t, clock.Now().UnixMilli(), loop.LastNudgedAt)
+
+	// One millisecond short of the cooldown the loop is left alone.
+	clock.Advance(30*time.Minute - time.Millisecond)
+	p.nudgeIdleReviewers()
+	assert.Equal(t, 1, loop.NudgeCount)
+
+	// Once the cooldown has fully elapsed the reviewers are nudged again.
+	clock.Advance(time.Millisecond)
+	p.nudgeIdleReviewers()
+	assert.Equal(t, 2, loop.NudgeCount)
+	assert.Equal(t, clock.Now().UnixMilli(), loop.LastNudgedAt)
+	ghMock.AssertNumberOfCalls(t, "CreateComment", 2)
+}
+
+func TestNudgeIdleReviewers_StopsAtMaxNudges(t *testing.T) {
+	p, _, store, ghMock := setupReviewLoopTestPlugin(t)
+	p.configuration.ReviewLoopNudgeAfterMinutes = 30
+	p.configuration.ReviewLoopMaxNudges = 2
+
+	loop := newNudgeReviewLoop(time.Now().Add(-3 * time.Hour))
+	loop.NudgeCount = 2
+	loop.LastNudgedAt = time.Now().Add(-time.Hour).UnixMilli()
+	store.On("ListWaitingReviewLoops").Return([]*kvstore.ReviewLoop{loop}, nil)
+
+	p.nudgeIdleReviewers()
+
+	store.AssertNotCalled(t, "SaveReviewLoop", mock.Anything)
+	ghMock.AssertNotCalled(t, "CreateComment", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestNudgeIdleReviewers_SkipsHumanReviewAndFreshLoops(t *testing.T) {
+	p, _, store, ghMock := setupReviewLoopTestPlugin(t)
+	p.configuration.ReviewLoopNudgeAfterMinutes = 30
+
+	human := newNudgeReviewLoop(time.Now().Add(-2 * time.Hour))
+	human.Phase = kvstore.ReviewPhaseHumanReview
+	fresh := newNudgeReviewLoop(time.Now().Add(-10 * time.Minute))
+	store.On("ListWaitingReviewLoops").Return([]*kvstore.ReviewLoop{human, fresh}, nil)
+
+	p.nudgeIdleReviewers()
+
+	store.AssertNotCalled(t, "SaveReviewLoop", mock.Anything)
+	ghMock.AssertNotCalled(t, "CreateComment", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestNudgeIdleReviewers_DisabledByDefault(t *testing.T) {
+	p, _, store, _ := setupReviewLoopTestPlugin(t)
+
+	p.nudgeIdleReviewers()
+
+	store.AssertNotCalled(t, "ListWaitingReviewLoops")
+}
+
+func TestNudgeIdleReviewers_SkipsWhileGloballyPaused(t *testing.T) {
+	p, _, store, ghMock := setupReviewLoopTestPlugin(t)
+	p.configuration.ReviewLoopNudgeAfterMinutes = 30
+	p.configuration.ReviewLoopGloballyPaused = true
+
+	p.nudgeIdleReviewers()
+
+	store.AssertNotCalled(t, "ListWaitingReviewLoops")
+	ghMock.AssertNotCalled(t, "CreateComment", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestReviewLoopWaitingSince_IgnoresNudges(t *testing.T) {
+	waitingSince := time.Now().Add(-2 * time.Hour)
+	loop := newWaitingReviewLoop(waitingSince)
+	loop.History = append(loop.History, kvstore.ReviewLoopEvent{
+		Phase:     kvstore.ReviewPhaseAwaitingReview,
+		Timestamp: time.Now().UnixMilli(),
+		Mode:      reviewLoopEventModeNudge,
+	})
+
+	assert.Equal(t, waitingSince.UnixMilli(), reviewLoopWaitingSince(loop))
+}
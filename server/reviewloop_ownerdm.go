@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+// reviewLoopOwnerLeftChannel reports whether the loop's owner is no longer a
+// member of the channel the loop posts to. Lookup failures other than "not
+// found" are treated as membership so notifications keep going to the thread.
+func (p *Plugin) reviewLoopOwnerLeftChannel(loop *kvstore.ReviewLoop) bool {
+	if loop.UserID == "" || loop.ChannelID == "" {
+		return false
+	}
+	_, appErr := p.API.GetChannelMember(loop.ChannelID, loop.UserID)
+	if appErr == nil {
+		return false
+	}
+	if appErr.StatusCode == http.StatusNotFound {
+		return true
+	}
+	p.API.LogWarn("Failed to check review loop owner channel membership",
+		"review_loop_id", loop.ID,
+		"error", appErr.Error(),
+	)
+	return false
+}
+
+// postReviewLoopOwnerDM sends a review loop notification to the owner's DM
+// instead of the loop thread. It returns false when the DM could not be
+// posted so the caller can fall back to the thread.
+func (p *Plugin) postReviewLoopOwnerDM(loop *kvstore.ReviewLoop, attachment *model.SlackAttachment) bool {
+	botUserID := p.botUserIDForChannel(loop.ChannelID)
+	channel, appErr := p.API.GetDirectChannel(botUserID, loop.UserID)
+	if appErr != nil {
+		p.API.LogError("Failed to open direct channel for review loop notification",
+			"review_loop_id", loop.ID,
+			"error", appErr.Error(),
+		)
+		return false
+	}
+
+	post := &model.Post{
+		UserId:    botUserID,
+		ChannelId: channel.Id,
+		Message:   fmt.Sprintf("Update on the AI review loop for %s. You're not a member of the channel it runs in, so it was sent here.", loop.PRURL),
+	}
+	model.ParseSlackAttachment(post, []*model.SlackAttachment{attachment})
+
+	if _, appErr := p.API.CreatePost(p.decorateBotPost(post)); appErr != nil {
+		p.API.LogError("Failed to post review loop notification to owner DM",
+			"review_loop_id", loop.ID,
+			"error", appErr.Error(),
+		)
+		return false
+	}
+	return true
+}
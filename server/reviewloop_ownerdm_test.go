@@ -0,0 +1,81 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+// setReviewLoopOwnerMembership replaces the default channel membership mock
+// with one answering for the given owner.
+func setReviewLoopOwnerMembership(api *mockPluginAPI, channelID, userID string, member bool) {
+	for _, call := range api.ExpectedCalls {
+		if call.Method == "GetChannelMember" {
+			call.Unset()
+		}
+	}
+	if member {
+		api.On("GetChannelMember", channelID, userID).Return(&model.ChannelMember{ChannelId: channelID, UserId: userID}, nil)
+		return
+	}
+	api.On("GetChannelMember", channelID, userID).Return(nil, model.NewAppError("GetChannelMember", "app.channel.get_member.missing.app_error", nil, "", http.StatusNotFound))
+}
+
+func newOwnerDMReviewLoop() *kvstore.ReviewLoop {
+	return &kvstore.ReviewLoop{
+		ID:         "loop-1",
+		UserID:     "user-1",
+		RootPostID: "root-1",
+		ChannelID:  "ch-1",
+		PRURL:      "https://github.com/org/repo/pull/42",
+	}
+}
+
+func TestPostReviewLoopCompletion_MemberGetsThreadPost(t *testing.T) {
+	p, api, _, _ := setupReviewLoopTestPlugin(t)
+	setReviewLoopOwnerMembership(api, "ch-1", "user-1", true)
+
+	api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		return post.ChannelId == "ch-1" && post.RootId == "root-1" &&
+			hasAttachmentWithTitle(post, "AI review loop approved.")
+	})).Return(&model.Post{Id: "post-1"}, nil).Once()
+
+	p.postReviewLoopCompletion(newOwnerDMReviewLoop(), &model.SlackAttachment{Title: "AI review loop approved."})
+
+	api.AssertExpectations(t)
+	api.AssertNotCalled(t, "GetDirectChannel", mock.Anything, mock.Anything)
+}
+
+func TestPostReviewLoopCompletion_NonMemberGetsDM(t *testing.T) {
+	p, api, _, _ := setupReviewLoopTestPlugin(t)
+	setReviewLoopOwnerMembership(api, "ch-1", "user-1", false)
+
+	api.On("GetDirectChannel", "bot-user-id", "user-1").Return(&model.Channel{Id: "dm-1"}, nil).Once()
+	api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		return post.ChannelId == "dm-1" && post.RootId == "" &&
+			post.UserId == "bot-user-id" &&
+			hasAttachmentWithTitle(post, "AI review loop hit max iterations.")
+	})).Return(&model.Post{Id: "post-1"}, nil).Once()
+
+	p.postReviewLoopCompletion(newOwnerDMReviewLoop(), &model.SlackAttachment{Title: "AI review loop hit max iterations."})
+
+	api.AssertExpectations(t)
+}
+
+func TestPostReviewLoopCompletion_FallsBackToThreadWhenDMFails(t *testing.T) {
+	p, api, _, _ := setupReviewLoopTestPlugin(t)
+	setReviewLoopOwnerMembership(api, "ch-1", "user-1", false)
+
+	api.On("GetDirectChannel", "bot-user-id", "user-1").Return(nil, model.NewAppError("GetDirectChannel", "boom", nil, "", http.StatusInternalServerError)).Once()
+	api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		return post.ChannelId == "ch-1" && post.RootId == "root-1"
+	})).Return(&model.Post{Id: "post-1"}, nil).Once()
+
+	p.postReviewLoopCompletion(newOwnerDMReviewLoop(), &model.SlackAttachment{Title: "AI review loop failed."})
+
+	api.AssertExpectations(t)
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/cursor"
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+const (
+	// pushCheckGrace is how long after a feedback dispatch the poller waits
+	// before treating a finished agent as one that failed to push.
+	pushCheckGrace = 5 * time.Minute
+
+	// pushFailureStackedPRThreshold is the number of consecutive follow-ups
+	// without a push after which the agent is told to open a stacked PR
+	// instead of pushing to the (likely protected) PR branch.
+	pushFailureStackedPRThreshold = 2
+
+	// maxReviewPushFailures is the number of consecutive follow-ups without a
+	// push after which the loop stops re-dispatching and asks for help.
+	maxReviewPushFailures = 3
+)
+
+// checkCursorFixingPushes is called from the poller. A loop stays in
+// cursor_fixing until a push arrives, so a follow-up that finishes without
+// pushing (most often because the PR branch is protected) would otherwise
+// stall the loop forever. Such loops have their open findings re-dispatched,
+// with the prompt switching to a stacked PR after repeated failures.
+func (p *Plugin) checkCursorFixingPushes() {
+	if !p.getConfiguration().EnableAIReviewLoop || p.reviewLoopsGloballyPaused() {
+		return
+	}
+	cursorClient := p.getCursorClient()
+	if cursorClient == nil {
+		return
+	}
+
+	loops, err := p.kvstore.ListFixingReviewLoops()
+	if err != nil {
+		p.API.LogError("Failed to list review loops awaiting Cursor fixes", "error", err.Error())
+		return
+	}
+
+	now := p.now()
+	for _, loop := range loops {
+		if loop.LastFeedbackDispatchAt == 0 || now.Sub(time.UnixMilli(loop.LastFeedbackDispatchAt)) < pushCheckGrace {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		agent, err := cursorClient.GetAgent(ctx, loop.AgentRecordID)
+		cancel()
+		if err != nil {
+			p.API.LogWarn("Failed to get agent status for review loop push check",
+				"review_loop_id", loop.ID,
+				"error", err.Error(),
+			)
+			continue
+		}
+		if agent.Status != cursor.AgentStatusFinished {
+			continue
+		}
+
+		p.redispatchAfterMissingPush(loop)
+	}
+}
+
+// redispatchAfterMissingPush re-sends the open findings of a loop whose last
+// follow-up finished without pushing, or fails the loop once the retry budget
+// is exhausted.
+func (p *Plugin) redispatchAfterMissingPush(loop *kvstore.ReviewLoop) {
+	attempt := loop.PushFailureCount + 1
+	if attempt > maxReviewPushFailures {
+		p.enterReviewErrorPhase(loop, fmt.Sprintf(
+			"Cursor finished %d follow-ups without pushing fixes; check the branch protection and Cursor's push permissions for this repository",
+			loop.PushFailureCount,
+		))
+		return
+	}
+
+	findings := make([]kvstore.ReviewFinding, 0, len(loop.Findings))
+	for _, finding := range loop.Findings {
+		if finding.Status == findingStatusOpen {
+			findings = append(findings, finding)
+		}
+	}
+	findings, _ = limitFindingsPerIteration(findings, p.getConfiguration().GetMaxFindingsPerIteration())
+
+	pr := ghPullRequest{}
+	pr.Head.SHA = loop.LastCommitSHA
+	if record, err := p.kvstore.GetAgent(loop.AgentRecordID); err == nil && record != nil {
+		pr.Head.Ref = record.TargetBranch
+	}
+
+	// Count this failure before formatting so the prompt reflects it.
+	loop.PushFailureCount = attempt
+	prompt := p.buildReviewFollowupPrompt(loop, pr, findings)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	if _, err := p.getCursorClient().AddFollowup(ctx, loop.AgentRecordID, cursor.FollowupRequest{
+		Prompt: cursor.Prompt{Text: prompt},
+	}); err != nil {
+		// Leave the loop untouched so the next poll retries.
+		loop.PushFailureCount = attempt - 1
+		p.API.LogError("Failed to re-dispatch review feedback after missing push",
+			"review_loop_id", loop.ID,
+			"error", err.Error(),
+		)
+		return
+	}
+
+	detail := fmt.Sprintf("Re-dispatched review feedback after Cursor finished without pushing (attempt %d)", attempt)
+	if attempt >= pushFailureStackedPRThreshold {
+		detail += "; asked Cursor to open a stacked PR"
+	}
+	now := p.now().UnixMilli()
+	p.applyReviewFeedbackDispatchTracking(loop, strings.TrimSpace(loop.LastCommitSHA), reviewFeedbackDigest(findings))
+	loop.History = append(loop.History, kvstore.ReviewLoopEvent{
+		Phase:     loop.Phase,
+		Timestamp: now,
+		Detail:    detail,
+	})
+	loop.UpdatedAt = now
+	if err := p.kvstore.SaveReviewLoop(loop); err != nil {
+		p.API.LogError("Failed to save review loop after re-dispatch",
+			"review_loop_id", loop.ID,
+			"error", err.Error(),
+		)
+		return
+	}
+
+	p.updateReviewLoopInlineStatus(loop)
+	p.publishReviewLoopChange(loop)
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v68/github"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/cursor"
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+const stackedPRInstruction = "open a new pull request from that branch targeting the existing pull request branch"
+
+func newFixingReviewLoop(pushFailures int) *kvstore.ReviewLoop {
+	return &kvstore.ReviewLoop{
+		ID:                     "loop-1",
+		AgentRecordID:          "agent-1",
+		RootPostID:             "root-1",
+		ChannelID:              "ch-1",
+		Owner:                  "org",
+		Repo:                   "repo",
+		PRNumber:               42,
+		PRURL:                  "https://github.com/org/repo/pull/42",
+		Phase:                  kvstore.ReviewPhaseCursorFixing,
+		Iteration:              2,
+		LastCommitSHA:          "sha-1",
+		LastFeedbackDispatchAt: time.Now().Add(-10 * time.Minute).UnixMilli(),
+		PushFailureCount:       pushFailures,
+		Findings: []kvstore.ReviewFinding{
+			{Key: "0123456789abcdef", Status: findingStatusOpen, ActionableText: "Add a nil guard before dereferencing."},
+			{Key: "fedcba9876543210", Status: findingStatusResolved, ActionableText: "Rename the helper."},
+		},
+	}
+}
+
+func TestFormatFindingsForCursorFollowup_StackedPRAfterRepeatedPushFailures(t *testing.T) {
+	pr := ghPullRequest{}
+	pr.Head.Ref = "cursor/fix-nil-guard"
+	findings := []kvstore.ReviewFinding{{Key: "0123456789abcdef", ActionableText: "Add a nil guard."}}
+
+	tests := []struct {
+		name         string
+		pushFailures int
+		wantStacked  bool
+		wantNote     bool
+	}{
+		{name: "no failures", pushFailures: 0},
+		{name: "one failure", pushFailures: 1, wantNote: true},
+		{name: "at threshold", pushFailures: pushFailureStackedPRThreshold, wantStacked: true, wantNote: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loop := newFixingReviewLoop(tt.pushFailures)
+			prompt := formatFindingsForCursorFollowup(loop, pr, findings, followupFormatOptions{})
+
+			assert.Equal(t, tt.wantStacked, strings.Contains(prompt, stackedPRInstruction))
+			assert.Equal(t, !tt.wantStacked, strings.Contains(prompt, "- do not create a new pull request"))
+			assert.Equal(t, tt.wantNote, strings.Contains(prompt, "finished without any new commits reaching the pull request"))
+			assert.Contains(t, prompt, "- branch: cursor/fix-nil-guard")
+		})
+	}
+}
+
+func TestCheckCursorFixingPushes_RedispatchesWhenAgentFinishedWithoutPush(t *testing.T) {
+	p, api, store, _ := setupReviewLoopTestPlugin(t)
+	cursorMock := p.cursorClient.(*mockCursorClient)
+
+	loop := newFixingReviewLoop(0)
+	store.On("ListFixingReviewLoops").Return([]*kvstore.ReviewLoop{loop}, nil)
+	store.On("SaveReviewLoop", mock.Anything).Return(nil)
+	mockInlineStatusUpdate(store, api, "agent-1", &kvstore.AgentRecord{CursorAgentID: "agent-1", TargetBranch: "cursor/fix-nil-guard"})
+	cursorMock.On("GetAgent", mock.Anything, "agent-1").Return(&cursor.Agent{ID: "agent-1", Status: cursor.AgentStatusFinished}, nil)
+	cursorMock.On("AddFollowup", mock.Anything, "agent-1", mock.MatchedBy(func(req cursor.FollowupRequest) bool {
+		return strings.Contains(req.Prompt.Text, "Add a nil guard before dereferencing.") &&
+			!strings.Contains(req.Prompt.Text, "Rename the helper.") &&
+			!strings.Contains(req.Prompt.Text, stackedPRInstruction)
+	})).Return(&cursor.FollowupResponse{ID: "agent-1"}, nil).Once()
+
+	p.checkCursorFixingPushes()
+
+	cursorMock.AssertExpectations(t)
+	assert.Equal(t, 1, loop.PushFailureCount)
+	assert.Equal(t, kvstore.ReviewPhaseCursorFixing, loop.Phase)
+	require.NotEmpty(t, loop.History)
+	assert.Contains(t, loop.History[len(loop.History)-1].Detail, "finished without pushing (attempt 1)")
+}
+
+func TestCheckCursorFixingPushes_SecondFailureAsksForStackedPR(t *testing.T) {
+	p, api, store, _ := setupReviewLoopTestPlugin(t)
+	cursorMock := p.cursorClient.(*mockCursorClient)
+
+	loop := newFixingReviewLoop(1)
+	store.On("ListFixingReviewLoops").Return([]*kvstore.ReviewLoop{loop}, nil)
+	store.On("SaveReviewLoop", mock.Anything).Return(nil)
+	mockInlineStatusUpdate(store, api, "agent-1", &kvstore.AgentRecord{CursorAgentID: "agent-1", TargetBranch: "cursor/fix-nil-guard"})
+	cursorMock.On("GetAgent", mock.Anything, "agent-1").Return(&cursor.Agent{ID: "agent-1", Status: cursor.AgentStatusFinished}, nil)
+	cursorMock.On("AddFollowup", mock.Anything, "agent-1", mock.MatchedBy(func(req cursor.FollowupRequest) bool {
+		return strings.Contains(req.Prompt.Text, stackedPRInstruction) &&
+			strings.Contains(req.Prompt.Text, "- branch: cursor/fix-nil-guard")
+	})).Return(&cursor.FollowupResponse{ID: "agent-1"}, nil).Once()
+
+	p.checkCursorFixingPushes()
+
+	cursorMock.AssertExpectations(t)
+	assert.Equal(t, 2, loop.PushFailureCount)
+	assert.Contains(t, loop.History[len(loop.History)-1].Detail, "asked Cursor to open a stacked PR")
+}
+
+func TestCheckCursorFixingPushes_SkipsRunningAgentAndRecentDispatch(t *testing.T) {
+	p, _, store, _ := setupReviewLoopTestPlugin(t)
+	cursorMock := p.cursorClient.(*mockCursorClient)
+
+	running := newFixingReviewLoop(0)
+	recent := newFixingReviewLoop(0)
+	recent.ID = "loop-2"
+	recent.AgentRecordID = "agent-2"
+	recent.LastFeedbackDispatchAt = time.Now().UnixMilli()
+
+	store.On("ListFixingReviewLoops").Return([]*kvstore.ReviewLoop{running, recent}, nil)
+	cursorMock.On("GetAgent", mock.Anything, "agent-1").Return(&cursor.Agent{ID: "agent-1", Status: cursor.AgentStatusRunning}, nil)
+
+	p.checkCursorFixingPushes()
+
+	cursorMock.AssertNotCalled(t, "GetAgent", mock.Anything, "agent-2")
+	cursorMock.AssertNotCalled(t, "AddFollowup", mock.Anything, mock.Anything, mock.Anything)
+	store.AssertNotCalled(t, "SaveReviewLoop", mock.Anything)
+	assert.Zero(t, running.PushFailureCount)
+}
+
+func TestCheckCursorFixingPushes_FailsLoopAfterMaxPushFailures(t *testing.T) {
+	p, api, store, _ := setupReviewLoopTestPlugin(t)
+	cursorMock := p.cursorClient.(*mockCursorClient)
+
+	loop := newFixingReviewLoop(maxReviewPushFailures)
+	store.On("ListFixingReviewLoops").Return([]*kvstore.ReviewLoop{loop}, nil)
+	store.On("SaveReviewLoop", mock.Anything).Return(nil)
+	mockInlineStatusUpdate(store, api, "agent-1", nil)
+	api.On("CreatePost", mock.Anything).Return(&model.Post{Id: "post-1"}, nil).Maybe()
+	api.On("RemoveReaction", mock.Anything).Return(nil).Maybe()
+	api.On("AddReaction", mock.Anything).Return(nil, nil).Maybe()
+	cursorMock.On("GetAgent", mock.Anything, "agent-1").Return(&cursor.Agent{ID: "agent-1", Status: cursor.AgentStatusFinished}, nil)
+
+	p.checkCursorFixingPushes()
+
+	cursorMock.AssertNotCalled(t, "AddFollowup", mock.Anything, mock.Anything, mock.Anything)
+	assert.Equal(t, kvstore.ReviewPhaseError, loop.Phase)
+	assert.Contains(t, loop.History[len(loop.History)-1].Detail, "without pushing fixes")
+}
+
+func TestHandlePRSynchronize_ResetsPushFailureCount(t *testing.T) {
+	p, api, store, ghMock := setupReviewLoopTestPlugin(t)
+
+	loop := newFixingReviewLoop(2)
+	loop.Findings = nil
+	ghMock.On("CompareCommits", mock.Anything, "org", "repo", "sha-1", "sha-2").
+		Return(&github.CommitsComparison{Status: github.Ptr("ahead")}, nil).Once()
+	store.On("SaveReviewLoop", mock.Anything).Return(nil)
+	mockInlineStatusUpdate(store, api, "agent-1", nil)
+
+	pr := ghPullRequest{}
+	pr.Head.SHA = "sha-2"
+	require.NoError(t, p.handlePRSynchronize(context.Background(), loop, pr))
+
+	assert.Zero(t, loop.PushFailureCount)
+	assert.Equal(t, kvstore.ReviewPhaseAwaitingReview, loop.Phase)
+}
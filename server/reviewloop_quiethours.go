@@ -0,0 +1,237 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/attachments"
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+// quietHours is a daily window, in a fixed timezone, during which review loop
+// dispatches and thread notifications are held until the window ends.
+type quietHours struct {
+	start    int // Minutes after local midnight
+	end      int // Minutes after local midnight; may be before start to wrap midnight
+	location *time.Location
+}
+
+// parseQuietHours parses an "HH:MM-HH:MM" window in the given IANA timezone.
+// An empty timezone means UTC.
+func parseQuietHours(spec, timezone string) (*quietHours, error) {
+	startText, endText, found := strings.Cut(strings.TrimSpace(spec), "-")
+	if !found {
+		return nil, fmt.Errorf("quiet hours must be in HH:MM-HH:MM format, got %q", spec)
+	}
+
+	start, err := parseClockMinutes(startText)
+	if err != nil {
+		return nil, err
+	}
+	end, err := parseClockMinutes(endText)
+	if err != nil {
+		return nil, err
+	}
+	if start == end {
+		return nil, fmt.Errorf("quiet hours start and end must differ, got %q", spec)
+	}
+
+	location := time.UTC
+	if tz := strings.TrimSpace(timezone); tz != "" {
+		location, err = time.LoadLocation(tz)
+		if err != nil {
+			return nil, fmt.Errorf("unknown quiet hours timezone %q: %w", tz, err)
+		}
+	}
+
+	return &quietHours{start: start, end: end, location: location}, nil
+}
+
+func parseClockMinutes(text string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(text))
+	if err != nil {
+		return 0, fmt.Errorf("invalid quiet hours time %q, expected HH:MM", strings.TrimSpace(text))
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// contains reports whether now falls inside the quiet hours window.
+func (q *quietHours) contains(now time.Time) bool {
+	local := now.In(q.location)
+	minute := local.Hour()*60 + local.Minute()
+	if q.start < q.end {
+		return minute >= q.start && minute < q.end
+	}
+	return minute >= q.start || minute < q.end
+}
+
+// inQuietHours reports whether review loop dispatches and notifications
+// should be held at the given time.
+func (p *Plugin) inQuietHours(now time.Time) bool {
+	window := p.getConfiguration().GetQuietHours()
+	return window != nil && window.contains(now)
+}
+
+// deferDispatchForQuietHours marks the loop as having a feedback dispatch
+// waiting for quiet hours to end. The PR head is kept so the released
+// dispatch targets the same commit the review was made against.
+func deferDispatchForQuietHours(loop *kvstore.ReviewLoop, pr ghPullRequest, now int64) {
+	if loop.QuietHoursDeferredAt == 0 {
+		loop.QuietHoursDeferredAt = now
+	}
+	loop.QuietHoursDispatchPending = true
+	loop.QuietHoursDispatchSHA = strings.TrimSpace(pr.Head.SHA)
+	loop.QuietHoursDispatchRef = pr.Head.Ref
+}
+
+func clearQuietHoursDeferral(loop *kvstore.ReviewLoop) {
+	loop.QuietHoursDeferredAt = 0
+	loop.QuietHoursDispatchPending = false
+	loop.QuietHoursDispatchSHA = ""
+	loop.QuietHoursDispatchRef = ""
+	loop.QuietHoursDigest = nil
+}
+
+// holdNotificationForQuietHours records a thread notification in the loop's
+// quiet hours digest instead of posting it. Returns false when quiet hours
+// are not in effect and the caller should post as usual.
+func (p *Plugin) holdNotificationForQuietHours(loop *kvstore.ReviewLoop, attachment *model.SlackAttachment) bool {
+	now := p.now()
+	if !p.inQuietHours(now) {
+		return false
+	}
+
+	entry := attachment.Title
+	if entry == "" {
+		entry = attachment.Text
+	}
+	loop.QuietHoursDigest = append(loop.QuietHoursDigest, entry)
+	if loop.QuietHoursDeferredAt == 0 {
+		loop.QuietHoursDeferredAt = now.UnixMilli()
+	}
+
+	if err := p.kvstore.SaveReviewLoop(loop); err != nil {
+		p.API.LogWarn("Failed to persist quiet hours notification digest",
+			"review_loop_id", loop.ID,
+			"error", err.Error(),
+		)
+	}
+	return true
+}
+
+// releaseQuietHoursDeferrals is called from the poller. Once quiet hours are
+// over it posts each loop's held notifications as a single digest and sends
+// any feedback dispatch that was deferred.
+func (p *Plugin) releaseQuietHoursDeferrals() {
+	if !p.getConfiguration().EnableAIReviewLoop || p.reviewLoopsGloballyPaused() {
+		return
+	}
+	if p.inQuietHours(p.now()) {
+		return
+	}
+
+	loops, err := p.kvstore.ListQuietHoursDeferredReviewLoops()
+	if err != nil {
+		p.API.LogError("Failed to list review loops deferred by quiet hours", "error", err.Error())
+		return
+	}
+
+	for _, loop := range loops {
+		if err := p.releaseQuietHoursDeferral(loop); err != nil {
+			p.API.LogError("Failed to release quiet hours deferral",
+				"review_loop_id", loop.ID,
+				"error", err.Error(),
+			)
+		}
+	}
+}
+
+func (p *Plugin) releaseQuietHoursDeferral(loop *kvstore.ReviewLoop) error {
+	digest := loop.QuietHoursDigest
+	dispatchPending := loop.QuietHoursDispatchPending
+	pr := ghPullRequest{}
+	pr.Head.SHA = loop.QuietHoursDispatchSHA
+	pr.Head.Ref = loop.QuietHoursDispatchRef
+
+	// Clear and persist first so a failure below cannot post the digest twice.
+	clearQuietHoursDeferral(loop)
+	loop.UpdatedAt = p.now().UnixMilli()
+	if err := p.kvstore.SaveReviewLoop(loop); err != nil {
+		return fmt.Errorf("failed to save review loop after quiet hours: %w", err)
+	}
+
+	if len(digest) > 0 {
+		p.postReviewLoopCompletion(loop, attachments.BuildQuietHoursDigestAttachment(loop.PRURL, digest))
+	}
+
+	if !dispatchPending {
+		return nil
+	}
+	return p.redispatchDeferredFeedback(loop, pr, "dispatched after quiet hours")
+}
+
+// redispatchDeferredFeedback sends a feedback dispatch that was held back
+// earlier, against the PR head recorded when it was deferred. modeLabel
+// describes why it was held for the loop history.
+func (p *Plugin) redispatchDeferredFeedback(loop *kvstore.ReviewLoop, pr ghPullRequest, modeLabel string) error {
+	_, err := p.redispatchReviewFeedback(loop, pr, modeLabel)
+	return err
+}
+
+// redispatchReviewFeedback collects and dispatches review feedback outside
+// the review webhook, then applies the outcome to the loop like the webhook
+// path does. modeLabel describes the dispatch for the loop history. Loops that
+// are no longer waiting on reviews are left alone and a zero outcome is
+// returned.
+func (p *Plugin) redispatchReviewFeedback(loop *kvstore.ReviewLoop, pr ghPullRequest, modeLabel string) (reviewDispatchOutcome, error) {
+	if loop.Phase != kvstore.ReviewPhaseAwaitingReview && loop.Phase != kvstore.ReviewPhaseHumanReview {
+		// The loop moved on while the dispatch was held; nothing left to send.
+		return reviewDispatchOutcome{}, nil
+	}
+
+	outcome, err := p.dispatchReviewFeedback(context.Background(), loop, pr)
+	if err != nil {
+		return outcome, fmt.Errorf("failed to dispatch review feedback: %w", err)
+	}
+
+	if outcome.Mode == reviewDispatchModeSkippedSeverity && loop.Phase == kvstore.ReviewPhaseAwaitingReview {
+		return outcome, p.transitionToHumanReview(loop)
+	}
+	if !outcome.Dispatched {
+		if err := p.kvstore.SaveReviewLoop(loop); err != nil {
+			return outcome, fmt.Errorf("failed to save review loop after dispatch outcome: %w", err)
+		}
+		if outcome.Failed {
+			p.notifyReviewLoopError(loop, loop.History[len(loop.History)-1].Detail)
+			return outcome, nil
+		}
+		p.publishReviewLoopChange(loop)
+		return outcome, nil
+	}
+
+	label := fmt.Sprintf("Iteration %d", loop.Iteration+1)
+	if loop.Phase == kvstore.ReviewPhaseHumanReview {
+		label = fmt.Sprintf("Human feedback iteration %d", loop.Iteration+1)
+	}
+
+	loop.Phase = kvstore.ReviewPhaseCursorFixing
+	loop.Iteration++
+	loop.History = append(loop.History, p.dispatchHistoryEvent(
+		outcome,
+		kvstore.ReviewPhaseCursorFixing,
+		formatReviewDispatchHistoryDetail(label, modeLabel, outcome.Counts),
+	))
+	p.maybeWarnIterationThreshold(loop)
+	loop.UpdatedAt = p.now().UnixMilli()
+	if err := p.kvstore.SaveReviewLoop(loop); err != nil {
+		return outcome, fmt.Errorf("failed to save review loop: %w", err)
+	}
+
+	p.updateReviewLoopInlineStatus(loop)
+	p.publishReviewLoopChange(loop)
+	return outcome, nil
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/cursor"
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+// quietHoursWindow returns an HH:MM-HH:MM spec in UTC whose start and end are
+// the given offsets from now.
+func quietHoursWindow(startOffset, endOffset time.Duration) string {
+	now := time.Now().UTC()
+	return now.Add(startOffset).Format("15:04") + "-" + now.Add(endOffset).Format("15:04")
+}
+
+func TestParseQuietHours(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2026, 3, 10, hour, minute, 0, 0, time.UTC)
+	}
+
+	t.Run("same-day window", func(t *testing.T) {
+		window, err := parseQuietHours("09:00-17:30", "")
+		require.NoError(t, err)
+		assert.True(t, window.contains(at(9, 0)))
+		assert.True(t, window.contains(at(17, 29)))
+		assert.False(t, window.contains(at(17, 30)))
+		assert.False(t, window.contains(at(8, 59)))
+	})
+
+	t.Run("window wrapping midnight", func(t *testing.T) {
+		window, err := parseQuietHours(" 22:00 - 07:00 ", "UTC")
+		require.NoError(t, err)
+		assert.True(t, window.contains(at(23, 15)))
+		assert.True(t, window.contains(at(3, 0)))
+		assert.False(t, window.contains(at(7, 0)))
+		assert.False(t, window.contains(at(12, 0)))
+	})
+
+	t.Run("timezone is applied", func(t *testing.T) {
+		window, err := parseQuietHours("22:00-07:00", "America/New_York")
+		require.NoError(t, err)
+		// 03:00 UTC is 23:00 the previous evening in New York (EDT, UTC-4).
+		assert.True(t, window.contains(at(3, 0)))
+		// 12:00 UTC is 08:00 in New York.
+		assert.False(t, window.contains(at(12, 0)))
+	})
+
+	for _, tc := range []struct {
+		name     string
+		spec     string
+		timezone string
+	}{
+		{name: "missing separator", spec: "22:00"},
+		{name: "invalid time", spec: "25:00-07:00"},
+		{name: "empty window", spec: "07:00-07:00"},
+		{name: "unknown timezone", spec: "22:00-07:00", timezone: "Mars/Olympus"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parseQuietHours(tc.spec, tc.timezone)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestDispatchReviewFeedback_DeferredDuringQuietHours(t *testing.T) {
+	p, _, _, ghMock := setupReviewLoopTestPlugin(t)
+	cursorMock := p.cursorClient.(*mockCursorClient)
+	p.configuration.ReviewLoopQuietHours = quietHoursWindow(-time.Hour, time.Hour)
+
+	loop := &kvstore.ReviewLoop{
+		ID:            "loop-1",
+		AgentRecordID: "agent-1",
+		Owner:         "org",
+		Repo:          "repo",
+		PRNumber:      42,
+		Phase:         kvstore.ReviewPhaseAwaitingReview,
+		Iteration:     1,
+	}
+
+	pr := ghPullRequest{}
+	pr.Head.SHA = "sha-1"
+	pr.Head.Ref = "cursor/fix-nil-guard"
+
+	mockCheckpointReviewFeedback(ghMock)
+
+	outcome, err := p.dispatchReviewFeedback(context.Background(), loop, pr)
+	require.NoError(t, err)
+	assert.True(t, outcome.Skipped)
+	assert.False(t, outcome.Dispatched)
+	assert.Equal(t, reviewDispatchModeDeferred, outcome.Mode)
+
+	assert.True(t, loop.QuietHoursDispatchPending)
+	assert.NotZero(t, loop.QuietHoursDeferredAt)
+	assert.Equal(t, "sha-1", loop.QuietHoursDispatchSHA)
+	assert.Equal(t, "cursor/fix-nil-guard", loop.QuietHoursDispatchRef)
+	assert.Zero(t, loop.LastFeedbackDispatchAt)
+	require.NotEmpty(t, loop.History)
+	assert.Contains(t, loop.History[len(loop.History)-1].Detail, "until quiet hours end")
+	cursorMock.AssertNotCalled(t, "AddFollowup", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestReleaseQuietHoursDeferrals_DispatchesAfterWindowEnds(t *testing.T) {
+	p, api, store, ghMock := setupReviewLoopTestPlugin(t)
+	cursorMock := p.cursorClient.(*mockCursorClient)
+	// Quiet hours are configured but not in effect right now.
+	p.configuration.ReviewLoopQuietHours = quietHoursWindow(time.Hour, 2*time.Hour)
+
+	loop := &kvstore.ReviewLoop{
+		ID:                        "loop-1",
+		AgentRecordID:             "agent-1",
+		RootPostID:                "root-1",
+		ChannelID:                 "ch-1",
+		Owner:                     "org",
+		Repo:                      "repo",
+		PRNumber:                  42,
+		PRURL:                     "https://github.com/org/repo/pull/42",
+		Phase:                     kvstore.ReviewPhaseAwaitingReview,
+		Iteration:                 1,
+		QuietHoursDeferredAt:      time.Now().Add(-6 * time.Hour).UnixMilli(),
+		QuietHoursDispatchPending: true,
+		QuietHoursDispatchSHA:     "sha-1",
+		QuietHoursDispatchRef:     "cursor/fix-nil-guard",
+		QuietHoursDigest:          []string{"AI review loop is at iteration 3 of 5."},
+	}
+
+	store.On("ListQuietHoursDeferredReviewLoops").Return([]*kvstore.ReviewLoop{loop}, nil)
+	store.On("SaveReviewLoop", mock.Anything).Return(nil)
+	mockInlineStatusUpdate(store, api, "agent-1", &kvstore.AgentRecord{CursorAgentID: "agent-1"})
+	mockCheckpointReviewFeedback(ghMock)
+
+	api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		return post.RootId == "root-1" &&
+			hasAttachmentWithTitle(post, "Quiet hours ended. 1 review loop update(s) were held:")
+	})).Return(&model.Post{Id: "digest-1"}, nil).Once()
+
+	cursorMock.On("AddFollowup", mock.Anything, "agent-1", mock.MatchedBy(func(req cursor.FollowupRequest) bool {
+		return strings.Contains(req.Prompt.Text, "Add a nil guard before dereferencing.") &&
+			strings.Contains(req.Prompt.Text, "- head_sha: sha-1") &&
+			strings.Contains(req.Prompt.Text, "- branch: cursor/fix-nil-guard")
+	})).Return(&cursor.FollowupResponse{ID: "agent-1"}, nil).Once()
+
+	p.releaseQuietHoursDeferrals()
+
+	cursorMock.AssertExpectations(t)
+	api.AssertExpectations(t)
+	assert.Equal(t, kvstore.ReviewPhaseCursorFixing, loop.Phase)
+	assert.Equal(t, 2, loop.Iteration)
+	assert.Zero(t, loop.QuietHoursDeferredAt)
+	assert.False(t, loop.QuietHoursDispatchPending)
+	assert.Empty(t, loop.QuietHoursDigest)
+	require.NotEmpty(t, loop.History)
+	assert.Contains(t, loop.History[len(loop.History)-1].Detail, "dispatched after quiet hours")
+}
+
+func TestReleaseQuietHoursDeferrals_WaitsUntilWindowEnds(t *testing.T) {
+	p, _, store, _ := setupReviewLoopTestPlugin(t)
+	p.configuration.ReviewLoopQuietHours = quietHoursWindow(-time.Hour, time.Hour)
+
+	p.releaseQuietHoursDeferrals()
+
+	store.AssertNotCalled(t, "ListQuietHoursDeferredReviewLoops")
+}
+
+func TestPostReviewLoopCompletion_HeldDuringQuietHours(t *testing.T) {
+	p, api, store, _ := setupReviewLoopTestPlugin(t)
+	p.configuration.ReviewLoopQuietHours = quietHoursWindow(-time.Hour, time.Hour)
+
+	loop := &kvstore.ReviewLoop{
+		ID:         "loop-1",
+		RootPostID: "root-1",
+		ChannelID:  "ch-1",
+	}
+
+	store.On("SaveReviewLoop", mock.MatchedBy(func(l *kvstore.ReviewLoop) bool {
+		return l.QuietHoursDeferredAt > 0 && len(l.QuietHoursDigest) == 1
+	})).Return(nil).Once()
+
+	p.postReviewLoopCompletion(loop, &model.SlackAttachment{Title: "AI review loop hit max iterations."})
+
+	store.AssertExpectations(t)
+	api.AssertNotCalled(t, "CreatePost", mock.Anything)
+	assert.Equal(t, []string{"AI review loop hit max iterations."}, loop.QuietHoursDigest)
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+const (
+	// reviewRateLimitRetryWindow is how long a rate-limited follow-up waits
+	// before the poller retries it.
+	reviewRateLimitRetryWindow = time.Minute
+
+	// reviewRateLimitMaxRetries bounds how many times a rate-limited
+	// follow-up is retried before the dispatch fails.
+	reviewRateLimitMaxRetries = 3
+)
+
+// deferDispatchForRateLimit marks the loop as having a feedback dispatch
+// waiting out a Cursor rate limit. The PR head is kept so the retried
+// dispatch targets the same commit the review was made against.
+func deferDispatchForRateLimit(loop *kvstore.ReviewLoop, pr ghPullRequest, now time.Time) {
+	loop.RateLimitRetryPending = true
+	loop.RateLimitRetryAt = now.Add(reviewRateLimitRetryWindow).UnixMilli()
+	loop.RateLimitRetryAttempts++
+	loop.RateLimitRetrySHA = strings.TrimSpace(pr.Head.SHA)
+	loop.RateLimitRetryRef = pr.Head.Ref
+}
+
+// clearRateLimitRetry drops the pending retry but keeps the attempt count so
+// a retry that is rate limited again continues toward the bound.
+func clearRateLimitRetry(loop *kvstore.ReviewLoop) {
+	loop.RateLimitRetryPending = false
+	loop.RateLimitRetryAt = 0
+	loop.RateLimitRetrySHA = ""
+	loop.RateLimitRetryRef = ""
+}
+
+func resetRateLimitRetry(loop *kvstore.ReviewLoop) {
+	clearRateLimitRetry(loop)
+	loop.RateLimitRetryAttempts = 0
+}
+
+// retryRateLimitedDispatches is called from the poller. It retries feedback
+// follow-ups that Cursor rejected with a rate limit once their retry window
+// has passed.
+func (p *Plugin) retryRateLimitedDispatches() {
+	if !p.getConfiguration().EnableAIReviewLoop || p.reviewLoopsGloballyPaused() {
+		return
+	}
+
+	loops, err := p.kvstore.ListRateLimitedReviewLoops()
+	if err != nil {
+		p.API.LogError("Failed to list rate limited review loops", "error", err.Error())
+		return
+	}
+
+	now := p.now().UnixMilli()
+	for _, loop := range loops {
+		if loop.RateLimitRetryAt > now {
+			continue
+		}
+		if err := p.retryRateLimitedDispatch(loop); err != nil {
+			p.API.LogError("Failed to retry rate limited review feedback dispatch",
+				"review_loop_id", loop.ID,
+				"error", err.Error(),
+			)
+		}
+	}
+}
+
+func (p *Plugin) retryRateLimitedDispatch(loop *kvstore.ReviewLoop) error {
+	pr := ghPullRequest{}
+	pr.Head.SHA = loop.RateLimitRetrySHA
+	pr.Head.Ref = loop.RateLimitRetryRef
+
+	if loop.Phase != kvstore.ReviewPhaseAwaitingReview && loop.Phase != kvstore.ReviewPhaseHumanReview {
+		// The loop moved on while the follow-up waited; start the next
+		// rate limit from a clean count.
+		resetRateLimitRetry(loop)
+	} else {
+		clearRateLimitRetry(loop)
+	}
+	loop.UpdatedAt = p.now().UnixMilli()
+	if err := p.kvstore.SaveReviewLoop(loop); err != nil {
+		return fmt.Errorf("failed to save review loop before rate limit retry: %w", err)
+	}
+
+	return p.redispatchDeferredFeedback(loop, pr, "dispatched after Cursor rate limit cleared")
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/cursor"
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+func rateLimitedErr() error {
+	return fmt.Errorf("request failed after 3 retries: %w", &cursor.APIError{StatusCode: 429, Message: "rate limited"})
+}
+
+func TestDispatchReviewFeedback_RateLimitedQueuesRetry(t *testing.T) {
+	p, _, store, ghMock := setupReviewLoopTestPlugin(t)
+	cursorMock := p.cursorClient.(*mockCursorClient)
+	store.On("SaveReviewLoop", mock.Anything).Return(nil)
+
+	loop := newAwaitingReviewLoop()
+	pr := ghPullRequest{}
+	pr.Head.SHA = "sha-1"
+	pr.Head.Ref = "cursor/fix-nil-guard"
+
+	mockCheckpointReviewFeedback(ghMock)
+	cursorMock.On("AddFollowup", mock.Anything, "agent-1", mock.Anything).Return(nil, rateLimitedErr()).Once()
+
+	before := time.Now()
+	outcome, err := p.dispatchReviewFeedback(context.Background(), loop, pr)
+	require.NoError(t, err)
+	assert.True(t, outcome.Skipped)
+	assert.False(t, outcome.Failed)
+	assert.Equal(t, reviewDispatchModeDeferredRateLimit, outcome.Mode)
+
+	assert.Equal(t, kvstore.ReviewPhaseAwaitingReview, loop.Phase)
+	assert.True(t, loop.RateLimitRetryPending)
+	assert.Equal(t, 1, loop.RateLimitRetryAttempts)
+	assert.GreaterOrEqual(t, loop.RateLimitRetryAt, before.Add(reviewRateLimitRetryWindow).UnixMilli())
+	assert.Equal(t, "sha-1", loop.RateLimitRetrySHA)
+	assert.Equal(t, "cursor/fix-nil-guard", loop.RateLimitRetryRef)
+	assert.Zero(t, loop.LastFeedbackDispatchAt)
+	assert.Empty(t, loop.PendingDispatchID)
+	require.NotEmpty(t, loop.History)
+	assert.Contains(t, loop.History[len(loop.History)-1].Detail, "rate limited the review feedback follow-up")
+	assert.Contains(t, loop.History[len(loop.History)-1].Detail, "attempt 1/3")
+}
+
+func TestRetryRateLimitedDispatches_SucceedsWithinWindow(t *testing.T) {
+	p, api, store, ghMock := setupReviewLoopTestPlugin(t)
+	cursorMock := p.cursorClient.(*mockCursorClient)
+
+	loop := newAwaitingReviewLoop()
+	loop.RateLimitRetryPending = true
+	loop.RateLimitRetryAt = time.Now().Add(-time.Second).UnixMilli()
+	loop.RateLimitRetryAttempts = 1
+	loop.RateLimitRetrySHA = "sha-1"
+	loop.RateLimitRetryRef = "cursor/fix-nil-guard"
+
+	store.On("ListRateLimitedReviewLoops").Return([]*kvstore.ReviewLoop{loop}, nil)
+	store.On("SaveReviewLoop", mock.Anything).Return(nil)
+	mockInlineStatusUpdate(store, api, "agent-1", nil)
+	mockCheckpointReviewFeedback(ghMock)
+	cursorMock.On("AddFollowup", mock.Anything, "agent-1", mock.Anything).
+		Return(&cursor.FollowupResponse{ID: "agent-1"}, nil).Once()
+
+	p.retryRateLimitedDispatches()
+
+	cursorMock.AssertExpectations(t)
+	assert.Equal(t, kvstore.ReviewPhaseCursorFixing, loop.Phase)
+	assert.Equal(t, 2, loop.Iteration)
+	assert.False(t, loop.RateLimitRetryPending)
+	assert.Zero(t, loop.RateLimitRetryAttempts)
+	assert.Empty(t, loop.RateLimitRetrySHA)
+	assert.Contains(t, loop.History[len(loop.History)-1].Detail, "dispatched after Cursor rate limit cleared")
+}
+
+func TestRetryRateLimitedDispatches_WaitsForWindow(t *testing.T) {
+	p, _, store, _ := setupReviewLoopTestPlugin(t)
+	cursorMock := p.cursorClient.(*mockCursorClient)
+
+	loop := newAwaitingReviewLoop()
+	loop.RateLimitRetryPending = true
+	loop.RateLimitRetryAt = time.Now().Add(reviewRateLimitRetryWindow).UnixMilli()
+	loop.RateLimitRetryAttempts = 1
+
+	store.On("ListRateLimitedReviewLoops").Return([]*kvstore.ReviewLoop{loop}, nil)
+
+	p.retryRateLimitedDispatches()
+
+	assert.True(t, loop.RateLimitRetryPending)
+	store.AssertNotCalled(t, "SaveReviewLoop", mock.Anything)
+	cursorMock.AssertNotCalled(t, "AddFollowup", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRetryRateLimitedDispatches_EscalatesAfterExhaustingRetries(t *testing.T) {
+	p, api, store, ghMock := setupReviewLoopTestPlugin(t)
+	cursorMock := p.cursorClient.(*mockCursorClient)
+
+	loop := newAwaitingReviewLoop()
+	pr := ghPullRequest{}
+	pr.Head.SHA = "sha-1"
+
+	store.On("ListRateLimitedReviewLoops").Return([]*kvstore.ReviewLoop{loop}, nil)
+	store.On("SaveReviewLoop", mock.Anything).Return(nil)
+	mockInlineStatusUpdate(store, api, "agent-1", nil)
+	mockCheckpointReviewFeedback(ghMock)
+	cursorMock.On("AddFollowup", mock.Anything, "agent-1", mock.Anything).Return(nil, rateLimitedErr())
+	api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		return post.RootId == "root-1"
+	})).Return(&model.Post{Id: "error-post"}, nil).Once()
+
+	outcome, err := p.dispatchReviewFeedback(context.Background(), loop, pr)
+	require.NoError(t, err)
+	require.Equal(t, reviewDispatchModeDeferredRateLimit, outcome.Mode)
+
+	for retry := 1; retry <= reviewRateLimitMaxRetries; retry++ {
+		require.Equal(t, kvstore.ReviewPhaseAwaitingReview, loop.Phase, "retry %d", retry)
+		require.True(t, loop.RateLimitRetryPending, "retry %d", retry)
+		loop.RateLimitRetryAt = time.Now().Add(-time.Second).UnixMilli()
+		p.retryRateLimitedDispatches()
+	}
+
+	cursorMock.AssertNumberOfCalls(t, "AddFollowup", reviewRateLimitMaxRetries+1)
+	assert.Equal(t, kvstore.ReviewPhaseError, loop.Phase)
+	assert.False(t, loop.RateLimitRetryPending)
+	assert.Zero(t, loop.RateLimitRetryAttempts)
+	detail := loop.History[len(loop.History)-1].Detail
+	assert.Contains(t, detail, "manual intervention required")
+	assert.Contains(t, detail, "still rate limited after 3 retries")
+	api.AssertExpectations(t)
+}
+
+func TestDispatchReviewFeedback_NonRateLimitErrorFailsImmediately(t *testing.T) {
+	p, _, store, ghMock := setupReviewLoopTestPlugin(t)
+	cursorMock := p.cursorClient.(*mockCursorClient)
+	store.On("SaveReviewLoop", mock.Anything).Return(nil)
+
+	loop := newAwaitingReviewLoop()
+	pr := ghPullRequest{}
+	pr.Head.SHA = "sha-1"
+
+	mockCheckpointReviewFeedback(ghMock)
+	cursorMock.On("AddFollowup", mock.Anything, "agent-1", mock.Anything).
+		Return(nil, &cursor.APIError{StatusCode: 409, Message: "agent is not running"}).Once()
+
+	outcome, err := p.dispatchReviewFeedback(context.Background(), loop, pr)
+	require.NoError(t, err)
+	assert.True(t, outcome.Failed)
+	assert.Equal(t, kvstore.ReviewPhaseError, loop.Phase)
+	assert.False(t, loop.RateLimitRetryPending)
+}
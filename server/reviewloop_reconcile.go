@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v68/github"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+// reconcileMaxReviewLoops bounds how many loops one reconciliation pass
+// fetches from GitHub; the rest catch up through webhooks and the poller.
+const reconcileMaxReviewLoops = 50
+
+// reconcileReviewLoopSpacing is the pause between two loops' GitHub lookups,
+// so a restart does not burst through the rate limit. It is a variable so
+// tests can shorten it.
+var reconcileReviewLoopSpacing = time.Second
+
+// reconcileReviewLoops is called from the poller and runs once per
+// activation; the scheduled poller runs on a single cluster node, so loops are
+// not reconciled twice in HA. In-flight loops are otherwise driven only by
+// webhooks, so each one waiting on GitHub has its PR compared against the loop
+// and is advanced past any review or push it missed while the plugin was down.
+// The pass stops early when the plugin is deactivated.
+func (p *Plugin) reconcileReviewLoops() {
+	if !p.reviewLoopsReconciled.CompareAndSwap(false, true) {
+		return
+	}
+	if !p.getConfiguration().EnableAIReviewLoop || p.reviewLoopsGloballyPaused() || p.getGitHubClient() == nil {
+		return
+	}
+
+	loops, err := p.kvstore.ListActiveReviewLoops()
+	if err != nil {
+		p.API.LogError("Failed to list review loops for reconciliation", "error", err.Error())
+		return
+	}
+
+	reconciled := 0
+	for _, loop := range loops {
+		if !reviewLoopReconcilable(loop) {
+			continue
+		}
+		if reconciled == reconcileMaxReviewLoops {
+			p.API.LogInfo("Review loop reconciliation limit reached; remaining loops left to webhooks",
+				"limit", reconcileMaxReviewLoops,
+			)
+			return
+		}
+		if reconciled > 0 {
+			select {
+			case <-p.stopping:
+				return
+			case <-time.After(reconcileReviewLoopSpacing):
+			}
+		}
+		reconciled++
+
+		if err := p.reconcileReviewLoop(loop); err != nil {
+			p.API.LogWarn("Failed to reconcile review loop",
+				"review_loop_id", loop.ID,
+				"error", err.Error(),
+			)
+		}
+	}
+}
+
+// reviewLoopReconcilable reports whether loop is waiting on a GitHub event
+// that reconciliation can replay.
+func reviewLoopReconcilable(loop *kvstore.ReviewLoop) bool {
+	switch loop.Phase {
+	case kvstore.ReviewPhaseAwaitingReview, kvstore.ReviewPhaseCursorFixing, kvstore.ReviewPhaseHumanReview:
+		return true
+	default:
+		return false
+	}
+}
+
+// reconcileReviewLoop fetches the loop's PR and replays the latest event the
+// loop missed: a push while Cursor was fixing, an AI review while awaiting
+// review, or a human verdict during human review. Closed PRs are left alone.
+// The loop is locked and re-read first, so a webhook for the same event that
+// arrives meanwhile is not applied twice.
+func (p *Plugin) reconcileReviewLoop(loop *kvstore.ReviewLoop) error {
+	ghClient := p.getGitHubClient()
+	if ghClient == nil {
+		return nil
+	}
+
+	loop, unlock := p.lockReviewLoop(loop)
+	defer unlock()
+	if loop == nil || !reviewLoopReconcilable(loop) {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	ghPR, err := ghClient.GetPullRequest(ctx, loop.Owner, loop.Repo, loop.PRNumber)
+	if err != nil {
+		return fmt.Errorf("failed to get PR: %w", err)
+	}
+	if ghPR.GetState() != "open" {
+		return nil
+	}
+
+	pr := ghPullRequest{
+		Number:  loop.PRNumber,
+		HTMLURL: loop.PRURL,
+		Title:   ghPR.GetTitle(),
+		State:   ghPR.GetState(),
+	}
+	pr.Head.SHA = ghPR.GetHead().GetSHA()
+	pr.Head.Ref = ghPR.GetHead().GetRef()
+	pr.Base.Ref = ghPR.GetBase().GetRef()
+
+	if loop.Phase == kvstore.ReviewPhaseCursorFixing {
+		if pr.Head.SHA == "" || pr.Head.SHA == loop.LastCommitSHA {
+			return nil
+		}
+		p.API.LogInfo("Reconciling push missed while the plugin was down",
+			"review_loop_id", loop.ID,
+			"head_sha", pr.Head.SHA,
+		)
+		return p.handlePRSynchronize(ctx, loop, pr)
+	}
+
+	reviews, err := ghClient.ListReviews(ctx, loop.Owner, loop.Repo, loop.PRNumber)
+	if err != nil {
+		return fmt.Errorf("failed to list reviews: %w", err)
+	}
+
+	wantType := reviewerTypeAIBot
+	if loop.Phase == kvstore.ReviewPhaseHumanReview {
+		wantType = reviewerTypeHuman
+	}
+	review := p.latestMissedReview(loop, reviews, pr.Head.SHA, wantType)
+	if review == nil {
+		return nil
+	}
+	p.API.LogInfo("Reconciling review missed while the plugin was down",
+		"review_loop_id", loop.ID,
+		"reviewer", review.User.Login,
+		"state", review.State,
+	)
+
+	switch {
+	case loop.Phase == kvstore.ReviewPhaseAwaitingReview:
+		return p.handleAIReview(ctx, loop, *review, pr)
+	case strings.EqualFold(review.State, reviewStateApproved):
+		return p.handleHumanReviewApproval(loop, review.User.Login)
+	case strings.EqualFold(review.State, reviewStateChangesRequested):
+		return p.handleHumanReviewFeedback(ctx, loop, *review, pr)
+	default:
+		return nil
+	}
+}
+
+// latestMissedReview returns the newest review of the given reviewer type
+// submitted on headSHA after the loop last changed, in webhook form, or nil.
+// Human reviews only count when they approve or request changes.
+func (p *Plugin) latestMissedReview(loop *kvstore.ReviewLoop, reviews []*github.PullRequestReview, headSHA, reviewerType string) *ghReview {
+	var latest *github.PullRequestReview
+	for _, r := range reviews {
+		if r.GetSubmittedAt().IsZero() || r.GetSubmittedAt().UnixMilli() <= loop.UpdatedAt {
+			continue
+		}
+		if headSHA != "" && r.GetCommitID() != "" && r.GetCommitID() != headSHA {
+			continue
+		}
+		if p.reviewerTypeForLogin(r.GetUser().GetLogin(), "") != reviewerType {
+			continue
+		}
+		state := strings.ToLower(r.GetState())
+		if reviewerType == reviewerTypeHuman && state != reviewStateApproved && state != reviewStateChangesRequested {
+			continue
+		}
+		if latest == nil || r.GetSubmittedAt().After(latest.GetSubmittedAt().Time) {
+			latest = r
+		}
+	}
+	if latest == nil {
+		return nil
+	}
+
+	review := &ghReview{
+		State:   strings.ToLower(latest.GetState()),
+		Body:    latest.GetBody(),
+		HTMLURL: latest.GetHTMLURL(),
+	}
+	review.User.Login = latest.GetUser().GetLogin()
+	return review
+}
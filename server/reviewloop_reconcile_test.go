@@ -0,0 +1,186 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v68/github"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+// setupReconcileTest returns a loop last updated an hour ago whose PR is open
+// at sha-2.
+func setupReconcileTest(t *testing.T, phase string) (*Plugin, *mockPluginAPI, *mockKVStore, *mockGitHubClient, *kvstore.ReviewLoop) {
+	t.Helper()
+	p, api, store, ghMock := setupReviewLoopTestPlugin(t)
+	allowAnyLogLines(api)
+
+	original := reconcileReviewLoopSpacing
+	reconcileReviewLoopSpacing = 0
+	t.Cleanup(func() { reconcileReviewLoopSpacing = original })
+
+	loop := &kvstore.ReviewLoop{
+		ID:            "loop-1",
+		AgentRecordID: "agent-1",
+		Owner:         "org",
+		Repo:          "repo",
+		PRNumber:      42,
+		PRURL:         "https://github.com/org/repo/pull/42",
+		Phase:         phase,
+		Iteration:     1,
+		LastCommitSHA: "sha-2",
+		TriggerPostID: "trigger-1",
+		RootPostID:    "root-1",
+		ChannelID:     "ch-1",
+		UserID:        "user-1",
+		UpdatedAt:     time.Now().Add(-time.Hour).UnixMilli(),
+	}
+	mockInlineStatusUpdate(store, api, "agent-1", &kvstore.AgentRecord{
+		CursorAgentID:  "agent-1",
+		BotReplyPostID: "reply-1",
+		ChannelID:      "ch-1",
+	})
+	store.On("ListActiveReviewLoops").Return([]*kvstore.ReviewLoop{loop}, nil)
+	store.On("GetReviewLoopByPRURL", loop.PRURL).Return(loop, nil).Maybe()
+	ghMock.On("GetPullRequest", mock.Anything, "org", "repo", 42).Return(&github.PullRequest{
+		State: github.Ptr("open"),
+		Title: github.Ptr("Fix login"),
+		Head:  &github.PullRequestBranch{SHA: github.Ptr("sha-2"), Ref: github.Ptr("cursor/fix-login")},
+		Base:  &github.PullRequestBranch{Ref: github.Ptr("main")},
+	}, nil)
+
+	return p, api, store, ghMock, loop
+}
+
+func reconcileTestReview(login, state, commitID string, submittedAt time.Time) *github.PullRequestReview {
+	return &github.PullRequestReview{
+		User:        &github.User{Login: github.Ptr(login)},
+		State:       github.Ptr(state),
+		CommitID:    github.Ptr(commitID),
+		SubmittedAt: &github.Timestamp{Time: submittedAt},
+	}
+}
+
+func TestReconcileReviewLoops_ApprovalMissedDuringDowntime(t *testing.T) {
+	p, api, store, ghMock, loop := setupReconcileTest(t, kvstore.ReviewPhaseAwaitingReview)
+
+	ghMock.On("ListReviews", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestReview{
+		reconcileTestReview("coderabbitai[bot]", "COMMENTED", "sha-1", time.Now().Add(-2*time.Hour)),
+		reconcileTestReview("coderabbitai[bot]", "APPROVED", "sha-2", time.Now().Add(-10*time.Minute)),
+	}, nil)
+	store.On("SaveReviewLoop", mock.MatchedBy(func(l *kvstore.ReviewLoop) bool {
+		return l.Phase == kvstore.ReviewPhaseApproved
+	})).Return(nil).Once()
+	store.On("SaveReviewLoop", mock.MatchedBy(func(l *kvstore.ReviewLoop) bool {
+		return l.Phase == kvstore.ReviewPhaseHumanReview
+	})).Return(nil).Once()
+	api.On("CreatePost", mock.Anything).Return(&model.Post{Id: "notif-1"}, nil)
+	api.On("RemoveReaction", mock.Anything).Return(nil).Maybe()
+	api.On("AddReaction", mock.Anything).Return(nil, nil).Maybe()
+
+	p.reconcileReviewLoops()
+
+	assert.Equal(t, kvstore.ReviewPhaseHumanReview, loop.Phase)
+	assert.True(t, historyContains(loop, "Approved after 1 iteration(s)"))
+	store.AssertExpectations(t)
+}
+
+func TestReconcileReviewLoops_IgnoresReviewsAlreadySeen(t *testing.T) {
+	p, _, store, ghMock, loop := setupReconcileTest(t, kvstore.ReviewPhaseAwaitingReview)
+
+	ghMock.On("ListReviews", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestReview{
+		reconcileTestReview("coderabbitai[bot]", "APPROVED", "sha-2", time.Now().Add(-2*time.Hour)),
+		reconcileTestReview("octocat", "APPROVED", "sha-2", time.Now().Add(-10*time.Minute)),
+	}, nil)
+
+	p.reconcileReviewLoops()
+
+	assert.Equal(t, kvstore.ReviewPhaseAwaitingReview, loop.Phase)
+	store.AssertNotCalled(t, "SaveReviewLoop", mock.Anything)
+}
+
+func TestReconcileReviewLoops_PushMissedDuringDowntime(t *testing.T) {
+	p, _, store, ghMock, loop := setupReconcileTest(t, kvstore.ReviewPhaseCursorFixing)
+	loop.LastCommitSHA = ""
+
+	store.On("SaveReviewLoop", mock.MatchedBy(func(l *kvstore.ReviewLoop) bool {
+		return l.Phase == kvstore.ReviewPhaseAwaitingReview
+	})).Return(nil).Once()
+
+	p.reconcileReviewLoops()
+
+	assert.Equal(t, kvstore.ReviewPhaseAwaitingReview, loop.Phase)
+	assert.Equal(t, "sha-2", loop.LastCommitSHA)
+	ghMock.AssertNotCalled(t, "ListReviews", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	store.AssertExpectations(t)
+}
+
+func TestReconcileReviewLoops_SkipsClosedPRs(t *testing.T) {
+	p, _, store, ghMock, _ := setupReconcileTest(t, kvstore.ReviewPhaseCursorFixing)
+	ghMock.ExpectedCalls = nil
+	ghMock.On("GetPullRequest", mock.Anything, "org", "repo", 42).Return(&github.PullRequest{
+		State: github.Ptr("closed"),
+		Head:  &github.PullRequestBranch{SHA: github.Ptr("sha-3")},
+	}, nil)
+
+	p.reconcileReviewLoops()
+
+	store.AssertNotCalled(t, "SaveReviewLoop", mock.Anything)
+	require.Len(t, ghMock.Calls, 1)
+}
+
+func TestReconcileReviewLoops_RunsOncePerActivation(t *testing.T) {
+	p, _, store, ghMock, _ := setupReconcileTest(t, kvstore.ReviewPhaseCursorFixing)
+
+	p.reconcileReviewLoops()
+	p.reconcileReviewLoops()
+
+	store.AssertNumberOfCalls(t, "ListActiveReviewLoops", 1)
+	ghMock.AssertNumberOfCalls(t, "GetPullRequest", 1)
+}
+
+func TestReconcileReviewLoops_SkipsLoopAdvancedUnderLock(t *testing.T) {
+	p, _, store, ghMock, loop := setupReconcileTest(t, kvstore.ReviewPhaseCursorFixing)
+	loop.LastCommitSHA = ""
+
+	// A webhook handled the push first; the re-read loop has moved on.
+	advanced := *loop
+	advanced.Phase = kvstore.ReviewPhaseAwaitingReview
+	advanced.LastCommitSHA = "sha-2"
+	advanced.UpdatedAt = time.Now().UnixMilli()
+	store.ExpectedCalls = nil
+	store.On("ListActiveReviewLoops").Return([]*kvstore.ReviewLoop{loop}, nil)
+	store.On("GetReviewLoopByPRURL", loop.PRURL).Return(&advanced, nil)
+	ghMock.On("ListReviews", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestReview{}, nil)
+
+	p.reconcileReviewLoops()
+
+	// The push is not replayed against the stale copy.
+	store.AssertNotCalled(t, "SaveReviewLoop", mock.Anything)
+	assert.Equal(t, kvstore.ReviewPhaseCursorFixing, loop.Phase)
+	ghMock.AssertCalled(t, "ListReviews", mock.Anything, "org", "repo", 42)
+}
+
+func TestReconcileReviewLoops_StopsOnDeactivate(t *testing.T) {
+	p, _, store, ghMock, loop := setupReconcileTest(t, kvstore.ReviewPhaseCursorFixing)
+	second := *loop
+	second.ID = "loop-2"
+	second.PRURL = "https://github.com/org/repo/pull/43"
+	store.ExpectedCalls = nil
+	store.On("ListActiveReviewLoops").Return([]*kvstore.ReviewLoop{loop, &second}, nil)
+	store.On("GetReviewLoopByPRURL", loop.PRURL).Return(loop, nil)
+	store.On("GetReviewLoopByPRURL", second.PRURL).Return(&second, nil).Maybe()
+
+	p.stopping = make(chan struct{})
+	close(p.stopping)
+	reconcileReviewLoopSpacing = time.Hour
+
+	p.reconcileReviewLoops()
+
+	ghMock.AssertNumberOfCalls(t, "GetPullRequest", 1)
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+// reviewEventKindAIGateReopened marks the history event recorded when the AI
+// review gate is reopened during human review, so an earlier AI approval no
+// longer satisfies ReviewLoopRequireAIGate.
+const reviewEventKindAIGateReopened = "ai_gate_reopened"
+
+// reopenAIReviewGate handles an AI reviewer review that arrives while the loop
+// is in human_review. With ReviewLoopReopenOnAIFindings set, a review carrying
+// actionable findings moves the loop back to awaiting_review and dispatches
+// them like any other AI iteration; the loop returns to human_review through
+// the usual approval path once the reviewer is satisfied. If nothing new is
+// dispatched, the loop goes straight back to human_review.
+func (p *Plugin) reopenAIReviewGate(ctx context.Context, loop *kvstore.ReviewLoop, review ghReview, pr ghPullRequest) error {
+	config := p.getConfiguration()
+	if !config.ReviewLoopReopenOnAIFindings {
+		return nil
+	}
+
+	isCodeRabbit := strings.EqualFold(review.User.Login, codeRabbitReviewerLogin)
+	if isCodeRabbitSatisfied(review) || (!isCodeRabbit && !p.isInlineOnlyAIReview(review)) {
+		// Approvals and informational bot reviews leave human review alone.
+		return nil
+	}
+	if p.holdReviewForGlobalPause(loop, review, pr) {
+		return nil
+	}
+	if loop.Iteration >= config.MaxReviewIterations {
+		// Reopening would only end the loop at max_iterations; leave the PR
+		// with the humans instead.
+		p.logger(ctx).LogDebug("Not reopening AI review gate; iteration limit reached",
+			"review_loop_id", loop.ID,
+			"reviewer", review.User.Login,
+			"iteration", loop.Iteration,
+		)
+		return nil
+	}
+
+	loop.Phase = kvstore.ReviewPhaseAwaitingReview
+	loop.History = append(loop.History, kvstore.ReviewLoopEvent{
+		Phase:     kvstore.ReviewPhaseAwaitingReview,
+		Timestamp: p.now().UnixMilli(),
+		Detail:    fmt.Sprintf("Reopened AI review gate for new findings from %s during human review", review.User.Login),
+		Kind:      reviewEventKindAIGateReopened,
+	})
+
+	err := p.handleAIReview(ctx, loop, review, pr)
+
+	// A dispatch, a terminal transition, or a deferred dispatch that will be
+	// replayed against awaiting_review all move the loop on. Otherwise there
+	// was nothing new to fix.
+	if loop.Phase != kvstore.ReviewPhaseAwaitingReview ||
+		loop.QuietHoursDispatchPending || loop.GitHubRetryPending || loop.RateLimitRetryPending {
+		return err
+	}
+
+	loop.Phase = kvstore.ReviewPhaseHumanReview
+	loop.History = append(loop.History, kvstore.ReviewLoopEvent{
+		Phase:     kvstore.ReviewPhaseHumanReview,
+		Timestamp: p.now().UnixMilli(),
+		Detail:    fmt.Sprintf("No new findings from %s to dispatch; returned to human review", review.User.Login),
+	})
+	loop.UpdatedAt = p.now().UnixMilli()
+	if saveErr := p.kvstore.SaveReviewLoop(loop); saveErr != nil {
+		return fmt.Errorf("failed to save review loop after reopening AI gate: %w", saveErr)
+	}
+	p.publishReviewLoopChange(loop)
+	return err
+}
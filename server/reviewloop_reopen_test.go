@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v68/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/cursor"
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+func newHumanReviewLoop(iteration int) *kvstore.ReviewLoop {
+	return &kvstore.ReviewLoop{
+		ID:            "loop-1",
+		AgentRecordID: "agent-1",
+		Owner:         "org",
+		Repo:          "repo",
+		PRNumber:      42,
+		Phase:         kvstore.ReviewPhaseHumanReview,
+		Iteration:     iteration,
+		TriggerPostID: "trigger-1",
+		RootPostID:    "root-1",
+		ChannelID:     "ch-1",
+		UserID:        "user-1",
+		PRURL:         "https://github.com/org/repo/pull/42",
+	}
+}
+
+func codeRabbitFindingsReview() ghReview {
+	review := ghReview{
+		State: "commented",
+		Body:  "## Summary\n\nActionable comments posted: 1",
+	}
+	review.User.Login = "coderabbitai[bot]"
+	return review
+}
+
+func TestReopenAIReviewGate_NewFindingsDispatch(t *testing.T) {
+	p, api, store, ghMock := setupReviewLoopTestPlugin(t)
+	p.configuration.ReviewLoopReopenOnAIFindings = true
+	cursorMock := p.cursorClient.(*mockCursorClient)
+
+	loop := newHumanReviewLoop(1)
+	pr := ghPullRequest{}
+	pr.Head.Ref = "cursor/fix-review-loop"
+	pr.Head.SHA = "rebased-sha"
+
+	ghMock.On("ListReviewComments", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestComment{
+		{
+			User:     &github.User{Login: github.Ptr("coderabbitai[bot]")},
+			Path:     github.Ptr("main.go"),
+			Line:     github.Ptr(7),
+			Body:     github.Ptr("Prompt for AI Agents\nRebase dropped the nil check"),
+			CommitID: github.Ptr("rebased-sha"),
+		},
+	}, nil)
+	ghMock.On("ListReviews", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestReview{}, nil)
+	ghMock.On("ListIssueComments", mock.Anything, "org", "repo", 42).Return([]*github.IssueComment{}, nil)
+
+	cursorMock.On("AddFollowup", mock.Anything, "agent-1", mock.MatchedBy(func(req cursor.FollowupRequest) bool {
+		return strings.Contains(req.Prompt.Text, "Rebase dropped the nil check")
+	})).Return(&cursor.FollowupResponse{ID: "agent-1"}, nil)
+
+	store.On("SaveReviewLoop", mock.Anything).Return(nil)
+	mockInlineStatusUpdate(store, api, "agent-1", &kvstore.AgentRecord{
+		CursorAgentID:  "agent-1",
+		BotReplyPostID: "reply-1",
+		ChannelID:      "ch-1",
+	})
+
+	err := p.reopenAIReviewGate(context.Background(), loop, codeRabbitFindingsReview(), pr)
+	require.NoError(t, err)
+
+	assert.Equal(t, kvstore.ReviewPhaseCursorFixing, loop.Phase)
+	assert.Equal(t, 2, loop.Iteration)
+	var reopened bool
+	for _, event := range loop.History {
+		if event.Phase == kvstore.ReviewPhaseAwaitingReview && event.Kind == reviewEventKindAIGateReopened &&
+			strings.Contains(event.Detail, "Reopened AI review gate for new findings from coderabbitai[bot]") {
+			reopened = true
+		}
+	}
+	assert.True(t, reopened, "expected a reopen event in history")
+	cursorMock.AssertExpectations(t)
+}
+
+func TestReopenAIReviewGate_NothingNewReturnsToHumanReview(t *testing.T) {
+	p, _, store, ghMock := setupReviewLoopTestPlugin(t)
+	p.configuration.ReviewLoopReopenOnAIFindings = true
+	cursorMock := p.cursorClient.(*mockCursorClient)
+
+	// The same feedback was already dispatched at this head.
+	loop := newHumanReviewLoop(1)
+	loop.LastFeedbackDispatchAt = 1
+	loop.LastFeedbackDispatchSHA = "sha-1"
+	loop.LastFeedbackDigest = reviewFeedbackDigest(nil)
+	pr := ghPullRequest{}
+	pr.Head.SHA = "sha-1"
+
+	ghMock.On("ListReviewComments", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestComment{}, nil)
+	ghMock.On("ListReviews", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestReview{}, nil)
+	ghMock.On("ListIssueComments", mock.Anything, "org", "repo", 42).Return([]*github.IssueComment{}, nil)
+
+	var saved []string
+	store.On("SaveReviewLoop", mock.Anything).Run(func(args mock.Arguments) {
+		saved = append(saved, args.Get(0).(*kvstore.ReviewLoop).Phase)
+	}).Return(nil)
+
+	err := p.reopenAIReviewGate(context.Background(), loop, codeRabbitFindingsReview(), pr)
+	require.NoError(t, err)
+
+	assert.Equal(t, kvstore.ReviewPhaseHumanReview, loop.Phase)
+	assert.Equal(t, 1, loop.Iteration)
+	require.NotEmpty(t, saved)
+	assert.Equal(t, kvstore.ReviewPhaseHumanReview, saved[len(saved)-1])
+	assert.Contains(t, loop.History[len(loop.History)-1].Detail, "returned to human review")
+	cursorMock.AssertNotCalled(t, "AddFollowup", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestReopenAIReviewGate_RespectsMaxIterations(t *testing.T) {
+	p, _, store, ghMock := setupReviewLoopTestPlugin(t)
+	p.configuration.ReviewLoopReopenOnAIFindings = true
+
+	loop := newHumanReviewLoop(5) // At MaxReviewIterations.
+
+	err := p.reopenAIReviewGate(context.Background(), loop, codeRabbitFindingsReview(), ghPullRequest{})
+	require.NoError(t, err)
+
+	assert.Equal(t, kvstore.ReviewPhaseHumanReview, loop.Phase)
+	assert.Empty(t, loop.History)
+	store.AssertNotCalled(t, "SaveReviewLoop", mock.Anything)
+	ghMock.AssertNotCalled(t, "ListReviewComments", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestReopenAIReviewGate_IgnoresApprovalsAndDisabledOption(t *testing.T) {
+	p, _, store, ghMock := setupReviewLoopTestPlugin(t)
+
+	loop := newHumanReviewLoop(1)
+
+	// Disabled by default.
+	require.NoError(t, p.reopenAIReviewGate(context.Background(), loop, codeRabbitFindingsReview(), ghPullRequest{}))
+	assert.Equal(t, kvstore.ReviewPhaseHumanReview, loop.Phase)
+
+	// Enabled, but a satisfied CodeRabbit review carries no findings.
+	p.configuration.ReviewLoopReopenOnAIFindings = true
+	approval := ghReview{State: "approved"}
+	approval.User.Login = "coderabbitai[bot]"
+	require.NoError(t, p.reopenAIReviewGate(context.Background(), loop, approval, ghPullRequest{}))
+	assert.Equal(t, kvstore.ReviewPhaseHumanReview, loop.Phase)
+
+	assert.Empty(t, loop.History)
+	store.AssertNotCalled(t, "SaveReviewLoop", mock.Anything)
+	ghMock.AssertNotCalled(t, "ListReviewComments", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
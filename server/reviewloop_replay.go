@@ -0,0 +1,158 @@
+package main
+
+import (
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+// Classifications of a finding in a reconstructed review loop snapshot.
+const (
+	snapshotFindingNew        = "new"
+	snapshotFindingRepeated   = "repeated"
+	snapshotFindingUnseen     = "unseen"
+	snapshotFindingResolved   = findingStatusResolved
+	snapshotFindingDismissed  = findingStatusDismissed
+	snapshotFindingSuperseded = findingStatusSuperseded
+)
+
+// reviewLoopSnapshot is a review loop's findings and history as they stood
+// at a past iteration.
+type reviewLoopSnapshot struct {
+	Iteration int
+	Phase     string
+	Findings  []reviewFindingSnapshot
+	History   []kvstore.ReviewLoopEvent
+}
+
+// reviewFindingSnapshot is a stored finding with the status and
+// classification it had at the snapshot iteration.
+type reviewFindingSnapshot struct {
+	Finding        kvstore.ReviewFinding
+	Status         string
+	Classification string
+	Dispatchable   bool
+}
+
+// reviewLoopSnapshotAt rebuilds the loop's state as of the given iteration
+// from the stored findings' first/last seen iterations and the loop history,
+// without contacting GitHub. Findings first seen after the iteration are
+// left out. A finding seen at the iteration was open then; one last seen
+// earlier is reported with its current status. The result is best effort:
+// only the latest snooze of each finding is stored, so earlier snoozes are
+// not reflected.
+func reviewLoopSnapshotAt(loop *kvstore.ReviewLoop, iteration int) reviewLoopSnapshot {
+	snapshot := reviewLoopSnapshot{
+		Iteration: iteration,
+		Findings:  []reviewFindingSnapshot{},
+		History:   reviewLoopHistoryThrough(loop.History, iteration),
+	}
+	if n := len(snapshot.History); n > 0 {
+		snapshot.Phase = snapshot.History[n-1].Phase
+	}
+
+	for _, finding := range loop.Findings {
+		firstSeen := finding.FirstSeenIteration
+		if firstSeen > iteration {
+			continue
+		}
+		lastSeen := finding.LastSeenIteration
+		if lastSeen < firstSeen {
+			lastSeen = firstSeen
+		}
+
+		entry := reviewFindingSnapshot{Finding: finding}
+		switch {
+		case lastSeen >= iteration:
+			entry.Status = findingStatusOpen
+			entry.Classification = snapshotFindingRepeated
+			if firstSeen == iteration {
+				entry.Classification = snapshotFindingNew
+			}
+			entry.Dispatchable = !isFindingSnoozed(finding, iteration)
+		case finding.Status == findingStatusOpen || finding.Status == "":
+			entry.Status = findingStatusOpen
+			entry.Classification = snapshotFindingUnseen
+		default:
+			entry.Status = finding.Status
+			entry.Classification = finding.Status
+		}
+		snapshot.Findings = append(snapshot.Findings, entry)
+	}
+
+	return snapshot
+}
+
+// reviewLoopHistoryThrough returns the history events recorded up to and
+// including the feedback dispatch that ended the given iteration. Loops start
+// at iteration 1, and each delivered dispatch moves them to the next one.
+func reviewLoopHistoryThrough(history []kvstore.ReviewLoopEvent, iteration int) []kvstore.ReviewLoopEvent {
+	current := 1
+	events := []kvstore.ReviewLoopEvent{}
+	for _, event := range history {
+		if current > iteration {
+			break
+		}
+		events = append(events, event)
+		if endsReviewIteration(event) {
+			current++
+		}
+	}
+	return events
+}
+
+// endsReviewIteration reports whether a history event records the delivered
+// feedback dispatch that advances the loop to its next iteration.
+func endsReviewIteration(event kvstore.ReviewLoopEvent) bool {
+	if event.Phase != kvstore.ReviewPhaseCursorFixing {
+		return false
+	}
+	return event.Mode == reviewDispatchModeDirect || event.Mode == reviewDispatchModeRecovered
+}
+
+// findingStatusChange is a finding's status from the iteration it took
+// effect, as reconstructed by reviewLoopSnapshotAt.
+type findingStatusChange struct {
+	Iteration int
+	StartedAt int64 // Unix millis the iteration began, 0 when unknown
+	Status    string
+}
+
+// findingStatusTimelines returns each finding's status changes across the
+// loop's iterations, keyed by finding key. Consecutive iterations with the
+// same status are collapsed into the first.
+func findingStatusTimelines(loop *kvstore.ReviewLoop) map[string][]findingStatusChange {
+	startedAt := reviewIterationStartTimes(loop.History)
+	timelines := map[string][]findingStatusChange{}
+	for iteration := 1; iteration <= loop.Iteration; iteration++ {
+		for _, entry := range reviewLoopSnapshotAt(loop, iteration).Findings {
+			key := entry.Finding.Key
+			timeline := timelines[key]
+			if n := len(timeline); n > 0 && timeline[n-1].Status == entry.Status {
+				continue
+			}
+			timelines[key] = append(timeline, findingStatusChange{
+				Iteration: iteration,
+				StartedAt: startedAt[iteration],
+				Status:    entry.Status,
+			})
+		}
+	}
+	return timelines
+}
+
+// reviewIterationStartTimes maps each iteration to the timestamp of its first
+// history event: the loop's first event for iteration 1, and the dispatch
+// that ended the previous iteration for later ones.
+func reviewIterationStartTimes(history []kvstore.ReviewLoopEvent) map[int]int64 {
+	startedAt := map[int]int64{}
+	current := 1
+	for _, event := range history {
+		if _, ok := startedAt[current]; !ok {
+			startedAt[current] = event.Timestamp
+		}
+		if endsReviewIteration(event) {
+			current++
+			startedAt[current] = event.Timestamp
+		}
+	}
+	return startedAt
+}
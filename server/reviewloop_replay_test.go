@@ -0,0 +1,103 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+// newMultiIterationReviewLoop returns a loop at iteration 4 whose findings
+// and history span its first three iterations.
+func newMultiIterationReviewLoop() *kvstore.ReviewLoop {
+	return &kvstore.ReviewLoop{
+		ID:        "loop-1",
+		UserID:    "user-1",
+		Phase:     kvstore.ReviewPhaseAwaitingReview,
+		Iteration: 4,
+		Findings: []kvstore.ReviewFinding{
+			{Key: "repeated", Status: findingStatusOpen, FirstSeenIteration: 1, LastSeenIteration: 3},
+			{Key: "new-at-2", Status: findingStatusResolved, FirstSeenIteration: 2, LastSeenIteration: 2},
+			{Key: "new-at-3", Status: findingStatusOpen, FirstSeenIteration: 3, LastSeenIteration: 3},
+			{Key: "resolved-after-1", Status: findingStatusResolved, FirstSeenIteration: 1, LastSeenIteration: 1},
+			{Key: "unseen-after-1", Status: findingStatusOpen, FirstSeenIteration: 1, LastSeenIteration: 1},
+		},
+		History: []kvstore.ReviewLoopEvent{
+			{Phase: kvstore.ReviewPhaseAwaitingReview, Timestamp: 1, Detail: "Review loop started"},
+			{Phase: kvstore.ReviewPhaseCursorFixing, Timestamp: 2, Mode: reviewDispatchModeDirect},
+			{Phase: kvstore.ReviewPhaseAwaitingReview, Timestamp: 3},
+			{Phase: kvstore.ReviewPhaseCursorFixing, Timestamp: 4, Mode: reviewDispatchModeSkippedIdempotent},
+			{Phase: kvstore.ReviewPhaseCursorFixing, Timestamp: 5, Mode: reviewDispatchModeDirect},
+			{Phase: kvstore.ReviewPhaseAwaitingReview, Timestamp: 6},
+			{Phase: kvstore.ReviewPhaseCursorFixing, Timestamp: 7, Mode: reviewDispatchModeRecovered},
+			{Phase: kvstore.ReviewPhaseAwaitingReview, Timestamp: 8},
+		},
+	}
+}
+
+func TestReviewLoopSnapshotAt_ExcludesLaterFindings(t *testing.T) {
+	loop := newMultiIterationReviewLoop()
+
+	snapshot := reviewLoopSnapshotAt(loop, 2)
+
+	classifications := map[string]string{}
+	for _, entry := range snapshot.Findings {
+		classifications[entry.Finding.Key] = entry.Classification
+	}
+	assert.Equal(t, map[string]string{
+		"repeated":         snapshotFindingRepeated,
+		"new-at-2":         snapshotFindingNew,
+		"resolved-after-1": snapshotFindingResolved,
+		"unseen-after-1":   snapshotFindingUnseen,
+	}, classifications)
+	assert.NotContains(t, classifications, "new-at-3")
+
+	for _, entry := range snapshot.Findings {
+		switch entry.Finding.Key {
+		case "new-at-2":
+			// Resolved today, but still open at iteration 2.
+			assert.Equal(t, findingStatusOpen, entry.Status)
+			assert.True(t, entry.Dispatchable)
+		case "resolved-after-1":
+			assert.Equal(t, findingStatusResolved, entry.Status)
+			assert.False(t, entry.Dispatchable)
+		}
+	}
+
+	// History runs through the dispatch that ended iteration 2.
+	require.Len(t, snapshot.History, 5)
+	assert.Equal(t, int64(5), snapshot.History[4].Timestamp)
+	assert.Equal(t, kvstore.ReviewPhaseCursorFixing, snapshot.Phase)
+
+	// Reconstruction never changes the stored loop.
+	assert.Equal(t, findingStatusResolved, loop.Findings[1].Status)
+	assert.Len(t, loop.History, 8)
+}
+
+func TestReviewLoopSnapshotAt_FirstIteration(t *testing.T) {
+	loop := newMultiIterationReviewLoop()
+
+	snapshot := reviewLoopSnapshotAt(loop, 1)
+
+	keys := []string{}
+	for _, entry := range snapshot.Findings {
+		keys = append(keys, entry.Finding.Key)
+		assert.Equal(t, snapshotFindingNew, entry.Classification)
+		assert.Equal(t, findingStatusOpen, entry.Status)
+	}
+	assert.Equal(t, []string{"repeated", "resolved-after-1", "unseen-after-1"}, keys)
+	require.Len(t, snapshot.History, 2)
+}
+
+func TestReviewLoopSnapshotAt_SnoozedFindingNotDispatchable(t *testing.T) {
+	loop := newMultiIterationReviewLoop()
+	loop.Findings[0].SnoozedUntilIteration = 2
+
+	snapshot := reviewLoopSnapshotAt(loop, 2)
+
+	require.Equal(t, "repeated", snapshot.Findings[0].Finding.Key)
+	assert.False(t, snapshot.Findings[0].Dispatchable)
+	assert.Equal(t, findingStatusOpen, snapshot.Findings[0].Status)
+}
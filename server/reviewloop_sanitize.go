@@ -0,0 +1,44 @@
+package main
+
+import (
+	"regexp"
+)
+
+// Markers enclosing reviewer-supplied text in review follow-up prompts when
+// SanitizeReviewFeedback is enabled.
+const (
+	untrustedFeedbackBegin = "<<<BEGIN UNTRUSTED REVIEW FEEDBACK>>>"
+	untrustedFeedbackEnd   = "<<<END UNTRUSTED REVIEW FEEDBACK>>>"
+
+	neutralizedDirective = "[directive removed]"
+)
+
+var (
+	// untrustedMarkerRE matches anything resembling the block markers so a
+	// comment cannot close the untrusted block early.
+	untrustedMarkerRE = regexp.MustCompile(`(?i)<<<\s*(BEGIN|END)\s+UNTRUSTED\s+REVIEW\s+FEEDBACK\s*>>>`)
+
+	// directiveRES match phrases that try to steer the agent rather than
+	// describe a code issue.
+	directiveRES = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)\b(ignore|disregard|forget|override|bypass)\s+(all\s+|any\s+|the\s+|your\s+)*(previous|prior|above|earlier|preceding|system|original)?\s*(instructions?|prompts?|rules|directions|constraints|guardrails)\b`),
+		regexp.MustCompile(`(?i)\b(new|updated|real)\s+(system\s+)?instructions\s*:`),
+		regexp.MustCompile(`(?i)\bsystem\s+prompt\b`),
+		regexp.MustCompile(`(?i)\b(open|create|submit|raise)\s+(a\s+)?(new|separate|another|second)\s+(pull\s+request|PR)\b`),
+		regexp.MustCompile(`(?i)</?\|?\s*(system|assistant|im_start|im_end)\s*\|?>`),
+	}
+)
+
+// sanitizeUntrustedFeedback neutralizes directive-like phrases in reviewer
+// text and strips look-alike block markers, leaving the technical content
+// untouched.
+func sanitizeUntrustedFeedback(text string) string {
+	if text == "" {
+		return text
+	}
+	text = untrustedMarkerRE.ReplaceAllString(text, "")
+	for _, re := range directiveRES {
+		text = re.ReplaceAllString(text, neutralizedDirective)
+	}
+	return text
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/attachments"
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+// escalateStaleReviewLoops is called from the poller. It posts a reminder in
+// the thread of each loop that has been waiting on reviewers longer than
+// ReviewLoopStaleHours. Each wait is escalated once, and snoozed loops are
+// skipped until the snooze expires.
+func (p *Plugin) escalateStaleReviewLoops() {
+	config := p.getConfiguration()
+	staleAfter := config.GetReviewLoopStaleAfter()
+	if !config.EnableAIReviewLoop || staleAfter == 0 {
+		return
+	}
+
+	loops, err := p.kvstore.ListWaitingReviewLoops()
+	if err != nil {
+		p.API.LogError("Failed to list review loops waiting on reviewers", "error", err.Error())
+		return
+	}
+
+	now := p.now()
+	for _, loop := range loops {
+		if !isReviewLoopStale(loop, now, staleAfter) {
+			continue
+		}
+		p.escalateStaleReviewLoop(loop, now)
+	}
+}
+
+// reviewLoopWaitingSince returns when the loop last made progress: its most
+// recent history event, or its creation time if it has none. Reviewer nudges
+// are not progress.
+func reviewLoopWaitingSince(loop *kvstore.ReviewLoop) int64 {
+	since := loop.CreatedAt
+	for _, event := range loop.History {
+		if event.Mode == reviewLoopEventModeNudge {
+			continue
+		}
+		if event.Timestamp > since {
+			since = event.Timestamp
+		}
+	}
+	return since
+}
+
+// isReviewLoopStale reports whether a waiting loop should be escalated now.
+func isReviewLoopStale(loop *kvstore.ReviewLoop, now time.Time, staleAfter time.Duration) bool {
+	if loop.SnoozeUntil > now.UnixMilli() {
+		return false
+	}
+	since := reviewLoopWaitingSince(loop)
+	if loop.StaleEscalatedAt >= since {
+		return false // Already escalated for this wait.
+	}
+	return now.Sub(time.UnixMilli(since)) >= staleAfter
+}
+
+func (p *Plugin) escalateStaleReviewLoop(loop *kvstore.ReviewLoop, now time.Time) {
+	waited := now.Sub(time.UnixMilli(reviewLoopWaitingSince(loop)))
+
+	// Persist before posting so a failed save cannot repeat the reminder.
+	loop.StaleEscalatedAt = now.UnixMilli()
+	loop.SnoozeUntil = 0
+	loop.UpdatedAt = now.UnixMilli()
+	if err := p.kvstore.SaveReviewLoop(loop); err != nil {
+		p.API.LogError("Failed to save stale review loop escalation",
+			"review_loop_id", loop.ID,
+			"error", err.Error(),
+		)
+		return
+	}
+
+	p.postReviewLoopCompletion(loop, attachments.BuildStaleReviewLoopAttachment(
+		loop.PRURL,
+		int(waited/time.Hour),
+		loop.Phase == kvstore.ReviewPhaseHumanReview,
+	))
+}
+
+// snoozeReviewLoop suppresses stale escalation for the loop until the given
+// time. A zero until clears the snooze. Clearing the last escalation lets the
+// loop be escalated again once the snooze expires.
+func (p *Plugin) snoozeReviewLoop(loop *kvstore.ReviewLoop, until int64) {
+	loop.SnoozeUntil = until
+	loop.StaleEscalatedAt = 0
+	loop.UpdatedAt = p.now().UnixMilli()
+}
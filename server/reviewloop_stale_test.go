@@ -0,0 +1,116 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+func newWaitingReviewLoop(waitingSince time.Time) *kvstore.ReviewLoop {
+	return &kvstore.ReviewLoop{
+		ID:         "loop-1",
+		UserID:     "user-1",
+		ChannelID:  "ch-1",
+		RootPostID: "root-1",
+		PRURL:      "https://github.com/org/repo/pull/42",
+		Phase:      kvstore.ReviewPhaseAwaitingReview,
+		CreatedAt:  waitingSince.Add(-time.Hour).UnixMilli(),
+		History: []kvstore.ReviewLoopEvent{
+			{Phase: kvstore.ReviewPhaseAwaitingReview, Timestamp: waitingSince.UnixMilli()},
+		},
+	}
+}
+
+func TestEscalateStaleReviewLoops_EscalatesOncePerWait(t *testing.T) {
+	p, api, store, _ := setupReviewLoopTestPlugin(t)
+	p.configuration.ReviewLoopStaleHours = 24
+
+	loop := newWaitingReviewLoop(time.Now().Add(-30 * time.Hour))
+	store.On("ListWaitingReviewLoops").Return([]*kvstore.ReviewLoop{loop}, nil)
+	store.On("SaveReviewLoop", loop).Return(nil).Once()
+	api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		attachment := post.Attachments()[0]
+		return post.RootId == "root-1" && attachment.Title == "PR has been waiting on AI reviewers for 30 hours."
+	})).Return(&model.Post{}, nil).Once()
+
+	p.escalateStaleReviewLoops()
+	assert.NotZero(t, loop.StaleEscalatedAt)
+
+	// The same wait is not escalated twice.
+	p.escalateStaleReviewLoops()
+
+	store.AssertExpectations(t)
+	api.AssertExpectations(t)
+}
+
+func TestEscalateStaleReviewLoops_SkipsFreshLoop(t *testing.T) {
+	p, api, store, _ := setupReviewLoopTestPlugin(t)
+	p.configuration.ReviewLoopStaleHours = 24
+
+	loop := newWaitingReviewLoop(time.Now().Add(-2 * time.Hour))
+	store.On("ListWaitingReviewLoops").Return([]*kvstore.ReviewLoop{loop}, nil)
+
+	p.escalateStaleReviewLoops()
+
+	store.AssertNotCalled(t, "SaveReviewLoop", mock.Anything)
+	api.AssertNotCalled(t, "CreatePost", mock.Anything)
+}
+
+func TestEscalateStaleReviewLoops_DisabledByDefault(t *testing.T) {
+	p, _, store, _ := setupReviewLoopTestPlugin(t)
+
+	p.escalateStaleReviewLoops()
+
+	store.AssertNotCalled(t, "ListWaitingReviewLoops")
+}
+
+func TestEscalateStaleReviewLoops_RespectsSnooze(t *testing.T) {
+	p, api, store, _ := setupReviewLoopTestPlugin(t)
+	p.configuration.ReviewLoopStaleHours = 24
+
+	loop := newWaitingReviewLoop(time.Now().Add(-30 * time.Hour))
+	p.snoozeReviewLoop(loop, time.Now().Add(time.Hour).UnixMilli())
+	store.On("ListWaitingReviewLoops").Return([]*kvstore.ReviewLoop{loop}, nil)
+
+	p.escalateStaleReviewLoops()
+	store.AssertNotCalled(t, "SaveReviewLoop", mock.Anything)
+	api.AssertNotCalled(t, "CreatePost", mock.Anything)
+
+	// Once the snooze expires the loop is escalated and the snooze cleared.
+	loop.SnoozeUntil = time.Now().Add(-time.Minute).UnixMilli()
+	store.On("SaveReviewLoop", loop).Return(nil).Once()
+	api.On("CreatePost", mock.Anything).Return(&model.Post{}, nil).Once()
+
+	p.escalateStaleReviewLoops()
+
+	store.AssertExpectations(t)
+	api.AssertExpectations(t)
+	assert.Zero(t, loop.SnoozeUntil)
+	assert.NotZero(t, loop.StaleEscalatedAt)
+}
+
+func TestEscalateStaleReviewLoops_SnoozeAfterEscalationRearms(t *testing.T) {
+	p, api, store, _ := setupReviewLoopTestPlugin(t)
+	p.configuration.ReviewLoopStaleHours = 24
+
+	loop := newWaitingReviewLoop(time.Now().Add(-30 * time.Hour))
+	loop.StaleEscalatedAt = time.Now().Add(-time.Hour).UnixMilli()
+
+	// Snoozing after an escalation re-arms it for when the snooze ends.
+	p.snoozeReviewLoop(loop, time.Now().Add(-time.Second).UnixMilli())
+	assert.Zero(t, loop.StaleEscalatedAt)
+
+	store.On("ListWaitingReviewLoops").Return([]*kvstore.ReviewLoop{loop}, nil)
+	store.On("SaveReviewLoop", loop).Return(nil).Once()
+	api.On("CreatePost", mock.Anything).Return(&model.Post{}, nil).Once()
+
+	p.escalateStaleReviewLoops()
+
+	store.AssertExpectations(t)
+	api.AssertExpectations(t)
+}
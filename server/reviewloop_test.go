@@ -3,8 +3,10 @@ package main
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-github/v68/github"
 	"github.com/mattermost/mattermost/server/public/model"
@@ -12,6 +14,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/mattermost/mattermost-plugin-cursor/server/attachments"
 	"github.com/mattermost/mattermost-plugin-cursor/server/cursor"
 	"github.com/mattermost/mattermost-plugin-cursor/server/ghclient"
 	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
@@ -65,6 +68,14 @@ func (m *mockGitHubClient) ListIssueComments(ctx context.Context, owner, repo st
 	return args.Get(0).([]*github.IssueComment), args.Error(1)
 }
 
+func (m *mockGitHubClient) ListPullRequestCommits(ctx context.Context, owner, repo string, prNumber int) ([]*github.RepositoryCommit, error) {
+	args := m.Called(ctx, owner, repo, prNumber)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*github.RepositoryCommit), args.Error(1)
+}
+
 func (m *mockGitHubClient) ReplyToReviewComment(ctx context.Context, owner, repo string, prNumber int, commentID int64, body string) (*github.PullRequestComment, error) {
 	args := m.Called(ctx, owner, repo, prNumber, commentID, body)
 	if args.Get(0) == nil {
@@ -73,6 +84,14 @@ func (m *mockGitHubClient) ReplyToReviewComment(ctx context.Context, owner, repo
 	return args.Get(0).(*github.PullRequestComment), args.Error(1)
 }
 
+func (m *mockGitHubClient) GetPullRequest(ctx context.Context, owner, repo string, prNumber int) (*github.PullRequest, error) {
+	args := m.Called(ctx, owner, repo, prNumber)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*github.PullRequest), args.Error(1)
+}
+
 func (m *mockGitHubClient) GetPullRequestByBranch(ctx context.Context, owner, repo, branch string) (*github.PullRequest, error) {
 	args := m.Called(ctx, owner, repo, branch)
 	if args.Get(0) == nil {
@@ -81,6 +100,58 @@ func (m *mockGitHubClient) GetPullRequestByBranch(ctx context.Context, owner, re
 	return args.Get(0).(*github.PullRequest), args.Error(1)
 }
 
+func (m *mockGitHubClient) GetPullRequestDiff(ctx context.Context, owner, repo string, prNumber int) (string, error) {
+	args := m.Called(ctx, owner, repo, prNumber)
+	return args.String(0), args.Error(1)
+}
+
+func (m *mockGitHubClient) CompareCommits(ctx context.Context, owner, repo, base, head string) (*github.CommitsComparison, error) {
+	args := m.Called(ctx, owner, repo, base, head)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*github.CommitsComparison), args.Error(1)
+}
+
+func (m *mockGitHubClient) GetBranch(ctx context.Context, owner, repo, branch string) (*github.Branch, error) {
+	args := m.Called(ctx, owner, repo, branch)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*github.Branch), args.Error(1)
+}
+
+func (m *mockGitHubClient) ListReviewThreads(ctx context.Context, owner, repo string, prNumber int) ([]ghclient.ReviewThread, error) {
+	args := m.Called(ctx, owner, repo, prNumber)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]ghclient.ReviewThread), args.Error(1)
+}
+
+func (m *mockGitHubClient) ResolveReviewThread(ctx context.Context, threadID string) error {
+	args := m.Called(ctx, threadID)
+	return args.Error(0)
+}
+
+func (m *mockGitHubClient) GetChecksState(ctx context.Context, owner, repo, ref string) (string, error) {
+	args := m.Called(ctx, owner, repo, ref)
+	return args.String(0), args.Error(1)
+}
+
+func (m *mockGitHubClient) GetIssue(ctx context.Context, owner, repo string, number int) (*github.Issue, error) {
+	args := m.Called(ctx, owner, repo, number)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*github.Issue), args.Error(1)
+}
+
+func (m *mockGitHubClient) MergePR(ctx context.Context, owner, repo string, prNumber int, sha, mergeMethod string) error {
+	args := m.Called(ctx, owner, repo, prNumber, sha, mergeMethod)
+	return args.Error(0)
+}
+
 func setupReviewLoopTestPlugin(t *testing.T) (*Plugin, *mockPluginAPI, *mockKVStore, *mockGitHubClient) {
 	t.Helper()
 	p, api, _, store := setupTestPlugin(t)
@@ -209,6 +280,22 @@ func assertDroppedCandidateLogRequiredFields(t *testing.T, fields map[string]any
 	}
 }
 
+func TestLockReviewLoop_ReturnsFreshLoopAndReusesStripe(t *testing.T) {
+	p, _, store, _ := setupReviewLoopTestPlugin(t)
+
+	stale := &kvstore.ReviewLoop{ID: "loop-1", PRURL: "https://github.com/org/repo/pull/42", Phase: kvstore.ReviewPhaseCursorFixing}
+	fresh := &kvstore.ReviewLoop{ID: "loop-1", PRURL: stale.PRURL, Phase: kvstore.ReviewPhaseAwaitingReview}
+	store.On("GetReviewLoopByPRURL", stale.PRURL).Return(fresh, nil)
+
+	loop, unlock := p.lockReviewLoop(stale)
+	assert.Same(t, fresh, loop)
+	assert.False(t, p.reviewLoopLock("loop-1").TryLock())
+	unlock()
+
+	assert.Same(t, p.reviewLoopLock("loop-1"), p.reviewLoopLock("loop-1"))
+	assert.True(t, p.reviewLoopLock("loop-1").TryLock())
+}
+
 func TestStartReviewLoop(t *testing.T) {
 	p, api, store, ghMock := setupReviewLoopTestPlugin(t)
 
@@ -257,6 +344,43 @@ func TestStartReviewLoop(t *testing.T) {
 	ghMock.AssertExpectations(t)
 }
 
+func TestStartReviewLoop_MappedPRURLUsesCanonicalRepo(t *testing.T) {
+	p, api, store, ghMock := setupReviewLoopTestPlugin(t)
+	p.configuration.GitHubRepoMappings = "git.example.com/mirrors/app=acme/app"
+
+	record := &kvstore.AgentRecord{
+		CursorAgentID:  "agent-1",
+		UserID:         "user-1",
+		ChannelID:      "ch-1",
+		PostID:         "root-1",
+		TriggerPostID:  "trigger-1",
+		BotReplyPostID: "reply-1",
+		PrURL:          "https://git.example.com/mirrors/app/pull/42",
+	}
+
+	store.On("GetReviewLoopByPRURL", record.PrURL).Return(nil, nil)
+	store.On("GetWorkflowByAgent", "agent-1").Return("", nil)
+	store.On("SaveReviewLoop", mock.MatchedBy(func(loop *kvstore.ReviewLoop) bool {
+		return loop.Owner == "acme" &&
+			loop.Repo == "app" &&
+			loop.Repository == "acme/app" &&
+			loop.PRNumber == 42 &&
+			loop.PRURL == record.PrURL
+	})).Return(nil)
+
+	// API calls go to the canonical repository, not the mirror path.
+	ghMock.On("MarkPRReadyForReview", mock.Anything, "acme", "app", 42).Return(nil)
+	ghMock.On("RequestReviewers", mock.Anything, "acme", "app", 42, mock.Anything).Return(nil)
+
+	mockInlineStatusUpdate(store, api, "agent-1", record)
+	api.On("AddReaction", mock.Anything).Return(nil, nil)
+
+	err := p.startReviewLoop(record)
+	require.NoError(t, err)
+	store.AssertExpectations(t)
+	ghMock.AssertExpectations(t)
+}
+
 func TestStartReviewLoop_AlreadyExists(t *testing.T) {
 	p, _, store, ghMock := setupReviewLoopTestPlugin(t)
 
@@ -385,7 +509,7 @@ func TestHandleAIReview_CodeRabbitApproved(t *testing.T) {
 		return r.PostId == "trigger-1" && r.EmojiName == "white_check_mark"
 	})).Return(nil, nil)
 
-	err := p.handleAIReview(loop, review, pr)
+	err := p.handleAIReview(context.Background(), loop, review, pr)
 	require.NoError(t, err)
 	store.AssertExpectations(t)
 }
@@ -427,7 +551,7 @@ func TestHandleAIReview_CodeRabbitApprovedViaState(t *testing.T) {
 	api.On("RemoveReaction", mock.Anything).Return(nil)
 	api.On("AddReaction", mock.Anything).Return(nil, nil)
 
-	err := p.handleAIReview(loop, review, pr)
+	err := p.handleAIReview(context.Background(), loop, review, pr)
 	require.NoError(t, err)
 
 	// Verify phase transitioned to approved (first save) then human_review (second save).
@@ -488,15 +612,20 @@ func TestHandleAIReview_FeedbackReceived(t *testing.T) {
 			strings.Contains(req.Prompt.Text, "pull_request_url: https://github.com/org/repo/pull/42")
 	})).Return(&cursor.FollowupResponse{ID: "agent-1"}, nil)
 
+	// SaveReviewLoop for the pre-dispatch checkpoint.
+	store.On("SaveReviewLoop", mock.MatchedBy(func(l *kvstore.ReviewLoop) bool {
+		return l.Phase == kvstore.ReviewPhaseAwaitingReview && l.PendingDispatchID != ""
+	})).Return(nil).Once()
+
 	// SaveReviewLoop for cursor_fixing transition.
 	store.On("SaveReviewLoop", mock.MatchedBy(func(l *kvstore.ReviewLoop) bool {
-		return l.Phase == kvstore.ReviewPhaseCursorFixing && l.Iteration == 2
+		return l.Phase == kvstore.ReviewPhaseCursorFixing && l.Iteration == 2 && l.PendingDispatchID == ""
 	})).Return(nil)
 
 	// Inline status update (replaces old thread notification).
 	mockInlineStatusUpdate(store, api, "agent-1", agentRecord)
 
-	err := p.handleAIReview(loop, review, pr)
+	err := p.handleAIReview(context.Background(), loop, review, pr)
 	require.NoError(t, err)
 	require.NotEmpty(t, loop.Findings)
 	require.NotEmpty(t, loop.History)
@@ -511,9 +640,12 @@ func TestHandleAIReview_FeedbackReceived(t *testing.T) {
 }
 
 func TestDispatchReviewFeedback_IdempotentSkip(t *testing.T) {
-	p, _, _, ghMock := setupReviewLoopTestPlugin(t)
+	p, _, store, ghMock := setupReviewLoopTestPlugin(t)
 	cursorMock := p.cursorClient.(*mockCursorClient)
 
+	// Pre-dispatch checkpoint persistence.
+	store.On("SaveReviewLoop", mock.Anything).Return(nil)
+
 	loop := &kvstore.ReviewLoop{
 		ID:            "loop-idempotent",
 		AgentRecordID: "agent-1",
@@ -545,7 +677,7 @@ func TestDispatchReviewFeedback_IdempotentSkip(t *testing.T) {
 	cursorMock.On("AddFollowup", mock.Anything, "agent-1", mock.Anything).
 		Return(&cursor.FollowupResponse{ID: "agent-1"}, nil).Once()
 
-	firstOutcome, err := p.dispatchReviewFeedback(loop, pr)
+	firstOutcome, err := p.dispatchReviewFeedback(context.Background(), loop, pr)
 	require.NoError(t, err)
 	require.True(t, firstOutcome.Dispatched)
 	assert.Equal(t, reviewDispatchModeDirect, firstOutcome.Mode)
@@ -555,7 +687,7 @@ func TestDispatchReviewFeedback_IdempotentSkip(t *testing.T) {
 	require.NotEmpty(t, loop.LastFeedbackDigest)
 	assert.Equal(t, "sha-1", loop.LastFeedbackDispatchSHA)
 
-	secondOutcome, err := p.dispatchReviewFeedback(loop, pr)
+	secondOutcome, err := p.dispatchReviewFeedback(context.Background(), loop, pr)
 	require.NoError(t, err)
 	require.True(t, secondOutcome.Skipped)
 	assert.Equal(t, reviewDispatchModeSkippedIdempotent, secondOutcome.Mode)
@@ -570,9 +702,12 @@ func TestDispatchReviewFeedback_IdempotentSkip(t *testing.T) {
 }
 
 func TestDispatchReviewFeedback_DifferentSHASameDigestDispatchesAgain(t *testing.T) {
-	p, _, _, ghMock := setupReviewLoopTestPlugin(t)
+	p, _, store, ghMock := setupReviewLoopTestPlugin(t)
 	cursorMock := p.cursorClient.(*mockCursorClient)
 
+	// Pre-dispatch checkpoint persistence.
+	store.On("SaveReviewLoop", mock.Anything).Return(nil)
+
 	loop := &kvstore.ReviewLoop{
 		ID:            "loop-sha-replay",
 		AgentRecordID: "agent-1",
@@ -602,7 +737,7 @@ func TestDispatchReviewFeedback_DifferentSHASameDigestDispatchesAgain(t *testing
 	pr1.Head.Ref = "cursor/fix-review-loop"
 	pr1.Head.SHA = "sha-1"
 
-	firstOutcome, err := p.dispatchReviewFeedback(loop, pr1)
+	firstOutcome, err := p.dispatchReviewFeedback(context.Background(), loop, pr1)
 	require.NoError(t, err)
 	require.True(t, firstOutcome.Dispatched)
 	require.False(t, firstOutcome.Skipped)
@@ -615,7 +750,7 @@ func TestDispatchReviewFeedback_DifferentSHASameDigestDispatchesAgain(t *testing
 	pr2.Head.Ref = "cursor/fix-review-loop"
 	pr2.Head.SHA = "sha-2"
 
-	secondOutcome, err := p.dispatchReviewFeedback(loop, pr2)
+	secondOutcome, err := p.dispatchReviewFeedback(context.Background(), loop, pr2)
 	require.NoError(t, err)
 	require.True(t, secondOutcome.Dispatched)
 	require.False(t, secondOutcome.Skipped)
@@ -629,9 +764,12 @@ func TestDispatchReviewFeedback_DifferentSHASameDigestDispatchesAgain(t *testing
 }
 
 func TestDispatchReviewFeedback_FailsFastOnDirectFailure(t *testing.T) {
-	p, api, _, ghMock := setupReviewLoopTestPlugin(t)
+	p, api, store, ghMock := setupReviewLoopTestPlugin(t)
 	cursorMock := p.cursorClient.(*mockCursorClient)
 
+	// Pre-dispatch checkpoint persistence.
+	store.On("SaveReviewLoop", mock.Anything).Return(nil)
+
 	loop := &kvstore.ReviewLoop{
 		ID:            "loop-direct-fail",
 		AgentRecordID: "agent-1",
@@ -663,7 +801,7 @@ func TestDispatchReviewFeedback_FailsFastOnDirectFailure(t *testing.T) {
 	cursorMock.On("AddFollowup", mock.Anything, "agent-1", mock.Anything).
 		Return(nil, fmt.Errorf("agent is not running")).Once()
 
-	outcome, err := p.dispatchReviewFeedback(loop, pr)
+	outcome, err := p.dispatchReviewFeedback(context.Background(), loop, pr)
 	require.NoError(t, err)
 	require.True(t, outcome.Failed)
 	assert.Equal(t, reviewDispatchModeFailed, outcome.Mode)
@@ -726,7 +864,7 @@ func TestDispatchReviewFeedback_FailsWhenCursorClientMissing(t *testing.T) {
 	ghMock.On("ListReviews", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestReview{}, nil)
 	ghMock.On("ListIssueComments", mock.Anything, "org", "repo", 42).Return([]*github.IssueComment{}, nil)
 
-	outcome, err := p.dispatchReviewFeedback(loop, pr)
+	outcome, err := p.dispatchReviewFeedback(context.Background(), loop, pr)
 	require.NoError(t, err)
 	require.True(t, outcome.Failed)
 	assert.Equal(t, reviewDispatchModeFailed, outcome.Mode)
@@ -756,9 +894,9 @@ func TestDispatchReviewFeedback_FailsWhenCursorClientMissing(t *testing.T) {
 	ghMock.AssertExpectations(t)
 }
 
-func TestHandleAIReview_MaxIterations(t *testing.T) {
-	p, api, store, _ := setupReviewLoopTestPlugin(t)
-	p.configuration.MaxReviewIterations = 3
+func TestHandleAIReview_DispatchFailureEntersErrorPhase(t *testing.T) {
+	p, api, store, ghMock := setupReviewLoopTestPlugin(t)
+	cursorMock := p.cursorClient.(*mockCursorClient)
 
 	loop := &kvstore.ReviewLoop{
 		ID:            "loop-1",
@@ -767,75 +905,58 @@ func TestHandleAIReview_MaxIterations(t *testing.T) {
 		Repo:          "repo",
 		PRNumber:      42,
 		Phase:         kvstore.ReviewPhaseAwaitingReview,
-		Iteration:     3, // At max.
+		Iteration:     1,
 		TriggerPostID: "trigger-1",
 		RootPostID:    "root-1",
 		ChannelID:     "ch-1",
 		UserID:        "user-1",
 	}
 
-	agentRecord := &kvstore.AgentRecord{
-		CursorAgentID:  "agent-1",
-		BotReplyPostID: "reply-1",
-		ChannelID:      "ch-1",
-	}
-
 	review := ghReview{
 		State: "commented",
-		Body:  "Actionable comments posted: 2",
+		Body:  "## Summary\n\nActionable comments posted: 1",
 	}
 	review.User.Login = "coderabbitai[bot]"
 
 	pr := ghPullRequest{}
+	pr.Head.SHA = "sha-1"
 
-	// Save loop with max_iterations phase.
+	mockCheckpointReviewFeedback(ghMock)
+	cursorMock.On("AddFollowup", mock.Anything, "agent-1", mock.Anything).
+		Return(nil, fmt.Errorf("agent is not running")).Once()
+
+	// Pre-dispatch checkpoint, then the errored loop.
 	store.On("SaveReviewLoop", mock.MatchedBy(func(l *kvstore.ReviewLoop) bool {
-		return l.Phase == kvstore.ReviewPhaseMaxIterations
-	})).Return(nil)
+		return l.Phase == kvstore.ReviewPhaseAwaitingReview
+	})).Return(nil).Once()
+	store.On("SaveReviewLoop", mock.MatchedBy(func(l *kvstore.ReviewLoop) bool {
+		return l.Phase == kvstore.ReviewPhaseError
+	})).Return(nil).Once()
+	mockInlineStatusUpdate(store, api, "agent-1", &kvstore.AgentRecord{CursorAgentID: "agent-1"})
 
-	// Inline status update + completion post.
-	mockInlineStatusUpdate(store, api, "agent-1", agentRecord)
 	api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
-		return post.RootId == "root-1" && hasAttachmentWithTitle(post, "AI review loop reached the maximum")
-	})).Return(&model.Post{Id: "notif-1"}, nil)
-
-	// Reaction swap.
+		return post.RootId == "root-1" && hasAttachmentWithTitle(post, "AI review loop failed.")
+	})).Return(&model.Post{Id: "notif-1"}, nil).Once()
 	api.On("RemoveReaction", mock.MatchedBy(func(r *model.Reaction) bool {
-		return r.EmojiName == "eyes"
-	})).Return(nil)
+		return r.PostId == "trigger-1" && r.EmojiName == "eyes"
+	})).Return(nil).Once()
 	api.On("AddReaction", mock.MatchedBy(func(r *model.Reaction) bool {
-		return r.EmojiName == "warning"
-	})).Return(nil, nil)
-
-	err := p.handleAIReview(loop, review, pr)
-	require.NoError(t, err)
-	store.AssertExpectations(t)
-}
-
-func TestHandleAIReview_NonCodeRabbitBot(t *testing.T) {
-	p, _, store, _ := setupReviewLoopTestPlugin(t)
-
-	loop := &kvstore.ReviewLoop{
-		ID:    "loop-1",
-		Phase: kvstore.ReviewPhaseAwaitingReview,
-	}
-
-	review := ghReview{
-		State: "commented",
-		Body:  "Some feedback",
-	}
-	review.User.Login = "copilot-pull-request-reviewer"
-
-	pr := ghPullRequest{}
+		return r.PostId == "trigger-1" && r.EmojiName == "x"
+	})).Return(nil, nil).Once()
 
-	err := p.handleAIReview(loop, review, pr)
+	err := p.handleAIReview(context.Background(), loop, review, pr)
 	require.NoError(t, err)
 
-	// Should NOT save or change phase.
-	store.AssertNotCalled(t, "SaveReviewLoop")
+	assert.Equal(t, kvstore.ReviewPhaseError, loop.Phase)
+	assert.Equal(t, 1, loop.Iteration)
+	last := loop.History[len(loop.History)-1]
+	assert.Equal(t, kvstore.ReviewPhaseError, last.Phase)
+	assert.Contains(t, last.Detail, "agent is not running")
+	store.AssertExpectations(t)
+	api.AssertExpectations(t)
 }
 
-func TestHandlePRSynchronize(t *testing.T) {
+func TestHandlePRSynchronize_SaveFailureEntersErrorPhase(t *testing.T) {
 	p, api, store, _ := setupReviewLoopTestPlugin(t)
 
 	loop := &kvstore.ReviewLoop{
@@ -843,114 +964,776 @@ func TestHandlePRSynchronize(t *testing.T) {
 		AgentRecordID: "agent-1",
 		Phase:         kvstore.ReviewPhaseCursorFixing,
 		Iteration:     2,
+		TriggerPostID: "trigger-1",
 		RootPostID:    "root-1",
 		ChannelID:     "ch-1",
 	}
 
-	agentRecord := &kvstore.AgentRecord{
-		CursorAgentID:  "agent-1",
-		BotReplyPostID: "reply-1",
-		ChannelID:      "ch-1",
-	}
-
 	pr := ghPullRequest{}
 	pr.Head.SHA = "newsha123"
 
-	store.On("SaveReviewLoop", mock.MatchedBy(func(l *kvstore.ReviewLoop) bool {
-		return l.Phase == kvstore.ReviewPhaseAwaitingReview &&
-			l.LastCommitSHA == "newsha123"
-	})).Return(nil)
+	store.On("SaveReviewLoop", mock.Anything).Return(fmt.Errorf("kv unavailable"))
+	mockInlineStatusUpdate(store, api, "agent-1", &kvstore.AgentRecord{CursorAgentID: "agent-1"})
+	api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		return hasAttachmentWithTitle(post, "AI review loop failed.")
+	})).Return(&model.Post{Id: "notif-1"}, nil).Once()
+	api.On("RemoveReaction", mock.Anything).Return(nil).Once()
+	api.On("AddReaction", mock.MatchedBy(func(r *model.Reaction) bool {
+		return r.PostId == "trigger-1" && r.EmojiName == "x"
+	})).Return(nil, nil).Once()
 
-	// Inline status update (replaces old thread notification).
-	mockInlineStatusUpdate(store, api, "agent-1", agentRecord)
+	err := p.handlePRSynchronize(context.Background(), loop, pr)
+	require.Error(t, err)
 
-	err := p.handlePRSynchronize(loop, pr)
-	require.NoError(t, err)
-	store.AssertExpectations(t)
+	assert.Equal(t, kvstore.ReviewPhaseError, loop.Phase)
+	assert.Contains(t, loop.History[len(loop.History)-1].Detail, "kv unavailable")
+	api.AssertExpectations(t)
 }
 
-func TestCollectReviewFeedbackPipeline_AwaitingReview_IncludesAIBotSources(t *testing.T) {
-	p, _, _, ghMock := setupReviewLoopTestPlugin(t)
-
-	loop := &kvstore.ReviewLoop{
-		ID:       "loop-1",
-		Owner:    "org",
-		Repo:     "repo",
-		PRNumber: 42,
-		Phase:    kvstore.ReviewPhaseAwaitingReview,
-	}
-
+func mockNitpickOnlyReviewFeedback(ghMock *mockGitHubClient) {
 	ghMock.On("ListReviewComments", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestComment{
 		{
+			ID:       github.Ptr(int64(501)),
 			User:     &github.User{Login: github.Ptr("coderabbitai[bot]")},
 			Path:     github.Ptr("main.go"),
 			Line:     github.Ptr(10),
-			Body:     github.Ptr("Prompt for AI Agents\nMissing error check"),
+			Body:     github.Ptr("_🧹 Nitpick (assertive)_\n\nPrompt for AI Agents\nRename variable x to count."),
 			CommitID: github.Ptr("abc123"),
 		},
 		{
-			User:     &github.User{Login: github.Ptr("human-reviewer")},
-			Path:     github.Ptr("main.go"),
-			Line:     github.Ptr(20),
-			Body:     github.Ptr("This is fine"),
-			CommitID: github.Ptr("abc123"),
-		},
-		{
-			User:     &github.User{Login: github.Ptr("copilot-pull-request-reviewer")},
+			ID:       github.Ptr(int64(502)),
+			User:     &github.User{Login: github.Ptr("coderabbitai[bot]")},
 			Path:     github.Ptr("util.go"),
-			Line:     github.Ptr(5),
-			Body:     github.Ptr("Consider using errors.Is()"),
+			Line:     github.Ptr(20),
+			Body:     github.Ptr("_⚠️ Potential issue_ | _🔵 Trivial_\n\nPrompt for AI Agents\nDrop the redundant blank line."),
 			CommitID: github.Ptr("abc123"),
 		},
 	}, nil)
+	ghMock.On("ListReviews", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestReview{}, nil)
+	ghMock.On("ListIssueComments", mock.Anything, "org", "repo", 42).Return([]*github.IssueComment{}, nil)
+}
 
-	ghMock.On("ListReviews", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestReview{
-		{
-			User: &github.User{Login: github.Ptr("coderabbitai[bot]")},
-			Body: github.Ptr("Actionable comments posted: 1\n\nPrompt for all review comments with AI agents\n```txt\nUse context.WithTimeout in API requests.\n```\n\nmeta"),
-		},
-	}, nil)
-	ghMock.On("ListIssueComments", mock.Anything, "org", "repo", 42).Return([]*github.IssueComment{
-		{
-			User: &github.User{Login: github.Ptr("copilot-pull-request-reviewer")},
-			Body: github.Ptr("Add unit tests for this edge case."),
-		},
-		{
-			User: &github.User{Login: github.Ptr("human-reviewer")},
-			Body: github.Ptr("Human-only note"),
-		},
-	}, nil)
+func TestDispatchReviewFeedback_AllNitsSkippedBelowMinSeverity(t *testing.T) {
+	p, _, _, ghMock := setupReviewLoopTestPlugin(t)
+	cursorMock := p.cursorClient.(*mockCursorClient)
+	p.configuration.ReviewMinimumSeverity = findingSeverityMinor
 
-	feedback, err := p.collectReviewFeedback(loop)
-	require.NoError(t, err)
+	loop := &kvstore.ReviewLoop{
+		ID:            "loop-1",
+		AgentRecordID: "agent-1",
+		Owner:         "org",
+		Repo:          "repo",
+		PRNumber:      42,
+		Phase:         kvstore.ReviewPhaseAwaitingReview,
+		Iteration:     1,
+	}
 
-	// Should include AI bot findings from inline and CodeRabbit review-body sources.
-	assert.Contains(t, feedback, "main.go:10")
-	assert.Contains(t, feedback, "Missing error check")
-	assert.Contains(t, feedback, "util.go:5")
-	assert.Contains(t, feedback, "errors.Is()")
-	assert.Contains(t, feedback, "context.WithTimeout")
-	assert.NotContains(t, feedback, "Add unit tests for this edge case")
+	pr := ghPullRequest{}
+	pr.Head.SHA = "abc123"
 
-	// Human review comments are ignored in awaiting_review.
-	assert.NotContains(t, feedback, "This is fine")
-	assert.NotContains(t, feedback, "Human-only note")
+	mockNitpickOnlyReviewFeedback(ghMock)
+
+	outcome, err := p.dispatchReviewFeedback(context.Background(), loop, pr)
+	require.NoError(t, err)
+	assert.True(t, outcome.Skipped)
+	assert.False(t, outcome.Dispatched)
+	assert.Equal(t, reviewDispatchModeSkippedSeverity, outcome.Mode)
+	assert.Equal(t, 2, outcome.Counts.Dispatchable)
+	assert.Zero(t, loop.LastFeedbackDispatchAt)
+	require.NotEmpty(t, loop.History)
+	assert.Contains(t, loop.History[len(loop.History)-1].Detail, "below minor severity")
+	cursorMock.AssertNotCalled(t, "AddFollowup", mock.Anything, mock.Anything, mock.Anything)
 }
 
-func TestCollectReviewFeedbackPipeline_FiltersByCommitSHA(t *testing.T) {
-	p, _, _, ghMock := setupReviewLoopTestPlugin(t)
+func TestDispatchReviewFeedback_AllNitsDispatchedWhenThresholdIncludesNits(t *testing.T) {
+	p, _, store, ghMock := setupReviewLoopTestPlugin(t)
+	cursorMock := p.cursorClient.(*mockCursorClient)
+
+	// Pre-dispatch checkpoint persistence.
+	store.On("SaveReviewLoop", mock.Anything).Return(nil)
+	p.configuration.ReviewMinimumSeverity = findingSeverityNit
 
 	loop := &kvstore.ReviewLoop{
 		ID:            "loop-1",
+		AgentRecordID: "agent-1",
 		Owner:         "org",
 		Repo:          "repo",
 		PRNumber:      42,
 		Phase:         kvstore.ReviewPhaseAwaitingReview,
-		LastCommitSHA: "newsha456",
+		Iteration:     1,
 	}
 
-	ghMock.On("ListReviewComments", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestComment{
-		{
+	pr := ghPullRequest{}
+	pr.Head.SHA = "abc123"
+
+	mockNitpickOnlyReviewFeedback(ghMock)
+	cursorMock.On("AddFollowup", mock.Anything, "agent-1", mock.MatchedBy(func(req cursor.FollowupRequest) bool {
+		return strings.Contains(req.Prompt.Text, "Rename variable x to count.")
+	})).Return(&cursor.FollowupResponse{ID: "agent-1"}, nil).Once()
+
+	outcome, err := p.dispatchReviewFeedback(context.Background(), loop, pr)
+	require.NoError(t, err)
+	assert.True(t, outcome.Dispatched)
+	assert.Equal(t, reviewDispatchModeDirect, outcome.Mode)
+	for _, finding := range loop.Findings {
+		assert.Equal(t, findingSeverityNit, finding.Severity)
+	}
+	cursorMock.AssertExpectations(t)
+}
+
+// mockMixedReviewerFeedback returns one Copilot finding collected before one
+// CodeRabbit finding so priority ordering is observable.
+func mockMixedReviewerFeedback(ghMock *mockGitHubClient) {
+	ghMock.On("ListReviewComments", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestComment{
+		{
+			ID:       github.Ptr(int64(601)),
+			User:     &github.User{Login: github.Ptr("copilot-pull-request-reviewer")},
+			Path:     github.Ptr("api.go"),
+			Line:     github.Ptr(30),
+			Body:     github.Ptr("Handle the error returned by json.Unmarshal."),
+			CommitID: github.Ptr("abc123"),
+		},
+		{
+			ID:       github.Ptr(int64(602)),
+			User:     &github.User{Login: github.Ptr("coderabbitai[bot]")},
+			Path:     github.Ptr("main.go"),
+			Line:     github.Ptr(10),
+			Body:     github.Ptr("_⚠️ Potential issue_\n\nPrompt for AI Agents\nClose the response body after reading it."),
+			CommitID: github.Ptr("abc123"),
+		},
+	}, nil)
+	ghMock.On("ListReviews", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestReview{}, nil)
+	ghMock.On("ListIssueComments", mock.Anything, "org", "repo", 42).Return([]*github.IssueComment{}, nil)
+}
+
+func TestDispatchReviewFeedback_OrdersFindingsByReviewerPriority(t *testing.T) {
+	p, _, store, ghMock := setupReviewLoopTestPlugin(t)
+	cursorMock := p.cursorClient.(*mockCursorClient)
+	p.configuration.AIReviewerPriority = "coderabbitai[bot], copilot-pull-request-reviewer"
+
+	store.On("SaveReviewLoop", mock.Anything).Return(nil)
+
+	loop := &kvstore.ReviewLoop{
+		ID:            "loop-1",
+		AgentRecordID: "agent-1",
+		Owner:         "org",
+		Repo:          "repo",
+		PRNumber:      42,
+		Phase:         kvstore.ReviewPhaseAwaitingReview,
+		Iteration:     1,
+	}
+
+	pr := ghPullRequest{}
+	pr.Head.SHA = "abc123"
+
+	mockMixedReviewerFeedback(ghMock)
+	var prompt string
+	cursorMock.On("AddFollowup", mock.Anything, "agent-1", mock.Anything).Run(func(args mock.Arguments) {
+		prompt = args.Get(2).(cursor.FollowupRequest).Prompt.Text
+	}).Return(&cursor.FollowupResponse{ID: "agent-1"}, nil).Once()
+
+	outcome, err := p.dispatchReviewFeedback(context.Background(), loop, pr)
+	require.NoError(t, err)
+	assert.True(t, outcome.Dispatched)
+
+	coderabbitIdx := strings.Index(prompt, "Close the response body after reading it.")
+	copilotIdx := strings.Index(prompt, "Handle the error returned by json.Unmarshal.")
+	require.NotEqual(t, -1, coderabbitIdx)
+	require.NotEqual(t, -1, copilotIdx)
+	assert.Less(t, coderabbitIdx, copilotIdx, "CodeRabbit findings should be listed before Copilot's")
+}
+
+func TestDispatchReviewFeedback_ExclusiveReviewerPriorityDefersLowerPriorityFindings(t *testing.T) {
+	p, _, store, ghMock := setupReviewLoopTestPlugin(t)
+	cursorMock := p.cursorClient.(*mockCursorClient)
+	p.configuration.AIReviewerPriority = "coderabbitai[bot],copilot-pull-request-reviewer"
+	p.configuration.AIReviewerPriorityExclusive = true
+
+	store.On("SaveReviewLoop", mock.Anything).Return(nil)
+
+	loop := &kvstore.ReviewLoop{
+		ID:            "loop-1",
+		AgentRecordID: "agent-1",
+		Owner:         "org",
+		Repo:          "repo",
+		PRNumber:      42,
+		Phase:         kvstore.ReviewPhaseAwaitingReview,
+		Iteration:     1,
+	}
+
+	pr := ghPullRequest{}
+	pr.Head.SHA = "abc123"
+
+	mockMixedReviewerFeedback(ghMock)
+	cursorMock.On("AddFollowup", mock.Anything, "agent-1", mock.MatchedBy(func(req cursor.FollowupRequest) bool {
+		return strings.Contains(req.Prompt.Text, "Close the response body after reading it.") &&
+			!strings.Contains(req.Prompt.Text, "Handle the error returned by json.Unmarshal.")
+	})).Return(&cursor.FollowupResponse{ID: "agent-1"}, nil).Once()
+
+	outcome, err := p.dispatchReviewFeedback(context.Background(), loop, pr)
+	require.NoError(t, err)
+	assert.True(t, outcome.Dispatched)
+	cursorMock.AssertExpectations(t)
+
+	// The deferred Copilot finding stays open for a later iteration.
+	var copilotFinding *kvstore.ReviewFinding
+	for i := range loop.Findings {
+		if loop.Findings[i].ReviewerLogin == "copilot-pull-request-reviewer" {
+			copilotFinding = &loop.Findings[i]
+		}
+	}
+	require.NotNil(t, copilotFinding)
+	assert.Equal(t, findingStatusOpen, copilotFinding.Status)
+}
+
+func TestPrioritizeFindingsByReviewer(t *testing.T) {
+	findings := []kvstore.ReviewFinding{
+		{Key: "a", ReviewerLogin: "human-bot"},
+		{Key: "b", ReviewerLogin: "copilot-pull-request-reviewer"},
+		{Key: "c", ReviewerLogin: "CodeRabbitAI[bot]"},
+		{Key: "d", ReviewerLogin: "copilot-pull-request-reviewer"},
+	}
+	priority := []string{"coderabbitai[bot]", "copilot-pull-request-reviewer"}
+
+	keys := func(fs []kvstore.ReviewFinding) []string {
+		out := make([]string, 0, len(fs))
+		for _, f := range fs {
+			out = append(out, f.Key)
+		}
+		return out
+	}
+
+	assert.Equal(t, []string{"c", "b", "d", "a"}, keys(prioritizeFindingsByReviewer(findings, priority, false)))
+	assert.Equal(t, []string{"c"}, keys(prioritizeFindingsByReviewer(findings, priority, true)))
+	assert.Equal(t, []string{"b"}, keys(prioritizeFindingsByReviewer(findings[:2], priority, true)))
+	assert.Equal(t, []string{"a", "b", "c", "d"}, keys(prioritizeFindingsByReviewer(findings, nil, true)))
+	assert.Equal(t, "a", findings[0].Key, "input slice must not be reordered")
+}
+
+func TestHandleAIReview_AllNitsBelowMinSeverity_DefersToHumanReview(t *testing.T) {
+	p, api, store, ghMock := setupReviewLoopTestPlugin(t)
+	cursorMock := p.cursorClient.(*mockCursorClient)
+	p.configuration.ReviewMinimumSeverity = findingSeverityMajor
+
+	loop := &kvstore.ReviewLoop{
+		ID:            "loop-1",
+		AgentRecordID: "agent-1",
+		Owner:         "org",
+		Repo:          "repo",
+		PRNumber:      42,
+		Phase:         kvstore.ReviewPhaseAwaitingReview,
+		Iteration:     1,
+		TriggerPostID: "trigger-1",
+		RootPostID:    "root-1",
+		ChannelID:     "ch-1",
+		UserID:        "user-1",
+	}
+
+	agentRecord := &kvstore.AgentRecord{
+		CursorAgentID:  "agent-1",
+		BotReplyPostID: "reply-1",
+		ChannelID:      "ch-1",
+	}
+
+	review := ghReview{
+		State: "commented",
+		Body:  "Actionable comments posted: 2",
+	}
+	review.User.Login = "coderabbitai[bot]"
+
+	pr := ghPullRequest{}
+	pr.Head.SHA = "abc123"
+
+	mockNitpickOnlyReviewFeedback(ghMock)
+	store.On("SaveReviewLoop", mock.MatchedBy(func(l *kvstore.ReviewLoop) bool {
+		return l.Phase == kvstore.ReviewPhaseHumanReview && l.Iteration == 1
+	})).Return(nil).Once()
+	mockInlineStatusUpdate(store, api, "agent-1", agentRecord)
+
+	err := p.handleAIReview(context.Background(), loop, review, pr)
+	require.NoError(t, err)
+	assert.Equal(t, kvstore.ReviewPhaseHumanReview, loop.Phase)
+	assert.Equal(t, 1, loop.Iteration)
+	cursorMock.AssertNotCalled(t, "AddFollowup", mock.Anything, mock.Anything, mock.Anything)
+	store.AssertExpectations(t)
+}
+
+func TestDetectFindingSeverity(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		expected string
+	}{
+		{name: "nitpick category", raw: "_🧹 Nitpick (assertive)_\n\nbody", expected: findingSeverityNit},
+		{name: "refactor suggestion category", raw: "_🛠️ Refactor suggestion_\n\nbody", expected: findingSeverityMinor},
+		{name: "potential issue category", raw: "_⚠️ Potential issue_\n\nbody", expected: findingSeverityMajor},
+		{name: "explicit level wins over category", raw: "_⚠️ Potential issue_ | _🟡 Minor_\n\nbody", expected: findingSeverityMinor},
+		{name: "critical level", raw: "\n_⚠️ Potential issue_ | _🔴 Critical_", expected: findingSeverityCritical},
+		{name: "label only on first line", raw: "Please fix this.\n_🔴 Critical_", expected: ""},
+		{name: "plain human comment", raw: "This is a major problem.", expected: ""},
+		{name: "empty", raw: "", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, detectFindingSeverity(tt.raw))
+		})
+	}
+}
+
+func newCheckpointTestLoop() *kvstore.ReviewLoop {
+	return &kvstore.ReviewLoop{
+		ID:                    "loop-checkpoint",
+		AgentRecordID:         "agent-1",
+		Owner:                 "org",
+		Repo:                  "repo",
+		PRNumber:              42,
+		Phase:                 kvstore.ReviewPhaseAwaitingReview,
+		Iteration:             1,
+		PendingDispatchID:     "loop-checkpoint-1700000000000",
+		PendingDispatchAt:     1700000000000,
+		PendingDispatchSHA:    "sha-1",
+		PendingDispatchDigest: "digest-1",
+	}
+}
+
+func mockCheckpointReviewFeedback(ghMock *mockGitHubClient) {
+	ghMock.On("ListReviewComments", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestComment{
+		{
+			User:     &github.User{Login: github.Ptr("coderabbitai[bot]")},
+			Path:     github.Ptr("server/api.go"),
+			Line:     github.Ptr(14),
+			Body:     github.Ptr("Prompt for AI Agents\nAdd a nil guard before dereferencing."),
+			CommitID: github.Ptr("sha-1"),
+		},
+	}, nil)
+	ghMock.On("ListReviews", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestReview{}, nil)
+	ghMock.On("ListIssueComments", mock.Anything, "org", "repo", 42).Return([]*github.IssueComment{}, nil)
+}
+
+func TestDispatchReviewFeedback_PendingCheckpointDelivered_PreventsDuplicateDispatch(t *testing.T) {
+	p, _, _, ghMock := setupReviewLoopTestPlugin(t)
+	cursorMock := p.cursorClient.(*mockCursorClient)
+
+	loop := newCheckpointTestLoop()
+	pr := ghPullRequest{}
+	pr.Head.SHA = "sha-1"
+
+	mockCheckpointReviewFeedback(ghMock)
+	cursorMock.On("GetConversation", mock.Anything, "agent-1").Return(&cursor.Conversation{
+		Messages: []cursor.Message{
+			{Type: "user_message", Text: "Apply the latest pull request review feedback...\n\ndispatch_checkpoint: loop-checkpoint-1700000000000"},
+			{Type: "assistant_message", Text: "Working on it."},
+		},
+	}, nil)
+
+	outcome, err := p.dispatchReviewFeedback(context.Background(), loop, pr)
+	require.NoError(t, err)
+	assert.True(t, outcome.Dispatched)
+	assert.Equal(t, reviewDispatchModeRecovered, outcome.Mode)
+	assert.Equal(t, int64(1700000000000), loop.LastFeedbackDispatchAt)
+	assert.Equal(t, "sha-1", loop.LastFeedbackDispatchSHA)
+	assert.Equal(t, "digest-1", loop.LastFeedbackDigest)
+	assert.Empty(t, loop.PendingDispatchID)
+	assert.Zero(t, loop.PendingDispatchAt)
+	cursorMock.AssertNotCalled(t, "AddFollowup", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDispatchReviewFeedback_PendingCheckpointNotDelivered_Dispatches(t *testing.T) {
+	p, _, store, ghMock := setupReviewLoopTestPlugin(t)
+	cursorMock := p.cursorClient.(*mockCursorClient)
+
+	loop := newCheckpointTestLoop()
+	pr := ghPullRequest{}
+	pr.Head.SHA = "sha-1"
+
+	mockCheckpointReviewFeedback(ghMock)
+	cursorMock.On("GetConversation", mock.Anything, "agent-1").Return(&cursor.Conversation{
+		Messages: []cursor.Message{
+			{Type: "user_message", Text: "Original task prompt"},
+		},
+	}, nil)
+	var checkpointHash string
+	store.On("SaveReviewLoop", mock.MatchedBy(func(l *kvstore.ReviewLoop) bool {
+		checkpointHash = l.PendingDispatchPromptHash
+		return l.PendingDispatchID != "" && l.PendingDispatchID != "loop-checkpoint-1700000000000"
+	})).Return(nil).Once()
+	var prompt string
+	cursorMock.On("AddFollowup", mock.Anything, "agent-1", mock.MatchedBy(func(req cursor.FollowupRequest) bool {
+		prompt = req.Prompt.Text
+		return !strings.Contains(req.Prompt.Text, "dispatch_checkpoint")
+	})).Return(&cursor.FollowupResponse{ID: "agent-1"}, nil).Once()
+
+	outcome, err := p.dispatchReviewFeedback(context.Background(), loop, pr)
+	require.NoError(t, err)
+	assert.True(t, outcome.Dispatched)
+	assert.Equal(t, reviewDispatchModeDirect, outcome.Mode)
+	assert.Equal(t, "sha-1", loop.LastFeedbackDispatchSHA)
+	assert.Empty(t, loop.PendingDispatchID)
+	assert.Equal(t, dispatchPromptHash(prompt), checkpointHash)
+	cursorMock.AssertExpectations(t)
+	store.AssertExpectations(t)
+}
+
+func TestDispatchReviewFeedback_PendingCheckpointMatchedByPromptHash(t *testing.T) {
+	p, _, _, ghMock := setupReviewLoopTestPlugin(t)
+	cursorMock := p.cursorClient.(*mockCursorClient)
+
+	loop := newCheckpointTestLoop()
+	loop.PendingDispatchPromptHash = dispatchPromptHash("Apply the latest pull request review feedback.")
+	pr := ghPullRequest{}
+	pr.Head.SHA = "sha-1"
+
+	mockCheckpointReviewFeedback(ghMock)
+	cursorMock.On("GetConversation", mock.Anything, "agent-1").Return(&cursor.Conversation{
+		Messages: []cursor.Message{
+			{Type: "user_message", Text: "Original task prompt"},
+			{Type: "user_message", Text: "Apply the latest pull request review feedback.\r\n"},
+		},
+	}, nil)
+
+	outcome, err := p.dispatchReviewFeedback(context.Background(), loop, pr)
+	require.NoError(t, err)
+	assert.Equal(t, reviewDispatchModeRecovered, outcome.Mode)
+	assert.Equal(t, "digest-1", loop.LastFeedbackDigest)
+	assert.Empty(t, loop.PendingDispatchPromptHash)
+	cursorMock.AssertNotCalled(t, "AddFollowup", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDispatchReviewFeedback_PendingCheckpointUnconfirmed_KeepsCheckpoint(t *testing.T) {
+	p, api, _, ghMock := setupReviewLoopTestPlugin(t)
+	api.On("LogWarn", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	cursorMock := p.cursorClient.(*mockCursorClient)
+
+	loop := newCheckpointTestLoop()
+	pr := ghPullRequest{}
+	pr.Head.SHA = "sha-1"
+
+	mockCheckpointReviewFeedback(ghMock)
+	cursorMock.On("GetConversation", mock.Anything, "agent-1").Return(nil, assert.AnError)
+
+	outcome, err := p.dispatchReviewFeedback(context.Background(), loop, pr)
+	require.NoError(t, err)
+	assert.True(t, outcome.Skipped)
+	assert.Equal(t, reviewDispatchModeUnconfirmed, outcome.Mode)
+	assert.Equal(t, "loop-checkpoint-1700000000000", loop.PendingDispatchID)
+	assert.Equal(t, int64(1700000000000), loop.PendingDispatchAt)
+	assert.Empty(t, loop.LastFeedbackDigest)
+	cursorMock.AssertNotCalled(t, "AddFollowup", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHandleAIReview_MaxIterations(t *testing.T) {
+	p, api, store, _ := setupReviewLoopTestPlugin(t)
+	p.configuration.MaxReviewIterations = 3
+
+	loop := &kvstore.ReviewLoop{
+		ID:            "loop-1",
+		AgentRecordID: "agent-1",
+		Owner:         "org",
+		Repo:          "repo",
+		PRNumber:      42,
+		Phase:         kvstore.ReviewPhaseAwaitingReview,
+		Iteration:     3, // At max.
+		TriggerPostID: "trigger-1",
+		RootPostID:    "root-1",
+		ChannelID:     "ch-1",
+		UserID:        "user-1",
+	}
+
+	agentRecord := &kvstore.AgentRecord{
+		CursorAgentID:  "agent-1",
+		BotReplyPostID: "reply-1",
+		ChannelID:      "ch-1",
+	}
+
+	review := ghReview{
+		State: "commented",
+		Body:  "Actionable comments posted: 2",
+	}
+	review.User.Login = "coderabbitai[bot]"
+
+	pr := ghPullRequest{}
+
+	// Save loop with max_iterations phase.
+	store.On("SaveReviewLoop", mock.MatchedBy(func(l *kvstore.ReviewLoop) bool {
+		return l.Phase == kvstore.ReviewPhaseMaxIterations
+	})).Return(nil)
+
+	// Inline status update + completion post.
+	mockInlineStatusUpdate(store, api, "agent-1", agentRecord)
+	api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		return post.RootId == "root-1" && hasAttachmentWithTitle(post, "AI review loop reached the maximum")
+	})).Return(&model.Post{Id: "notif-1"}, nil)
+
+	// Reaction swap.
+	api.On("RemoveReaction", mock.MatchedBy(func(r *model.Reaction) bool {
+		return r.EmojiName == "eyes"
+	})).Return(nil)
+	api.On("AddReaction", mock.MatchedBy(func(r *model.Reaction) bool {
+		return r.EmojiName == "warning"
+	})).Return(nil, nil)
+
+	err := p.handleAIReview(context.Background(), loop, review, pr)
+	require.NoError(t, err)
+	store.AssertExpectations(t)
+}
+
+func TestMaybeWarnIterationThreshold_FiresOnceAtThreshold(t *testing.T) {
+	p, api, _, _ := setupReviewLoopTestPlugin(t)
+	p.configuration.MaxReviewIterations = 5
+	p.configuration.ReviewIterationWarning = 3
+
+	loop := &kvstore.ReviewLoop{
+		ID:         "loop-1",
+		PRURL:      "https://github.com/org/repo/pull/42",
+		Phase:      kvstore.ReviewPhaseCursorFixing,
+		Iteration:  2,
+		RootPostID: "root-1",
+		ChannelID:  "ch-1",
+	}
+
+	api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		return post.RootId == "root-1" &&
+			hasAttachmentWithTitle(post, "iteration 3 of 5") &&
+			hasAttachmentWithColor(post, attachments.ColorYellow)
+	})).Return(&model.Post{Id: "warn-1"}, nil).Once()
+
+	p.maybeWarnIterationThreshold(loop)
+	assert.False(t, loop.IterationWarningSent)
+	api.AssertNotCalled(t, "CreatePost", mock.Anything)
+
+	loop.Iteration = 3
+	p.maybeWarnIterationThreshold(loop)
+	assert.True(t, loop.IterationWarningSent)
+	assert.Contains(t, loop.History[len(loop.History)-1].Detail, "3 of 5")
+
+	loop.Iteration = 4
+	p.maybeWarnIterationThreshold(loop)
+	api.AssertNumberOfCalls(t, "CreatePost", 1)
+}
+
+func TestMaybeWarnIterationThreshold_DisabledByDefault(t *testing.T) {
+	p, api, _, _ := setupReviewLoopTestPlugin(t)
+
+	loop := &kvstore.ReviewLoop{
+		ID:         "loop-1",
+		Phase:      kvstore.ReviewPhaseCursorFixing,
+		Iteration:  4,
+		RootPostID: "root-1",
+	}
+
+	p.maybeWarnIterationThreshold(loop)
+	assert.False(t, loop.IterationWarningSent)
+	assert.Empty(t, loop.History)
+	api.AssertNotCalled(t, "CreatePost", mock.Anything)
+}
+
+func TestHandleAIReview_NonCodeRabbitBot(t *testing.T) {
+	p, _, store, _ := setupReviewLoopTestPlugin(t)
+
+	loop := &kvstore.ReviewLoop{
+		ID:    "loop-1",
+		Phase: kvstore.ReviewPhaseAwaitingReview,
+	}
+
+	review := ghReview{
+		State: "commented",
+		Body:  "Some feedback",
+	}
+	review.User.Login = "copilot-pull-request-reviewer"
+
+	pr := ghPullRequest{}
+
+	err := p.handleAIReview(context.Background(), loop, review, pr)
+	require.NoError(t, err)
+
+	// Should NOT save or change phase.
+	store.AssertNotCalled(t, "SaveReviewLoop")
+}
+
+func TestHandleAIReview_InlineOnlyReviewFromOtherBotDispatches(t *testing.T) {
+	p, api, store, ghMock := setupReviewLoopTestPlugin(t)
+	cursorMock := p.cursorClient.(*mockCursorClient)
+	p.configuration.ReviewLoopDispatchInlineOnlyReviews = true
+
+	loop := &kvstore.ReviewLoop{
+		ID:            "loop-1",
+		AgentRecordID: "agent-1",
+		Owner:         "org",
+		Repo:          "repo",
+		PRNumber:      42,
+		Phase:         kvstore.ReviewPhaseAwaitingReview,
+		Iteration:     1,
+	}
+
+	review := ghReview{State: "commented"}
+	review.User.Login = "copilot-pull-request-reviewer"
+
+	pr := ghPullRequest{}
+	pr.Head.SHA = "sha-1"
+
+	ghMock.On("ListReviewComments", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestComment{
+		{
+			User:     &github.User{Login: github.Ptr("copilot-pull-request-reviewer")},
+			Path:     github.Ptr("server/api.go"),
+			Line:     github.Ptr(14),
+			Body:     github.Ptr("This error is silently dropped."),
+			CommitID: github.Ptr("sha-1"),
+		},
+	}, nil)
+	ghMock.On("ListReviews", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestReview{}, nil)
+	ghMock.On("ListIssueComments", mock.Anything, "org", "repo", 42).Return([]*github.IssueComment{}, nil)
+
+	cursorMock.On("AddFollowup", mock.Anything, "agent-1", mock.MatchedBy(func(req cursor.FollowupRequest) bool {
+		return strings.Contains(req.Prompt.Text, "This error is silently dropped.")
+	})).Return(&cursor.FollowupResponse{ID: "agent-1"}, nil).Once()
+	store.On("SaveReviewLoop", mock.Anything).Return(nil)
+	mockInlineStatusUpdate(store, api, "agent-1", &kvstore.AgentRecord{CursorAgentID: "agent-1"})
+
+	err := p.handleAIReview(context.Background(), loop, review, pr)
+	require.NoError(t, err)
+
+	cursorMock.AssertExpectations(t)
+	assert.Equal(t, kvstore.ReviewPhaseCursorFixing, loop.Phase)
+	assert.Equal(t, 2, loop.Iteration)
+	require.Len(t, loop.Findings, 1)
+}
+
+func TestHandleAIReview_InlineOnlyReviewFromOtherBotIgnoredWhenDisabled(t *testing.T) {
+	p, _, store, _ := setupReviewLoopTestPlugin(t)
+	p.configuration.ReviewLoopDispatchInlineOnlyReviews = false
+
+	loop := &kvstore.ReviewLoop{
+		ID:    "loop-1",
+		Phase: kvstore.ReviewPhaseAwaitingReview,
+	}
+
+	review := ghReview{State: "commented"}
+	review.User.Login = "copilot-pull-request-reviewer"
+
+	err := p.handleAIReview(context.Background(), loop, review, ghPullRequest{})
+	require.NoError(t, err)
+
+	store.AssertNotCalled(t, "SaveReviewLoop")
+	assert.Equal(t, kvstore.ReviewPhaseAwaitingReview, loop.Phase)
+}
+
+func TestHandlePRSynchronize(t *testing.T) {
+	p, api, store, _ := setupReviewLoopTestPlugin(t)
+
+	loop := &kvstore.ReviewLoop{
+		ID:            "loop-1",
+		AgentRecordID: "agent-1",
+		Phase:         kvstore.ReviewPhaseCursorFixing,
+		Iteration:     2,
+		RootPostID:    "root-1",
+		ChannelID:     "ch-1",
+	}
+
+	agentRecord := &kvstore.AgentRecord{
+		CursorAgentID:  "agent-1",
+		BotReplyPostID: "reply-1",
+		ChannelID:      "ch-1",
+	}
+
+	pr := ghPullRequest{}
+	pr.Head.SHA = "newsha123"
+
+	store.On("SaveReviewLoop", mock.MatchedBy(func(l *kvstore.ReviewLoop) bool {
+		return l.Phase == kvstore.ReviewPhaseAwaitingReview &&
+			l.LastCommitSHA == "newsha123"
+	})).Return(nil)
+
+	// Inline status update (replaces old thread notification).
+	mockInlineStatusUpdate(store, api, "agent-1", agentRecord)
+
+	err := p.handlePRSynchronize(context.Background(), loop, pr)
+	require.NoError(t, err)
+	store.AssertExpectations(t)
+}
+
+func TestCollectReviewFeedbackPipeline_AwaitingReview_IncludesAIBotSources(t *testing.T) {
+	p, _, _, ghMock := setupReviewLoopTestPlugin(t)
+
+	loop := &kvstore.ReviewLoop{
+		ID:       "loop-1",
+		Owner:    "org",
+		Repo:     "repo",
+		PRNumber: 42,
+		Phase:    kvstore.ReviewPhaseAwaitingReview,
+	}
+
+	ghMock.On("ListReviewComments", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestComment{
+		{
+			User:     &github.User{Login: github.Ptr("coderabbitai[bot]")},
+			Path:     github.Ptr("main.go"),
+			Line:     github.Ptr(10),
+			Body:     github.Ptr("Prompt for AI Agents\nMissing error check"),
+			CommitID: github.Ptr("abc123"),
+		},
+		{
+			User:     &github.User{Login: github.Ptr("human-reviewer")},
+			Path:     github.Ptr("main.go"),
+			Line:     github.Ptr(20),
+			Body:     github.Ptr("This is fine"),
+			CommitID: github.Ptr("abc123"),
+		},
+		{
+			User:     &github.User{Login: github.Ptr("copilot-pull-request-reviewer")},
+			Path:     github.Ptr("util.go"),
+			Line:     github.Ptr(5),
+			Body:     github.Ptr("Consider using errors.Is()"),
+			CommitID: github.Ptr("abc123"),
+		},
+	}, nil)
+
+	ghMock.On("ListReviews", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestReview{
+		{
+			User: &github.User{Login: github.Ptr("coderabbitai[bot]")},
+			Body: github.Ptr("Actionable comments posted: 1\n\nPrompt for all review comments with AI agents\n```txt\nUse context.WithTimeout in API requests.\n```\n\nmeta"),
+		},
+	}, nil)
+	ghMock.On("ListIssueComments", mock.Anything, "org", "repo", 42).Return([]*github.IssueComment{
+		{
+			User: &github.User{Login: github.Ptr("copilot-pull-request-reviewer")},
+			Body: github.Ptr("Add unit tests for this edge case."),
+		},
+		{
+			User: &github.User{Login: github.Ptr("human-reviewer")},
+			Body: github.Ptr("Human-only note"),
+		},
+	}, nil)
+
+	feedback, err := p.collectReviewFeedback(loop)
+	require.NoError(t, err)
+
+	// Should include AI bot findings from inline and CodeRabbit review-body sources.
+	assert.Contains(t, feedback, "main.go:10")
+	assert.Contains(t, feedback, "Missing error check")
+	assert.Contains(t, feedback, "util.go:5")
+	assert.Contains(t, feedback, "errors.Is()")
+	assert.Contains(t, feedback, "context.WithTimeout")
+	assert.NotContains(t, feedback, "Add unit tests for this edge case")
+
+	// Human review comments are ignored in awaiting_review.
+	assert.NotContains(t, feedback, "This is fine")
+	assert.NotContains(t, feedback, "Human-only note")
+}
+
+func TestCollectReviewFeedbackPipeline_FiltersByCommitSHA(t *testing.T) {
+	p, _, _, ghMock := setupReviewLoopTestPlugin(t)
+
+	loop := &kvstore.ReviewLoop{
+		ID:            "loop-1",
+		Owner:         "org",
+		Repo:          "repo",
+		PRNumber:      42,
+		Phase:         kvstore.ReviewPhaseAwaitingReview,
+		LastCommitSHA: "newsha456",
+	}
+
+	ghMock.On("ListReviewComments", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestComment{
+		{
 			User:     &github.User{Login: github.Ptr("coderabbitai[bot]")},
 			Path:     github.Ptr("old.go"),
 			Line:     github.Ptr(1),
@@ -1095,6 +1878,26 @@ func TestCollectReviewFeedbackBundle_LogsDroppedCandidatesWithReasonAndRoute(t *
 				ghMock.On("ListIssueComments", mock.Anything, "org", "repo", 42).Return([]*github.IssueComment{}, nil)
 			},
 		},
+		{
+			name:           "coderabbit chat reply dropped",
+			expectedRoute:  reviewerExtractionRouteCodeRabbit,
+			expectedReason: reviewerExtractionDropReasonCodeRabbitChat,
+			setupGitHub: func(ghMock *mockGitHubClient) {
+				ghMock.On("ListReviewComments", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestComment{
+					{
+						ID:        github.Ptr(int64(102)),
+						InReplyTo: github.Ptr(int64(101)),
+						User:      &github.User{Login: github.Ptr("coderabbitai[bot]")},
+						Path:      github.Ptr("server/api.go"),
+						Line:      github.Ptr(11),
+						Body:      github.Ptr("@alice The retry is handled by the caller, so no change is needed here."),
+						CommitID:  github.Ptr("sha-101"),
+					},
+				}, nil)
+				ghMock.On("ListReviews", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestReview{}, nil)
+				ghMock.On("ListIssueComments", mock.Anything, "org", "repo", 42).Return([]*github.IssueComment{}, nil)
+			},
+		},
 		{
 			name:           "non-coderabbit non-inline source dropped",
 			expectedRoute:  reviewerExtractionRouteNonCodeRabbit,
@@ -1352,9 +2155,42 @@ func TestExtractCandidateActionableText_CodeRabbit_MarkerMissingReturnsEmpty(t *
 
 	actionable, route, dropReason := extractCandidateActionableText(candidate)
 
-	assert.Empty(t, actionable)
+	assert.Empty(t, actionable)
+	assert.Equal(t, reviewerExtractionRouteCodeRabbit, route)
+	assert.Equal(t, reviewerExtractionDropReasonCodeRabbitMarkersMissing, dropReason)
+}
+
+func TestExtractCandidateActionableText_CodeRabbit_ChatReplyDropped(t *testing.T) {
+	candidate := reviewFeedbackCandidate{
+		ReviewerLogin:  "coderabbitai[bot]",
+		SourceType:     "review_comment",
+		InReplyToID:    101,
+		NormalizedText: "@alice Good question! The nil check is there because buildPayload can be called before the config loads.",
+	}
+
+	actionable, route, dropReason := extractCandidateActionableText(candidate)
+
+	assert.Empty(t, actionable)
+	assert.Equal(t, reviewerExtractionRouteCodeRabbit, route)
+	assert.Equal(t, reviewerExtractionDropReasonCodeRabbitChat, dropReason)
+}
+
+func TestExtractCandidateActionableText_CodeRabbit_PromptMarkedReplyStillActionable(t *testing.T) {
+	candidate := reviewFeedbackCandidate{
+		ReviewerLogin: "coderabbitai[bot]",
+		SourceType:    "review_comment",
+		InReplyToID:   101,
+		NormalizedText: `@alice You're right, this needs a fix.
+
+Prompt for AI Agents
+Guard against a nil config in buildPayload.`,
+	}
+
+	actionable, route, dropReason := extractCandidateActionableText(candidate)
+
+	assert.Equal(t, "Guard against a nil config in buildPayload.", actionable)
 	assert.Equal(t, reviewerExtractionRouteCodeRabbit, route)
-	assert.Equal(t, reviewerExtractionDropReasonCodeRabbitMarkersMissing, dropReason)
+	assert.Empty(t, dropReason)
 }
 
 func TestExtractCandidateActionableText_CodeRabbit_StripsVerifyBoilerplateAfterExtraction(t *testing.T) {
@@ -1510,55 +2346,548 @@ func TestSummarizeReviewFeedbackTelemetry(t *testing.T) {
 func TestClassifyFeedback_NewRepeatedResolved(t *testing.T) {
 	loop := &kvstore.ReviewLoop{
 		Phase:     kvstore.ReviewPhaseAwaitingReview,
-		Iteration: 3,
+		Iteration: 3,
+		Findings: []kvstore.ReviewFinding{
+			{
+				Key:            buildFindingKey(reviewFeedbackCandidate{Path: "server/api.go", Line: 12, ActionableText: "add nil check"}),
+				Status:         findingStatusOpen,
+				ReviewerType:   reviewerTypeAIBot,
+				Path:           "server/api.go",
+				Line:           12,
+				ActionableText: "add nil check",
+			},
+			{
+				Key:            buildFindingKey(reviewFeedbackCandidate{Path: "server/webhook.go", Line: 88, ActionableText: "remove dead code"}),
+				Status:         findingStatusOpen,
+				ReviewerType:   reviewerTypeAIBot,
+				Path:           "server/webhook.go",
+				Line:           88,
+				ActionableText: "remove dead code",
+			},
+		},
+	}
+
+	candidates := []reviewFeedbackCandidate{
+		{
+			SourceType:     "review_comment",
+			ReviewerType:   reviewerTypeAIBot,
+			Path:           "server/api.go",
+			Line:           12,
+			RawText:        "add nil check",
+			ActionableText: "add nil check",
+		},
+		{
+			SourceType:     "review_comment",
+			ReviewerType:   reviewerTypeAIBot,
+			Path:           "server/poller.go",
+			Line:           99,
+			RawText:        "handle timeout properly",
+			ActionableText: "handle timeout properly",
+		},
+	}
+
+	classification := classifyFeedback(loop, candidates, 1700000000000)
+	require.Len(t, classification.New, 1)
+	require.Len(t, classification.Repeated, 1)
+	require.Len(t, classification.Resolved, 1)
+	require.Len(t, classification.Dispatchable, 2)
+
+	assert.Equal(t, "handle timeout properly", classification.New[0].ActionableText)
+	assert.Equal(t, "add nil check", classification.Repeated[0].ActionableText)
+	assert.Equal(t, "remove dead code", classification.Resolved[0].ActionableText)
+	assert.Equal(t, findingResolvedByAbsence, classification.Resolved[0].ResolvedBy)
+	assert.Equal(t, findingShortID(classification.New[0].Key), classification.New[0].ShortID)
+}
+
+func TestResolveFindingsByReference_ExplicitAndAbsenceResolution(t *testing.T) {
+	referencedKey := buildFindingKey(reviewFeedbackCandidate{Path: "server/api.go", Line: 12, ActionableText: "add nil check"})
+	absentKey := buildFindingKey(reviewFeedbackCandidate{Path: "server/webhook.go", Line: 88, ActionableText: "remove dead code"})
+	repeatedKey := buildFindingKey(reviewFeedbackCandidate{Path: "server/poller.go", Line: 5, ActionableText: "close the ticker"})
+
+	loop := &kvstore.ReviewLoop{
+		Phase:     kvstore.ReviewPhaseAwaitingReview,
+		Iteration: 2,
+		Findings: []kvstore.ReviewFinding{
+			{Key: referencedKey, Status: findingStatusOpen, ReviewerType: reviewerTypeAIBot, Path: "server/api.go", Line: 12, ActionableText: "add nil check"},
+			{Key: absentKey, Status: findingStatusOpen, ReviewerType: reviewerTypeAIBot, Path: "server/webhook.go", Line: 88, ActionableText: "remove dead code"},
+			{Key: repeatedKey, Status: findingStatusOpen, ReviewerType: reviewerTypeAIBot, Path: "server/poller.go", Line: 5, ActionableText: "close the ticker"},
+		},
+	}
+
+	resolved := resolveFindingsByReference(loop, []string{
+		"Add nil guard\n\nFixes " + strings.ToUpper(findingShortID(referencedKey)),
+		"unrelated RF-zzzzzzzz text",
+	}, 1700000000000)
+	require.Len(t, resolved, 1)
+	assert.Equal(t, referencedKey, resolved[0].Key)
+	assert.Equal(t, findingResolvedByReference, loop.Findings[0].ResolvedBy)
+	assert.Equal(t, findingStatusResolved, loop.Findings[0].Status)
+	assert.Equal(t, findingStatusOpen, loop.Findings[1].Status)
+
+	// The next review no longer mentions the webhook finding; it resolves by absence
+	// while the referenced finding keeps its explicit resolution.
+	classification := classifyFeedback(loop, []reviewFeedbackCandidate{
+		{
+			SourceType:     "review_comment",
+			ReviewerType:   reviewerTypeAIBot,
+			Path:           "server/poller.go",
+			Line:           5,
+			RawText:        "close the ticker",
+			ActionableText: "close the ticker",
+		},
+	}, 1700000001000)
+	require.Len(t, classification.Resolved, 1)
+	assert.Equal(t, absentKey, classification.Resolved[0].Key)
+	assert.Equal(t, findingResolvedByAbsence, classification.Resolved[0].ResolvedBy)
+	require.Len(t, classification.Repeated, 1)
+	assert.Equal(t, repeatedKey, classification.Repeated[0].Key)
+	assert.Equal(t, findingResolvedByReference, loop.Findings[0].ResolvedBy)
+}
+
+func TestHandlePRSynchronize_ReRequestsAIReviewersWhenEnabled(t *testing.T) {
+	p, api, store, ghMock := setupReviewLoopTestPlugin(t)
+	p.configuration.ReviewLoopReRequestOnSynchronize = true
+
+	loop := &kvstore.ReviewLoop{
+		ID:            "loop-1",
+		AgentRecordID: "agent-1",
+		Owner:         "org",
+		Repo:          "repo",
+		PRNumber:      42,
+		PRURL:         "https://github.com/org/repo/pull/42",
+		Phase:         kvstore.ReviewPhaseCursorFixing,
+		Iteration:     2,
+	}
+
+	pr := ghPullRequest{}
+	pr.Head.SHA = "newsha123"
+
+	store.On("SaveReviewLoop", mock.Anything).Return(nil)
+	mockInlineStatusUpdate(store, api, "agent-1", &kvstore.AgentRecord{CursorAgentID: "agent-1"})
+	ghMock.On("RequestReviewers", mock.Anything, "org", "repo", 42, github.ReviewersRequest{
+		Reviewers: []string{"coderabbitai[bot]", "copilot-pull-request-reviewer"},
+	}).Return(fmt.Errorf("422 review already requested")).Once()
+
+	err := p.handlePRSynchronize(context.Background(), loop, pr)
+	require.NoError(t, err) // Non-fatal: the re-request is a best-effort nudge.
+	assert.Equal(t, kvstore.ReviewPhaseAwaitingReview, loop.Phase)
+	ghMock.AssertExpectations(t)
+}
+
+func TestHandlePRSynchronize_DoesNotReRequestAIReviewersWhenDisabled(t *testing.T) {
+	p, api, store, ghMock := setupReviewLoopTestPlugin(t)
+
+	loop := &kvstore.ReviewLoop{
+		ID:            "loop-1",
+		AgentRecordID: "agent-1",
+		Owner:         "org",
+		Repo:          "repo",
+		PRNumber:      42,
+		Phase:         kvstore.ReviewPhaseCursorFixing,
+		Iteration:     2,
+	}
+
+	pr := ghPullRequest{}
+	pr.Head.SHA = "newsha123"
+
+	store.On("SaveReviewLoop", mock.Anything).Return(nil)
+	mockInlineStatusUpdate(store, api, "agent-1", &kvstore.AgentRecord{CursorAgentID: "agent-1"})
+
+	err := p.handlePRSynchronize(context.Background(), loop, pr)
+	require.NoError(t, err)
+	ghMock.AssertNotCalled(t, "RequestReviewers", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHandlePRSynchronize_ResolvesReferencedFindings(t *testing.T) {
+	p, api, store, ghMock := setupReviewLoopTestPlugin(t)
+
+	referencedKey := buildFindingKey(reviewFeedbackCandidate{Path: "server/api.go", Line: 12, ActionableText: "add nil check"})
+	openKey := buildFindingKey(reviewFeedbackCandidate{Path: "server/webhook.go", Line: 88, ActionableText: "remove dead code"})
+
+	loop := &kvstore.ReviewLoop{
+		ID:            "loop-1",
+		AgentRecordID: "agent-1",
+		Owner:         "org",
+		Repo:          "repo",
+		PRNumber:      42,
+		Phase:         kvstore.ReviewPhaseCursorFixing,
+		Iteration:     1,
+		Findings: []kvstore.ReviewFinding{
+			{Key: referencedKey, Status: findingStatusOpen, Path: "server/api.go", Line: 12},
+			{Key: openKey, Status: findingStatusOpen, Path: "server/webhook.go", Line: 88},
+		},
+	}
+
+	agentRecord := &kvstore.AgentRecord{
+		CursorAgentID:  "agent-1",
+		BotReplyPostID: "reply-1",
+		ChannelID:      "ch-1",
+	}
+
+	pr := ghPullRequest{}
+	pr.Head.SHA = "newsha123"
+
+	ghMock.On("ListPullRequestCommits", mock.Anything, "org", "repo", 42).Return([]*github.RepositoryCommit{
+		{SHA: github.Ptr("newsha123"), Commit: &github.Commit{Message: github.Ptr("Guard nil input (" + findingShortID(referencedKey) + ")")}},
+	}, nil)
+	ghMock.On("ListIssueComments", mock.Anything, "org", "repo", 42).Return([]*github.IssueComment{
+		{User: &github.User{Login: github.Ptr("coderabbitai[bot]")}, Body: github.Ptr("Mentions " + findingShortID(openKey))},
+	}, nil)
+
+	store.On("SaveReviewLoop", mock.MatchedBy(func(l *kvstore.ReviewLoop) bool {
+		return l.Phase == kvstore.ReviewPhaseAwaitingReview
+	})).Return(nil)
+	mockInlineStatusUpdate(store, api, "agent-1", agentRecord)
+
+	err := p.handlePRSynchronize(context.Background(), loop, pr)
+	require.NoError(t, err)
+	assert.Equal(t, findingStatusResolved, loop.Findings[0].Status)
+	assert.Equal(t, findingResolvedByReference, loop.Findings[0].ResolvedBy)
+	assert.Equal(t, findingStatusOpen, loop.Findings[1].Status, "AI bot comments must not resolve findings")
+	assert.Contains(t, loop.History[len(loop.History)-1].Detail, "1 finding(s) resolved by reference")
+}
+
+func TestCollectFindingReferenceTexts_OnlySinceLastDispatch(t *testing.T) {
+	p, _, _, ghMock := setupReviewLoopTestPlugin(t)
+
+	dispatchedAt := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	loop := &kvstore.ReviewLoop{
+		ID:                      "loop-1",
+		Owner:                   "org",
+		Repo:                    "repo",
+		PRNumber:                42,
+		LastFeedbackDispatchSHA: "sha-2",
+		LastFeedbackDispatchAt:  dispatchedAt.UnixMilli(),
+	}
+
+	ghMock.On("ListPullRequestCommits", mock.Anything, "org", "repo", 42).Return([]*github.RepositoryCommit{
+		{SHA: github.Ptr("sha-1"), Commit: &github.Commit{Message: github.Ptr("Fixes abc123 from round one")}},
+		{SHA: github.Ptr("sha-2"), Commit: &github.Commit{Message: github.Ptr("Dispatched head")}},
+		{SHA: github.Ptr("sha-3"), Commit: &github.Commit{Message: github.Ptr("Fixes def456")}},
+	}, nil)
+	ghMock.On("ListIssueComments", mock.Anything, "org", "repo", 42).Return([]*github.IssueComment{
+		{
+			User:      &github.User{Login: github.Ptr("human-reviewer")},
+			Body:      github.Ptr("Addressed abc123 earlier"),
+			CreatedAt: &github.Timestamp{Time: dispatchedAt.Add(-time.Hour)},
+		},
+		{
+			User:      &github.User{Login: github.Ptr("human-reviewer")},
+			Body:      github.Ptr("Also fixed 987fed"),
+			CreatedAt: &github.Timestamp{Time: dispatchedAt.Add(time.Hour)},
+		},
+	}, nil)
+
+	texts, err := p.collectFindingReferenceTexts(loop)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Fixes def456", "Also fixed 987fed"}, texts)
+}
+
+func TestHandlePRSynchronize_AppendKeepsDispatchSHA(t *testing.T) {
+	p, api, store, ghMock := setupReviewLoopTestPlugin(t)
+
+	loop := &kvstore.ReviewLoop{
+		ID:                      "loop-1",
+		AgentRecordID:           "agent-1",
+		Owner:                   "org",
+		Repo:                    "repo",
+		PRNumber:                42,
+		Phase:                   kvstore.ReviewPhaseCursorFixing,
+		Iteration:               2,
+		LastCommitSHA:           "sha-1",
+		LastFeedbackDispatchSHA: "sha-1",
+	}
+
+	pr := ghPullRequest{}
+	pr.Head.SHA = "sha-2"
+
+	ghMock.On("CompareCommits", mock.Anything, "org", "repo", "sha-1", "sha-2").
+		Return(&github.CommitsComparison{Status: github.Ptr("ahead")}, nil).Once()
+	store.On("SaveReviewLoop", mock.Anything).Return(nil)
+	mockInlineStatusUpdate(store, api, "agent-1", &kvstore.AgentRecord{CursorAgentID: "agent-1"})
+
+	err := p.handlePRSynchronize(context.Background(), loop, pr)
+	require.NoError(t, err)
+	ghMock.AssertExpectations(t)
+	assert.Equal(t, "sha-2", loop.LastCommitSHA)
+	assert.Equal(t, "sha-1", loop.LastFeedbackDispatchSHA)
+	require.Len(t, loop.History, 1)
+	assert.Equal(t, "Cursor pushed fixes", loop.History[0].Detail)
+}
+
+func TestHandlePRSynchronize_ForcePushClearsDispatchSHA(t *testing.T) {
+	tests := []struct {
+		name       string
+		comparison *github.CommitsComparison
+		compareErr error
+	}{
+		{name: "diverged", comparison: &github.CommitsComparison{Status: github.Ptr("diverged")}},
+		{name: "behind", comparison: &github.CommitsComparison{Status: github.Ptr("behind")}},
+		{name: "previous head gone", compareErr: &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusNotFound}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, api, store, ghMock := setupReviewLoopTestPlugin(t)
+
+			loop := &kvstore.ReviewLoop{
+				ID:                      "loop-1",
+				AgentRecordID:           "agent-1",
+				Owner:                   "org",
+				Repo:                    "repo",
+				PRNumber:                42,
+				Phase:                   kvstore.ReviewPhaseCursorFixing,
+				Iteration:               2,
+				LastCommitSHA:           "aaaaaaaaaa",
+				LastFeedbackDispatchSHA: "aaaaaaaaaa",
+			}
+
+			pr := ghPullRequest{}
+			pr.Head.SHA = "bbbbbbbbbb"
+
+			ghMock.On("CompareCommits", mock.Anything, "org", "repo", "aaaaaaaaaa", "bbbbbbbbbb").
+				Return(tt.comparison, tt.compareErr).Once()
+			store.On("SaveReviewLoop", mock.Anything).Return(nil)
+			mockInlineStatusUpdate(store, api, "agent-1", &kvstore.AgentRecord{CursorAgentID: "agent-1"})
+
+			err := p.handlePRSynchronize(context.Background(), loop, pr)
+			require.NoError(t, err)
+			assert.Empty(t, loop.LastFeedbackDispatchSHA)
+			assert.Equal(t, "bbbbbbbbbb", loop.LastCommitSHA)
+			require.Len(t, loop.History, 2)
+			assert.Equal(t, "Force-push detected (aaaaaaa rewritten to bbbbbbb)", loop.History[0].Detail)
+			assert.Equal(t, kvstore.ReviewPhaseAwaitingReview, loop.History[1].Phase)
+		})
+	}
+}
+
+// dispatchThenResetHead dispatches feedback at sha-1, records a push to
+// sha-2, then a push that moves the head back to sha-1. The compare result
+// for the second push decides whether it is treated as a force-push.
+func dispatchThenResetHead(t *testing.T, resetComparison *github.CommitsComparison, resetErr error) (*Plugin, *kvstore.ReviewLoop, ghPullRequest) {
+	t.Helper()
+	p, api, store, ghMock := setupReviewLoopTestPlugin(t)
+	cursorMock := p.cursorClient.(*mockCursorClient)
+
+	loop := &kvstore.ReviewLoop{
+		ID:            "loop-1",
+		AgentRecordID: "agent-1",
+		Owner:         "org",
+		Repo:          "repo",
+		PRNumber:      42,
+		Phase:         kvstore.ReviewPhaseAwaitingReview,
+		Iteration:     1,
+		LastCommitSHA: "sha-1",
+	}
+
+	store.On("SaveReviewLoop", mock.Anything).Return(nil)
+	mockInlineStatusUpdate(store, api, "agent-1", &kvstore.AgentRecord{CursorAgentID: "agent-1"})
+	mockCheckpointReviewFeedback(ghMock)
+	ghMock.On("ListPullRequestCommits", mock.Anything, "org", "repo", 42).Return([]*github.RepositoryCommit{}, nil)
+	cursorMock.On("AddFollowup", mock.Anything, "agent-1", mock.Anything).
+		Return(&cursor.FollowupResponse{ID: "agent-1"}, nil)
+
+	headAtSHA1 := ghPullRequest{}
+	headAtSHA1.Head.SHA = "sha-1"
+	outcome, err := p.dispatchReviewFeedback(context.Background(), loop, headAtSHA1)
+	require.NoError(t, err)
+	require.True(t, outcome.Dispatched)
+
+	headAtSHA2 := ghPullRequest{}
+	headAtSHA2.Head.SHA = "sha-2"
+	ghMock.On("CompareCommits", mock.Anything, "org", "repo", "sha-1", "sha-2").
+		Return(&github.CommitsComparison{Status: github.Ptr("ahead")}, nil).Once()
+	require.NoError(t, p.handlePRSynchronize(context.Background(), loop, headAtSHA2))
+	require.Equal(t, "sha-1", loop.LastFeedbackDispatchSHA)
+
+	ghMock.On("CompareCommits", mock.Anything, "org", "repo", "sha-2", "sha-1").
+		Return(resetComparison, resetErr).Once()
+	require.NoError(t, p.handlePRSynchronize(context.Background(), loop, headAtSHA1))
+
+	return p, loop, headAtSHA1
+}
+
+func TestDispatchReviewFeedback_ForcePushedHeadIsNotSkippedAsDuplicate(t *testing.T) {
+	p, loop, pr := dispatchThenResetHead(t, &github.CommitsComparison{Status: github.Ptr("behind")}, nil)
+
+	outcome, err := p.dispatchReviewFeedback(context.Background(), loop, pr)
+	require.NoError(t, err)
+	assert.True(t, outcome.Dispatched)
+	assert.NotEqual(t, reviewDispatchModeSkippedIdempotent, outcome.Mode)
+	assert.Equal(t, "sha-1", loop.LastFeedbackDispatchSHA)
+}
+
+func TestDispatchReviewFeedback_UndetectedResetStillSkippedAsDuplicate(t *testing.T) {
+	// A compare failure falls back to normal-push handling, so the same SHA
+	// and digest are treated as already dispatched.
+	p, loop, pr := dispatchThenResetHead(t, nil, fmt.Errorf("connection reset"))
+
+	outcome, err := p.dispatchReviewFeedback(context.Background(), loop, pr)
+	require.NoError(t, err)
+	assert.True(t, outcome.Skipped)
+	assert.Equal(t, reviewDispatchModeSkippedIdempotent, outcome.Mode)
+}
+
+func TestFormatFindingsForCursorFollowup_IncludesFindingIDs(t *testing.T) {
+	key := buildFindingKey(reviewFeedbackCandidate{Path: "main.go", Line: 3, ActionableText: "fix it"})
+	prompt := formatFindingsForCursorFollowup(&kvstore.ReviewLoop{}, ghPullRequest{}, []kvstore.ReviewFinding{
+		{Key: key, ActionableText: "fix it", Path: "main.go", Line: 3},
+	}, followupFormatOptions{})
+
+	assert.Contains(t, prompt, "finding_id="+findingShortID(key))
+	assert.Contains(t, prompt, "cite the finding_id")
+}
+
+func TestExtractReviewSuggestion(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		expected string
+	}{
+		{
+			name:     "single-line suggestion",
+			raw:      "Use the helper here.\n\n```suggestion\nreturn writeJSON(w, resp)\n```",
+			expected: "return writeJSON(w, resp)",
+		},
+		{
+			name:     "multi-line suggestion keeps indentation",
+			raw:      "```suggestion\nif x == nil {\n\treturn nil\n}\n\n```",
+			expected: "if x == nil {\n\treturn nil\n}",
+		},
+		{
+			name:     "CRLF line endings",
+			raw:      "```suggestion\r\nreturn nil\r\n```",
+			expected: "return nil",
+		},
+		{
+			name: "plain code fence is not a suggestion",
+			raw:  "```go\nreturn nil\n```",
+		},
+		{
+			name: "empty suggestion is ignored",
+			raw:  "```suggestion\n```",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, extractReviewSuggestion(tt.raw))
+		})
+	}
+}
+
+func TestExtractCandidateActionableText_PureSuggestionIsCanonicalized(t *testing.T) {
+	candidate := normalizeFeedbackCandidate(reviewFeedbackCandidate{
+		ReviewerLogin: "human-reviewer",
+		SourceType:    "review_comment",
+		Path:          "server/api.go",
+		Line:          14,
+		RawText:       "```suggestion\nreturn nil\n\n```",
+	})
+	require.Equal(t, "return nil", candidate.Suggestion)
+
+	actionable, _, dropReason := extractCandidateActionableText(candidate)
+	assert.Empty(t, dropReason)
+	assert.Equal(t, "```suggestion\nreturn nil\n```", actionable)
+}
+
+func TestClassifyFeedback_SnoozedFindingExcludedUntilNextIteration(t *testing.T) {
+	candidate := reviewFeedbackCandidate{
+		SourceType:     "review_comment",
+		ReviewerType:   reviewerTypeAIBot,
+		Path:           "server/api.go",
+		Line:           12,
+		RawText:        "add nil check",
+		ActionableText: "add nil check",
+	}
+	loop := &kvstore.ReviewLoop{
+		Phase:     kvstore.ReviewPhaseAwaitingReview,
+		Iteration: 2,
 		Findings: []kvstore.ReviewFinding{
 			{
-				Key:            buildFindingKey(reviewFeedbackCandidate{Path: "server/api.go", Line: 12, ActionableText: "add nil check"}),
+				Key:            buildFindingKey(candidate),
 				Status:         findingStatusOpen,
 				ReviewerType:   reviewerTypeAIBot,
 				Path:           "server/api.go",
 				Line:           12,
 				ActionableText: "add nil check",
 			},
-			{
-				Key:            buildFindingKey(reviewFeedbackCandidate{Path: "server/webhook.go", Line: 88, ActionableText: "remove dead code"}),
-				Status:         findingStatusOpen,
-				ReviewerType:   reviewerTypeAIBot,
-				Path:           "server/webhook.go",
-				Line:           88,
-				ActionableText: "remove dead code",
-			},
 		},
 	}
+	require.NotNil(t, snoozeReviewFinding(loop, loop.Findings[0].Key))
 
-	candidates := []reviewFeedbackCandidate{
+	classification := classifyFeedback(loop, []reviewFeedbackCandidate{candidate}, 1700000000000)
+	require.Len(t, classification.Repeated, 1)
+	assert.Empty(t, classification.Dispatchable)
+	assert.Equal(t, 2, loop.Findings[0].SnoozedUntilIteration)
+
+	loop.Iteration = 3
+	classification = classifyFeedback(loop, []reviewFeedbackCandidate{candidate}, 1700000001000)
+	require.Len(t, classification.Dispatchable, 1)
+	assert.Equal(t, "add nil check", classification.Dispatchable[0].ActionableText)
+	assert.Zero(t, loop.Findings[0].SnoozedUntilIteration)
+}
+
+func TestClassifyFeedback_IdenticalSuggestionsAtSameLocationCollapse(t *testing.T) {
+	loop := &kvstore.ReviewLoop{
+		Phase:     kvstore.ReviewPhaseHumanReview,
+		Iteration: 1,
+	}
+
+	raws := []struct {
+		login string
+		body  string
+	}{
+		{login: "alice", body: "```suggestion\nif cfg == nil {\n\treturn nil\n}\n```"},
+		{login: "bob", body: "\n````suggestion\nif cfg == nil {\n\treturn nil\n}\n\n````\n"},
+	}
+
+	var candidates []reviewFeedbackCandidate
+	for i, raw := range raws {
+		candidate := normalizeFeedbackCandidate(reviewFeedbackCandidate{
+			SourceType:    "review_comment",
+			SourceID:      int64(i + 1),
+			ReviewerLogin: raw.login,
+			ReviewerType:  reviewerTypeHuman,
+			Path:          "server/configuration.go",
+			Line:          27,
+			RawText:       raw.body,
+		})
+		actionable, _, dropReason := extractCandidateActionableText(candidate)
+		require.Empty(t, dropReason)
+		candidate.ActionableText = actionable
+		candidates = append(candidates, candidate)
+	}
+
+	classification := classifyFeedback(loop, candidates, 1700000000000)
+	require.Len(t, classification.New, 1)
+	require.Len(t, loop.Findings, 1)
+	assert.Equal(t, "if cfg == nil {\n\treturn nil\n}", loop.Findings[0].Suggestion)
+}
+
+func TestFormatFindingsForCursorFollowup_IncludesSuggestionDirective(t *testing.T) {
+	prompt := formatFindingsForCursorFollowup(&kvstore.ReviewLoop{}, ghPullRequest{}, []kvstore.ReviewFinding{
 		{
-			SourceType:     "review_comment",
-			ReviewerType:   reviewerTypeAIBot,
+			Key:            "abcdef0123456789",
+			ActionableText: "Prefer an early return.\n\n```suggestion\nreturn nil\n```",
+			Suggestion:     "return nil",
 			Path:           "server/api.go",
-			Line:           12,
-			RawText:        "add nil check",
-			ActionableText: "add nil check",
+			Line:           14,
 		},
 		{
-			SourceType:     "review_comment",
-			ReviewerType:   reviewerTypeAIBot,
+			Key:            "0123456789abcdef",
+			ActionableText: canonicalSuggestionText("defer cancel()"),
+			Suggestion:     "defer cancel()",
 			Path:           "server/poller.go",
-			Line:           99,
-			RawText:        "handle timeout properly",
-			ActionableText: "handle timeout properly",
+			Line:           88,
 		},
-	}
+	}, followupFormatOptions{})
 
-	classification := classifyFeedback(loop, candidates, 1700000000000)
-	require.Len(t, classification.New, 1)
-	require.Len(t, classification.Repeated, 1)
-	require.Len(t, classification.Resolved, 1)
-	require.Len(t, classification.Dispatchable, 2)
-
-	assert.Equal(t, "handle timeout properly", classification.New[0].ActionableText)
-	assert.Equal(t, "add nil check", classification.Repeated[0].ActionableText)
-	assert.Equal(t, "remove dead code", classification.Resolved[0].ActionableText)
+	assert.Contains(t, prompt, "1. Prefer an early return.\n")
+	assert.Contains(t, prompt, "apply this suggestion at server/api.go:14, replacing the commented line(s) with:\n```\nreturn nil\n```")
+	assert.Contains(t, prompt, "2. Apply the reviewer's suggested change.\n")
+	assert.Contains(t, prompt, "apply this suggestion at server/poller.go:88")
+	assert.NotContains(t, prompt, "```suggestion")
 }
 
 func TestClassifyFeedback_SupersedesOlderSameLocationInstruction(t *testing.T) {
@@ -1638,11 +2967,11 @@ func TestClassifyFeedback_UnscopedFeedback_DedupesAcrossSourceURLs(t *testing.T)
 func TestIsAIReviewerBot(t *testing.T) {
 	p, _, _, _ := setupReviewLoopTestPlugin(t)
 
-	assert.True(t, p.isAIReviewerBot("coderabbitai[bot]"))
-	assert.True(t, p.isAIReviewerBot("CODERABBITAI[BOT]")) // case-insensitive
-	assert.True(t, p.isAIReviewerBot("copilot-pull-request-reviewer"))
-	assert.False(t, p.isAIReviewerBot("human-reviewer"))
-	assert.False(t, p.isAIReviewerBot(""))
+	assert.True(t, p.isAIReviewerBot("coderabbitai[bot]", ""))
+	assert.True(t, p.isAIReviewerBot("CODERABBITAI[BOT]", "")) // case-insensitive
+	assert.True(t, p.isAIReviewerBot("copilot-pull-request-reviewer", ""))
+	assert.False(t, p.isAIReviewerBot("human-reviewer", ""))
+	assert.False(t, p.isAIReviewerBot("", ""))
 }
 
 func TestPublishReviewLoopChange(t *testing.T) {
@@ -1735,6 +3064,123 @@ func TestHandleHumanReviewApproval(t *testing.T) {
 	api.AssertExpectations(t)
 }
 
+func TestHandleHumanReviewApproval_RequireAIGateBeforeAIApproval(t *testing.T) {
+	p, api, store, _ := setupReviewLoopTestPlugin(t)
+	p.configuration.ReviewLoopRequireAIGate = true
+
+	loop := &kvstore.ReviewLoop{
+		ID:            "loop-1",
+		AgentRecordID: "agent-1",
+		Phase:         kvstore.ReviewPhaseHumanReview,
+		Iteration:     1,
+		TriggerPostID: "trigger-1",
+		RootPostID:    "root-1",
+		ChannelID:     "ch-1",
+		UserID:        "user-1",
+		PRURL:         "https://github.com/org/repo/pull/42",
+		History: []kvstore.ReviewLoopEvent{
+			{Phase: kvstore.ReviewPhaseAwaitingReview, Timestamp: 1000},
+			{Phase: kvstore.ReviewPhaseHumanReview, Timestamp: 2000},
+		},
+	}
+
+	store.On("SaveReviewLoop", mock.MatchedBy(func(l *kvstore.ReviewLoop) bool {
+		return l.Phase == kvstore.ReviewPhaseHumanReview
+	})).Return(nil)
+	api.On("PublishWebSocketEvent", "review_loop_changed", mock.Anything, mock.Anything).Return()
+
+	err := p.handleHumanReviewApproval(loop, "testuser")
+	require.NoError(t, err)
+
+	assert.Equal(t, kvstore.ReviewPhaseHumanReview, loop.Phase)
+	require.Len(t, loop.History, 3)
+	lastEvent := loop.History[2]
+	assert.Equal(t, kvstore.ReviewPhaseHumanReview, lastEvent.Phase)
+	assert.Contains(t, lastEvent.Detail, "testuser")
+	assert.Contains(t, lastEvent.Detail, "before AI approval")
+
+	api.AssertNotCalled(t, "CreatePost", mock.Anything)
+	api.AssertNotCalled(t, "AddReaction", mock.Anything)
+	store.AssertExpectations(t)
+}
+
+func TestHandleHumanReviewApproval_RequireAIGateAfterAIApproval(t *testing.T) {
+	p, api, store, _ := setupReviewLoopTestPlugin(t)
+	p.configuration.ReviewLoopRequireAIGate = true
+
+	loop := &kvstore.ReviewLoop{
+		ID:            "loop-1",
+		AgentRecordID: "agent-1",
+		Phase:         kvstore.ReviewPhaseHumanReview,
+		Iteration:     2,
+		TriggerPostID: "trigger-1",
+		RootPostID:    "root-1",
+		ChannelID:     "ch-1",
+		UserID:        "user-1",
+		PRURL:         "https://github.com/org/repo/pull/42",
+		History: []kvstore.ReviewLoopEvent{
+			{Phase: kvstore.ReviewPhaseApproved, Timestamp: 1000},
+			{Phase: kvstore.ReviewPhaseHumanReview, Timestamp: 1001},
+		},
+	}
+
+	store.On("SaveReviewLoop", mock.MatchedBy(func(l *kvstore.ReviewLoop) bool {
+		return l.Phase == kvstore.ReviewPhaseComplete
+	})).Return(nil)
+	mockInlineStatusUpdate(store, api, "agent-1", &kvstore.AgentRecord{
+		CursorAgentID:  "agent-1",
+		BotReplyPostID: "reply-1",
+		ChannelID:      "ch-1",
+	})
+	api.On("CreatePost", mock.Anything).Return(&model.Post{Id: "notif-1"}, nil)
+	api.On("AddReaction", mock.Anything).Return(nil, nil)
+	api.On("PublishWebSocketEvent", "review_loop_changed", mock.Anything, mock.Anything).Return()
+
+	err := p.handleHumanReviewApproval(loop, "testuser")
+	require.NoError(t, err)
+
+	assert.Equal(t, kvstore.ReviewPhaseComplete, loop.Phase)
+	store.AssertExpectations(t)
+}
+
+func TestHandleHumanReviewApproval_RequireAIGateAfterReopen(t *testing.T) {
+	p, api, store, _ := setupReviewLoopTestPlugin(t)
+	p.configuration.ReviewLoopRequireAIGate = true
+
+	loop := &kvstore.ReviewLoop{
+		ID:            "loop-1",
+		AgentRecordID: "agent-1",
+		Phase:         kvstore.ReviewPhaseHumanReview,
+		Iteration:     2,
+		PRURL:         "https://github.com/org/repo/pull/42",
+		History: []kvstore.ReviewLoopEvent{
+			{Phase: kvstore.ReviewPhaseApproved, Timestamp: 1000},
+			{Phase: kvstore.ReviewPhaseHumanReview, Timestamp: 1001},
+			{Phase: kvstore.ReviewPhaseAwaitingReview, Timestamp: 2000, Detail: "Reopened AI review gate", Kind: reviewEventKindAIGateReopened},
+			{Phase: kvstore.ReviewPhaseHumanReview, Timestamp: 2001, Detail: "No new findings from coderabbitai[bot] to dispatch; returned to human review"},
+		},
+	}
+
+	store.On("SaveReviewLoop", mock.MatchedBy(func(l *kvstore.ReviewLoop) bool {
+		return l.Phase == kvstore.ReviewPhaseHumanReview
+	})).Return(nil)
+	api.On("PublishWebSocketEvent", "review_loop_changed", mock.Anything, mock.Anything).Return()
+
+	err := p.handleHumanReviewApproval(loop, "testuser")
+	require.NoError(t, err)
+
+	assert.Equal(t, kvstore.ReviewPhaseHumanReview, loop.Phase)
+	assert.Contains(t, loop.History[len(loop.History)-1].Detail, "before AI approval")
+	store.AssertExpectations(t)
+
+	// A fresh AI approval after the reopen passes the gate again.
+	loop.History = append(loop.History,
+		kvstore.ReviewLoopEvent{Phase: kvstore.ReviewPhaseApproved, Timestamp: 3000},
+		kvstore.ReviewLoopEvent{Phase: kvstore.ReviewPhaseHumanReview, Timestamp: 3001},
+	)
+	assert.True(t, reviewLoopAIGatePassed(loop))
+}
+
 // --- ensureReviewLoop tests ---
 
 func TestEnsureReviewLoop_ExistingLoop(t *testing.T) {
@@ -1890,3 +3336,324 @@ func TestEnsureReviewLoop_Idempotent(t *testing.T) {
 	store.AssertNotCalled(t, "GetAgentByPRURL")
 	store.AssertNotCalled(t, "SaveReviewLoop")
 }
+
+func TestBuildReviewFollowupPrompt_OriginalTask(t *testing.T) {
+	findings := []kvstore.ReviewFinding{{Key: "0123456789abcdef", ActionableText: "Add a nil guard."}}
+
+	t.Run("disabled omits the original task", func(t *testing.T) {
+		p, _, store, _ := setupReviewLoopTestPlugin(t)
+		loop := &kvstore.ReviewLoop{ID: "loop-1", AgentRecordID: "agent-1", WorkflowID: "wf-1"}
+
+		prompt := p.buildReviewFollowupPrompt(loop, ghPullRequest{}, findings)
+
+		assert.NotContains(t, prompt, "Original task")
+		store.AssertNotCalled(t, "GetWorkflow", mock.Anything)
+		store.AssertNotCalled(t, "GetAgent", mock.Anything)
+	})
+
+	t.Run("enabled appends the approved workflow context", func(t *testing.T) {
+		p, _, store, _ := setupReviewLoopTestPlugin(t)
+		p.configuration.ReviewLoopIncludeOriginalPrompt = true
+		loop := &kvstore.ReviewLoop{ID: "loop-1", AgentRecordID: "agent-1", WorkflowID: "wf-1"}
+		store.On("GetWorkflow", "wf-1").Return(&kvstore.HITLWorkflow{
+			ID:              "wf-1",
+			OriginalPrompt:  "fix login",
+			ApprovedContext: "Fix the login redirect loop for SSO users.",
+		}, nil)
+
+		prompt := p.buildReviewFollowupPrompt(loop, ghPullRequest{}, findings)
+
+		assert.Contains(t, prompt, "Add a nil guard.")
+		assert.True(t, strings.HasSuffix(prompt, "Original task (context only; address the findings above, not the whole task again):\nFix the login redirect loop for SSO users."))
+		assert.NotContains(t, prompt, "fix login\n")
+	})
+
+	t.Run("enabled falls back to the agent prompt", func(t *testing.T) {
+		p, _, store, _ := setupReviewLoopTestPlugin(t)
+		p.configuration.ReviewLoopIncludeOriginalPrompt = true
+		loop := &kvstore.ReviewLoop{ID: "loop-1", AgentRecordID: "agent-1"}
+		store.On("GetAgent", "agent-1").Return(&kvstore.AgentRecord{CursorAgentID: "agent-1", Prompt: "Add dark mode to settings"}, nil)
+
+		prompt := p.buildReviewFollowupPrompt(loop, ghPullRequest{}, findings)
+
+		assert.Contains(t, prompt, "Original task")
+		assert.Contains(t, prompt, "Add dark mode to settings")
+	})
+
+	t.Run("enabled without a known task leaves the prompt unchanged", func(t *testing.T) {
+		p, _, store, _ := setupReviewLoopTestPlugin(t)
+		p.configuration.ReviewLoopIncludeOriginalPrompt = true
+		loop := &kvstore.ReviewLoop{ID: "loop-1", AgentRecordID: "agent-1"}
+		store.On("GetAgent", "agent-1").Return(nil, nil)
+
+		prompt := p.buildReviewFollowupPrompt(loop, ghPullRequest{}, findings)
+
+		assert.Equal(t, formatFindingsForCursorFollowup(loop, ghPullRequest{}, findings, followupFormatOptions{}), prompt)
+	})
+}
+
+func TestBuildReviewFollowupPrompt_TruncatesOversizedFinding(t *testing.T) {
+	p, _, _, _ := setupReviewLoopTestPlugin(t)
+	p.configuration.MaxFindingTextLength = 40
+
+	stackTrace := "panic: runtime error: invalid memory address\n" + strings.Repeat("goroutine 1 [running]:\n", 200)
+	findings := []kvstore.ReviewFinding{
+		{Key: "0123456789abcdef", ActionableText: stackTrace, Path: "server/api.go", Line: 10},
+		{Key: "fedcba9876543210", ActionableText: "Add a nil guard.", Path: "server/poller.go", Line: 20},
+	}
+	loop := &kvstore.ReviewLoop{ID: "loop-1", AgentRecordID: "agent-1"}
+
+	prompt := p.buildReviewFollowupPrompt(loop, ghPullRequest{}, findings)
+
+	assert.Contains(t, prompt, "1. panic: runtime error: invalid memory add\n   [truncated ")
+	assert.Contains(t, prompt, "see the original comment for the full text]")
+	assert.Less(t, strings.Count(prompt, "goroutine 1 [running]"), 2)
+	assert.Contains(t, prompt, "2. Add a nil guard.\n")
+	assert.Contains(t, prompt, "path=server/poller.go")
+
+	// The caller's findings are not modified.
+	assert.Equal(t, stackTrace, findings[0].ActionableText)
+}
+
+func TestLimitFindingTextLength(t *testing.T) {
+	findings := []kvstore.ReviewFinding{
+		{Key: "a", ActionableText: "short"},
+		{Key: "b", RawText: "ééééééééééé"},
+		{Key: "c", ActionableText: "Fix it.\n\n```suggestion\n" + strings.Repeat("x", 50) + "\n```", Suggestion: strings.Repeat("x", 50)},
+	}
+
+	limited := limitFindingTextLength(findings, 10)
+
+	require.Len(t, limited, 3)
+	assert.Equal(t, "short", limited[0].ActionableText)
+	assert.Equal(t, "éééééééééé\n   [truncated 1 characters; see the original comment for the full text]", limited[1].ActionableText)
+	assert.Equal(t, "Fix it.\n\n```suggestion\n"+strings.Repeat("x", 50)+"\n```", limited[2].ActionableText, "suggestion blocks do not count toward the limit")
+	assert.Equal(t, strings.Repeat("x", 50), limited[2].Suggestion)
+
+	assert.Equal(t, findings, limitFindingTextLength(findings, 0))
+}
+
+func TestLimitFindingsPerIteration(t *testing.T) {
+	findings := []kvstore.ReviewFinding{
+		{Key: "a", Severity: findingSeverityNit, FirstSeenAt: 1},
+		{Key: "b", Severity: findingSeverityMajor, FirstSeenAt: 3},
+		{Key: "c", FirstSeenAt: 2},
+		{Key: "d", Severity: findingSeverityMajor, FirstSeenAt: 2},
+		{Key: "e", Severity: findingSeverityCritical, FirstSeenAt: 5},
+	}
+
+	keys := func(findings []kvstore.ReviewFinding) []string {
+		out := make([]string, 0, len(findings))
+		for _, f := range findings {
+			out = append(out, f.Key)
+		}
+		return out
+	}
+
+	selected, heldBack := limitFindingsPerIteration(findings, 3)
+	assert.Equal(t, []string{"b", "d", "e"}, keys(selected), "keeps critical then oldest major, in original order")
+	assert.Equal(t, []string{"a", "c"}, keys(heldBack))
+
+	selected, heldBack = limitFindingsPerIteration(findings, 4)
+	assert.Equal(t, []string{"b", "c", "d", "e"}, keys(selected), "unlabeled ranks with minor, above nit")
+	assert.Equal(t, []string{"a"}, keys(heldBack))
+
+	selected, heldBack = limitFindingsPerIteration(findings, 0)
+	assert.Len(t, selected, len(findings))
+	assert.Empty(t, heldBack)
+}
+
+func TestDispatchReviewFeedback_MaxFindingsPerIterationCarriesOverflow(t *testing.T) {
+	p, api, store, ghMock := setupReviewLoopTestPlugin(t)
+	cursorMock := p.cursorClient.(*mockCursorClient)
+	p.configuration.MaxFindingsPerIteration = 2
+
+	store.On("SaveReviewLoop", mock.Anything).Return(nil)
+	mockInlineStatusUpdate(store, api, "agent-1", &kvstore.AgentRecord{CursorAgentID: "agent-1"})
+
+	loop := &kvstore.ReviewLoop{
+		ID:            "loop-1",
+		AgentRecordID: "agent-1",
+		Owner:         "org",
+		Repo:          "repo",
+		PRNumber:      42,
+		Phase:         kvstore.ReviewPhaseAwaitingReview,
+		Iteration:     1,
+		LastCommitSHA: "sha-1",
+	}
+
+	reviewComment := func(line int, label, text string) *github.PullRequestComment {
+		return &github.PullRequestComment{
+			User:     &github.User{Login: github.Ptr("coderabbitai[bot]")},
+			Path:     github.Ptr("server/api.go"),
+			Line:     github.Ptr(line),
+			Body:     github.Ptr(label + "\n\nPrompt for AI Agents\n" + text),
+			CommitID: github.Ptr("sha-1"),
+		}
+	}
+	minor := reviewComment(10, "_🛠️ Refactor suggestion_ | _🟡 Minor_", "Extract the retry helper.")
+	critical := reviewComment(20, "_⚠️ Potential issue_ | _🔴 Critical_", "Escape the query parameter.")
+	major := reviewComment(30, "_⚠️ Potential issue_ | _🟠 Major_", "Close the response body.")
+
+	ghMock.On("ListReviewComments", mock.Anything, "org", "repo", 42).
+		Return([]*github.PullRequestComment{minor, critical, major}, nil).Once()
+	ghMock.On("ListReviews", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestReview{}, nil)
+	ghMock.On("ListIssueComments", mock.Anything, "org", "repo", 42).Return([]*github.IssueComment{}, nil)
+
+	cursorMock.On("AddFollowup", mock.Anything, "agent-1", mock.MatchedBy(func(req cursor.FollowupRequest) bool {
+		return strings.Contains(req.Prompt.Text, "Escape the query parameter.") &&
+			strings.Contains(req.Prompt.Text, "Close the response body.") &&
+			!strings.Contains(req.Prompt.Text, "Extract the retry helper.")
+	})).Return(&cursor.FollowupResponse{ID: "agent-1"}, nil).Once()
+
+	pr := ghPullRequest{}
+	pr.Head.SHA = "sha-1"
+	outcome, err := p.dispatchReviewFeedback(context.Background(), loop, pr)
+	require.NoError(t, err)
+	require.True(t, outcome.Dispatched)
+	assert.Contains(t, loop.History[len(loop.History)-1].Detail, "Held back 1 lower-priority finding(s)")
+
+	// The held back finding stays open alongside the dispatched ones.
+	shortIDs := map[string]string{}
+	for _, finding := range loop.Findings {
+		assert.Equal(t, findingStatusOpen, finding.Status)
+		assert.Equal(t, finding.Severity == findingSeverityMinor, finding.HeldBack)
+		shortIDs[finding.Severity] = finding.ShortID
+	}
+	require.Len(t, shortIDs, 3)
+
+	// The agent pushes fixes for the two dispatched findings.
+	loop.Phase = kvstore.ReviewPhaseCursorFixing
+	ghMock.On("CompareCommits", mock.Anything, "org", "repo", "sha-1", "sha-2").
+		Return(&github.CommitsComparison{Status: github.Ptr("ahead")}, nil).Once()
+	ghMock.On("ListPullRequestCommits", mock.Anything, "org", "repo", 42).Return([]*github.RepositoryCommit{
+		{Commit: &github.Commit{Message: github.Ptr(fmt.Sprintf(
+			"Fix review findings\n\nFixes %s, fixes %s",
+			shortIDs[findingSeverityCritical], shortIDs[findingSeverityMajor],
+		))}},
+	}, nil).Once()
+	pr.Head.SHA = "sha-2"
+	require.NoError(t, p.handlePRSynchronize(context.Background(), loop, pr))
+	require.Equal(t, kvstore.ReviewPhaseAwaitingReview, loop.Phase)
+
+	// The reviewer does not repeat feedback on the earlier commit, yet the next
+	// round still sends the held back finding.
+	ghMock.On("ListReviewComments", mock.Anything, "org", "repo", 42).
+		Return([]*github.PullRequestComment{minor, critical, major}, nil).Once()
+	cursorMock.On("AddFollowup", mock.Anything, "agent-1", mock.MatchedBy(func(req cursor.FollowupRequest) bool {
+		return strings.Contains(req.Prompt.Text, "Extract the retry helper.") &&
+			strings.Contains(req.Prompt.Text, "finding_id="+shortIDs[findingSeverityMinor])
+	})).Return(&cursor.FollowupResponse{ID: "agent-1"}, nil).Once()
+
+	outcome, err = p.dispatchReviewFeedback(context.Background(), loop, pr)
+	require.NoError(t, err)
+	require.True(t, outcome.Dispatched)
+	assert.Equal(t, 1, outcome.Counts.Repeated)
+	for _, finding := range loop.Findings {
+		assert.False(t, finding.HeldBack)
+	}
+	cursorMock.AssertExpectations(t)
+}
+
+func TestUpdateReviewLoopInlineStatus_FindingTallyAcrossIterations(t *testing.T) {
+	p, api, store, _ := setupReviewLoopTestPlugin(t)
+
+	store.On("GetAgent", "agent-1").Return(&kvstore.AgentRecord{
+		CursorAgentID:  "agent-1",
+		BotReplyPostID: "reply-1",
+		ChannelID:      "ch-1",
+	}, nil)
+	api.On("GetPost", "reply-1").Return(func(string) *model.Post {
+		return &model.Post{Id: "reply-1", ChannelId: "ch-1"}
+	}, nil)
+	var texts []string
+	api.On("UpdatePost", mock.Anything).Run(func(args mock.Arguments) {
+		post := args.Get(0).(*model.Post)
+		require.Len(t, post.Attachments(), 1)
+		texts = append(texts, post.Attachments()[0].Text)
+	}).Return(&model.Post{}, nil)
+
+	loop := &kvstore.ReviewLoop{
+		ID:            "loop-1",
+		AgentRecordID: "agent-1",
+		Phase:         kvstore.ReviewPhaseCursorFixing,
+		Iteration:     1,
+		Findings: []kvstore.ReviewFinding{
+			{Key: "a", Status: findingStatusOpen},
+			{Key: "b", Status: findingStatusOpen},
+			{Key: "c", Status: findingStatusOpen},
+		},
+	}
+	p.updateReviewLoopInlineStatus(loop)
+
+	// The next classification resolves two findings; dismissed ones are not counted.
+	loop.Iteration = 2
+	loop.Findings[0].Status = findingStatusResolved
+	loop.Findings[1].Status = findingStatusResolved
+	loop.Findings = append(loop.Findings, kvstore.ReviewFinding{Key: "d", Status: findingStatusDismissed})
+	p.updateReviewLoopInlineStatus(loop)
+
+	require.Len(t, texts, 2)
+	assert.Contains(t, texts[0], "0/3 findings resolved | 3 outstanding")
+	assert.Contains(t, texts[1], "2/3 findings resolved | 1 outstanding")
+}
+
+// assertDispatchEventMatchesDetail checks that an event's structured counts
+// are the ones its display detail reports.
+func assertDispatchEventMatchesDetail(t *testing.T, event kvstore.ReviewLoopEvent) {
+	t.Helper()
+	assert.Contains(t, event.Detail, formatReviewFeedbackCountSummary(event.New, event.Repeated, event.Dismissed))
+}
+
+func TestDispatchReviewFeedback_HistoryEventsCarryStructuredOutcome(t *testing.T) {
+	p, api, store, ghMock := setupReviewLoopTestPlugin(t)
+	cursorMock := p.cursorClient.(*mockCursorClient)
+
+	loop := &kvstore.ReviewLoop{
+		ID:            "loop-1",
+		AgentRecordID: "agent-1",
+		Owner:         "org",
+		Repo:          "repo",
+		PRNumber:      42,
+		Phase:         kvstore.ReviewPhaseAwaitingReview,
+		Iteration:     1,
+	}
+	pr := ghPullRequest{}
+	pr.Head.SHA = "sha-1"
+
+	store.On("SaveReviewLoop", mock.Anything).Return(nil)
+	mockInlineStatusUpdate(store, api, "agent-1", &kvstore.AgentRecord{CursorAgentID: "agent-1"})
+	mockCheckpointReviewFeedback(ghMock)
+	cursorMock.On("AddFollowup", mock.Anything, "agent-1", mock.Anything).
+		Return(&cursor.FollowupResponse{ID: "agent-1"}, nil).Once()
+
+	// A dispatch records the outcome on the iteration event.
+	_, err := p.redispatchReviewFeedback(loop, pr, "")
+	require.NoError(t, err)
+
+	require.NotEmpty(t, loop.History)
+	dispatched := loop.History[len(loop.History)-1]
+	assert.Equal(t, kvstore.ReviewPhaseCursorFixing, dispatched.Phase)
+	assert.Equal(t, reviewDispatchModeDirect, dispatched.Mode)
+	assert.Equal(t, 1, dispatched.New)
+	assert.Equal(t, "sha-1", dispatched.DispatchSHA)
+	assert.Equal(t, loop.LastFeedbackDigest, dispatched.Digest)
+	assert.NotEmpty(t, dispatched.Digest)
+	assertDispatchEventMatchesDetail(t, dispatched)
+
+	// The same bundle again is skipped, and the skip event says so.
+	loop.Phase = kvstore.ReviewPhaseAwaitingReview
+	outcome, err := p.dispatchReviewFeedback(context.Background(), loop, pr)
+	require.NoError(t, err)
+	require.True(t, outcome.Skipped)
+
+	skipped := loop.History[len(loop.History)-1]
+	assert.Equal(t, reviewDispatchModeSkippedIdempotent, skipped.Mode)
+	assert.Equal(t, outcome.Counts.New, skipped.New)
+	assert.Equal(t, outcome.Counts.Repeated, skipped.Repeated)
+	assert.Equal(t, "sha-1", skipped.DispatchSHA)
+	assert.Equal(t, dispatched.Digest, skipped.Digest)
+	assert.Contains(t, skipped.Detail, "Skipped duplicate review feedback dispatch")
+	assertDispatchEventMatchesDetail(t, skipped)
+	cursorMock.AssertNumberOfCalls(t, "AddFollowup", 1)
+}
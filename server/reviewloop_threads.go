@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/ghclient"
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+// attachReviewThreadIDs records the GitHub review thread of each inline
+// comment candidate so the thread can be resolved once its finding is fixed.
+// Failures are logged; the candidates are still collected without thread IDs.
+func (p *Plugin) attachReviewThreadIDs(ctx context.Context, ghClient ghclient.Client, loop *kvstore.ReviewLoop, candidates []reviewFeedbackCandidate) {
+	if len(candidates) == 0 {
+		return
+	}
+
+	threads, err := ghClient.ListReviewThreads(ctx, loop.Owner, loop.Repo, loop.PRNumber)
+	if err != nil {
+		p.API.LogWarn("Failed to list review threads for feedback collection",
+			"error", err.Error(),
+			"review_loop_id", loop.ID,
+		)
+		return
+	}
+
+	threadByComment := map[int64]string{}
+	for _, thread := range threads {
+		for _, commentID := range thread.CommentIDs {
+			threadByComment[commentID] = thread.ID
+		}
+	}
+	for i := range candidates {
+		if candidates[i].SourceType == "review_comment" {
+			candidates[i].ThreadID = threadByComment[candidates[i].SourceID]
+		}
+	}
+}
+
+// resolveFindingThreads resolves the GitHub review threads of findings that
+// were just classified as resolved, when ResolveThreadsOnFix is enabled.
+// Failures are logged and never block the review loop.
+func (p *Plugin) resolveFindingThreads(loop *kvstore.ReviewLoop, resolved []kvstore.ReviewFinding) {
+	if !p.getConfiguration().ResolveThreadsOnFix || len(resolved) == 0 {
+		return
+	}
+	ghClient := p.getGitHubClient()
+	if ghClient == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	resolvedThreads := map[string]bool{}
+	for _, finding := range resolved {
+		if finding.ThreadID == "" || resolvedThreads[finding.ThreadID] {
+			continue
+		}
+		resolvedThreads[finding.ThreadID] = true
+
+		if err := ghClient.ResolveReviewThread(ctx, finding.ThreadID); err != nil {
+			p.API.LogWarn("Failed to resolve review thread for fixed finding",
+				"error", err.Error(),
+				"review_loop_id", loop.ID,
+				"finding", finding.ShortID,
+				"thread_id", finding.ThreadID,
+			)
+		}
+	}
+}
+
+// reviewFindingAckText is the reply posted under an inline review comment
+// once its finding has been sent to the agent.
+const reviewFindingAckText = "🤖 Cursor is addressing this"
+
+// isReviewFindingAck reports whether body is an acknowledgement reply posted
+// by acknowledgeDispatchedFindings.
+func isReviewFindingAck(body string) bool {
+	return strings.TrimSpace(body) == reviewFindingAckText
+}
+
+// acknowledgeDispatchedFindings replies to the review comment of each
+// dispatched inline finding, when AckFindingsOnDispatch is enabled. Findings
+// are acknowledged at most once, so re-dispatching the same feedback does not
+// post duplicate replies. Failures are logged and retried on the next
+// dispatch.
+func (p *Plugin) acknowledgeDispatchedFindings(loop *kvstore.ReviewLoop, dispatched []kvstore.ReviewFinding) {
+	if !p.getConfiguration().AckFindingsOnDispatch || len(dispatched) == 0 {
+		return
+	}
+	ghClient := p.getGitHubClient()
+	if ghClient == nil {
+		return
+	}
+
+	dispatchedKeys := make(map[string]bool, len(dispatched))
+	for _, finding := range dispatched {
+		dispatchedKeys[finding.Key] = true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	for i := range loop.Findings {
+		finding := &loop.Findings[i]
+		if !dispatchedKeys[finding.Key] || finding.AcknowledgedAt > 0 {
+			continue
+		}
+		if finding.SourceType != "review_comment" || finding.SourceID == 0 {
+			continue
+		}
+
+		if _, err := ghClient.ReplyToReviewComment(ctx, loop.Owner, loop.Repo, loop.PRNumber, finding.SourceID, reviewFindingAckText); err != nil {
+			p.API.LogWarn("Failed to acknowledge dispatched review finding",
+				"error", err.Error(),
+				"review_loop_id", loop.ID,
+				"finding", finding.ShortID,
+			)
+			continue
+		}
+		finding.AcknowledgedAt = p.now().UnixMilli()
+	}
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-github/v68/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/cursor"
+	"github.com/mattermost/mattermost-plugin-cursor/server/ghclient"
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+func humanInlineComment(id int64, path, body string) *github.PullRequestComment {
+	return &github.PullRequestComment{
+		ID:   github.Ptr(id),
+		User: &github.User{Login: github.Ptr("human-reviewer")},
+		Path: github.Ptr(path),
+		Line: github.Ptr(7),
+		Body: github.Ptr(body),
+	}
+}
+
+func TestCollectReviewFeedback_ResolveThreadsOnFix(t *testing.T) {
+	p, _, _, ghMock := setupReviewLoopTestPlugin(t)
+	p.configuration.ResolveThreadsOnFix = true
+
+	loop := &kvstore.ReviewLoop{
+		ID:       "loop-1",
+		Owner:    "org",
+		Repo:     "repo",
+		PRNumber: 42,
+		Phase:    kvstore.ReviewPhaseHumanReview,
+	}
+
+	fixed := humanInlineComment(101, "a.go", "Please add a nil guard.")
+	open := humanInlineComment(202, "b.go", "Please cover the empty input case.")
+	ghMock.On("ListReviewComments", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestComment{fixed, open}, nil).Once()
+	ghMock.On("ListReviewComments", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestComment{open}, nil).Once()
+	ghMock.On("ListReviews", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestReview{}, nil)
+	ghMock.On("ListIssueComments", mock.Anything, "org", "repo", 42).Return([]*github.IssueComment{}, nil)
+	ghMock.On("ListReviewThreads", mock.Anything, "org", "repo", 42).Return([]ghclient.ReviewThread{
+		{ID: "thread-a", CommentIDs: []int64{101}},
+		{ID: "thread-b", CommentIDs: []int64{202, 203}},
+	}, nil)
+	ghMock.On("ResolveReviewThread", mock.Anything, "thread-a").Return(nil).Once()
+
+	// First pass captures the thread of each new inline finding.
+	_, err := p.collectReviewFeedback(loop)
+	require.NoError(t, err)
+	require.Len(t, loop.Findings, 2)
+	assert.Equal(t, "thread-a", loop.Findings[0].ThreadID)
+	assert.Equal(t, "thread-b", loop.Findings[1].ThreadID)
+	ghMock.AssertNotCalled(t, "ResolveReviewThread", mock.Anything, mock.Anything)
+
+	// Second pass: the a.go comment is gone, so its finding resolves.
+	_, err = p.collectReviewFeedback(loop)
+	require.NoError(t, err)
+
+	assert.Equal(t, findingStatusResolved, loop.Findings[0].Status)
+	assert.Equal(t, findingStatusOpen, loop.Findings[1].Status)
+	ghMock.AssertCalled(t, "ResolveReviewThread", mock.Anything, "thread-a")
+	ghMock.AssertNotCalled(t, "ResolveReviewThread", mock.Anything, "thread-b")
+}
+
+func TestCollectReviewFeedback_ResolveThreadsOnFixDisabled(t *testing.T) {
+	p, _, _, ghMock := setupReviewLoopTestPlugin(t)
+
+	loop := &kvstore.ReviewLoop{
+		ID:       "loop-1",
+		Owner:    "org",
+		Repo:     "repo",
+		PRNumber: 42,
+		Phase:    kvstore.ReviewPhaseHumanReview,
+		Findings: []kvstore.ReviewFinding{
+			{Key: "0123456789abcdef", Status: findingStatusOpen, ReviewerType: reviewerTypeHuman, ThreadID: "thread-a", ActionableText: "Please add a nil guard."},
+		},
+	}
+
+	ghMock.On("ListReviewComments", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestComment{}, nil)
+	ghMock.On("ListReviews", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestReview{}, nil)
+	ghMock.On("ListIssueComments", mock.Anything, "org", "repo", 42).Return([]*github.IssueComment{}, nil)
+
+	_, err := p.collectReviewFeedback(loop)
+	require.NoError(t, err)
+
+	assert.Equal(t, findingStatusResolved, loop.Findings[0].Status)
+	ghMock.AssertNotCalled(t, "ListReviewThreads", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	ghMock.AssertNotCalled(t, "ResolveReviewThread", mock.Anything, mock.Anything)
+}
+
+func TestDispatchReviewFeedback_AcknowledgesInlineFindings(t *testing.T) {
+	p, _, store, ghMock := setupReviewLoopTestPlugin(t)
+	p.configuration.AckFindingsOnDispatch = true
+	cursorMock := p.cursorClient.(*mockCursorClient)
+
+	store.On("SaveReviewLoop", mock.Anything).Return(nil)
+
+	loop := &kvstore.ReviewLoop{
+		ID:            "loop-ack",
+		AgentRecordID: "agent-1",
+		Owner:         "org",
+		Repo:          "repo",
+		PRNumber:      42,
+		Phase:         kvstore.ReviewPhaseAwaitingReview,
+		Iteration:     1,
+		PRURL:         "https://github.com/org/repo/pull/42",
+	}
+
+	ghMock.On("ListReviewComments", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestComment{
+		{
+			ID:   github.Ptr(int64(101)),
+			User: &github.User{Login: github.Ptr("coderabbitai[bot]")},
+			Path: github.Ptr("server/api.go"),
+			Line: github.Ptr(44),
+			Body: github.Ptr("Prompt for AI Agents\nAdd a nil guard before dereferencing the request body."),
+		},
+	}, nil).Twice()
+	ghMock.On("ListReviews", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestReview{
+		{
+			ID:   github.Ptr(int64(303)),
+			User: &github.User{Login: github.Ptr("coderabbitai[bot]")},
+			Body: github.Ptr("Actionable comments posted: 1\n\nPrompt for all review comments with AI agents\n```txt\nUse context.WithTimeout in API requests.\n```\n\nmeta"),
+		},
+	}, nil).Twice()
+	ghMock.On("ListIssueComments", mock.Anything, "org", "repo", 42).Return([]*github.IssueComment{}, nil).Twice()
+	ghMock.On("ReplyToReviewComment", mock.Anything, "org", "repo", 42, int64(101), reviewFindingAckText).
+		Return(&github.PullRequestComment{}, nil).Once()
+	cursorMock.On("AddFollowup", mock.Anything, "agent-1", mock.Anything).
+		Return(&cursor.FollowupResponse{ID: "agent-1"}, nil).Twice()
+
+	pr := ghPullRequest{}
+	pr.Head.SHA = "sha-1"
+	outcome, err := p.dispatchReviewFeedback(context.Background(), loop, pr)
+	require.NoError(t, err)
+	require.True(t, outcome.Dispatched)
+
+	require.Len(t, loop.Findings, 2)
+	for _, finding := range loop.Findings {
+		if finding.SourceType == "review_comment" {
+			assert.NotZero(t, finding.AcknowledgedAt)
+		} else {
+			assert.Zero(t, finding.AcknowledgedAt)
+		}
+	}
+
+	// Re-dispatching the same findings on a new head must not reply again.
+	pr.Head.SHA = "sha-2"
+	outcome, err = p.dispatchReviewFeedback(context.Background(), loop, pr)
+	require.NoError(t, err)
+	require.True(t, outcome.Dispatched)
+
+	ghMock.AssertNumberOfCalls(t, "ReplyToReviewComment", 1)
+	cursorMock.AssertNumberOfCalls(t, "AddFollowup", 2)
+}
+
+func TestDispatchReviewFeedback_AckFindingsDisabled(t *testing.T) {
+	p, _, store, ghMock := setupReviewLoopTestPlugin(t)
+	cursorMock := p.cursorClient.(*mockCursorClient)
+
+	store.On("SaveReviewLoop", mock.Anything).Return(nil)
+
+	loop := &kvstore.ReviewLoop{
+		ID:            "loop-ack-disabled",
+		AgentRecordID: "agent-1",
+		Owner:         "org",
+		Repo:          "repo",
+		PRNumber:      42,
+		Phase:         kvstore.ReviewPhaseHumanReview,
+		Iteration:     1,
+		PRURL:         "https://github.com/org/repo/pull/42",
+	}
+
+	ghMock.On("ListReviewComments", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestComment{
+		humanInlineComment(101, "a.go", "Please add a nil guard."),
+	}, nil)
+	ghMock.On("ListReviews", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestReview{}, nil)
+	ghMock.On("ListIssueComments", mock.Anything, "org", "repo", 42).Return([]*github.IssueComment{}, nil)
+	cursorMock.On("AddFollowup", mock.Anything, "agent-1", mock.Anything).
+		Return(&cursor.FollowupResponse{ID: "agent-1"}, nil).Once()
+
+	pr := ghPullRequest{}
+	pr.Head.SHA = "sha-1"
+	outcome, err := p.dispatchReviewFeedback(context.Background(), loop, pr)
+	require.NoError(t, err)
+	require.True(t, outcome.Dispatched)
+
+	require.Len(t, loop.Findings, 1)
+	assert.Zero(t, loop.Findings[0].AcknowledgedAt)
+	ghMock.AssertNotCalled(t, "ReplyToReviewComment", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"strings"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/ghclient"
+)
+
+// postReviewerWarmupComment comments on the PR mentioning each AI reviewer bot,
+// followed by ReviewLoopWarmupComment, for bots that only start reviewing when
+// mentioned. Skipped when no warmup comment or no bots are configured.
+// Failures are logged and non-fatal.
+func (p *Plugin) postReviewerWarmupComment(ctx context.Context, ghClient ghclient.Client, owner, repo string, prNumber int, prURL string, botUsernames []string) {
+	body := formatReviewerWarmupComment(p.getConfiguration().ReviewLoopWarmupComment, botUsernames)
+	if body == "" {
+		return
+	}
+
+	if _, err := ghClient.CreateComment(ctx, owner, repo, prNumber, body); err != nil {
+		p.API.LogWarn("Failed to post reviewer warmup comment (non-fatal)",
+			"error", err.Error(),
+			"pr_url", prURL,
+		)
+	}
+}
+
+// formatReviewerWarmupComment builds the warmup comment body. Bots are
+// mentioned by login without the "[bot]" suffix, which is how GitHub
+// resolves app mentions. Returns "" when text or bots are empty.
+func formatReviewerWarmupComment(text string, botUsernames []string) string {
+	text = strings.TrimSpace(text)
+	if text == "" || len(botUsernames) == 0 {
+		return ""
+	}
+
+	mentions := make([]string, 0, len(botUsernames))
+	for _, login := range botUsernames {
+		mentions = append(mentions, "@"+strings.TrimSuffix(login, "[bot]"))
+	}
+	return strings.Join(mentions, " ") + " " + text
+}
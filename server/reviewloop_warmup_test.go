@@ -0,0 +1,65 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/google/go-github/v68/github"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+func startWarmupTestLoop(t *testing.T, p *Plugin, api *mockPluginAPI, store *mockKVStore, ghMock *mockGitHubClient) {
+	t.Helper()
+	record := &kvstore.AgentRecord{
+		CursorAgentID:  "agent-1",
+		UserID:         "user-1",
+		ChannelID:      "ch-1",
+		PostID:         "root-1",
+		TriggerPostID:  "trigger-1",
+		BotReplyPostID: "reply-1",
+		PrURL:          "https://github.com/org/repo/pull/42",
+		Repository:     "org/repo",
+	}
+
+	store.On("GetReviewLoopByPRURL", record.PrURL).Return(nil, nil)
+	store.On("GetWorkflowByAgent", "agent-1").Return("", nil)
+	store.On("SaveReviewLoop", mock.Anything).Return(nil)
+	ghMock.On("MarkPRReadyForReview", mock.Anything, "org", "repo", 42).Return(nil)
+	ghMock.On("RequestReviewers", mock.Anything, "org", "repo", 42, mock.Anything).Return(nil)
+	mockInlineStatusUpdate(store, api, "agent-1", record)
+	api.On("AddReaction", mock.Anything).Return(&model.Reaction{}, nil)
+
+	require.NoError(t, p.startReviewLoop(record))
+}
+
+func TestStartReviewLoop_PostsWarmupCommentMentioningBots(t *testing.T) {
+	p, api, store, ghMock := setupReviewLoopTestPlugin(t)
+	p.configuration.AIReviewerBots = "coderabbitai[bot], cursor[bot]"
+	p.configuration.ReviewLoopWarmupComment = "review"
+
+	ghMock.On("CreateComment", mock.Anything, "org", "repo", 42, "@coderabbitai @cursor review").
+		Return(&github.IssueComment{}, nil).Once()
+
+	startWarmupTestLoop(t, p, api, store, ghMock)
+
+	ghMock.AssertExpectations(t)
+}
+
+func TestStartReviewLoop_WarmupCommentSkippedWhenDisabled(t *testing.T) {
+	p, api, store, ghMock := setupReviewLoopTestPlugin(t)
+	p.configuration.AIReviewerBots = "coderabbitai[bot]"
+
+	startWarmupTestLoop(t, p, api, store, ghMock)
+
+	ghMock.AssertNotCalled(t, "CreateComment", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestFormatReviewerWarmupComment(t *testing.T) {
+	assert.Equal(t, "@coderabbitai review", formatReviewerWarmupComment(" review ", []string{"coderabbitai[bot]"}))
+	assert.Empty(t, formatReviewerWarmupComment("review", nil))
+	assert.Empty(t, formatReviewerWarmupComment("  ", []string{"coderabbitai[bot]"}))
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+// attachmentFieldValue returns the value of the first field with the given
+// title on the post's first attachment.
+func attachmentFieldValue(post *model.Post, title string) (string, bool) {
+	for _, attachment := range post.Attachments() {
+		for _, field := range attachment.Fields {
+			if field.Title == title {
+				value, _ := field.Value.(string)
+				return value, true
+			}
+		}
+	}
+	return "", false
+}
+
+func TestStartReviewLoop_StoresWorkflowID(t *testing.T) {
+	p, api, store, ghMock := setupReviewLoopTestPlugin(t)
+
+	record := &kvstore.AgentRecord{
+		CursorAgentID:  "agent-1",
+		UserID:         "user-1",
+		ChannelID:      "ch-1",
+		PostID:         "root-1",
+		TriggerPostID:  "trigger-1",
+		BotReplyPostID: "reply-1",
+		PrURL:          "https://github.com/org/repo/pull/42",
+		Repository:     "org/repo",
+	}
+
+	store.On("GetReviewLoopByPRURL", "https://github.com/org/repo/pull/42").Return(nil, nil)
+	store.On("GetWorkflowByAgent", "agent-1").Return("wf-1", nil)
+	store.On("SaveReviewLoop", mock.MatchedBy(func(loop *kvstore.ReviewLoop) bool {
+		return loop.AgentRecordID == "agent-1" && loop.WorkflowID == "wf-1"
+	})).Return(nil)
+	ghMock.On("MarkPRReadyForReview", mock.Anything, "org", "repo", 42).Return(nil)
+	ghMock.On("RequestReviewers", mock.Anything, "org", "repo", 42, mock.Anything).Return(nil)
+	mockInlineStatusUpdate(store, api, "agent-1", record)
+	api.On("AddReaction", mock.Anything).Return(nil, nil)
+
+	require.NoError(t, p.startReviewLoop(record))
+	store.AssertExpectations(t)
+}
+
+func TestPostReviewLoopCompletion_WorkflowLoopShowsPromptAndPlan(t *testing.T) {
+	p, api, store, _ := setupReviewLoopTestPlugin(t)
+	siteURL := "http://localhost:8065"
+	api.On("GetConfig").Return(&model.Config{
+		ServiceSettings: model.ServiceSettings{SiteURL: &siteURL},
+	}).Maybe()
+
+	store.On("GetWorkflow", "wf-1").Return(&kvstore.HITLWorkflow{
+		ID:             "wf-1",
+		OriginalPrompt: "Add retry handling to the webhook client",
+		ApprovedPlan:   "1. Wrap the call in a retry loop",
+		PlanPostID:     "plan-post-1",
+	}, nil)
+	api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		prompt, _ := attachmentFieldValue(post, "Original prompt")
+		plan, _ := attachmentFieldValue(post, "Approved plan")
+		return post.RootId == "root-1" &&
+			prompt == "Add retry handling to the webhook client" &&
+			plan == "[View plan](http://localhost:8065/_redirect/pl/plan-post-1)"
+	})).Return(&model.Post{Id: "post-1"}, nil).Once()
+
+	loop := newOwnerDMReviewLoop()
+	loop.WorkflowID = "wf-1"
+	p.postReviewLoopCompletion(loop, &model.SlackAttachment{Title: "PR approved by alice! Review loop complete."})
+
+	api.AssertExpectations(t)
+}
+
+func TestPostReviewLoopCompletion_WorkflowWithoutApprovedPlanOmitsLink(t *testing.T) {
+	p, api, store, _ := setupReviewLoopTestPlugin(t)
+
+	store.On("GetWorkflow", "wf-1").Return(&kvstore.HITLWorkflow{
+		ID:             "wf-1",
+		OriginalPrompt: "Fix the flaky test",
+		PlanPostID:     "plan-post-1",
+	}, nil)
+	api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		_, hasPrompt := attachmentFieldValue(post, "Original prompt")
+		_, hasPlan := attachmentFieldValue(post, "Approved plan")
+		return hasPrompt && !hasPlan
+	})).Return(&model.Post{Id: "post-1"}, nil).Once()
+
+	loop := newOwnerDMReviewLoop()
+	loop.WorkflowID = "wf-1"
+	p.postReviewLoopCompletion(loop, &model.SlackAttachment{Title: "AI review loop failed."})
+
+	api.AssertExpectations(t)
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+// slackWebhookTimeout bounds a single Slack webhook delivery.
+const slackWebhookTimeout = 10 * time.Second
+
+// slackNotification is a compact agent or review loop event mirrored to Slack.
+type slackNotification struct {
+	Title      string
+	Repository string
+	PRURL      string
+	Detail     string
+}
+
+// slackText is a Slack block kit text object.
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// slackBlock is the subset of Slack block kit blocks the bridge emits.
+// Elements holds slackText values for context blocks and slackButton values
+// for actions blocks.
+type slackBlock struct {
+	Type     string     `json:"type"`
+	Text     *slackText `json:"text,omitempty"`
+	Elements []any      `json:"elements,omitempty"`
+}
+
+// slackButton is a block kit link button.
+type slackButton struct {
+	Type string    `json:"type"`
+	Text slackText `json:"text"`
+	URL  string    `json:"url"`
+}
+
+// slackPayload is the body of a Slack incoming webhook request. Text is the
+// fallback shown in notifications.
+type slackPayload struct {
+	Text   string       `json:"text"`
+	Blocks []slackBlock `json:"blocks"`
+}
+
+// buildSlackPayload formats n as a Slack block kit message: a section with
+// the title and detail, the repository as context, and a "View PR" button
+// when the PR is known.
+func buildSlackPayload(n slackNotification) slackPayload {
+	fallback := n.Title
+	if n.PRURL != "" {
+		fallback = fmt.Sprintf("%s: %s", n.Title, n.PRURL)
+	}
+
+	section := "*" + n.Title + "*"
+	if detail := strings.TrimSpace(n.Detail); detail != "" {
+		section += "\n" + truncateText(detail, 500)
+	}
+	blocks := []slackBlock{{
+		Type: "section",
+		Text: &slackText{Type: "mrkdwn", Text: section},
+	}}
+	if n.Repository != "" {
+		blocks = append(blocks, slackBlock{
+			Type:     "context",
+			Elements: []any{slackText{Type: "mrkdwn", Text: "`" + n.Repository + "`"}},
+		})
+	}
+	if n.PRURL != "" {
+		blocks = append(blocks, slackBlock{
+			Type: "actions",
+			Elements: []any{slackButton{
+				Type: "button",
+				Text: slackText{Type: "plain_text", Text: "View PR"},
+				URL:  n.PRURL,
+			}},
+		})
+	}
+	return slackPayload{Text: fallback, Blocks: blocks}
+}
+
+// notifySlack mirrors n to the configured Slack webhook. Delivery happens in
+// the background and failures are only logged, so the Mattermost flow never
+// waits on or fails because of Slack.
+func (p *Plugin) notifySlack(n slackNotification) {
+	webhookURL := strings.TrimSpace(p.getConfiguration().SlackWebhookURL)
+	if webhookURL == "" {
+		return
+	}
+	go p.deliverSlackNotification(webhookURL, buildSlackPayload(n))
+}
+
+// deliverSlackNotification posts payload to webhookURL, logging any failure.
+func (p *Plugin) deliverSlackNotification(webhookURL string, payload slackPayload) {
+	ctx, cancel := context.WithTimeout(context.Background(), slackWebhookTimeout)
+	defer cancel()
+
+	if err := postSlackWebhook(ctx, http.DefaultClient, webhookURL, payload); err != nil {
+		p.API.LogWarn("Failed to deliver Slack notification", "error", err.Error())
+	}
+}
+
+// postSlackWebhook sends payload to a Slack incoming webhook.
+func postSlackWebhook(ctx context.Context, client *http.Client, webhookURL string, payload slackPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal Slack payload: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build Slack request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("slack webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("slack webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// notifySlackAgentFinished mirrors an agent completion to Slack.
+func (p *Plugin) notifySlackAgentFinished(record *kvstore.AgentRecord, prURL, summary string) {
+	p.notifySlack(slackNotification{
+		Title:      "Cursor agent finished",
+		Repository: record.Repository,
+		PRURL:      prURL,
+		Detail:     summary,
+	})
+}
+
+// notifySlackReviewLoopApproved mirrors an AI review approval to Slack. Like
+// the thread notification it is skipped during quiet hours.
+func (p *Plugin) notifySlackReviewLoopApproved(loop *kvstore.ReviewLoop) {
+	if p.inQuietHours(p.now()) {
+		return
+	}
+	repository := loop.Repository
+	if repository == "" {
+		repository = strings.Trim(loop.Owner+"/"+loop.Repo, "/")
+	}
+	p.notifySlack(slackNotification{
+		Title:      "AI review approved",
+		Repository: repository,
+		PRURL:      loop.PRURL,
+		Detail:     fmt.Sprintf("Approved after %d iteration(s); waiting on human review.", loop.Iteration),
+	})
+}
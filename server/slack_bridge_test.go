@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/cursor"
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+func TestBuildSlackPayload(t *testing.T) {
+	payload := buildSlackPayload(slackNotification{
+		Title:      "Cursor agent finished",
+		Repository: "org/repo",
+		PRURL:      "https://github.com/org/repo/pull/7",
+		Detail:     "Fixed the login bug.",
+	})
+
+	raw, err := json.Marshal(payload)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"text": "Cursor agent finished: https://github.com/org/repo/pull/7",
+		"blocks": [
+			{"type": "section", "text": {"type": "mrkdwn", "text": "*Cursor agent finished*\nFixed the login bug."}},
+			{"type": "context", "elements": [{"type": "mrkdwn", "text": "`+"`org/repo`"+`"}]},
+			{"type": "actions", "elements": [
+				{"type": "button", "text": {"type": "plain_text", "text": "View PR"}, "url": "https://github.com/org/repo/pull/7"}
+			]}
+		]
+	}`, string(raw))
+}
+
+func TestBuildSlackPayload_WithoutPR(t *testing.T) {
+	payload := buildSlackPayload(slackNotification{Title: "Cursor agent finished"})
+
+	assert.Equal(t, "Cursor agent finished", payload.Text)
+	require.Len(t, payload.Blocks, 1)
+	assert.Equal(t, "section", payload.Blocks[0].Type)
+}
+
+func TestPostSlackWebhook(t *testing.T) {
+	var received slackPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		body, _ := io.ReadAll(r.Body)
+		assert.NoError(t, json.Unmarshal(body, &received))
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	err := postSlackWebhook(context.Background(), server.Client(), server.URL, slackPayload{Text: "hello"})
+	require.NoError(t, err)
+	assert.Equal(t, "hello", received.Text)
+}
+
+func TestPostSlackWebhook_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	err := postSlackWebhook(context.Background(), server.Client(), server.URL, slackPayload{Text: "hello"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 403")
+}
+
+func TestPoller_AgentFinished_SlackFailureDoesNotAffectMattermost(t *testing.T) {
+	p, api, cursorClient, store := setupPollerPlugin(t)
+
+	delivered := make(chan slackPayload, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload slackPayload
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		delivered <- payload
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	p.configuration.SlackWebhookURL = server.URL
+
+	record := &kvstore.AgentRecord{
+		CursorAgentID:  "agent-1",
+		Status:         "RUNNING",
+		TriggerPostID:  "trigger-1",
+		PostID:         "root-1",
+		ChannelID:      "ch-1",
+		BotReplyPostID: "bot-reply-1",
+		Repository:     "org/repo",
+	}
+
+	store.On("ListActiveAgents").Return([]*kvstore.AgentRecord{record}, nil)
+	store.On("GetAgent", "agent-1").Return(record, nil)
+	cursorClient.On("GetAgent", mock.Anything, "agent-1").Return(&cursor.Agent{
+		ID:     "agent-1",
+		Status: cursor.AgentStatusFinished,
+		Target: cursor.AgentTarget{PrURL: "https://github.com/org/repo/pull/5"},
+	}, nil)
+	store.On("GetWorkflowByAgent", "agent-1").Return("", nil)
+	store.On("SaveAgent", mock.Anything).Return(nil)
+	api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		return post.RootId == "root-1" && post.Message == "Agent finished! [View PR](https://github.com/org/repo/pull/5)"
+	})).Return(&model.Post{Id: "msg-1"}, nil).Once()
+	api.On("CreatePost", mock.Anything).Return(&model.Post{Id: "msg-2"}, nil)
+
+	p.pollAgentStatuses()
+
+	api.AssertExpectations(t)
+	store.AssertCalled(t, "SaveAgent", mock.Anything)
+
+	select {
+	case payload := <-delivered:
+		assert.Equal(t, "Cursor agent finished: https://github.com/org/repo/pull/5", payload.Text)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Slack notification was not delivered")
+	}
+}
+
+func TestConfigurationIsValid_SlackWebhookURL(t *testing.T) {
+	cfg := &configuration{CursorAPIKey: "key", PollIntervalSeconds: 30}
+
+	cfg.SlackWebhookURL = "https://hooks.slack.com/services/T/B/X"
+	assert.NoError(t, cfg.IsValid())
+
+	cfg.SlackWebhookURL = "http://hooks.slack.com/services/T/B/X"
+	assert.Error(t, cfg.IsValid())
+
+	cfg.SlackWebhookURL = "not a url"
+	assert.Error(t, cfg.IsValid())
+}
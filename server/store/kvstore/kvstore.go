@@ -12,6 +12,9 @@ type AgentRecord struct {
 	Repository     string `json:"repository"`
 	Branch         string `json:"branch"`
 	TargetBranch   string `json:"targetBranch,omitempty"` // Cursor-created branch (e.g., "cursor/fix-login")
+	BaseBranch     string `json:"baseBranch,omitempty"`   // Base branch of the agent's PR, backfilled from GitHub
+	SourceRef      string `json:"sourceRef,omitempty"`    // Commit, tag, or branch from "ref=", when the launch did not start from Branch
+	Draft          bool   `json:"draft,omitempty"`        // Launched with "draft=true": the PR stays a draft until someone marks it ready
 	PrURL          string `json:"prUrl"`
 	Prompt         string `json:"prompt"`
 	Description    string `json:"description,omitempty"` // AI-generated short task summary
@@ -20,12 +23,30 @@ type AgentRecord struct {
 	CreatedAt      int64  `json:"createdAt"`          // Unix millis
 	UpdatedAt      int64  `json:"updatedAt"`          // Unix millis
 	Archived       bool   `json:"archived,omitempty"` // Soft-archived by user
+
+	// Progress mirroring (channels with MirrorProgress on).
+	ProgressPostID    string `json:"progressPostId,omitempty"`    // Thread post showing the latest agent message
+	ProgressMessageID string `json:"progressMessageId,omitempty"` // Conversation message currently mirrored
+
+	// Live progress on the launch card (ProgressUpdateSeconds > 0).
+	LiveProgressMessageID string `json:"liveProgressMessageId,omitempty"` // Last conversation message shown on the launch card
+	LiveProgressAt        int64  `json:"liveProgressAt,omitempty"`        // When the launch card last showed new activity
+
+	// Further PRs the agent opened after PrURL, oldest first, e.g. when it
+	// split its work.
+	ExtraPRURLs []string `json:"extraPrUrls,omitempty"`
 }
 
 // ChannelSettings stores per-channel defaults.
 type ChannelSettings struct {
 	DefaultRepository string `json:"defaultRepository"`
 	DefaultBranch     string `json:"defaultBranch"`
+	BotUsername       string `json:"botUsername,omitempty"`    // Bot identity used in this channel; empty = default bot
+	MirrorProgress    bool   `json:"mirrorProgress,omitempty"` // Mirror the running agent's latest message into the thread
+
+	// AllowedLauncherUserIDs restricts who may launch agents in the channel.
+	// Empty means everyone; channel and system admins may always launch.
+	AllowedLauncherUserIDs []string `json:"allowedLauncherUserIds,omitempty"`
 }
 
 // UserSettings stores per-user defaults.
@@ -35,6 +56,27 @@ type UserSettings struct {
 	DefaultModel        string `json:"defaultModel"`
 	EnableContextReview *bool  `json:"enableContextReview,omitempty"` // nil = use global config
 	EnablePlanLoop      *bool  `json:"enablePlanLoop,omitempty"`      // nil = use global config
+
+	Aliases map[string]string `json:"aliases,omitempty"` // Launch shortcut name -> option string, expanded from "@name"
+}
+
+// UserLaunchHistory holds a user's most recent launches, newest first, so
+// they can be listed and re-run with /cursor recent.
+type UserLaunchHistory struct {
+	Entries []LaunchHistoryEntry `json:"entries"`
+}
+
+// LaunchHistoryEntry is one launch in a user's history.
+type LaunchHistoryEntry struct {
+	Prompt       string `json:"prompt"`
+	Repository   string `json:"repository"`
+	Branch       string `json:"branch"`
+	SourceRef    string `json:"sourceRef,omitempty"`  // Launch ref from "ref=", empty to start from Branch
+	BaseBranch   string `json:"baseBranch,omitempty"` // PR base from "base=", empty for the repo default
+	Model        string `json:"model"`
+	AutoCreatePR *bool  `json:"autoCreatePr,omitempty"` // nil for entries recorded before it was stored; replayed as true
+	Draft        bool   `json:"draft,omitempty"`
+	LaunchedAt   int64  `json:"launchedAt"` // Unix millis
 }
 
 // HITLWorkflow tracks the full lifecycle of a Human-In-The-Loop verification
@@ -52,9 +94,12 @@ type HITLWorkflow struct {
 	// Resolved parameters (from parse + defaults cascade).
 	Repository     string `json:"repository"`
 	Branch         string `json:"branch"`
+	BaseBranch     string `json:"baseBranch,omitempty"` // PR base from "base=", empty for the repo default
+	SourceRef      string `json:"sourceRef,omitempty"`  // Launch ref from "ref=", empty to start from Branch
 	Model          string `json:"model"`
 	AutoCreatePR   bool   `json:"autoCreatePr"`
-	OriginalPrompt string `json:"originalPrompt"` // Raw user prompt text
+	Draft          bool   `json:"draft,omitempty"` // Keep the PR a draft, from "draft=true"
+	OriginalPrompt string `json:"originalPrompt"`  // Raw user prompt text
 
 	// Context review state.
 	EnrichedContext string     `json:"enrichedContext,omitempty"` // Bridge client output
@@ -69,6 +114,16 @@ type HITLWorkflow struct {
 	PlanPostID         string `json:"planPostId,omitempty"`         // Post with Accept/Reject buttons
 	PlanIterationCount int    `json:"planIterationCount,omitempty"` // Number of plan iterations
 	PlanFeedback       string `json:"planFeedback,omitempty"`       // User's feedback for the next planning iteration
+	PlanCommentPRURL   string `json:"planCommentPrUrl,omitempty"`   // PR the approved plan was commented on
+
+	// PlanChecklist holds the approved plan's steps for the PR checklist,
+	// nested steps indented by two spaces. See PlanAsPRChecklist.
+	PlanChecklist      []string `json:"planChecklist,omitempty"`
+	PlanChecklistPRURL string   `json:"planChecklistPrUrl,omitempty"` // PR the checklist was commented on
+
+	// PlanVersions holds the most recent retrieved plans, oldest first, so
+	// successive iterations can be compared. See AppendPlanVersion.
+	PlanVersions []string `json:"planVersions,omitempty"`
 
 	// PendingFeedback stores user feedback submitted while a planner agent is running.
 	// When the planner finishes and transitions to plan_review, this feedback is
@@ -87,6 +142,64 @@ type HITLWorkflow struct {
 	UpdatedAt int64 `json:"updatedAt"` // Unix milliseconds
 }
 
+// MaxPlanVersions is the number of retrieved plans kept on a workflow.
+const MaxPlanVersions = 10
+
+// AppendPlanVersion records a retrieved plan, dropping the oldest versions
+// beyond MaxPlanVersions. A plan identical to the latest version is ignored.
+func (w *HITLWorkflow) AppendPlanVersion(plan string) {
+	if n := len(w.PlanVersions); n > 0 && w.PlanVersions[n-1] == plan {
+		return
+	}
+	w.PlanVersions = append(w.PlanVersions, plan)
+	if extra := len(w.PlanVersions) - MaxPlanVersions; extra > 0 {
+		w.PlanVersions = append([]string(nil), w.PlanVersions[extra:]...)
+	}
+}
+
+// WebhookDelivery is a verified GitHub webhook delivery recorded for debugging
+// while RecordWebhookDeliveries is on. Only the most recent deliveries are kept.
+type WebhookDelivery struct {
+	DeliveryID string `json:"deliveryId"`
+	Event      string `json:"event"`
+	Action     string `json:"action,omitempty"`
+	Body       string `json:"body"`                // Raw payload, cut at the capture limit
+	Truncated  bool   `json:"truncated,omitempty"` // Body was cut; the delivery cannot be replayed
+	Status     int    `json:"status"`              // HTTP status returned by the handler
+	Duplicate  bool   `json:"duplicate,omitempty"` // Skipped as an already processed delivery
+	ReceivedAt int64  `json:"receivedAt"`          // Unix millis
+}
+
+// Lookup index kinds reported by ListIndexEntries.
+const (
+	IndexKindThread            = "thread"            // Root post ID -> agent ID or workflow ID
+	IndexKindAgentWorkflow     = "agent_workflow"    // Agent ID -> workflow ID
+	IndexKindPRURL             = "pr_url"            // PR URL -> agent ID
+	IndexKindBranch            = "branch"            // Branch name -> agent ID
+	IndexKindActiveAgent       = "active_agent"      // Active agent ID -> agent ID
+	IndexKindUserAgent         = "user_agent"        // User ID and agent ID -> agent ID
+	IndexKindReviewLoopByPR    = "review_loop_pr"    // PR URL -> review loop ID
+	IndexKindReviewLoopByAgent = "review_loop_agent" // Agent ID -> review loop ID
+	IndexKindWebhookDelivery   = "webhook_delivery"  // Recorded webhook delivery
+)
+
+// Kinds of record an index entry can reference.
+const (
+	IndexTargetAgent       = "agent"
+	IndexTargetWorkflow    = "workflow"
+	IndexTargetReviewLoop  = "review_loop"
+	IndexTargetDeliveryLog = "delivery_log" // The recorded webhook delivery log
+)
+
+// IndexEntry is a lookup index key and the record it references. Entries
+// whose target no longer exists are orphans left behind by deleted records.
+type IndexEntry struct {
+	Kind       string
+	Key        string // Full KV key
+	TargetKind string
+	TargetID   string
+}
+
 // ImageRef is a serializable reference to a prompt image. Full image data
 // is stored in Mattermost file storage and re-fetched by file ID when needed.
 type ImageRef struct {
@@ -95,26 +208,50 @@ type ImageRef struct {
 	Height int    `json:"height"`
 }
 
+// DroppedCandidate is a review feedback candidate that was dropped before
+// classification, e.g. because no actionable text could be extracted.
+type DroppedCandidate struct {
+	Reason        string `json:"reason"` // Drop reason, e.g. "ignored_path"
+	Route         string `json:"route"`  // Extraction route, e.g. "coderabbit"
+	SourceType    string `json:"sourceType"`
+	SourceID      int64  `json:"sourceId,omitempty"`
+	SourceURL     string `json:"sourceUrl,omitempty"`
+	ReviewerLogin string `json:"reviewerLogin,omitempty"`
+	Path          string `json:"path,omitempty"`
+	Line          int    `json:"line,omitempty"`
+	CommitSHA     string `json:"commitSha,omitempty"`
+	Iteration     int    `json:"iteration"`
+	DroppedAt     int64  `json:"droppedAt"` // Unix millis of the latest drop
+}
+
 // ReviewLoop tracks the automated AI review cycle for a Cursor-created PR.
 // Separate from AgentRecord and HITLWorkflow. Linked back via AgentRecordID.
 type ReviewFinding struct {
-	Key                string `json:"key,omitempty"`                // Stable fingerprint for dedupe tracking
-	Status             string `json:"status,omitempty"`             // open|resolved|dismissed|superseded
-	SourceType         string `json:"sourceType,omitempty"`         // review_comment|review_body|issue_comment
-	SourceID           int64  `json:"sourceId,omitempty"`           // Numeric source comment/review ID
-	SourceNodeID       string `json:"sourceNodeId,omitempty"`       // GitHub node ID for traceability
-	SourceURL          string `json:"sourceUrl,omitempty"`          // GitHub HTML URL
-	ReviewerLogin      string `json:"reviewerLogin,omitempty"`      // GitHub login of feedback author
-	ReviewerType       string `json:"reviewerType,omitempty"`       // ai_bot|human
-	Path               string `json:"path,omitempty"`               // File path for inline comments
-	Line               int    `json:"line,omitempty"`               // File line for inline comments
-	CommitSHA          string `json:"commitSha,omitempty"`          // Commit SHA associated with finding
-	RawText            string `json:"rawText,omitempty"`            // Raw reviewer text (may be truncated)
-	ActionableText     string `json:"actionableText,omitempty"`     // Extracted actionable directive
-	FirstSeenAt        int64  `json:"firstSeenAt,omitempty"`        // Unix millis
-	LastSeenAt         int64  `json:"lastSeenAt,omitempty"`         // Unix millis
-	FirstSeenIteration int    `json:"firstSeenIteration,omitempty"` // Review-loop iteration first observed
-	LastSeenIteration  int    `json:"lastSeenIteration,omitempty"`  // Review-loop iteration last observed
+	Key                   string `json:"key,omitempty"`                   // Stable fingerprint for dedupe tracking
+	ShortID               string `json:"shortId,omitempty"`               // Short reference ID shown to the agent (e.g. RF-1a2b3c4d)
+	Status                string `json:"status,omitempty"`                // open|resolved|dismissed|superseded
+	ResolvedBy            string `json:"resolvedBy,omitempty"`            // reference|absence; set when Status is resolved
+	SourceType            string `json:"sourceType,omitempty"`            // review_comment|review_body|issue_comment
+	SourceID              int64  `json:"sourceId,omitempty"`              // Numeric source comment/review ID
+	SourceNodeID          string `json:"sourceNodeId,omitempty"`          // GitHub node ID for traceability
+	SourceURL             string `json:"sourceUrl,omitempty"`             // GitHub HTML URL
+	ThreadID              string `json:"threadId,omitempty"`              // GraphQL review thread ID for inline comments
+	ReviewerLogin         string `json:"reviewerLogin,omitempty"`         // GitHub login of feedback author
+	ReviewerType          string `json:"reviewerType,omitempty"`          // ai_bot|human
+	Path                  string `json:"path,omitempty"`                  // File path for inline comments
+	Line                  int    `json:"line,omitempty"`                  // File line for inline comments
+	CommitSHA             string `json:"commitSha,omitempty"`             // Commit SHA associated with finding
+	RawText               string `json:"rawText,omitempty"`               // Raw reviewer text (may be truncated)
+	ActionableText        string `json:"actionableText,omitempty"`        // Extracted actionable directive
+	Suggestion            string `json:"suggestion,omitempty"`            // Replacement code from a ```suggestion block
+	Severity              string `json:"severity,omitempty"`              // nit|minor|major|critical; empty when unlabeled
+	FirstSeenAt           int64  `json:"firstSeenAt,omitempty"`           // Unix millis
+	LastSeenAt            int64  `json:"lastSeenAt,omitempty"`            // Unix millis
+	FirstSeenIteration    int    `json:"firstSeenIteration,omitempty"`    // Review-loop iteration first observed
+	LastSeenIteration     int    `json:"lastSeenIteration,omitempty"`     // Review-loop iteration last observed
+	HeldBack              bool   `json:"heldBack,omitempty"`              // Held back by MaxFindingsPerIteration; sent in a later iteration
+	SnoozedUntilIteration int    `json:"snoozedUntilIteration,omitempty"` // Not dispatched while the loop iteration is at or below this
+	AcknowledgedAt        int64  `json:"acknowledgedAt,omitempty"`        // Unix millis the dispatch acknowledgement reply was posted
 }
 
 type ReviewLoop struct {
@@ -129,14 +266,17 @@ type ReviewLoop struct {
 	// PR info (populated from AgentRecord + parsed PR URL)
 	PRURL      string `json:"prUrl"`
 	PRNumber   int    `json:"prNumber"`
-	Repository string `json:"repository"` // "owner/repo"
-	Owner      string `json:"owner"`      // Parsed from PR URL
-	Repo       string `json:"repo"`       // Parsed from PR URL
+	Repository string `json:"repository"`           // "owner/repo"
+	Owner      string `json:"owner"`                // Parsed from PR URL
+	Repo       string `json:"repo"`                 // Parsed from PR URL
+	BaseBranch string `json:"baseBranch,omitempty"` // PR base branch, re-synced when the base is edited
 
 	// State machine
 	Phase     string `json:"phase"`     // See ReviewPhase* constants
 	Iteration int    `json:"iteration"` // Current fix-review iteration (starts at 1)
 
+	IterationWarningSent bool `json:"iterationWarningSent,omitempty"` // Iteration warning threshold notice already posted
+
 	// Tracking
 	LastCommitSHA string `json:"lastCommitSha,omitempty"` // HEAD SHA we last saw
 
@@ -147,6 +287,89 @@ type ReviewLoop struct {
 	FeedbackCursor          string          `json:"feedbackCursor,omitempty"`          // Reserved for paging/cursor strategies
 	Findings                []ReviewFinding `json:"findings,omitempty"`                // Persisted bounded finding history
 
+	// Recent feedback candidates dropped before classification, bounded and
+	// newest last, so users can audit why feedback was not actioned.
+	DroppedCandidates []DroppedCandidate `json:"droppedCandidates,omitempty"`
+
+	// In-progress dispatch checkpoint, persisted before AddFollowup and cleared
+	// once dispatch tracking is recorded. A checkpoint that survives a restart
+	// is reconciled against the agent conversation before re-dispatching.
+	PendingDispatchID     string `json:"pendingDispatchId,omitempty"`
+	PendingDispatchAt     int64  `json:"pendingDispatchAt,omitempty"` // Unix millis
+	PendingDispatchSHA    string `json:"pendingDispatchSha,omitempty"`
+	PendingDispatchDigest string `json:"pendingDispatchDigest,omitempty"`
+	// PendingDispatchPromptHash fingerprints the follow-up prompt so delivery
+	// can be confirmed from the conversation without tagging the prompt.
+	PendingDispatchPromptHash string `json:"pendingDispatchPromptHash,omitempty"`
+
+	// Quiet hours deferral. A dispatch or notification held during quiet hours
+	// is released by the poller once the window ends.
+	QuietHoursDeferredAt      int64    `json:"quietHoursDeferredAt,omitempty"`      // Unix millis of the first held item
+	QuietHoursDispatchPending bool     `json:"quietHoursDispatchPending,omitempty"` // A feedback dispatch is waiting
+	QuietHoursDispatchSHA     string   `json:"quietHoursDispatchSha,omitempty"`     // PR head SHA at deferral time
+	QuietHoursDispatchRef     string   `json:"quietHoursDispatchRef,omitempty"`     // PR head branch at deferral time
+	QuietHoursDigest          []string `json:"quietHoursDigest,omitempty"`          // Held notification titles
+
+	// GitHub outage deferral. A dispatch that could not collect feedback
+	// because the GitHub circuit breaker was open is retried by the poller.
+	GitHubRetryPending bool   `json:"githubRetryPending,omitempty"` // A feedback dispatch is waiting on GitHub
+	GitHubRetrySHA     string `json:"githubRetrySha,omitempty"`     // PR head SHA at deferral time
+	GitHubRetryRef     string `json:"githubRetryRef,omitempty"`     // PR head branch at deferral time
+
+	// Cursor rate limit deferral. A follow-up rejected with HTTP 429 is
+	// retried by the poller once RateLimitRetryAt passes, up to a bounded
+	// number of attempts before the dispatch fails.
+	RateLimitRetryPending  bool   `json:"rateLimitRetryPending,omitempty"`  // A feedback dispatch is waiting out a rate limit
+	RateLimitRetryAt       int64  `json:"rateLimitRetryAt,omitempty"`       // Unix millis when the retry is due
+	RateLimitRetryAttempts int    `json:"rateLimitRetryAttempts,omitempty"` // Consecutive rate-limited dispatch attempts
+	RateLimitRetrySHA      string `json:"rateLimitRetrySha,omitempty"`      // PR head SHA at deferral time
+	RateLimitRetryRef      string `json:"rateLimitRetryRef,omitempty"`      // PR head branch at deferral time
+
+	// Idempotency window. A feedback dispatch within IdempotencyWindowSeconds
+	// of the last one is held, and held dispatches are coalesced into a single
+	// dispatch by the poller once the window has passed.
+	IdempotencyWindowSeconds int    `json:"idempotencyWindowSeconds,omitempty"` // 0 disables the window
+	CoalescePending          bool   `json:"coalescePending,omitempty"`          // A feedback dispatch is held by the window
+	CoalesceSHA              string `json:"coalesceSha,omitempty"`              // PR head SHA at the latest held dispatch
+	CoalesceRef              string `json:"coalesceRef,omitempty"`              // PR head branch at the latest held dispatch
+
+	// Consecutive feedback follow-ups that finished without new commits on the
+	// PR, e.g. because the branch is protected. Reset by the next push.
+	PushFailureCount int `json:"pushFailureCount,omitempty"`
+
+	// Merge conflicts. Set while the PR is unmergeable and Cursor has been
+	// asked to resolve the conflicts; cleared once the PR is mergeable again.
+	MergeConflictAt  int64  `json:"mergeConflictAt,omitempty"`  // Unix millis the conflict was detected
+	MergeConflictSHA string `json:"mergeConflictSha,omitempty"` // PR head SHA the resolution was dispatched for
+
+	// Auto-merge held after AI approval because CI was not green yet. The
+	// poller merges once CI on this head passes; cleared when the merge is
+	// attempted or the hold no longer applies.
+	AutoMergeHeldSHA string `json:"autoMergeHeldSha,omitempty"`
+
+	// Global pause. The latest review received while all review loops were
+	// paused; the poller replays it once the pause is lifted.
+	GlobalPauseHeld *HeldReview `json:"globalPauseHeld,omitempty"`
+
+	// The latest push to the PR received while all review loops were paused;
+	// replayed before any held review once the pause is lifted.
+	GlobalPauseHeldPush *HeldPush `json:"globalPauseHeldPush,omitempty"`
+
+	// Time spent under a global pause, which does not count against the
+	// loop's maximum lifetime.
+	LifetimePausedAt     int64 `json:"lifetimePausedAt,omitempty"`     // Unix millis the current pause was first seen
+	LifetimePausedMillis int64 `json:"lifetimePausedMillis,omitempty"` // Total of earlier pauses
+
+	// Stale escalation. A loop waiting on reviewers past the configured
+	// threshold is escalated once per wait unless snoozed.
+	StaleEscalatedAt int64 `json:"staleEscalatedAt,omitempty"` // Unix millis of the last escalation
+	SnoozeUntil      int64 `json:"snoozeUntil,omitempty"`      // Unix millis; escalation is suppressed until then
+
+	// Reviewer nudges. A loop waiting on AI reviewers past the configured
+	// threshold pings them with a PR comment, up to a per-loop limit.
+	NudgeCount   int   `json:"nudgeCount,omitempty"`
+	LastNudgedAt int64 `json:"lastNudgedAt,omitempty"` // Unix millis of the last nudge
+
 	// Timeline (append-only log of phase transitions for dashboard display)
 	History []ReviewLoopEvent `json:"history,omitempty"`
 
@@ -154,11 +377,41 @@ type ReviewLoop struct {
 	UpdatedAt int64 `json:"updatedAt"` // Unix millis
 }
 
+// HeldReview is a PR review whose handling was postponed.
+type HeldReview struct {
+	ReviewerLogin string `json:"reviewerLogin"`
+	State         string `json:"state"`
+	Body          string `json:"body,omitempty"`
+	HTMLURL       string `json:"htmlUrl,omitempty"`
+	HeadSHA       string `json:"headSha,omitempty"`
+	HeadRef       string `json:"headRef,omitempty"`
+	HeldAt        int64  `json:"heldAt"` // Unix millis
+}
+
+// HeldPush is a push to a PR held on a review loop while review loops are
+// globally paused.
+type HeldPush struct {
+	HeadSHA string `json:"headSha"`
+	HeadRef string `json:"headRef,omitempty"`
+	HeldAt  int64  `json:"heldAt"` // Unix millis
+}
+
 // ReviewLoopEvent records a single phase transition for the dashboard timeline.
 type ReviewLoopEvent struct {
 	Phase     string `json:"phase"`
 	Timestamp int64  `json:"timestamp"`        // Unix millis
 	Detail    string `json:"detail,omitempty"` // e.g., "3 comments", "approved after 2 iterations"
+	Kind      string `json:"kind,omitempty"`   // Marks events the loop's logic looks back for, e.g. "ai_gate_reopened"
+
+	// Structured feedback dispatch outcome, set on events recorded when review
+	// feedback is dispatched, skipped, deferred, or fails. Detail repeats it
+	// for display.
+	Mode        string `json:"mode,omitempty"` // Dispatch mode, e.g. "direct", "skipped_idempotent"
+	New         int    `json:"new,omitempty"`
+	Repeated    int    `json:"repeated,omitempty"`
+	Dismissed   int    `json:"dismissed,omitempty"`
+	DispatchSHA string `json:"dispatchSha,omitempty"`
+	Digest      string `json:"digest,omitempty"`
 }
 
 // HITL workflow phase constants.
@@ -181,8 +434,27 @@ const (
 	ReviewPhaseComplete         = "complete"          // Human approved (terminal)
 	ReviewPhaseMaxIterations    = "max_iterations"    // Safety limit hit (terminal)
 	ReviewPhaseFailed           = "failed"            // Error during review loop (terminal)
+	ReviewPhaseStalled          = "stalled"           // Exceeded its maximum lifetime (terminal)
+	ReviewPhaseError            = "error"             // Unrecoverable loop state; owner can reset to awaiting_review
 )
 
+// IsReviewPhaseWaiting reports whether a review loop in the given phase is
+// blocked on reviewers rather than on Cursor or the plugin.
+func IsReviewPhaseWaiting(phase string) bool {
+	return phase == ReviewPhaseAwaitingReview || phase == ReviewPhaseHumanReview
+}
+
+// IsReviewPhaseTerminal reports whether a review loop in the given phase has
+// finished for good.
+func IsReviewPhaseTerminal(phase string) bool {
+	switch phase {
+	case ReviewPhaseComplete, ReviewPhaseMaxIterations, ReviewPhaseFailed, ReviewPhaseStalled:
+		return true
+	default:
+		return false
+	}
+}
+
 // KVStore defines the storage interface for the plugin.
 type KVStore interface {
 	// Agent records
@@ -191,6 +463,9 @@ type KVStore interface {
 	DeleteAgent(cursorAgentID string) error
 	ListActiveAgents() ([]*AgentRecord, error)
 	GetAgentsByUser(userID string) ([]*AgentRecord, error)
+	// RemoveAgentFromUser drops the per-user index entry tying an agent to a
+	// former owner, e.g. after an ownership transfer.
+	RemoveAgentFromUser(userID, cursorAgentID string) error
 
 	// Agent lookup by PR URL or branch (Phase 6: GitHub webhook support)
 	GetAgentByPRURL(prURL string) (*AgentRecord, error)
@@ -208,15 +483,26 @@ type KVStore interface {
 	// User settings
 	GetUserSettings(userID string) (*UserSettings, error)
 	SaveUserSettings(userID string, settings *UserSettings) error
+	DeleteUserSettings(userID string) error
+
+	// Per-user launch history
+	GetUserLaunchHistory(userID string) (*UserLaunchHistory, error)
+	AddUserLaunchHistoryEntry(userID string, entry LaunchHistoryEntry, keep int) error
 
 	// Idempotency (Phase 6: GitHub webhook dedup)
 	HasDeliveryBeenProcessed(deliveryID string) (bool, error)
 	MarkDeliveryProcessed(deliveryID string) error
 
+	// Recent webhook deliveries (debugging)
+	SaveWebhookDelivery(delivery *WebhookDelivery, keep int) error
+	GetWebhookDelivery(deliveryID string) (*WebhookDelivery, error)
+	ListWebhookDeliveries() ([]*WebhookDelivery, error)
+
 	// HITL workflow records
 	GetWorkflow(workflowID string) (*HITLWorkflow, error)
 	SaveWorkflow(workflow *HITLWorkflow) error
 	DeleteWorkflow(workflowID string) error
+	ListWorkflows() ([]*HITLWorkflow, error)
 
 	// HITL workflow lookups
 	GetWorkflowByThread(rootPostID string) (*HITLWorkflow, error)
@@ -233,7 +519,23 @@ type KVStore interface {
 	// ReviewLoop lookups
 	GetReviewLoopByPRURL(prURL string) (*ReviewLoop, error)
 	GetReviewLoopByAgent(agentRecordID string) (*ReviewLoop, error)
+	ListQuietHoursDeferredReviewLoops() ([]*ReviewLoop, error)
+	ListGitHubRetryReviewLoops() ([]*ReviewLoop, error)
+	ListRateLimitedReviewLoops() ([]*ReviewLoop, error)
+	ListPendingDispatchReviewLoops() ([]*ReviewLoop, error)
+	ListGloballyPausedReviewLoops() ([]*ReviewLoop, error)
+	ListWaitingReviewLoops() ([]*ReviewLoop, error)
+	ListFixingReviewLoops() ([]*ReviewLoop, error)
+	ListActiveReviewLoops() ([]*ReviewLoop, error)
+
+	// Scheduled review loop digest
+	GetReviewLoopDigestDate() (string, error)
+	SetReviewLoopDigestDate(date string) error
 
 	// Janitor indexes
 	GetAllFinishedAgentsWithPR() ([]*AgentRecord, error)
+
+	// Index maintenance (/cursor admin gc)
+	ListIndexEntries() ([]IndexEntry, error)
+	DeleteIndexEntry(key string) error
 }
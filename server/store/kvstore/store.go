@@ -10,23 +10,57 @@ import (
 
 // Key prefixes for the KV store.
 const (
-	prefixAgent        = "agent:"
-	prefixThread       = "thread:"
-	prefixChannel      = "channel:"
-	prefixUser         = "user:"
-	prefixAgentIdx     = "agentidx:"     // Index for listing active agents
-	prefixUserAgentIdx = "useragentidx:" // Index for listing agents by user
-	prefixPRURLIdx     = "prurlidx:"     // Index for PR URL -> agent ID lookup
-	prefixBranchIdx    = "branchidx:"    // Index for branch name -> agent ID lookup
-	prefixDelivery     = "ghdelivery:"   // Idempotency key for GitHub webhook deliveries
-	prefixHITL         = "hitl:"         // HITL workflow records
-	prefixHITLAgent    = "hitlagent:"    // Reverse index: Cursor agent ID -> workflow ID
-	prefixReviewLoop   = "reviewloop:"   // ReviewLoop records
-	prefixRLByPR       = "rlbypr:"       // PR URL -> ReviewLoop ID index
-	prefixRLByAgent      = "rlbyagent:"    // Agent record ID -> ReviewLoop ID index
-	prefixFinishedWithPR = "finishedpr:"   // Index for FINISHED agents with PrURL (janitor)
+	prefixAgent             = "agent:"
+	prefixThread            = "thread:"
+	prefixChannel           = "channel:"
+	prefixUser              = "user:"
+	prefixLaunchHist        = "launchhist:"   // Per-user recent launches
+	prefixAgentIdx          = "agentidx:"     // Index for listing active agents
+	prefixUserAgentIdx      = "useragentidx:" // Index for listing agents by user
+	prefixPRURLIdx          = "prurlidx:"     // Index for PR URL -> agent ID lookup
+	prefixBranchIdx         = "branchidx:"    // Index for branch name -> agent ID lookup
+	prefixDelivery          = "ghdelivery:"   // Idempotency key for GitHub webhook deliveries
+	prefixHITL              = "hitl:"         // HITL workflow records
+	prefixHITLAgent         = "hitlagent:"    // Reverse index: Cursor agent ID -> workflow ID
+	prefixReviewLoop        = "reviewloop:"   // ReviewLoop records
+	prefixRLByPR            = "rlbypr:"       // PR URL -> ReviewLoop ID index
+	prefixRLByAgent         = "rlbyagent:"    // Agent record ID -> ReviewLoop ID index
+	prefixFinishedWithPR    = "finishedpr:"   // Index for FINISHED agents with PrURL (janitor)
+	prefixRLQuietHours      = "rlquiet:"      // ReviewLoops holding work until quiet hours end
+	prefixRLGitHubRetry     = "rlghretry:"    // ReviewLoops waiting on GitHub to recover
+	prefixRLRateLimit       = "rlratelimit:"  // ReviewLoops waiting out a Cursor rate limit
+	prefixRLPendingDispatch = "rlpending:"    // ReviewLoops with an unreconciled dispatch checkpoint
+	prefixRLPaused          = "rlpaused:"     // ReviewLoops holding a review or push until the global pause is lifted
+	prefixRLWaiting         = "rlwaiting:"    // ReviewLoops waiting on reviewers (stale sweep)
+	prefixRLFixing          = "rlfixing:"     // ReviewLoops waiting on Cursor to push fixes
+	prefixWebhookDelivery   = "whdelivery:"   // Recorded webhook deliveries (debugging)
+	prefixRLActive          = "rlactive:"     // Non-terminal ReviewLoops (scheduled digest)
+	keyRLDigestDate         = "rldigest:last" // Local date of the last scheduled review loop digest
+	keyWebhookDeliveryLog   = "whdeliverylog" // Recorded delivery IDs, oldest first
 )
 
+// indexListPageSize is the number of keys fetched per page when scanning the
+// whole store for lookup index keys.
+const indexListPageSize = 1000
+
+// lookupIndexes describes the index prefixes scanned by ListIndexEntries and
+// the kind of record each one references.
+var lookupIndexes = []struct {
+	prefix     string
+	kind       string
+	targetKind string
+}{
+	{prefixThread, IndexKindThread, IndexTargetAgent},
+	{prefixHITLAgent, IndexKindAgentWorkflow, IndexTargetWorkflow},
+	{prefixPRURLIdx, IndexKindPRURL, IndexTargetAgent},
+	{prefixBranchIdx, IndexKindBranch, IndexTargetAgent},
+	{prefixAgentIdx, IndexKindActiveAgent, IndexTargetAgent},
+	{prefixUserAgentIdx, IndexKindUserAgent, IndexTargetAgent},
+	{prefixRLByPR, IndexKindReviewLoopByPR, IndexTargetReviewLoop},
+	{prefixRLByAgent, IndexKindReviewLoopByAgent, IndexTargetReviewLoop},
+	{prefixWebhookDelivery, IndexKindWebhookDelivery, IndexTargetDeliveryLog},
+}
+
 // hitlThreadPrefix is prepended to workflow IDs when stored in thread mappings
 // to distinguish them from bare agent IDs.
 const hitlThreadPrefix = "hitl:"
@@ -88,6 +122,9 @@ func (s *store) SaveAgent(record *AgentRecord) error {
 	if record.PrURL != "" {
 		_, _ = s.client.KV.Set(prefixPRURLIdx+normalizeURL(record.PrURL), record.CursorAgentID)
 	}
+	for _, extraURL := range record.ExtraPRURLs {
+		_, _ = s.client.KV.Set(prefixPRURLIdx+normalizeURL(extraURL), record.CursorAgentID)
+	}
 
 	// Maintain branch index for GitHub webhook lookup.
 	if record.TargetBranch != "" {
@@ -122,6 +159,13 @@ func (s *store) DeleteAgent(cursorAgentID string) error {
 	return nil
 }
 
+func (s *store) RemoveAgentFromUser(userID, cursorAgentID string) error {
+	if err := s.client.KV.Delete(prefixUserAgentIdx + userID + ":" + cursorAgentID); err != nil {
+		return errors.Wrap(err, "failed to delete user agent index")
+	}
+	return nil
+}
+
 func (s *store) GetAgentsByUser(userID string) ([]*AgentRecord, error) {
 	prefix := prefixUserAgentIdx + userID + ":"
 	keys, err := s.client.KV.ListKeys(0, 1000, pluginapi.WithPrefix(prefix))
@@ -133,12 +177,14 @@ func (s *store) GetAgentsByUser(userID string) ([]*AgentRecord, error) {
 	for _, key := range keys {
 		agentID := strings.TrimPrefix(key, prefix)
 		record, err := s.GetAgent(agentID)
-		if err != nil {
+		if err != nil || record == nil {
 			continue
 		}
-		if record != nil {
-			agents = append(agents, record)
+		if record.UserID != userID {
+			_ = s.client.KV.Delete(key) // Stale entry left behind for a previous owner.
+			continue
 		}
+		agents = append(agents, record)
 	}
 	return agents, nil
 }
@@ -246,6 +292,52 @@ func (s *store) SaveUserSettings(userID string, settings *UserSettings) error {
 	return nil
 }
 
+func (s *store) GetUserLaunchHistory(userID string) (*UserLaunchHistory, error) {
+	var history UserLaunchHistory
+	err := s.client.KV.Get(prefixLaunchHist+userID, &history)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get user launch history")
+	}
+	return &history, nil
+}
+
+// AddUserLaunchHistoryEntry records a launch as the user's newest history
+// entry and keeps only the newest keep entries. An identical earlier launch
+// is moved to the front instead of being listed twice.
+func (s *store) AddUserLaunchHistoryEntry(userID string, entry LaunchHistoryEntry, keep int) error {
+	history, err := s.GetUserLaunchHistory(userID)
+	if err != nil {
+		return err
+	}
+
+	entries := make([]LaunchHistoryEntry, 0, len(history.Entries)+1)
+	entries = append(entries, entry)
+	for _, existing := range history.Entries {
+		if existing.Prompt == entry.Prompt && existing.Repository == entry.Repository &&
+			existing.Branch == entry.Branch && existing.Model == entry.Model {
+			continue
+		}
+		entries = append(entries, existing)
+	}
+	if keep > 0 && len(entries) > keep {
+		entries = entries[:keep]
+	}
+
+	history.Entries = entries
+	if _, err := s.client.KV.Set(prefixLaunchHist+userID, history); err != nil {
+		return errors.Wrap(err, "failed to save user launch history")
+	}
+	return nil
+}
+
+func (s *store) DeleteUserSettings(userID string) error {
+	err := s.client.KV.Delete(prefixUser + userID)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete user settings")
+	}
+	return nil
+}
+
 func (s *store) HasDeliveryBeenProcessed(deliveryID string) (bool, error) {
 	var seen bool
 	err := s.client.KV.Get(prefixDelivery+deliveryID, &seen)
@@ -263,6 +355,75 @@ func (s *store) MarkDeliveryProcessed(deliveryID string) error {
 	return nil
 }
 
+// SaveWebhookDelivery records a delivery and evicts the oldest recorded
+// deliveries beyond keep. The ID log is updated without locking; concurrent
+// deliveries may occasionally drop an entry, which is acceptable for a
+// debugging aid.
+func (s *store) SaveWebhookDelivery(delivery *WebhookDelivery, keep int) error {
+	if _, err := s.client.KV.Set(prefixWebhookDelivery+delivery.DeliveryID, delivery); err != nil {
+		return errors.Wrap(err, "failed to save webhook delivery")
+	}
+
+	var ids []string
+	if err := s.client.KV.Get(keyWebhookDeliveryLog, &ids); err != nil {
+		return errors.Wrap(err, "failed to get webhook delivery log")
+	}
+
+	kept := make([]string, 0, len(ids)+1)
+	for _, id := range ids {
+		if id != delivery.DeliveryID {
+			kept = append(kept, id)
+		}
+	}
+	kept = append(kept, delivery.DeliveryID)
+
+	if keep > 0 && len(kept) > keep {
+		for _, id := range kept[:len(kept)-keep] {
+			if err := s.client.KV.Delete(prefixWebhookDelivery + id); err != nil {
+				return errors.Wrap(err, "failed to evict webhook delivery")
+			}
+		}
+		kept = kept[len(kept)-keep:]
+	}
+
+	if _, err := s.client.KV.Set(keyWebhookDeliveryLog, kept); err != nil {
+		return errors.Wrap(err, "failed to save webhook delivery log")
+	}
+	return nil
+}
+
+func (s *store) GetWebhookDelivery(deliveryID string) (*WebhookDelivery, error) {
+	var delivery WebhookDelivery
+	err := s.client.KV.Get(prefixWebhookDelivery+deliveryID, &delivery)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get webhook delivery")
+	}
+	if delivery.DeliveryID == "" {
+		return nil, nil // Not found
+	}
+	return &delivery, nil
+}
+
+// ListWebhookDeliveries returns the recorded deliveries, newest first.
+func (s *store) ListWebhookDeliveries() ([]*WebhookDelivery, error) {
+	var ids []string
+	if err := s.client.KV.Get(keyWebhookDeliveryLog, &ids); err != nil {
+		return nil, errors.Wrap(err, "failed to get webhook delivery log")
+	}
+
+	deliveries := make([]*WebhookDelivery, 0, len(ids))
+	for i := len(ids) - 1; i >= 0; i-- {
+		delivery, err := s.GetWebhookDelivery(ids[i])
+		if err != nil {
+			return nil, err
+		}
+		if delivery != nil {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	return deliveries, nil
+}
+
 func (s *store) GetWorkflow(workflowID string) (*HITLWorkflow, error) {
 	var workflow HITLWorkflow
 	err := s.client.KV.Get(prefixHITL+workflowID, &workflow)
@@ -291,6 +452,26 @@ func (s *store) DeleteWorkflow(workflowID string) error {
 	return nil
 }
 
+// ListWorkflows returns every stored HITL workflow, regardless of phase.
+func (s *store) ListWorkflows() ([]*HITLWorkflow, error) {
+	keys, err := s.client.KV.ListKeys(0, 1000, pluginapi.WithPrefix(prefixHITL))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list workflow keys")
+	}
+
+	var workflows []*HITLWorkflow
+	for _, key := range keys {
+		workflow, err := s.GetWorkflow(strings.TrimPrefix(key, prefixHITL))
+		if err != nil {
+			continue // Skip errored records
+		}
+		if workflow != nil {
+			workflows = append(workflows, workflow)
+		}
+	}
+	return workflows, nil
+}
+
 func (s *store) GetWorkflowByThread(rootPostID string) (*HITLWorkflow, error) {
 	var value string
 	err := s.client.KV.Get(prefixThread+rootPostID, &value)
@@ -374,6 +555,70 @@ func (s *store) SaveReviewLoop(loop *ReviewLoop) error {
 		}
 	}
 
+	// Maintain quiet hours index. Stale entries are cleaned up on listing.
+	if loop.QuietHoursDeferredAt > 0 {
+		_, err = s.client.KV.Set(prefixRLQuietHours+loop.ID, loop.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to save review loop quiet hours index")
+		}
+	}
+
+	// Maintain GitHub retry index. Stale entries are cleaned up on listing.
+	if loop.GitHubRetryPending {
+		_, err = s.client.KV.Set(prefixRLGitHubRetry+loop.ID, loop.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to save review loop GitHub retry index")
+		}
+	}
+
+	// Maintain Cursor rate limit index. Stale entries are cleaned up on listing.
+	if loop.RateLimitRetryPending {
+		_, err = s.client.KV.Set(prefixRLRateLimit+loop.ID, loop.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to save review loop rate limit index")
+		}
+	}
+
+	// Maintain dispatch checkpoint index. Stale entries are cleaned up on listing.
+	if loop.PendingDispatchAt > 0 {
+		_, err = s.client.KV.Set(prefixRLPendingDispatch+loop.ID, loop.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to save review loop dispatch checkpoint index")
+		}
+	}
+
+	// Maintain global pause index. Stale entries are cleaned up on listing.
+	if loop.GlobalPauseHeld != nil || loop.GlobalPauseHeldPush != nil {
+		_, err = s.client.KV.Set(prefixRLPaused+loop.ID, loop.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to save review loop global pause index")
+		}
+	}
+
+	// Maintain waiting-on-reviewers index. Stale entries are cleaned up on listing.
+	if IsReviewPhaseWaiting(loop.Phase) {
+		_, err = s.client.KV.Set(prefixRLWaiting+loop.ID, loop.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to save review loop waiting index")
+		}
+	}
+
+	// Maintain cursor_fixing index. Stale entries are cleaned up on listing.
+	if loop.Phase == ReviewPhaseCursorFixing {
+		_, err = s.client.KV.Set(prefixRLFixing+loop.ID, loop.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to save review loop fixing index")
+		}
+	}
+
+	// Maintain active (non-terminal) index. Stale entries are cleaned up on listing.
+	if !IsReviewPhaseTerminal(loop.Phase) {
+		_, err = s.client.KV.Set(prefixRLActive+loop.ID, loop.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to save review loop active index")
+		}
+	}
+
 	// Remove from janitor index since a loop now exists for this agent.
 	if loop.AgentRecordID != "" {
 		_ = s.client.KV.Delete(prefixFinishedWithPR + loop.AgentRecordID)
@@ -444,3 +689,233 @@ func (s *store) GetAllFinishedAgentsWithPR() ([]*AgentRecord, error) {
 	}
 	return agents, nil
 }
+
+func (s *store) ListQuietHoursDeferredReviewLoops() ([]*ReviewLoop, error) {
+	keys, err := s.client.KV.ListKeys(0, 1000, pluginapi.WithPrefix(prefixRLQuietHours))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list quiet hours review loop keys")
+	}
+	var loops []*ReviewLoop
+	for _, key := range keys {
+		reviewLoopID := strings.TrimPrefix(key, prefixRLQuietHours)
+		loop, err := s.GetReviewLoop(reviewLoopID)
+		if err != nil || loop == nil || loop.QuietHoursDeferredAt == 0 {
+			_ = s.client.KV.Delete(key) // Clean up released or orphaned index entry.
+			continue
+		}
+		loops = append(loops, loop)
+	}
+	return loops, nil
+}
+
+func (s *store) ListGitHubRetryReviewLoops() ([]*ReviewLoop, error) {
+	keys, err := s.client.KV.ListKeys(0, 1000, pluginapi.WithPrefix(prefixRLGitHubRetry))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list GitHub retry review loop keys")
+	}
+	var loops []*ReviewLoop
+	for _, key := range keys {
+		reviewLoopID := strings.TrimPrefix(key, prefixRLGitHubRetry)
+		loop, err := s.GetReviewLoop(reviewLoopID)
+		if err != nil || loop == nil || !loop.GitHubRetryPending {
+			_ = s.client.KV.Delete(key) // Clean up retried or orphaned index entry.
+			continue
+		}
+		loops = append(loops, loop)
+	}
+	return loops, nil
+}
+
+func (s *store) ListRateLimitedReviewLoops() ([]*ReviewLoop, error) {
+	keys, err := s.client.KV.ListKeys(0, 1000, pluginapi.WithPrefix(prefixRLRateLimit))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list rate limited review loop keys")
+	}
+	var loops []*ReviewLoop
+	for _, key := range keys {
+		reviewLoopID := strings.TrimPrefix(key, prefixRLRateLimit)
+		loop, err := s.GetReviewLoop(reviewLoopID)
+		if err != nil || loop == nil || !loop.RateLimitRetryPending {
+			_ = s.client.KV.Delete(key) // Clean up retried or orphaned index entry.
+			continue
+		}
+		loops = append(loops, loop)
+	}
+	return loops, nil
+}
+
+func (s *store) ListPendingDispatchReviewLoops() ([]*ReviewLoop, error) {
+	keys, err := s.client.KV.ListKeys(0, 1000, pluginapi.WithPrefix(prefixRLPendingDispatch))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list dispatch checkpoint review loop keys")
+	}
+	var loops []*ReviewLoop
+	for _, key := range keys {
+		reviewLoopID := strings.TrimPrefix(key, prefixRLPendingDispatch)
+		loop, err := s.GetReviewLoop(reviewLoopID)
+		if err != nil || loop == nil || loop.PendingDispatchAt == 0 {
+			_ = s.client.KV.Delete(key) // Clean up reconciled or orphaned index entry.
+			continue
+		}
+		loops = append(loops, loop)
+	}
+	return loops, nil
+}
+
+func (s *store) ListGloballyPausedReviewLoops() ([]*ReviewLoop, error) {
+	keys, err := s.client.KV.ListKeys(0, 1000, pluginapi.WithPrefix(prefixRLPaused))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list globally paused review loop keys")
+	}
+	var loops []*ReviewLoop
+	for _, key := range keys {
+		reviewLoopID := strings.TrimPrefix(key, prefixRLPaused)
+		loop, err := s.GetReviewLoop(reviewLoopID)
+		if err != nil || loop == nil || (loop.GlobalPauseHeld == nil && loop.GlobalPauseHeldPush == nil) {
+			_ = s.client.KV.Delete(key) // Clean up replayed or orphaned index entry.
+			continue
+		}
+		loops = append(loops, loop)
+	}
+	return loops, nil
+}
+
+func (s *store) ListWaitingReviewLoops() ([]*ReviewLoop, error) {
+	keys, err := s.client.KV.ListKeys(0, 1000, pluginapi.WithPrefix(prefixRLWaiting))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list waiting review loop keys")
+	}
+	var loops []*ReviewLoop
+	for _, key := range keys {
+		reviewLoopID := strings.TrimPrefix(key, prefixRLWaiting)
+		loop, err := s.GetReviewLoop(reviewLoopID)
+		if err != nil || loop == nil || !IsReviewPhaseWaiting(loop.Phase) {
+			_ = s.client.KV.Delete(key) // Clean up moved-on or orphaned index entry.
+			continue
+		}
+		loops = append(loops, loop)
+	}
+	return loops, nil
+}
+
+func (s *store) ListActiveReviewLoops() ([]*ReviewLoop, error) {
+	keys, err := s.client.KV.ListKeys(0, 1000, pluginapi.WithPrefix(prefixRLActive))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list active review loop keys")
+	}
+	var loops []*ReviewLoop
+	for _, key := range keys {
+		reviewLoopID := strings.TrimPrefix(key, prefixRLActive)
+		loop, err := s.GetReviewLoop(reviewLoopID)
+		if err != nil || loop == nil || IsReviewPhaseTerminal(loop.Phase) {
+			_ = s.client.KV.Delete(key) // Clean up finished or orphaned index entry.
+			continue
+		}
+		loops = append(loops, loop)
+	}
+	return loops, nil
+}
+
+func (s *store) GetReviewLoopDigestDate() (string, error) {
+	var date string
+	if err := s.client.KV.Get(keyRLDigestDate, &date); err != nil {
+		return "", errors.Wrap(err, "failed to get review loop digest date")
+	}
+	return date, nil
+}
+
+func (s *store) SetReviewLoopDigestDate(date string) error {
+	if _, err := s.client.KV.Set(keyRLDigestDate, date); err != nil {
+		return errors.Wrap(err, "failed to save review loop digest date")
+	}
+	return nil
+}
+
+func (s *store) ListFixingReviewLoops() ([]*ReviewLoop, error) {
+	keys, err := s.client.KV.ListKeys(0, 1000, pluginapi.WithPrefix(prefixRLFixing))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list fixing review loop keys")
+	}
+	var loops []*ReviewLoop
+	for _, key := range keys {
+		reviewLoopID := strings.TrimPrefix(key, prefixRLFixing)
+		loop, err := s.GetReviewLoop(reviewLoopID)
+		if err != nil || loop == nil || loop.Phase != ReviewPhaseCursorFixing {
+			_ = s.client.KV.Delete(key) // Clean up moved-on or orphaned index entry.
+			continue
+		}
+		loops = append(loops, loop)
+	}
+	return loops, nil
+}
+
+// ListIndexEntries scans every key in the store and reports the lookup index
+// entries along with the record each one references. Thread mappings that
+// point at a HITL workflow are reported as workflow references, and recorded
+// webhook deliveries reference the delivery log by their delivery ID.
+func (s *store) ListIndexEntries() ([]IndexEntry, error) {
+	var entries []IndexEntry
+	for page := 0; ; page++ {
+		keys, err := s.client.KV.ListKeys(page, indexListPageSize)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list keys")
+		}
+
+		for _, key := range keys {
+			entry, ok, err := s.indexEntryForKey(key)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				entries = append(entries, entry)
+			}
+		}
+
+		if len(keys) < indexListPageSize {
+			return entries, nil
+		}
+	}
+}
+
+// indexEntryForKey resolves key to an IndexEntry. ok is false for keys that
+// are not lookup index keys.
+func (s *store) indexEntryForKey(key string) (entry IndexEntry, ok bool, err error) {
+	for _, index := range lookupIndexes {
+		if !strings.HasPrefix(key, index.prefix) {
+			continue
+		}
+
+		entry = IndexEntry{Kind: index.kind, Key: key, TargetKind: index.targetKind}
+		if index.kind == IndexKindWebhookDelivery {
+			entry.TargetID = strings.TrimPrefix(key, index.prefix)
+			return entry, true, nil
+		}
+
+		var value string
+		if err := s.client.KV.Get(key, &value); err != nil {
+			return IndexEntry{}, false, errors.Wrapf(err, "failed to get index entry %s", key)
+		}
+		if index.kind == IndexKindThread && strings.HasPrefix(value, hitlThreadPrefix) {
+			entry.TargetKind = IndexTargetWorkflow
+			value = strings.TrimPrefix(value, hitlThreadPrefix)
+		}
+		entry.TargetID = value
+		return entry, true, nil
+	}
+	return IndexEntry{}, false, nil
+}
+
+// DeleteIndexEntry removes a lookup index key reported by ListIndexEntries.
+// Keys outside the scanned indexes are rejected so records cannot be deleted
+// through this path.
+func (s *store) DeleteIndexEntry(key string) error {
+	for _, index := range lookupIndexes {
+		if strings.HasPrefix(key, index.prefix) {
+			if err := s.client.KV.Delete(key); err != nil {
+				return errors.Wrap(err, "failed to delete index entry")
+			}
+			return nil
+		}
+	}
+	return errors.Errorf("%q is not a lookup index key", key)
+}
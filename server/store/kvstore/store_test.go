@@ -2,6 +2,7 @@ package kvstore
 
 import (
 	"encoding/json"
+	"fmt"
 	"testing"
 
 	"github.com/mattermost/mattermost/server/public/model"
@@ -144,6 +145,37 @@ func TestDeleteAgentWithUserIndex(t *testing.T) {
 	api.AssertExpectations(t)
 }
 
+func TestRemoveAgentFromUser(t *testing.T) {
+	s, api := setupStore(t)
+
+	mockKVDelete(api, prefixUserAgentIdx+"user-1:agent-moved")
+
+	err := s.RemoveAgentFromUser("user-1", "agent-moved")
+	require.NoError(t, err)
+	api.AssertExpectations(t)
+}
+
+func TestGetAgentsByUserDropsPreviousOwnerEntries(t *testing.T) {
+	s, api := setupStore(t)
+
+	owned := &AgentRecord{CursorAgentID: "a1", UserID: "user-1", Status: "RUNNING"}
+	transferred := &AgentRecord{CursorAgentID: "a2", UserID: "user-2", Status: "RUNNING"}
+
+	api.On("KVList", 0, 1000).Return([]string{
+		prefixUserAgentIdx + "user-1:a1",
+		prefixUserAgentIdx + "user-1:a2",
+	}, nil)
+	api.On("KVGet", prefixAgent+"a1").Return(mustJSON(t, owned), nil)
+	api.On("KVGet", prefixAgent+"a2").Return(mustJSON(t, transferred), nil)
+	mockKVDelete(api, prefixUserAgentIdx+"user-1:a2")
+
+	agents, err := s.GetAgentsByUser("user-1")
+	require.NoError(t, err)
+	require.Len(t, agents, 1)
+	assert.Equal(t, "a1", agents[0].CursorAgentID)
+	api.AssertExpectations(t)
+}
+
 func TestGetNonExistentAgent(t *testing.T) {
 	s, api := setupStore(t)
 
@@ -301,6 +333,23 @@ func TestUserSettingsCRUD(t *testing.T) {
 	api.AssertExpectations(t)
 }
 
+func TestDeleteUserSettings(t *testing.T) {
+	s, api := setupStore(t)
+
+	mockKVDelete(api, prefixUser+"user-1")
+
+	err := s.DeleteUserSettings("user-1")
+	require.NoError(t, err)
+
+	// With the key gone, reads return empty settings so global defaults apply.
+	api.On("KVGet", prefixUser+"user-1").Return(nil, nil)
+
+	got, err := s.GetUserSettings("user-1")
+	require.NoError(t, err)
+	assert.Equal(t, UserSettings{}, *got)
+	api.AssertExpectations(t)
+}
+
 func TestIsActiveStatus(t *testing.T) {
 	assert.True(t, isActiveStatus("CREATING"))
 	assert.True(t, isActiveStatus("RUNNING"))
@@ -369,6 +418,27 @@ func TestDeleteWorkflow(t *testing.T) {
 	api.AssertExpectations(t)
 }
 
+func TestListWorkflowsSkipsMissingRecords(t *testing.T) {
+	s, api := setupStore(t)
+
+	rejected := &HITLWorkflow{ID: "wf-1", Phase: PhaseRejected}
+
+	api.On("KVList", 0, 1000).Return([]string{
+		prefixHITL + "wf-1",
+		prefixHITL + "wf-gone",
+		prefixHITLAgent + "agent-1",
+	}, nil)
+	api.On("KVGet", prefixHITL+"wf-1").Return(mustJSON(t, rejected), nil)
+	api.On("KVGet", prefixHITL+"wf-gone").Return([]byte(nil), nil)
+
+	workflows, err := s.ListWorkflows()
+	require.NoError(t, err)
+	require.Len(t, workflows, 1)
+	assert.Equal(t, "wf-1", workflows[0].ID)
+	assert.Equal(t, PhaseRejected, workflows[0].Phase)
+	api.AssertExpectations(t)
+}
+
 func TestSetAndGetThreadWorkflow(t *testing.T) {
 	s, api := setupStore(t)
 
@@ -537,6 +607,7 @@ func TestSaveAndGetReviewLoop(t *testing.T) {
 	}
 
 	mockKVSet(api, prefixReviewLoop+"rl-123", mustJSON(t, loop))
+	mockKVSet(api, prefixRLActive+"rl-123", mustJSON(t, "rl-123"))
 	mockKVSet(api, prefixRLByPR+"https://github.com/org/repo/pull/42", mustJSON(t, "rl-123"))
 	mockKVSet(api, prefixRLByAgent+"agent-456", mustJSON(t, "rl-123"))
 	mockKVDelete(api, prefixFinishedWithPR+"agent-456") // Clear janitor index on loop creation
@@ -605,8 +676,10 @@ func TestSaveAndGetReviewLoopWithFeedbackFields(t *testing.T) {
 	}
 
 	mockKVSet(api, prefixReviewLoop+"rl-feedback", mustJSON(t, loop))
+	mockKVSet(api, prefixRLActive+"rl-feedback", mustJSON(t, "rl-feedback"))
 	mockKVSet(api, prefixRLByPR+"https://github.com/org/repo/pull/77", mustJSON(t, "rl-feedback"))
 	mockKVSet(api, prefixRLByAgent+"agent-feedback", mustJSON(t, "rl-feedback"))
+	mockKVSet(api, prefixRLFixing+"rl-feedback", mustJSON(t, "rl-feedback"))
 	mockKVDelete(api, prefixFinishedWithPR+"agent-feedback")
 
 	err := s.SaveReviewLoop(loop)
@@ -781,6 +854,337 @@ func TestGetReviewLoopByAgent(t *testing.T) {
 	api.AssertExpectations(t)
 }
 
+func TestSaveReviewLoopIndexesQuietHoursDeferral(t *testing.T) {
+	s, api := setupStore(t)
+
+	loop := &ReviewLoop{
+		ID:                        "rl-quiet",
+		Phase:                     ReviewPhaseAwaitingReview,
+		QuietHoursDeferredAt:      1000,
+		QuietHoursDispatchPending: true,
+	}
+
+	mockKVSet(api, prefixReviewLoop+"rl-quiet", mustJSON(t, loop))
+	mockKVSet(api, prefixRLActive+"rl-quiet", mustJSON(t, "rl-quiet"))
+	mockKVSet(api, prefixRLQuietHours+"rl-quiet", mustJSON(t, "rl-quiet"))
+	mockKVSet(api, prefixRLWaiting+"rl-quiet", mustJSON(t, "rl-quiet"))
+
+	err := s.SaveReviewLoop(loop)
+	require.NoError(t, err)
+	api.AssertExpectations(t)
+}
+
+func TestListQuietHoursDeferredReviewLoopsCleansStaleEntries(t *testing.T) {
+	s, api := setupStore(t)
+
+	held := &ReviewLoop{ID: "rl-held", QuietHoursDeferredAt: 1000}
+	released := &ReviewLoop{ID: "rl-released"}
+
+	api.On("KVList", 0, 1000).Return([]string{
+		prefixRLQuietHours + "rl-held",
+		prefixRLQuietHours + "rl-released",
+		prefixRLQuietHours + "rl-gone",
+	}, nil)
+	api.On("KVGet", prefixReviewLoop+"rl-held").Return(mustJSON(t, held), nil)
+	api.On("KVGet", prefixReviewLoop+"rl-released").Return(mustJSON(t, released), nil)
+	api.On("KVGet", prefixReviewLoop+"rl-gone").Return([]byte(nil), nil)
+	mockKVDelete(api, prefixRLQuietHours+"rl-released")
+	mockKVDelete(api, prefixRLQuietHours+"rl-gone")
+
+	loops, err := s.ListQuietHoursDeferredReviewLoops()
+	require.NoError(t, err)
+	require.Len(t, loops, 1)
+	assert.Equal(t, "rl-held", loops[0].ID)
+	api.AssertExpectations(t)
+}
+
+func TestSaveReviewLoopIndexesGitHubRetry(t *testing.T) {
+	s, api := setupStore(t)
+
+	loop := &ReviewLoop{
+		ID:                 "rl-retry",
+		Phase:              ReviewPhaseAwaitingReview,
+		GitHubRetryPending: true,
+		GitHubRetrySHA:     "sha-1",
+	}
+
+	mockKVSet(api, prefixReviewLoop+"rl-retry", mustJSON(t, loop))
+	mockKVSet(api, prefixRLActive+"rl-retry", mustJSON(t, "rl-retry"))
+	mockKVSet(api, prefixRLGitHubRetry+"rl-retry", mustJSON(t, "rl-retry"))
+	mockKVSet(api, prefixRLWaiting+"rl-retry", mustJSON(t, "rl-retry"))
+
+	err := s.SaveReviewLoop(loop)
+	require.NoError(t, err)
+	api.AssertExpectations(t)
+}
+
+func TestListGitHubRetryReviewLoopsCleansStaleEntries(t *testing.T) {
+	s, api := setupStore(t)
+
+	pending := &ReviewLoop{ID: "rl-pending", GitHubRetryPending: true}
+	retried := &ReviewLoop{ID: "rl-retried"}
+
+	api.On("KVList", 0, 1000).Return([]string{
+		prefixRLGitHubRetry + "rl-pending",
+		prefixRLGitHubRetry + "rl-retried",
+		prefixRLGitHubRetry + "rl-gone",
+	}, nil)
+	api.On("KVGet", prefixReviewLoop+"rl-pending").Return(mustJSON(t, pending), nil)
+	api.On("KVGet", prefixReviewLoop+"rl-retried").Return(mustJSON(t, retried), nil)
+	api.On("KVGet", prefixReviewLoop+"rl-gone").Return([]byte(nil), nil)
+	mockKVDelete(api, prefixRLGitHubRetry+"rl-retried")
+	mockKVDelete(api, prefixRLGitHubRetry+"rl-gone")
+
+	loops, err := s.ListGitHubRetryReviewLoops()
+	require.NoError(t, err)
+	require.Len(t, loops, 1)
+	assert.Equal(t, "rl-pending", loops[0].ID)
+	api.AssertExpectations(t)
+}
+
+func TestSaveReviewLoopIndexesRateLimitRetry(t *testing.T) {
+	s, api := setupStore(t)
+
+	loop := &ReviewLoop{
+		ID:                     "rl-limited",
+		Phase:                  ReviewPhaseAwaitingReview,
+		RateLimitRetryPending:  true,
+		RateLimitRetryAt:       1700000060000,
+		RateLimitRetryAttempts: 1,
+	}
+
+	mockKVSet(api, prefixReviewLoop+"rl-limited", mustJSON(t, loop))
+	mockKVSet(api, prefixRLActive+"rl-limited", mustJSON(t, "rl-limited"))
+	mockKVSet(api, prefixRLRateLimit+"rl-limited", mustJSON(t, "rl-limited"))
+	mockKVSet(api, prefixRLWaiting+"rl-limited", mustJSON(t, "rl-limited"))
+
+	err := s.SaveReviewLoop(loop)
+	require.NoError(t, err)
+	api.AssertExpectations(t)
+}
+
+func TestListRateLimitedReviewLoopsCleansStaleEntries(t *testing.T) {
+	s, api := setupStore(t)
+
+	pending := &ReviewLoop{ID: "rl-pending", RateLimitRetryPending: true}
+	retried := &ReviewLoop{ID: "rl-retried"}
+
+	api.On("KVList", 0, 1000).Return([]string{
+		prefixRLRateLimit + "rl-pending",
+		prefixRLRateLimit + "rl-retried",
+		prefixRLRateLimit + "rl-gone",
+	}, nil)
+	api.On("KVGet", prefixReviewLoop+"rl-pending").Return(mustJSON(t, pending), nil)
+	api.On("KVGet", prefixReviewLoop+"rl-retried").Return(mustJSON(t, retried), nil)
+	api.On("KVGet", prefixReviewLoop+"rl-gone").Return([]byte(nil), nil)
+	mockKVDelete(api, prefixRLRateLimit+"rl-retried")
+	mockKVDelete(api, prefixRLRateLimit+"rl-gone")
+
+	loops, err := s.ListRateLimitedReviewLoops()
+	require.NoError(t, err)
+	require.Len(t, loops, 1)
+	assert.Equal(t, "rl-pending", loops[0].ID)
+	api.AssertExpectations(t)
+}
+
+func TestSaveReviewLoopIndexesPendingDispatch(t *testing.T) {
+	s, api := setupStore(t)
+
+	loop := &ReviewLoop{
+		ID:                "rl-checkpoint",
+		Phase:             ReviewPhaseAwaitingReview,
+		PendingDispatchID: "rl-checkpoint-1700000000000",
+		PendingDispatchAt: 1700000000000,
+	}
+
+	mockKVSet(api, prefixReviewLoop+"rl-checkpoint", mustJSON(t, loop))
+	mockKVSet(api, prefixRLActive+"rl-checkpoint", mustJSON(t, "rl-checkpoint"))
+	mockKVSet(api, prefixRLPendingDispatch+"rl-checkpoint", mustJSON(t, "rl-checkpoint"))
+	mockKVSet(api, prefixRLWaiting+"rl-checkpoint", mustJSON(t, "rl-checkpoint"))
+
+	err := s.SaveReviewLoop(loop)
+	require.NoError(t, err)
+	api.AssertExpectations(t)
+}
+
+func TestListPendingDispatchReviewLoopsCleansStaleEntries(t *testing.T) {
+	s, api := setupStore(t)
+
+	pending := &ReviewLoop{ID: "rl-pending", PendingDispatchID: "rl-pending-1", PendingDispatchAt: 1700000000000}
+	reconciled := &ReviewLoop{ID: "rl-reconciled"}
+
+	api.On("KVList", 0, 1000).Return([]string{
+		prefixRLPendingDispatch + "rl-pending",
+		prefixRLPendingDispatch + "rl-reconciled",
+		prefixRLPendingDispatch + "rl-gone",
+	}, nil)
+	api.On("KVGet", prefixReviewLoop+"rl-pending").Return(mustJSON(t, pending), nil)
+	api.On("KVGet", prefixReviewLoop+"rl-reconciled").Return(mustJSON(t, reconciled), nil)
+	api.On("KVGet", prefixReviewLoop+"rl-gone").Return([]byte(nil), nil)
+	mockKVDelete(api, prefixRLPendingDispatch+"rl-reconciled")
+	mockKVDelete(api, prefixRLPendingDispatch+"rl-gone")
+
+	loops, err := s.ListPendingDispatchReviewLoops()
+	require.NoError(t, err)
+	require.Len(t, loops, 1)
+	assert.Equal(t, "rl-pending", loops[0].ID)
+	api.AssertExpectations(t)
+}
+
+func TestListGloballyPausedReviewLoopsCleansStaleEntries(t *testing.T) {
+	s, api := setupStore(t)
+
+	held := &ReviewLoop{ID: "rl-held", GlobalPauseHeld: &HeldReview{ReviewerLogin: "coderabbitai[bot]", State: "changes_requested"}}
+	replayed := &ReviewLoop{ID: "rl-replayed"}
+
+	api.On("KVList", 0, 1000).Return([]string{
+		prefixRLPaused + "rl-held",
+		prefixRLPaused + "rl-replayed",
+		prefixRLPaused + "rl-gone",
+	}, nil)
+	api.On("KVGet", prefixReviewLoop+"rl-held").Return(mustJSON(t, held), nil)
+	api.On("KVGet", prefixReviewLoop+"rl-replayed").Return(mustJSON(t, replayed), nil)
+	api.On("KVGet", prefixReviewLoop+"rl-gone").Return([]byte(nil), nil)
+	mockKVDelete(api, prefixRLPaused+"rl-replayed")
+	mockKVDelete(api, prefixRLPaused+"rl-gone")
+
+	loops, err := s.ListGloballyPausedReviewLoops()
+	require.NoError(t, err)
+	require.Len(t, loops, 1)
+	assert.Equal(t, "rl-held", loops[0].ID)
+	assert.Equal(t, "changes_requested", loops[0].GlobalPauseHeld.State)
+	api.AssertExpectations(t)
+}
+
+func TestSaveReviewLoopSkipsActiveIndexWhenTerminal(t *testing.T) {
+	s, api := setupStore(t)
+
+	loop := &ReviewLoop{ID: "rl-done", Phase: ReviewPhaseComplete}
+
+	mockKVSet(api, prefixReviewLoop+"rl-done", mustJSON(t, loop))
+
+	err := s.SaveReviewLoop(loop)
+	require.NoError(t, err)
+	api.AssertExpectations(t)
+	api.AssertNotCalled(t, "KVSetWithOptions", prefixRLActive+"rl-done", mock.Anything, mock.Anything)
+}
+
+func TestListActiveReviewLoopsCleansStaleEntries(t *testing.T) {
+	s, api := setupStore(t)
+
+	active := &ReviewLoop{ID: "rl-active", Phase: ReviewPhaseAwaitingReview}
+	done := &ReviewLoop{ID: "rl-done", Phase: ReviewPhaseComplete}
+
+	api.On("KVList", 0, 1000).Return([]string{
+		prefixRLActive + "rl-active",
+		prefixRLActive + "rl-done",
+		prefixRLActive + "rl-gone",
+	}, nil)
+	api.On("KVGet", prefixReviewLoop+"rl-active").Return(mustJSON(t, active), nil)
+	api.On("KVGet", prefixReviewLoop+"rl-done").Return(mustJSON(t, done), nil)
+	api.On("KVGet", prefixReviewLoop+"rl-gone").Return([]byte(nil), nil)
+	mockKVDelete(api, prefixRLActive+"rl-done")
+	mockKVDelete(api, prefixRLActive+"rl-gone")
+
+	loops, err := s.ListActiveReviewLoops()
+	require.NoError(t, err)
+	require.Len(t, loops, 1)
+	assert.Equal(t, "rl-active", loops[0].ID)
+	api.AssertExpectations(t)
+}
+
+func TestReviewLoopDigestDateRoundTrip(t *testing.T) {
+	s, api := setupStore(t)
+
+	mockKVSet(api, keyRLDigestDate, mustJSON(t, "2026-10-17"))
+	api.On("KVGet", keyRLDigestDate).Return(mustJSON(t, "2026-10-17"), nil)
+
+	require.NoError(t, s.SetReviewLoopDigestDate("2026-10-17"))
+	date, err := s.GetReviewLoopDigestDate()
+	require.NoError(t, err)
+	assert.Equal(t, "2026-10-17", date)
+	api.AssertExpectations(t)
+}
+
+func TestSaveReviewLoopIndexesWaitingPhase(t *testing.T) {
+	s, api := setupStore(t)
+
+	loop := &ReviewLoop{
+		ID:    "rl-human",
+		Phase: ReviewPhaseHumanReview,
+	}
+
+	mockKVSet(api, prefixReviewLoop+"rl-human", mustJSON(t, loop))
+	mockKVSet(api, prefixRLActive+"rl-human", mustJSON(t, "rl-human"))
+	mockKVSet(api, prefixRLWaiting+"rl-human", mustJSON(t, "rl-human"))
+
+	err := s.SaveReviewLoop(loop)
+	require.NoError(t, err)
+	api.AssertExpectations(t)
+}
+
+func TestListWaitingReviewLoopsCleansStaleEntries(t *testing.T) {
+	s, api := setupStore(t)
+
+	waiting := &ReviewLoop{ID: "rl-waiting", Phase: ReviewPhaseAwaitingReview}
+	fixing := &ReviewLoop{ID: "rl-fixing", Phase: ReviewPhaseCursorFixing}
+
+	api.On("KVList", 0, 1000).Return([]string{
+		prefixRLWaiting + "rl-waiting",
+		prefixRLWaiting + "rl-fixing",
+		prefixRLWaiting + "rl-gone",
+	}, nil)
+	api.On("KVGet", prefixReviewLoop+"rl-waiting").Return(mustJSON(t, waiting), nil)
+	api.On("KVGet", prefixReviewLoop+"rl-fixing").Return(mustJSON(t, fixing), nil)
+	api.On("KVGet", prefixReviewLoop+"rl-gone").Return([]byte(nil), nil)
+	mockKVDelete(api, prefixRLWaiting+"rl-fixing")
+	mockKVDelete(api, prefixRLWaiting+"rl-gone")
+
+	loops, err := s.ListWaitingReviewLoops()
+	require.NoError(t, err)
+	require.Len(t, loops, 1)
+	assert.Equal(t, "rl-waiting", loops[0].ID)
+	api.AssertExpectations(t)
+}
+
+func TestSaveReviewLoopIndexesFixingPhase(t *testing.T) {
+	s, api := setupStore(t)
+
+	loop := &ReviewLoop{
+		ID:    "rl-fixing",
+		Phase: ReviewPhaseCursorFixing,
+	}
+
+	mockKVSet(api, prefixReviewLoop+"rl-fixing", mustJSON(t, loop))
+	mockKVSet(api, prefixRLActive+"rl-fixing", mustJSON(t, "rl-fixing"))
+	mockKVSet(api, prefixRLFixing+"rl-fixing", mustJSON(t, "rl-fixing"))
+
+	err := s.SaveReviewLoop(loop)
+	require.NoError(t, err)
+	api.AssertExpectations(t)
+}
+
+func TestListFixingReviewLoopsCleansStaleEntries(t *testing.T) {
+	s, api := setupStore(t)
+
+	fixing := &ReviewLoop{ID: "rl-fixing", Phase: ReviewPhaseCursorFixing}
+	waiting := &ReviewLoop{ID: "rl-waiting", Phase: ReviewPhaseAwaitingReview}
+
+	api.On("KVList", 0, 1000).Return([]string{
+		prefixRLFixing + "rl-fixing",
+		prefixRLFixing + "rl-waiting",
+	}, nil)
+	api.On("KVGet", prefixReviewLoop+"rl-fixing").Return(mustJSON(t, fixing), nil)
+	api.On("KVGet", prefixReviewLoop+"rl-waiting").Return(mustJSON(t, waiting), nil)
+	mockKVDelete(api, prefixRLFixing+"rl-waiting")
+
+	loops, err := s.ListFixingReviewLoops()
+	require.NoError(t, err)
+	require.Len(t, loops, 1)
+	assert.Equal(t, "rl-fixing", loops[0].ID)
+	api.AssertExpectations(t)
+}
+
 func TestGetReviewLoopByAgentNotFound(t *testing.T) {
 	s, api := setupStore(t)
 
@@ -814,8 +1218,10 @@ func TestReviewLoopWithHistory(t *testing.T) {
 	}
 
 	mockKVSet(api, prefixReviewLoop+"rl-hist", mustJSON(t, loop))
+	mockKVSet(api, prefixRLActive+"rl-hist", mustJSON(t, "rl-hist"))
 	mockKVSet(api, prefixRLByPR+"https://github.com/org/repo/pull/10", mustJSON(t, "rl-hist"))
 	mockKVSet(api, prefixRLByAgent+"agent-hist", mustJSON(t, "rl-hist"))
+	mockKVSet(api, prefixRLFixing+"rl-hist", mustJSON(t, "rl-hist"))
 	mockKVDelete(api, prefixFinishedWithPR+"agent-hist") // Clear janitor index on loop creation
 
 	err := s.SaveReviewLoop(loop)
@@ -844,3 +1250,141 @@ func TestReviewPhaseConstants(t *testing.T) {
 	assert.Equal(t, "max_iterations", ReviewPhaseMaxIterations)
 	assert.Equal(t, "failed", ReviewPhaseFailed)
 }
+
+func TestAddUserLaunchHistoryEntryMovesRepeatToFrontAndCaps(t *testing.T) {
+	s, api := setupStore(t)
+
+	first := LaunchHistoryEntry{Prompt: "fix bug", Repository: "org/repo", Branch: "main", Model: "auto", LaunchedAt: 1000}
+	second := LaunchHistoryEntry{Prompt: "add tests", Repository: "org/repo", Branch: "main", Model: "auto", LaunchedAt: 2000}
+	third := LaunchHistoryEntry{Prompt: "bump deps", Repository: "org/web", Branch: "main", Model: "auto", LaunchedAt: 3000}
+	api.On("KVGet", prefixLaunchHist+"user-1").Return(mustJSON(t, UserLaunchHistory{
+		Entries: []LaunchHistoryEntry{third, first, second},
+	}), nil)
+
+	relaunch := first
+	relaunch.LaunchedAt = 4000
+	mockKVSet(api, prefixLaunchHist+"user-1", mustJSON(t, &UserLaunchHistory{
+		Entries: []LaunchHistoryEntry{relaunch, third},
+	}))
+
+	err := s.AddUserLaunchHistoryEntry("user-1", relaunch, 2)
+	require.NoError(t, err)
+	api.AssertExpectations(t)
+}
+
+func TestSaveWebhookDeliveryEvictsOldest(t *testing.T) {
+	s, api := setupStore(t)
+
+	delivery := &WebhookDelivery{DeliveryID: "d-3", Event: "pull_request", Body: "{}", Status: 200, ReceivedAt: 3000}
+	mockKVSet(api, prefixWebhookDelivery+"d-3", mustJSON(t, delivery))
+	api.On("KVGet", keyWebhookDeliveryLog).Return(mustJSON(t, []string{"d-1", "d-2"}), nil)
+	mockKVDelete(api, prefixWebhookDelivery+"d-1")
+	mockKVSet(api, keyWebhookDeliveryLog, mustJSON(t, []string{"d-2", "d-3"}))
+
+	err := s.SaveWebhookDelivery(delivery, 2)
+	require.NoError(t, err)
+	api.AssertExpectations(t)
+}
+
+func TestSaveWebhookDeliveryMovesRedeliveryToNewest(t *testing.T) {
+	s, api := setupStore(t)
+
+	delivery := &WebhookDelivery{DeliveryID: "d-1", Event: "ping", Body: "{}", Status: 200, Duplicate: true}
+	mockKVSet(api, prefixWebhookDelivery+"d-1", mustJSON(t, delivery))
+	api.On("KVGet", keyWebhookDeliveryLog).Return(mustJSON(t, []string{"d-1", "d-2"}), nil)
+	mockKVSet(api, keyWebhookDeliveryLog, mustJSON(t, []string{"d-2", "d-1"}))
+
+	err := s.SaveWebhookDelivery(delivery, 5)
+	require.NoError(t, err)
+	api.AssertExpectations(t)
+}
+
+func TestListWebhookDeliveriesNewestFirst(t *testing.T) {
+	s, api := setupStore(t)
+
+	older := &WebhookDelivery{DeliveryID: "d-1", Event: "ping", ReceivedAt: 1000}
+	newer := &WebhookDelivery{DeliveryID: "d-2", Event: "pull_request", ReceivedAt: 2000}
+	api.On("KVGet", keyWebhookDeliveryLog).Return(mustJSON(t, []string{"d-1", "gone", "d-2"}), nil)
+	api.On("KVGet", prefixWebhookDelivery+"d-1").Return(mustJSON(t, older), nil)
+	api.On("KVGet", prefixWebhookDelivery+"d-2").Return(mustJSON(t, newer), nil)
+	api.On("KVGet", prefixWebhookDelivery+"gone").Return(nil, nil)
+
+	deliveries, err := s.ListWebhookDeliveries()
+	require.NoError(t, err)
+	require.Len(t, deliveries, 2)
+	assert.Equal(t, "d-2", deliveries[0].DeliveryID)
+	assert.Equal(t, "d-1", deliveries[1].DeliveryID)
+}
+
+func TestHITLWorkflowAppendPlanVersion(t *testing.T) {
+	w := &HITLWorkflow{}
+	w.AppendPlanVersion("plan 1")
+	w.AppendPlanVersion("plan 1")
+	assert.Equal(t, []string{"plan 1"}, w.PlanVersions, "an unchanged plan is not a new version")
+
+	for i := 2; i <= MaxPlanVersions+2; i++ {
+		w.AppendPlanVersion(fmt.Sprintf("plan %d", i))
+	}
+	require.Len(t, w.PlanVersions, MaxPlanVersions)
+	assert.Equal(t, "plan 3", w.PlanVersions[0])
+	assert.Equal(t, fmt.Sprintf("plan %d", MaxPlanVersions+2), w.PlanVersions[MaxPlanVersions-1])
+}
+
+func TestListIndexEntries(t *testing.T) {
+	s, api := setupStore(t)
+
+	api.On("KVList", 0, 1000).Return([]string{
+		prefixAgent + "a1",
+		prefixThread + "post-1",
+		prefixThread + "post-2",
+		prefixHITLAgent + "a1",
+		prefixRLByPR + "https://github.com/org/repo/pull/1",
+		prefixWebhookDelivery + "d-1",
+		prefixHITL + "wf-1",
+	}, nil)
+	api.On("KVGet", prefixThread+"post-1").Return(mustJSON(t, "a1"), nil)
+	api.On("KVGet", prefixThread+"post-2").Return(mustJSON(t, hitlThreadPrefix+"wf-1"), nil)
+	api.On("KVGet", prefixHITLAgent+"a1").Return(mustJSON(t, "wf-1"), nil)
+	api.On("KVGet", prefixRLByPR+"https://github.com/org/repo/pull/1").Return(mustJSON(t, "loop-1"), nil)
+
+	entries, err := s.ListIndexEntries()
+	require.NoError(t, err)
+	assert.Equal(t, []IndexEntry{
+		{Kind: IndexKindThread, Key: prefixThread + "post-1", TargetKind: IndexTargetAgent, TargetID: "a1"},
+		{Kind: IndexKindThread, Key: prefixThread + "post-2", TargetKind: IndexTargetWorkflow, TargetID: "wf-1"},
+		{Kind: IndexKindAgentWorkflow, Key: prefixHITLAgent + "a1", TargetKind: IndexTargetWorkflow, TargetID: "wf-1"},
+		{Kind: IndexKindReviewLoopByPR, Key: prefixRLByPR + "https://github.com/org/repo/pull/1", TargetKind: IndexTargetReviewLoop, TargetID: "loop-1"},
+		{Kind: IndexKindWebhookDelivery, Key: prefixWebhookDelivery + "d-1", TargetKind: IndexTargetDeliveryLog, TargetID: "d-1"},
+	}, entries)
+	api.AssertExpectations(t)
+}
+
+func TestListIndexEntriesPagesThroughAllKeys(t *testing.T) {
+	s, api := setupStore(t)
+
+	firstPage := make([]string, indexListPageSize)
+	for i := range firstPage {
+		firstPage[i] = fmt.Sprintf("%sa%d", prefixAgent, i)
+	}
+	api.On("KVList", 0, indexListPageSize).Return(firstPage, nil)
+	api.On("KVList", 1, indexListPageSize).Return([]string{prefixBranchIdx + "cursor/fix"}, nil)
+	api.On("KVGet", prefixBranchIdx+"cursor/fix").Return(mustJSON(t, "a1"), nil)
+
+	entries, err := s.ListIndexEntries()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, IndexKindBranch, entries[0].Kind)
+	api.AssertExpectations(t)
+}
+
+func TestDeleteIndexEntry(t *testing.T) {
+	s, api := setupStore(t)
+
+	mockKVDelete(api, prefixThread+"post-1")
+	require.NoError(t, s.DeleteIndexEntry(prefixThread+"post-1"))
+
+	err := s.DeleteIndexEntry(prefixAgent + "a1")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not a lookup index key")
+	api.AssertExpectations(t)
+}
@@ -1,16 +1,18 @@
 package main
 
 import (
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
 	"regexp"
 	"strings"
-	"time"
 
 	"github.com/mattermost/mattermost/server/public/model"
 
@@ -23,22 +25,25 @@ const (
 	eventHeader           = "X-GitHub-Event"
 	deliveryHeader        = "X-GitHub-Delivery"
 
-	eventPullRequest       = "pull_request"
-	eventPullRequestReview = "pull_request_review"
-	eventPing              = "ping"
+	eventPullRequest              = "pull_request"
+	eventPullRequestReview        = "pull_request_review"
+	eventPullRequestReviewComment = "pull_request_review_comment"
+	eventPing                     = "ping"
 
 	prActionClosed      = "closed"
 	prActionOpened      = "opened"
 	prActionSynchronize = "synchronize"
+	prActionEdited      = "edited"
+
+	prActionReadyForReview = "ready_for_review"
 
 	reviewActionSubmitted = "submitted"
 
+	reviewCommentActionCreated = "created"
+
 	reviewStateApproved         = "approved"
 	reviewStateChangesRequested = "changes_requested"
 	reviewStateCommented        = "commented"
-
-	// maxWebhookBodySize limits the body we read to prevent DoS.
-	maxWebhookBodySize = 1 << 20 // 1 MB
 )
 
 // --- GitHub event payload types ---
@@ -50,6 +55,7 @@ type ghPullRequest struct {
 	Title   string `json:"title"`
 	State   string `json:"state"`
 	Merged  bool   `json:"merged"`
+	Draft   bool   `json:"draft"`
 	Head    struct {
 		Ref string `json:"ref"`
 		SHA string `json:"sha"`
@@ -72,6 +78,21 @@ type ghReview struct {
 	} `json:"user"`
 }
 
+// ghReviewComment represents the minimal inline review comment fields we
+// need from GitHub webhooks.
+type ghReviewComment struct {
+	ID                  int64  `json:"id"`
+	PullRequestReviewID int64  `json:"pull_request_review_id"`
+	InReplyToID         int64  `json:"in_reply_to_id"`
+	Path                string `json:"path"`
+	Body                string `json:"body"`
+	CommitID            string `json:"commit_id"`
+	HTMLURL             string `json:"html_url"`
+	User                struct {
+		Login string `json:"login"`
+	} `json:"user"`
+}
+
 // ghRepository represents the minimal repo fields from GitHub webhooks.
 type ghRepository struct {
 	FullName string `json:"full_name"`
@@ -83,12 +104,25 @@ type ghSender struct {
 	Login string `json:"login"`
 }
 
+// ghPullRequestChanges holds the previous values of the PR fields changed by
+// an edited action. Only the fields we act on are modeled.
+type ghPullRequestChanges struct {
+	Base *struct {
+		Ref struct {
+			From string `json:"from"`
+		} `json:"ref"`
+	} `json:"base,omitempty"`
+}
+
 // PullRequestEvent is the GitHub webhook payload for pull_request events.
 type PullRequestEvent struct {
-	Action      string        `json:"action"`
-	PullRequest ghPullRequest `json:"pull_request"`
-	Repository  ghRepository  `json:"repository"`
-	Sender      ghSender      `json:"sender"`
+	Action      string               `json:"action"`
+	PullRequest ghPullRequest        `json:"pull_request"`
+	Repository  ghRepository         `json:"repository"`
+	Sender      ghSender             `json:"sender"`
+	Changes     ghPullRequestChanges `json:"changes"`
+
+	webhookPayload
 }
 
 // PullRequestReviewEvent is the GitHub webhook payload for pull_request_review events.
@@ -98,6 +132,20 @@ type PullRequestReviewEvent struct {
 	PullRequest ghPullRequest `json:"pull_request"`
 	Repository  ghRepository  `json:"repository"`
 	Sender      ghSender      `json:"sender"`
+
+	webhookPayload
+}
+
+// PullRequestReviewCommentEvent is the GitHub webhook payload for
+// pull_request_review_comment events.
+type PullRequestReviewCommentEvent struct {
+	Action      string          `json:"action"`
+	Comment     ghReviewComment `json:"comment"`
+	PullRequest ghPullRequest   `json:"pull_request"`
+	Repository  ghRepository    `json:"repository"`
+	Sender      ghSender        `json:"sender"`
+
+	webhookPayload
 }
 
 // PingEvent is the GitHub webhook payload for ping events (sent on webhook creation).
@@ -143,16 +191,36 @@ func (r *statusRecorder) WriteHeader(code int) {
 func (p *Plugin) handleGitHubWebhook(w http.ResponseWriter, r *http.Request) {
 	config := p.getConfiguration()
 
-	// 1. Read the body with size limit.
-	r.Body = http.MaxBytesReader(w, r.Body, maxWebhookBodySize)
+	// 1. Only JSON payloads are supported; GitHub webhooks configured with
+	// application/x-www-form-urlencoded are rejected before reading the body.
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != "application/json" {
+		p.API.LogWarn("GitHub webhook rejected: unsupported content type",
+			"content_type", r.Header.Get("Content-Type"),
+		)
+		http.Error(w, "unsupported content type, expected application/json", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	// 2. Read the body with size limit. The exact bytes read are used for
+	// HMAC verification below.
+	r.Body = http.MaxBytesReader(w, r.Body, config.GetMaxWebhookBodySize())
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			p.API.LogWarn("GitHub webhook rejected: body too large",
+				"limit_bytes", fmt.Sprintf("%d", maxBytesErr.Limit),
+			)
+			http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
 		http.Error(w, "failed to read request body", http.StatusBadRequest)
 		return
 	}
 	defer func() { _ = r.Body.Close() }()
 
-	// 2. Verify HMAC signature.
+	// 3. Verify HMAC signature.
 	secret := config.GitHubWebhookSecret
 	if secret == "" {
 		p.API.LogWarn("GitHub webhook received but GitHubWebhookSecret is not configured")
@@ -167,37 +235,63 @@ func (p *Plugin) handleGitHubWebhook(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// 3. Idempotency: check delivery ID.
+	// 4. Idempotency: check delivery ID. The lookup doubles as a KV store
+	// health check.
 	deliveryID := r.Header.Get(deliveryHeader)
+	eventType := r.Header.Get(eventHeader)
 	if deliveryID != "" {
-		seen, _ := p.kvstore.HasDeliveryBeenProcessed(deliveryID)
+		seen, err := p.kvstore.HasDeliveryBeenProcessed(deliveryID)
+		if err != nil {
+			p.recordKVError("HasDeliveryBeenProcessed", err)
+		} else {
+			p.recordKVSuccess()
+		}
 		if seen {
 			p.API.LogDebug("Duplicate GitHub webhook delivery, skipping", "delivery", deliveryID)
+			p.recordWebhookDelivery(deliveryID, eventType, body, http.StatusOK, true)
 			w.WriteHeader(http.StatusOK)
 			return
 		}
 	}
 
-	// 4. Route by event type, recording the response status.
+	// While the KV store is unavailable, acknowledge the delivery without
+	// processing it rather than failing part way through the handlers.
+	if p.kvDegraded() {
+		p.API.LogWarn("KV store unavailable, skipping GitHub webhook", "delivery", deliveryID, "event", eventType)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// 5. Route by event type, recording the response status. Lines logged
+	// while handling the event carry the delivery ID.
+	ctx := p.withDeliveryLogger(r.Context(), deliveryID)
 	sr := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
-	eventType := r.Header.Get(eventHeader)
-	p.API.LogDebug("GitHub webhook received", "event", eventType, "delivery", deliveryID)
+	p.logger(ctx).LogDebug("GitHub webhook received", "event", eventType)
+	p.routeGitHubEvent(ctx, sr, eventType, body)
+	p.recordWebhookDelivery(deliveryID, eventType, body, sr.status, false)
 
+	// 6. Mark delivery as processed only after successful handling.
+	if deliveryID != "" && sr.status >= 200 && sr.status < 300 {
+		if err := p.kvstore.MarkDeliveryProcessed(deliveryID); err != nil {
+			p.recordKVError("MarkDeliveryProcessed", err)
+		}
+	}
+}
+
+// routeGitHubEvent dispatches a verified webhook payload to its event handler.
+func (p *Plugin) routeGitHubEvent(ctx context.Context, w http.ResponseWriter, eventType string, body []byte) {
 	switch eventType {
 	case eventPing:
-		p.handlePingEvent(sr, body)
+		p.handlePingEvent(w, body)
 	case eventPullRequest:
-		p.handlePullRequestEvent(sr, body)
+		p.handlePullRequestEvent(ctx, w, body)
 	case eventPullRequestReview:
-		p.handlePullRequestReviewEvent(sr, body)
+		p.handlePullRequestReviewEvent(ctx, w, body)
+	case eventPullRequestReviewComment:
+		p.handlePullRequestReviewCommentEvent(ctx, w, body)
 	default:
-		p.API.LogDebug("Ignoring unhandled GitHub event type", "event", eventType)
-		sr.WriteHeader(http.StatusOK)
-	}
-
-	// 5. Mark delivery as processed only after successful handling.
-	if deliveryID != "" && sr.status >= 200 && sr.status < 300 {
-		_ = p.kvstore.MarkDeliveryProcessed(deliveryID)
+		p.logger(ctx).LogDebug("Ignoring unhandled GitHub event type", "event", eventType)
+		w.WriteHeader(http.StatusOK)
 	}
 }
 
@@ -220,22 +314,29 @@ func (p *Plugin) handlePingEvent(w http.ResponseWriter, body []byte) {
 	_, _ = w.Write([]byte(`{"status": "ok"}`))
 }
 
-func (p *Plugin) handlePullRequestEvent(w http.ResponseWriter, body []byte) {
+func (p *Plugin) handlePullRequestEvent(ctx context.Context, w http.ResponseWriter, body []byte) {
 	var event PullRequestEvent
-	if err := json.Unmarshal(body, &event); err != nil {
-		p.API.LogWarn("Failed to parse pull_request event", "error", err.Error())
+	if err := parseWebhookEvent(body, &event); err != nil {
+		p.logger(ctx).LogWarn("Failed to parse pull_request event", "error", err.Error())
 		http.Error(w, "invalid payload", http.StatusBadRequest)
 		return
 	}
+	p.logUnknownWebhookFields(eventPullRequest, &event.webhookPayload)
 
 	// Route by action.
 	switch event.Action {
 	case prActionSynchronize:
-		p.handlePRSynchronizeWebhook(event, w)
+		p.handlePRSynchronizeWebhook(ctx, event, w)
 		return
 	case prActionOpened:
 		p.handlePROpened(event, w)
 		return
+	case prActionReadyForReview:
+		p.handlePRReadyForReview(event, w)
+		return
+	case prActionEdited:
+		p.handlePREditedWebhook(ctx, event, w)
+		return
 	case prActionClosed:
 		// Fall through to existing closed handling below.
 	default:
@@ -246,34 +347,37 @@ func (p *Plugin) handlePullRequestEvent(w http.ResponseWriter, body []byte) {
 	// Look up the agent associated with this PR.
 	agent := p.findAgentForPR(event.PullRequest)
 	if agent == nil {
-		p.API.LogDebug("No agent found for PR", "pr_url", event.PullRequest.HTMLURL)
+		p.logger(ctx).LogDebug("No agent found for PR", "pr_url", event.PullRequest.HTMLURL)
 		w.WriteHeader(http.StatusOK)
 		return
 	}
 
 	prTitle := fmt.Sprintf("PR #%d: %s", event.PullRequest.Number, event.PullRequest.Title)
 
-	if event.PullRequest.Merged {
-		mergedAttachment := &model.SlackAttachment{
+	prVars := prNotificationVars(event.PullRequest, "")
+
+	muted := p.prNotificationsMuted(event.PullRequest)
+	if event.PullRequest.Merged && !muted {
+		mergedAttachment := p.styleNotification(notificationEventPRMerged, &model.SlackAttachment{
 			Color:     "#3DB887", // green
 			Title:     prTitle,
 			TitleLink: event.PullRequest.HTMLURL,
 			Text:      "This pull request has been merged.",
-		}
+		}, prVars)
 		p.postThreadNotificationWithAttachment(agent, mergedAttachment)
-	} else {
-		closedAttachment := &model.SlackAttachment{
+	} else if !muted {
+		closedAttachment := p.styleNotification(notificationEventPRClosed, &model.SlackAttachment{
 			Color:     "#8B8FA7", // grey
 			Title:     prTitle,
 			TitleLink: event.PullRequest.HTMLURL,
 			Text:      "This pull request was closed without merging.",
-		}
+		}, prVars)
 		p.postThreadNotificationWithAttachment(agent, closedAttachment)
 	}
 
 	// Update reaction on the trigger post for merged PRs.
 	if event.PullRequest.Merged {
-		p.swapReaction(agent.TriggerPostID, "white_check_mark", "rocket")
+		p.swapTerminalReaction(agent.ChannelID, agent.TriggerPostID, "white_check_mark", "rocket")
 	}
 
 	// Update agent status in KV store.
@@ -289,10 +393,10 @@ func (p *Plugin) handlePullRequestEvent(w http.ResponseWriter, body []byte) {
 
 // handlePRSynchronizeWebhook handles the synchronize action (new commits pushed) for a PR.
 // If the PR has an active review loop in the cursor_fixing phase, it triggers re-review.
-func (p *Plugin) handlePRSynchronizeWebhook(event PullRequestEvent, w http.ResponseWriter) {
+func (p *Plugin) handlePRSynchronizeWebhook(ctx context.Context, event PullRequestEvent, w http.ResponseWriter) {
 	loop, err := p.kvstore.GetReviewLoopByPRURL(event.PullRequest.HTMLURL)
 	if err != nil {
-		p.API.LogError("Failed to look up review loop for synchronize event",
+		p.logger(ctx).LogError("Failed to look up review loop for synchronize event",
 			"error", err.Error(),
 			"pr_url", event.PullRequest.HTMLURL,
 		)
@@ -300,14 +404,31 @@ func (p *Plugin) handlePRSynchronizeWebhook(event PullRequestEvent, w http.Respo
 		return
 	}
 
+	if loop != nil && p.stallReviewLoopIfExpired(loop) {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if loop != nil && !kvstore.IsReviewPhaseTerminal(loop.Phase) && p.holdPushForGlobalPause(loop, event.PullRequest) {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if loop != nil && loop.MergeConflictAt != 0 && loop.Phase != kvstore.ReviewPhaseCursorFixing {
+		// A push while merge conflicts are outstanding may have resolved them.
+		p.checkReviewLoopMergeConflict(loop)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
 	if loop == nil || loop.Phase != kvstore.ReviewPhaseCursorFixing {
 		// No active review loop or not in cursor_fixing phase -- ignore.
 		w.WriteHeader(http.StatusOK)
 		return
 	}
 
-	if err := p.handlePRSynchronize(loop, event.PullRequest); err != nil {
-		p.API.LogError("Failed to handle PR synchronize for review loop",
+	if err := p.handlePRSynchronize(ctx, loop, event.PullRequest); err != nil {
+		p.logger(ctx).LogError("Failed to handle PR synchronize for review loop",
 			"error", err.Error(),
 			"review_loop_id", loop.ID,
 		)
@@ -316,6 +437,50 @@ func (p *Plugin) handlePRSynchronizeWebhook(event PullRequestEvent, w http.Respo
 	w.WriteHeader(http.StatusOK)
 }
 
+// handlePRReadyForReview starts the review loop when a draft PR is marked
+// ready by hand. The loop normally starts when the agent finishes, but a PR
+// that was still a draft then (or opened as a draft manually) gets its loop
+// here. A PR that already has a loop is left alone.
+func (p *Plugin) handlePRReadyForReview(event PullRequestEvent, w http.ResponseWriter) {
+	agent := p.findAgentForPR(event.PullRequest)
+	if agent == nil {
+		p.API.LogDebug("No agent found for ready_for_review PR", "pr_url", event.PullRequest.HTMLURL)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	prURL := event.PullRequest.HTMLURL
+	if agent.PrURL == "" || agent.Draft {
+		// ensureReviewLoop finds the agent by PR URL, and a draft launch
+		// stops holding back its loop once the PR is ready.
+		if agent.PrURL == "" {
+			agent.PrURL = prURL
+		}
+		agent.Draft = false
+		agent.UpdatedAt = p.now().UnixMilli()
+		if err := p.kvstore.SaveAgent(agent); err != nil {
+			p.API.LogError("Failed to backfill agent from ready_for_review webhook",
+				"error", err.Error(),
+				"agent_id", agent.CursorAgentID,
+				"pr_url", prURL,
+			)
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		p.publishAgentStatusChange(agent)
+	}
+
+	if loop := p.ensureReviewLoop(prURL); loop != nil {
+		p.API.LogDebug("Review loop active for ready_for_review PR",
+			"review_loop_id", loop.ID,
+			"phase", loop.Phase,
+			"pr_url", prURL,
+		)
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
 // handlePROpened handles a newly opened PR. This is the PRIMARY path for:
 // 1. Linking a PR to an agent (backfilling PrURL)
 // 2. Starting the AI review loop
@@ -331,20 +496,28 @@ func (p *Plugin) handlePROpened(event PullRequestEvent, w http.ResponseWriter) {
 	prURL := event.PullRequest.HTMLURL
 	changed := false
 
-	// Step 1: Backfill PrURL if empty.
+	// Step 1: Backfill PrURL if empty. A further PR from an agent that
+	// already has one is kept as an additional PR.
 	if agent.PrURL == "" {
 		agent.PrURL = prURL
 		changed = true
+	} else if recordExtraPR(agent, prURL) {
+		changed = true
 	}
 
-	// Step 2: Backfill TargetBranch if empty.
+	// Step 2: Backfill TargetBranch and BaseBranch if empty.
 	if agent.TargetBranch == "" && event.PullRequest.Head.Ref != "" {
 		agent.TargetBranch = event.PullRequest.Head.Ref
 		changed = true
 	}
 
+	if agent.BaseBranch == "" && event.PullRequest.Base.Ref != "" {
+		agent.BaseBranch = event.PullRequest.Base.Ref
+		changed = true
+	}
+
 	if changed {
-		agent.UpdatedAt = time.Now().UnixMilli()
+		agent.UpdatedAt = p.now().UnixMilli()
 		if err := p.kvstore.SaveAgent(agent); err != nil {
 			p.API.LogError("Failed to backfill agent from PR opened webhook",
 				"error", err.Error(),
@@ -356,22 +529,40 @@ func (p *Plugin) handlePROpened(event PullRequestEvent, w http.ResponseWriter) {
 		p.publishAgentStatusChange(agent)
 	}
 
-	// Step 3: Post PR notification in thread.
-	prTitle := fmt.Sprintf("PR #%d: %s", event.PullRequest.Number, event.PullRequest.Title)
+	// Step 3: Post PR notification in thread. Draft PRs get their own style.
+	prEvent := notificationEventPROpened
 	prAttachment := &model.SlackAttachment{
 		Color:     "#2389D7", // blue
-		Title:     prTitle,
+		Title:     fmt.Sprintf("PR #%d: %s", event.PullRequest.Number, event.PullRequest.Title),
 		TitleLink: prURL,
 		Text:      fmt.Sprintf("Pull request opened on branch `%s`.", event.PullRequest.Head.Ref),
 	}
-	p.postThreadNotificationWithAttachment(agent, prAttachment)
+	if event.PullRequest.Draft {
+		prEvent = notificationEventPRDraftOpened
+		prAttachment.Color = "#FFBC1F" // amber
+		prAttachment.Title = fmt.Sprintf("Draft PR #%d: %s", event.PullRequest.Number, event.PullRequest.Title)
+		prAttachment.Text = fmt.Sprintf("Draft pull request opened on branch `%s`.", event.PullRequest.Head.Ref)
+	}
+	prAttachment = p.styleNotification(prEvent, prAttachment, prNotificationVars(event.PullRequest, ""))
+	if !p.prNotificationsMuted(event.PullRequest) {
+		p.postThreadNotificationWithAttachment(agent, prAttachment)
+	}
+	p.postApprovedPlanToPR(agent, prURL)
 
 	// Step 4: Start review loop if agent is FINISHED and review loop is enabled.
 	// If agent is still RUNNING, the poller will handle it when it detects FINISHED.
+	// Additional PRs only get a loop when ReviewLoopStartForExtraPRs is enabled,
+	// and draft launches wait for the PR to be marked ready when
+	// ReviewLoopSkipDraftPRs is enabled.
+	loopPRURL := agent.PrURL
+	if isExtraPR(agent, prURL) {
+		loopPRURL = prURL
+	}
 	if cursor.AgentStatus(agent.Status).IsTerminal() &&
 		p.getConfiguration().EnableAIReviewLoop &&
-		p.getGitHubClient() != nil {
-		if err := p.startReviewLoop(agent); err != nil {
+		p.getGitHubClient() != nil &&
+		p.reviewLoopAllowedForPR(agent, loopPRURL) {
+		if err := p.startReviewLoopForPR(agent, loopPRURL); err != nil {
 			p.API.LogError("Failed to start review loop from PR opened webhook",
 				"error", err.Error(),
 				"agent_id", agent.CursorAgentID,
@@ -383,13 +574,14 @@ func (p *Plugin) handlePROpened(event PullRequestEvent, w http.ResponseWriter) {
 	w.WriteHeader(http.StatusOK)
 }
 
-func (p *Plugin) handlePullRequestReviewEvent(w http.ResponseWriter, body []byte) {
+func (p *Plugin) handlePullRequestReviewEvent(ctx context.Context, w http.ResponseWriter, body []byte) {
 	var event PullRequestReviewEvent
-	if err := json.Unmarshal(body, &event); err != nil {
-		p.API.LogWarn("Failed to parse pull_request_review event", "error", err.Error())
+	if err := parseWebhookEvent(body, &event); err != nil {
+		p.logger(ctx).LogWarn("Failed to parse pull_request_review event", "error", err.Error())
 		http.Error(w, "invalid payload", http.StatusBadRequest)
 		return
 	}
+	p.logUnknownWebhookFields(eventPullRequestReview, &event.webhookPayload)
 
 	// Only handle submitted reviews (not edited/dismissed).
 	if event.Action != reviewActionSubmitted {
@@ -398,15 +590,20 @@ func (p *Plugin) handlePullRequestReviewEvent(w http.ResponseWriter, body []byte
 	}
 
 	// --- Review Loop phase-aware gating ---
-	reviewerType := p.reviewerTypeForLogin(event.Review.User.Login)
+	reviewerType := p.reviewerTypeForLogin(event.Review.User.Login, "")
 	loop := p.ensureReviewLoop(event.PullRequest.HTMLURL)
 	if loop != nil {
+		var unlock func()
+		loop, unlock = p.lockReviewLoop(loop)
+		defer unlock()
+	}
+	if loop != nil && !p.stallReviewLoopIfExpired(loop) {
 		switch loop.Phase {
 		case kvstore.ReviewPhaseAwaitingReview:
 			// AI reviews in awaiting_review drive CodeRabbit gate + fix iterations.
 			if reviewerType == reviewerTypeAIBot {
-				if err := p.handleAIReview(loop, event.Review, event.PullRequest); err != nil {
-					p.API.LogError("Failed to handle AI review",
+				if err := p.handleAIReview(ctx, loop, event.Review, event.PullRequest); err != nil {
+					p.logger(ctx).LogError("Failed to handle AI review",
 						"error", err.Error(),
 						"review_loop_id", loop.ID,
 					)
@@ -416,24 +613,33 @@ func (p *Plugin) handlePullRequestReviewEvent(w http.ResponseWriter, body []byte
 			}
 		case kvstore.ReviewPhaseHumanReview:
 			// Human approval is terminal; only explicit changes_requested should
-			// trigger another cursor_fixing iteration. Plain commented reviews
-			// remain informational.
+			// trigger another cursor_fixing iteration, and only while human
+			// review fixing is enabled. Plain commented reviews remain
+			// informational.
 			if reviewerType == reviewerTypeHuman {
 				if strings.EqualFold(event.Review.State, reviewStateApproved) {
 					if err := p.handleHumanReviewApproval(loop, event.Review.User.Login); err != nil {
-						p.API.LogError("Failed to handle human review approval",
+						p.logger(ctx).LogError("Failed to handle human review approval",
 							"error", err.Error(),
 							"review_loop_id", loop.ID,
 						)
 					}
 				} else if strings.EqualFold(event.Review.State, reviewStateChangesRequested) {
-					if err := p.handleHumanReviewFeedback(loop, event.Review, event.PullRequest); err != nil {
-						p.API.LogError("Failed to handle human review feedback",
+					if err := p.handleHumanReviewFeedback(ctx, loop, event.Review, event.PullRequest); err != nil {
+						p.logger(ctx).LogError("Failed to handle human review feedback",
 							"error", err.Error(),
 							"review_loop_id", loop.ID,
 						)
 					}
 				}
+			} else if reviewerType == reviewerTypeAIBot {
+				// New AI findings (e.g. after a rebase) can reopen the AI gate.
+				if err := p.reopenAIReviewGate(ctx, loop, event.Review, event.PullRequest); err != nil {
+					p.logger(ctx).LogError("Failed to reopen AI review gate",
+						"error", err.Error(),
+						"review_loop_id", loop.ID,
+					)
+				}
 			}
 		}
 	}
@@ -441,7 +647,7 @@ func (p *Plugin) handlePullRequestReviewEvent(w http.ResponseWriter, body []byte
 	// --- Existing: Look up the agent and post notification ---
 	agent := p.findAgentForPR(event.PullRequest)
 	if agent == nil {
-		p.API.LogDebug("No agent found for reviewed PR", "pr_url", event.PullRequest.HTMLURL)
+		p.logger(ctx).LogDebug("No agent found for reviewed PR", "pr_url", event.PullRequest.HTMLURL)
 		w.WriteHeader(http.StatusOK)
 		return
 	}
@@ -449,11 +655,16 @@ func (p *Plugin) handlePullRequestReviewEvent(w http.ResponseWriter, body []byte
 	// Backfill PrURL if empty (agent may have finished before PR was linked).
 	if agent.PrURL == "" && event.PullRequest.HTMLURL != "" {
 		agent.PrURL = event.PullRequest.HTMLURL
-		agent.UpdatedAt = time.Now().UnixMilli()
+		agent.UpdatedAt = p.now().UnixMilli()
 		_ = p.kvstore.SaveAgent(agent)
 		p.publishAgentStatusChange(agent)
 	}
 
+	if p.prNotificationsMuted(event.PullRequest) {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
 	reviewer := event.Review.User.Login
 	prNumber := event.PullRequest.Number
 	reviewURL := event.Review.HTMLURL
@@ -461,9 +672,11 @@ func (p *Plugin) handlePullRequestReviewEvent(w http.ResponseWriter, body []byte
 	prTitle := fmt.Sprintf("PR #%d", prNumber)
 
 	var reviewAttachment *model.SlackAttachment
+	var notificationEvent string
 
 	switch event.Review.State {
 	case reviewStateApproved:
+		notificationEvent = notificationEventReviewApproved
 		reviewAttachment = &model.SlackAttachment{
 			Color:     "#3DB887", // green
 			Title:     fmt.Sprintf("%s approved by %s", prTitle, reviewer),
@@ -471,6 +684,7 @@ func (p *Plugin) handlePullRequestReviewEvent(w http.ResponseWriter, body []byte
 		}
 	case reviewStateChangesRequested:
 		bodyText := truncateText(sanitizeReviewBodyForMattermost(event.Review.Body), 200)
+		notificationEvent = notificationEventReviewChangesRequested
 		reviewAttachment = &model.SlackAttachment{
 			Color:     "#D24B4E", // red (changes requested)
 			Title:     fmt.Sprintf("%s: %s requested changes", prTitle, reviewer),
@@ -483,6 +697,7 @@ func (p *Plugin) handlePullRequestReviewEvent(w http.ResponseWriter, body []byte
 			w.WriteHeader(http.StatusOK)
 			return
 		}
+		notificationEvent = notificationEventReviewCommented
 		reviewAttachment = &model.SlackAttachment{
 			Color:     "#2389D7", // blue
 			Title:     fmt.Sprintf("%s: %s commented", prTitle, reviewer),
@@ -490,16 +705,53 @@ func (p *Plugin) handlePullRequestReviewEvent(w http.ResponseWriter, body []byte
 			Text:      bodyText,
 		}
 	default:
-		p.API.LogDebug("Unhandled review state", "state", event.Review.State)
+		p.logger(ctx).LogDebug("Unhandled review state", "state", event.Review.State)
 		w.WriteHeader(http.StatusOK)
 		return
 	}
 
+	p.styleNotification(notificationEvent, reviewAttachment, prNotificationVars(event.PullRequest, reviewer))
 	p.postThreadNotificationWithAttachment(agent, reviewAttachment)
 
 	w.WriteHeader(http.StatusOK)
 }
 
+// handlePullRequestReviewCommentEvent reacts to a single new inline review
+// comment, so AI feedback left outside a formal review reaches the agent
+// without waiting for the next push. Replies are ignored, and no thread
+// notification is posted per comment.
+func (p *Plugin) handlePullRequestReviewCommentEvent(ctx context.Context, w http.ResponseWriter, body []byte) {
+	var event PullRequestReviewCommentEvent
+	if err := parseWebhookEvent(body, &event); err != nil {
+		p.logger(ctx).LogWarn("Failed to parse pull_request_review_comment event", "error", err.Error())
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+	p.logUnknownWebhookFields(eventPullRequestReviewComment, &event.webhookPayload)
+
+	if event.Action != reviewCommentActionCreated || event.Comment.InReplyToID != 0 {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	loop := p.ensureReviewLoop(event.PullRequest.HTMLURL)
+	if loop != nil {
+		var unlock func()
+		loop, unlock = p.lockReviewLoop(loop)
+		defer unlock()
+	}
+	if loop != nil && !p.stallReviewLoopIfExpired(loop) {
+		if err := p.handleAIInlineComment(ctx, loop, event.Comment, event.PullRequest); err != nil {
+			p.logger(ctx).LogError("Failed to handle AI inline review comment",
+				"error", err.Error(),
+				"review_loop_id", loop.ID,
+			)
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
 // --- Agent lookup ---
 
 // findAgentForPR looks up a Cursor agent record associated with the given PR.
@@ -526,6 +778,19 @@ func (p *Plugin) findAgentForPR(pr ghPullRequest) *kvstore.AgentRecord {
 
 // --- Helpers ---
 
+// prNotificationsMuted reports whether thread notifications for pr are
+// suppressed because its author is listed in MutedPRAuthors.
+func (p *Plugin) prNotificationsMuted(pr ghPullRequest) bool {
+	if !p.getConfiguration().IsMutedPRAuthor(pr.User.Login) {
+		return false
+	}
+	p.API.LogDebug("Skipping notification for PR by muted author",
+		"pr_url", pr.HTMLURL,
+		"author", pr.User.Login,
+	)
+	return true
+}
+
 // postThreadNotificationWithAttachment posts a SlackAttachment in the agent's Mattermost thread.
 func (p *Plugin) postThreadNotificationWithAttachment(agent *kvstore.AgentRecord, attachment *model.SlackAttachment) {
 	if agent.PostID == "" {
@@ -535,13 +800,13 @@ func (p *Plugin) postThreadNotificationWithAttachment(agent *kvstore.AgentRecord
 	}
 
 	post := &model.Post{
-		UserId:    p.getBotUserID(),
+		UserId:    p.botUserIDForChannel(agent.ChannelID),
 		ChannelId: agent.ChannelID,
 		RootId:    agent.PostID,
 	}
 	model.ParseSlackAttachment(post, []*model.SlackAttachment{attachment})
 
-	if _, appErr := p.API.CreatePost(post); appErr != nil {
+	if _, appErr := p.API.CreatePost(p.decorateBotPost(post)); appErr != nil {
 		p.API.LogError("Failed to post GitHub notification attachment in thread",
 			"error", appErr.Error(),
 			"agent_id", agent.CursorAgentID,
@@ -551,12 +816,12 @@ func (p *Plugin) postThreadNotificationWithAttachment(agent *kvstore.AgentRecord
 }
 
 // swapReaction removes one reaction and adds another on the trigger post.
-func (p *Plugin) swapReaction(postID, removeEmoji, addEmoji string) {
+func (p *Plugin) swapReaction(channelID, postID, removeEmoji, addEmoji string) {
 	if postID == "" {
 		return
 	}
-	p.removeReaction(postID, removeEmoji)
-	p.addReaction(postID, addEmoji)
+	p.removeReaction(channelID, postID, removeEmoji)
+	p.addReaction(channelID, postID, addEmoji)
 }
 
 // sanitizeReviewBodyForMattermost converts common HTML tags (from tools like
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/mattermost/mattermost-plugin-cursor/server/store/kvstore"
+)
+
+const (
+	// maxRecordedWebhookDeliveries is how many recent deliveries are kept
+	// while RecordWebhookDeliveries is on.
+	maxRecordedWebhookDeliveries = 50
+
+	// maxRecordedWebhookBodyBytes caps the stored payload. Larger payloads are
+	// cut and cannot be replayed.
+	maxRecordedWebhookBodyBytes = 64 * 1024
+)
+
+// recordWebhookDelivery stores a verified delivery and how it was handled
+// when RecordWebhookDeliveries is on. Failures are logged and never affect
+// webhook handling.
+func (p *Plugin) recordWebhookDelivery(deliveryID, eventType string, body []byte, status int, duplicate bool) {
+	if !p.getConfiguration().RecordWebhookDeliveries || deliveryID == "" {
+		return
+	}
+
+	var envelope struct {
+		Action string `json:"action"`
+	}
+	_ = json.Unmarshal(body, &envelope)
+
+	delivery := &kvstore.WebhookDelivery{
+		DeliveryID: deliveryID,
+		Event:      eventType,
+		Action:     envelope.Action,
+		Body:       string(body),
+		Status:     status,
+		Duplicate:  duplicate,
+		ReceivedAt: p.now().UnixMilli(),
+	}
+	if len(body) > maxRecordedWebhookBodyBytes {
+		delivery.Body = string(body[:maxRecordedWebhookBodyBytes])
+		delivery.Truncated = true
+	}
+
+	if err := p.kvstore.SaveWebhookDelivery(delivery, maxRecordedWebhookDeliveries); err != nil {
+		p.API.LogWarn("Failed to record GitHub webhook delivery",
+			"delivery", deliveryID,
+			"error", err.Error(),
+		)
+	}
+}
+
+// replayWebhookDelivery re-runs the event handler against a recorded payload.
+// The processed-delivery check is bypassed and the delivery is not marked
+// processed again. Returns the handler's status and response body.
+func (p *Plugin) replayWebhookDelivery(delivery *kvstore.WebhookDelivery) (int, string) {
+	rw := &bufferedResponseWriter{header: http.Header{}}
+	sr := &statusRecorder{ResponseWriter: rw, status: http.StatusOK}
+
+	p.API.LogInfo("Replaying GitHub webhook delivery",
+		"delivery", delivery.DeliveryID,
+		"event", delivery.Event,
+	)
+	ctx := p.withDeliveryLogger(context.Background(), delivery.DeliveryID)
+	p.routeGitHubEvent(ctx, sr, delivery.Event, []byte(delivery.Body))
+	return sr.status, rw.body.String()
+}
+
+// bufferedResponseWriter collects a handler's response in memory.
+type bufferedResponseWriter struct {
+	header http.Header
+	body   bytes.Buffer
+}
+
+func (w *bufferedResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *bufferedResponseWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
+
+func (w *bufferedResponseWriter) WriteHeader(int) {}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// webhookPayload retains the raw body of a GitHub webhook event along with
+// any top-level fields the typed event does not model. GitHub adds fields
+// over time; keeping them lets debugging and failed-delivery tooling see the
+// full payload without the typed parse ever rejecting it.
+type webhookPayload struct {
+	Raw     json.RawMessage            `json:"-"`
+	Unknown map[string]json.RawMessage `json:"-"`
+}
+
+func (w *webhookPayload) payload() *webhookPayload {
+	return w
+}
+
+// UnknownFields returns the names of unmodeled top-level fields, sorted.
+func (w *webhookPayload) UnknownFields() []string {
+	names := make([]string, 0, len(w.Unknown))
+	for name := range w.Unknown {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// webhookEvent is a typed GitHub event that embeds webhookPayload.
+type webhookEvent interface {
+	payload() *webhookPayload
+}
+
+// parseWebhookEvent decodes body into event, then records the raw body and
+// the top-level fields that event has no json tag for. Only malformed JSON
+// is an error; unknown fields never are.
+func parseWebhookEvent(body []byte, event webhookEvent) error {
+	if err := json.Unmarshal(body, event); err != nil {
+		return err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return err
+	}
+
+	known := knownJSONFields(reflect.TypeOf(event))
+	unknown := make(map[string]json.RawMessage)
+	for name, value := range fields {
+		if !known[name] {
+			unknown[name] = value
+		}
+	}
+
+	payload := event.payload()
+	payload.Raw = append(json.RawMessage(nil), body...)
+	if len(unknown) > 0 {
+		payload.Unknown = unknown
+	}
+	return nil
+}
+
+// knownJSONFields returns the top-level json field names of the struct t
+// (or *t) decodes into.
+func knownJSONFields(t reflect.Type) map[string]bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	known := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		switch name {
+		case "-":
+			continue
+		case "":
+			known[field.Name] = true
+		default:
+			known[name] = true
+		}
+	}
+	return known
+}
+
+// logUnknownWebhookFields notes unmodeled top-level fields at debug level so
+// new GitHub payload fields are visible without failing the delivery.
+func (p *Plugin) logUnknownWebhookFields(eventType string, payload *webhookPayload) {
+	if len(payload.Unknown) == 0 {
+		return
+	}
+	p.logDebug("GitHub webhook payload has unrecognized fields",
+		"event", eventType,
+		"fields", strings.Join(payload.UnknownFields(), ","),
+	)
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWebhookEvent_RetainsUnknownFields(t *testing.T) {
+	body := []byte(`{
+		"action": "submitted",
+		"review": {"state": "approved", "body": "LGTM", "user": {"login": "coderabbitai[bot]"}, "brand_new": true},
+		"pull_request": {"number": 42, "html_url": "https://github.com/org/repo/pull/42", "head": {"ref": "fix", "sha": "abc"}},
+		"repository": {"full_name": "org/repo"},
+		"sender": {"login": "coderabbitai[bot]"},
+		"installation": {"id": 99},
+		"enterprise": null,
+		"future_field": ["a", "b"]
+	}`)
+
+	var event PullRequestReviewEvent
+	require.NoError(t, parseWebhookEvent(body, &event))
+
+	assert.Equal(t, "submitted", event.Action)
+	assert.Equal(t, reviewStateApproved, event.Review.State)
+	assert.Equal(t, "coderabbitai[bot]", event.Review.User.Login)
+	assert.Equal(t, 42, event.PullRequest.Number)
+	assert.Equal(t, "abc", event.PullRequest.Head.SHA)
+	assert.Equal(t, "org/repo", event.Repository.FullName)
+
+	assert.Equal(t, []string{"enterprise", "future_field", "installation"}, event.UnknownFields())
+	assert.JSONEq(t, `{"id": 99}`, string(event.Unknown["installation"]))
+	assert.JSONEq(t, string(body), string(event.Raw))
+}
+
+func TestParseWebhookEvent_NoUnknownFields(t *testing.T) {
+	body := []byte(`{"action": "opened", "pull_request": {"number": 7}, "repository": {}, "sender": {}}`)
+
+	var event PullRequestEvent
+	require.NoError(t, parseWebhookEvent(body, &event))
+
+	assert.Equal(t, prActionOpened, event.Action)
+	assert.Equal(t, 7, event.PullRequest.Number)
+	assert.Nil(t, event.Unknown)
+	assert.Empty(t, event.UnknownFields())
+	assert.NotEmpty(t, event.Raw)
+}
+
+func TestParseWebhookEvent_MalformedJSON(t *testing.T) {
+	var event PullRequestEvent
+	err := parseWebhookEvent([]byte(`{"action": "opened"`), &event)
+
+	var syntaxErr *json.SyntaxError
+	assert.ErrorAs(t, err, &syntaxErr)
+}
@@ -6,9 +6,11 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/google/go-github/v68/github"
@@ -133,6 +135,53 @@ func TestWebhook_InvalidSignature(t *testing.T) {
 	assert.Contains(t, rr.Body.String(), "invalid signature")
 }
 
+func TestWebhook_WrongContentType(t *testing.T) {
+	p, _ := setupWebhookTestPlugin(t)
+
+	body := []byte(`payload=%7B%7D`)
+	req := makeWebhookRequest(t, "ping", "delivery-1", body, signPayload(testWebhookSecret, body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rr := httptest.NewRecorder()
+
+	p.handleGitHubWebhook(rr, req)
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, rr.Code)
+	assert.Contains(t, rr.Body.String(), "unsupported content type")
+}
+
+func TestWebhook_JSONContentTypeWithCharset(t *testing.T) {
+	p, _ := setupWebhookTestPlugin(t)
+
+	body := []byte(`{"zen":"Keep it logically awesome.","hook_id":1}`)
+	req := makeWebhookRequest(t, "ping", "", body, signPayload(testWebhookSecret, body))
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	rr := httptest.NewRecorder()
+
+	p.handleGitHubWebhook(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestWebhook_OversizedBody(t *testing.T) {
+	p, _ := setupWebhookTestPlugin(t)
+	p.configuration.WebhookMaxBodySizeKB = 1
+
+	body := []byte(`{"zen":"` + strings.Repeat("a", 2048) + `"}`)
+	req := makeWebhookRequest(t, "ping", "delivery-1", body, signPayload(testWebhookSecret, body))
+	rr := httptest.NewRecorder()
+
+	p.handleGitHubWebhook(rr, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+	assert.Contains(t, rr.Body.String(), "request body too large")
+}
+
+func TestConfigurationGetMaxWebhookBodySize(t *testing.T) {
+	assert.Equal(t, int64(5*1024*1024), (&configuration{}).GetMaxWebhookBodySize())
+	assert.Equal(t, int64(5*1024*1024), (&configuration{WebhookMaxBodySizeKB: -1}).GetMaxWebhookBodySize())
+	assert.Equal(t, int64(64*1024), (&configuration{WebhookMaxBodySizeKB: 64}).GetMaxWebhookBodySize())
+}
+
 func TestWebhook_PingEvent(t *testing.T) {
 	p, store := setupWebhookTestPlugin(t)
 
@@ -281,6 +330,83 @@ func TestWebhook_PRClosedNotMerged(t *testing.T) {
 	store.AssertExpectations(t)
 }
 
+// mutedAuthorMergedEvent returns a signed merged-PR webhook delivery for a PR
+// authored by login, with the agent lookup and status save mocked.
+func mutedAuthorMergedEvent(t *testing.T, store *mockKVStore, login, deliveryID string) *http.Request {
+	t.Helper()
+	agent := &kvstore.AgentRecord{
+		CursorAgentID: "agent-123",
+		PostID:        "root-post-1",
+		ChannelID:     "ch-1",
+		UserID:        "user-1",
+		Status:        "FINISHED",
+		PrURL:         "https://github.com/org/repo/pull/42",
+	}
+
+	event := PullRequestEvent{
+		Action: "closed",
+		PullRequest: ghPullRequest{
+			Number:  42,
+			HTMLURL: "https://github.com/org/repo/pull/42",
+			Title:   "Bump golang.org/x/net",
+			State:   "closed",
+			Merged:  true,
+		},
+	}
+	event.PullRequest.User.Login = login
+	body, _ := json.Marshal(event)
+
+	store.On("HasDeliveryBeenProcessed", deliveryID).Return(false, nil)
+	store.On("MarkDeliveryProcessed", deliveryID).Return(nil)
+	store.On("GetAgentByPRURL", "https://github.com/org/repo/pull/42").Return(agent, nil)
+	store.On("SaveAgent", mock.MatchedBy(func(r *kvstore.AgentRecord) bool {
+		return r.CursorAgentID == "agent-123" && r.Status == "MERGED"
+	})).Return(nil)
+
+	return makeWebhookRequest(t, "pull_request", deliveryID, body, signPayload(testWebhookSecret, body))
+}
+
+func TestWebhook_PRByMutedAuthorPostsNoNotification(t *testing.T) {
+	p, store := setupWebhookTestPlugin(t)
+	p.configuration.MutedPRAuthors = "dependabot, renovate"
+	api := p.API.(*mockPluginAPI)
+
+	rr := httptest.NewRecorder()
+	p.handleGitHubWebhook(rr, mutedAuthorMergedEvent(t, store, "dependabot[bot]", "delivery-muted"))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	api.AssertNotCalled(t, "CreatePost", mock.Anything)
+	store.AssertExpectations(t)
+}
+
+func TestWebhook_PRByHumanAuthorStillNotifies(t *testing.T) {
+	p, store := setupWebhookTestPlugin(t)
+	p.configuration.MutedPRAuthors = "dependabot, renovate"
+	api := p.API.(*mockPluginAPI)
+
+	api.On("CreatePost", mock.MatchedBy(func(p *model.Post) bool {
+		return p.RootId == "root-post-1" && hasAttachmentWithColor(p, "#3DB887")
+	})).Return(&model.Post{Id: "notification-1"}, nil).Once()
+
+	rr := httptest.NewRecorder()
+	p.handleGitHubWebhook(rr, mutedAuthorMergedEvent(t, store, "octocat", "delivery-human"))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	api.AssertExpectations(t)
+	store.AssertExpectations(t)
+}
+
+func TestIsMutedPRAuthor(t *testing.T) {
+	cfg := &configuration{MutedPRAuthors: " Dependabot[bot], renovate ,"}
+
+	assert.True(t, cfg.IsMutedPRAuthor("dependabot[bot]"))
+	assert.True(t, cfg.IsMutedPRAuthor("dependabot"))
+	assert.True(t, cfg.IsMutedPRAuthor("renovate[bot]"))
+	assert.False(t, cfg.IsMutedPRAuthor("octocat"))
+	assert.False(t, cfg.IsMutedPRAuthor(""))
+	assert.False(t, (&configuration{}).IsMutedPRAuthor("dependabot[bot]"))
+}
+
 func TestWebhook_PROpened_NoAgentFound(t *testing.T) {
 	p, store := setupWebhookTestPlugin(t)
 
@@ -311,6 +437,34 @@ func TestWebhook_PROpened_NoAgentFound(t *testing.T) {
 	store.AssertNotCalled(t, "SaveAgent")
 }
 
+func TestWebhook_PROpened_UnknownFieldsTolerated(t *testing.T) {
+	p, store := setupWebhookTestPlugin(t)
+
+	body := []byte(`{
+		"action": "opened",
+		"number": 10,
+		"pull_request": {"number": 10, "html_url": "https://github.com/org/repo/pull/10", "head": {"ref": "cursor/new-feature", "repo": {"id": 1}}, "auto_merge": null},
+		"repository": {"full_name": "org/repo"},
+		"sender": {"login": "octocat"},
+		"installation": {"id": 99},
+		"performed_via_github_app": {"slug": "new-thing"}
+	}`)
+	sig := signPayload(testWebhookSecret, body)
+
+	store.On("HasDeliveryBeenProcessed", "delivery-pr-opened-unknown").Return(false, nil)
+	store.On("MarkDeliveryProcessed", "delivery-pr-opened-unknown").Return(nil)
+	store.On("GetAgentByPRURL", "https://github.com/org/repo/pull/10").Return(nil, nil)
+	store.On("GetAgentByBranch", "cursor/new-feature").Return(nil, nil)
+
+	req := makeWebhookRequest(t, "pull_request", "delivery-pr-opened-unknown", body, sig)
+	rr := httptest.NewRecorder()
+
+	p.handleGitHubWebhook(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	store.AssertExpectations(t)
+}
+
 func TestWebhook_PROpened_BackfillsPrURL(t *testing.T) {
 	p, store := setupWebhookTestPlugin(t)
 	api := p.API.(*mockPluginAPI)
@@ -334,6 +488,7 @@ func TestWebhook_PROpened_BackfillsPrURL(t *testing.T) {
 		},
 	}
 	event.PullRequest.Head.Ref = "cursor/new-feature"
+	event.PullRequest.Base.Ref = "main"
 	body, _ := json.Marshal(event)
 	sig := signPayload(testWebhookSecret, body)
 
@@ -342,11 +497,12 @@ func TestWebhook_PROpened_BackfillsPrURL(t *testing.T) {
 	store.On("GetAgentByPRURL", "https://github.com/org/repo/pull/10").Return(nil, nil)
 	store.On("GetAgentByBranch", "cursor/new-feature").Return(agent, nil)
 
-	// Expect SaveAgent with backfilled PrURL and TargetBranch.
+	// Expect SaveAgent with backfilled PrURL, TargetBranch and BaseBranch.
 	store.On("SaveAgent", mock.MatchedBy(func(r *kvstore.AgentRecord) bool {
 		return r.CursorAgentID == "agent-opened-1" &&
 			r.PrURL == "https://github.com/org/repo/pull/10" &&
 			r.TargetBranch == "cursor/new-feature" &&
+			r.BaseBranch == "main" &&
 			r.UpdatedAt > 0
 	})).Return(nil)
 
@@ -489,6 +645,194 @@ func TestWebhook_PROpened_StartsReviewLoop(t *testing.T) {
 	store.AssertCalled(t, "SaveReviewLoop", mock.Anything)
 }
 
+func TestWebhook_PRReadyForReview_BootstrapsReviewLoop(t *testing.T) {
+	p, store := setupWebhookTestPlugin(t)
+	api := p.API.(*mockPluginAPI)
+
+	agent := &kvstore.AgentRecord{
+		CursorAgentID: "agent-draft-1",
+		PostID:        "root-post-draft",
+		TriggerPostID: "trigger-post-draft",
+		ChannelID:     "ch-draft",
+		UserID:        "user-1",
+		Status:        "FINISHED",
+		PrURL:         "https://github.com/org/repo/pull/13",
+		Repository:    "org/repo",
+	}
+
+	p.configuration.EnableAIReviewLoop = true
+	p.configuration.AIReviewerBots = "coderabbitai[bot]"
+	p.configuration.MaxReviewIterations = 5
+
+	mockGH := &mockGitHubClient{}
+	p.githubClient = mockGH
+
+	event := PullRequestEvent{
+		Action: "ready_for_review",
+		PullRequest: ghPullRequest{
+			Number:  13,
+			HTMLURL: "https://github.com/org/repo/pull/13",
+			Title:   "Manual draft",
+		},
+	}
+	event.PullRequest.Head.Ref = "cursor/manual-draft"
+	body, _ := json.Marshal(event)
+	sig := signPayload(testWebhookSecret, body)
+
+	store.On("HasDeliveryBeenProcessed", "delivery-pr-ready").Return(false, nil)
+	store.On("MarkDeliveryProcessed", "delivery-pr-ready").Return(nil)
+	store.On("GetAgentByPRURL", "https://github.com/org/repo/pull/13").Return(agent, nil)
+	store.On("GetReviewLoopByPRURL", "https://github.com/org/repo/pull/13").Return(nil, nil)
+	store.On("GetWorkflowByAgent", "agent-draft-1").Return("", nil)
+	store.On("SaveReviewLoop", mock.Anything).Return(nil)
+	store.On("GetAgent", "agent-draft-1").Return(agent, nil).Maybe()
+
+	mockGH.On("MarkPRReadyForReview", mock.Anything, "org", "repo", 13).Return(nil)
+	mockGH.On("RequestReviewers", mock.Anything, "org", "repo", 13, mock.Anything).Return(nil)
+
+	api.On("PublishWebSocketEvent", mock.Anything, mock.Anything, mock.Anything).Return().Maybe()
+	api.On("GetPost", mock.Anything).Return(&model.Post{Id: "reply-1"}, nil).Maybe()
+	api.On("UpdatePost", mock.Anything).Return(&model.Post{}, nil).Maybe()
+	api.On("AddReaction", mock.Anything).Return(nil, nil).Maybe()
+
+	req := makeWebhookRequest(t, "pull_request", "delivery-pr-ready", body, sig)
+	rr := httptest.NewRecorder()
+
+	p.handleGitHubWebhook(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	store.AssertCalled(t, "SaveReviewLoop", mock.MatchedBy(func(loop *kvstore.ReviewLoop) bool {
+		return loop.PRURL == "https://github.com/org/repo/pull/13" &&
+			loop.AgentRecordID == "agent-draft-1" &&
+			loop.Phase == kvstore.ReviewPhaseAwaitingReview
+	}))
+	mockGH.AssertExpectations(t)
+}
+
+func TestWebhook_PRReadyForReview_ExistingLoopNoops(t *testing.T) {
+	p, store := setupWebhookTestPlugin(t)
+
+	agent := &kvstore.AgentRecord{
+		CursorAgentID: "agent-draft-2",
+		Status:        "FINISHED",
+		PrURL:         "https://github.com/org/repo/pull/14",
+	}
+	existing := &kvstore.ReviewLoop{
+		ID:     "loop-existing",
+		PRURL:  "https://github.com/org/repo/pull/14",
+		Phase:  kvstore.ReviewPhaseCursorFixing,
+		UserID: "user-1",
+	}
+
+	p.configuration.EnableAIReviewLoop = true
+	mockGH := &mockGitHubClient{}
+	p.githubClient = mockGH
+
+	event := PullRequestEvent{
+		Action: "ready_for_review",
+		PullRequest: ghPullRequest{
+			Number:  14,
+			HTMLURL: "https://github.com/org/repo/pull/14",
+		},
+	}
+	body, _ := json.Marshal(event)
+	sig := signPayload(testWebhookSecret, body)
+
+	store.On("HasDeliveryBeenProcessed", "delivery-pr-ready-existing").Return(false, nil)
+	store.On("MarkDeliveryProcessed", "delivery-pr-ready-existing").Return(nil)
+	store.On("GetAgentByPRURL", "https://github.com/org/repo/pull/14").Return(agent, nil)
+	store.On("GetReviewLoopByPRURL", "https://github.com/org/repo/pull/14").Return(existing, nil)
+
+	req := makeWebhookRequest(t, "pull_request", "delivery-pr-ready-existing", body, sig)
+	rr := httptest.NewRecorder()
+
+	p.handleGitHubWebhook(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, kvstore.ReviewPhaseCursorFixing, existing.Phase)
+	store.AssertNotCalled(t, "SaveReviewLoop", mock.Anything)
+	store.AssertNotCalled(t, "SaveAgent", mock.Anything)
+	mockGH.AssertNotCalled(t, "MarkPRReadyForReview", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestWebhook_PREdited_BaseChangeUpdatesLoop(t *testing.T) {
+	p, store := setupWebhookTestPlugin(t)
+	api := p.API.(*mockPluginAPI)
+	p.configuration.ReviewLoopSyncBaseOnEdit = true
+
+	loop := &kvstore.ReviewLoop{
+		ID:            "loop-1",
+		AgentRecordID: "agent-1",
+		PRURL:         "https://github.com/org/repo/pull/15",
+		Repository:    "org/repo",
+		Owner:         "org",
+		Repo:          "repo",
+		BaseBranch:    "main",
+		Phase:         kvstore.ReviewPhaseAwaitingReview,
+	}
+	record := &kvstore.AgentRecord{
+		CursorAgentID: "agent-1",
+		UserID:        "user-1",
+		BaseBranch:    "main",
+	}
+
+	body := []byte(`{
+		"action": "edited",
+		"changes": {"base": {"ref": {"from": "main"}, "sha": {"from": "abc123"}}},
+		"pull_request": {"number": 15, "html_url": "https://github.com/org/repo/pull/15", "base": {"ref": "release-1.2"}},
+		"repository": {"full_name": "org/repo"}
+	}`)
+	sig := signPayload(testWebhookSecret, body)
+
+	store.On("HasDeliveryBeenProcessed", "delivery-pr-edited").Return(false, nil)
+	store.On("MarkDeliveryProcessed", "delivery-pr-edited").Return(nil)
+	store.On("GetReviewLoopByPRURL", "https://github.com/org/repo/pull/15").Return(loop, nil)
+	store.On("SaveReviewLoop", loop).Return(nil).Once()
+	store.On("GetAgent", "agent-1").Return(record, nil)
+	store.On("SaveAgent", record).Return(nil).Once()
+	api.On("PublishWebSocketEvent", mock.Anything, mock.Anything, mock.Anything).Return().Maybe()
+
+	req := makeWebhookRequest(t, "pull_request", "delivery-pr-edited", body, sig)
+	rr := httptest.NewRecorder()
+
+	p.handleGitHubWebhook(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	store.AssertExpectations(t)
+	assert.Equal(t, "release-1.2", loop.BaseBranch)
+	assert.Equal(t, "org", loop.Owner)
+	assert.Equal(t, "repo", loop.Repo)
+	require.Len(t, loop.History, 1)
+	assert.Equal(t, kvstore.ReviewPhaseAwaitingReview, loop.History[0].Phase)
+	assert.Equal(t, "Base branch changed from `main` to `release-1.2`", loop.History[0].Detail)
+	assert.Equal(t, "release-1.2", record.BaseBranch)
+}
+
+func TestWebhook_PREdited_TitleEditIgnored(t *testing.T) {
+	p, store := setupWebhookTestPlugin(t)
+	p.configuration.ReviewLoopSyncBaseOnEdit = true
+
+	body := []byte(`{
+		"action": "edited",
+		"changes": {"title": {"from": "Old title"}, "body": {"from": "Old body"}},
+		"pull_request": {"number": 15, "html_url": "https://github.com/org/repo/pull/15", "title": "New title", "base": {"ref": "main"}},
+		"repository": {"full_name": "org/repo"}
+	}`)
+	sig := signPayload(testWebhookSecret, body)
+
+	store.On("HasDeliveryBeenProcessed", "delivery-pr-title").Return(false, nil)
+	store.On("MarkDeliveryProcessed", "delivery-pr-title").Return(nil)
+
+	req := makeWebhookRequest(t, "pull_request", "delivery-pr-title", body, sig)
+	rr := httptest.NewRecorder()
+
+	p.handleGitHubWebhook(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	store.AssertNotCalled(t, "GetReviewLoopByPRURL", mock.Anything)
+	store.AssertNotCalled(t, "SaveReviewLoop", mock.Anything)
+}
+
 func TestWebhook_PROpened_IdempotentPrURL(t *testing.T) {
 	p, store := setupWebhookTestPlugin(t)
 	api := p.API.(*mockPluginAPI)
@@ -538,6 +882,185 @@ func TestWebhook_PROpened_IdempotentPrURL(t *testing.T) {
 	api.AssertNotCalled(t, "PublishWebSocketEvent")
 }
 
+// setupExtraPRTest sends a second PR opened webhook for a FINISHED agent that
+// already has a primary PR.
+func TestWebhook_PROpened_DraftPRUsesDraftNotification(t *testing.T) {
+	p, store := setupWebhookTestPlugin(t)
+	api := p.API.(*mockPluginAPI)
+
+	agent := &kvstore.AgentRecord{
+		CursorAgentID: "agent-draft-1",
+		PostID:        "root-post-draft",
+		ChannelID:     "ch-draft",
+		UserID:        "user-1",
+		Status:        "FINISHED",
+		PrURL:         "https://github.com/org/repo/pull/12",
+		TargetBranch:  "cursor/explore-cache",
+		BaseBranch:    "main",
+		Draft:         true,
+	}
+
+	p.configuration.EnableAIReviewLoop = true
+	p.configuration.ReviewLoopSkipDraftPRs = true
+	mockGH := &mockGitHubClient{}
+	p.githubClient = mockGH
+
+	event := PullRequestEvent{
+		Action: "opened",
+		PullRequest: ghPullRequest{
+			Number:  12,
+			HTMLURL: "https://github.com/org/repo/pull/12",
+			Title:   "Explore a caching layer",
+			Draft:   true,
+		},
+	}
+	event.PullRequest.Head.Ref = "cursor/explore-cache"
+	event.PullRequest.Base.Ref = "main"
+	body, _ := json.Marshal(event)
+
+	store.On("HasDeliveryBeenProcessed", "delivery-pr-opened-draft").Return(false, nil)
+	store.On("MarkDeliveryProcessed", "delivery-pr-opened-draft").Return(nil)
+	store.On("GetAgentByPRURL", "https://github.com/org/repo/pull/12").Return(agent, nil)
+
+	api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		return post.RootId == "root-post-draft" &&
+			hasAttachmentWithColor(post, "#FFBC1F") &&
+			hasAttachmentWithTitle(post, "Draft PR #12: Explore a caching layer")
+	})).Return(&model.Post{Id: "notif-draft-1"}, nil).Once()
+
+	req := makeWebhookRequest(t, "pull_request", "delivery-pr-opened-draft", body, signPayload(testWebhookSecret, body))
+	rr := httptest.NewRecorder()
+	p.handleGitHubWebhook(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	api.AssertExpectations(t)
+	// The draft launch keeps its PR a draft: no loop, not marked ready.
+	store.AssertNotCalled(t, "SaveReviewLoop", mock.Anything)
+	mockGH.AssertNotCalled(t, "MarkPRReadyForReview", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestReviewLoopAllowedForPR_DraftLaunch(t *testing.T) {
+	p, _ := setupWebhookTestPlugin(t)
+	agent := &kvstore.AgentRecord{PrURL: "https://github.com/org/repo/pull/12", Draft: true}
+
+	assert.True(t, p.reviewLoopAllowedForPR(agent, agent.PrURL))
+
+	p.configuration.ReviewLoopSkipDraftPRs = true
+	assert.False(t, p.reviewLoopAllowedForPR(agent, agent.PrURL))
+
+	agent.Draft = false
+	assert.True(t, p.reviewLoopAllowedForPR(agent, agent.PrURL))
+}
+
+func setupExtraPRTest(t *testing.T) (*Plugin, *mockKVStore, *mockGitHubClient, *kvstore.AgentRecord, []byte, string) {
+	t.Helper()
+	p, store := setupWebhookTestPlugin(t)
+	api := p.API.(*mockPluginAPI)
+
+	agent := &kvstore.AgentRecord{
+		CursorAgentID: "agent-split-1",
+		PostID:        "root-post-split",
+		TriggerPostID: "trigger-post-split",
+		ChannelID:     "ch-split",
+		UserID:        "user-1",
+		Status:        "FINISHED",
+		PrURL:         "https://github.com/org/repo/pull/20",
+		TargetBranch:  "cursor/split-part-1",
+		BaseBranch:    "main",
+		Repository:    "org/repo",
+	}
+
+	p.configuration.EnableAIReviewLoop = true
+	p.configuration.AIReviewerBots = "coderabbitai[bot]"
+	p.configuration.MaxReviewIterations = 5
+	mockGH := &mockGitHubClient{}
+	p.githubClient = mockGH
+
+	event := PullRequestEvent{
+		Action: "opened",
+		PullRequest: ghPullRequest{
+			Number:  21,
+			HTMLURL: "https://github.com/org/repo/pull/21",
+			Title:   "Split part 2",
+		},
+	}
+	event.PullRequest.Head.Ref = "cursor/split-part-2"
+	event.PullRequest.Base.Ref = "main"
+	body, _ := json.Marshal(event)
+
+	store.On("HasDeliveryBeenProcessed", "delivery-pr-opened-split").Return(false, nil)
+	store.On("MarkDeliveryProcessed", "delivery-pr-opened-split").Return(nil)
+	store.On("GetAgentByPRURL", "https://github.com/org/repo/pull/21").Return(nil, nil)
+	store.On("GetAgentByBranch", "cursor/split-part-2").Return(agent, nil)
+	store.On("SaveAgent", mock.MatchedBy(func(r *kvstore.AgentRecord) bool {
+		return r.PrURL == "https://github.com/org/repo/pull/20" &&
+			len(r.ExtraPRURLs) == 1 && r.ExtraPRURLs[0] == "https://github.com/org/repo/pull/21"
+	})).Return(nil).Once()
+
+	api.On("PublishWebSocketEvent", mock.Anything, mock.Anything, mock.Anything).Return().Maybe()
+	api.On("CreatePost", mock.Anything).Return(&model.Post{Id: "notif-1"}, nil).Maybe()
+	api.On("GetPost", mock.Anything).Return(&model.Post{Id: "reply-1"}, nil).Maybe()
+	api.On("UpdatePost", mock.Anything).Return(&model.Post{}, nil).Maybe()
+	api.On("AddReaction", mock.Anything).Return(nil, nil).Maybe()
+
+	return p, store, mockGH, agent, body, signPayload(testWebhookSecret, body)
+}
+
+func TestWebhook_PROpened_SecondPRStoredAsExtra(t *testing.T) {
+	p, store, mockGH, agent, body, sig := setupExtraPRTest(t)
+
+	req := makeWebhookRequest(t, "pull_request", "delivery-pr-opened-split", body, sig)
+	rr := httptest.NewRecorder()
+	p.handleGitHubWebhook(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "https://github.com/org/repo/pull/20", agent.PrURL)
+	assert.Equal(t, []string{"https://github.com/org/repo/pull/21"}, agent.ExtraPRURLs)
+	assert.Equal(t, "cursor/split-part-1", agent.TargetBranch)
+	store.AssertExpectations(t)
+	// Additional PRs get no review loop unless ReviewLoopStartForExtraPRs is on.
+	store.AssertNotCalled(t, "SaveReviewLoop", mock.Anything)
+	mockGH.AssertNotCalled(t, "RequestReviewers", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestWebhook_PROpened_StartsReviewLoopForExtraPRWhenEnabled(t *testing.T) {
+	p, store, mockGH, agent, body, sig := setupExtraPRTest(t)
+	p.configuration.ReviewLoopStartForExtraPRs = true
+
+	store.On("GetReviewLoopByPRURL", "https://github.com/org/repo/pull/21").Return(nil, nil)
+	store.On("GetWorkflowByAgent", "agent-split-1").Return("", nil)
+	store.On("SaveReviewLoop", mock.MatchedBy(func(loop *kvstore.ReviewLoop) bool {
+		return loop.PRURL == "https://github.com/org/repo/pull/21" && loop.PRNumber == 21
+	})).Return(nil)
+	store.On("GetAgent", "agent-split-1").Return(agent, nil).Maybe()
+	mockGH.On("MarkPRReadyForReview", mock.Anything, "org", "repo", 21).Return(nil)
+	mockGH.On("RequestReviewers", mock.Anything, "org", "repo", 21, mock.Anything).Return(nil)
+
+	req := makeWebhookRequest(t, "pull_request", "delivery-pr-opened-split", body, sig)
+	rr := httptest.NewRecorder()
+	p.handleGitHubWebhook(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	store.AssertExpectations(t)
+	mockGH.AssertExpectations(t)
+}
+
+func TestReviewLoopPRURLs(t *testing.T) {
+	p, _ := setupWebhookTestPlugin(t)
+	agent := &kvstore.AgentRecord{PrURL: "https://github.com/org/repo/pull/20"}
+
+	assert.False(t, recordExtraPR(agent, "https://github.com/org/repo/pull/20/"))
+	assert.True(t, recordExtraPR(agent, "https://github.com/org/repo/pull/21"))
+	assert.False(t, recordExtraPR(agent, "https://github.com/Org/Repo/pull/21"))
+
+	assert.Equal(t, []string{"https://github.com/org/repo/pull/20"}, p.reviewLoopPRURLs(agent))
+	p.configuration.ReviewLoopStartForExtraPRs = true
+	assert.Equal(t, []string{
+		"https://github.com/org/repo/pull/20",
+		"https://github.com/org/repo/pull/21",
+	}, p.reviewLoopPRURLs(agent))
+}
+
 func TestWebhook_PRNotFound(t *testing.T) {
 	p, store := setupWebhookTestPlugin(t)
 
@@ -756,6 +1279,246 @@ func TestWebhook_ReviewCommentedEmpty(t *testing.T) {
 	api.AssertNotCalled(t, "CreatePost")
 }
 
+func TestWebhook_ReviewCommentedEmpty_ActiveLoopCollectsInlineFindings(t *testing.T) {
+	p, api, store, ghMock := setupReviewLoopTestPlugin(t)
+	cursorMock := p.cursorClient.(*mockCursorClient)
+	p.configuration.GitHubWebhookSecret = testWebhookSecret
+
+	loop := &kvstore.ReviewLoop{
+		ID:            "loop-1",
+		AgentRecordID: "agent-1",
+		Phase:         kvstore.ReviewPhaseAwaitingReview,
+		Iteration:     1,
+		RootPostID:    "root-1",
+		ChannelID:     "ch-1",
+		UserID:        "user-1",
+		Owner:         "org",
+		Repo:          "repo",
+		PRNumber:      42,
+		PRURL:         "https://github.com/org/repo/pull/42",
+	}
+
+	event := PullRequestReviewEvent{
+		Action: "submitted",
+		Review: ghReview{
+			State: "commented",
+			Body:  "", // Inline-only review: the comments are the feedback.
+		},
+		PullRequest: ghPullRequest{
+			Number:  42,
+			HTMLURL: "https://github.com/org/repo/pull/42",
+		},
+	}
+	event.Review.User.Login = "coderabbitai[bot]"
+	event.PullRequest.Head.SHA = "sha-1"
+	body, _ := json.Marshal(event)
+	sig := signPayload(testWebhookSecret, body)
+
+	store.On("HasDeliveryBeenProcessed", "delivery-rv-empty-loop").Return(false, nil)
+	store.On("MarkDeliveryProcessed", "delivery-rv-empty-loop").Return(nil)
+	store.On("GetReviewLoopByPRURL", "https://github.com/org/repo/pull/42").Return(loop, nil)
+	store.On("SaveReviewLoop", mock.Anything).Return(nil)
+	mockInlineStatusUpdate(store, api, "agent-1", &kvstore.AgentRecord{CursorAgentID: "agent-1"})
+	mockCheckpointReviewFeedback(ghMock)
+
+	cursorMock.On("AddFollowup", mock.Anything, "agent-1", mock.MatchedBy(func(req cursor.FollowupRequest) bool {
+		return strings.Contains(req.Prompt.Text, "Add a nil guard before dereferencing.")
+	})).Return(&cursor.FollowupResponse{ID: "agent-1"}, nil).Once()
+
+	req := makeWebhookRequest(t, "pull_request_review", "delivery-rv-empty-loop", body, sig)
+	rr := httptest.NewRecorder()
+
+	p.handleGitHubWebhook(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	cursorMock.AssertExpectations(t)
+	require.Len(t, loop.Findings, 1)
+	assert.Equal(t, kvstore.ReviewPhaseCursorFixing, loop.Phase)
+	// The human notification stays suppressed for empty bodies.
+	api.AssertNotCalled(t, "CreatePost", mock.Anything)
+}
+
+func signedReviewCommentRequest(t *testing.T, deliveryID string, event PullRequestReviewCommentEvent) *http.Request {
+	t.Helper()
+	body, _ := json.Marshal(event)
+	return makeWebhookRequest(t, "pull_request_review_comment", deliveryID, body, signPayload(testWebhookSecret, body))
+}
+
+func newInlineCommentEvent(login string, commentID int64) PullRequestReviewCommentEvent {
+	event := PullRequestReviewCommentEvent{
+		Action: "created",
+		Comment: ghReviewComment{
+			ID:   commentID,
+			Path: "server/api.go",
+			Body: "Check the error returned by Close.",
+		},
+		PullRequest: ghPullRequest{
+			Number:  42,
+			HTMLURL: "https://github.com/org/repo/pull/42",
+		},
+	}
+	event.Comment.User.Login = login
+	event.PullRequest.Head.SHA = "sha-1"
+	return event
+}
+
+func TestWebhook_ReviewComment_AIInlineCommentDispatches(t *testing.T) {
+	p, api, store, ghMock := setupReviewLoopTestPlugin(t)
+	cursorMock := p.cursorClient.(*mockCursorClient)
+	p.configuration.GitHubWebhookSecret = testWebhookSecret
+
+	loop := newAwaitingReviewLoop()
+	store.On("HasDeliveryBeenProcessed", mock.Anything).Return(false, nil)
+	store.On("MarkDeliveryProcessed", mock.Anything).Return(nil)
+	store.On("GetReviewLoopByPRURL", "https://github.com/org/repo/pull/42").Return(loop, nil)
+	store.On("SaveReviewLoop", mock.Anything).Return(nil)
+	mockInlineStatusUpdate(store, api, "agent-1", &kvstore.AgentRecord{CursorAgentID: "agent-1"})
+
+	ghMock.On("ListReviewComments", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestComment{
+		{
+			ID:       github.Ptr(int64(555)),
+			User:     &github.User{Login: github.Ptr("copilot-pull-request-reviewer")},
+			Path:     github.Ptr("server/api.go"),
+			Line:     github.Ptr(14),
+			Body:     github.Ptr("Check the error returned by Close."),
+			CommitID: github.Ptr("sha-1"),
+		},
+	}, nil).Once()
+	ghMock.On("ListReviews", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestReview{}, nil).Once()
+	ghMock.On("ListIssueComments", mock.Anything, "org", "repo", 42).Return([]*github.IssueComment{}, nil).Once()
+	cursorMock.On("AddFollowup", mock.Anything, "agent-1", mock.MatchedBy(func(req cursor.FollowupRequest) bool {
+		return strings.Contains(req.Prompt.Text, "Check the error returned by Close.")
+	})).Return(&cursor.FollowupResponse{ID: "agent-1"}, nil).Once()
+
+	rr := httptest.NewRecorder()
+	p.handleGitHubWebhook(rr, signedReviewCommentRequest(t, "delivery-inline-1", newInlineCommentEvent("copilot-pull-request-reviewer", 555)))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, kvstore.ReviewPhaseCursorFixing, loop.Phase)
+	assert.Equal(t, 2, loop.Iteration)
+	require.Len(t, loop.Findings, 1)
+	assert.Equal(t, int64(555), loop.Findings[0].SourceID)
+
+	// A second comment from the same review arrives after the dispatch; the
+	// loop has moved on, so nothing is collected or sent again.
+	rr = httptest.NewRecorder()
+	p.handleGitHubWebhook(rr, signedReviewCommentRequest(t, "delivery-inline-2", newInlineCommentEvent("copilot-pull-request-reviewer", 556)))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	cursorMock.AssertNumberOfCalls(t, "AddFollowup", 1)
+	ghMock.AssertNumberOfCalls(t, "ListReviewComments", 1)
+	api.AssertNotCalled(t, "CreatePost", mock.Anything)
+}
+
+func TestWebhook_ReviewComment_ConcurrentDeliveriesDispatchOnce(t *testing.T) {
+	p, api, store, ghMock := setupReviewLoopTestPlugin(t)
+	cursorMock := p.cursorClient.(*mockCursorClient)
+	p.configuration.GitHubWebhookSecret = testWebhookSecret
+
+	loop := newAwaitingReviewLoop()
+	store.On("HasDeliveryBeenProcessed", mock.Anything).Return(false, nil)
+	store.On("MarkDeliveryProcessed", mock.Anything).Return(nil)
+	store.On("GetReviewLoopByPRURL", "https://github.com/org/repo/pull/42").Return(loop, nil)
+	store.On("SaveReviewLoop", mock.Anything).Return(nil)
+	mockInlineStatusUpdate(store, api, "agent-1", &kvstore.AgentRecord{CursorAgentID: "agent-1"})
+
+	var comments []*github.PullRequestComment
+	for id := int64(700); id < 705; id++ {
+		comments = append(comments, &github.PullRequestComment{
+			ID:       github.Ptr(id),
+			User:     &github.User{Login: github.Ptr("copilot-pull-request-reviewer")},
+			Path:     github.Ptr("server/api.go"),
+			Line:     github.Ptr(int(id - 680)),
+			Body:     github.Ptr(fmt.Sprintf("Check the error returned by Close (%d).", id)),
+			CommitID: github.Ptr("sha-1"),
+		})
+	}
+	ghMock.On("ListReviewComments", mock.Anything, "org", "repo", 42).Return(comments, nil)
+	ghMock.On("ListReviews", mock.Anything, "org", "repo", 42).Return([]*github.PullRequestReview{}, nil)
+	ghMock.On("ListIssueComments", mock.Anything, "org", "repo", 42).Return([]*github.IssueComment{}, nil)
+	cursorMock.On("AddFollowup", mock.Anything, "agent-1", mock.Anything).Return(&cursor.FollowupResponse{ID: "agent-1"}, nil)
+
+	// One review with several inline comments arrives as a burst of
+	// concurrent deliveries; only the first may dispatch.
+	var wg sync.WaitGroup
+	for _, comment := range comments {
+		wg.Add(1)
+		go func(id int64) {
+			defer wg.Done()
+			rr := httptest.NewRecorder()
+			p.handleGitHubWebhook(rr, signedReviewCommentRequest(t, fmt.Sprintf("delivery-burst-%d", id), newInlineCommentEvent("copilot-pull-request-reviewer", id)))
+			assert.Equal(t, http.StatusOK, rr.Code)
+		}(comment.GetID())
+	}
+	wg.Wait()
+
+	cursorMock.AssertNumberOfCalls(t, "AddFollowup", 1)
+	assert.Equal(t, kvstore.ReviewPhaseCursorFixing, loop.Phase)
+	assert.Equal(t, 2, loop.Iteration)
+}
+
+func TestWebhook_ReviewComment_AlreadyCollectedFindingNotDispatched(t *testing.T) {
+	p, _, store, ghMock := setupReviewLoopTestPlugin(t)
+	cursorMock := p.cursorClient.(*mockCursorClient)
+	p.configuration.GitHubWebhookSecret = testWebhookSecret
+
+	// The batched review path already collected and dispatched this comment.
+	loop := newAwaitingReviewLoop()
+	loop.Findings = []kvstore.ReviewFinding{{
+		Key:                "0123456789abcdef",
+		Status:             findingStatusOpen,
+		SourceType:         "review_comment",
+		SourceID:           555,
+		ReviewerLogin:      "copilot-pull-request-reviewer",
+		ReviewerType:       reviewerTypeAIBot,
+		FirstSeenIteration: 1,
+		LastSeenIteration:  1,
+	}}
+	store.On("HasDeliveryBeenProcessed", "delivery-inline-dup").Return(false, nil)
+	store.On("MarkDeliveryProcessed", "delivery-inline-dup").Return(nil)
+	store.On("GetReviewLoopByPRURL", "https://github.com/org/repo/pull/42").Return(loop, nil)
+
+	rr := httptest.NewRecorder()
+	p.handleGitHubWebhook(rr, signedReviewCommentRequest(t, "delivery-inline-dup", newInlineCommentEvent("copilot-pull-request-reviewer", 555)))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, kvstore.ReviewPhaseAwaitingReview, loop.Phase)
+	store.AssertNotCalled(t, "SaveReviewLoop", mock.Anything)
+	ghMock.AssertNotCalled(t, "ListReviewComments", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	cursorMock.AssertNotCalled(t, "AddFollowup", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestWebhook_ReviewComment_IgnoresRepliesHumansAndCodeRabbit(t *testing.T) {
+	p, _, store, ghMock := setupReviewLoopTestPlugin(t)
+	cursorMock := p.cursorClient.(*mockCursorClient)
+	p.configuration.GitHubWebhookSecret = testWebhookSecret
+
+	loop := newAwaitingReviewLoop()
+	store.On("HasDeliveryBeenProcessed", mock.Anything).Return(false, nil)
+	store.On("MarkDeliveryProcessed", mock.Anything).Return(nil)
+	store.On("GetReviewLoopByPRURL", "https://github.com/org/repo/pull/42").Return(loop, nil)
+
+	reply := newInlineCommentEvent("copilot-pull-request-reviewer", 601)
+	reply.Comment.InReplyToID = 555
+	edited := newInlineCommentEvent("copilot-pull-request-reviewer", 602)
+	edited.Action = "edited"
+	events := map[string]PullRequestReviewCommentEvent{
+		"delivery-reply":      reply,
+		"delivery-edited":     edited,
+		"delivery-human":      newInlineCommentEvent("human-reviewer", 603),
+		"delivery-coderabbit": newInlineCommentEvent("coderabbitai[bot]", 604),
+	}
+	for deliveryID, event := range events {
+		rr := httptest.NewRecorder()
+		p.handleGitHubWebhook(rr, signedReviewCommentRequest(t, deliveryID, event))
+		assert.Equal(t, http.StatusOK, rr.Code, deliveryID)
+	}
+
+	assert.Equal(t, kvstore.ReviewPhaseAwaitingReview, loop.Phase)
+	ghMock.AssertNotCalled(t, "ListReviewComments", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	cursorMock.AssertNotCalled(t, "AddFollowup", mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestWebhook_ReviewEdited_Ignored(t *testing.T) {
 	p, store := setupWebhookTestPlugin(t)
 	api := p.API.(*mockPluginAPI)
@@ -1349,6 +2112,11 @@ func TestWebhook_HumanReview_ChangesRequested_TriggersCursorFixing(t *testing.T)
 		return strings.Contains(req.Prompt.Text, "Please move this to a helper")
 	})).Return(&cursor.FollowupResponse{ID: "agent-1"}, nil)
 
+	// Pre-dispatch checkpoint persistence.
+	store.On("SaveReviewLoop", mock.MatchedBy(func(l *kvstore.ReviewLoop) bool {
+		return l.Phase == kvstore.ReviewPhaseHumanReview && l.PendingDispatchSHA == "human-sha-2"
+	})).Return(nil).Once()
+
 	store.On("SaveReviewLoop", mock.MatchedBy(func(l *kvstore.ReviewLoop) bool {
 		return l.Phase == kvstore.ReviewPhaseCursorFixing &&
 			l.Iteration == 2 &&
@@ -1412,6 +2180,79 @@ func TestWebhook_HumanReview_ChangesRequested_TriggersCursorFixing(t *testing.T)
 	cursorMock.AssertExpectations(t)
 }
 
+func TestWebhook_HumanReview_ChangesRequested_FixingDisabled_OnlyNotifies(t *testing.T) {
+	p, store := setupWebhookTestPlugin(t)
+	api := p.API.(*mockPluginAPI)
+	mockGH := &mockGitHubClient{}
+	cursorMock := p.cursorClient.(*mockCursorClient)
+	p.githubClient = mockGH
+
+	p.configuration.AIReviewerBots = "coderabbitai[bot]"
+	p.configuration.MaxReviewIterations = 5
+	p.configuration.DisableHumanReviewFixing = true
+
+	loop := &kvstore.ReviewLoop{
+		ID:            "loop-1",
+		AgentRecordID: "agent-1",
+		Phase:         kvstore.ReviewPhaseHumanReview,
+		Iteration:     1,
+		Owner:         "org",
+		Repo:          "repo",
+		PRNumber:      42,
+		RootPostID:    "root-1",
+		ChannelID:     "ch-1",
+		UserID:        "user-1",
+		PRURL:         "https://github.com/org/repo/pull/42",
+	}
+	store.On("GetReviewLoopByPRURL", "https://github.com/org/repo/pull/42").Return(loop, nil)
+
+	store.On("GetAgentByPRURL", "https://github.com/org/repo/pull/42").Return(&kvstore.AgentRecord{
+		CursorAgentID: "agent-1",
+		PostID:        "root-1",
+		ChannelID:     "ch-1",
+		PrURL:         "https://github.com/org/repo/pull/42",
+	}, nil)
+
+	// The changes-requested notification (red) is still posted.
+	api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		return post.RootId == "root-1" && hasAttachmentWithColor(post, "#D24B4E")
+	})).Return(&model.Post{Id: "rv-1"}, nil)
+
+	event := PullRequestReviewEvent{
+		Action: "submitted",
+		Review: ghReview{
+			State:   "changes_requested",
+			Body:    "Need one more refactor pass.",
+			HTMLURL: "https://github.com/org/repo/pull/42#pullrequestreview-3",
+		},
+		PullRequest: ghPullRequest{
+			Number:  42,
+			HTMLURL: "https://github.com/org/repo/pull/42",
+		},
+	}
+	event.PullRequest.Head.Ref = "cursor/fix-review-loop"
+	event.PullRequest.Head.SHA = "human-sha-2"
+	event.Review.User.Login = "humandev"
+	body, _ := json.Marshal(event)
+	sig := signPayload(testWebhookSecret, body)
+
+	store.On("HasDeliveryBeenProcessed", "delivery-human-changes-disabled").Return(false, nil)
+	store.On("MarkDeliveryProcessed", "delivery-human-changes-disabled").Return(nil)
+
+	req := makeWebhookRequest(t, "pull_request_review", "delivery-human-changes-disabled", body, sig)
+	rr := httptest.NewRecorder()
+
+	p.handleGitHubWebhook(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	api.AssertExpectations(t)
+	store.AssertNotCalled(t, "SaveReviewLoop", mock.Anything)
+	cursorMock.AssertNotCalled(t, "AddFollowup", mock.Anything, mock.Anything, mock.Anything)
+	mockGH.AssertNotCalled(t, "ListReviewComments", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	assert.Equal(t, kvstore.ReviewPhaseHumanReview, loop.Phase)
+	assert.Equal(t, 1, loop.Iteration)
+}
+
 func TestWebhook_HumanReview_AIBotReview_IsInformationalOnly(t *testing.T) {
 	p, store := setupWebhookTestPlugin(t)
 	api := p.API.(*mockPluginAPI)
@@ -1513,3 +2354,84 @@ func TestWebhook_AwaitingReview_HumanReview_IsInformationalOnly(t *testing.T) {
 	// Human reviews do not drive awaiting_review transitions.
 	store.AssertNotCalled(t, "SaveReviewLoop")
 }
+
+func TestWebhook_RecordsDeliveryWhenEnabled(t *testing.T) {
+	p, store := setupWebhookTestPlugin(t)
+	p.configuration.RecordWebhookDeliveries = true
+
+	body := []byte(`{"action":"labeled"}`)
+	sig := signPayload(testWebhookSecret, body)
+
+	store.On("HasDeliveryBeenProcessed", "delivery-rec").Return(false, nil)
+	store.On("MarkDeliveryProcessed", "delivery-rec").Return(nil)
+	store.On("SaveWebhookDelivery", mock.MatchedBy(func(d *kvstore.WebhookDelivery) bool {
+		return d.DeliveryID == "delivery-rec" &&
+			d.Event == eventPullRequest &&
+			d.Action == "labeled" &&
+			d.Body == string(body) &&
+			d.Status == http.StatusOK &&
+			!d.Duplicate && !d.Truncated
+	}), maxRecordedWebhookDeliveries).Return(nil).Once()
+
+	rr := httptest.NewRecorder()
+	p.handleGitHubWebhook(rr, makeWebhookRequest(t, eventPullRequest, "delivery-rec", body, sig))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	store.AssertExpectations(t)
+}
+
+func TestWebhook_RecordsDuplicateDelivery(t *testing.T) {
+	p, store := setupWebhookTestPlugin(t)
+	p.configuration.RecordWebhookDeliveries = true
+
+	body := []byte(`{}`)
+	sig := signPayload(testWebhookSecret, body)
+
+	store.On("HasDeliveryBeenProcessed", "delivery-dup").Return(true, nil)
+	store.On("SaveWebhookDelivery", mock.MatchedBy(func(d *kvstore.WebhookDelivery) bool {
+		return d.DeliveryID == "delivery-dup" && d.Duplicate
+	}), maxRecordedWebhookDeliveries).Return(nil).Once()
+
+	rr := httptest.NewRecorder()
+	p.handleGitHubWebhook(rr, makeWebhookRequest(t, eventPing, "delivery-dup", body, sig))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	store.AssertExpectations(t)
+}
+
+func TestWebhook_TruncatesRecordedBody(t *testing.T) {
+	p, store := setupWebhookTestPlugin(t)
+	p.configuration.RecordWebhookDeliveries = true
+	p.configuration.WebhookMaxBodySizeKB = 1024
+
+	body := []byte(`{"zen":"` + strings.Repeat("a", maxRecordedWebhookBodyBytes) + `"}`)
+	sig := signPayload(testWebhookSecret, body)
+
+	store.On("HasDeliveryBeenProcessed", "delivery-big").Return(false, nil)
+	store.On("MarkDeliveryProcessed", "delivery-big").Return(nil)
+	store.On("SaveWebhookDelivery", mock.MatchedBy(func(d *kvstore.WebhookDelivery) bool {
+		return d.Truncated && len(d.Body) == maxRecordedWebhookBodyBytes
+	}), maxRecordedWebhookDeliveries).Return(nil).Once()
+
+	rr := httptest.NewRecorder()
+	p.handleGitHubWebhook(rr, makeWebhookRequest(t, eventPing, "delivery-big", body, sig))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	store.AssertExpectations(t)
+}
+
+func TestWebhook_DoesNotRecordDeliveryWhenDisabled(t *testing.T) {
+	p, store := setupWebhookTestPlugin(t)
+
+	body := []byte(`{}`)
+	sig := signPayload(testWebhookSecret, body)
+
+	store.On("HasDeliveryBeenProcessed", "delivery-off").Return(false, nil)
+	store.On("MarkDeliveryProcessed", "delivery-off").Return(nil)
+
+	rr := httptest.NewRecorder()
+	p.handleGitHubWebhook(rr, makeWebhookRequest(t, eventPing, "delivery-off", body, sig))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	store.AssertNotCalled(t, "SaveWebhookDelivery", mock.Anything, mock.Anything)
+}
@@ -35,6 +35,7 @@ interface AgentStatusChangedAction {
         pr_url: string;
         summary: string;
         target_branch?: string;
+        base_branch?: string;
         updated_at: number;
     };
 }
@@ -223,6 +224,20 @@ export function fetchReviewLoop(reviewLoopId: string) {
     };
 }
 
+// resetReviewLoop resolves to an error message when the reset fails, or null
+// on success, so the caller can surface the failure in the RHS.
+export function resetReviewLoop(reviewLoopId: string) {
+    return async (): Promise<string | null> => {
+        try {
+            await Client.resetReviewLoop(reviewLoopId);
+            return null;
+        } catch (error) {
+            console.error('Failed to reset review loop:', error); // eslint-disable-line no-console
+            return error instanceof Error ? error.message : String(error);
+        }
+    };
+}
+
 // --- WebSocket event handlers ---
 
 const parseTimestamp = (value: string): number => {
@@ -238,6 +253,7 @@ export const websocketAgentStatusChange = (data: AgentStatusChangeEvent): AgentS
         pr_url: data.pr_url,
         summary: data.summary,
         target_branch: data.target_branch,
+        base_branch: data.base_branch,
         updated_at: parseTimestamp(data.updated_at),
     },
 });
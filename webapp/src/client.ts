@@ -1,7 +1,7 @@
 import {Client4} from 'mattermost-redux/client';
 
 import manifest from './manifest';
-import type {Agent, AgentsResponse, FollowupRequest, ReviewLoop, StatusResponse, Workflow} from './types';
+import type {Agent, AgentsResponse, FollowupRequest, HITLFlagsRequest, HITLFlagsResponse, ReviewLoop, ReviewLoopIdempotencyWindowResponse, SnoozeReviewFindingResponse, SnoozeReviewLoopResponse, StatusResponse, Workflow} from './types';
 
 const pluginApiBase = `/plugins/${manifest.id}/api/v1`;
 
@@ -74,6 +74,18 @@ class ClientClass {
         return response.json();
     };
 
+    getHITLFlags = async (prompt: string): Promise<HITLFlagsResponse> => {
+        const url = `${pluginApiBase}/hitl/flags`;
+        const response = await fetch(url, Client4.getOptions({
+            method: 'POST',
+            body: JSON.stringify({prompt} as HITLFlagsRequest),
+        }));
+        if (!response.ok) {
+            throw new Error(`POST /hitl/flags failed: ${response.status}`);
+        }
+        return response.json();
+    };
+
     getReviewLoop = async (reviewLoopId: string): Promise<ReviewLoop> => {
         const url = `${pluginApiBase}/review-loops/${encodeURIComponent(reviewLoopId)}`;
         const response = await fetch(url, Client4.getOptions({
@@ -85,6 +97,52 @@ class ClientClass {
         return response.json();
     };
 
+    resetReviewLoop = async (reviewLoopId: string): Promise<StatusResponse> => {
+        const url = `${pluginApiBase}/review-loops/${encodeURIComponent(reviewLoopId)}/reset`;
+        const response = await fetch(url, Client4.getOptions({
+            method: 'POST',
+        }));
+        if (!response.ok) {
+            throw new Error(`POST /review-loops/${reviewLoopId}/reset failed: ${response.status}`);
+        }
+        return response.json();
+    };
+
+    snoozeReviewLoop = async (reviewLoopId: string, duration: string): Promise<SnoozeReviewLoopResponse> => {
+        const url = `${pluginApiBase}/review-loops/${encodeURIComponent(reviewLoopId)}/snooze`;
+        const response = await fetch(url, Client4.getOptions({
+            method: 'POST',
+            body: JSON.stringify({duration}),
+        }));
+        if (!response.ok) {
+            throw new Error(`POST /review-loops/${reviewLoopId}/snooze failed: ${response.status}`);
+        }
+        return response.json();
+    };
+
+    snoozeReviewFinding = async (reviewLoopId: string, findingKey: string): Promise<SnoozeReviewFindingResponse> => {
+        const url = `${pluginApiBase}/review-loops/${encodeURIComponent(reviewLoopId)}/findings/${encodeURIComponent(findingKey)}/snooze`;
+        const response = await fetch(url, Client4.getOptions({
+            method: 'POST',
+        }));
+        if (!response.ok) {
+            throw new Error(`POST /review-loops/${reviewLoopId}/findings/${findingKey}/snooze failed: ${response.status}`);
+        }
+        return response.json();
+    };
+
+    setReviewLoopIdempotencyWindow = async (reviewLoopId: string, window: string): Promise<ReviewLoopIdempotencyWindowResponse> => {
+        const url = `${pluginApiBase}/review-loops/${encodeURIComponent(reviewLoopId)}/idempotency-window`;
+        const response = await fetch(url, Client4.getOptions({
+            method: 'POST',
+            body: JSON.stringify({window}),
+        }));
+        if (!response.ok) {
+            throw new Error(`POST /review-loops/${reviewLoopId}/idempotency-window failed: ${response.status}`);
+        }
+        return response.json();
+    };
+
     getWorkflow = async (workflowId: string): Promise<Workflow> => {
         const url = `${pluginApiBase}/workflows/${encodeURIComponent(workflowId)}`;
         const response = await fetch(url, Client4.getOptions({
@@ -38,7 +38,9 @@ const PHASE_CONFIG: Record<string, {label: string; className: string}> = {
     approved: {label: 'AI Approved', className: 'cursor-phase-rl-approved'},
     human_review: {label: 'Human Review', className: 'cursor-phase-rl-human'},
     max_iterations: {label: 'Needs Attention', className: 'cursor-phase-rl-maxiter'},
+    stalled: {label: 'Stalled', className: 'cursor-phase-rl-maxiter'},
     failed: {label: 'Review Failed', className: 'cursor-phase-rl-failed'},
+    error: {label: 'Review Error', className: 'cursor-phase-rl-failed'},
 };
 
 const PhaseBadge: React.FC<Props> = ({phase}) => {
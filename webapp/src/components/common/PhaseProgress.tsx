@@ -49,7 +49,7 @@ function buildSteps(phase: WorkflowPhase, planIterationCount: number, skipContex
 
     // Optional "Review" step -- only shown when a review loop is active.
     if (reviewLoopPhase) {
-        const isTerminal = reviewLoopPhase === 'complete' || reviewLoopPhase === 'max_iterations' || reviewLoopPhase === 'failed';
+        const isTerminal = reviewLoopPhase === 'complete' || reviewLoopPhase === 'max_iterations' || reviewLoopPhase === 'stalled' || reviewLoopPhase === 'failed' || reviewLoopPhase === 'error';
         const isActive = phase === 'complete' && !isTerminal;
         const isComplete = isTerminal && reviewLoopPhase === 'complete';
         const reviewLabel = reviewLoopIteration && reviewLoopIteration > 1 ?
@@ -4,7 +4,7 @@ import {useHistory} from 'react-router-dom';
 
 import type {GlobalState} from '@mattermost/types/store';
 
-import {addFollowup, cancelAgent, fetchAgent, fetchReviewLoop, fetchWorkflow} from '../../actions';
+import {addFollowup, cancelAgent, fetchAgent, fetchReviewLoop, fetchWorkflow, resetReviewLoop} from '../../actions';
 import {getReviewLoopForAgent, getWorkflowForAgent} from '../../selectors';
 import type {Agent, ReviewLoopPhase} from '../../types';
 import ExternalLink from '../common/ExternalLink';
@@ -31,8 +31,10 @@ function getStatusBarClass(status: string, workflowPhase?: string, reviewLoopPha
         case 'human_review':
             return 'cursor-agent-detail-status-bar--yellow';
         case 'max_iterations':
+        case 'stalled':
             return 'cursor-agent-detail-status-bar--grey';
         case 'failed':
+        case 'error':
             return 'cursor-agent-detail-status-bar--red';
         default:
             break;
@@ -106,8 +108,12 @@ function getReviewLoopPhaseLabel(phase: string): string {
         return 'Review complete';
     case 'max_iterations':
         return 'Max iterations reached';
+    case 'stalled':
+        return 'Stalled: lifetime exceeded';
     case 'failed':
         return 'Review failed';
+    case 'error':
+        return 'Review error';
     default:
         return phase;
     }
@@ -146,10 +152,18 @@ const ReviewLoopWhosUp: React.FC<{phase: ReviewLoopPhase}> = ({phase}) => {
         label = 'Needs manual review';
         className = 'cursor-review-loop-whosup--warning';
         break;
+    case 'stalled':
+        label = 'Stalled -- needs manual review';
+        className = 'cursor-review-loop-whosup--warning';
+        break;
     case 'failed':
         label = 'Review failed';
         className = 'cursor-review-loop-whosup--failed';
         break;
+    case 'error':
+        label = 'Review error -- reset to resume';
+        className = 'cursor-review-loop-whosup--failed';
+        break;
     default:
         label = phase;
         className = '';
@@ -162,6 +176,7 @@ const AgentDetail: React.FC<Props> = ({agent, onBack}) => {
     const dispatch = useDispatch();
     const history = useHistory();
     const [followupText, setFollowupText] = useState('');
+    const [resetError, setResetError] = useState('');
     const isActive = agent.status === 'RUNNING' || agent.status === 'CREATING';
     const isAborted = agent.status === 'STOPPED' || agent.status === 'FAILED';
     const workflow = useSelector((state: GlobalState) => getWorkflowForAgent(state, agent.id));
@@ -201,6 +216,17 @@ const AgentDetail: React.FC<Props> = ({agent, onBack}) => {
         }
     };
 
+    const handleResetReviewLoop = async () => {
+        if (!reviewLoop) {
+            return;
+        }
+        setResetError('');
+        const error = await (dispatch(resetReviewLoop(reviewLoop.id) as any) as Promise<string | null>);
+        if (error) {
+            setResetError(`Failed to reset review loop: ${error}`);
+        }
+    };
+
     return (
         <div className='cursor-agent-detail'>
             <div className={`cursor-agent-detail-status-bar ${getStatusBarClass(agent.status, workflow?.phase, reviewLoop?.phase)}`}/>
@@ -236,10 +262,10 @@ const AgentDetail: React.FC<Props> = ({agent, onBack}) => {
                     <div className='cursor-agent-detail-value'>{agent.repository}</div>
                 </div>
 
-                {agent.branch && (
+                {(agent.base_branch || agent.branch) && (
                     <div className='cursor-agent-detail-section'>
                         <div className='cursor-agent-detail-label'>{'Base Branch'}</div>
-                        <div className='cursor-agent-detail-value'>{agent.branch}</div>
+                        <div className='cursor-agent-detail-value'>{agent.base_branch || agent.branch}</div>
                     </div>
                 )}
 
@@ -297,6 +323,21 @@ const AgentDetail: React.FC<Props> = ({agent, onBack}) => {
                             <div className='cursor-review-loop-whosup'>
                                 <ReviewLoopWhosUp phase={reviewLoop.phase}/>
                             </div>
+                            {reviewLoop.phase === 'error' && (
+                                <div className='cursor-review-loop-reset'>
+                                    <button
+                                        className='btn btn-tertiary btn-sm'
+                                        onClick={handleResetReviewLoop}
+                                    >
+                                        {'Reset Review Loop'}
+                                    </button>
+                                    {resetError && (
+                                        <div className='cursor-review-loop-reset-error'>
+                                            {resetError}
+                                        </div>
+                                    )}
+                                </div>
+                            )}
                             {reviewLoop.iteration > 0 && (
                                 <div className='cursor-review-loop-iteration'>
                                     {`Iteration ${reviewLoop.iteration}`}
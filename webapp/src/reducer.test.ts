@@ -181,6 +181,37 @@ describe('reducer', () => {
         expect(state.agents.a1.target_branch).toBe('');
     });
 
+    it('AGENT_STATUS_CHANGED updates base_branch and keeps it when omitted', () => {
+        const prevState: PluginState = {
+            ...initialState,
+            agents: {a1: makeAgent({id: 'a1', status: 'FINISHED', base_branch: 'main'})},
+        };
+        const retargeted = reducer(prevState, {
+            type: AGENT_STATUS_CHANGED,
+            data: {
+                agent_id: 'a1',
+                status: 'FINISHED',
+                pr_url: '',
+                summary: '',
+                base_branch: 'release-1.2',
+                updated_at: 2000,
+            },
+        });
+        expect(retargeted.agents.a1.base_branch).toBe('release-1.2');
+
+        const unchanged = reducer(retargeted, {
+            type: AGENT_STATUS_CHANGED,
+            data: {
+                agent_id: 'a1',
+                status: 'FINISHED',
+                pr_url: '',
+                summary: '',
+                updated_at: 3000,
+            },
+        });
+        expect(unchanged.agents.a1.base_branch).toBe('release-1.2');
+    });
+
     it('AGENT_STATUS_CHANGED ignores unknown agent', () => {
         const state = reducer(initialState, {
             type: AGENT_STATUS_CHANGED,
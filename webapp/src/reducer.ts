@@ -51,6 +51,7 @@ export default function reducer(state: PluginState = initialState, action: Plugi
                     pr_url: action.data.pr_url || existing.pr_url,
                     summary: action.data.summary || existing.summary,
                     target_branch: action.data.target_branch === undefined ? existing.target_branch : action.data.target_branch,
+                    base_branch: action.data.base_branch || existing.base_branch,
                     updated_at: action.data.updated_at,
                 },
             },
@@ -19,7 +19,9 @@ export type ReviewLoopPhase =
     | 'human_review'
     | 'complete'
     | 'max_iterations'
-    | 'failed';
+    | 'stalled'
+    | 'failed'
+    | 'error';
 
 // Agent data as stored/returned by the plugin backend
 export interface Agent {
@@ -28,6 +30,7 @@ export interface Agent {
     repository: string;
     branch: string;
     target_branch?: string;
+    base_branch?: string;
     prompt: string;
     description?: string;
     pr_url: string;
@@ -48,6 +51,9 @@ export interface Agent {
     workflow_phase?: WorkflowPhase;
     plan_iteration_count?: number;
 
+    // Further PRs the agent opened after pr_url
+    extra_pr_urls?: string[];
+
     // Review loop fields (populated when agent has an active review loop)
     review_loop_id?: string;
     review_loop_phase?: ReviewLoopPhase;
@@ -88,11 +94,35 @@ export interface FollowupRequest {
     message: string;
 }
 
+// Request body for POST /api/v1/hitl/flags
+export interface HITLFlagsRequest {
+    prompt: string;
+}
+
+// Effective HITL stage flags for a prompt, as resolved by the plugin backend
+export interface HITLFlagsResponse {
+    skip_context_review: boolean;
+    skip_plan_loop: boolean;
+}
+
 // Generic status response
 export interface StatusResponse {
     status: string;
 }
 
+export interface SnoozeReviewLoopResponse {
+    snooze_until: number;
+}
+
+export interface SnoozeReviewFindingResponse {
+    key: string;
+    snoozed_until_iteration: number;
+}
+
+export interface ReviewLoopIdempotencyWindowResponse {
+    idempotency_window_seconds: number;
+}
+
 // WebSocket event data for agent_status_change
 export interface AgentStatusChangeEvent {
     agent_id: string;
@@ -101,6 +131,7 @@ export interface AgentStatusChangeEvent {
     summary: string;
     repository: string;
     target_branch?: string;
+    base_branch?: string;
     updated_at: string;
 }
 
@@ -124,6 +155,14 @@ export interface ReviewLoopEvent {
     phase: ReviewLoopPhase;
     timestamp: number;
     detail?: string;
+
+    // Set on feedback dispatch events.
+    mode?: string;
+    new?: number;
+    repeated?: number;
+    dismissed?: number;
+    dispatch_sha?: string;
+    digest?: string;
 }
 
 // ReviewLoop data as returned by the plugin backend
@@ -138,14 +177,39 @@ export interface ReviewLoop {
     pr_url: string;
     pr_number: number;
     repository: string;
+    base_branch?: string;
     phase: ReviewLoopPhase;
     iteration: number;
     last_commit_sha?: string;
+    snooze_until?: number;
+    idempotency_window_seconds?: number;
     history: ReviewLoopEvent[];
     created_at: number;
     updated_at: number;
 }
 
+// Review feedback candidate dropped by one of the user's review loops
+export interface DroppedCandidate {
+    review_loop_id: string;
+    pr_url: string;
+    repository: string;
+    reason: string;
+    route: string;
+    source_type: string;
+    source_url?: string;
+    reviewer_login?: string;
+    path?: string;
+    line?: number;
+    commit_sha?: string;
+    iteration: number;
+    dropped_at: number;
+}
+
+// Response from GET /review-loops/dropped-candidates
+export interface DroppedCandidatesResponse {
+    candidates: DroppedCandidate[];
+}
+
 // WebSocket event data for workflow_phase_change
 export interface WorkflowPhaseChangeEvent {
     workflow_id: string;